@@ -0,0 +1,144 @@
+/**
+ * Announcer
+ *
+ * Linear stream of short spoken messages for screen-reader mode. The TUI
+ * renders a cell grid that screen readers can't follow, so focus changes,
+ * dialog contents, completion items and diagnostics are announced as plain
+ * text and delivered to listeners (the status bar speech line, the external
+ * reader bridge).
+ */
+
+// ============================================
+// Types
+// ============================================
+
+/**
+ * What caused an announcement.
+ */
+export type AnnouncementKind = 'focus' | 'dialog' | 'completion' | 'diagnostic' | 'notification' | 'info';
+
+/**
+ * How urgently a reader should speak an announcement. Assertive
+ * announcements interrupt; polite ones wait for the reader to finish.
+ */
+export type AnnouncementPriority = 'polite' | 'assertive';
+
+/**
+ * A single announcement.
+ */
+export interface Announcement {
+  /** Text to speak (may contain newlines between logical lines) */
+  text: string;
+  kind: AnnouncementKind;
+  priority: AnnouncementPriority;
+  /** Milliseconds since epoch */
+  timestamp: number;
+}
+
+/**
+ * Listener for announcements.
+ */
+export type AnnouncementListener = (announcement: Announcement) => void;
+
+// ============================================
+// Announcer
+// ============================================
+
+export class Announcer {
+  private listeners = new Set<AnnouncementListener>();
+  private history: Announcement[] = [];
+
+  /** Maximum announcements kept for "repeat last" and the bridge backlog */
+  private static readonly MAX_HISTORY = 50;
+
+  /** Identical announcements within this window are dropped */
+  private static readonly DUPLICATE_WINDOW_MS = 500;
+
+  /**
+   * Announce text. Blank lines and repeated whitespace are dropped.
+   * Returns the announcement, or null if it was empty or a duplicate.
+   */
+  announce(
+    text: string,
+    kind: AnnouncementKind = 'info',
+    priority: AnnouncementPriority = 'polite',
+    now = Date.now()
+  ): Announcement | null {
+    const normalized = normalizeAnnouncementText(text);
+    if (!normalized) return null;
+
+    const last = this.history[this.history.length - 1];
+    if (
+      last &&
+      last.text === normalized &&
+      last.kind === kind &&
+      now - last.timestamp < Announcer.DUPLICATE_WINDOW_MS
+    ) {
+      return null;
+    }
+
+    const announcement: Announcement = { text: normalized, kind, priority, timestamp: now };
+    this.history.push(announcement);
+    if (this.history.length > Announcer.MAX_HISTORY) {
+      this.history.shift();
+    }
+
+    for (const listener of this.listeners) {
+      listener(announcement);
+    }
+    return announcement;
+  }
+
+  /**
+   * Subscribe to announcements. Returns an unsubscribe function.
+   */
+  onAnnouncement(listener: AnnouncementListener): () => void {
+    this.listeners.add(listener);
+    return () => {
+      this.listeners.delete(listener);
+    };
+  }
+
+  /**
+   * Get the most recent announcement.
+   */
+  getLast(): Announcement | null {
+    return this.history[this.history.length - 1] ?? null;
+  }
+
+  /**
+   * Get recent announcements, oldest first.
+   */
+  getHistory(): Announcement[] {
+    return [...this.history];
+  }
+
+  /**
+   * Forget all announcements (listeners are kept).
+   */
+  clear(): void {
+    this.history = [];
+  }
+}
+
+// ============================================
+// Helpers
+// ============================================
+
+/**
+ * Collapse runs of whitespace within each line and drop empty lines.
+ */
+export function normalizeAnnouncementText(text: string): string {
+  return text
+    .split('\n')
+    .map((line) => line.replace(/\s+/g, ' ').trim())
+    .filter((line) => line.length > 0)
+    .join('\n');
+}
+
+/**
+ * Render an announcement on a single line (for the status bar).
+ */
+export function toSpeechLine(text: string): string {
+  return text.split('\n').join(' — ');
+}
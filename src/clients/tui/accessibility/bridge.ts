@@ -0,0 +1,121 @@
+/**
+ * Reader Bridge
+ *
+ * Sends announcements to an external screen-reader bridge as ECP
+ * notifications: newline-delimited JSON-RPC 2.0 messages with method
+ * `accessibility/announce` written to a Unix socket (or named pipe) the
+ * bridge listens on. The bridge hands the text to the platform's speech
+ * API, so the user hears the editor without the reader parsing the screen.
+ */
+
+import * as net from 'net';
+import { createNotification } from '../../../ecp/types.ts';
+import type { Announcement } from './announcer.ts';
+import { debugLog } from '../../../debug.ts';
+
+// ============================================
+// Constants
+// ============================================
+
+/** ECP notification method for announcements */
+export const ANNOUNCE_NOTIFICATION = 'accessibility/announce';
+
+/**
+ * Params of the `accessibility/announce` notification.
+ */
+export interface AnnounceNotificationParams {
+  text: string;
+  kind: Announcement['kind'];
+  priority: Announcement['priority'];
+  timestamp: number;
+}
+
+/**
+ * Serialize an announcement as one line of newline-delimited JSON-RPC.
+ */
+export function formatAnnounceNotification(announcement: Announcement): string {
+  const params: AnnounceNotificationParams = {
+    text: announcement.text,
+    kind: announcement.kind,
+    priority: announcement.priority,
+    timestamp: announcement.timestamp,
+  };
+  return JSON.stringify(createNotification(ANNOUNCE_NOTIFICATION, params)) + '\n';
+}
+
+// ============================================
+// Bridge Connection
+// ============================================
+
+export class ReaderBridge {
+  private socketPath: string;
+  private socket: net.Socket | null = null;
+  private connecting = false;
+  /** Messages written while (re)connecting */
+  private pending: string[] = [];
+
+  /** Pending messages beyond this are dropped, oldest first */
+  private static readonly MAX_PENDING = 20;
+
+  constructor(socketPath: string) {
+    this.socketPath = socketPath;
+  }
+
+  /**
+   * Get the socket path of the bridge.
+   */
+  getSocketPath(): string {
+    return this.socketPath;
+  }
+
+  /**
+   * Send an announcement, connecting on first use. Failures are logged and
+   * the message is dropped; the next announcement retries the connection.
+   */
+  send(announcement: Announcement): void {
+    const line = formatAnnounceNotification(announcement);
+    if (this.socket && !this.connecting) {
+      this.socket.write(line);
+      return;
+    }
+
+    this.pending.push(line);
+    if (this.pending.length > ReaderBridge.MAX_PENDING) {
+      this.pending.shift();
+    }
+    this.connect();
+  }
+
+  /**
+   * Close the connection.
+   */
+  dispose(): void {
+    this.socket?.destroy();
+    this.socket = null;
+    this.pending = [];
+  }
+
+  private connect(): void {
+    if (this.connecting || this.socket) return;
+    this.connecting = true;
+
+    const socket = net.createConnection(this.socketPath);
+    socket.on('connect', () => {
+      this.connecting = false;
+      for (const line of this.pending) {
+        socket.write(line);
+      }
+      this.pending = [];
+    });
+    socket.on('error', (error) => {
+      debugLog(`[ReaderBridge] ${this.socketPath}: ${error.message}`);
+    });
+    socket.on('close', () => {
+      this.connecting = false;
+      if (this.socket === socket) {
+        this.socket = null;
+      }
+    });
+    this.socket = socket;
+  }
+}
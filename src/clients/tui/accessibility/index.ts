@@ -0,0 +1,30 @@
+/**
+ * TUI Accessibility
+ *
+ * Screen-reader mode: announcements, focus tracking and the external
+ * reader bridge.
+ */
+
+export {
+  Announcer,
+  normalizeAnnouncementText,
+  toSpeechLine,
+  type Announcement,
+  type AnnouncementKind,
+  type AnnouncementPriority,
+  type AnnouncementListener,
+} from './announcer.ts';
+
+export {
+  ScreenReaderTracker,
+  linearizeRegion,
+  describeDiagnostic,
+  type AccessibilitySnapshot,
+} from './screen-reader.ts';
+
+export {
+  ReaderBridge,
+  ANNOUNCE_NOTIFICATION,
+  formatAnnounceNotification,
+  type AnnounceNotificationParams,
+} from './bridge.ts';
@@ -0,0 +1,125 @@
+/**
+ * Screen Reader Tracker
+ *
+ * Turns what the user is looking at into announcements. After each render
+ * the client takes a snapshot (focused element, top overlay, diagnostic at
+ * the cursor); the tracker compares it with the previous one and announces
+ * only what changed, so moving around doesn't repeat the whole screen.
+ */
+
+import type { Rect } from '../types.ts';
+import type { ScreenBuffer } from '../rendering/buffer.ts';
+import type { Announcer, AnnouncementKind } from './announcer.ts';
+import { normalizeAnnouncementText } from './announcer.ts';
+
+// ============================================
+// Types
+// ============================================
+
+/**
+ * Accessible state of the UI after a render.
+ */
+export interface AccessibilitySnapshot {
+  /** Focused element */
+  focus: { id: string; description: string } | null;
+  /** Topmost visible overlay (dialog, palette, completion list) */
+  overlay: { id: string; text: string; kind: Extract<AnnouncementKind, 'dialog' | 'completion'> } | null;
+  /** Diagnostic under the cursor of the focused editor */
+  diagnostic: { text: string; isError: boolean } | null;
+}
+
+// ============================================
+// Tracker
+// ============================================
+
+export class ScreenReaderTracker {
+  private announcer: Announcer;
+  private previous: AccessibilitySnapshot | null = null;
+
+  constructor(announcer: Announcer) {
+    this.announcer = announcer;
+  }
+
+  /**
+   * Announce the differences between this snapshot and the last one.
+   */
+  update(snapshot: AccessibilitySnapshot): void {
+    const previous = this.previous;
+    this.previous = snapshot;
+
+    const { overlay, focus, diagnostic } = snapshot;
+    if (overlay) {
+      const sameOverlay = previous?.overlay?.id === overlay.id;
+      if (!sameOverlay) {
+        this.announcer.announce(overlay.text, overlay.kind, overlay.kind === 'dialog' ? 'assertive' : 'polite');
+      } else if (previous!.overlay!.text !== overlay.text) {
+        this.announcer.announce(changedLines(previous!.overlay!.text, overlay.text), overlay.kind);
+      }
+    } else if (focus) {
+      const focusChanged =
+        !previous ||
+        previous.overlay !== null ||
+        previous.focus?.id !== focus.id ||
+        previous.focus.description !== focus.description;
+      if (focusChanged) {
+        this.announcer.announce(focus.description, 'focus');
+      }
+    }
+
+    if (diagnostic && diagnostic.text !== previous?.diagnostic?.text) {
+      this.announcer.announce(diagnostic.text, 'diagnostic', diagnostic.isError ? 'assertive' : 'polite');
+    }
+  }
+
+  /**
+   * Forget the previous snapshot so the next update announces everything.
+   */
+  reset(): void {
+    this.previous = null;
+  }
+}
+
+// ============================================
+// Helpers
+// ============================================
+
+/** Box drawing, block and scrollbar characters carry no meaning when spoken */
+const DECORATIVE_CHARS = /[─-▟▲▼▶◀]/g;
+
+/**
+ * Read the text inside a rectangle of the screen buffer, one line per row,
+ * without borders and decorative characters.
+ */
+export function linearizeRegion(buffer: ScreenBuffer, rect: Rect): string {
+  const lines: string[] = [];
+  for (let y = rect.y; y < rect.y + rect.height; y++) {
+    let line = '';
+    for (let x = rect.x; x < rect.x + rect.width; x++) {
+      const cell = buffer.get(x, y);
+      if (cell && cell.char !== '') {
+        line += cell.char;
+      }
+    }
+    lines.push(line.replace(DECORATIVE_CHARS, ' '));
+  }
+  return normalizeAnnouncementText(lines.join('\n'));
+}
+
+/**
+ * Lines of `next` that aren't in `previous` (all of `next` if none are new).
+ */
+function changedLines(previous: string, next: string): string {
+  const before = new Set(previous.split('\n'));
+  const added = next.split('\n').filter((line) => !before.has(line));
+  return added.length > 0 ? added.join('\n') : next;
+}
+
+/**
+ * Describe a diagnostic for speech, e.g. "Error: Cannot find name 'x' (ts)".
+ * Severity follows LSP: 1 error, 2 warning, 3 information, 4 hint.
+ */
+export function describeDiagnostic(severity: number, message: string, source?: string): string {
+  const label = severity === 1 ? 'Error' : severity === 2 ? 'Warning' : severity === 3 ? 'Info' : 'Hint';
+  const firstLine = message.split('\n')[0]!.trim();
+  return `${label}: ${firstLine}${source ? ` (${source})` : ''}`;
+}
@@ -0,0 +1,256 @@
+/**
+ * Terminal Graphics
+ *
+ * Encoders for the two inline image protocols terminals support — the
+ * Kitty graphics protocol and DEC sixel — and detection of which one the
+ * host terminal understands. Terminals with neither get half-block
+ * rendering, which only needs truecolor cells.
+ */
+
+import { deflateSync } from 'zlib';
+import type { PixelBuffer } from '../../../core/image/index.ts';
+
+// ============================================
+// Types
+// ============================================
+
+export type GraphicsProtocol = 'kitty' | 'sixel' | 'halfblock';
+
+/** Value of the `tui.imagePreview.protocol` setting */
+export type GraphicsProtocolSetting = GraphicsProtocol | 'auto';
+
+/**
+ * Pixel size assumed for one terminal cell when sizing sixel images.
+ * Kitty placements are scaled to whole cells by the terminal instead.
+ */
+export const CELL_PIXEL_WIDTH = 10;
+export const CELL_PIXEL_HEIGHT = 20;
+
+/** Kitty payloads are sent in chunks of at most this many base64 bytes */
+const KITTY_CHUNK_SIZE = 4096;
+
+/** Largest sixel palette (terminals commonly support 256 registers) */
+const SIXEL_MAX_COLORS = 256;
+
+const ESC = '\x1b';
+const ST = `${ESC}\\`;
+
+// ============================================
+// Detection
+// ============================================
+
+/**
+ * Pick the graphics protocol for the host terminal from its environment.
+ * Inside tmux or screen, escape sequences for images don't reach the
+ * outer terminal, so half-blocks are used.
+ */
+export function detectGraphicsProtocol(env: Record<string, string | undefined> = process.env): GraphicsProtocol {
+  const term = env.TERM ?? '';
+  const program = env.TERM_PROGRAM ?? '';
+
+  if (env.TMUX || term.startsWith('screen') || term.startsWith('tmux')) {
+    return 'halfblock';
+  }
+  if (term === 'xterm-kitty' || env.KITTY_WINDOW_ID || term === 'xterm-ghostty' || program === 'ghostty' || program === 'WezTerm') {
+    return 'kitty';
+  }
+  if (
+    program === 'iTerm.app' ||
+    program === 'mlterm' ||
+    env.WT_SESSION ||
+    /^(foot|mlterm|contour|yaft)/.test(term) ||
+    env.MLTERM
+  ) {
+    return 'sixel';
+  }
+  return 'halfblock';
+}
+
+/**
+ * Resolve the protocol setting ('auto' detects from the environment).
+ */
+export function resolveGraphicsProtocol(
+  setting: string,
+  env: Record<string, string | undefined> = process.env
+): GraphicsProtocol {
+  if (setting === 'kitty' || setting === 'sixel' || setting === 'halfblock') {
+    return setting;
+  }
+  return detectGraphicsProtocol(env);
+}
+
+// ============================================
+// Kitty Graphics Protocol
+// ============================================
+
+/**
+ * Options for placing a Kitty image.
+ */
+export interface KittyImageOptions {
+  /** Image id (reusing an id replaces the previous image) */
+  id: number;
+  /** Cells the image is scaled to fill */
+  columns: number;
+  rows: number;
+}
+
+/**
+ * Transmit and display an image at the cursor position. The cursor is not
+ * moved and the terminal sends no response.
+ */
+export function kittyImage(pixels: PixelBuffer, options: KittyImageOptions): string {
+  const raw = new Uint8Array(pixels.data.buffer, pixels.data.byteOffset, pixels.data.byteLength);
+  const payload = Buffer.from(deflateSync(raw)).toString('base64');
+  const control =
+    `a=T,f=32,o=z,s=${pixels.width},v=${pixels.height},` +
+    `c=${options.columns},r=${options.rows},i=${options.id},q=2,C=1`;
+
+  let output = '';
+  for (let offset = 0; offset < payload.length || offset === 0; offset += KITTY_CHUNK_SIZE) {
+    const chunk = payload.slice(offset, offset + KITTY_CHUNK_SIZE);
+    const more = offset + KITTY_CHUNK_SIZE < payload.length ? 1 : 0;
+    output += offset === 0 ? `${ESC}_G${control},m=${more};${chunk}${ST}` : `${ESC}_Gm=${more};${chunk}${ST}`;
+  }
+  return output;
+}
+
+/**
+ * Delete an image and free its data.
+ */
+export function kittyDelete(id: number): string {
+  return `${ESC}_Ga=d,d=I,i=${id},q=2${ST}`;
+}
+
+// ============================================
+// Sixel
+// ============================================
+
+/**
+ * Encode an image as sixel. Pixels are composited onto `background`;
+ * fully transparent pixels are left unpainted.
+ */
+export function sixelImage(pixels: PixelBuffer, background: { r: number; g: number; b: number }): string {
+  const { width, height, data } = pixels;
+  const { palette, indices } = quantize(pixels, background);
+
+  let output = `${ESC}P0;1;0q"1;1;${width};${height}`;
+  for (let i = 0; i < palette.length; i++) {
+    const [r, g, b] = palette[i]!;
+    output += `#${i};2;${Math.round((r * 100) / 255)};${Math.round((g * 100) / 255)};${Math.round((b * 100) / 255)}`;
+  }
+
+  for (let bandTop = 0; bandTop < height; bandTop += 6) {
+    const bandHeight = Math.min(6, height - bandTop);
+
+    // Sixel bits per color for each column of the band
+    const columnsByColor = new Map<number, Uint8Array>();
+    for (let dy = 0; dy < bandHeight; dy++) {
+      const y = bandTop + dy;
+      for (let x = 0; x < width; x++) {
+        const p = y * width + x;
+        if (data[p * 4 + 3]! === 0) continue;
+        const color = indices[p]!;
+        let columns = columnsByColor.get(color);
+        if (!columns) {
+          columns = new Uint8Array(width);
+          columnsByColor.set(color, columns);
+        }
+        columns[x] = columns[x]! | (1 << dy);
+      }
+    }
+
+    let first = true;
+    for (const [color, columns] of columnsByColor) {
+      if (!first) output += '$';
+      first = false;
+      output += `#${color}${runLengthSixels(columns)}`;
+    }
+    // No newline after the last band, so the image never scrolls the screen
+    if (bandTop + 6 < height) output += '-';
+  }
+
+  return output + ST;
+}
+
+/**
+ * Encode a row of sixel values with `!n` repeat introducers. Trailing
+ * empty sixels are dropped.
+ */
+function runLengthSixels(columns: Uint8Array): string {
+  let end = columns.length;
+  while (end > 0 && columns[end - 1] === 0) end--;
+
+  let output = '';
+  let x = 0;
+  while (x < end) {
+    const value = columns[x]!;
+    let run = 1;
+    while (x + run < end && columns[x + run] === value) run++;
+    const char = String.fromCharCode(63 + value);
+    output += run > 3 ? `!${run}${char}` : char.repeat(run);
+    x += run;
+  }
+  return output;
+}
+
+/**
+ * Reduce an image to at most 256 colors: the most common colors at 5 bits
+ * per channel, with every pixel mapped to its nearest palette entry.
+ */
+function quantize(
+  pixels: PixelBuffer,
+  background: { r: number; g: number; b: number }
+): { palette: Array<[number, number, number]>; indices: Uint8Array } {
+  const { width, height, data } = pixels;
+  const count = width * height;
+  const composited = new Uint8Array(count * 3);
+  const histogram = new Map<number, number>();
+
+  for (let p = 0; p < count; p++) {
+    const alpha = data[p * 4 + 3]! / 255;
+    const r = Math.round(data[p * 4]! * alpha + background.r * (1 - alpha));
+    const g = Math.round(data[p * 4 + 1]! * alpha + background.g * (1 - alpha));
+    const b = Math.round(data[p * 4 + 2]! * alpha + background.b * (1 - alpha));
+    composited[p * 3] = r;
+    composited[p * 3 + 1] = g;
+    composited[p * 3 + 2] = b;
+    if (alpha > 0) {
+      const key = ((r >> 3) << 10) | ((g >> 3) << 5) | (b >> 3);
+      histogram.set(key, (histogram.get(key) ?? 0) + 1);
+    }
+  }
+
+  const buckets = [...histogram.entries()].sort((a, b) => b[1] - a[1]).slice(0, SIXEL_MAX_COLORS);
+  const palette: Array<[number, number, number]> = buckets.map(([key]) => [
+    (((key >> 10) & 31) << 3) | 4,
+    (((key >> 5) & 31) << 3) | 4,
+    ((key & 31) << 3) | 4,
+  ]);
+  if (palette.length === 0) palette.push([background.r, background.g, background.b]);
+
+  const nearest = new Map<number, number>();
+  const indices = new Uint8Array(count);
+  for (let p = 0; p < count; p++) {
+    const r = composited[p * 3]!;
+    const g = composited[p * 3 + 1]!;
+    const b = composited[p * 3 + 2]!;
+    const key = ((r >> 3) << 10) | ((g >> 3) << 5) | (b >> 3);
+    let index = nearest.get(key);
+    if (index === undefined) {
+      let best = Infinity;
+      index = 0;
+      for (let i = 0; i < palette.length; i++) {
+        const [pr, pg, pb] = palette[i]!;
+        const distance = (pr - r) ** 2 + (pg - g) ** 2 + (pb - b) ** 2;
+        if (distance < best) {
+          best = distance;
+          index = i;
+        }
+      }
+      nearest.set(key, index);
+    }
+    indices[p] = index;
+  }
+
+  return { palette, indices };
+}
@@ -52,6 +52,7 @@ export {
   setTitle,
   requestTerminalSize,
   bell,
+  desktopNotification,
   softReset,
   hardReset,
   focusTrackingOn,
@@ -352,6 +352,16 @@ export function bell(): string {
   return BEL;
 }
 
+/**
+ * Desktop notification (OSC 99, supported by kitty and others).
+ * Terminals without support ignore the sequence.
+ */
+export function desktopNotification(title: string, body: string, id = 'ultra'): string {
+  // Control characters would terminate the sequence early
+  const clean = (text: string) => text.replace(/[\x00-\x1f\x7f]/g, ' ');
+  return `${OSC}99;i=${id}:d=0:p=title;${clean(title)}${ST}${OSC}99;i=${id}:d=1:p=body;${clean(body)}${ST}`;
+}
+
 // ============================================
 // Utility Sequences
 // ============================================
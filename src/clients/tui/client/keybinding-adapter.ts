@@ -91,6 +91,19 @@ export const DEFAULT_KEYBINDINGS: KeyBinding[] = [
   { key: 'ctrl+k ctrl+s', command: 'file.saveAll' },
   { key: 'ctrl+k ctrl+0', command: 'editor.foldAll' },
   { key: 'ctrl+k ctrl+j', command: 'editor.unfoldAll' },
+  { key: 'ctrl+k ctrl+1', command: 'editor.foldLevel1' },
+  { key: 'ctrl+k ctrl+2', command: 'editor.foldLevel2' },
+  { key: 'ctrl+k ctrl+3', command: 'editor.foldLevel3' },
+  { key: 'ctrl+k ctrl+4', command: 'editor.foldLevel4' },
+  { key: 'ctrl+k ctrl+5', command: 'editor.foldLevel5' },
+  { key: 'ctrl+k ctrl+6', command: 'editor.foldLevel6' },
+  { key: 'ctrl+k ctrl+7', command: 'editor.foldLevel7' },
+  { key: 'ctrl+k ctrl+/', command: 'editor.foldAllComments' },
+  { key: 'ctrl+k ctrl+-', command: 'editor.foldAllExceptSelected' },
+  { key: 'ctrl+k ctrl+,', command: 'editor.createFoldFromSelection' },
+  { key: 'ctrl+k ctrl+.', command: 'editor.removeManualFolds' },
+  { key: 'ctrl+k ctrl+r', command: 'workbench.toggleScreenReaderMode' },
+  { key: 'ctrl+k ctrl+a', command: 'workbench.repeatLastAnnouncement' },
   { key: 'ctrl+k ctrl+w', command: 'editor.closeAll' },
 ];
 
@@ -2,7 +2,8 @@
  * LSP Integration
  *
  * Manages Language Server Protocol integration for the TUI client.
- * Provides autocomplete, hover, go to definition, signature help, and diagnostics.
+ * Provides autocomplete, hover, go to definition, signature help, diagnostics,
+ * folding ranges, document links and colour decorators.
  */
 
 import { debugLog } from '../../../debug.ts';
@@ -12,6 +13,7 @@ import {
   createAutocompletePopup,
 } from '../overlays/autocomplete-popup.ts';
 import { HoverTooltip, createHoverTooltip } from '../overlays/hover-tooltip.ts';
+import { ColorPicker, createColorPicker } from '../overlays/color-picker.ts';
 import {
   SignatureHelpOverlay,
   createSignatureHelp,
@@ -22,10 +24,22 @@ import {
   type LSPService,
   type LSPPosition,
   type LSPCompletionItem,
+  type LSPTextEdit,
+  type LSPRange,
+  type LSPFoldingRange,
+  type LSPDocumentLink,
+  type LSPColor,
+  type LSPColorInformation,
+  type LSPColorPresentation,
   type LSPDiagnostic,
+  type LSPProgress,
+  type LSPServerMessage,
+  type ServerDiscoveryResult,
   EXTENSION_TO_LANGUAGE,
+  MessageType,
 } from '../../../services/lsp/index.ts';
 import type { TUISettings } from '../config/config-manager.ts';
+import { rgbToHex } from '../../../core/colors.ts';
 
 // ============================================
 // Types
@@ -50,6 +64,30 @@ export interface LSPIntegrationCallbacks {
   onDiagnosticsUpdate?: (uri: string, diagnostics: LSPDiagnostic[]) => void;
   /** Called when a completion item is accepted (Enter/Tab pressed) */
   onCompletionAccepted?: (item: LSPCompletionItem, prefix: string, startColumn: number) => void;
+  /** Called with auto-import edits that arrived after the item was already applied */
+  onCompletionAdditionalEdits?: (uri: string, edits: LSPTextEdit[]) => void;
+  /** Called when server progress changes, with all progress still running */
+  onProgressChange?: (active: LSPProgress[]) => void;
+  /** Ask the user to pick one of a server's message actions (window/showMessageRequest) */
+  pickMessageAction?: (message: LSPServerMessage) => Promise<string | null>;
+  /** Called when folding ranges, links or colours for a document are refreshed */
+  onDocumentFeatures?: (uri: string, features: DocumentFeatures) => void;
+  /** Apply edits to an open document, keeping the cursor in place */
+  applyTextEdits?: (uri: string, edits: LSPTextEdit[]) => void;
+  /** Open a link target that isn't a file (e.g. an https URL) */
+  openExternal?: (target: string) => void;
+  /** Called once per language when its server command can't be found */
+  onServerNotFound?: (languageId: string, discovery: ServerDiscoveryResult) => void;
+}
+
+/**
+ * Per-document features fetched after open and edits.
+ */
+export interface DocumentFeatures {
+  /** null = server has no folding ranges; use indentation folding */
+  foldingRanges: LSPFoldingRange[] | null;
+  links: LSPDocumentLink[];
+  colors: LSPColorInformation[];
 }
 
 export interface DocumentInfo {
@@ -58,6 +96,9 @@ export interface DocumentInfo {
   version: number;
 }
 
+/** Debounce for folding/link/colour refreshes after edits */
+const DOCUMENT_FEATURES_DELAY_MS = 400;
+
 // ============================================
 // LSP Integration
 // ============================================
@@ -90,18 +131,43 @@ export class LSPIntegration {
   /** Debounce timer for completion */
   private completionDebounceTimer: ReturnType<typeof setTimeout> | null = null;
 
+  /** Document the visible completions were requested for */
+  private completionUri: string | null = null;
+
+  /** In-flight and finished completionItem/resolve requests */
+  private completionResolves = new WeakMap<LSPCompletionItem, Promise<LSPCompletionItem>>();
+
+  /** Items returned by resolve */
+  private resolvedCompletions = new WeakSet<LSPCompletionItem>();
+
   /** Active language servers */
   private activeServers = new Set<string>();
 
   /** Diagnostics unsubscribe function */
   private diagnosticsUnsubscribe: (() => void) | null = null;
 
+  /** Progress and server message unsubscribe functions */
+  private windowUnsubscribes: Array<() => void> = [];
+
+  /** Languages whose missing server was already reported */
+  private reportedMissingServers = new Set<string>();
+
   /** Diagnostics by URI */
   private diagnosticsByUri = new Map<string, LSPDiagnostic[]>();
 
   /** Completion trigger characters */
   private triggerCharacters = '.:/<@(';
 
+  /** Colour picker overlay */
+  private colorPicker: ColorPicker;
+
+  /** Debounce timers for folding/link/colour refreshes, by URI */
+  private featureTimers = new Map<string, ReturnType<typeof setTimeout>>();
+
+  /** Last fetched links and colours, by URI */
+  private linksByUri = new Map<string, LSPDocumentLink[]>();
+  private colorsByUri = new Map<string, LSPColorInformation[]>();
+
   constructor(
     overlayManager: OverlayManager,
     callbacks: LSPIntegrationCallbacks,
@@ -123,16 +189,19 @@ export class LSPIntegration {
     this.autocompletePopup = createAutocompletePopup('lsp-autocomplete', overlayCallbacks);
     this.hoverTooltip = createHoverTooltip('lsp-hover', overlayCallbacks);
     this.signatureHelp = createSignatureHelp('lsp-signature', overlayCallbacks);
+    this.colorPicker = createColorPicker('lsp-color-picker', overlayCallbacks);
 
     // Setup autocomplete selection callback
     this.autocompletePopup.onSelect((item, prefix, startColumn) => {
-      this.callbacks.onCompletionAccepted?.(item, prefix, startColumn);
+      this.acceptCompletionItem(item, prefix, startColumn);
     });
+    this.autocompletePopup.setResolver((item) => this.resolveCompletion(item));
 
     // Register overlays with manager
     this.overlayManager.addOverlay(this.autocompletePopup);
     this.overlayManager.addOverlay(this.hoverTooltip);
     this.overlayManager.addOverlay(this.signatureHelp);
+    this.overlayManager.addOverlay(this.colorPicker);
 
     // Setup signature help status bar callback
     if (callbacks.setStatusBarSignature) {
@@ -148,6 +217,43 @@ export class LSPIntegration {
       this.callbacks.onDiagnosticsUpdate?.(uri, diagnostics);
       this.callbacks.onDirty();
     });
+
+    // Surface server progress, messages and message requests
+    this.lspService.setTraceLevel(this.callbacks.getSetting('lsp.trace') ?? 'messages');
+    this.windowUnsubscribes.push(
+      this.lspService.onProgress(() => {
+        this.callbacks.onProgressChange?.(this.lspService.getActiveProgress());
+      }),
+      this.lspService.onServerMessage((message) => {
+        this.showServerMessage(message);
+      }),
+      this.lspService.onServerStatusChange((status) => {
+        if (status.status !== 'error' || !status.discovery || status.discovery.resolved) return;
+        if (this.reportedMissingServers.has(status.languageId)) return;
+        this.reportedMissingServers.add(status.languageId);
+        this.callbacks.onServerNotFound?.(status.languageId, status.discovery);
+      })
+    );
+    this.lspService.setMessageRequestHandler(async (message) => {
+      if (!this.callbacks.pickMessageAction || !message.actions?.length) {
+        this.showServerMessage(message);
+        return null;
+      }
+      return this.callbacks.pickMessageAction(message);
+    });
+  }
+
+  /**
+   * Show a window/showMessage message as a notification.
+   * Log-type messages only go to the trace.
+   */
+  private showServerMessage(message: LSPServerMessage): void {
+    if (message.type === MessageType.Log || !message.message) return;
+
+    const type = message.type === MessageType.Error
+      ? 'error'
+      : message.type === MessageType.Warning ? 'warning' : 'info';
+    this.callbacks.showNotification(`${message.languageId}: ${message.message}`, type);
   }
 
   // ─────────────────────────────────────────────────────────────────────────
@@ -173,7 +279,7 @@ export class LSPIntegration {
     // Start server for this language if not already running
     if (!this.activeServers.has(languageId) && this.lspService.hasServerFor(languageId)) {
       try {
-        await this.lspService.startServer(languageId, this.workspaceRoot);
+        await this.lspService.startServer(languageId, this.workspaceRoot, uri);
         this.activeServers.add(languageId);
         debugLog(`[LSPIntegration] Started server for ${languageId}`);
       } catch (error) {
@@ -186,12 +292,22 @@ export class LSPIntegration {
     try {
       await this.lspService.documentOpened(uri, languageId, content);
       this.currentDocument = { uri, languageId, version: 1 };
+      this.scheduleDocumentFeatures(uri, 0);
       debugLog(`[LSPIntegration] Document opened: ${uri}`);
     } catch (error) {
       debugLog(`[LSPIntegration] Failed to notify document opened: ${error}`);
     }
   }
 
+  /**
+   * Look for a language server again (e.g. after installing it).
+   * Documents must be reopened with initForDocument to start it.
+   */
+  async retryServer(languageId: string, uri?: string): Promise<ServerDiscoveryResult | null> {
+    this.reportedMissingServers.delete(languageId);
+    return this.lspService.discoverServer(languageId, uri);
+  }
+
   /**
    * Notify document content changed.
    */
@@ -204,6 +320,7 @@ export class LSPIntegration {
 
     try {
       await this.lspService.documentChanged(uri, content, this.currentDocument.version);
+      this.scheduleDocumentFeatures(uri);
     } catch (error) {
       debugLog(`[LSPIntegration] Failed to notify document changed: ${error}`);
     }
@@ -230,6 +347,7 @@ export class LSPIntegration {
         this.currentDocument = null;
       }
       this.diagnosticsByUri.delete(uri);
+      this.clearDocumentFeatures(uri);
     } catch (error) {
       debugLog(`[LSPIntegration] Failed to notify document closed: ${error}`);
     }
@@ -246,9 +364,19 @@ export class LSPIntegration {
     this.autocompletePopup.hide();
     this.hoverTooltip.hide();
     this.signatureHelp.hide();
+    this.colorPicker.hide();
+    for (const uri of [...this.featureTimers.keys()]) {
+      this.clearDocumentFeatures(uri);
+    }
 
-    // Unsubscribe from diagnostics
+    // Unsubscribe from diagnostics, progress and messages
     this.diagnosticsUnsubscribe?.();
+    for (const unsubscribe of this.windowUnsubscribes) {
+      unsubscribe();
+    }
+    this.windowUnsubscribes = [];
+    this.reportedMissingServers.clear();
+    this.lspService.setMessageRequestHandler(null);
 
     // Shutdown LSP service
     try {
@@ -286,6 +414,10 @@ export class LSPIntegration {
       }
 
       // Show popup with LSP completion items
+      this.completionUri = uri;
+      this.autocompletePopup.setShowDocumentation(
+        this.callbacks.getSetting('lsp.completion.showDocumentation') ?? true
+      );
       this.autocompletePopup.showCompletions(completions, screenX, screenY, prefix, startColumn);
     } catch (error) {
       debugLog(`[LSPIntegration] Completion failed: ${error}`);
@@ -314,6 +446,45 @@ export class LSPIntegration {
     }, debounceMs);
   }
 
+  /**
+   * Resolve a completion item's documentation and edits (cached per item).
+   */
+  resolveCompletion(item: LSPCompletionItem): Promise<LSPCompletionItem> {
+    if (this.resolvedCompletions.has(item) || !this.completionUri) {
+      return Promise.resolve(item);
+    }
+
+    let pending = this.completionResolves.get(item);
+    if (!pending) {
+      pending = this.lspService.resolveCompletionItem(this.completionUri, item).then((resolved) => {
+        this.resolvedCompletions.add(resolved);
+        return resolved;
+      });
+      this.completionResolves.set(item, pending);
+    }
+    return pending;
+  }
+
+  /**
+   * Apply an accepted item right away so typing isn't blocked on the server.
+   * If the item wasn't resolved yet, auto-import edits from the resolve
+   * response are applied when they arrive.
+   */
+  private acceptCompletionItem(item: LSPCompletionItem, prefix: string, startColumn: number): void {
+    this.callbacks.onCompletionAccepted?.(item, prefix, startColumn);
+
+    const uri = this.completionUri;
+    if (!uri || this.resolvedCompletions.has(item) || item.additionalTextEdits?.length) return;
+
+    this.resolveCompletion(item).then((resolved) => {
+      if (resolved.additionalTextEdits?.length) {
+        this.callbacks.onCompletionAdditionalEdits?.(uri, resolved.additionalTextEdits);
+      }
+    }).catch((error) => {
+      debugLog(`[LSPIntegration] Completion resolve failed: ${error}`);
+    });
+  }
+
   /**
    * Cancel pending completion.
    */
@@ -536,6 +707,125 @@ export class LSPIntegration {
     }
   }
 
+  // ─────────────────────────────────────────────────────────────────────────
+  // Folding Ranges, Links and Colours
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /**
+   * Refresh folding ranges, links and colours after a short delay
+   * (so a burst of edits sends one set of requests).
+   */
+  scheduleDocumentFeatures(uri: string, delayMs = DOCUMENT_FEATURES_DELAY_MS): void {
+    const existing = this.featureTimers.get(uri);
+    if (existing) clearTimeout(existing);
+
+    this.featureTimers.set(uri, setTimeout(() => {
+      this.featureTimers.delete(uri);
+      void this.refreshDocumentFeatures(uri);
+    }, delayMs));
+  }
+
+  /**
+   * Fetch folding ranges, links and colours for a document now.
+   * Each feature can be turned off in settings.
+   */
+  async refreshDocumentFeatures(uri: string): Promise<DocumentFeatures | null> {
+    if (!this.isEnabled()) return null;
+
+    const useServerFolding = (this.callbacks.getSetting('editor.foldingStrategy') ?? 'auto') === 'auto';
+    const showLinks = this.callbacks.getSetting('editor.links') !== false;
+    const showColors = this.callbacks.getSetting('editor.colorDecorators') !== false;
+
+    try {
+      const [foldingRanges, links, colors] = await Promise.all([
+        useServerFolding ? this.lspService.getFoldingRanges(uri) : Promise.resolve(null),
+        showLinks ? this.lspService.getDocumentLinks(uri) : Promise.resolve([]),
+        showColors ? this.lspService.getDocumentColors(uri) : Promise.resolve([]),
+      ]);
+
+      const features: DocumentFeatures = { foldingRanges, links, colors };
+      this.linksByUri.set(uri, links);
+      this.colorsByUri.set(uri, colors);
+      this.callbacks.onDocumentFeatures?.(uri, features);
+      this.callbacks.onDirty();
+      return features;
+    } catch (error) {
+      debugLog(`[LSPIntegration] Failed to refresh document features: ${error}`);
+      return null;
+    }
+  }
+
+  /**
+   * Open the link covering a range (resolving its target first if needed).
+   */
+  async openLink(uri: string, range: LSPRange): Promise<void> {
+    const link = (this.linksByUri.get(uri) ?? []).find((l) =>
+      comparePositions(l.range.start, range.start) === 0 && comparePositions(l.range.end, range.end) === 0
+    );
+    if (!link) return;
+
+    const resolved = await this.lspService.resolveDocumentLink(uri, link);
+    if (!resolved.target) {
+      this.callbacks.showNotification('Link has no target', 'info');
+      return;
+    }
+
+    const target = parseLinkTarget(resolved.target);
+    if (target.uri.startsWith('file://')) {
+      await this.callbacks.openFile(target.uri, target.line, target.column);
+    } else if (this.callbacks.openExternal) {
+      this.callbacks.openExternal(target.uri);
+    } else {
+      this.callbacks.showNotification(resolved.target, 'info');
+    }
+  }
+
+  /**
+   * Show the colour picker for the colour at a position.
+   * Returns false if there is no colour there.
+   */
+  showColorPicker(uri: string, position: LSPPosition, screenX: number, screenY: number): boolean {
+    const info = (this.colorsByUri.get(uri) ?? []).find((c) =>
+      c.range.start.line === position.line &&
+      c.range.start.character <= position.character &&
+      position.character <= c.range.end.character
+    );
+    if (!info) return false;
+
+    this.colorPicker.showPicker(info.color, screenX, screenY, (color, formatIndex) => {
+      void this.applyColor(uri, info.range, color, formatIndex);
+    });
+
+    // Offer the formats the server would write the original colour in
+    void this.lspService.getColorPresentations(uri, info.color, info.range).then((presentations) => {
+      this.colorPicker.setFormats(presentations.map((p) => p.label));
+    });
+    return true;
+  }
+
+  /**
+   * Replace a colour value with the chosen presentation of a new colour.
+   */
+  private async applyColor(uri: string, range: LSPRange, color: LSPColor, formatIndex: number): Promise<void> {
+    const presentations = await this.lspService.getColorPresentations(uri, color, range);
+    const presentation = presentations[Math.min(formatIndex, presentations.length - 1)];
+    if (!presentation) {
+      this.callbacks.showNotification('Language server returned no colour formats', 'warning');
+      return;
+    }
+
+    this.callbacks.applyTextEdits?.(uri, colorPresentationEdits(presentation, range));
+    this.scheduleDocumentFeatures(uri, 0);
+  }
+
+  private clearDocumentFeatures(uri: string): void {
+    const timer = this.featureTimers.get(uri);
+    if (timer) clearTimeout(timer);
+    this.featureTimers.delete(uri);
+    this.linksByUri.delete(uri);
+    this.colorsByUri.delete(uri);
+  }
+
   // ─────────────────────────────────────────────────────────────────────────
   // Diagnostics
   // ─────────────────────────────────────────────────────────────────────────
@@ -583,7 +873,7 @@ export class LSPIntegration {
   /**
    * Get language ID from file URI.
    */
-  private getLanguageId(uri: string): string | null {
+  getLanguageId(uri: string): string | null {
     const path = uri.replace(/^file:\/\//, '');
     const ext = path.split('.').pop()?.toLowerCase();
     if (!ext) return null;
@@ -611,6 +901,159 @@ export class LSPIntegration {
   getSignatureHelpOverlay(): SignatureHelpOverlay {
     return this.signatureHelp;
   }
+
+  /**
+   * Get the colour picker overlay.
+   */
+  getColorPicker(): ColorPicker {
+    return this.colorPicker;
+  }
+}
+
+// ============================================
+// Helpers
+// ============================================
+
+/**
+ * Plan the text edits for accepting a completion item.
+ *
+ * The main edit replaces the item's textEdit range, or the typed prefix
+ * (startColumn..cursor) when there is none. If the user kept typing after
+ * the request, the range is extended to the cursor. additionalTextEdits
+ * (auto-imports) are included. Edits are returned last-to-first so they
+ * can be applied in order without shifting each other, together with the
+ * final cursor position.
+ */
+export function planCompletionEdits(
+  item: LSPCompletionItem,
+  cursor: LSPPosition,
+  startColumn: number
+): { edits: LSPTextEdit[]; cursor: LSPPosition } {
+  let main: LSPTextEdit;
+  if (item.textEdit) {
+    const { start, end } = item.textEdit.range;
+    const extend = end.line === cursor.line && end.character < cursor.character;
+    main = {
+      range: { start, end: extend ? { ...cursor } : end },
+      newText: item.textEdit.newText,
+    };
+  } else {
+    main = {
+      range: { start: { line: cursor.line, character: startColumn }, end: { ...cursor } },
+      newText: item.insertText ?? item.label,
+    };
+  }
+
+  const edits = [main, ...(item.additionalTextEdits ?? [])].sort(
+    (a, b) => comparePositions(b.range.start, a.range.start)
+  );
+
+  // Cursor goes after the inserted text, shifted by edits before it
+  const before = edits.filter((e) => e !== main && comparePositions(e.range.end, main.range.start) <= 0);
+  const finalCursor = positionAfterEdits(endOfInsert(main), before);
+
+  return { edits, cursor: finalCursor };
+}
+
+/**
+ * Map a position through a set of non-overlapping edits (given in pre-edit
+ * coordinates). Edits after the position don't move it.
+ */
+export function positionAfterEdits(position: LSPPosition, edits: LSPTextEdit[]): LSPPosition {
+  const sorted = [...edits].sort((a, b) => comparePositions(b.range.start, a.range.start));
+  let result = { ...position };
+
+  // Last-to-first: earlier edits' ranges stay valid as later ones are applied
+  for (const edit of sorted) {
+    const { start, end } = edit.range;
+    if (comparePositions(end, result) > 0) continue;
+
+    const inserted = edit.newText.split('\n');
+    const lastLength = inserted[inserted.length - 1]!.length;
+    if (end.line === result.line) {
+      result = {
+        line: start.line + inserted.length - 1,
+        character: (inserted.length > 1 ? 0 : start.character) + lastLength + (result.character - end.character),
+      };
+    } else {
+      result = {
+        line: result.line + (inserted.length - 1) - (end.line - start.line),
+        character: result.character,
+      };
+    }
+  }
+
+  return result;
+}
+
+function endOfInsert(edit: LSPTextEdit): LSPPosition {
+  const inserted = edit.newText.split('\n');
+  const lastLength = inserted[inserted.length - 1]!.length;
+  return {
+    line: edit.range.start.line + inserted.length - 1,
+    character: (inserted.length > 1 ? 0 : edit.range.start.character) + lastLength,
+  };
+}
+
+/**
+ * Split a link target into a URI and an optional 0-based position.
+ * Servers encode positions as `#L12`, `#L12,5` or `#12:5` fragments (1-based).
+ */
+export function parseLinkTarget(target: string): { uri: string; line?: number; column?: number } {
+  const match = target.match(/^(.*)#L?(\d+)(?:[,:](\d+))?$/);
+  if (!match || !match[1]!.startsWith('file://')) {
+    return { uri: target };
+  }
+  return {
+    uri: match[1]!,
+    line: parseInt(match[2]!, 10) - 1,
+    column: match[3] ? parseInt(match[3], 10) - 1 : undefined,
+  };
+}
+
+/**
+ * Convert an LSP colour to #rrggbb (alpha is ignored).
+ */
+export function lspColorToHex(color: LSPColor): string {
+  return rgbToHex({ r: color.red * 255, g: color.green * 255, b: color.blue * 255 });
+}
+
+/**
+ * Edits for applying a colour presentation: its textEdit (or the label over
+ * the colour's range) plus any additional edits.
+ */
+export function colorPresentationEdits(presentation: LSPColorPresentation, range: LSPRange): LSPTextEdit[] {
+  const main = presentation.textEdit ?? { range, newText: presentation.label };
+  return [main, ...(presentation.additionalTextEdits ?? [])];
+}
+
+function comparePositions(a: LSPPosition, b: LSPPosition): number {
+  return a.line !== b.line ? a.line - b.line : a.character - b.character;
+}
+
+/**
+ * Format running server progress for the status bar.
+ *
+ * @example
+ * formatProgressStatus([{ title: 'Loading packages…', percentage: 45, ... }])
+ * // → "⟳ Loading packages… 45%"
+ */
+export function formatProgressStatus(active: LSPProgress[]): string {
+  const running = active.filter((p) => !p.done);
+  const first = running[0];
+  if (!first) return '';
+
+  let text = first.title || first.languageId;
+  if (first.message) {
+    text += `: ${first.message}`;
+  }
+  if (first.percentage !== undefined) {
+    text += ` ${Math.round(first.percentage)}%`;
+  }
+  if (running.length > 1) {
+    text += ` (+${running.length - 1})`;
+  }
+  return `⟳ ${text}`;
 }
 
 // ============================================
@@ -5,9 +5,24 @@
  * Connects the TUI components to the ECP services.
  */
 
-import type { Size, SplitDirection } from '../types.ts';
+import * as path from 'path';
+import type { PaneConfig, Size, SplitConfig, SplitDirection } from '../types.ts';
 import { Window, createWindow, type WindowConfig } from '../window.ts';
 import { Renderer, createRenderer } from '../rendering/renderer.ts';
+import type { ScreenBuffer } from '../rendering/buffer.ts';
+import { GraphicsLayer, type GraphicsPlacement } from '../rendering/graphics-layer.ts';
+import { resolveGraphicsProtocol } from '../ansi/graphics.ts';
+import { isImagePath } from '../../../core/image/index.ts';
+import {
+  checkLayers,
+  detectGraphKind,
+  exportDependencyGraph,
+  loadDependencyGraph,
+  type DependencyGraph,
+  type DependencyGraphFormat,
+  type DependencyLayer,
+  type DependencyNode,
+} from '../../../core/deps/index.ts';
 import { TUIInputHandler, createInputHandler } from '../input/input-handler.ts';
 import {
   BaseElement,
@@ -16,7 +31,9 @@ import {
   GitPanel,
   OutlinePanel,
   GitTimelinePanel,
+  TodoPanel,
   GitDiffBrowser,
+  SearchResultBrowser,
   TerminalSession,
   TerminalPanel,
   AITerminalChat,
@@ -36,12 +53,15 @@ import {
   type TerminalSessionCallbacks,
   type AITerminalChatState,
   type AIProvider,
+  type CustomAIProviderConfig,
+  findCustomAIProvider,
   type TerminalTabDropdownInfo,
   type GitDiffBrowserCallbacks,
   type DiagnosticsProvider,
   type EditCallbacks,
 } from '../elements/index.ts';
 import { createGitDiffArtifact } from '../artifacts/git-diff-artifact.ts';
+import { createSearchResultArtifact, type SearchResultArtifact } from '../artifacts/search-result-artifact.ts';
 import type { Pane } from '../layout/pane.ts';
 
 // Dialog system
@@ -61,6 +81,18 @@ import {
   type KeybindingItem,
 } from '../overlays/index.ts';
 import { TabSwitcherDialog, type TabInfo } from '../overlays/tab-switcher.ts';
+import { AutocompletePopup } from '../overlays/autocomplete-popup.ts';
+import type { Notification } from '../overlays/overlay-manager.ts';
+import {
+  Announcer,
+  ScreenReaderTracker,
+  ReaderBridge,
+  linearizeRegion,
+  describeDiagnostic,
+  toSpeechLine,
+  type Announcement,
+} from '../accessibility/index.ts';
+import { t, setLocale, getLocale } from '../i18n/index.ts';
 
 // Debug utilities
 import { debugLog, isDebugEnabled } from '../../../debug.ts';
@@ -73,13 +105,24 @@ import { defaultThemes, defaultSettings, defaultKeybindings } from '../../../con
 import { localDocumentService, type DocumentService } from '../../../services/document/index.ts';
 import { fileService, type FileService, type WatchHandle } from '../../../services/file/index.ts';
 import { gitCliService } from '../../../services/git/index.ts';
-import type { GitDiffHunk } from '../../../services/git/types.ts';
+import type { GitBlame, GitDiffHunk } from '../../../services/git/types.ts';
+import {
+  LocalSearchService,
+  localSearchService,
+  DEFAULT_COMMENT_TAGS,
+  scanCommentTags,
+  type SearchResult,
+} from '../../../services/search/index.ts';
 import { localSyntaxService, type SyntaxService, type HighlightToken } from '../../../services/syntax/index.ts';
 import {
   localSessionService,
+  AISessionHistory,
+  type AISessionHistoryEntry,
+  formatTranscriptMarkdown,
   type SessionState,
   type SessionDocumentState,
   type SessionTerminalState,
+  type SessionReplState,
   type SessionAIChatState,
   type SessionSQLEditorState,
   type SessionLayoutNode,
@@ -89,10 +132,27 @@ import {
 // Terminal
 import { createPtyBackend } from '../../../terminal/pty-factory.ts';
 import type { PTYBackend } from '../../../terminal/pty-backend.ts';
+import type { TerminalWatchAction } from '../../../terminal/output-watcher.ts';
+import {
+  findEnclosingBlock,
+  formatReplInput,
+  resolveRepl,
+  waitForReplReady,
+  type ReplDefinition,
+} from '../../../terminal/repl.ts';
+import { bell, desktopNotification } from '../ansi/sequences.ts';
 
 // LSP
-import { createLSPIntegration, type LSPIntegration } from './lsp-integration.ts';
-import { localLSPService, type LSPDocumentSymbol } from '../../../services/lsp/index.ts';
+import {
+  createLSPIntegration,
+  formatProgressStatus,
+  planCompletionEdits,
+  positionAfterEdits,
+  lspColorToHex,
+  type LSPIntegration,
+  type DocumentFeatures,
+} from './lsp-integration.ts';
+import { localLSPService, type LSPDocumentSymbol, type ServerDiscoveryResult } from '../../../services/lsp/index.ts';
 
 // Database
 import {
@@ -103,6 +163,7 @@ import {
   SQLCompletionKind,
 } from '../../../services/database/index.ts';
 import { localSecretService } from '../../../services/secret/index.ts';
+import { localNotificationService, type NotificationInfo } from '../../../services/notification/index.ts';
 import type { ConnectionInfo, QueryResult } from '../../../services/database/types.ts';
 import {
   ConnectionPickerDialog,
@@ -119,6 +180,10 @@ import {
   RowDetailsPanel,
   type RowDetailsPanelCallbacks,
   type PrimaryKeyDef,
+  LSPTracePanel,
+  LSPServerNotFoundPanel,
+  ImageViewer,
+  DependencyGraphViewer,
 } from '../elements/index.ts';
 
 // ============================================
@@ -147,6 +212,8 @@ export interface OpenFileOptions {
   line?: number;
   /** Column number to navigate to (0-indexed) */
   column?: number;
+  /** Open as a preview tab, replacing the pane's current preview */
+  preview?: boolean;
 }
 
 // ============================================
@@ -188,6 +255,8 @@ export class TUIClient {
     syntaxSessionId?: string;
     /** Last known file modification time (ms since epoch) */
     lastModified?: number;
+    /** Editor content version last copied to the document service */
+    syncedVersion?: number;
   }>();
 
   /** Internal clipboard for cut/copy/paste */
@@ -196,9 +265,18 @@ export class TUIClient {
   /** Open terminals in panes by element ID -> PTY mapping */
   private paneTerminals = new Map<string, PTYBackend>();
 
+  /** REPL terminals by element ID -> language, REPL program and when it is ready for input */
+  private replTerminals = new Map<string, { languageId: string; repl: ReplDefinition; ready: Promise<void> }>();
+
+  /** REPL terminal used by each document (document URI -> terminal element ID) */
+  private documentRepls = new Map<string, string>();
+
   /** Open AI chats in panes by element ID -> AITerminalChat mapping */
   private paneAIChats = new Map<string, AITerminalChat>();
 
+  /** History of AI chat sessions for the session browser */
+  private aiSessionHistory = new AISessionHistory();
+
   /** Open SQL editors in panes by element ID -> SQLEditor mapping */
   private paneSQLEditors = new Map<string, SQLEditor>();
 
@@ -268,6 +346,19 @@ export class TUIClient {
   /** Saved sidebar width ratio for restore after hiding */
   private savedSidebarRatio: number = 0.2;
 
+  /** Zen mode: the zoomed pane and what to restore on exit, null when off */
+  private zenMode: {
+    paneId: string;
+    layout: PaneConfig | SplitConfig;
+    terminalPanelVisible: boolean;
+  } | null = null;
+
+  /** Recently closed documents, oldest first, for reopening */
+  private closedTabs: Array<{ uri: string; paneId: string; line: number; column: number; scrollTop: number }> = [];
+
+  /** Number of closed tabs remembered */
+  private static readonly MAX_CLOSED_TABS = 20;
+
   /** Git status polling interval */
   private gitStatusInterval: ReturnType<typeof setInterval> | null = null;
 
@@ -301,6 +392,27 @@ export class TUIClient {
   /** Git timeline panel element reference */
   private gitTimelinePanel: GitTimelinePanel | null = null;
 
+  /** TODO panel element reference */
+  private todoPanel: TodoPanel | null = null;
+
+  /** Search service for the TODO index (separate so it never cancels user searches) */
+  private todoSearchService = new LocalSearchService();
+
+  /** Blame per workspace-relative path, for TODO author and age */
+  private todoBlameCache = new Map<string, GitBlame[]>();
+
+  /** Debounce timer for rescanning TODOs after file changes */
+  private todoRescanTimer: ReturnType<typeof setTimeout> | null = null;
+
+  /** Files changed since the last TODO scan (workspace-relative) */
+  private pendingTodoPaths = new Set<string>();
+
+  /** Whether the whole TODO index needs a rescan (set while the panel is hidden) */
+  private todoIndexStale = true;
+
+  /** Changed files rescanned one by one; beyond this the workspace is rescanned */
+  private static readonly MAX_TODO_FILE_RESCANS = 20;
+
   /** Workspace directory watcher */
   private workspaceWatcher: WatchHandle | null = null;
 
@@ -310,6 +422,33 @@ export class TUIClient {
   /** Unsubscribe function for git change events */
   private gitChangeUnsubscribe: (() => void) | null = null;
 
+  /** Unsubscribe function for document service content changes */
+  private documentChangeUnsubscribe: (() => void) | null = null;
+
+  /** Set while copying editor buffers into the document service */
+  private syncingDocuments = false;
+
+  /** Screen-reader mode (tui.accessibility.screenReaderMode) */
+  private screenReaderMode = false;
+
+  /** Announcements for screen-reader mode */
+  private announcer = new Announcer();
+
+  /** Announces focus, overlay and diagnostic changes after each render */
+  private screenReaderTracker = new ScreenReaderTracker(this.announcer);
+
+  /** Connection to an external reader bridge, if configured */
+  private readerBridge: ReaderBridge | null = null;
+
+  /** Toast IDs of notification service notifications, by service ID */
+  private serviceToastIds = new Map<string, string>();
+
+  /** Inline images (Kitty/sixel) drawn over image viewers */
+  private graphicsLayer = new GraphicsLayer('halfblock');
+
+  /** Graphics output to write after the next flush (e.g. protocol switch) */
+  private pendingGraphicsOutput = '';
+
   constructor(options: TUIClientOptions = {}) {
     this.workingDirectory = options.workingDirectory ?? process.cwd();
     this.initialFile = options.initialFile;
@@ -328,6 +467,7 @@ export class TUIClient {
 
     // Initialize services
     this.documentService = localDocumentService;
+    this.todoSearchService.setDocumentService(this.documentService);
     this.fileService = fileService;
     this.syntaxService = localSyntaxService;
 
@@ -348,11 +488,14 @@ export class TUIClient {
       getSetting: (key, defaultValue) => this.configManager.getWithDefault(key as any, defaultValue),
       onDirty: () => this.scheduleRender(),
       onElementCloseRequest: (elementId, element) => this.handleElementCloseRequest(elementId, element),
-      onFocusChange: (_prevElemId, _nextElemId, _prevPaneId, nextPaneId) => {
+      onFocusChange: (_prevElemId, nextElemId, _prevPaneId, nextPaneId) => {
         // Track last focused editor pane (for opening files from sidebar)
         const nextPane = nextPaneId ? this.window.getPaneContainer().getPane(nextPaneId) : null;
         if (nextPane && nextPane.getMode() === 'tabs') {
           this.lastFocusedEditorPaneId = nextPaneId;
+          if (nextElemId) {
+            nextPane.markElementUsed(nextElemId);
+          }
         }
 
         // Look up the focused element and update status bar
@@ -361,6 +504,13 @@ export class TUIClient {
         // Don't update when clicking on sidebar panels themselves
         const isTabsPane = nextPane !== null && nextPane.getMode() === 'tabs';
         this.handleFocusChange(focusedElement, isTabsPane);
+
+        // Zen mode shows a single pane; leaving it ends zen mode
+        if (this.zenMode && nextPaneId && nextPaneId !== this.zenMode.paneId) {
+          this.exitZenMode();
+        } else if (this.zenMode) {
+          this.layoutZenMode();
+        }
       },
       onShowTabDropdown: (paneId, tabs, x, y) => {
         this.showTabSwitcher(paneId, tabs);
@@ -469,6 +619,10 @@ export class TUIClient {
     this.theme = this.loadThemeColors(themeName);
     this.log(`Theme: ${themeName}`);
 
+    // Apply UI language from config
+    setLocale(this.configManager.getWithDefault('workbench.locale', 'auto'));
+    this.log(`Locale: ${getLocale()}`);
+
     // Initialize session service (before layout setup)
     await this.initSessionService();
 
@@ -491,10 +645,20 @@ export class TUIClient {
     // Start window
     this.window.start();
 
+    // Screen-reader announcements
+    this.initAccessibility();
+
+    // Notifications raised by services and extensions
+    this.initNotifications();
+
+    // Inline image protocol for the image viewer
+    this.applyImagePreviewProtocol();
+
     // Start input handler
     this.inputHandler.start();
 
     // Setup keybindings from config
+    this.applyChordSettings();
     this.setupKeybindings();
 
     // Setup initial layout
@@ -546,6 +710,16 @@ export class TUIClient {
       this.log(`Failed to save session: ${error}`);
     }
 
+    // Store open AI chat transcripts and write pending history
+    try {
+      for (const chat of this.paneAIChats.values()) {
+        await this.storeAITranscript(chat);
+      }
+      await this.aiSessionHistory.flush();
+    } catch (error) {
+      this.log(`Failed to save AI session history: ${error}`);
+    }
+
     // Cleanup config manager (stop file watching)
     this.configManager.destroy();
 
@@ -555,13 +729,17 @@ export class TUIClient {
     // Stop git change listener
     this.stopGitChangeListener();
 
+    // Stop document change listener
+    this.stopDocumentChangeListener();
+
     // Stop input handler
     this.inputHandler.stop();
 
     // Stop window
     this.window.stop();
 
-    // Cleanup renderer
+    // Remove inline images, then cleanup renderer
+    this.renderer.writeRaw(this.graphicsLayer.clear(this.renderer.getBuffer()));
     this.renderer.cleanup();
 
     // Shutdown LSP integration
@@ -657,6 +835,20 @@ export class TUIClient {
       }
     }
 
+    // Add TODO panel
+    const todoPanelId = sidePane.addElement('TodoPanel', 'TODOs');
+    const todoPanel = sidePane.getElement(todoPanelId) as TodoPanel | null;
+    if (todoPanel) {
+      this.todoPanel = todoPanel;
+      this.configureTodoPanel(todoPanel);
+
+      // Collapse by default if configured
+      const collapseOnStartup = this.configManager.getWithDefault('tui.todo.collapsedOnStartup', true);
+      if (collapseOnStartup) {
+        sidePane.collapseAccordionSection(todoPanelId);
+      }
+    }
+
     // Split for main editor area (vertical = side by side)
     const editorPaneId = container.split('vertical', sidePane.id);
     this.editorPaneId = editorPaneId;
@@ -696,6 +888,9 @@ export class TUIClient {
     // Start listening for git changes to auto-refresh diff browsers
     this.startGitChangeListener();
 
+    // Apply edits made through the document service (e.g. search replace) to editors
+    this.startDocumentChangeListener();
+
     // Set initial focus to the file tree in sidebar
     if (fileTree) {
       this.window.focusElement(fileTree);
@@ -715,7 +910,7 @@ export class TUIClient {
 
     const callbacks: FileTreeCallbacks = {
       onFileOpen: async (path) => {
-        await this.openFile(`file://${path}`);
+        await this.openFile(`file://${path}`, { preview: true });
       },
       onExpand: async (_path, _expanded) => {
         // Expansion state is tracked in file tree
@@ -1180,6 +1375,176 @@ export class TUIClient {
     }
   }
 
+  /**
+   * Configure the TODO panel and start indexing.
+   */
+  private configureTodoPanel(todoPanel: TodoPanel): void {
+    todoPanel.setCallbacks({
+      onOpen: async (path, line, column) => {
+        const uri = `file://${this.workingDirectory}/${path}`;
+        await this.openFile(uri, { focus: true, preview: true });
+        const docInfo = this.openDocuments.get(uri);
+        const pane = docInfo ? this.findPaneForElement(docInfo.editorId) : null;
+        const element = docInfo ? pane?.getElement(docInfo.editorId) : null;
+        if (element instanceof DocumentEditor) {
+          element.setCursor({ line: line - 1, column });
+          this.window.focusElement(element);
+        }
+      },
+      onRefresh: () => {
+        this.todoBlameCache.clear();
+        void this.rescanTodos();
+      },
+      onBlameNeeded: (paths) => {
+        void this.loadTodoBlame(paths);
+      },
+      onShow: () => {
+        void this.flushTodoRescan();
+      },
+    });
+    this.scheduleTodoRescan();
+  }
+
+  /**
+   * Re-index comment tags across the workspace.
+   */
+  private async rescanTodos(): Promise<void> {
+    const panel = this.todoPanel;
+    if (!panel) return;
+
+    const tags = this.configManager.getWithDefault('tui.todo.tags', DEFAULT_COMMENT_TAGS);
+    this.todoIndexStale = false;
+    this.pendingTodoPaths.clear();
+    panel.setLoading(true);
+    try {
+      this.todoSearchService.setWorkspaceRoot(this.workingDirectory);
+      this.syncEditorDocuments();
+      const found = await scanCommentTags(this.todoSearchService, tags);
+      panel.setTags(found, tags);
+      for (const [path, blame] of this.todoBlameCache) {
+        panel.setBlame(path, blame);
+      }
+    } catch (error) {
+      this.log(`Failed to index TODOs: ${error}`);
+      this.todoIndexStale = true;
+      panel.setLoading(false);
+    }
+  }
+
+  /**
+   * Re-index comment tags in one changed file and splice them into the panel.
+   */
+  private async rescanTodoFile(path: string): Promise<void> {
+    const panel = this.todoPanel;
+    if (!panel) return;
+
+    const tags = this.configManager.getWithDefault('tui.todo.tags', DEFAULT_COMMENT_TAGS);
+    try {
+      this.todoSearchService.setWorkspaceRoot(this.workingDirectory);
+      this.syncEditorDocuments();
+      panel.setFileTags(path, await scanCommentTags(this.todoSearchService, tags, path));
+      const blame = this.todoBlameCache.get(path);
+      if (blame) {
+        panel.setBlame(path, blame);
+      }
+    } catch (error) {
+      this.log(`Failed to index TODOs in ${path}: ${error}`);
+    }
+  }
+
+  /**
+   * Whether the TODO panel can be seen: its section is expanded (or its
+   * tab active) and, if it lives in the sidebar, the sidebar is shown.
+   */
+  private isTodoPanelShown(): boolean {
+    const panel = this.todoPanel;
+    if (!panel?.isVisible()) return false;
+    return this.sidebarVisible || this.findPaneForElement(panel.id)?.id !== this.sidebarPaneId;
+  }
+
+  /**
+   * Load blame for files in the TODO panel (for author grouping and age).
+   */
+  private async loadTodoBlame(paths: string[]): Promise<void> {
+    const repoUri = `file://${this.workingDirectory}`;
+    for (const path of paths) {
+      let blame = this.todoBlameCache.get(path);
+      if (!blame) {
+        try {
+          blame = await gitCliService.blame(repoUri, `${this.workingDirectory}/${path}`);
+        } catch {
+          blame = [];
+        }
+        this.todoBlameCache.set(path, blame);
+      }
+      this.todoPanel?.setBlame(path, blame);
+    }
+  }
+
+  /**
+   * Schedule a debounced TODO rescan of a changed file, or of the whole
+   * workspace when no file is given.
+   */
+  private scheduleTodoRescan(relativePath?: string): void {
+    if (!this.todoPanel) return;
+    if (relativePath) {
+      this.todoBlameCache.delete(relativePath);
+      this.pendingTodoPaths.add(relativePath);
+    } else {
+      this.todoIndexStale = true;
+    }
+
+    if (this.todoRescanTimer) {
+      clearTimeout(this.todoRescanTimer);
+    }
+    this.todoRescanTimer = setTimeout(() => {
+      this.todoRescanTimer = null;
+      void this.flushTodoRescan();
+    }, 500);
+  }
+
+  /**
+   * Run pending TODO rescans: the changed files, or the whole workspace if
+   * the index is stale or many files changed. Nothing is scanned while the
+   * panel is hidden; the index is marked stale and rebuilt once it shows.
+   */
+  private async flushTodoRescan(): Promise<void> {
+    if (!this.isTodoPanelShown()) {
+      if (this.pendingTodoPaths.size > 0) {
+        this.todoIndexStale = true;
+        this.pendingTodoPaths.clear();
+      }
+      return;
+    }
+
+    if (this.todoIndexStale || this.pendingTodoPaths.size > TUIClient.MAX_TODO_FILE_RESCANS) {
+      await this.rescanTodos();
+      return;
+    }
+    const paths = [...this.pendingTodoPaths];
+    this.pendingTodoPaths.clear();
+    for (const path of paths) {
+      await this.rescanTodoFile(path);
+    }
+  }
+
+  /**
+   * After a commit, reload blame only for files that had uncommitted lines;
+   * committing doesn't change the blame of any other file.
+   */
+  private refreshCommittedTodoBlame(): void {
+    const paths: string[] = [];
+    for (const [path, blame] of this.todoBlameCache) {
+      if (blame.some((entry) => /^0+$/.test(entry.commit))) {
+        this.todoBlameCache.delete(path);
+        paths.push(path);
+      }
+    }
+    if (paths.length > 0) {
+      void this.loadTodoBlame(paths);
+    }
+  }
+
   /**
    * Open a file at a specific commit.
    */
@@ -1495,6 +1860,111 @@ export class TUIClient {
     }
   }
 
+  /**
+   * Search the workspace and list the matches in a search result browser.
+   * Open documents are searched and replaced with their unsaved edits.
+   */
+  private async showFindInFiles(): Promise<void> {
+    const selected = this.getFocusedDocumentEditor()?.getSelectedText() ?? '';
+    const input = await this.dialogManager.showInput({
+      title: 'Find in Files',
+      prompt: 'Search the workspace',
+      initialValue: selected.includes('\n') ? '' : selected,
+      selectAll: true,
+    });
+    if (!input.confirmed || !input.value) return;
+    const query = input.value;
+
+    localSearchService.setWorkspaceRoot(this.workingDirectory);
+    this.syncEditorDocuments();
+    let result: SearchResult;
+    try {
+      result = await localSearchService.search(query);
+    } catch (error) {
+      this.window.showNotification(`Search failed: ${error}`, 'error');
+      return;
+    }
+
+    if (result.totalMatches === 0) {
+      this.window.showNotification(`No results for "${query}"`, 'info');
+      return;
+    }
+
+    // Find or create a pane for the results
+    const pane = this.editorPaneId
+      ? this.window.getPaneContainer().getPane(this.editorPaneId)
+      : this.window.getPaneContainer().ensureRoot();
+
+    if (!pane) return;
+
+    const browserId = pane.addElement('SearchResultBrowser', `Search: ${query}`);
+    const browser = pane.getElement(browserId) as SearchResultBrowser | null;
+    if (!browser) return;
+
+    browser.setQuery(query);
+    browser.setArtifacts(this.createSearchArtifacts(result));
+
+    // Replace, then search again so the list shows what is left
+    const replace = async (
+      files: { path: string; matches: { line: number; column: number; length: number }[]; version?: number }[],
+      replacement: string
+    ) => {
+      try {
+        this.syncEditorDocuments();
+        const replaced = await localSearchService.replaceInFiles(files, query, replacement);
+        if (replaced.errors.length > 0) {
+          const paths = replaced.errors.map((e) => e.path).join(', ');
+          this.window.showNotification(`Failed to replace in ${paths}`, 'error');
+        } else if (replaced.matchesSkipped) {
+          const message = `Replaced ${replaced.matchesReplaced} matches; ${replaced.matchesSkipped} changed since the search`;
+          this.window.showNotification(message, 'warning');
+        } else {
+          const message = `Replaced ${replaced.matchesReplaced} matches in ${replaced.filesModified} files`;
+          this.window.showNotification(message, 'success');
+        }
+        result = await localSearchService.search(query);
+        browser.setArtifacts(this.createSearchArtifacts(result));
+        this.scheduleRender();
+      } catch (error) {
+        this.window.showNotification(`Failed to replace: ${error}`, 'error');
+      }
+    };
+
+    browser.setSearchCallbacks({
+      onOpenFile: (path, line, column) => {
+        this.openFile(`file://${this.workingDirectory}/${path}`, { line, column });
+      },
+      onReplace: (filePath, match, replacement) => {
+        const version = result.files.find((file) => file.path === filePath)?.version;
+        replace([{ path: filePath, matches: [match], version }], replacement);
+      },
+      onReplaceInFile: (filePath, replacement) => {
+        replace(result.files.filter((file) => file.path === filePath), replacement);
+      },
+      onReplaceAll: (replacement) => {
+        replace(result.files, replacement);
+      },
+    });
+
+    this.window.focusElement(browser);
+  }
+
+  /**
+   * Convert search results to search result browser artifacts.
+   */
+  private createSearchArtifacts(result: SearchResult): SearchResultArtifact[] {
+    return result.files.map((file) =>
+      createSearchResultArtifact(
+        file.path,
+        result.query,
+        file.matches.map((match) => ({
+          ...match,
+          matchText: match.lineText.slice(match.column, match.column + match.length),
+        }))
+      )
+    );
+  }
+
   /**
    * Configure document editor callbacks.
    * @param uri File URI, or null for untitled documents
@@ -1510,6 +1980,9 @@ export class TUIClient {
         // Update status bar (dirty indicator may change)
         this.updateStatusBarFile(editor);
 
+        // Editing keeps a preview tab
+        this.findPaneForElement(editor.id)?.keepElement(editor.id);
+
         // Only update syntax/LSP for saved files with a URI
         if (uri) {
           // Debounce syntax highlighting updates (200ms delay)
@@ -1548,6 +2021,11 @@ export class TUIClient {
           this.handleCharTyped(editor, uri, char, position);
         }
       },
+      onOpenLink: (link) => {
+        if (uri) {
+          this.lspOpenLink(uri, link);
+        }
+      },
       onFocus: () => {
         // Check for external file changes when editor receives focus (only for saved files)
         if (uri) {
@@ -1607,6 +2085,7 @@ export class TUIClient {
           const fileContent = await this.fileService.read(uri);
           editor.setContent(fileContent.content);
           editor.markSaved();
+          this.syncEditorDocument(editor, true);
           // Refresh git status
           await this.refreshGitStatus();
           await this.updateGitLineChanges(editor, uri);
@@ -1632,9 +2111,9 @@ export class TUIClient {
       editor.setUri(uri);
     }
 
-    // Apply minimap setting
+    // Apply minimap setting (decorative, so off in screen-reader mode)
     const minimapEnabled = this.configManager.getWithDefault('editor.minimap.enabled', false);
-    editor.setMinimapEnabled(minimapEnabled);
+    editor.setMinimapEnabled(minimapEnabled && !this.screenReaderMode);
 
     // Apply word wrap setting (default to 'on' for better terminal experience)
     const wordWrap = this.configManager.getWithDefault('editor.wordWrap', 'on');
@@ -1655,13 +2134,22 @@ export class TUIClient {
       return null; // SQLEditor is not a DocumentEditor
     }
 
+    // Images open in the image viewer
+    if (isImagePath(uri)) {
+      await this.openImageFile(uri, options);
+      return null;
+    }
+
     // Check if already open
     const existing = this.openDocuments.get(uri);
     if (existing) {
       // Find the editor across all panes
       const editor = this.findEditorById(existing.editorId);
       if (editor) {
-        // Editor still exists, just focus it
+        // Editor still exists, just focus it; a non-preview open keeps it
+        if (!options.preview) {
+          this.findPaneForElement(editor.id)?.keepElement(editor.id);
+        }
         if (options.focus !== false) {
           this.window.focusElement(editor);
         }
@@ -1714,8 +2202,23 @@ export class TUIClient {
         return null;
       }
 
+      // A preview open takes the place of the pane's unmodified preview tab
+      if (options.preview && this.configManager.getWithDefault('tui.tabBar.enablePreview', true)) {
+        const previous = pane.getElement(pane.getPreviewElementId() ?? '');
+        if (previous instanceof DocumentEditor && !previous.isModified()) {
+          pane.moveElement(editorId, pane.getElements().indexOf(previous));
+          const previousUri = previous.getUri();
+          if (previousUri) {
+            this.releaseDocument(previousUri);
+          }
+          pane.removeElement(previous.id);
+        }
+        pane.setPreviewElement(editorId);
+      }
+
       // Configure the editor
       this.configureDocumentEditor(editor, uri);
+      editor.setLanguageId(this.detectLanguage(uri));
       editor.setContent(fileContent.content);
 
       // Create syntax session and apply highlighting
@@ -1739,6 +2242,7 @@ export class TUIClient {
         editorId,
         syntaxSessionId,
         lastModified: fileContent.modTime,
+        syncedVersion: editor.getContentVersion(),
       });
 
       // Notify LSP of document open
@@ -1857,6 +2361,7 @@ export class TUIClient {
 
       // Clear modified flag
       editor.markSaved();
+      this.syncEditorDocument(editor, true);
 
       this.window.showNotification('File saved', 'success');
       return true;
@@ -1927,6 +2432,7 @@ export class TUIClient {
       // Reload the file content
       const fileContent = await this.fileService.read(uri);
       editor.setContent(fileContent.content);
+      this.syncEditorDocument(editor, true);
 
       // Update mtime
       docInfo.lastModified = fileContent.modTime;
@@ -2029,15 +2535,23 @@ export class TUIClient {
       const filename = newPath.split('/').pop() || 'untitled';
       editor.setTitle(filename);
 
-      // Update open documents map
-      if (oldUri) {
-        const docInfo = this.openDocuments.get(oldUri);
-        if (docInfo) {
-          this.openDocuments.delete(oldUri);
-          docInfo.lastModified = result.modTime;
-          this.openDocuments.set(newUri, docInfo);
-        }
+      // Re-open the document under its new URI (untitled documents get one now)
+      const previous = oldUri ? this.openDocuments.get(oldUri) : undefined;
+      if (oldUri && previous) {
+        this.openDocuments.delete(oldUri);
+        await this.documentService.close(previous.documentId);
       }
+      const { documentId } = await this.documentService.open({
+        uri: newUri,
+        content,
+        languageId: this.detectLanguage(newPath),
+      });
+      this.openDocuments.set(newUri, {
+        ...(previous ?? { editorId: editor.id }),
+        documentId,
+        lastModified: result.modTime,
+        syncedVersion: editor.getContentVersion(),
+      });
 
       // Notify LSP of the change
       if (oldUri) {
@@ -2054,6 +2568,7 @@ export class TUIClient {
         }
         // Start new syntax session with correct language
         const languageId = this.detectLanguage(newPath);
+        editor.setLanguageId(languageId);
         const syntaxSession = await this.syntaxService.createSession(newPath, languageId, content);
         docInfo.syntaxSessionId = syntaxSession.sessionId;
         if (docInfo.syntaxSessionId) {
@@ -2111,6 +2626,7 @@ export class TUIClient {
         // else: result.value === false means "Don't Save" - proceed with close
       }
 
+      this.rememberClosedTab(element);
       const uri = element.getUri();
       if (uri) {
         const doc = this.openDocuments.get(uri);
@@ -2135,6 +2651,69 @@ export class TUIClient {
     return true;
   }
 
+  /**
+   * Copy an editor's buffer into its document service document if it
+   * changed since the last copy. Editors are synced on save and before
+   * searches rather than on every keystroke.
+   * @param saved The buffer matches the file on disk
+   */
+  private syncEditorDocument(editor: DocumentEditor, saved = false): void {
+    const uri = editor.getUri();
+    const docInfo = uri ? this.openDocuments.get(uri) : undefined;
+    if (!docInfo) return;
+
+    const version = editor.getContentVersion();
+    if (docInfo.syncedVersion !== version) {
+      this.syncingDocuments = true;
+      try {
+        this.documentService.setContent(docInfo.documentId, editor.getContent());
+      } finally {
+        this.syncingDocuments = false;
+      }
+      docInfo.syncedVersion = version;
+    }
+    if (saved) {
+      this.documentService.markClean(docInfo.documentId);
+    }
+  }
+
+  /**
+   * Sync every open editor into the document service, so search and
+   * replace see unsaved edits.
+   */
+  private syncEditorDocuments(): void {
+    for (const docInfo of this.openDocuments.values()) {
+      const editor = this.findEditorById(docInfo.editorId);
+      if (editor) {
+        this.syncEditorDocument(editor);
+      }
+    }
+  }
+
+  /**
+   * Release the document service, syntax and LSP resources of a closed
+   * document.
+   */
+  private releaseDocument(uri: string): void {
+    const doc = this.openDocuments.get(uri);
+    if (!doc) return;
+
+    // Close document service
+    this.documentService.close(doc.documentId).catch((err) => {
+      this.log(`Failed to close document: ${err}`);
+    });
+
+    // Dispose syntax session
+    if (doc.syntaxSessionId) {
+      this.syntaxService.disposeSession(doc.syntaxSessionId);
+    }
+
+    // Notify LSP of document close
+    this.lspDocumentClosed(uri);
+
+    this.openDocuments.delete(uri);
+  }
+
   /**
    * Handle element close request from tab X click.
    * Returns true to proceed with close, false to cancel.
@@ -2170,25 +2749,10 @@ export class TUIClient {
       }
 
       // Clean up document resources
+      this.rememberClosedTab(element);
       const uri = element.getUri();
       if (uri) {
-        const doc = this.openDocuments.get(uri);
-        if (doc) {
-          // Close document service
-          this.documentService.close(doc.documentId).catch((err) => {
-            this.log(`Failed to close document: ${err}`);
-          });
-
-          // Dispose syntax session
-          if (doc.syntaxSessionId) {
-            this.syntaxService.disposeSession(doc.syntaxSessionId);
-          }
-
-          // Notify LSP of document close
-          this.lspDocumentClosed(uri);
-
-          this.openDocuments.delete(uri);
-        }
+        this.releaseDocument(uri);
       }
 
       // Mark session dirty
@@ -2202,6 +2766,7 @@ export class TUIClient {
         pty.kill();
         this.paneTerminals.delete(elementId);
       }
+      this.forgetReplTerminal(elementId);
       return true;
     } else if (element instanceof AITerminalChat) {
       // Show confirmation dialog if the AI chat is running
@@ -2226,6 +2791,7 @@ export class TUIClient {
       // Clean up AI chat tracking
       if (this.paneAIChats.has(elementId)) {
         debugLog(`[TUIClient] Removing AI chat from tracking: ${elementId}`);
+        await this.storeAITranscript(element);
         this.paneAIChats.delete(elementId);
       }
       return true;
@@ -2374,6 +2940,7 @@ export class TUIClient {
 
           this.log(`Workspace file ${event.type}: ${relativePath}`);
           this.scheduleWorkspaceRefresh();
+          this.scheduleTodoRescan(relativePath);
         },
         {
           recursive: true,
@@ -2400,6 +2967,10 @@ export class TUIClient {
       clearTimeout(this.workspaceRefreshTimer);
       this.workspaceRefreshTimer = null;
     }
+    if (this.todoRescanTimer) {
+      clearTimeout(this.todoRescanTimer);
+      this.todoRescanTimer = null;
+    }
   }
 
   /**
@@ -2430,6 +3001,10 @@ export class TUIClient {
     this.gitChangeUnsubscribe = gitCliService.onChange((event) => {
       this.log(`Git change event: ${event.type}`);
       this.notifyDiffBrowsersGitChange(event.type);
+      // Commits change blame authors and dates
+      if (event.type === 'commit') {
+        this.refreshCommittedTodoBlame();
+      }
     });
 
     this.log('Started git change listener');
@@ -2445,6 +3020,72 @@ export class TUIClient {
     }
   }
 
+  /**
+   * Start applying document service content changes to open editors.
+   */
+  private startDocumentChangeListener(): void {
+    this.stopDocumentChangeListener();
+
+    this.documentChangeUnsubscribe = this.documentService.onDidChangeContent((event) => {
+      if (this.syncingDocuments) return;
+      const docInfo = this.openDocuments.get(event.uri);
+      const editor = docInfo ? this.findEditorById(docInfo.editorId) : null;
+      const content = this.documentService.getContent(event.documentId)?.content;
+      if (docInfo && editor && content !== undefined) {
+        this.applyDocumentContent(editor, content);
+        docInfo.syncedVersion = editor.getContentVersion();
+      }
+    });
+  }
+
+  /**
+   * Stop applying document service content changes.
+   */
+  private stopDocumentChangeListener(): void {
+    if (this.documentChangeUnsubscribe) {
+      this.documentChangeUnsubscribe();
+      this.documentChangeUnsubscribe = null;
+    }
+  }
+
+  /**
+   * Bring an editor's buffer to the given content with one undoable edit
+   * covering the changed span, keeping the cursor where it was.
+   */
+  private applyDocumentContent(editor: DocumentEditor, content: string): void {
+    const current = editor.getContent();
+    if (current === content) return;
+
+    // Shrink the edit to the span between the common prefix and suffix
+    let start = 0;
+    const maxPrefix = Math.min(current.length, content.length);
+    while (start < maxPrefix && current[start] === content[start]) start++;
+    let end = 0;
+    const maxSuffix = maxPrefix - start;
+    while (end < maxSuffix && current[current.length - 1 - end] === content[content.length - 1 - end]) end++;
+
+    const toPosition = (offset: number) => {
+      const before = current.slice(0, offset).split('\n');
+      return { line: before.length - 1, character: before[before.length - 1]!.length };
+    };
+
+    const range = { start: toPosition(start), end: toPosition(current.length - end) };
+    const newText = content.slice(start, content.length - end);
+
+    // Lines added or removed above the cursor move it along
+    const cursor = editor.getCursor();
+    if (range.end.line < cursor.line) {
+      cursor.line += newText.split('\n').length - (range.end.line - range.start.line + 1);
+    }
+
+    editor.setCursor(cursor);
+    editor.breakUndoGroup();
+    this.applyEditorTextEdits(editor, [{ range, newText }]);
+    editor.breakUndoGroup();
+    editor.setCursor(cursor);
+    this.scheduleRender();
+  }
+
   /**
    * Notify all active GitDiffBrowsers about a git change.
    */
@@ -2838,8 +3479,8 @@ export class TUIClient {
       return true;
     });
 
-    this.commandHandlers.set('search.findInFiles', () => {
-      this.window.showNotification('Find in files not yet implemented', 'info');
+    this.commandHandlers.set('search.findInFiles', async () => {
+      await this.showFindInFiles();
       return true;
     });
 
@@ -2908,42 +3549,77 @@ export class TUIClient {
       return true;
     });
 
-    // View commands
-    this.commandHandlers.set('workbench.toggleSidebar', () => {
-      this.toggleSidebar();
+    this.commandHandlers.set('editor.keepTab', () => {
+      this.keepActiveTab();
       return true;
     });
 
-    this.commandHandlers.set('workbench.toggleTerminal', () => {
-      this.toggleTerminalPanel();
+    this.commandHandlers.set('editor.togglePinTab', () => {
+      this.togglePinTab();
       return true;
     });
 
-    this.commandHandlers.set('workbench.openSettings', async () => {
-      await this.showSettingsPalette();
+    this.commandHandlers.set('editor.closeOtherTabs', async () => {
+      await this.closeOtherTabs();
       return true;
     });
 
-    this.commandHandlers.set('workbench.openKeybindings', async () => {
-      await this.showKeybindingsPalette();
+    this.commandHandlers.set('editor.closeTabsToRight', async () => {
+      await this.closeTabsToRight();
       return true;
     });
 
-    // Terminal commands
-    this.commandHandlers.set('terminal.new', async () => {
-      await this.createNewTerminal();
+    this.commandHandlers.set('editor.closeSavedTabs', async () => {
+      await this.closeSavedTabs();
       return true;
     });
 
-    this.commandHandlers.set('terminal.close', () => {
-      this.closeActiveTerminal();
+    this.commandHandlers.set('editor.reopenClosedTab', async () => {
+      await this.reopenClosedTab();
       return true;
     });
 
-    this.commandHandlers.set('terminal.focus', () => {
-      this.focusTerminalPanel();
-      return true;
-    });
+    // View commands
+    this.commandHandlers.set('workbench.toggleSidebar', () => {
+      this.toggleSidebar();
+      return true;
+    });
+
+    this.commandHandlers.set('view.toggleZenMode', () => {
+      this.toggleZenMode();
+      return true;
+    });
+
+    this.commandHandlers.set('workbench.toggleTerminal', () => {
+      this.toggleTerminalPanel();
+      return true;
+    });
+
+    this.commandHandlers.set('workbench.openSettings', async () => {
+      await this.showSettingsPalette();
+      return true;
+    });
+
+    this.commandHandlers.set('workbench.openKeybindings', async () => {
+      await this.showKeybindingsPalette();
+      return true;
+    });
+
+    // Terminal commands
+    this.commandHandlers.set('terminal.new', async () => {
+      await this.createNewTerminal();
+      return true;
+    });
+
+    this.commandHandlers.set('terminal.close', () => {
+      this.closeActiveTerminal();
+      return true;
+    });
+
+    this.commandHandlers.set('terminal.focus', () => {
+      this.focusTerminalPanel();
+      return true;
+    });
 
     this.commandHandlers.set('terminal.nextTab', () => {
       if (this.terminalPanel) {
@@ -2964,6 +3640,36 @@ export class TUIClient {
       return true;
     });
 
+    this.commandHandlers.set('terminal.notifyWhenFinished', () => {
+      this.toggleTerminalNotifyWhenFinished();
+      return true;
+    });
+
+    this.commandHandlers.set('terminal.addWatchPattern', async () => {
+      await this.addTerminalWatchPattern();
+      return true;
+    });
+
+    this.commandHandlers.set('terminal.sendToRepl', async () => {
+      await this.sendToRepl('selection');
+      return true;
+    });
+
+    this.commandHandlers.set('terminal.sendBlockToRepl', async () => {
+      await this.sendToRepl('block');
+      return true;
+    });
+
+    this.commandHandlers.set('terminal.clearWatchPatterns', () => {
+      const terminal = this.getFocusedTerminalSession();
+      if (terminal) {
+        terminal.setWatchRules([]);
+        this.markSessionDirty();
+        this.window.showNotification('Terminal watch patterns cleared', 'info');
+      }
+      return true;
+    });
+
     // AI Chat commands
     this.commandHandlers.set('ai.newChat', async () => {
       await this.createNewAIChat();
@@ -2985,11 +3691,26 @@ export class TUIClient {
       return true;
     });
 
+    this.commandHandlers.set('ai.newCustomChat', async () => {
+      await this.newCustomAIChat();
+      return true;
+    });
+
     this.commandHandlers.set('ai.toggleChat', () => {
       this.toggleAIChat();
       return true;
     });
 
+    this.commandHandlers.set('ai.browseSessions', async () => {
+      await this.browseAISessions();
+      return true;
+    });
+
+    this.commandHandlers.set('ai.exportTranscript', async () => {
+      await this.exportAITranscript();
+      return true;
+    });
+
     // Git commands
     this.commandHandlers.set('git.commit', async () => {
       await this.showCommitDialog();
@@ -3097,6 +3818,21 @@ export class TUIClient {
       return true;
     });
 
+    this.commandHandlers.set('view.showDependencyGraph', async () => {
+      await this.showDependencyGraph();
+      return true;
+    });
+
+    this.commandHandlers.set('view.exportDependencyGraphDot', async () => {
+      await this.exportDependencyGraph('dot');
+      return true;
+    });
+
+    this.commandHandlers.set('view.exportDependencyGraphMermaid', async () => {
+      await this.exportDependencyGraph('mermaid');
+      return true;
+    });
+
     this.commandHandlers.set('git.focusPanel', () => {
       this.focusGitPanel();
       return true;
@@ -3539,6 +4275,56 @@ export class TUIClient {
       return true;
     });
 
+    for (let level = 1; level <= 7; level++) {
+      this.commandHandlers.set(`editor.foldLevel${level}`, () => {
+        const element = this.window.getFocusedElement();
+        if (element instanceof DocumentEditor) {
+          element.foldToLevel(level);
+        }
+        return true;
+      });
+    }
+
+    this.commandHandlers.set('editor.foldAllComments', () => {
+      const element = this.window.getFocusedElement();
+      if (element instanceof DocumentEditor) {
+        element.foldAllComments();
+      }
+      return true;
+    });
+
+    this.commandHandlers.set('editor.foldImports', () => {
+      const element = this.window.getFocusedElement();
+      if (element instanceof DocumentEditor) {
+        element.foldImports();
+      }
+      return true;
+    });
+
+    this.commandHandlers.set('editor.foldAllExceptSelected', () => {
+      const element = this.window.getFocusedElement();
+      if (element instanceof DocumentEditor) {
+        element.foldAllExceptSelection();
+      }
+      return true;
+    });
+
+    this.commandHandlers.set('editor.createFoldFromSelection', () => {
+      const element = this.window.getFocusedElement();
+      if (element instanceof DocumentEditor && !element.createFoldFromSelection()) {
+        this.window.showNotification('Select more than one line to create a fold', 'info');
+      }
+      return true;
+    });
+
+    this.commandHandlers.set('editor.removeManualFolds', () => {
+      const element = this.window.getFocusedElement();
+      if (element instanceof DocumentEditor) {
+        element.removeManualFolds();
+      }
+      return true;
+    });
+
     // Selection commands
     this.commandHandlers.set('edit.selectAll', () => {
       const element = this.window.getFocusedElement();
@@ -3590,6 +4376,103 @@ export class TUIClient {
       return true;
     });
 
+    this.commandHandlers.set('workbench.toggleScreenReaderMode', () => {
+      this.configManager.set('tui.accessibility.screenReaderMode', !this.screenReaderMode);
+      this.configManager.saveSettings();
+      this.applyScreenReaderSettings();
+      if (!this.screenReaderMode) {
+        this.window.showNotification('Screen reader mode off', 'info');
+      }
+      return true;
+    });
+
+    this.commandHandlers.set('workbench.repeatLastAnnouncement', () => {
+      const last = this.announcer.getLast();
+      if (last) {
+        this.deliverAnnouncement(last);
+      }
+      return true;
+    });
+
+    this.commandHandlers.set('workbench.announceCursorPosition', () => {
+      this.announceCursorPosition();
+      return true;
+    });
+
+    this.commandHandlers.set('workbench.showNotifications', async () => {
+      await this.dialogManager?.showNotificationCenter();
+      return true;
+    });
+
+    this.commandHandlers.set('workbench.clearNotifications', () => {
+      const overlayManager = this.window.getOverlayManager();
+      overlayManager.clearNotifications();
+      overlayManager.clearNotificationHistory();
+      localNotificationService.clearHistory();
+      return true;
+    });
+
+    this.commandHandlers.set('editor.surroundWithPair', async () => {
+      const editor = this.getFocusedDocumentEditor();
+      if (!editor) return true;
+      if (!editor.getSelectedText()) {
+        this.window.showNotification('Select text to surround', 'info');
+        return true;
+      }
+      const result = await this.dialogManager.showInput({
+        title: 'Surround With',
+        prompt: 'Pair, quote or tag (e.g. ( " <div class="x"> em)',
+        placeholder: '(',
+      });
+      if (result.confirmed && result.value && !editor.surroundSelections(result.value.trim())) {
+        this.window.showNotification(`Cannot surround with "${result.value}"`, 'error');
+      }
+      return true;
+    });
+
+    this.commandHandlers.set('editor.changeSurrounding', async () => {
+      const editor = this.getFocusedDocumentEditor();
+      if (!editor) return true;
+      const target = await this.dialogManager.showInput({
+        title: 'Change Surrounding',
+        prompt: 'Pair to change (t for any tag)',
+        placeholder: '"',
+      });
+      if (!target.confirmed || !target.value) return true;
+      const replacement = await this.dialogManager.showInput({
+        title: 'Change Surrounding',
+        prompt: 'Replace with',
+        placeholder: "'",
+      });
+      if (!replacement.confirmed || !replacement.value) return true;
+      if (!editor.changeSurrounding(target.value.trim(), replacement.value.trim())) {
+        this.window.showNotification(`No surrounding "${target.value}" found`, 'info');
+      }
+      return true;
+    });
+
+    this.commandHandlers.set('editor.deleteSurrounding', async () => {
+      const editor = this.getFocusedDocumentEditor();
+      if (!editor) return true;
+      const target = await this.dialogManager.showInput({
+        title: 'Delete Surrounding',
+        prompt: 'Pair to delete (t for any tag)',
+        placeholder: '(',
+      });
+      if (target.confirmed && target.value && !editor.deleteSurrounding(target.value.trim())) {
+        this.window.showNotification(`No surrounding "${target.value}" found`, 'info');
+      }
+      return true;
+    });
+
+    this.commandHandlers.set('editor.emmet.expandAbbreviation', () => {
+      const editor = this.getFocusedDocumentEditor();
+      if (editor && !editor.expandEmmetAbbreviation()) {
+        this.window.showNotification('No Emmet abbreviation at the cursor', 'info');
+      }
+      return true;
+    });
+
     this.commandHandlers.set('editor.addCursorAbove', () => {
       const element = this.window.getFocusedElement();
       if (element instanceof DocumentEditor) {
@@ -3635,6 +4518,35 @@ export class TUIClient {
       return true;
     });
 
+    this.commandHandlers.set('lsp.showMissingServers', async () => {
+      await this.showMissingServers();
+      return true;
+    });
+
+    this.commandHandlers.set('lsp.showTrace', async () => {
+      await this.showLSPTrace();
+      return true;
+    });
+
+    this.commandHandlers.set('lsp.openLink', async () => {
+      const info = this.getCurrentEditorInfo();
+      const link = info?.editor.getLinkAt({ line: info.position.line, column: info.position.character });
+      if (!info || !link) {
+        this.window.showNotification('No link at cursor', 'info');
+        return true;
+      }
+      await this.lspOpenLink(info.uri, link);
+      return true;
+    });
+
+    this.commandHandlers.set('lsp.pickColor', () => {
+      const info = this.getCurrentEditorInfo();
+      if (!info || !this.lspIntegration?.showColorPicker(info.uri, info.position, info.screenX, info.screenY)) {
+        this.window.showNotification('No color at cursor', 'info');
+      }
+      return true;
+    });
+
     // Database commands
     this.commandHandlers.set('database.newQuery', async () => {
       await this.openNewSqlEditor();
@@ -3681,6 +4593,16 @@ export class TUIClient {
       this.addSidebarPanel('GitTimelinePanel', 'Timeline');
       return true;
     });
+
+    this.commandHandlers.set('sidebar.addTodos', () => {
+      this.addSidebarPanel('TodoPanel', 'TODOs');
+      return true;
+    });
+
+    this.commandHandlers.set('search.showTodos', () => {
+      this.focusTodoPanel();
+      return true;
+    });
   }
 
   /**
@@ -3716,6 +4638,7 @@ export class TUIClient {
           return false;
         },
         when: binding.when ? () => this.evaluateWhenClause(binding.when!) : undefined,
+        title: TUIClient.COMMAND_INFO[binding.command]?.label ?? binding.command,
       });
     }
 
@@ -3779,6 +4702,7 @@ export class TUIClient {
    * Toggle sidebar visibility.
    */
   private toggleSidebar(): void {
+    this.exitZenMode();
     if (this.sidebarVisible) {
       this.hideSidebar();
     } else {
@@ -3805,6 +4729,8 @@ export class TUIClient {
     }
 
     this.sidebarVisible = true;
+    // Catch up on TODO scans skipped while hidden
+    void this.flushTodoRescan();
     this.scheduleRender();
     debugLog('[TUIClient] Sidebar shown');
   }
@@ -3905,12 +4831,73 @@ export class TUIClient {
         this.scheduleRender();
         break;
 
+      case 'workbench.locale':
+        this.applyLocale(value as string);
+        break;
+
+      case 'tui.zenMode.width':
+      case 'tui.zenMode.dimOutsideParagraph':
+        this.layoutZenMode();
+        break;
+
+      case 'tui.todo.tags':
+        this.scheduleTodoRescan();
+        break;
+
+      case 'tui.dependencyGraph.layers': {
+        const viewer = this.findDependencyGraphViewer();
+        const graph = viewer?.getGraph();
+        if (viewer && graph) viewer.setGraph(graph, this.getDependencyLayers());
+        break;
+      }
+
+      case 'tui.dependencyGraph.includeExternal': {
+        const viewer = this.findDependencyGraphViewer();
+        if (viewer) void this.loadDependencyGraphInto(viewer);
+        break;
+      }
+
+      case 'tui.imagePreview.protocol':
+        this.applyImagePreviewProtocol();
+        this.notifySettingsChanged();
+        this.scheduleRender();
+        break;
+
+      case 'tui.accessibility.screenReaderMode':
+      case 'tui.accessibility.announcementChannel':
+      case 'tui.accessibility.bridgeSocket':
+        this.applyScreenReaderSettings();
+        break;
+
+      case 'tui.notifications.doNotShowAgain':
+        this.applySuppressedNotifications();
+        break;
+
+      case 'tui.whichKey.enabled':
+      case 'tui.whichKey.delay':
+      case 'tui.keybindings.leader':
+        this.applyChordSettings();
+        break;
+
       default:
         // Other settings don't need live updates
         break;
     }
   }
 
+  /**
+   * Expand and focus the TODO panel in the sidebar.
+   */
+  private focusTodoPanel(): void {
+    const pane = this.sidebarPaneId ? this.window.getPaneContainer().getPane(this.sidebarPaneId) : null;
+    if (!pane || !this.todoPanel) {
+      this.window.showNotification('TODO panel not found', 'warning');
+      return;
+    }
+    pane.expandAccordionSection(this.todoPanel.id);
+    this.window.focusElement(this.todoPanel);
+  }
+
   /**
    * Focus the git panel.
    */
@@ -3930,6 +4917,226 @@ export class TUIClient {
     this.window.showNotification('Git panel not found', 'warning');
   }
 
+  // ─────────────────────────────────────────────────────────────────────────
+  // Tab Management
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /**
+   * Remember a closed document so it can be reopened.
+   */
+  private rememberClosedTab(element: DocumentEditor): void {
+    const uri = element.getUri();
+    const pane = this.findPaneForElement(element.id);
+    if (!uri || !pane) return;
+
+    const cursor = element.getCursor();
+    this.closedTabs = this.closedTabs.filter((tab) => tab.uri !== uri);
+    this.closedTabs.push({
+      uri,
+      paneId: pane.id,
+      line: cursor.line,
+      column: cursor.column,
+      scrollTop: element.getState().scrollTop ?? 0,
+    });
+    if (this.closedTabs.length > TUIClient.MAX_CLOSED_TABS) {
+      this.closedTabs.shift();
+    }
+  }
+
+  /**
+   * Reopen the most recently closed tab that isn't open again, in its
+   * old pane if it still exists.
+   */
+  private async reopenClosedTab(): Promise<void> {
+    let tab = this.closedTabs.pop();
+    while (tab && this.openDocuments.has(tab.uri)) {
+      tab = this.closedTabs.pop();
+    }
+    if (!tab) {
+      this.window.showNotification('No closed tabs to reopen', 'info');
+      return;
+    }
+
+    const pane = this.window.getPaneContainer().getPane(tab.paneId) ?? undefined;
+    const editor = await this.openFile(tab.uri, { focus: true, pane });
+    if (editor) {
+      editor.setCursorPosition({ line: tab.line, column: tab.column }, false);
+      editor.scrollToLine(tab.scrollTop);
+      this.scheduleRender();
+    }
+  }
+
+  /**
+   * Close tabs one at a time, confirming unsaved changes. Stops at the
+   * first close that is cancelled.
+   */
+  private async closeTabs(pane: Pane, elements: BaseElement[]): Promise<void> {
+    for (const element of elements) {
+      if (!(await this.handleElementCloseRequest(element.id, element))) break;
+      pane.removeElement(element.id);
+    }
+    this.markSessionDirty();
+    this.scheduleRender();
+  }
+
+  /**
+   * Close every unpinned tab in the focused pane except the active one.
+   */
+  private async closeOtherTabs(): Promise<void> {
+    const pane = this.window.getFocusedPane();
+    const active = pane?.getActiveElement();
+    if (!pane || !active) return;
+    const others = pane.getElements().filter((e) => e !== active && !pane.isElementPinned(e.id));
+    await this.closeTabs(pane, others);
+  }
+
+  /**
+   * Close the unpinned tabs right of the active one in the focused pane.
+   */
+  private async closeTabsToRight(): Promise<void> {
+    const pane = this.window.getFocusedPane();
+    if (!pane || pane.getElementCount() === 0) return;
+    const right = pane.getElements()
+      .slice(pane.getActiveElementIndex() + 1)
+      .filter((e) => !pane.isElementPinned(e.id));
+    await this.closeTabs(pane, right);
+  }
+
+  /**
+   * Close the unpinned documents without unsaved changes in the focused
+   * pane.
+   */
+  private async closeSavedTabs(): Promise<void> {
+    const pane = this.window.getFocusedPane();
+    if (!pane) return;
+    const saved = pane.getElements().filter((e) =>
+      e instanceof DocumentEditor && e.getUri() !== null && !e.isModified() && !pane.isElementPinned(e.id)
+    );
+    await this.closeTabs(pane, saved);
+  }
+
+  /**
+   * Pin or unpin the active tab of the focused pane.
+   */
+  private togglePinTab(): void {
+    const pane = this.window.getFocusedPane();
+    const active = pane?.getActiveElement();
+    if (!pane || !active) return;
+    if (pane.isElementPinned(active.id)) {
+      pane.unpinElement(active.id);
+    } else {
+      pane.pinElement(active.id);
+    }
+    this.markSessionDirty();
+  }
+
+  /**
+   * Keep the active tab of the focused pane if it is a preview.
+   */
+  private keepActiveTab(): void {
+    const pane = this.window.getFocusedPane();
+    const active = pane?.getActiveElement();
+    if (pane && active && pane.keepElement(active.id)) {
+      this.markSessionDirty();
+    }
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Zen Mode
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /**
+   * Toggle zen mode.
+   */
+  private toggleZenMode(): void {
+    if (this.zenMode) {
+      this.exitZenMode();
+    } else {
+      this.enterZenMode();
+    }
+  }
+
+  /**
+   * Show the focused editor alone, centered, without sidebar, tab bar,
+   * status bar or terminal panel.
+   */
+  private enterZenMode(): void {
+    if (this.zenMode) return;
+    const pane = this.window.getFocusedPane() ?? this.getTargetEditorPane();
+    if (!pane || pane.getMode() !== 'tabs') {
+      this.window.showNotification('Focus an editor to enter zen mode', 'info');
+      return;
+    }
+
+    const container = this.window.getPaneContainer();
+    this.zenMode = {
+      paneId: pane.id,
+      layout: container.serialize(),
+      terminalPanelVisible: this.terminalPanelVisible,
+    };
+
+    if (this.terminalPanelVisible) {
+      this.hideTerminalPanel();
+    }
+    this.window.setStatusBarVisible(false);
+    pane.setTabBarHidden(true);
+    this.layoutZenMode();
+
+    const element = pane.getActiveElement();
+    if (element) {
+      this.window.focusElement(element);
+    }
+    this.scheduleRender();
+  }
+
+  /**
+   * Restore the layout from before zen mode.
+   */
+  private exitZenMode(): void {
+    const zen = this.zenMode;
+    if (!zen) return;
+    this.zenMode = null;
+
+    const container = this.window.getPaneContainer();
+    const pane = container.getPane(zen.paneId);
+    pane?.setTabBarHidden(false);
+    for (const element of pane?.getElements() ?? []) {
+      if (element instanceof DocumentEditor) element.setDimOutsideParagraph(false);
+    }
+
+    container.setZoomedPane(null);
+    container.restoreRatios(zen.layout);
+    this.window.setStatusBarVisible(true);
+    if (zen.terminalPanelVisible) {
+      void this.showTerminalPanel();
+    }
+    this.scheduleRender();
+  }
+
+  /**
+   * Center the zen pane at `tui.zenMode.width` text columns, plus the
+   * active editor's gutter and scrollbar, and apply paragraph dimming.
+   */
+  private layoutZenMode(): void {
+    if (!this.zenMode) return;
+    const container = this.window.getPaneContainer();
+    const pane = container.getPane(this.zenMode.paneId);
+    if (!pane) {
+      this.zenMode = null;
+      return;
+    }
+
+    const textWidth = this.configManager.getWithDefault('tui.zenMode.width', 100);
+    const dim = this.configManager.getWithDefault('tui.zenMode.dimOutsideParagraph', false);
+    const active = pane.getActiveElement();
+    const chrome = active instanceof DocumentEditor ? active.getGutterWidth() + active.getRightMarginWidth() : 0;
+
+    for (const element of pane.getElements()) {
+      if (element instanceof DocumentEditor) element.setDimOutsideParagraph(dim);
+    }
+    container.setZoomedPane(pane.id, textWidth > 0 ? textWidth + chrome : 0);
+  }
+
   // ─────────────────────────────────────────────────────────────────────────
   // Terminal Panel
   // ─────────────────────────────────────────────────────────────────────────
@@ -3951,6 +5158,7 @@ export class TUIClient {
    */
   private async showTerminalPanel(): Promise<void> {
     if (this.terminalPanelVisible) return;
+    this.exitZenMode();
 
     debugLog('[TUIClient] Showing terminal panel');
 
@@ -3969,11 +5177,21 @@ export class TUIClient {
         },
         getThemeColor: (key: string, fallback = '#ffffff') =>
           this.theme[key] ?? fallback,
+        getSetting: (key, defaultValue) => this.configManager.getWithDefault(key as any, defaultValue),
         isPaneFocused: () => this.terminalPanelVisible,
       });
 
       this.terminalPanel = createTerminalPanel(ctx);
 
+      // Surface watch matches and notifications from panel terminals
+      this.terminalPanel.setSessionCallbacksProvider((session) =>
+        this.createTerminalWatchCallbacks(session, () => {
+          void this.showTerminalPanel();
+          this.terminalPanel?.setActiveTerminal(session.id);
+          this.setTerminalFocus(true);
+        })
+      );
+
       // Set up dropdown callback for terminal tabs
       this.terminalPanel.setTabDropdownCallback((tabs, x, y) => {
         this.showTerminalTabSwitcher(tabs, x, y);
@@ -4074,29 +5292,30 @@ export class TUIClient {
   /**
    * Create a terminal in the specified pane (or focused pane).
    * Unlike the terminal panel, this creates a terminal as a tab in an editor pane.
+   * When `repl` is given the terminal runs that program instead of the shell.
    */
-  private async createTerminalInPane(pane?: Pane): Promise<void> {
+  private async createTerminalInPane(pane?: Pane, repl?: ReplDefinition): Promise<TerminalSession | null> {
     const targetPane = pane ?? this.window.getFocusedPane();
     if (!targetPane) {
       this.window.showNotification('No pane available for terminal', 'warning');
-      return;
+      return null;
     }
 
     // Only allow terminals in tab-mode panes (editor panes)
     if (targetPane.getMode() !== 'tabs') {
       this.window.showNotification('Cannot add terminal to sidebar pane', 'warning');
-      return;
+      return null;
     }
 
     debugLog(`[TUIClient] Creating terminal in pane: ${targetPane.id}`);
 
     // Create terminal element via pane factory
-    const terminalId = targetPane.addElement('TerminalSession', 'Terminal');
+    const terminalId = targetPane.addElement('TerminalSession', repl?.name ?? 'Terminal');
     const terminal = targetPane.getElement(terminalId) as TerminalSession | null;
 
     if (!terminal) {
       this.window.showNotification('Failed to create terminal', 'error');
-      return;
+      return null;
     }
 
     // Get pane bounds for PTY size
@@ -4108,6 +5327,7 @@ export class TUIClient {
         cwd: this.workingDirectory,
         cols: Math.max(1, bounds.width - 1), // -1 for scrollbar
         rows: Math.max(1, bounds.height),
+        ...(repl && { shell: repl.command, args: repl.args }),
       });
 
       // Set up callbacks
@@ -4119,6 +5339,10 @@ export class TUIClient {
         onExit: (code) => {
           debugLog(`[TUIClient] Terminal ${terminalId} exited with code ${code}`);
         },
+        ...this.createTerminalWatchCallbacks(terminal, () => {
+          targetPane.setActiveElement(terminalId);
+          this.window.focusElement(terminal);
+        }),
       });
 
       // Attach PTY to session
@@ -4135,11 +5359,282 @@ export class TUIClient {
       // Focus the terminal
       targetPane.setActiveElement(terminalId);
       this.scheduleRender();
+      return terminal;
     } catch (error) {
       debugLog(`[TUIClient] Failed to create PTY: ${error}`);
-      this.window.showNotification('Failed to start terminal', 'error');
+      this.window.showNotification(repl ? `Failed to start ${repl.name}` : 'Failed to start terminal', 'error');
       // Remove the terminal element since PTY failed
       targetPane.removeElement(terminalId);
+      return null;
+    }
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // REPL
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /**
+   * Send code from the focused document to its REPL: the selection or the
+   * cursor line, or the top-level block around the cursor.
+   */
+  private async sendToRepl(scope: 'selection' | 'block'): Promise<void> {
+    const editor = this.getFocusedDocumentEditor();
+    const uri = editor?.getUri();
+    if (!editor || !uri) {
+      this.window.showNotification('No document focused', 'info');
+      return;
+    }
+
+    const lines = editor.getLines().map((l) => l.text);
+    const cursorLine = editor.getCursor().line;
+    let code: string;
+    if (scope === 'selection' && editor.getSelection()) {
+      code = editor.getSelectedText();
+    } else if (scope === 'block') {
+      const range = findEnclosingBlock(lines, cursorLine);
+      if (!range) {
+        this.window.showNotification('No code block at cursor', 'info');
+        return;
+      }
+      code = lines.slice(range.startLine, range.endLine + 1).join('\n');
+    } else {
+      code = lines[cursorLine] ?? '';
+    }
+    if (code.trim() === '') return;
+
+    const terminal = await this.getDocumentRepl(uri);
+    const pty = terminal?.getPty();
+    const entry = terminal && this.replTerminals.get(terminal.id);
+    if (!pty || !entry) return;
+
+    // A REPL that is just starting enables bracketed paste with its first prompt
+    await entry.ready;
+    pty.write(formatReplInput(code, entry.repl, pty.isBracketedPasteEnabled()));
+    this.scheduleRender();
+  }
+
+  /**
+   * Get the REPL terminal for a document. Uses the document's REPL if it is
+   * still running, then any running REPL for the same language, and starts
+   * a new one otherwise.
+   */
+  private async getDocumentRepl(uri: string): Promise<TerminalSession | null> {
+    const languageId = this.detectLanguage(uri);
+    const candidates = [this.documentRepls.get(uri), ...this.replTerminals.keys()];
+    for (const id of candidates) {
+      if (!id || this.replTerminals.get(id)?.languageId !== languageId) continue;
+      const element = this.findPaneForElement(id)?.getElement(id);
+      if (element instanceof TerminalSession && element.getPty() && !element.hasExited()) {
+        this.documentRepls.set(uri, id);
+        return element;
+      }
+    }
+
+    const overrides = this.configManager.getWithDefault('tui.repl.commands', {});
+    const repl = resolveRepl(languageId, overrides);
+    if (!repl) {
+      this.window.showNotification(`No REPL found for ${languageId}`, 'warning');
+      return null;
+    }
+
+    const terminal = await this.startRepl(languageId, repl, this.getReplPane(uri));
+    if (terminal) {
+      this.documentRepls.set(uri, terminal.id);
+      this.markSessionDirty();
+    }
+    return terminal;
+  }
+
+  /**
+   * Pick the pane for a new REPL: another editor pane if there is one,
+   * otherwise a new split beside the document. Focus stays on the document.
+   */
+  private getReplPane(uri: string): Pane | undefined {
+    const editorId = this.openDocuments.get(uri)?.editorId;
+    const documentPane = editorId ? this.findPaneForElement(editorId) : null;
+    const container = this.window.getPaneContainer();
+    const other = container.getPanes().find((p) => p.getMode() === 'tabs' && p !== documentPane);
+    if (other || !documentPane) return other ?? undefined;
+
+    const newPaneId = container.split('vertical', documentPane.id);
+    return container.getPane(newPaneId) ?? undefined;
+  }
+
+  /**
+   * Start a REPL terminal in a pane and track it.
+   */
+  private async startRepl(languageId: string, repl: ReplDefinition, pane?: Pane): Promise<TerminalSession | null> {
+    const focused = this.window.getFocusedElement();
+    const terminal = await this.createTerminalInPane(pane, repl);
+    if (terminal) {
+      const pty = terminal.getPty();
+      const ready = pty ? waitForReplReady(pty) : Promise.resolve();
+      this.replTerminals.set(terminal.id, { languageId, repl, ready });
+    }
+    if (focused) {
+      this.window.focusElement(focused);
+    }
+    return terminal;
+  }
+
+  /**
+   * Session state for a REPL terminal, or undefined for a shell.
+   */
+  private serializeReplTerminal(elementId: string): SessionReplState | undefined {
+    const entry = this.replTerminals.get(elementId);
+    if (!entry) return undefined;
+    const documents = [...this.documentRepls].filter(([, id]) => id === elementId).map(([uri]) => uri);
+    return { languageId: entry.languageId, repl: entry.repl, documents };
+  }
+
+  /**
+   * Drop a closed REPL terminal and its document associations.
+   */
+  private forgetReplTerminal(elementId: string): void {
+    if (!this.replTerminals.delete(elementId)) return;
+    for (const [uri, id] of this.documentRepls) {
+      if (id === elementId) this.documentRepls.delete(uri);
+    }
+  }
+
+  /**
+   * Create callbacks that surface terminal watch matches, command completion
+   * and OSC 99 notifications from a terminal session.
+   *
+   * @param terminal - The terminal session
+   * @param focus - Brings the terminal's tab to the front and focuses it
+   */
+  private createTerminalWatchCallbacks(
+    terminal: TerminalSession,
+    focus: () => void
+  ): TerminalSessionCallbacks {
+    return {
+      onNotification: (message) => {
+        this.window.showNotification(`${terminal.getTitle()}: ${message}`, 'info');
+      },
+      onWatchMatch: (match) => {
+        const actions = match.rule.actions ?? ['notify'];
+        const message = `${terminal.getTitle()}: ${match.message}`;
+        this.runTerminalWatchActions(actions, message, match.rule.severity ?? 'info', focus, match.rule.command);
+      },
+      onCommandFinished: (event) => {
+        const seconds = Math.round(event.durationMs / 1000);
+        const elapsed = seconds >= 60 ? `${Math.floor(seconds / 60)}m ${seconds % 60}s` : `${seconds}s`;
+        const status = event.exitCode === null ? 'finished' : `exited with code ${event.exitCode}`;
+        const failed = event.exitCode !== null && event.exitCode !== 0;
+        const actions = this.configManager.getWithDefault(
+          'tui.terminal.finishedActions',
+          ['notify', 'bell'] as TerminalWatchAction[]
+        );
+        this.runTerminalWatchActions(
+          actions,
+          `${terminal.getTitle()}: command ${status} after ${elapsed}`,
+          failed ? 'error' : 'success',
+          focus
+        );
+      },
+    };
+  }
+
+  /**
+   * Perform terminal watch actions.
+   */
+  private runTerminalWatchActions(
+    actions: TerminalWatchAction[],
+    message: string,
+    severity: 'info' | 'warning' | 'error' | 'success',
+    focus: () => void,
+    command?: string
+  ): void {
+    for (const action of actions) {
+      switch (action) {
+        case 'notify':
+          this.window.showNotification(message, severity);
+          this.window.addStatusHistory(message, severity);
+          break;
+        case 'bell':
+          this.renderer.writeRaw(bell());
+          break;
+        case 'desktop':
+          this.renderer.writeRaw(desktopNotification('Ultra', message));
+          break;
+        case 'focus':
+          focus();
+          break;
+        case 'command':
+          if (command) {
+            const handler = this.commandHandlers.get(command);
+            if (handler) {
+              Promise.resolve(handler()).catch((error) => {
+                debugLog(`[TUIClient] Terminal watch command ${command} failed: ${error}`);
+              });
+            } else {
+              debugLog(`[TUIClient] Terminal watch command not found: ${command}`);
+            }
+          }
+          break;
+      }
+    }
+    this.scheduleRender();
+  }
+
+  /**
+   * Get the terminal session that currently has focus (panel or pane).
+   */
+  private getFocusedTerminalSession(): TerminalSession | null {
+    if (this.terminalFocused && this.terminalPanel) {
+      return this.terminalPanel.getActiveSession();
+    }
+    const element = this.window.getFocusedElement();
+    return element instanceof TerminalSession ? element : null;
+  }
+
+  /**
+   * Toggle "notify when the running command finishes" on the focused terminal.
+   */
+  private toggleTerminalNotifyWhenFinished(): void {
+    const terminal = this.getFocusedTerminalSession();
+    if (!terminal) {
+      this.window.showNotification('No terminal focused', 'info');
+      return;
+    }
+    const armed = !terminal.isNotifyWhenFinishedArmed();
+    terminal.setNotifyWhenFinished(armed);
+    this.window.showNotification(
+      armed ? 'Will notify when the command finishes' : 'Finish notification cancelled',
+      'info'
+    );
+  }
+
+  /**
+   * Prompt for a regex and add it as a watch rule on the focused terminal.
+   */
+  private async addTerminalWatchPattern(): Promise<void> {
+    const terminal = this.getFocusedTerminalSession();
+    if (!terminal) {
+      this.window.showNotification('No terminal focused', 'info');
+      return;
+    }
+    if (!this.dialogManager) return;
+
+    const result = await this.dialogManager.showInput({
+      title: 'Watch Terminal Output',
+      prompt: 'Notify when a line matches (regular expression):',
+      placeholder: 'FAIL|panic:|listening on :\\d+',
+      validate: (value) => {
+        try {
+          new RegExp(value);
+          return null;
+        } catch {
+          return 'Invalid regular expression';
+        }
+      },
+    });
+
+    if (result.confirmed && result.value) {
+      terminal.addWatchRule({ pattern: result.value, actions: ['notify', 'bell'] });
+      this.markSessionDirty();
+      this.window.showNotification(`Watching for /${result.value}/`, 'info');
     }
   }
 
@@ -4184,6 +5679,7 @@ export class TUIClient {
     sessionId?: string;
     cwd?: string;
     provider?: AIProvider;
+    customProviderId?: string;
   }): Promise<AITerminalChat | null> {
     const targetPane = pane ?? this.window.getFocusedPane();
     if (!targetPane) {
@@ -4197,17 +5693,39 @@ export class TUIClient {
       return null;
     }
 
-    // Get default provider from settings if not specified
-    const provider = options?.provider ?? this.configManager.getWithDefault('ai.defaultProvider', 'claude-code') as AIProvider;
+    // Get default provider from settings if not specified.
+    // ai.defaultProvider may name a custom provider by its ID.
+    let provider: AIProvider;
+    let customProviderId = options?.customProviderId;
+    if (options?.provider) {
+      provider = options.provider;
+    } else {
+      const defaultProvider = this.configManager.getWithDefault('ai.defaultProvider', 'claude-code');
+      if (defaultProvider === 'claude-code' || defaultProvider === 'codex' || defaultProvider === 'gemini') {
+        provider = defaultProvider;
+      } else {
+        provider = 'custom';
+        customProviderId = defaultProvider;
+      }
+    }
+
+    let customProvider: CustomAIProviderConfig | null = null;
+    if (provider === 'custom') {
+      customProvider = findCustomAIProvider(this.getCustomAIProviders(), customProviderId ?? '');
+      if (!customProvider) {
+        this.window.showNotification(`Unknown AI provider: ${customProviderId ?? 'custom'}`, 'warning');
+        return null;
+      }
+    }
 
-    debugLog(`[TUIClient] Creating AI chat in pane: ${targetPane.id}, provider: ${provider}`);
+    debugLog(`[TUIClient] Creating AI chat in pane: ${targetPane.id}, provider: ${provider}${customProvider ? ` (${customProvider.id})` : ''}`);
 
     // Get tab title based on provider
     const titleMap: Record<AIProvider, string> = {
       'claude-code': 'Claude',
       'codex': 'Codex',
       'gemini': 'Gemini',
-      'custom': 'AI Chat',
+      'custom': customProvider?.name || 'AI Chat',
     };
     const tabTitle = titleMap[provider] || 'AI Chat';
 
@@ -4215,9 +5733,15 @@ export class TUIClient {
     const state: AITerminalChatState = {
       provider,
       sessionId: options?.sessionId ?? null,
-      cwd: options?.cwd ?? this.workingDirectory,
+      cwd: options?.cwd ?? this.resolveAIChatCwd(customProvider),
+      customProviderId: customProvider?.id,
     };
 
+    // A resumed session continues its stored transcript instead of replacing it
+    if (options?.sessionId) {
+      await this.aiSessionHistory.resumeTranscript({ ...state, sessionId: options.sessionId });
+    }
+
     const chatId = targetPane.addElement('AgentChat', tabTitle, state);
     const chat = targetPane.getElement(chatId);
 
@@ -4226,11 +5750,24 @@ export class TUIClient {
       return null;
     }
 
-    // Wire up notification callback to show OSC 99 messages in the notification system
+    // Wire up notification callback to show OSC 99 messages in the notification system,
+    // and record sessions in history for the session browser
     chat.setCallbacks({
       onNotification: (message) => {
         this.window.showNotification(message, 'info');
       },
+      onSessionIdCaptured: () => {
+        this.recordAISession(chat);
+        this.markSessionDirty();
+      },
+      onPromptSubmitted: () => {
+        this.recordAISession(chat);
+      },
+      onExit: () => {
+        this.storeAITranscript(chat).catch((error) => {
+          debugLog(`[TUIClient] Failed to store AI transcript: ${error}`);
+        });
+      },
     });
 
     // Track the AI chat for session persistence
@@ -4245,6 +5782,232 @@ export class TUIClient {
     return chat;
   }
 
+  /**
+   * Record an AI chat in session history. Chats without a session ID are skipped.
+   */
+  private recordAISession(chat: AITerminalChat): void {
+    const sessionId = chat.getSessionId();
+    if (!sessionId) return;
+
+    const state = chat.getState();
+    this.aiSessionHistory.record({
+      sessionId,
+      provider: state.provider,
+      customProviderId: state.customProviderId,
+      workspace: this.workingDirectory,
+      cwd: state.cwd,
+      firstPrompt: chat.getFirstPrompt() ?? undefined,
+    }).catch((error) => {
+      debugLog(`[TUIClient] Failed to record AI session: ${error}`);
+    });
+  }
+
+  /**
+   * Pick a past AI session for this workspace in the session browser.
+   */
+  private async pickAISession(title: string): Promise<AISessionHistoryEntry | null> {
+    if (!this.dialogManager) return null;
+
+    const entries = await this.aiSessionHistory.list({ workspace: this.workingDirectory });
+    if (entries.length === 0) {
+      this.window.showNotification('No AI sessions recorded for this workspace', 'info');
+      return null;
+    }
+
+    const result = await this.dialogManager.showFilePicker({
+      files: entries.map((entry) => {
+        const when = new Date(entry.lastActiveAt).toLocaleString();
+        return {
+          path: `${entry.provider}:${entry.sessionId}`,
+          name: entry.firstPrompt || `(session ${entry.sessionId.slice(0, 8)})`,
+          directory: `${this.getAISessionProviderName(entry)} · ${when}`,
+          extension: undefined,
+        };
+      }),
+      placeholder: 'Search AI sessions...',
+      title,
+    });
+    if (!result.confirmed || !result.value) return null;
+
+    return entries.find((e) => `${e.provider}:${e.sessionId}` === result.value!.path) ?? null;
+  }
+
+  /**
+   * Display name of the provider a history entry belongs to.
+   */
+  private getAISessionProviderName(entry: AISessionHistoryEntry): string {
+    const providerNames: Record<AIProvider, string> = {
+      'claude-code': 'Claude',
+      'codex': 'Codex',
+      'gemini': 'Gemini',
+      'custom': 'Custom',
+    };
+    return entry.provider === 'custom'
+      ? findCustomAIProvider(this.getCustomAIProviders(), entry.customProviderId ?? '')?.name ?? entry.customProviderId ?? 'Custom'
+      : providerNames[entry.provider];
+  }
+
+  /**
+   * Find the open chat for a recorded session.
+   */
+  private findOpenAIChat(entry: AISessionHistoryEntry): AITerminalChat | null {
+    for (const chat of this.paneAIChats.values()) {
+      const state = chat.getState();
+      if (
+        state.provider === entry.provider &&
+        state.sessionId === entry.sessionId &&
+        state.customProviderId === entry.customProviderId
+      ) {
+        return chat;
+      }
+    }
+    return null;
+  }
+
+  /**
+   * Store an AI chat's transcript so it can be exported after the chat is gone.
+   */
+  private async storeAITranscript(chat: AITerminalChat): Promise<void> {
+    const state = chat.getState();
+    const lines = chat.getTranscriptLines();
+    if (!state.sessionId || lines.length === 0) return;
+    await this.aiSessionHistory.saveTranscript({ ...state, sessionId: state.sessionId }, lines);
+  }
+
+  /**
+   * List past AI sessions for this workspace and resume the selected one in a new tab.
+   */
+  private async browseAISessions(): Promise<void> {
+    const selected = await this.pickAISession('AI Sessions');
+    if (!selected) return;
+
+    // Focus the chat if this session is already open
+    const chat = this.findOpenAIChat(selected);
+    if (chat) {
+      const container = this.window.getPaneContainer();
+      const paneId = container.findPaneForElement(chat.id);
+      const pane = paneId ? container.getPane(paneId) : null;
+      if (pane) {
+        pane.setActiveElement(chat.id);
+        this.window.focusPane(pane);
+        this.scheduleRender();
+        return;
+      }
+    }
+
+    await this.createNewAIChat(undefined, {
+      provider: selected.provider,
+      sessionId: selected.sessionId,
+      cwd: selected.cwd,
+      customProviderId: selected.customProviderId,
+    });
+  }
+
+  /**
+   * Export the stored transcript of a session picked in the session browser
+   * to a Markdown file. Open chats store their transcript first.
+   */
+  private async exportAITranscript(): Promise<void> {
+    const entry = await this.pickAISession('Export AI Transcript');
+    if (!entry) return;
+
+    const chat = this.findOpenAIChat(entry);
+    if (chat) {
+      await this.storeAITranscript(chat);
+    }
+
+    const lines = await this.aiSessionHistory.getTranscript(entry);
+    if (!lines || lines.length === 0) {
+      this.window.showNotification('No transcript stored for this AI session', 'info');
+      return;
+    }
+
+    const markdown = formatTranscriptMarkdown({
+      providerName: this.getAISessionProviderName(entry),
+      sessionId: entry.sessionId,
+      cwd: entry.cwd,
+      firstPrompt: entry.firstPrompt,
+      createdAt: entry.createdAt,
+    }, lines);
+
+    const date = entry.createdAt.slice(0, 10);
+    const suggestedFilename = `${entry.provider}-session-${date}.md`;
+    let targetPath = path.join(this.workingDirectory, suggestedFilename);
+    if (this.saveAsDialog) {
+      const result = await this.saveAsDialog.showSaveAs({
+        title: 'Export AI Transcript',
+        startPath: this.workingDirectory,
+        suggestedFilename,
+      });
+      if (!result.confirmed || !result.value) return;
+      targetPath = result.value;
+    }
+
+    try {
+      await Bun.write(targetPath, markdown);
+      this.window.showNotification(`Exported transcript: ${targetPath}`, 'info');
+    } catch (error) {
+      const message = error instanceof Error ? error.message : 'Unknown error';
+      this.window.showNotification(`Export failed: ${message}`, 'error');
+    }
+  }
+
+  /**
+   * Get custom AI providers from settings.
+   */
+  private getCustomAIProviders(): CustomAIProviderConfig[] {
+    const providers = this.configManager.getWithDefault('ai.customProviders', []);
+    return Array.isArray(providers) ? providers : [];
+  }
+
+  /**
+   * Resolve the working directory for a new AI chat from the provider's cwd policy.
+   */
+  private resolveAIChatCwd(customProvider: CustomAIProviderConfig | null): string {
+    const policy = customProvider?.cwd ?? 'workspace';
+    if (policy === 'workspace' || policy === '') {
+      return this.workingDirectory;
+    }
+    if (policy === 'file') {
+      const focused = this.window.getFocusedElement();
+      const uri = focused instanceof DocumentEditor ? focused.getUri() : null;
+      return uri?.startsWith('file://') ? path.dirname(uri.slice('file://'.length)) : this.workingDirectory;
+    }
+    const expanded = policy.replace(/^~(?=$|\/)/, process.env.HOME ?? '~');
+    return path.resolve(this.workingDirectory, expanded);
+  }
+
+  /**
+   * Pick a custom AI provider and open a chat with it.
+   */
+  private async newCustomAIChat(): Promise<void> {
+    if (!this.dialogManager) return;
+
+    const providers = this.getCustomAIProviders().filter((p) => p && p.id && p.command);
+    if (providers.length === 0) {
+      this.window.showNotification('No custom AI providers configured (ai.customProviders)', 'info');
+      return;
+    }
+
+    let providerId = providers[0]!.id;
+    if (providers.length > 1) {
+      const result = await this.dialogManager.showFilePicker({
+        files: providers.map((p) => ({
+          path: p.id,
+          name: p.name || p.id,
+          directory: p.command,
+          extension: undefined,
+        })),
+        placeholder: 'Search AI providers...',
+        title: 'New Custom AI Chat',
+      });
+      if (!result.confirmed || !result.value) return;
+      providerId = result.value.path;
+    }
+
+    await this.createNewAIChat(undefined, { provider: 'custom', customProviderId: providerId });
+  }
+
   /**
    * Toggle AI chat visibility / focus.
    * If no AI chat exists, creates one.
@@ -4355,8 +6118,294 @@ export class TUIClient {
       }
     }
 
+    // Sync inline images before flushing (sixel removals repaint cells)
+    const graphicsOutput = this.pendingGraphicsOutput + this.graphicsLayer.update(this.collectGraphicsPlacements(), rendererBuffer);
+    this.pendingGraphicsOutput = '';
+
     // Flush to terminal
     this.renderer.flush();
+    if (graphicsOutput) {
+      this.renderer.writeRaw(graphicsOutput);
+    }
+
+    if (this.screenReaderMode) {
+      this.updateScreenReader(windowBuffer);
+    }
+  }
+
+  /**
+   * Inline images for the image viewers currently on screen. Overlays
+   * would be covered by them, so none are shown while one is open.
+   */
+  private collectGraphicsPlacements(): GraphicsPlacement[] {
+    if (this.graphicsLayer.getProtocol() === 'halfblock' || this.window.hasOverlay()) {
+      return [];
+    }
+    const placements: GraphicsPlacement[] = [];
+    for (const pane of this.window.getPaneContainer().getPanes()) {
+      const element = pane.getActiveElement();
+      if (element instanceof ImageViewer) {
+        const placement = element.getGraphicsPlacement();
+        if (placement) placements.push(placement);
+      }
+    }
+    return placements;
+  }
+
+  /**
+   * Apply tui.imagePreview.protocol.
+   */
+  private applyImagePreviewProtocol(): void {
+    const protocol = resolveGraphicsProtocol(this.configManager.getWithDefault('tui.imagePreview.protocol', 'auto'));
+    this.pendingGraphicsOutput += this.graphicsLayer.setProtocol(protocol, this.renderer.getBuffer());
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Accessibility
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /**
+   * Route announcements and notifications, and apply screen-reader settings.
+   */
+  private initAccessibility(): void {
+    this.announcer.onAnnouncement((announcement) => this.deliverAnnouncement(announcement));
+    this.window.getOverlayManager().onNotification((notification: Notification) => {
+      if (!this.screenReaderMode) return;
+      this.announcer.announce(
+        notification.message,
+        'notification',
+        notification.type === 'error' ? 'assertive' : 'polite'
+      );
+    });
+    this.applyScreenReaderSettings();
+  }
+
+  /**
+   * Enter or leave screen-reader mode and (re)connect the reader bridge.
+   */
+  private applyScreenReaderSettings(): void {
+    const enabled = this.configManager.getWithDefault('tui.accessibility.screenReaderMode', false);
+    const channel = this.configManager.getWithDefault('tui.accessibility.announcementChannel', 'statusLine');
+    const socketPath = this.configManager.getWithDefault('tui.accessibility.bridgeSocket', '');
+    const wasEnabled = this.screenReaderMode;
+    this.screenReaderMode = enabled;
+
+    // Bridge: only while enabled, and reconnect if the path changed
+    const wantsBridge = enabled && channel !== 'statusLine' && socketPath !== '';
+    if (!wantsBridge || this.readerBridge?.getSocketPath() !== socketPath) {
+      this.readerBridge?.dispose();
+      this.readerBridge = wantsBridge ? new ReaderBridge(socketPath) : null;
+    }
+
+    const statusBar = this.window.getStatusBar();
+    if (enabled && channel !== 'ecp') {
+      statusBar.setSpeechLine(statusBar.getSpeechLine() ?? '');
+    } else {
+      statusBar.setSpeechLine(null);
+    }
+
+    if (enabled && !wasEnabled) {
+      // Drop decorative state and announce the current focus from scratch
+      this.window.setStatusItem('lspProgress', '');
+      this.screenReaderTracker.reset();
+      this.announcer.announce('Screen reader mode on', 'info', 'assertive');
+    } else if (!enabled && wasEnabled) {
+      this.renderer.hideCursor();
+    }
+
+    for (const { editor } of this.openDocuments.values()) {
+      editor.setMinimapEnabled(!enabled && this.configManager.getWithDefault('editor.minimap.enabled', false));
+    }
+    this.scheduleRender();
+  }
+
+  /**
+   * Deliver an announcement to the configured channels.
+   */
+  private deliverAnnouncement(announcement: Announcement): void {
+    if (!this.screenReaderMode) return;
+
+    const statusBar = this.window.getStatusBar();
+    if (statusBar.getSpeechLine() !== null) {
+      statusBar.setSpeechLine(toSpeechLine(announcement.text));
+      this.scheduleRender();
+    }
+    this.readerBridge?.send(announcement);
+  }
+
+  /**
+   * Announce what changed since the last render and put the terminal cursor
+   * at the logical focus, where screen readers look for it.
+   */
+  private updateScreenReader(windowBuffer: ScreenBuffer): void {
+    const overlay = this.window.getOverlayManager().getTopOverlay();
+    const element = this.terminalFocused && this.terminalPanel
+      ? this.terminalPanel
+      : this.window.getFocusedElement();
+    const diagnostic = element instanceof DocumentEditor ? element.getDiagnosticAtCursor() : null;
+    const isCompletion = overlay instanceof AutocompletePopup;
+
+    this.screenReaderTracker.update({
+      focus: element ? { id: element.id, description: element.getAccessibleDescription() } : null,
+      overlay: overlay
+        ? {
+            id: overlay.id,
+            text: overlay.getAccessibleText?.() ?? linearizeRegion(windowBuffer, overlay.getBounds()),
+            kind: isCompletion ? 'completion' : 'dialog',
+          }
+        : null,
+      diagnostic: diagnostic
+        ? {
+            text: describeDiagnostic(diagnostic.severity, diagnostic.message, diagnostic.source),
+            isError: diagnostic.severity === 1,
+          }
+        : null,
+    });
+
+    // Completion lists leave the cursor in the editor; dialogs take it
+    let cursor = overlay && !isCompletion ? overlay.getCursorPosition?.() ?? null : null;
+    if (!cursor && overlay && !isCompletion) {
+      const bounds = overlay.getBounds();
+      cursor = { x: bounds.x + 1, y: bounds.y + 1 };
+    }
+    if (!cursor && element) {
+      const bounds = element.getBounds();
+      cursor = element.getCursorScreenPosition() ?? { x: bounds.x, y: bounds.y };
+    }
+    if (cursor) {
+      this.renderer.showCursor(cursor.x, cursor.y);
+    }
+  }
+
+  /**
+   * Speak the cursor position and the diagnostic under it.
+   */
+  private announceCursorPosition(): void {
+    const editor = this.getFocusedDocumentEditor();
+    if (!editor) {
+      const element = this.window.getFocusedElement();
+      if (element) this.announcer.announce(element.getAccessibleDescription(), 'focus', 'assertive');
+      return;
+    }
+
+    const { line, column } = editor.getCursor();
+    const lines = [`${editor.getTitle()}, line ${line + 1}, column ${column + 1}`];
+    const diagnostic = editor.getDiagnosticAtCursor();
+    if (diagnostic) {
+      lines.push(describeDiagnostic(diagnostic.severity, diagnostic.message, diagnostic.source));
+    }
+    this.announcer.announce(lines.join('\n'), 'info', 'assertive');
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Notifications
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /**
+   * Show notification service notifications as toasts, report the user's
+   * choices back, and persist "do not show again".
+   */
+  private initNotifications(): void {
+    const overlayManager = this.window.getOverlayManager();
+    this.applySuppressedNotifications();
+
+    overlayManager.onDoNotShowAgain((key) => {
+      localNotificationService.suppress(key);
+      this.saveSuppressedNotifications();
+    });
+    localNotificationService.onSuppressedChange((keys) => {
+      for (const key of keys) {
+        overlayManager.suppressNotificationKey(key);
+      }
+      this.saveSuppressedNotifications();
+    });
+
+    localNotificationService.onShown((notification) => {
+      const toastId = this.showServiceNotification(notification);
+      if (toastId) {
+        this.serviceToastIds.set(notification.id, toastId);
+      } else {
+        localNotificationService.close(notification.id, 'dismissed');
+      }
+    });
+    localNotificationService.onUpdated((notification) => {
+      const toastId = this.serviceToastIds.get(notification.id);
+      if (!toastId) return;
+      overlayManager.updateNotification(toastId, {
+        message: notification.message,
+        type: notification.severity,
+        percent: notification.progress?.percent,
+      });
+    });
+    localNotificationService.onClosed((event) => {
+      const toastId = this.serviceToastIds.get(event.id);
+      if (!toastId) return;
+      this.serviceToastIds.delete(event.id);
+      overlayManager.closeNotification(toastId, event.reason);
+    });
+  }
+
+  /**
+   * Show a notification service notification as a toast whose buttons
+   * report back to the service.
+   */
+  private showServiceNotification(notification: NotificationInfo): string | null {
+    const { id, progress } = notification;
+    return this.window.notify(notification.message, notification.severity, {
+      duration: notification.timeout,
+      source: notification.source,
+      doNotShowAgainKey: notification.doNotShowAgainKey,
+      actions: notification.actions.map((action) => ({
+        label: action.label,
+        run: () => localNotificationService.invokeAction(id, action.id),
+      })),
+      progress: progress && {
+        percent: progress.percent,
+        onCancel: progress.cancellable ? () => localNotificationService.cancel(id) : undefined,
+      },
+      onClose: (reason) => {
+        // Actions and cancellation report themselves
+        if (reason === 'action' || reason === 'cancelled') return;
+        localNotificationService.close(id, reason);
+      },
+    });
+  }
+
+  /**
+   * Apply the leader key and which-key popup settings.
+   */
+  private applyChordSettings(): void {
+    this.window.setLeaderKey(this.configManager.getWithDefault('tui.keybindings.leader', ''));
+    this.window.setWhichKeyOptions({
+      enabled: this.configManager.getWithDefault('tui.whichKey.enabled', true),
+      delay: this.configManager.getWithDefault('tui.whichKey.delay', 300),
+    });
+  }
+
+  /**
+   * Load "do not show again" keys from settings.
+   */
+  private applySuppressedNotifications(): void {
+    const keys = this.configManager.getWithDefault('tui.notifications.doNotShowAgain', []);
+    this.window.getOverlayManager().setSuppressedNotificationKeys(keys);
+    localNotificationService.setSuppressed(keys);
+  }
+
+  /**
+   * Persist "do not show again" keys if they changed.
+   */
+  private saveSuppressedNotifications(): void {
+    const keys = [
+      ...new Set([
+        ...this.window.getOverlayManager().getSuppressedNotificationKeys(),
+        ...localNotificationService.getSuppressed(),
+      ]),
+    ].sort();
+    const saved = this.configManager.getWithDefault('tui.notifications.doNotShowAgain', []);
+    if (keys.join('\n') === [...saved].sort().join('\n')) return;
+
+    this.configManager.set('tui.notifications.doNotShowAgain', keys);
+    this.configManager.saveSettings();
   }
 
   // ─────────────────────────────────────────────────────────────────────────
@@ -4476,6 +6525,34 @@ export class TUIClient {
         this.log(`Sidebar location updated to: ${newLocation}`);
       }
 
+      // Apply screen-reader mode and reader bridge
+      this.applyScreenReaderSettings();
+
+      // Apply inline image protocol
+      this.applyImagePreviewProtocol();
+
+      // Re-index TODOs (the tag list may have changed)
+      this.scheduleTodoRescan();
+
+      // Apply UI language
+      this.applyLocale(this.configManager.getWithDefault('workbench.locale', 'auto'));
+
+      // Apply LSP trace level
+      this.lspIntegration?.getLSPService().setTraceLevel(
+        this.configManager.getWithDefault('lsp.trace', 'messages')
+      );
+
+      // Apply "do not show again" choices
+      this.applySuppressedNotifications();
+
+      // Apply leader key and which-key popup
+      this.applyChordSettings();
+
+      // Re-fetch folding ranges, links and colours (their settings may have changed)
+      for (const uri of this.openDocuments.keys()) {
+        this.lspIntegration?.scheduleDocumentFeatures(uri, 0);
+      }
+
       // Apply theme from updated config
       const themeName = this.configManager.get('workbench.colorTheme') ?? 'catppuccin-frappe';
       const newTheme = this.loadThemeColors(themeName);
@@ -4562,6 +6639,27 @@ export class TUIClient {
     }
   }
 
+  // ─────────────────────────────────────────────────────────────────────────
+  // Localization
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /**
+   * Switch the UI language and redraw. Open dialogs pick up the new
+   * language the next time they are shown.
+   */
+  private applyLocale(setting: string): void {
+    const previous = getLocale();
+    setLocale(setting);
+    if (getLocale() === previous) return;
+
+    this.log(`Locale changed to ${getLocale()}`);
+    const editor = this.getFocusedDocumentEditor();
+    if (editor) {
+      this.updateStatusBarFile(editor);
+    }
+    this.scheduleRender();
+  }
+
   // ─────────────────────────────────────────────────────────────────────────
   // Status Bar Updates
   // ─────────────────────────────────────────────────────────────────────────
@@ -4616,14 +6714,14 @@ export class TUIClient {
 
     // Position (1-indexed for display)
     const cursor = editor.getCursor();
-    this.window.setStatusItem('position', `Ln ${cursor.line + 1}, Col ${cursor.column + 1}`);
+    this.window.setStatusItem('position', t('statusBar.position', { line: cursor.line + 1, column: cursor.column + 1 }));
 
     // Encoding and line ending (defaults for now)
     this.window.setStatusItem('encoding', 'UTF-8');
     this.window.setStatusItem('eol', 'LF');
 
     // Indentation
-    this.window.setStatusItem('indent', 'Spaces: 2');
+    this.window.setStatusItem('indent', t('statusBar.spaces', { size: 2 }));
   }
 
   /**
@@ -4633,7 +6731,7 @@ export class TUIClient {
     // File name
     const filename = sqlEditor.getFilePath()
       ? sqlEditor.getFilePath()!.split('/').pop() || 'query.sql'
-      : `Query ${(sqlEditor as any).queryId || ''}`;
+      : t('statusBar.query', { id: (sqlEditor as any).queryId || '' });
     const dirtyIndicator = sqlEditor.getIsDirty() ? '● ' : '';
     this.window.setStatusItem('file', `${dirtyIndicator}${filename}`);
 
@@ -4642,14 +6740,14 @@ export class TUIClient {
 
     // Position (1-indexed for display)
     const cursor = docEditor.getCursor();
-    this.window.setStatusItem('position', `Ln ${cursor.line + 1}, Col ${cursor.column + 1}`);
+    this.window.setStatusItem('position', t('statusBar.position', { line: cursor.line + 1, column: cursor.column + 1 }));
 
     // Selection info
     const selection = docEditor.getSelection();
     if (selection) {
       const lines = Math.abs(selection.end.line - selection.start.line) + 1;
       const chars = docEditor.getSelectedText()?.length || 0;
-      this.window.setStatusItem('selection', `${lines} lines, ${chars} chars selected`);
+      this.window.setStatusItem('selection', t('statusBar.selection', { lines, chars }));
     } else {
       this.window.setStatusItem('selection', '');
     }
@@ -4659,7 +6757,7 @@ export class TUIClient {
     this.window.setStatusItem('lsp', lspStatus);
 
     // Indent (default for SQL)
-    this.window.setStatusItem('indent', 'Spaces: 2');
+    this.window.setStatusItem('indent', t('statusBar.spaces', { size: 2 }));
   }
 
   /**
@@ -4846,10 +6944,17 @@ export class TUIClient {
     'editor.previousTab': { label: 'Previous Tab', category: 'Editor' },
     'editor.switchTab': { label: 'Switch Tab (Current Pane)', category: 'Editor' },
     'editor.switchTabAllPanes': { label: 'Switch Tab (All Panes)', category: 'Editor' },
+    'editor.keepTab': { label: 'Keep Tab', category: 'Editor' },
+    'editor.togglePinTab': { label: 'Pin/Unpin Tab', category: 'Editor' },
+    'editor.closeOtherTabs': { label: 'Close Other Tabs', category: 'Editor' },
+    'editor.closeTabsToRight': { label: 'Close Tabs to the Right', category: 'Editor' },
+    'editor.closeSavedTabs': { label: 'Close Saved Tabs', category: 'Editor' },
+    'editor.reopenClosedTab': { label: 'Reopen Closed Tab', category: 'Editor' },
     // Search
     'search.find': { label: 'Find', category: 'Search' },
     'search.replace': { label: 'Find and Replace', category: 'Search' },
     'search.findInFiles': { label: 'Find in Files', category: 'Search' },
+    'search.showTodos': { label: 'Show TODO/FIXME Tags', category: 'Search' },
     // Editor
     'editor.gotoLine': { label: 'Go to Line...', category: 'Editor' },
     'editor.gotoSymbol': { label: 'Go to Symbol in File...', category: 'Editor' },
@@ -4858,9 +6963,30 @@ export class TUIClient {
     'editor.unfold': { label: 'Unfold Region', category: 'Editor' },
     'editor.foldAll': { label: 'Fold All Regions', category: 'Editor' },
     'editor.unfoldAll': { label: 'Unfold All Regions', category: 'Editor' },
+    'editor.foldLevel1': { label: 'Fold Level 1', category: 'Editor' },
+    'editor.foldLevel2': { label: 'Fold Level 2', category: 'Editor' },
+    'editor.foldLevel3': { label: 'Fold Level 3', category: 'Editor' },
+    'editor.foldLevel4': { label: 'Fold Level 4', category: 'Editor' },
+    'editor.foldLevel5': { label: 'Fold Level 5', category: 'Editor' },
+    'editor.foldLevel6': { label: 'Fold Level 6', category: 'Editor' },
+    'editor.foldLevel7': { label: 'Fold Level 7', category: 'Editor' },
+    'editor.foldAllComments': { label: 'Fold All Block Comments', category: 'Editor' },
+    'editor.foldImports': { label: 'Fold Imports', category: 'Editor' },
+    'editor.foldAllExceptSelected': { label: 'Fold All Except Selected', category: 'Editor' },
+    'editor.createFoldFromSelection': { label: 'Create Fold from Selection', category: 'Editor' },
+    'editor.removeManualFolds': { label: 'Remove Manual Fold Ranges', category: 'Editor' },
     'editor.selectLine': { label: 'Select Line', category: 'Editor' },
     'editor.duplicateLine': { label: 'Duplicate Line', category: 'Editor' },
     'editor.duplicateSelection': { label: 'Duplicate Selection', category: 'Editor' },
+    'workbench.toggleScreenReaderMode': { label: 'Toggle Screen Reader Mode', category: 'Accessibility' },
+    'workbench.repeatLastAnnouncement': { label: 'Repeat Last Announcement', category: 'Accessibility' },
+    'workbench.announceCursorPosition': { label: 'Announce Cursor Position', category: 'Accessibility' },
+    'workbench.showNotifications': { label: 'Show Notifications', category: 'View' },
+    'workbench.clearNotifications': { label: 'Clear All Notifications', category: 'View' },
+    'editor.surroundWithPair': { label: 'Surround With...', category: 'Editor' },
+    'editor.changeSurrounding': { label: 'Change Surrounding Pair...', category: 'Editor' },
+    'editor.deleteSurrounding': { label: 'Delete Surrounding Pair...', category: 'Editor' },
+    'editor.emmet.expandAbbreviation': { label: 'Emmet: Expand Abbreviation', category: 'Editor' },
     'editor.addCursorAbove': { label: 'Add Cursor Above', category: 'Editor' },
     'editor.addCursorBelow': { label: 'Add Cursor Below', category: 'Editor' },
     'editor.clearCursors': { label: 'Clear Secondary Cursors', category: 'Editor' },
@@ -4868,7 +6994,11 @@ export class TUIClient {
     'view.splitVertical': { label: 'Split Editor Right', category: 'View' },
     'view.splitHorizontal': { label: 'Split Editor Down', category: 'View' },
     'view.closePane': { label: 'Close Pane', category: 'View' },
+    'view.showDependencyGraph': { label: 'Show Dependency Graph', category: 'View' },
+    'view.exportDependencyGraphDot': { label: 'Export Dependency Graph as DOT', category: 'View' },
+    'view.exportDependencyGraphMermaid': { label: 'Export Dependency Graph as Mermaid', category: 'View' },
     'workbench.toggleSidebar': { label: 'Toggle Sidebar', category: 'View' },
+    'view.toggleZenMode': { label: 'Toggle Zen Mode', category: 'View' },
     'workbench.toggleTerminal': { label: 'Toggle Terminal Panel', category: 'Term' },
     'workbench.focusNextPane': { label: 'Focus Next Pane', category: 'View' },
     'workbench.focusPreviousPane': { label: 'Focus Previous Pane', category: 'View' },
@@ -4887,12 +7017,25 @@ export class TUIClient {
     'terminal.focus': { label: 'Focus Terminal', category: 'Term' },
     'terminal.nextTab': { label: 'Next Terminal Tab', category: 'Term' },
     'terminal.previousTab': { label: 'Previous Terminal Tab', category: 'Term' },
+    'terminal.notifyWhenFinished': { label: 'Notify When Command Finishes', category: 'Term' },
+    'terminal.addWatchPattern': { label: 'Watch Output for Pattern...', category: 'Term' },
+    'terminal.clearWatchPatterns': { label: 'Clear Watch Patterns', category: 'Term' },
+    'terminal.sendToRepl': { label: 'Send Selection or Line to REPL', category: 'Term' },
+    'terminal.sendBlockToRepl': { label: 'Send Enclosing Block to REPL', category: 'Term' },
     // AI Chat
     'ai.newChat': { label: 'New AI Chat (Default)', category: 'AI' },
     'ai.newClaudeChat': { label: 'New Claude Chat', category: 'AI' },
     'ai.newCodexChat': { label: 'New Codex Chat', category: 'AI' },
     'ai.newGeminiChat': { label: 'New Gemini Chat', category: 'AI' },
+    'ai.newCustomChat': { label: 'New Custom AI Chat...', category: 'AI' },
     'ai.toggleChat': { label: 'Toggle AI Chat', category: 'AI' },
+    'ai.browseSessions': { label: 'Browse AI Sessions...', category: 'AI' },
+    'ai.exportTranscript': { label: 'Export AI Chat Transcript to Markdown', category: 'AI' },
+    // LSP
+    'lsp.showTrace': { label: 'Show Language Server Trace...', category: 'LSP' },
+    'lsp.showMissingServers': { label: 'Show Missing Language Servers...', category: 'LSP' },
+    'lsp.openLink': { label: 'Open Link at Cursor', category: 'LSP' },
+    'lsp.pickColor': { label: 'Pick Color at Cursor...', category: 'LSP' },
     // Git
     'git.commit': { label: 'Git: Commit...', category: 'Git' },
     'git.push': { label: 'Git: Push', category: 'Git' },
@@ -5097,7 +7240,7 @@ export class TUIClient {
 
       if (result.confirmed && result.value) {
         const fileUri = `file://${this.workingDirectory}/${result.value.path}`;
-        await this.openFile(fileUri);
+        await this.openFile(fileUri, { preview: true });
       }
     } catch (error) {
       this.log(`Failed to list files: ${error}`);
@@ -5313,21 +7456,18 @@ export class TUIClient {
         isActive: tab.isActive,
         isModified,
         filePath,
+        lastUsed: pane.getElementLastUsed(tab.id),
       };
     });
 
-    // Find current tab for highlight
-    const currentTab = tabInfos.find((t) => t.isActive);
-
-    const result = await this.tabSwitcherDialog.showWithItems(
+    const result = await this.tabSwitcherDialog.showTabs(
       {
         title: 'Switch Tab',
         placeholder: 'Type to filter tabs...',
         showSearchInput: true,
         maxResults: 15,
       },
-      tabInfos,
-      currentTab?.id
+      tabInfos
     );
 
     if (result.confirmed && result.value) {
@@ -5377,6 +7517,7 @@ export class TUIClient {
           isActive: paneId === focusedPaneId && idx === activeIdx,
           isModified,
           filePath,
+          lastUsed: pane.getElementLastUsed(element.id),
         });
       });
     }
@@ -5386,18 +7527,14 @@ export class TUIClient {
       return;
     }
 
-    // Find current tab for highlight
-    const currentTab = allTabs.find((t) => t.isActive);
-
-    const result = await this.tabSwitcherDialog.showWithItems(
+    const result = await this.tabSwitcherDialog.showTabs(
       {
         title: 'Switch Tab (All Panes)',
         placeholder: 'Type to filter tabs...',
         showSearchInput: true,
         maxResults: 15,
       },
-      allTabs,
-      currentTab?.id
+      allTabs
     );
 
     if (result.confirmed && result.value) {
@@ -5812,53 +7949,70 @@ export class TUIClient {
    * Push commits to remote.
    */
   private async gitPush(): Promise<void> {
-    this.window.showNotification('Pushing...', 'info');
-
-    try {
-      const result = await gitCliService.push(this.workingDirectory);
-      if (result.success) {
-        this.window.showNotification('Pushed successfully', 'success');
-      } else {
-        this.window.showNotification(`Push failed: ${result.error}`, 'error');
-      }
-      await this.refreshGitStatus();
-    } catch (error) {
-      this.window.showNotification(`Push failed: ${error}`, 'error');
-    }
+    await this.runGitRemoteCommand('Push', 'Pushing...', 'Pushed successfully', () =>
+      gitCliService.push(this.workingDirectory)
+    );
   }
 
   /**
    * Pull changes from remote.
    */
   private async gitPull(): Promise<void> {
-    this.window.showNotification('Pulling...', 'info');
-
-    try {
-      const result = await gitCliService.pull(this.workingDirectory);
-      if (result.success) {
-        this.window.showNotification('Pulled successfully', 'success');
-      } else {
-        this.window.showNotification(`Pull failed: ${result.error}`, 'error');
-      }
-      await this.refreshGitStatus();
-    } catch (error) {
-      this.window.showNotification(`Pull failed: ${error}`, 'error');
-    }
+    await this.runGitRemoteCommand('Pull', 'Pulling...', 'Pulled successfully', () =>
+      gitCliService.pull(this.workingDirectory)
+    );
   }
 
   /**
    * Fetch from remote.
    */
   private async gitFetch(): Promise<void> {
-    this.window.showNotification('Fetching...', 'info');
+    await this.runGitRemoteCommand('Fetch', 'Fetching...', 'Fetched successfully', async () => {
+      await gitCliService.fetch(this.workingDirectory);
+      return { success: true };
+    });
+  }
+
+  /**
+   * Run a push, pull or fetch behind a progress notification. Failures stay
+   * on screen with a Retry button.
+   */
+  private async runGitRemoteCommand(
+    name: string,
+    progressMessage: string,
+    successMessage: string,
+    run: () => Promise<{ success: boolean; error?: string }>
+  ): Promise<void> {
+    const progressId = this.window.getOverlayManager().showProgress(progressMessage, { source: 'git' });
 
+    let error: string | undefined;
     try {
-      await gitCliService.fetch(this.workingDirectory);
-      this.window.showNotification('Fetched successfully', 'success');
-      await this.refreshGitStatus();
-    } catch (error) {
-      this.window.showNotification(`Fetch failed: ${error}`, 'error');
+      const result = await run();
+      if (!result.success) {
+        error = result.error ?? 'unknown error';
+      }
+    } catch (err) {
+      error = err instanceof Error ? err.message : String(err);
+    }
+    this.window.getOverlayManager().closeNotification(progressId, 'done');
+
+    if (error === undefined) {
+      this.window.showNotification(successMessage, 'success');
+    } else {
+      this.window.notify(`${name} failed: ${error}`, 'error', {
+        source: 'git',
+        actions: [
+          {
+            label: 'Retry',
+            run: () => {
+              void this.runGitRemoteCommand(name, progressMessage, successMessage, run);
+            },
+          },
+        ],
+      });
     }
+
+    await this.refreshGitStatus();
   }
 
   /**
@@ -6564,6 +8718,8 @@ export class TUIClient {
         paneId: pane?.id ?? 'main',
         tabOrder: tabOrder++,
         isActiveInPane: pane?.getActiveElement() === editor,
+        pinned: pane?.isElementPinned(editor.id) || undefined,
+        preview: pane?.getPreviewElementId() === editor.id || undefined,
         unsavedContent,
         undoHistory: state.undoHistory,
       });
@@ -6585,6 +8741,8 @@ export class TUIClient {
           isActiveInPane: pane.getActiveElement() === element,
           cwd: pty.getCwd() || this.workingDirectory,
           title: element.getTitle(),
+          watchRules: element.getState().watchRules,
+          repl: this.serializeReplTerminal(elementId),
         });
         debugLog(`[TUIClient] Serialized terminal: ${elementId} in pane ${pane.id}`);
       }
@@ -6603,6 +8761,7 @@ export class TUIClient {
           tabOrder: tabOrder++,
           isActiveInPane: pane.getActiveElement() === chat,
           provider: chatState.provider,
+          customProviderId: chatState.customProviderId,
           sessionId: chatState.sessionId,
           cwd: chatState.cwd,
           title: chat.getTitle(),
@@ -6829,15 +8988,21 @@ export class TUIClient {
 
         // Restore folded regions
         if (doc.foldedRegions && doc.foldedRegions.length > 0) {
-          for (const line of doc.foldedRegions) {
-            editor.foldLine(line);
-          }
+          editor.restoreFoldedRegions(doc.foldedRegions);
         }
 
         // Restore undo history
         if (doc.undoHistory) {
           editor.setState({ undoHistory: doc.undoHistory });
         }
+
+        // Restore pinned and preview tabs
+        const pane = this.findPaneForElement(editor.id);
+        if (doc.pinned) {
+          pane?.pinElement(editor.id);
+        } else if (doc.preview) {
+          pane?.setPreviewElement(editor.id);
+        }
       } catch (error) {
         this.log(`Failed to restore document ${doc.filePath}: ${error}`);
       }
@@ -6850,8 +9015,19 @@ export class TUIClient {
         try {
           const targetPane = existingPanes.get(termState.paneId);
           if (targetPane) {
-            // Create terminal in the target pane
-            await this.createTerminalInPane(targetPane);
+            // Create terminal (or REPL) in the target pane
+            const replState = termState.repl;
+            const terminal = replState
+              ? await this.startRepl(replState.languageId, replState.repl, targetPane)
+              : await this.createTerminalInPane(targetPane);
+            if (terminal && termState.watchRules) {
+              terminal.setWatchRules(termState.watchRules);
+            }
+            if (terminal && replState) {
+              for (const uri of replState.documents) {
+                this.documentRepls.set(uri, terminal.id);
+              }
+            }
             debugLog(`[TUIClient] Restored terminal in pane ${termState.paneId}`);
           } else {
             debugLog(`[TUIClient] Pane ${termState.paneId} not found for terminal`);
@@ -6874,6 +9050,7 @@ export class TUIClient {
               sessionId: chatState.sessionId ?? undefined,
               cwd: chatState.cwd,
               provider: chatState.provider,
+              customProviderId: chatState.customProviderId,
             });
             debugLog(`[TUIClient] Restored AI chat in pane ${chatState.paneId} (session: ${chatState.sessionId})`);
           } else {
@@ -7116,6 +9293,45 @@ export class TUIClient {
         onCompletionAccepted: (item, prefix, startColumn) => {
           this.applyCompletion(item, prefix, startColumn);
         },
+        onCompletionAdditionalEdits: (uri, edits) => {
+          this.applyDocumentEdits(uri, edits);
+        },
+        onDocumentFeatures: (uri, features) => {
+          this.updateEditorDocumentFeatures(uri, features);
+        },
+        applyTextEdits: (uri, edits) => {
+          this.applyDocumentEdits(uri, edits);
+        },
+        openExternal: (target) => {
+          this.openExternalUrl(target);
+        },
+        onServerNotFound: (languageId, discovery) => {
+          this.window.notify(`${discovery.command} not found for ${languageId}`, 'warning', {
+            actions: [{ label: 'Show Details', run: () => this.showServerNotFound(languageId, discovery) }],
+            doNotShowAgainKey: `lsp.serverNotFound.${languageId}`,
+          });
+          this.scheduleRender();
+        },
+        onProgressChange: (active) => {
+          // Progress updates are decorative redraws a screen reader would re-read
+          if (this.screenReaderMode) return;
+          this.window.setStatusItem('lspProgress', formatProgressStatus(active));
+          this.scheduleRender();
+        },
+        pickMessageAction: async (message) => {
+          if (!this.dialogManager || !message.actions) return null;
+          const result = await this.dialogManager.showFilePicker({
+            files: message.actions.map((action) => ({
+              path: action,
+              name: action,
+              directory: '',
+              extension: undefined,
+            })),
+            placeholder: 'Choose an action...',
+            title: `${message.languageId}: ${message.message}`,
+          });
+          return result.confirmed && result.value ? result.value.path : null;
+        },
       },
       this.workingDirectory
     );
@@ -7123,31 +9339,20 @@ export class TUIClient {
 
   /**
    * Apply a completion item to the current document.
+   * Honours the item's textEdit range and applies additionalTextEdits
+   * (auto-imports) in the same step.
    */
   private applyCompletion(
     item: import('../../../services/lsp/types.ts').LSPCompletionItem,
     prefix: string,
     startColumn: number
   ): void {
-    const element = this.window.getFocusedElement();
-
-    // Get the DocumentEditor - either directly or from SQLEditor
-    let editor: DocumentEditor | null = null;
-    if (element instanceof DocumentEditor) {
-      editor = element;
-    } else if (element instanceof SQLEditor) {
-      editor = element.getDocumentEditor();
-    }
-
+    const editor = this.getFocusedDocumentEditor();
     if (!editor) {
       debugLog('[TUIClient] No document editor focused for completion');
       return;
     }
 
-    // Get the text to insert
-    const insertText = item.insertText ?? item.label;
-
-    // Get the current cursor position
     const cursors = editor.getCursors();
     if (cursors.length === 0) return;
 
@@ -7155,23 +9360,74 @@ export class TUIClient {
     const line = cursor.position.line;
     const column = cursor.position.column;
 
-    // Calculate the range to replace (from startColumn to current column)
-    const deleteLength = column - startColumn;
-
-    if (deleteLength > 0) {
-      // Delete the typed prefix first
+    // Multi-cursor: replace the typed prefix at every cursor
+    if (cursors.length > 1 && !item.textEdit && !item.additionalTextEdits?.length) {
+      const deleteLength = column - startColumn;
       for (let i = 0; i < deleteLength; i++) {
         editor.deleteBackward();
       }
+      editor.insertText(item.insertText ?? item.label);
+      this.scheduleRender();
+      return;
     }
 
-    // Insert the completion text
-    editor.insertText(insertText);
+    editor.clearSecondaryCursors();
+    const plan = planCompletionEdits(item, { line, character: column }, startColumn);
+    this.applyEditorTextEdits(editor, plan.edits);
+    editor.setCursorPosition({ line: plan.cursor.line, column: plan.cursor.character });
+
+    debugLog(`[TUIClient] Applied completion "${item.label}" (prefix "${prefix}", ${plan.edits.length} edits)`);
+    this.scheduleRender();
+  }
+
+  /**
+   * Apply edits to the focused document (late auto-imports, colour changes),
+   * keeping the cursor where the user is typing.
+   */
+  private applyDocumentEdits(
+    uri: string,
+    edits: import('../../../services/lsp/types.ts').LSPTextEdit[]
+  ): void {
+    const editor = this.getFocusedDocumentEditor();
+    if (!editor || editor.getUri() !== uri) return;
 
-    debugLog(`[TUIClient] Applied completion: "${insertText}" (replaced ${deleteLength} chars)`);
+    const cursor = editor.getPrimaryCursor().position;
+    const target = positionAfterEdits({ line: cursor.line, character: cursor.column }, edits);
+    const sorted = [...edits].sort((a, b) =>
+      b.range.start.line - a.range.start.line || b.range.start.character - a.range.start.character
+    );
+    this.applyEditorTextEdits(editor, sorted);
+    editor.setCursorPosition({ line: target.line, column: target.character });
     this.scheduleRender();
   }
 
+  /**
+   * Apply text edits (sorted last-to-first) by selecting each range and typing over it.
+   */
+  private applyEditorTextEdits(
+    editor: DocumentEditor,
+    edits: import('../../../services/lsp/types.ts').LSPTextEdit[]
+  ): void {
+    for (const edit of edits) {
+      const { start, end } = edit.range;
+      editor.setCursorPosition({ line: start.line, column: start.character });
+      if (end.line !== start.line || end.character !== start.character) {
+        editor.setCursorPosition({ line: end.line, column: end.character }, true);
+      }
+      editor.insertText(edit.newText);
+    }
+  }
+
+  /**
+   * Get the focused DocumentEditor, including one embedded in a SQLEditor.
+   */
+  private getFocusedDocumentEditor(): DocumentEditor | null {
+    const element = this.window.getFocusedElement();
+    if (element instanceof DocumentEditor) return element;
+    if (element instanceof SQLEditor) return element.getDocumentEditor();
+    return null;
+  }
+
   /**
    * Get the current document info for LSP.
    * Works with both DocumentEditor and SQLEditor (which embeds a DocumentEditor).
@@ -7408,6 +9664,280 @@ export class TUIClient {
     await this.lspIntegration.triggerSignatureHelp(info.uri, info.position, info.screenX, info.screenY);
   }
 
+  /**
+   * Pick a language server and open its trace in the focused pane.
+   */
+  private async showLSPTrace(): Promise<void> {
+    if (!this.lspIntegration || !this.dialogManager) return;
+    const lspService = this.lspIntegration.getLSPService();
+
+    const servers = lspService.getTracedServers();
+    if (servers.length === 0) {
+      this.window.showNotification('No language servers have been started', 'info');
+      return;
+    }
+
+    let languageId = servers[0]!;
+    if (servers.length > 1) {
+      const result = await this.dialogManager.showFilePicker({
+        files: servers.map((id) => ({ path: id, name: id, directory: '', extension: undefined })),
+        placeholder: 'Select a language server...',
+        title: 'LSP Trace',
+      });
+      if (!result.confirmed || !result.value) return;
+      languageId = result.value.path;
+    }
+
+    const activePane = this.window.getFocusedPane();
+    if (!activePane) return;
+
+    // Reuse an open trace for this server
+    let panel = activePane.getElements().find(
+      (el): el is LSPTracePanel => el instanceof LSPTracePanel && el.getLanguageId() === languageId
+    );
+    if (panel) {
+      activePane.setActiveElement(panel.id);
+      this.scheduleRender();
+      return;
+    }
+
+    const newId = activePane.addElement('LSPTrace', `LSP Trace: ${languageId}`, { languageId, follow: true });
+    const el = newId ? activePane.getElement(newId) : null;
+    if (!(el instanceof LSPTracePanel)) return;
+    panel = el;
+
+    const unsubscribe = lspService.onTrace((id, entry) => {
+      if (id === languageId) panel!.appendEntry(entry);
+    });
+    panel.setCallbacks({
+      onClear: (id) => lspService.clearTrace(id),
+      onClose: unsubscribe,
+    });
+    panel.setEntries(lspService.getTrace(languageId));
+    this.scheduleRender();
+  }
+
+  /**
+   * Pick a language server that couldn't be found and show where it was
+   * looked for.
+   */
+  private async showMissingServers(): Promise<void> {
+    if (!this.lspIntegration || !this.dialogManager) return;
+
+    const missing = this.lspIntegration.getLSPService().getServerStatus()
+      .filter((status) => status.discovery && !status.discovery.resolved);
+    if (missing.length === 0) {
+      this.window.showNotification('All started language servers were found', 'info');
+      return;
+    }
+
+    let status = missing[0]!;
+    if (missing.length > 1) {
+      const result = await this.dialogManager.showFilePicker({
+        files: missing.map((s) => ({ path: s.languageId, name: s.languageId, directory: s.discovery!.command, extension: undefined })),
+        placeholder: 'Select a language server...',
+        title: 'Missing Language Servers',
+      });
+      if (!result.confirmed || !result.value) return;
+      status = missing.find((s) => s.languageId === result.value!.path) ?? status;
+    }
+
+    this.showServerNotFound(status.languageId, status.discovery!);
+  }
+
+  /**
+   * Show where a language server's command was looked for, reusing an
+   * open panel for the language.
+   */
+  private showServerNotFound(languageId: string, discovery: ServerDiscoveryResult): void {
+    const activePane = this.window.getFocusedPane();
+    if (!activePane) return;
+
+    let panel = activePane.getElements().find(
+      (el): el is LSPServerNotFoundPanel => el instanceof LSPServerNotFoundPanel && el.getLanguageId() === languageId
+    );
+    if (panel) {
+      activePane.setActiveElement(panel.id);
+    } else {
+      const newId = activePane.addElement('LSPServerNotFound', `Server Not Found: ${languageId}`, { languageId, discovery });
+      const el = newId ? activePane.getElement(newId) : null;
+      if (!(el instanceof LSPServerNotFoundPanel)) return;
+      panel = el;
+    }
+
+    const target = panel;
+    target.setDiscovery(discovery);
+    target.setCallbacks({
+      onRetry: (id) => {
+        this.retryServer(id, target).catch((error) => {
+          debugLog(`[TUIClient] Retrying ${id} server failed: ${error}`);
+        });
+      },
+    });
+    this.scheduleRender();
+  }
+
+  /**
+   * Look for a language server again and, if found, start it for the open
+   * documents of its language.
+   */
+  private async retryServer(languageId: string, panel: LSPServerNotFoundPanel): Promise<void> {
+    if (!this.lspIntegration) return;
+
+    const documents = [...this.openDocuments].filter(([uri]) => this.lspIntegration!.getLanguageId(uri) === languageId);
+    const discovery = await this.lspIntegration.retryServer(languageId, documents[0]?.[0]);
+    if (!discovery) return;
+
+    panel.setDiscovery(discovery);
+    if (!discovery.resolved) {
+      this.window.showNotification(`${discovery.command} still not found`, 'warning');
+      this.scheduleRender();
+      return;
+    }
+
+    for (const [uri, { editorId }] of documents) {
+      const editor = this.findEditorById(editorId);
+      if (editor) await this.lspDocumentOpened(uri, editor.getContent());
+    }
+    this.window.showNotification(`Started ${discovery.command} for ${languageId}`, 'success');
+    this.scheduleRender();
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Dependency Graph
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /**
+   * Open the workspace dependency graph in the focused pane, reusing an
+   * open viewer.
+   */
+  private async showDependencyGraph(): Promise<void> {
+    if (!detectGraphKind(this.workingDirectory)) {
+      this.window.showNotification('No go.mod, package.json or tsconfig.json in the workspace', 'warning');
+      return;
+    }
+
+    const existing = this.findDependencyGraphViewer();
+    if (existing) {
+      const pane = this.findPaneForElement(existing.id);
+      pane?.setActiveElement(existing.id);
+      this.window.focusElement(existing);
+      this.scheduleRender();
+      return;
+    }
+
+    const pane = this.getTargetEditorPane();
+    if (!pane) return;
+    const viewerId = pane.addElement('DependencyGraph', 'Dependencies');
+    const viewer = pane.getElement(viewerId);
+    if (!(viewer instanceof DependencyGraphViewer)) return;
+
+    viewer.setCallbacks({
+      onOpen: (node) => {
+        void this.openDependencyNode(node);
+      },
+      onRefresh: () => {
+        void this.loadDependencyGraphInto(viewer);
+      },
+    });
+    this.window.focusElement(viewer);
+    await this.loadDependencyGraphInto(viewer);
+  }
+
+  private findDependencyGraphViewer(): DependencyGraphViewer | null {
+    const focused = this.window.getFocusedElement();
+    if (focused instanceof DependencyGraphViewer) return focused;
+    for (const pane of this.window.getPaneContainer().getPanes()) {
+      const viewer = pane.getElements().find((el) => el instanceof DependencyGraphViewer);
+      if (viewer) return viewer as DependencyGraphViewer;
+    }
+    return null;
+  }
+
+  private getDependencyLayers(): DependencyLayer[] {
+    return this.configManager.getWithDefault('tui.dependencyGraph.layers', []);
+  }
+
+  /**
+   * Build the workspace graph.
+   */
+  private async buildDependencyGraph(): Promise<DependencyGraph> {
+    const kind = detectGraphKind(this.workingDirectory);
+    if (!kind) {
+      throw new Error('No go.mod, package.json or tsconfig.json in the workspace');
+    }
+    return loadDependencyGraph(this.workingDirectory, kind, {
+      includeExternal: this.configManager.getWithDefault('tui.dependencyGraph.includeExternal', false),
+    });
+  }
+
+  /**
+   * (Re)build the graph shown in a viewer.
+   */
+  private async loadDependencyGraphInto(viewer: DependencyGraphViewer): Promise<void> {
+    viewer.setLoading(true);
+    try {
+      const graph = await this.buildDependencyGraph();
+      viewer.setGraph(graph, this.getDependencyLayers());
+      const violations = viewer.getViolations().length;
+      if (violations > 0) {
+        this.window.showNotification(`${violations} layer violation${violations !== 1 ? 's' : ''} found`, 'warning');
+      }
+    } catch (error) {
+      const message = error instanceof Error ? error.message : String(error);
+      viewer.setError(`Failed to build graph: ${message}`);
+    }
+  }
+
+  /**
+   * Open a graph node: a module's file, or the first source file of a Go
+   * package.
+   */
+  private async openDependencyNode(node: DependencyNode): Promise<void> {
+    if (!node.path) return;
+    const target = path.join(this.workingDirectory, node.path);
+
+    if (this.findDependencyGraphViewer()?.getGraph()?.kind !== 'go') {
+      await this.openFile(`file://${target}`, { focus: true });
+      return;
+    }
+
+    try {
+      const entries = await this.fileService.readDir(`file://${target}`);
+      const sources = entries
+        .filter((entry) => entry.type === 'file' && entry.name.endsWith('.go'))
+        .map((entry) => entry.name)
+        .sort((a, b) => Number(a.endsWith('_test.go')) - Number(b.endsWith('_test.go')) || a.localeCompare(b));
+      if (sources.length === 0) {
+        this.window.showNotification(`No Go files in ${node.path}`, 'info');
+        return;
+      }
+      await this.openFile(`file://${path.join(target, sources[0]!)}`, { focus: true });
+    } catch (error) {
+      this.log(`Failed to open package ${node.id}: ${error}`);
+    }
+  }
+
+  /**
+   * Export the dependency graph to a new untitled document.
+   */
+  private async exportDependencyGraph(format: DependencyGraphFormat): Promise<void> {
+    let graph = this.findDependencyGraphViewer()?.getGraph() ?? null;
+    if (!graph) {
+      try {
+        graph = await this.buildDependencyGraph();
+      } catch (error) {
+        const message = error instanceof Error ? error.message : String(error);
+        this.window.showNotification(`Failed to build graph: ${message}`, 'error');
+        return;
+      }
+    }
+
+    const text = exportDependencyGraph(graph, format, { violations: checkLayers(graph, this.getDependencyLayers()) });
+    const editor = this.newFile();
+    editor?.setContent(text);
+  }
+
   /**
    * Notify LSP that a document was opened.
    */
@@ -7619,6 +10149,68 @@ export class TUIClient {
     }
   }
 
+  /**
+   * Push folding ranges, links and colours from the language server into
+   * the document's editor.
+   */
+  private updateEditorDocumentFeatures(
+    uri: string,
+    features: DocumentFeatures
+  ): void {
+    const docInfo = this.openDocuments.get(uri);
+    if (!docInfo) return;
+
+    const editor = this.findEditorById(docInfo.editorId);
+    if (!editor || !(editor instanceof DocumentEditor)) return;
+
+    editor.setFoldingRanges(features.foldingRanges);
+    editor.setDocumentLinks(features.links.map((link) => ({
+      startLine: link.range.start.line,
+      startColumn: link.range.start.character,
+      endLine: link.range.end.line,
+      endColumn: link.range.end.character,
+      target: link.target,
+      tooltip: link.tooltip,
+    })));
+    editor.setColorDecorators(features.colors
+      .filter((c) => c.range.start.line === c.range.end.line)
+      .map((c) => ({
+        line: c.range.start.line,
+        startColumn: c.range.start.character,
+        endColumn: c.range.end.character,
+        color: lspColorToHex(c.color),
+      })));
+  }
+
+  /**
+   * Open a document link (Ctrl+click or Open Link at Cursor).
+   */
+  private async lspOpenLink(
+    uri: string,
+    link: import('../elements/document-editor.ts').DocumentLinkInfo
+  ): Promise<void> {
+    if (!this.lspIntegration) return;
+    await this.lspIntegration.openLink(uri, {
+      start: { line: link.startLine, character: link.startColumn },
+      end: { line: link.endLine, character: link.endColumn },
+    });
+  }
+
+  /**
+   * Open a URL with the system handler.
+   */
+  private openExternalUrl(url: string): void {
+    const command = process.platform === 'darwin'
+      ? ['open', url]
+      : process.platform === 'win32' ? ['cmd', '/c', 'start', '', url] : ['xdg-open', url];
+    try {
+      Bun.spawn(command, { stdout: 'ignore', stderr: 'ignore' });
+    } catch (error) {
+      debugLog(`[TUIClient] Failed to open ${url}: ${error}`);
+      this.window.showNotification(`Could not open ${url}`, 'error');
+    }
+  }
+
   /**
    * Update diagnostics for a document editor.
    * Converts LSP diagnostics to the DocumentEditor's format.
@@ -7806,6 +10398,47 @@ export class TUIClient {
     return null;
   }
 
+  /**
+   * Open an image file in the image viewer.
+   */
+  private async openImageFile(uri: string, options: OpenFileOptions = {}): Promise<ImageViewer | null> {
+    const activePane = this.getTargetEditorPane(options.pane);
+    if (!activePane) return null;
+
+    const fileUri = uri.startsWith('file://') ? uri : `file://${uri}`;
+
+    // Check if already open
+    for (const el of activePane.getElements()) {
+      if (el instanceof ImageViewer && el.getUri() === fileUri) {
+        if (options.focus !== false) {
+          activePane.setActiveElement(el.id);
+        }
+        return el;
+      }
+    }
+
+    const filename = fileUri.split('/').pop() ?? 'image';
+    const viewerId = activePane.addElement('ImageViewer', filename);
+    const viewer = viewerId ? activePane.getElement(viewerId) : null;
+    if (!(viewer instanceof ImageViewer)) {
+      this.window.showNotification('Failed to create image viewer', 'error');
+      return null;
+    }
+
+    try {
+      viewer.setImage(await this.fileService.readBytes(fileUri), fileUri);
+    } catch (error) {
+      viewer.setError(fileUri, error instanceof Error ? error.message : String(error));
+    }
+
+    if (options.focus !== false) {
+      activePane.setActiveElement(viewer.id);
+    }
+
+    this.scheduleRender();
+    return viewer;
+  }
+
   /**
    * Set up callbacks for a SQL editor.
    */
@@ -8583,7 +11216,7 @@ export class TUIClient {
    * New panels are added at the top of the sidebar.
    */
   private addSidebarPanel(
-    type: 'FileTree' | 'GitPanel' | 'OutlinePanel' | 'GitTimelinePanel',
+    type: 'FileTree' | 'GitPanel' | 'OutlinePanel' | 'GitTimelinePanel' | 'TodoPanel',
     title: string
   ): void {
     if (!this.sidebarPaneId) {
@@ -8634,6 +11267,10 @@ export class TUIClient {
           this.gitTimelinePanel = element as GitTimelinePanel;
           this.configureGitTimelinePanel(this.gitTimelinePanel);
           break;
+        case 'TodoPanel':
+          this.todoPanel = element as TodoPanel;
+          this.configureTodoPanel(this.todoPanel);
+          break;
       }
     }
 
@@ -10,7 +10,8 @@ import { mkdir, cp, rm } from 'fs/promises';
 import * as fs from 'fs';
 import { debugLog } from '../../../debug.ts';
 import type { EditorSettings } from '../../../config/settings.ts';
-import type { KeyBinding } from '../../../services/session/types.ts';
+import type { KeyBinding, CustomAIProviderConfig } from '../../../services/session/types.ts';
+import type { TerminalWatchRule, TerminalWatchAction } from '../../../terminal/output-watcher.ts';
 
 // Import embedded defaults (generated at build time from JSONC files)
 // Source of truth: config/default-settings.jsonc and config/default-keybindings.jsonc
@@ -94,6 +95,14 @@ export interface TUISettings extends Partial<EditorSettings> {
 
   /** Number of lines to keep in terminal scrollback buffer */
   'tui.terminal.scrollback'?: number;
+  /** Output watch rules applied to every terminal */
+  'tui.terminal.watchRules'?: TerminalWatchRule[];
+  /** Regex matching the shell prompt, used to detect command completion */
+  'tui.terminal.promptPattern'?: string;
+  /** Actions to run when a watched command finishes */
+  'tui.terminal.finishedActions'?: TerminalWatchAction[];
+  /** REPL command line per language ID, overriding the built-in choice */
+  'tui.repl.commands'?: Record<string, string>;
 
   // ─────────────────────────────────────────────────────────────────────────
   // Git
@@ -122,8 +131,11 @@ export interface TUISettings extends Partial<EditorSettings> {
   // AI
   // ─────────────────────────────────────────────────────────────────────────
 
-  /** Default AI provider: 'claude-code', 'codex', or 'gemini' */
-  'ai.defaultProvider'?: 'claude-code' | 'codex' | 'gemini';
+  /** Default AI provider: 'claude-code', 'codex', 'gemini', or a custom provider ID */
+  'ai.defaultProvider'?: string;
+
+  /** User-defined AI chat providers */
+  'ai.customProviders'?: CustomAIProviderConfig[];
 
   // ─────────────────────────────────────────────────────────────────────────
   // TUI Tab Bar
@@ -132,6 +144,9 @@ export interface TUISettings extends Partial<EditorSettings> {
   /** Number of tabs to scroll when using scroll buttons */
   'tui.tabBar.scrollAmount'?: number;
 
+  /** Open files from quick open, the file tree and search results as preview tabs */
+  'tui.tabBar.enablePreview'?: boolean;
+
   // ─────────────────────────────────────────────────────────────────────────
   // TUI Diff Viewer
   // ─────────────────────────────────────────────────────────────────────────
@@ -162,6 +177,69 @@ export interface TUISettings extends Partial<EditorSettings> {
   'tui.timeline.commitCount'?: number;
   /** Whether to collapse timeline panel on startup */
   'tui.timeline.collapsedOnStartup'?: boolean;
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // TUI TODO Panel
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /** Whether to collapse TODO panel on startup */
+  'tui.todo.collapsedOnStartup'?: boolean;
+  /** Comment tags indexed by the TODO panel */
+  'tui.todo.tags'?: string[];
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // TUI Zen Mode
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /** Text width of the centered editor in zen mode (0 = full width) */
+  'tui.zenMode.width'?: number;
+  /** Dim every line except the current paragraph in zen mode */
+  'tui.zenMode.dimOutsideParagraph'?: boolean;
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // TUI Dependency Graph
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /** Show standard library and third-party imports in the dependency graph */
+  'tui.dependencyGraph.includeExternal'?: boolean;
+  /** Architecture layers, top first (usually set in workspace settings) */
+  'tui.dependencyGraph.layers'?: Array<{ name: string; include: string[] }>;
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // TUI Accessibility
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /** Screen-reader mode: announce focus, dialogs, completions and diagnostics */
+  'tui.accessibility.screenReaderMode'?: boolean;
+  /** Where announcements go: the status bar speech line, the reader bridge, or both */
+  'tui.accessibility.announcementChannel'?: 'statusLine' | 'ecp' | 'both';
+  /** Unix socket of an external reader bridge receiving ECP announcements */
+  'tui.accessibility.bridgeSocket'?: string;
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // TUI Notifications
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /** Notification keys the user chose "Don't show again" for */
+  'tui.notifications.doNotShowAgain'?: string[];
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // TUI Keybindings
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /** Key that `leader` stands for in keybindings ('' disables leader bindings) */
+  'tui.keybindings.leader'?: string;
+  /** Show the keys that can follow a chord prefix */
+  'tui.whichKey.enabled'?: boolean;
+  /** Delay before the which-key popup appears (ms) */
+  'tui.whichKey.delay'?: number;
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // TUI Image Preview
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /** How images are drawn: detected, Kitty graphics, sixel or half-blocks */
+  'tui.imagePreview.protocol'?: 'auto' | 'kitty' | 'sixel' | 'halfblock';
 }
 
 /**
@@ -7,6 +7,8 @@
  * Provider-specific subclasses:
  * - ClaudeTerminalChat: Claude Code with session capture and --resume
  * - CodexTerminalChat: OpenAI Codex CLI
+ * - GeminiTerminalChat: Google Gemini CLI
+ * - CustomTerminalChat: user-defined providers from ai.customProviders
  */
 
 import { BaseElement, type ElementContext } from './base.ts';
@@ -16,10 +18,11 @@ import type { PTYBackend, Unsubscribe } from '../../../terminal/pty-backend.ts';
 import { createPtyBackend } from '../../../terminal/pty-factory.ts';
 import { debugLog, isDebugEnabled } from '../../../debug.ts';
 import { settings } from '../../../config/settings.ts';
-import type { AIProvider } from '../../../services/session/types.ts';
+import type { AIProvider, CustomAIProviderConfig } from '../../../services/session/types.ts';
+import { stripAnsi } from '../../../terminal/output-watcher.ts';
 
 // Re-export AIProvider for convenience
-export type { AIProvider };
+export type { AIProvider, CustomAIProviderConfig };
 
 // ============================================
 // Types
@@ -39,6 +42,8 @@ export interface AITerminalChatState {
   provider: AIProvider;
   sessionId: string | null;
   cwd: string;
+  /** Custom provider ID when provider is 'custom' */
+  customProviderId?: string;
 }
 
 /**
@@ -51,6 +56,8 @@ export interface AITerminalChatCallbacks {
   onExit?: (code: number) => void;
   /** Called when a notification is received (OSC 99) */
   onNotification?: (message: string) => void;
+  /** Called when the user submits a line of input */
+  onPromptSubmitted?: (prompt: string) => void;
 }
 
 // ============================================
@@ -110,6 +117,12 @@ export abstract class AITerminalChat extends BaseElement {
   /** Whether the process is starting */
   protected starting = false;
 
+  /** Line currently being typed (tracked for prompt history) */
+  protected inputLine = '';
+
+  /** First prompt submitted in this chat */
+  protected firstPrompt: string | null = null;
+
   constructor(
     id: string,
     title: string,
@@ -199,6 +212,20 @@ export abstract class AITerminalChat extends BaseElement {
     this.callbacks.onSessionIdCaptured?.(sessionId);
   }
 
+  /**
+   * Get the first prompt submitted in this chat.
+   */
+  getFirstPrompt(): string | null {
+    return this.firstPrompt;
+  }
+
+  /**
+   * Get the terminal transcript (scrollback + screen) as plain text lines.
+   */
+  getTranscriptLines(): string[] {
+    return this.pty?.getTextLines() ?? [];
+  }
+
   /**
    * Check if the AI process is running.
    */
@@ -483,6 +510,7 @@ export abstract class AITerminalChat extends BaseElement {
       const input = this.keyEventToInput(event);
       if (input) {
         this.pty.write(input);
+        this.trackInput(input);
         return true;
       }
     }
@@ -496,6 +524,38 @@ export abstract class AITerminalChat extends BaseElement {
   writeInput(text: string): void {
     if (this.pty?.isRunning()) {
       this.pty.write(text);
+      this.trackInput(text, true);
+    }
+  }
+
+  /**
+   * Track typed input so submitted prompts can be recorded.
+   * Escape sequences (arrows, etc.) are ignored. Newlines in pasted text
+   * don't submit the prompt.
+   */
+  protected trackInput(input: string, pasted = false): void {
+    if (input.startsWith('\x1b')) return;
+
+    for (const ch of input) {
+      if (pasted && (ch === '\r' || ch === '\n')) {
+        this.inputLine += ' ';
+      } else if (ch === '\r' || ch === '\n') {
+        const prompt = this.inputLine.trim();
+        this.inputLine = '';
+        if (prompt) {
+          if (this.firstPrompt === null) {
+            this.firstPrompt = prompt;
+          }
+          this.callbacks.onPromptSubmitted?.(prompt);
+        }
+      } else if (ch === '\x7f' || ch === '\b') {
+        this.inputLine = this.inputLine.slice(0, -1);
+      } else if (ch === '\x03' || ch === '\x15') {
+        // Ctrl+C / Ctrl+U discard the line
+        this.inputLine = '';
+      } else if (ch >= ' ') {
+        this.inputLine += ch;
+      }
     }
   }
 
@@ -854,6 +914,248 @@ export class GeminiTerminalChat extends AITerminalChat {
   }
 }
 
+// ============================================
+// Custom Terminal Chat
+// ============================================
+
+/**
+ * Template variables for custom provider commands.
+ */
+export interface CustomProviderTemplateVars {
+  sessionId: string | null;
+  cwd: string;
+  prompt: string;
+}
+
+/**
+ * Expand ${sessionId}, ${cwd}, ${prompt} and ${env:NAME} in a template.
+ */
+export function expandProviderTemplate(value: string, vars: CustomProviderTemplateVars): string {
+  return value.replace(/\$\{(sessionId|cwd|prompt|env:[^}]+)\}/g, (_, name: string) => {
+    if (name.startsWith('env:')) {
+      return process.env[name.slice(4)] ?? '';
+    }
+    if (name === 'sessionId') return vars.sessionId ?? '';
+    if (name === 'cwd') return vars.cwd;
+    return vars.prompt;
+  });
+}
+
+/**
+ * Expand an argument template, dropping placeholder arguments that came
+ * out empty. A flag directly before a dropped value (`--model ${model}`)
+ * is dropped with it, as is a `--flag=${var}` argument with no value.
+ */
+export function expandProviderArgs(template: string[], expand: (value: string) => string): string[] {
+  const hasPlaceholder = (arg: string) => /\$\{[^}]+\}/.test(arg);
+  const args: string[] = [];
+  let previousFlag = false;
+
+  for (const arg of template) {
+    const value = expand(arg);
+    const inline = /^(-[^=]+)=(.*)$/.exec(arg);
+    if (inline && hasPlaceholder(inline[2]!) && value === `${inline[1]}=`) {
+      previousFlag = false;
+      continue;
+    }
+    if (value === '' && hasPlaceholder(arg)) {
+      if (previousFlag) args.pop();
+      previousFlag = false;
+      continue;
+    }
+    args.push(value);
+    previousFlag = arg.startsWith('-') && !arg.includes('=') && !hasPlaceholder(arg);
+  }
+
+  return args;
+}
+
+/**
+ * Find a custom provider by ID in the ai.customProviders setting value.
+ * Entries without an id, name or command are ignored.
+ */
+export function findCustomAIProvider(
+  providers: CustomAIProviderConfig[] | undefined,
+  id: string
+): CustomAIProviderConfig | null {
+  if (!Array.isArray(providers)) return null;
+  return providers.find((p) => p && p.id === id && !!p.command) ?? null;
+}
+
+/**
+ * Terminal chat for a user-defined provider (aider, internal agent CLIs, ...).
+ *
+ * The command line, environment, resume arguments, session ID capture and
+ * initial prompt delivery all come from the provider's settings entry.
+ */
+export class CustomTerminalChat extends AITerminalChat {
+  /** How long output must be quiet before typing the initial prompt (ms) */
+  protected static readonly PROMPT_SETTLE_MS = 500;
+
+  /** Maximum output kept while searching for the session ID */
+  private static readonly CAPTURE_BUFFER_LIMIT = 8192;
+
+  private config: CustomAIProviderConfig;
+  private initialPrompt: string;
+  private sessionIdRegex: RegExp | null = null;
+  private captureBuffer = '';
+  private promptTimer: ReturnType<typeof setTimeout> | null = null;
+  private promptSent = false;
+
+  constructor(
+    id: string,
+    title: string,
+    ctx: ElementContext,
+    options: {
+      config: CustomAIProviderConfig;
+      sessionId?: string | null;
+      cwd?: string;
+      callbacks?: AITerminalChatCallbacks;
+      initialPrompt?: string;
+    }
+  ) {
+    super(id, title || options.config.name || 'AI Chat', ctx, options);
+    this.config = options.config;
+    this.initialPrompt = options.config.initialPrompt ??
+      options.initialPrompt ??
+      settings.get('ai.panel.initialPrompt') ??
+      '';
+
+    if (this.config.sessionIdPattern) {
+      try {
+        this.sessionIdRegex = new RegExp(this.config.sessionIdPattern);
+      } catch (error) {
+        this.debugLog(`Invalid sessionIdPattern "${this.config.sessionIdPattern}": ${error}`);
+      }
+    }
+  }
+
+  getProvider(): AIProvider {
+    return 'custom';
+  }
+
+  /**
+   * Get the custom provider ID.
+   */
+  getCustomProviderId(): string {
+    return this.config.id;
+  }
+
+  getCommand(): string {
+    return this.expand(this.config.command);
+  }
+
+  getArgs(): string[] {
+    const template = this.sessionId && this.config.resumeArgs
+      ? this.config.resumeArgs
+      : this.config.args ?? [];
+    return expandProviderArgs(template, (arg) => this.expand(arg));
+  }
+
+  getEnv(): Record<string, string> {
+    const env: Record<string, string> = {};
+    for (const [key, value] of Object.entries(this.config.env ?? {})) {
+      env[key] = this.expand(String(value));
+    }
+    return env;
+  }
+
+  getProviderName(): string {
+    return this.config.name || this.config.id;
+  }
+
+  protected override usesInkCursor(): boolean {
+    return this.config.usesInkCursor ?? false;
+  }
+
+  /**
+   * Whether this chat starts a new conversation (no session to resume).
+   */
+  private isNewSession(): boolean {
+    return !this.sessionId;
+  }
+
+  private expand(value: string): string {
+    const deliverAsArg = this.config.initialPromptMode === 'arg' && this.isNewSession();
+    return expandProviderTemplate(value, {
+      sessionId: this.sessionId,
+      cwd: this.cwd,
+      prompt: deliverAsArg ? this.initialPrompt : '',
+    });
+  }
+
+  /**
+   * Start the PTY, then watch output for the session ID and the moment
+   * to type the initial prompt.
+   */
+  protected override async startInteractive(): Promise<void> {
+    const typePrompt =
+      this.config.initialPromptMode === 'type' && this.isNewSession() && !!this.initialPrompt;
+
+    await super.startInteractive();
+    if (!this.pty) return;
+
+    this.ptyUnsubscribes.push(
+      this.pty.onData((data) => {
+        if (this.sessionIdRegex && !this.sessionId) {
+          this.captureSessionId(data);
+        }
+        if (typePrompt && !this.promptSent) {
+          this.schedulePrompt();
+        }
+      })
+    );
+  }
+
+  /**
+   * Look for the session ID in PTY output.
+   */
+  private captureSessionId(data: string): void {
+    this.captureBuffer += stripAnsi(data);
+    if (this.captureBuffer.length > CustomTerminalChat.CAPTURE_BUFFER_LIMIT) {
+      this.captureBuffer = this.captureBuffer.slice(-CustomTerminalChat.CAPTURE_BUFFER_LIMIT);
+    }
+
+    const match = this.sessionIdRegex!.exec(this.captureBuffer);
+    const captured = match?.[1] ?? match?.[0];
+    if (captured) {
+      this.captureBuffer = '';
+      this.setSessionId(captured);
+    }
+  }
+
+  /**
+   * Type the initial prompt once output has been quiet for a moment.
+   */
+  private schedulePrompt(): void {
+    if (this.promptTimer) {
+      clearTimeout(this.promptTimer);
+    }
+    this.promptTimer = setTimeout(() => {
+      this.promptTimer = null;
+      if (this.promptSent || !this.pty?.isRunning()) return;
+      this.promptSent = true;
+      this.debugLog('Typing initial prompt');
+      this.pty.write(`${this.initialPrompt}\r`);
+    }, CustomTerminalChat.PROMPT_SETTLE_MS);
+  }
+
+  override stop(): void {
+    if (this.promptTimer) {
+      clearTimeout(this.promptTimer);
+      this.promptTimer = null;
+    }
+    super.stop();
+  }
+
+  override getState(): AITerminalChatState {
+    return {
+      ...super.getState(),
+      customProviderId: this.config.id,
+    };
+  }
+}
+
 // ============================================
 // Factory Functions
 // ============================================
@@ -871,6 +1173,8 @@ export function createAITerminalChat(
     cwd?: string;
     callbacks?: AITerminalChatCallbacks;
     initialPrompt?: string;
+    customProviderId?: string;
+    customProvider?: CustomAIProviderConfig;
   } = {}
 ): AITerminalChat {
   const provider = options.provider ?? 'claude-code';
@@ -882,6 +1186,17 @@ export function createAITerminalChat(
       return new CodexTerminalChat(id, title, ctx, options);
     case 'gemini':
       return new GeminiTerminalChat(id, title, ctx, options);
+    case 'custom': {
+      const config = options.customProvider ?? findCustomAIProvider(
+        ctx.getSetting<CustomAIProviderConfig[]>('ai.customProviders', []),
+        options.customProviderId ?? ''
+      );
+      if (config) {
+        return new CustomTerminalChat(id, title, ctx, { ...options, config });
+      }
+      debugLog(`[AITerminalChat] Unknown custom provider "${options.customProviderId}", using Claude`);
+      return new ClaudeTerminalChat(id, title, ctx, options);
+    }
     default:
       // Default to Claude
       return new ClaudeTerminalChat(id, title, ctx, options);
@@ -936,3 +1251,21 @@ export function createGeminiTerminalChat(
 ): GeminiTerminalChat {
   return new GeminiTerminalChat(id, title, ctx, options);
 }
+
+/**
+ * Create a terminal chat for a custom provider.
+ */
+export function createCustomTerminalChat(
+  id: string,
+  title: string,
+  ctx: ElementContext,
+  options: {
+    config: CustomAIProviderConfig;
+    sessionId?: string | null;
+    cwd?: string;
+    callbacks?: AITerminalChatCallbacks;
+    initialPrompt?: string;
+  }
+): CustomTerminalChat {
+  return new CustomTerminalChat(id, title, ctx, options);
+}
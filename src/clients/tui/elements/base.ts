@@ -8,6 +8,7 @@
 import type {
   Rect,
   Size,
+  Position,
   ElementType,
   ElementLifecycle,
   KeyEvent,
@@ -196,6 +197,28 @@ export abstract class BaseElement implements ElementLifecycle {
     return false;
   }
 
+  // ─────────────────────────────────────────────────────────────────────────
+  // Accessibility
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /**
+   * Describe the element for screen readers. Announced when the element
+   * gains focus or the description changes, so it should name the focused
+   * item (e.g. the selected file) but not change on every keystroke.
+   */
+  getAccessibleDescription(): string {
+    return this.title;
+  }
+
+  /**
+   * Screen position of the element's logical cursor (e.g. the selected row).
+   * In screen-reader mode the terminal cursor is placed here.
+   * Returns null to use the element's top-left corner.
+   */
+  getCursorScreenPosition(): Position | null {
+    return null;
+  }
+
   // ─────────────────────────────────────────────────────────────────────────
   // State Serialization
   // ─────────────────────────────────────────────────────────────────────────
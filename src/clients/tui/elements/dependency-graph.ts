@@ -0,0 +1,573 @@
+/**
+ * Dependency Graph Viewer
+ *
+ * Shows a package (Go) or module (TypeScript) import graph as an
+ * expandable tree. Each node expands to what it imports, or with the
+ * direction flipped to what imports it. Nodes on import cycles and
+ * imports that break the configured layering are highlighted.
+ *
+ * Keys:
+ * - Up/Down, j/k: move selection
+ * - PageUp/PageDown, Home/End: jump
+ * - Right/l: expand, Left/h: collapse or go to parent
+ * - Space: toggle expansion
+ * - Enter: open the node's file or package
+ * - d: toggle direction (imports / imported by)
+ * - c: cycle filter (all, cycles, layer violations)
+ * - f: focus on the selected node, Backspace: clear focus
+ * - r: rebuild the graph
+ */
+
+import { BaseElement, type ElementContext } from './base.ts';
+import type { KeyEvent, MouseEvent } from '../types.ts';
+import type { ScreenBuffer } from '../rendering/buffer.ts';
+import {
+  checkLayers,
+  edgeKey,
+  findCycles,
+  getCycleEdges,
+  getDependencies,
+  reverseDependencies,
+  type DependencyGraph,
+  type DependencyLayer,
+  type DependencyNode,
+  type LayerViolation,
+} from '../../../core/deps/index.ts';
+
+// ============================================
+// Types
+// ============================================
+
+/**
+ * Edge direction shown as children.
+ */
+export type DependencyDirection = 'imports' | 'importedBy';
+
+/**
+ * Which nodes are listed at the top level.
+ */
+export type DependencyFilter = 'all' | 'cycles' | 'violations';
+
+/**
+ * Callbacks for the dependency graph viewer.
+ */
+export interface DependencyGraphViewerCallbacks {
+  /** Open a node's file or package directory */
+  onOpen?: (node: DependencyNode) => void;
+  /** Rebuild the graph */
+  onRefresh?: () => void;
+}
+
+/**
+ * Viewer state for serialization.
+ */
+export interface DependencyGraphViewerState {
+  direction: DependencyDirection;
+  filter: DependencyFilter;
+  focusId: string | null;
+}
+
+/**
+ * A rendered tree row.
+ */
+interface GraphRow {
+  id: string;
+  /** Ids from the root to this row, joined with newlines */
+  treeKey: string;
+  depth: number;
+  childCount: number;
+  /** The node already appears above this row on the same branch */
+  repeated: boolean;
+  /** Violation on the edge from the parent row, if any */
+  violation?: LayerViolation;
+  /** The edge from the parent row lies on a cycle */
+  cycleEdge: boolean;
+}
+
+const DIRECTIONS: DependencyDirection[] = ['imports', 'importedBy'];
+const FILTERS: DependencyFilter[] = ['all', 'cycles', 'violations'];
+
+const KIND_LABELS: Record<DependencyGraph['kind'], string> = {
+  go: 'Go packages',
+  typescript: 'TS modules',
+};
+
+// ============================================
+// Dependency Graph Viewer Element
+// ============================================
+
+export class DependencyGraphViewer extends BaseElement {
+  private graph: DependencyGraph | null = null;
+  private layers: DependencyLayer[] = [];
+  private callbacks: DependencyGraphViewerCallbacks;
+
+  // Derived from the graph
+  private dependents = new Map<string, Set<string>>();
+  private cycles: string[][] = [];
+  private cycleMembers = new Set<string>();
+  private cycleEdges = new Set<string>();
+  private violations: LayerViolation[] = [];
+  private violationsByEdge = new Map<string, LayerViolation>();
+
+  private direction: DependencyDirection = 'imports';
+  private filter: DependencyFilter = 'all';
+  private focusId: string | null = null;
+  private expanded = new Set<string>();
+
+  private loading = false;
+  private error: string | null = null;
+  private rows: GraphRow[] = [];
+  private selectedIndex = 0;
+  private scrollTop = 0;
+
+  constructor(id: string, title: string, ctx: ElementContext, callbacks: DependencyGraphViewerCallbacks = {}) {
+    super('DependencyGraph', id, title, ctx);
+    this.callbacks = callbacks;
+  }
+
+  setCallbacks(callbacks: DependencyGraphViewerCallbacks): void {
+    this.callbacks = { ...this.callbacks, ...callbacks };
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Graph
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /**
+   * Show a graph, checking it against `layers` (top layer first).
+   */
+  setGraph(graph: DependencyGraph, layers: DependencyLayer[] = this.layers): void {
+    this.graph = graph;
+    this.layers = layers;
+    this.loading = false;
+    this.error = null;
+
+    this.dependents = reverseDependencies(graph);
+    this.cycles = findCycles(graph);
+    this.cycleMembers = new Set(this.cycles.flat());
+    this.cycleEdges = getCycleEdges(graph, this.cycles);
+    this.violations = checkLayers(graph, layers);
+    this.violationsByEdge = new Map(this.violations.map((v) => [edgeKey(v.from, v.to), v]));
+
+    if (this.focusId && !graph.nodes.has(this.focusId)) this.focusId = null;
+    this.rebuildRows();
+    this.setStatus(`${graph.nodes.size} nodes`);
+    this.ctx.markDirty();
+  }
+
+  getGraph(): DependencyGraph | null {
+    return this.graph;
+  }
+
+  getCycles(): string[][] {
+    return this.cycles;
+  }
+
+  getViolations(): LayerViolation[] {
+    return this.violations;
+  }
+
+  setLoading(loading: boolean): void {
+    this.loading = loading;
+    if (loading) this.error = null;
+    this.ctx.markDirty();
+  }
+
+  setError(message: string): void {
+    this.loading = false;
+    this.error = message;
+    this.ctx.markDirty();
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // View Options
+  // ─────────────────────────────────────────────────────────────────────────
+
+  getDirection(): DependencyDirection {
+    return this.direction;
+  }
+
+  setDirection(direction: DependencyDirection): void {
+    this.direction = direction;
+    this.resetTree();
+  }
+
+  toggleDirection(): void {
+    this.setDirection(this.direction === 'imports' ? 'importedBy' : 'imports');
+  }
+
+  getFilter(): DependencyFilter {
+    return this.filter;
+  }
+
+  setFilter(filter: DependencyFilter): void {
+    this.filter = filter;
+    this.resetTree();
+  }
+
+  cycleFilter(): void {
+    this.setFilter(FILTERS[(FILTERS.indexOf(this.filter) + 1) % FILTERS.length]!);
+  }
+
+  getFocus(): string | null {
+    return this.focusId;
+  }
+
+  /**
+   * Show only one node at the top level (null shows all).
+   */
+  setFocus(id: string | null): void {
+    this.focusId = id && this.graph?.nodes.has(id) ? id : null;
+    this.resetTree();
+    if (this.focusId) this.setExpanded(this.rows[0]!, true);
+  }
+
+  private resetTree(): void {
+    this.expanded.clear();
+    this.selectedIndex = 0;
+    this.scrollTop = 0;
+    this.rebuildRows();
+    this.ctx.markDirty();
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Rows
+  // ─────────────────────────────────────────────────────────────────────────
+
+  private getChildren(id: string): string[] {
+    if (!this.graph) return [];
+    if (this.direction === 'imports') return getDependencies(this.graph, id);
+    return [...(this.dependents.get(id) ?? [])].sort();
+  }
+
+  private getRoots(): string[] {
+    if (!this.graph) return [];
+    if (this.focusId) return [this.focusId];
+
+    let ids: string[];
+    switch (this.filter) {
+      case 'cycles':
+        ids = [...this.cycleMembers];
+        break;
+      case 'violations':
+        ids = [...new Set(this.violations.map((v) => (this.direction === 'imports' ? v.from : v.to)))];
+        break;
+      default:
+        ids = [...this.graph.nodes.values()].filter((node) => !node.external).map((node) => node.id);
+    }
+    return ids.sort((a, b) => this.labelOf(a).localeCompare(this.labelOf(b)));
+  }
+
+  private rebuildRows(): void {
+    this.rows = [];
+    for (const id of this.getRoots()) {
+      this.addRows(id, id, [], undefined);
+    }
+    this.selectedIndex = Math.min(this.selectedIndex, Math.max(0, this.rows.length - 1));
+  }
+
+  private addRows(id: string, treeKey: string, ancestors: string[], parent: string | undefined): void {
+    const repeated = ancestors.includes(id);
+    const children = repeated ? [] : this.getChildren(id);
+    let violation: LayerViolation | undefined;
+    let cycleEdge = false;
+    if (parent !== undefined) {
+      // Edges always point from importer to imported
+      const key = this.direction === 'imports' ? edgeKey(parent, id) : edgeKey(id, parent);
+      violation = this.violationsByEdge.get(key);
+      cycleEdge = this.cycleEdges.has(key);
+    }
+
+    this.rows.push({ id, treeKey, depth: ancestors.length, childCount: children.length, repeated, violation, cycleEdge });
+
+    if (children.length === 0 || !this.expanded.has(treeKey)) return;
+    const path = [...ancestors, id];
+    for (const child of children) {
+      this.addRows(child, `${treeKey}\n${child}`, path, id);
+    }
+  }
+
+  private labelOf(id: string): string {
+    return this.graph?.nodes.get(id)?.label ?? id;
+  }
+
+  /**
+   * Get the display lines (for tests).
+   */
+  getLines(): string[] {
+    return this.rows.map((row) => this.formatRow(row));
+  }
+
+  private formatRow(row: GraphRow): string {
+    const indent = '  '.repeat(row.depth);
+    const arrow = row.childCount === 0 ? ' ' : this.expanded.has(row.treeKey) ? '▾' : '▸';
+    let text = `${indent}${arrow} ${this.labelOf(row.id)}`;
+    if (row.childCount > 0) text += ` (${row.childCount})`;
+    if (row.repeated) text += ' ↻ cycle';
+    else if (this.cycleMembers.has(row.id)) text += ' ↻';
+    if (row.violation) text += ` ✗ ${row.violation.fromLayer} → ${row.violation.toLayer}`;
+    return text;
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Navigation
+  // ─────────────────────────────────────────────────────────────────────────
+
+  private getViewportHeight(): number {
+    return Math.max(1, this.bounds.height - 1);
+  }
+
+  private ensureVisible(): void {
+    const viewportHeight = this.getViewportHeight();
+    if (this.selectedIndex < this.scrollTop) {
+      this.scrollTop = this.selectedIndex;
+    } else if (this.selectedIndex >= this.scrollTop + viewportHeight) {
+      this.scrollTop = this.selectedIndex - viewportHeight + 1;
+    }
+  }
+
+  private moveSelection(delta: number): void {
+    if (this.rows.length === 0) return;
+    this.selectedIndex = Math.max(0, Math.min(this.rows.length - 1, this.selectedIndex + delta));
+    this.ensureVisible();
+    this.ctx.markDirty();
+  }
+
+  private setExpanded(row: GraphRow, expanded: boolean): void {
+    if (row.childCount === 0) return;
+    if (expanded) this.expanded.add(row.treeKey);
+    else this.expanded.delete(row.treeKey);
+    this.rebuildRows();
+    this.selectedIndex = Math.max(0, this.rows.findIndex((r) => r.treeKey === row.treeKey));
+    this.ensureVisible();
+    this.ctx.markDirty();
+  }
+
+  /**
+   * Collapse the selected row, or select its parent if it is collapsed.
+   */
+  private collapseOrParent(): void {
+    const row = this.rows[this.selectedIndex];
+    if (!row) return;
+    if (this.expanded.has(row.treeKey)) {
+      this.setExpanded(row, false);
+      return;
+    }
+    const parentKey = row.treeKey.slice(0, row.treeKey.lastIndexOf('\n'));
+    const parentIndex = row.depth > 0 ? this.rows.findIndex((r) => r.treeKey === parentKey) : -1;
+    if (parentIndex >= 0) {
+      this.selectedIndex = parentIndex;
+      this.ensureVisible();
+      this.ctx.markDirty();
+    }
+  }
+
+  getSelectedNode(): DependencyNode | null {
+    const row = this.rows[this.selectedIndex];
+    return row ? (this.graph?.nodes.get(row.id) ?? null) : null;
+  }
+
+  /**
+   * Open the selected node.
+   */
+  activate(): void {
+    const node = this.getSelectedNode();
+    if (node && !node.external) this.callbacks.onOpen?.(node);
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Rendering
+  // ─────────────────────────────────────────────────────────────────────────
+
+  private getHeader(): string {
+    if (!this.graph) return ' Dependency Graph';
+    const parts = [KIND_LABELS[this.graph.kind], `${this.graph.nodes.size} nodes`];
+    parts.push(`${this.cycles.length} cycle${this.cycles.length !== 1 ? 's' : ''}`);
+    if (this.layers.length > 0) {
+      parts.push(`${this.violations.length} violation${this.violations.length !== 1 ? 's' : ''}`);
+    }
+    parts.push(this.direction === 'imports' ? 'imports' : 'imported by');
+    if (this.filter !== 'all') parts.push(`only ${this.filter}`);
+    if (this.focusId) parts.push(`focus ${this.labelOf(this.focusId)}`);
+    if (this.loading) parts.push('building…');
+    return ` ${parts.join(' · ')}`;
+  }
+
+  render(buffer: ScreenBuffer): void {
+    const { x, y, width, height } = this.bounds;
+    if (height === 0 || width === 0) return;
+
+    const bg = this.ctx.getBackgroundForFocus('editor', this.focused);
+    const fg = this.ctx.getForegroundForFocus('editor', this.focused);
+    const selectedBg = this.ctx.getSelectionBackground('editor', this.focused);
+    const headerBg = this.ctx.getThemeColor('sideBarSectionHeader.background', '#383838');
+    const headerFg = this.ctx.getThemeColor('sideBarSectionHeader.foreground', '#cccccc');
+    const dimFg = this.ctx.getThemeColor('descriptionForeground', '#888888');
+    const errorFg = this.ctx.getThemeColor('editorError.foreground', '#f44747');
+    const warningFg = this.ctx.getThemeColor('editorWarning.foreground', '#cca700');
+
+    buffer.writeString(x, y, truncate(this.getHeader(), width).padEnd(width, ' '), headerFg, headerBg);
+
+    const viewportHeight = Math.min(this.getViewportHeight(), height - 1);
+    if (this.rows.length === 0) {
+      for (let row = 0; row < viewportHeight; row++) {
+        buffer.writeString(x, y + 1 + row, ' '.repeat(width), fg, bg);
+      }
+      let msg = 'No nodes';
+      if (this.error) msg = this.error;
+      else if (this.loading || !this.graph) msg = 'Building graph…';
+      else if (this.filter === 'cycles') msg = 'No import cycles';
+      else if (this.filter === 'violations') msg = 'No layer violations';
+      if (viewportHeight > 0) buffer.writeString(x + 1, y + 1, truncate(msg, width - 1), this.error ? errorFg : dimFg, bg);
+      return;
+    }
+
+    for (let row = 0; row < viewportHeight; row++) {
+      const screenY = y + 1 + row;
+      const rowIndex = this.scrollTop + row;
+      const graphRow = this.rows[rowIndex];
+      const rowBg = rowIndex === this.selectedIndex ? selectedBg : bg;
+      buffer.writeString(x, screenY, ' '.repeat(width), fg, rowBg);
+      if (!graphRow) continue;
+
+      let rowFg = fg;
+      if (graphRow.violation) rowFg = warningFg;
+      else if (graphRow.repeated || graphRow.cycleEdge || (graphRow.depth === 0 && this.cycleMembers.has(graphRow.id))) {
+        rowFg = errorFg;
+      } else if (this.graph?.nodes.get(graphRow.id)?.external) {
+        rowFg = dimFg;
+      }
+      buffer.writeString(x, screenY, truncate(this.formatRow(graphRow), width), rowFg, rowBg);
+    }
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Input Handling
+  // ─────────────────────────────────────────────────────────────────────────
+
+  override handleKey(event: KeyEvent): boolean {
+    if (event.ctrl || event.alt || event.meta) return false;
+
+    const row = this.rows[this.selectedIndex];
+    switch (event.key) {
+      case 'ArrowUp':
+      case 'k':
+        this.moveSelection(-1);
+        return true;
+      case 'ArrowDown':
+      case 'j':
+        this.moveSelection(1);
+        return true;
+      case 'PageUp':
+        this.moveSelection(-this.getViewportHeight());
+        return true;
+      case 'PageDown':
+        this.moveSelection(this.getViewportHeight());
+        return true;
+      case 'Home':
+        this.moveSelection(-this.rows.length);
+        return true;
+      case 'End':
+        this.moveSelection(this.rows.length);
+        return true;
+      case 'ArrowRight':
+      case 'l':
+        if (row) this.setExpanded(row, true);
+        return true;
+      case 'ArrowLeft':
+      case 'h':
+        this.collapseOrParent();
+        return true;
+      case ' ':
+        if (row) this.setExpanded(row, !this.expanded.has(row.treeKey));
+        return true;
+      case 'Enter':
+        this.activate();
+        return true;
+      case 'd':
+        this.toggleDirection();
+        return true;
+      case 'c':
+        this.cycleFilter();
+        return true;
+      case 'f':
+        if (row) this.setFocus(row.id);
+        return true;
+      case 'Backspace':
+        if (this.focusId) this.setFocus(null);
+        return true;
+      case 'r':
+        this.callbacks.onRefresh?.();
+        return true;
+    }
+
+    return false;
+  }
+
+  override handleMouse(event: MouseEvent): boolean {
+    if (event.type === 'scroll') {
+      const delta = (event.scrollDirection ?? 1) * 3;
+      const maxScroll = Math.max(0, this.rows.length - this.getViewportHeight());
+      this.scrollTop = Math.max(0, Math.min(this.scrollTop + delta, maxScroll));
+      this.ctx.markDirty();
+      return true;
+    }
+
+    if (event.type === 'press' && event.button === 'left') {
+      this.ctx.requestFocus();
+      const rowIndex = this.scrollTop + (event.y - this.bounds.y - 1);
+      const row = this.rows[rowIndex];
+      if (row) {
+        const wasSelected = rowIndex === this.selectedIndex;
+        this.selectedIndex = rowIndex;
+        // A second click toggles expansion
+        if (wasSelected) this.setExpanded(row, !this.expanded.has(row.treeKey));
+        this.ctx.markDirty();
+      }
+      return true;
+    }
+
+    return false;
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // State Serialization
+  // ─────────────────────────────────────────────────────────────────────────
+
+  override getState(): DependencyGraphViewerState {
+    return {
+      direction: this.direction,
+      filter: this.filter,
+      focusId: this.focusId,
+    };
+  }
+
+  override setState(state: unknown): void {
+    const s = state as Partial<DependencyGraphViewerState> | undefined;
+    if (s?.direction && DIRECTIONS.includes(s.direction)) this.direction = s.direction;
+    if (s?.filter && FILTERS.includes(s.filter)) this.filter = s.filter;
+    if (typeof s?.focusId === 'string') this.focusId = s.focusId;
+    this.rebuildRows();
+    this.ctx.markDirty();
+  }
+}
+
+function truncate(text: string, width: number): string {
+  return text.length > width ? text.slice(0, Math.max(0, width - 1)) + '…' : text;
+}
+
+// ============================================
+// Factory Function
+// ============================================
+
+/**
+ * Create a dependency graph viewer element.
+ */
+export function createDependencyGraphViewer(
+  id: string,
+  title: string,
+  ctx: ElementContext,
+  callbacks?: DependencyGraphViewerCallbacks
+): DependencyGraphViewer {
+  return new DependencyGraphViewer(id, title, ctx, callbacks);
+}
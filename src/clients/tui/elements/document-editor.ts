@@ -8,9 +8,17 @@
 import { BaseElement, type ElementContext } from './base.ts';
 import type { KeyEvent, MouseEvent, Position, UnderlineStyle } from '../types.ts';
 import type { ScreenBuffer } from '../rendering/buffer.ts';
-import { darken, lighten } from '../../../core/colors.ts';
+import { blendColors, darken, lighten, isLightColor } from '../../../core/colors.ts';
 import { getCharWidth } from '../../../core/char-width.ts';
-import { FoldManager } from '../../../core/fold.ts';
+import { FoldManager, type FoldState } from '../../../core/fold.ts';
+import {
+  getAutoCloseTag,
+  getSurroundPair,
+  changeSurroundingPair,
+  deleteSurroundingPair,
+  type TextChange,
+} from '../../../core/auto-pair.ts';
+import { getEmmetSyntax, extractAbbreviation, expandAbbreviation } from '../../../core/emmet.ts';
 import { UndoManager, type EditOperation, type UndoAction, type SerializedUndoState } from '../../../core/undo.ts';
 import { InlineDiffExpander, type InlineDiffCallbacks } from '../components/inline-diff-expander.ts';
 import type { GitDiffHunk } from '../../../services/git/types.ts';
@@ -88,6 +96,30 @@ export interface DiagnosticInfo {
   source?: string;
 }
 
+/**
+ * A link in the document (import path, URL), e.g. from LSP documentLink.
+ */
+export interface DocumentLinkInfo {
+  startLine: number;
+  startColumn: number;
+  endLine: number;
+  endColumn: number;
+  /** Target URI, if already known */
+  target?: string;
+  tooltip?: string;
+}
+
+/**
+ * A colour value in the document, rendered as a swatch.
+ */
+export interface ColorDecoratorInfo {
+  line: number;
+  startColumn: number;
+  endColumn: number;
+  /** Colour as #rrggbb */
+  color: string;
+}
+
 /**
  * Internal search options.
  */
@@ -116,7 +148,8 @@ export interface DocumentEditorState {
   cursor?: CursorPosition;
   /** All cursors (for multi-cursor support) */
   cursors?: Cursor[];
-  foldedRegions?: number[];
+  /** Folded region start lines, plus ranges for folded manual regions */
+  foldedRegions?: FoldState[];
   /** Serialized undo/redo history for session persistence */
   undoHistory?: SerializedUndoState;
 }
@@ -145,6 +178,8 @@ export interface DocumentEditorCallbacks {
   onRevertHunk?: (bufferLine: number, hunk: GitDiffHunk) => void | Promise<void>;
   /** Called to confirm revert action (returns true if confirmed) */
   onConfirmRevert?: (message: string) => Promise<boolean>;
+  /** Called when a document link is Ctrl+clicked */
+  onOpenLink?: (link: DocumentLinkInfo) => void;
 }
 
 // ============================================
@@ -197,6 +232,12 @@ export class DocumentEditor extends BaseElement {
   /** Minimap scroll offset (in minimap rows) */
   private minimapScrollTop = 0;
 
+  /** Dim lines outside the paragraph around the cursor (zen mode) */
+  private dimOutsideParagraph = false;
+
+  /** Screen position of the primary cursor in the last render */
+  private primaryCursorScreen: Position | null = null;
+
   /** Whether scrollbar dragging is active */
   private scrollbarDragging = false;
 
@@ -226,6 +267,12 @@ export class DocumentEditor extends BaseElement {
   /** Version of content when fold regions were last computed */
   private lastFoldVersion = -1;
 
+  /** Folding ranges from a language server (null = compute from indentation) */
+  private providedFoldRanges: Array<{ startLine: number; endLine: number; kind?: string }> | null = null;
+
+  /** Language of the document (selects region marker and comment syntax for folding) */
+  private languageId: string | undefined;
+
   /** Current content version (increments on change) */
   private contentVersion = 0;
 
@@ -241,6 +288,12 @@ export class DocumentEditor extends BaseElement {
   /** Diagnostics for this document (errors, warnings, etc.) */
   private diagnostics: DiagnosticInfo[] = [];
 
+  /** Links (import paths, URLs) */
+  private links: DocumentLinkInfo[] = [];
+
+  /** Colour values shown as swatches */
+  private colorDecorators: ColorDecoratorInfo[] = [];
+
   /** Git line changes for gutter indicators */
   private gitLineChanges: Map<number, 'added' | 'modified' | 'deleted'> = new Map();
 
@@ -345,10 +398,40 @@ export class DocumentEditor extends BaseElement {
     return this.minimapEnabled;
   }
 
+  /**
+   * Dim every line except the paragraph (run of non-blank lines) around
+   * the primary cursor.
+   */
+  setDimOutsideParagraph(enabled: boolean): void {
+    this.dimOutsideParagraph = enabled;
+    this.ctx.markDirty();
+  }
+
+  isDimOutsideParagraph(): boolean {
+    return this.dimOutsideParagraph;
+  }
+
+  /**
+   * Get the paragraph around the primary cursor (0-indexed, inclusive).
+   * On a blank line the paragraph is that line alone.
+   */
+  getCurrentParagraph(): { startLine: number; endLine: number } {
+    const line = this.getCursor().line;
+    const isBlank = (l: number) => this.lines[l]!.text.trim() === '';
+    if (line >= this.lines.length || isBlank(line)) {
+      return { startLine: line, endLine: line };
+    }
+    let startLine = line;
+    while (startLine > 0 && !isBlank(startLine - 1)) startLine--;
+    let endLine = line;
+    while (endLine + 1 < this.lines.length && !isBlank(endLine + 1)) endLine++;
+    return { startLine, endLine };
+  }
+
   /**
    * Get the width of the right margin (scrollbar + minimap).
    */
-  private getRightMarginWidth(): number {
+  getRightMarginWidth(): number {
     let width = DocumentEditor.SCROLLBAR_WIDTH;
     if (this.minimapEnabled) {
       width += DocumentEditor.MINIMAP_WIDTH;
@@ -542,6 +625,127 @@ export class DocumentEditor extends BaseElement {
     this.ctx.markDirty();
   }
 
+  /**
+   * Fold regions at a nesting level (1 = outermost), keeping the
+   * regions that contain a cursor open.
+   */
+  foldToLevel(level: number): void {
+    if (!this.foldingEnabled) return;
+
+    this.foldManager.foldToLevel(level, this.getCursorLines());
+    this.afterFoldCommand();
+  }
+
+  /**
+   * Fold all comment blocks not containing a cursor.
+   */
+  foldAllComments(): void {
+    if (!this.foldingEnabled) return;
+
+    this.foldManager.foldKind('comment', this.getCursorLines());
+    this.afterFoldCommand();
+  }
+
+  /**
+   * Fold the import block.
+   */
+  foldImports(): void {
+    if (!this.foldingEnabled) return;
+
+    this.foldManager.foldKind('imports', this.getCursorLines());
+    this.afterFoldCommand();
+  }
+
+  /**
+   * Fold everything except the regions containing a selection or cursor.
+   */
+  foldAllExceptSelection(): void {
+    if (!this.foldingEnabled) return;
+
+    const lines: number[] = [];
+    for (const cursor of this.cursors) {
+      if (this.hasSelectionContent(cursor.selection)) {
+        const sel = this.getSelectionRange(cursor.selection!);
+        for (let line = sel.start.line; line <= sel.end.line; line++) lines.push(line);
+      } else {
+        lines.push(cursor.position.line);
+      }
+    }
+    this.foldManager.foldAllExcept(lines);
+    this.afterFoldCommand();
+  }
+
+  /**
+   * Create a folded manual region from each multi-line selection.
+   * Returns false if no selection spans more than one line.
+   */
+  createFoldFromSelection(): boolean {
+    if (!this.foldingEnabled) return false;
+
+    let created = false;
+    for (const cursor of this.cursors) {
+      if (!this.hasSelectionContent(cursor.selection)) continue;
+      const sel = this.getSelectionRange(cursor.selection!);
+      // A selection ending at column 0 doesn't include that line
+      const endLine = sel.end.column === 0 && sel.end.line > sel.start.line ? sel.end.line - 1 : sel.end.line;
+      if (this.foldManager.addManualRegion(sel.start.line, endLine)) {
+        created = true;
+      }
+    }
+    if (!created) return false;
+
+    for (const cursor of this.cursors) {
+      cursor.selection = null;
+    }
+    this.afterFoldCommand();
+    return true;
+  }
+
+  /**
+   * Remove manual fold regions at the cursors. Returns how many were removed.
+   */
+  removeManualFolds(): number {
+    if (!this.foldingEnabled) return 0;
+
+    const removed = this.foldManager.removeManualRegions(this.getCursorLines());
+    if (removed > 0) {
+      this.afterFoldCommand();
+    }
+    return removed;
+  }
+
+  /**
+   * Restore fold state saved by getState().
+   */
+  restoreFoldedRegions(state: FoldState[]): void {
+    this.updateFoldRegions();
+    this.foldManager.restoreFoldState(state);
+    this.ctx.markDirty();
+  }
+
+  private getCursorLines(): number[] {
+    return this.cursors.map((c) => c.position.line);
+  }
+
+  /**
+   * Move cursors off hidden lines and notify listeners after a fold command.
+   */
+  private afterFoldCommand(): void {
+    for (const cursor of this.cursors) {
+      if (!this.foldManager.isHidden(cursor.position.line)) continue;
+      for (let i = cursor.position.line; i >= 0; i--) {
+        if (!this.foldManager.isHidden(i)) {
+          cursor.position.line = i;
+          break;
+        }
+      }
+      this.ensureCursorColumnInBounds(cursor);
+    }
+
+    this.callbacks.onFoldChange?.();
+    this.ctx.markDirty();
+  }
+
   /**
    * Ensure a cursor's column is within the line bounds.
    */
@@ -562,6 +766,28 @@ export class DocumentEditor extends BaseElement {
     this.ctx.markDirty();
   }
 
+  /**
+   * Use folding ranges from a language server, or null to go back to
+   * indentation-based folding. Ranges are kept across edits until replaced.
+   */
+  setFoldingRanges(ranges: Array<{ startLine: number; endLine: number; kind?: string }> | null): void {
+    if (ranges === null && this.providedFoldRanges === null) return;
+    this.providedFoldRanges = ranges;
+    this.lastFoldVersion = -1;
+    this.updateFoldRegions();
+    this.ctx.markDirty();
+  }
+
+  /**
+   * Set the document language (used for region markers and comment folding).
+   */
+  setLanguageId(languageId: string | undefined): void {
+    if (languageId === this.languageId) return;
+    this.languageId = languageId;
+    this.lastFoldVersion = -1;
+    this.updateFoldRegions();
+  }
+
   /**
    * Update fold regions when content changes.
    */
@@ -570,7 +796,11 @@ export class DocumentEditor extends BaseElement {
     if (this.contentVersion === this.lastFoldVersion) return;
 
     const lineTexts = this.lines.map((l) => l.text);
-    this.foldManager.computeRegions(lineTexts);
+    if (this.providedFoldRanges) {
+      this.foldManager.setRegions(this.providedFoldRanges, lineTexts);
+    } else {
+      this.foldManager.computeRegions(lineTexts, this.languageId);
+    }
     this.lastFoldVersion = this.contentVersion;
   }
 
@@ -615,6 +845,13 @@ export class DocumentEditor extends BaseElement {
     return this.lines.map((l) => l.text).join('\n');
   }
 
+  /**
+   * Get the content version (increments on every change).
+   */
+  getContentVersion(): number {
+    return this.contentVersion;
+  }
+
   /**
    * Get lines (read-only access for external use).
    */
@@ -637,6 +874,22 @@ export class DocumentEditor extends BaseElement {
     return this.diagnostics;
   }
 
+  /**
+   * Get the most severe diagnostic under the primary cursor.
+   */
+  getDiagnosticAtCursor(): DiagnosticInfo | null {
+    const { line, column } = this.getCursor();
+    let found: DiagnosticInfo | null = null;
+    for (const d of this.diagnostics) {
+      const afterStart = line > d.startLine || (line === d.startLine && column >= d.startColumn);
+      const beforeEnd = line < d.endLine || (line === d.endLine && column <= d.endColumn);
+      if (afterStart && beforeEnd && (!found || d.severity < found.severity)) {
+        found = d;
+      }
+    }
+    return found;
+  }
+
   /**
    * Get diagnostics for a specific line (for gutter rendering).
    */
@@ -688,6 +941,48 @@ export class DocumentEditor extends BaseElement {
     }
   }
 
+  // ─────────────────────────────────────────────────────────────────────────
+  // Links and Colour Decorators
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /**
+   * Set links (underlined; Ctrl+click opens them).
+   */
+  setDocumentLinks(links: DocumentLinkInfo[]): void {
+    this.links = links;
+    this.ctx.markDirty();
+  }
+
+  getDocumentLinks(): readonly DocumentLinkInfo[] {
+    return this.links;
+  }
+
+  /**
+   * Get the link at a position, if any.
+   */
+  getLinkAt(position: CursorPosition): DocumentLinkInfo | null {
+    return this.links.find((link) => isInRange(position, link.startLine, link.startColumn, link.endLine, link.endColumn)) ?? null;
+  }
+
+  /**
+   * Set colour values to render as swatches.
+   */
+  setColorDecorators(colors: ColorDecoratorInfo[]): void {
+    this.colorDecorators = colors;
+    this.ctx.markDirty();
+  }
+
+  getColorDecorators(): readonly ColorDecoratorInfo[] {
+    return this.colorDecorators;
+  }
+
+  /**
+   * Get the colour value at a position, if any.
+   */
+  getColorAt(position: CursorPosition): ColorDecoratorInfo | null {
+    return this.colorDecorators.find((c) => isInRange(position, c.line, c.startColumn, c.line, c.endColumn)) ?? null;
+  }
+
   // ─────────────────────────────────────────────────────────────────────────
   // Git Line Changes
   // ─────────────────────────────────────────────────────────────────────────
@@ -2697,6 +2992,11 @@ export class DocumentEditor extends BaseElement {
 
     // Clear inline diff screen positions for this render pass
     this.inlineDiffScreenPositions.clear();
+    this.primaryCursorScreen = null;
+
+    // Screen readers follow the terminal cursor, so the painted cursor and
+    // current-line highlight only add redraws
+    const screenReaderMode = this.ctx.getSetting('tui.accessibility.screenReaderMode', false);
 
     // Use centralized focus colors for consistent focus indication
     const bg = this.ctx.getBackgroundForFocus('editor', this.focused);
@@ -2709,6 +3009,7 @@ export class DocumentEditor extends BaseElement {
     const selectionBg = this.ctx.getSelectionBackground('editor', this.focused);
     const lineHighlight = this.ctx.getThemeColor('editor.lineHighlightBackground', '#2a2d2e');
     const foldEllipsisFg = this.ctx.getThemeColor('editorGutter.foldingControlForeground', '#c5c5c5');
+    const paragraph = this.dimOutsideParagraph ? this.getCurrentParagraph() : null;
 
     // Calculate layout dimensions
     const rightMargin = this.getRightMarginWidth();
@@ -2743,8 +3044,9 @@ export class DocumentEditor extends BaseElement {
 
       // Determine line background - highlight if any cursor is on this line
       const isCurrentLine = this.cursors.some((c) => c.position.line === bufferLine);
-      const lineBg = isCurrentLine && this.focused ? lineHighlight : bg;
-      const currentGutterBg = isCurrentLine && this.focused ? lineHighlight : gutterBg;
+      const highlightLine = isCurrentLine && this.focused && !screenReaderMode;
+      const lineBg = highlightLine ? lineHighlight : bg;
+      const currentGutterBg = highlightLine ? lineHighlight : gutterBg;
 
       // Render gutter: [diagnostic icon][line number][fold indicator][space]
       // Only show line number on first wrapped row
@@ -2826,12 +3128,18 @@ export class DocumentEditor extends BaseElement {
         }
       }
 
-      // Render diagnostic underlines
+      // Render colour swatches, link and diagnostic underlines
       if (wrapOffset === 0) {
-        // Only render underlines on first wrapped row (simplification)
+        // Only render decorations on first wrapped row (simplification)
+        this.renderColorDecorators(buffer, contentX, screenY, bufferLine, contentWidth);
+        this.renderLinkUnderlines(buffer, contentX, screenY, bufferLine, contentWidth);
         this.renderDiagnosticUnderlines(buffer, contentX, screenY, bufferLine, contentWidth, lineBg);
       }
 
+      if (paragraph && (bufferLine < paragraph.startLine || bufferLine > paragraph.endLine)) {
+        this.dimRow(buffer, x, screenY, width - rightMargin);
+      }
+
       // Render all cursors on this line
       if (this.focused) {
         for (const cursor of this.cursors) {
@@ -2853,8 +3161,14 @@ export class DocumentEditor extends BaseElement {
               const cursorCol = screenColFromStart - (this.wordWrapEnabled ? 0 : this.scrollLeft);
               if (cursorCol >= 0 && cursorCol < contentWidth) {
                 const cursorX = contentX + cursorCol;
-                const cursorChar = buffer.get(cursorX, screenY)?.char ?? ' ';
-                buffer.set(cursorX, screenY, { char: cursorChar, fg: bg, bg: cursorBg });
+                const isPrimary = cursor === this.cursors[this.primaryCursorIndex];
+                if (isPrimary) {
+                  this.primaryCursorScreen = { x: cursorX, y: screenY };
+                }
+                if (!isPrimary || !screenReaderMode) {
+                  const cursorChar = buffer.get(cursorX, screenY)?.char ?? ' ';
+                  buffer.set(cursorX, screenY, { char: cursorChar, fg: bg, bg: cursorBg });
+                }
               }
             }
           }
@@ -2909,6 +3223,16 @@ export class DocumentEditor extends BaseElement {
     this.renderScrollbar(buffer);
   }
 
+  /**
+   * Fade a rendered row toward its background.
+   */
+  private dimRow(buffer: ScreenBuffer, x: number, y: number, width: number): void {
+    for (let col = x; col < x + width; col++) {
+      const cell = buffer.get(col, y);
+      if (cell) buffer.set(col, y, { ...cell, fg: blendColors(cell.fg, cell.bg, 0.6) });
+    }
+  }
+
   /**
    * Render diagnostic underlines for a line.
    * Adds a colored underline character under the diagnostic range.
@@ -2978,6 +3302,68 @@ export class DocumentEditor extends BaseElement {
     }
   }
 
+  /**
+   * Render colour values with the colour itself as background.
+   */
+  private renderColorDecorators(
+    buffer: ScreenBuffer,
+    contentX: number,
+    screenY: number,
+    bufferLine: number,
+    contentWidth: number
+  ): void {
+    if (this.colorDecorators.length === 0) return;
+
+    const tabSize = this.ctx.getSetting('editor.tabSize', 2);
+    const lineText = this.lines[bufferLine]?.text ?? '';
+
+    for (const decorator of this.colorDecorators) {
+      if (decorator.line !== bufferLine) continue;
+
+      const fg = isLightColor(decorator.color) ? '#000000' : '#ffffff';
+      const start = this.bufferColumnToScreenColumn(lineText, decorator.startColumn, tabSize) - this.scrollLeft;
+      const end = this.bufferColumnToScreenColumn(lineText, decorator.endColumn, tabSize) - this.scrollLeft;
+      for (let col = Math.max(0, start); col < Math.min(contentWidth, end); col++) {
+        const cell = buffer.get(contentX + col, screenY);
+        if (cell) {
+          buffer.set(contentX + col, screenY, { ...cell, fg, bg: decorator.color });
+        }
+      }
+    }
+  }
+
+  /**
+   * Underline document links.
+   */
+  private renderLinkUnderlines(
+    buffer: ScreenBuffer,
+    contentX: number,
+    screenY: number,
+    bufferLine: number,
+    contentWidth: number
+  ): void {
+    if (this.links.length === 0) return;
+
+    const tabSize = this.ctx.getSetting('editor.tabSize', 2);
+    const lineText = this.lines[bufferLine]?.text ?? '';
+    const linkColor = this.ctx.getThemeColor('editorLink.activeForeground', '#4e94ce');
+
+    for (const link of this.links) {
+      if (bufferLine < link.startLine || bufferLine > link.endLine) continue;
+
+      const startCol = link.startLine === bufferLine ? link.startColumn : 0;
+      const endCol = link.endLine === bufferLine ? link.endColumn : lineText.length;
+      const start = this.bufferColumnToScreenColumn(lineText, startCol, tabSize) - this.scrollLeft;
+      const end = this.bufferColumnToScreenColumn(lineText, endCol, tabSize) - this.scrollLeft;
+      for (let col = Math.max(0, start); col < Math.min(contentWidth, end); col++) {
+        const cell = buffer.get(contentX + col, screenY);
+        if (cell) {
+          buffer.set(contentX + col, screenY, { ...cell, underline: true, underlineColor: linkColor });
+        }
+      }
+    }
+  }
+
   /**
    * Get the underline color for a diagnostic severity.
    */
@@ -3730,6 +4116,9 @@ export class DocumentEditor extends BaseElement {
       return true;
     }
     if (event.key === 'Tab') {
+      if (!event.shift && this.ctx.getSetting('editor.emmet.triggerExpansionOnTab', true) && this.expandEmmetAbbreviation()) {
+        return true;
+      }
       const indentOptions = this.getIndentOptions();
       const tabText = indentOptions.insertSpaces
         ? ' '.repeat(indentOptions.tabSize)
@@ -3764,7 +4153,13 @@ export class DocumentEditor extends BaseElement {
 
     // Regular character input
     if (event.key.length === 1 && !event.ctrl && !event.alt && !event.meta) {
+      if (this.shouldAutoSurround(event.key) && this.surroundSelections(event.key)) {
+        return true;
+      }
       this.insertText(event.key);
+      if (event.key === '>') {
+        this.autoCloseTags();
+      }
       // Notify of character typed for autocomplete trigger
       const position = this.getPrimaryCursor().position;
       this.callbacks.onCharTyped?.(event.key, { line: position.line, column: position.column });
@@ -3946,7 +4341,11 @@ export class DocumentEditor extends BaseElement {
           const clickPos = { line: bufferLine, column };
 
           // Check modifiers FIRST - they take priority over multi-click detection
-          if (event.ctrl) {
+          const link = event.ctrl && this.callbacks.onOpenLink ? this.getLinkAt(clickPos) : null;
+          if (link) {
+            // Ctrl+click on a link - open it
+            this.callbacks.onOpenLink!(link);
+          } else if (event.ctrl) {
             // Ctrl+click - add cursor (always, regardless of click count)
             this.addCursor(clickPos);
           } else if (event.shift) {
@@ -4222,6 +4621,263 @@ export class DocumentEditor extends BaseElement {
     this.ctx.markDirty();
   }
 
+  // ─────────────────────────────────────────────────────────────────────────
+  // Accessibility
+  // ─────────────────────────────────────────────────────────────────────────
+
+  override getAccessibleDescription(): string {
+    const name = this.title.replace(/ \(read-only\)$/, '');
+    const flags = [this.modified ? 'modified' : '', this.readOnly ? 'read only' : ''].filter(Boolean);
+    return [`${name}, editor`, ...flags].join(', ');
+  }
+
+  override getCursorScreenPosition(): Position | null {
+    return this.primaryCursorScreen;
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Surround, Tags & Emmet
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /**
+   * Surround each selection with a pair or tag (see getSurroundPair).
+   * The selections stay on the surrounded text. Returns false if there is
+   * no selection or the target isn't a pair.
+   */
+  surroundSelections(target: string): boolean {
+    if (this.readOnly) return false;
+
+    const pair = getSurroundPair(target);
+    const selected = this.cursors.filter((c) => this.hasSelectionContent(c.selection));
+    if (!pair || selected.length === 0) return false;
+
+    const changes: TextChange[] = [];
+    const ranges = selected
+      .map((c) => this.getSelectionRange(c.selection!))
+      .sort((a, b) => this.comparePositions(a.start, b.start));
+    for (const { start, end } of ranges) {
+      const startOffset = this.positionToOffset(start);
+      const endOffset = this.positionToOffset(end);
+      changes.push({ start: startOffset, end: startOffset, text: pair.open });
+      changes.push({ start: endOffset, end: endOffset, text: pair.close });
+    }
+
+    const selections = this.cursors.map((c) => {
+      const anchor = this.positionToOffset(c.selection?.anchor ?? c.position);
+      const head = this.positionToOffset(c.position);
+      const forward = anchor <= head;
+      return {
+        anchor: mapOffset(anchor, changes, forward ? 'after' : 'before'),
+        head: mapOffset(head, changes, forward ? 'before' : 'after'),
+      };
+    });
+
+    this.applyTextChanges(changes, selections);
+    return true;
+  }
+
+  /**
+   * Replace the pair of `target` around each cursor with `replacement`
+   * (vim-surround `cs`). Returns false if no cursor is inside such a pair.
+   */
+  changeSurrounding(target: string, replacement: string): boolean {
+    const content = this.getContent();
+    return this.applySurroundChanges((offset) => changeSurroundingPair(content, offset, target, replacement));
+  }
+
+  /**
+   * Delete the pair of `target` around each cursor (vim-surround `ds`).
+   */
+  deleteSurrounding(target: string): boolean {
+    const content = this.getContent();
+    return this.applySurroundChanges((offset) => deleteSurroundingPair(content, offset, target));
+  }
+
+  /**
+   * Expand the Emmet abbreviation before each cursor. The syntax (HTML, JSX
+   * or CSS) comes from the language and the scope at the cursor. Returns
+   * false, changing nothing, unless every cursor has an abbreviation.
+   */
+  expandEmmetAbbreviation(): boolean {
+    if (this.readOnly || !this.languageId) return false;
+    if (this.cursors.some((c) => this.hasSelectionContent(c.selection))) return false;
+
+    const content = this.getContent();
+    const indentOptions = this.getIndentOptions();
+    const indentUnit = indentOptions.insertSpaces ? ' '.repeat(indentOptions.tabSize) : '\t';
+    const expansions: Array<{ change: TextChange; cursor: number }> = [];
+
+    for (const cursor of this.cursors) {
+      const offset = this.positionToOffset(cursor.position);
+      const syntax = getEmmetSyntax(this.languageId, content, offset);
+      if (!syntax) return false;
+
+      const lineText = this.lines[cursor.position.line]!.text;
+      const found = extractAbbreviation(lineText.slice(0, cursor.position.column), syntax);
+      const expansion = found && expandAbbreviation(found.abbreviation, syntax, indentUnit);
+      if (!found || !expansion) return false;
+
+      // Continuation lines keep the current line's indentation
+      const lineIndent = /^\s*/.exec(lineText)![0];
+      const indentLines = (text: string): string => text.replace(/\n/g, `\n${lineIndent}`);
+      expansions.push({
+        change: { start: offset - (cursor.position.column - found.start), end: offset, text: indentLines(expansion.text) },
+        cursor: indentLines(expansion.text.slice(0, expansion.cursor)).length,
+      });
+    }
+
+    const changes = expansions.map((e) => e.change).sort((a, b) => a.start - b.start);
+    const selections = expansions.map(({ change, cursor }) => {
+      const offset = mapOffset(change.start, changes, 'before') + cursor;
+      return { anchor: offset, head: offset };
+    });
+
+    this.applyTextChanges(changes, selections);
+    return true;
+  }
+
+  /**
+   * Insert closing tags after `>` was typed at each cursor, in markup
+   * (HTML or JSX) scope.
+   */
+  private autoCloseTags(): void {
+    if (!this.languageId || !this.ctx.getSetting('editor.autoClosingTags', true)) return;
+
+    const content = this.getContent();
+    const changes: TextChange[] = [];
+    for (const cursor of this.cursors) {
+      const offset = this.positionToOffset(cursor.position);
+      const syntax = this.languageId === 'xml' ? 'html' : getEmmetSyntax(this.languageId, content, offset);
+      if (syntax !== 'html' && syntax !== 'jsx') continue;
+
+      const lineText = this.lines[cursor.position.line]!.text;
+      const close = getAutoCloseTag(
+        lineText.slice(0, cursor.position.column),
+        lineText.slice(cursor.position.column),
+        { jsx: syntax === 'jsx' }
+      );
+      if (close) changes.push({ start: offset, end: offset, text: close });
+    }
+    if (changes.length === 0) return;
+
+    changes.sort((a, b) => a.start - b.start);
+    const selections = this.cursors.map((c) => {
+      const offset = mapOffset(this.positionToOffset(c.position), changes, 'before');
+      return { anchor: offset, head: offset };
+    });
+    this.applyTextChanges(changes, selections);
+  }
+
+  /**
+   * Whether typing `char` should surround the selections instead of
+   * replacing them (editor.autoSurround).
+   */
+  private shouldAutoSurround(char: string): boolean {
+    const mode = this.ctx.getSetting('editor.autoSurround', 'languageDefined');
+    const isBracket = char === '(' || char === '[' || char === '{';
+    const isQuote = char === '"' || char === "'" || char === '`';
+    const enabled = mode === 'languageDefined'
+      ? isBracket || isQuote
+      : (mode === 'brackets' && isBracket) || (mode === 'quotes' && isQuote);
+    return enabled && this.cursors.some((c) => this.hasSelectionContent(c.selection));
+  }
+
+  /**
+   * Apply the changes computed at each cursor, skipping duplicates (two
+   * cursors in the same pair) and cursors whose changes overlap others.
+   */
+  private applySurroundChanges(changesAt: (offset: number) => TextChange[] | null): boolean {
+    if (this.readOnly) return false;
+
+    const changes: TextChange[] = [];
+    for (const cursor of this.cursors) {
+      const found = changesAt(this.positionToOffset(cursor.position));
+      if (!found) continue;
+      const overlaps = found.some((f) =>
+        changes.some((c) => f.start < c.end && c.start < f.end && !(f.start === c.start && f.end === c.end))
+      );
+      const duplicate = found.every((f) => changes.some((c) => f.start === c.start && f.end === c.end));
+      if (!overlaps && !duplicate) changes.push(...found);
+    }
+    if (changes.length === 0) return false;
+
+    changes.sort((a, b) => a.start - b.start);
+    const selections = this.cursors.map((c) => {
+      const offset = mapOffset(this.positionToOffset(c.position), changes, 'before');
+      return { anchor: offset, head: offset };
+    });
+    this.applyTextChanges(changes, selections);
+    return true;
+  }
+
+  /**
+   * Apply non-overlapping changes (sorted by offset) as one undoable edit,
+   * then set one cursor per selection (offsets in the new content).
+   */
+  private applyTextChanges(changes: TextChange[], selections: Array<{ anchor: number; head: number }>): void {
+    const cursorsBefore = this.createCursorSnapshot();
+    const operations: EditOperation[] = [];
+
+    // Resolve positions up front, then apply last-to-first so they stay valid
+    const resolved = changes.map((c) => ({
+      start: this.offsetToPosition(c.start),
+      end: this.offsetToPosition(c.end),
+      text: c.text,
+    }));
+    for (let i = resolved.length - 1; i >= 0; i--) {
+      const { start, end, text } = resolved[i]!;
+      const removed = this.getTextInRange(start, end);
+      if (removed) {
+        operations.push({ type: 'delete', position: this.clonePosition(start), text: removed });
+        this.deleteTextRange(start, removed);
+      }
+      if (text) {
+        operations.push({ type: 'insert', position: this.clonePosition(start), text });
+        this.insertTextRaw(start, text);
+      }
+    }
+
+    this.cursors = selections.map(({ anchor, head }) => {
+      const position = this.offsetToPosition(head);
+      return {
+        position,
+        selection: anchor === head ? null : { anchor: this.offsetToPosition(anchor), head: this.clonePosition(position) },
+        desiredColumn: position.column,
+      };
+    });
+    this.primaryCursorIndex = Math.min(this.primaryCursorIndex, this.cursors.length - 1);
+
+    const cursorsAfter = this.createCursorSnapshot();
+    this.pushUndoAction(operations, cursorsBefore, cursorsAfter);
+
+    this.modified = true;
+    this.contentVersion++;
+    this.updateGutterWidth();
+    this.updateFoldRegions();
+    this.ensurePrimaryCursorVisible();
+    this.callbacks.onContentChange?.(this.getContent());
+    this.ctx.markDirty();
+  }
+
+  private positionToOffset(position: CursorPosition): number {
+    let offset = 0;
+    for (let i = 0; i < position.line && i < this.lines.length; i++) {
+      offset += this.lines[i]!.text.length + 1;
+    }
+    return offset + position.column;
+  }
+
+  private offsetToPosition(offset: number): CursorPosition {
+    let remaining = offset;
+    for (let line = 0; line < this.lines.length; line++) {
+      const length = this.lines[line]!.text.length;
+      if (remaining <= length) return { line, column: remaining };
+      remaining -= length + 1;
+    }
+    const last = this.lines.length - 1;
+    return { line: last, column: this.lines[last]!.text.length };
+  }
+
   /**
    * Select the next occurrence of the currently selected text.
    * If nothing is selected, selects the word under the cursor first.
@@ -4554,7 +5210,7 @@ export class DocumentEditor extends BaseElement {
       scrollTop: this.scrollTop,
       cursor: { line: primaryCursor.position.line, column: primaryCursor.position.column },
       cursors: this.cursors.map((c) => this.cloneCursor(c)),
-      foldedRegions: this.foldManager.getFoldedLines(),
+      foldedRegions: this.foldManager.getFoldState(),
       undoHistory: this.undoManager.serialize(),
     };
   }
@@ -4574,9 +5230,7 @@ export class DocumentEditor extends BaseElement {
     }
     // Restore folded regions
     if (s.foldedRegions && s.foldedRegions.length > 0) {
-      for (const line of s.foldedRegions) {
-        this.foldManager.fold(line);
-      }
+      this.foldManager.restoreFoldState(s.foldedRegions);
     }
     // Restore undo history
     if (s.undoHistory) {
@@ -4607,6 +5261,22 @@ export class DocumentEditor extends BaseElement {
   }
 }
 
+/**
+ * Check whether a position is inside a range (end exclusive).
+ */
+function isInRange(
+  position: CursorPosition,
+  startLine: number,
+  startColumn: number,
+  endLine: number,
+  endColumn: number
+): boolean {
+  if (position.line < startLine || position.line > endLine) return false;
+  if (position.line === startLine && position.column < startColumn) return false;
+  if (position.line === endLine && position.column >= endColumn) return false;
+  return true;
+}
+
 // ============================================
 // Factory Function
 // ============================================
@@ -4622,3 +5292,23 @@ export function createDocumentEditor(
 ): DocumentEditor {
   return new DocumentEditor(id, title, ctx, callbacks);
 }
+
+/**
+ * Map an offset through sorted changes. At an insertion point, 'after'
+ * moves past the inserted text and 'before' stays in front of it.
+ */
+function mapOffset(offset: number, changes: TextChange[], bias: 'before' | 'after'): number {
+  let delta = 0;
+  for (const change of changes) {
+    const delta2 = change.text.length - (change.end - change.start);
+    if (change.end < offset || (change.end === offset && (change.start < offset || bias === 'after'))) {
+      delta += delta2;
+    } else if (change.start < offset) {
+      // Inside a replaced range
+      return change.start + delta + (bias === 'after' ? change.text.length : 0);
+    } else {
+      break;
+    }
+  }
+  return offset + delta;
+}
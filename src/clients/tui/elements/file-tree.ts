@@ -5,7 +5,7 @@
  */
 
 import { BaseElement, type ElementContext } from './base.ts';
-import type { KeyEvent, MouseEvent } from '../types.ts';
+import type { KeyEvent, MouseEvent, Position } from '../types.ts';
 import type { ScreenBuffer } from '../rendering/buffer.ts';
 
 // Import shared character width utilities (single source of truth)
@@ -544,6 +544,40 @@ export class FileTree extends BaseElement {
     }
   }
 
+  // ─────────────────────────────────────────────────────────────────────────
+  // Accessibility
+  // ─────────────────────────────────────────────────────────────────────────
+
+  override getAccessibleDescription(): string {
+    const viewNode = this.viewNodes[this.selectedIndex];
+    if (!viewNode) return `${this.title}, empty`;
+
+    const { node, depth } = viewNode;
+    const parts = [node.name];
+    if (node.isDirectory) {
+      parts.push(node.expanded ? 'folder, expanded' : 'folder, collapsed');
+    }
+    const gitStatus = node.gitStatus ? FileTree.GIT_STATUS_NAMES[node.gitStatus] : undefined;
+    if (gitStatus) parts.push(gitStatus);
+    parts.push(`level ${depth + 1}`, `${this.selectedIndex + 1} of ${this.viewNodes.length}`);
+    return parts.join(', ');
+  }
+
+  override getCursorScreenPosition(): Position | null {
+    const viewNode = this.viewNodes[this.selectedIndex];
+    const row = this.selectedIndex - this.scrollTop;
+    if (!viewNode || row < 0 || row >= this.bounds.height) return null;
+    return { x: this.bounds.x + 1 + viewNode.depth * 2, y: this.bounds.y + row };
+  }
+
+  /** Spoken names of git status letters */
+  private static readonly GIT_STATUS_NAMES: Record<string, string> = {
+    M: 'modified',
+    A: 'added',
+    D: 'deleted',
+    '?': 'untracked',
+  };
+
   // ─────────────────────────────────────────────────────────────────────────
   // Rendering
   // ─────────────────────────────────────────────────────────────────────────
@@ -8,6 +8,7 @@ import { BaseElement, type ElementContext } from './base.ts';
 import type { KeyEvent, MouseEvent } from '../types.ts';
 import type { ScreenBuffer } from '../rendering/buffer.ts';
 import { TIMEOUTS } from '../../../constants.ts';
+import { t } from '../i18n/index.ts';
 
 // ============================================
 // Types
@@ -420,8 +421,8 @@ export class GitPanel extends BaseElement {
       if (this.state.behind > 0) headerText += `↓${this.state.behind}`;
       headerText += `)`;
     }
-    if (this.state.merging) headerText += ' MERGING';
-    if (this.state.rebasing) headerText += ' REBASING';
+    if (this.state.merging) headerText += ` ${t('git.merging')}`;
+    if (this.state.rebasing) headerText += ` ${t('git.rebasing')}`;
     buffer.writeString(x, y, headerText.padEnd(width, ' '), headerFg, headerBg);
 
     // Render view nodes (reserve 2 rows for hint bar at bottom when focused)
@@ -498,7 +499,7 @@ export class GitPanel extends BaseElement {
 
     // Empty state message
     if (this.viewNodes.length === 0) {
-      const msg = 'No changes';
+      const msg = t('git.noChanges');
       const msgX = x + Math.floor((width - msg.length) / 2);
       buffer.writeString(msgX, contentStart + 2, msg, '#888888', bg);
     }
@@ -512,8 +513,8 @@ export class GitPanel extends BaseElement {
       // Build hints - 2 lines, truncated to fit width
       // Line 1: s:stage, S:stage-all, u:unstage, d:discard
       // Line 2: c:commit, r:refresh, Enter:diff, o:open
-      let line1 = ' s:stage S:all u:unstage d:discard';
-      let line2 = ' c:commit r:refresh ↵:diff o:open';
+      let line1 = ` ${t('git.hintStage')}`;
+      let line2 = ` ${t('git.hintCommit')}`;
 
       // Truncate and pad to exact width
       if (line1.length > width) line1 = line1.slice(0, width);
@@ -541,11 +542,11 @@ export class GitPanel extends BaseElement {
   private getSectionName(section: GitSection): string {
     switch (section) {
       case 'staged':
-        return 'Staged Changes';
+        return t('git.stagedChanges');
       case 'unstaged':
-        return 'Changes';
+        return t('git.changes');
       case 'untracked':
-        return 'Untracked Files';
+        return t('git.untrackedFiles');
     }
   }
 
@@ -0,0 +1,575 @@
+/**
+ * Image Viewer
+ *
+ * Shows PNG, JPEG, GIF and SVG files. Images are decoded in-process and
+ * drawn with the Kitty graphics protocol or sixel when the terminal
+ * supports them (see `tui.imagePreview.protocol`), or with half-block
+ * characters in truecolor otherwise.
+ *
+ * Keys:
+ * - +/=, -: zoom in/out
+ * - 0, f: fit to the view
+ * - 1: actual size
+ * - Arrows, h/j/k/l: pan
+ * - i: toggle the metadata panel
+ */
+
+import { BaseElement, type ElementContext } from './base.ts';
+import type { KeyEvent, MouseEvent, Rect } from '../types.ts';
+import type { ScreenBuffer } from '../rendering/buffer.ts';
+import type { GraphicsPlacement } from '../rendering/graphics-layer.ts';
+import {
+  decodeImage,
+  decodeSvg,
+  fitSize,
+  resampleRegion,
+  type DecodedImage,
+  type PixelBuffer,
+} from '../../../core/image/index.ts';
+import {
+  CELL_PIXEL_HEIGHT,
+  CELL_PIXEL_WIDTH,
+  resolveGraphicsProtocol,
+  type GraphicsProtocol,
+} from '../ansi/graphics.ts';
+import { hexToRgb } from '../ansi/colors.ts';
+
+// ============================================
+// Types
+// ============================================
+
+/**
+ * Image viewer state for serialization.
+ */
+export interface ImageViewerState {
+  uri: string;
+  /** Zoom factor, or null to fit the view */
+  zoom: number | null;
+  showInfo: boolean;
+}
+
+/** Zoom steps for +/- */
+const ZOOM_LEVELS = [0.05, 0.1, 0.125, 0.25, 0.33, 0.5, 0.67, 0.75, 1, 1.5, 2, 3, 4, 6, 8, 12, 16];
+
+/** Width of the metadata panel in cells */
+const INFO_PANEL_WIDTH = 28;
+
+/** Fraction of the view moved by one pan step */
+const PAN_STEP = 0.1;
+
+/** Largest scale an SVG is re-rasterized at for sharp zoomed views */
+const MAX_SVG_RASTER_SCALE = 8;
+
+// ============================================
+// Image Viewer Element
+// ============================================
+
+export class ImageViewer extends BaseElement {
+  private uri = '';
+  private bytes: Uint8Array | null = null;
+  private fileSize = 0;
+  private image: DecodedImage | null = null;
+  private error: string | null = null;
+
+  /** Zoom factor (1 = one image pixel per display pixel), null = fit */
+  private zoom: number | null = null;
+  /** Pan offset of the view into the scaled image, in display pixels */
+  private panX = 0;
+  private panY = 0;
+  private showInfo = false;
+
+  /** SVG re-rasterized at a larger scale, keyed by that scale */
+  private svgRaster: { scale: number; image: PixelBuffer } | null = null;
+
+  constructor(id: string, title: string, ctx: ElementContext) {
+    super('ImageViewer', id, title, ctx);
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Image
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /**
+   * Decode and show image bytes. Decode errors are shown in the view.
+   */
+  setImage(bytes: Uint8Array, uri: string): void {
+    this.uri = uri;
+    this.bytes = bytes;
+    this.fileSize = bytes.length;
+    this.svgRaster = null;
+    this.panX = 0;
+    this.panY = 0;
+    try {
+      this.image = decodeImage(bytes, uri);
+      this.error = null;
+    } catch (error) {
+      this.image = null;
+      this.error = error instanceof Error ? error.message : String(error);
+    }
+    this.ctx.markDirty();
+  }
+
+  /**
+   * Show an error instead of an image (e.g. the file couldn't be read).
+   */
+  setError(uri: string, message: string): void {
+    this.uri = uri;
+    this.bytes = null;
+    this.image = null;
+    this.error = message;
+    this.ctx.markDirty();
+  }
+
+  getUri(): string {
+    return this.uri;
+  }
+
+  getImage(): DecodedImage | null {
+    return this.image;
+  }
+
+  getError(): string | null {
+    return this.error;
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Zoom and Pan
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /**
+   * Whether the image is fitted to the view.
+   */
+  isFit(): boolean {
+    return this.zoom === null;
+  }
+
+  /**
+   * Effective scale (display pixels per image pixel).
+   */
+  getScale(): number {
+    if (this.zoom !== null || !this.image) return this.zoom ?? 1;
+    const grid = this.getGridSize();
+    return fitSize(this.image.width, this.image.height, grid.width, grid.height).scale;
+  }
+
+  setZoom(zoom: number | null): void {
+    if (zoom === null) {
+      this.zoom = null;
+    } else {
+      // Keep the center of the view in place
+      const grid = this.getGridSize();
+      const ratio = zoom / this.getScale();
+      const centerX = (this.panX + grid.width / 2) * ratio;
+      const centerY = (this.panY + grid.height / 2) * ratio;
+      this.zoom = Math.max(ZOOM_LEVELS[0]!, Math.min(ZOOM_LEVELS[ZOOM_LEVELS.length - 1]!, zoom));
+      this.panX = centerX - grid.width / 2;
+      this.panY = centerY - grid.height / 2;
+    }
+    this.clampPan();
+    this.ctx.markDirty();
+  }
+
+  zoomIn(): void {
+    const scale = this.getScale();
+    this.setZoom(ZOOM_LEVELS.find((level) => level > scale + 1e-6) ?? ZOOM_LEVELS[ZOOM_LEVELS.length - 1]!);
+  }
+
+  zoomOut(): void {
+    const scale = this.getScale();
+    const lower = ZOOM_LEVELS.filter((level) => level < scale - 1e-6);
+    this.setZoom(lower[lower.length - 1] ?? ZOOM_LEVELS[0]!);
+  }
+
+  fit(): void {
+    this.setZoom(null);
+  }
+
+  actualSize(): void {
+    this.setZoom(1);
+  }
+
+  /**
+   * Pan by a fraction of the view in each direction.
+   */
+  pan(dx: number, dy: number): void {
+    const grid = this.getGridSize();
+    this.panX += Math.round(dx * grid.width);
+    this.panY += Math.round(dy * grid.height);
+    this.clampPan();
+    this.ctx.markDirty();
+  }
+
+  getPan(): { x: number; y: number } {
+    return { x: this.panX, y: this.panY };
+  }
+
+  isInfoVisible(): boolean {
+    return this.showInfo;
+  }
+
+  toggleInfo(): void {
+    this.showInfo = !this.showInfo;
+    this.ctx.markDirty();
+  }
+
+  private clampPan(): void {
+    if (!this.image) return;
+    const grid = this.getGridSize();
+    const scale = this.getScale();
+    const maxX = Math.max(0, Math.round(this.image.width * scale) - grid.width);
+    const maxY = Math.max(0, Math.round(this.image.height * scale) - grid.height);
+    this.panX = Math.max(0, Math.min(maxX, Math.round(this.panX)));
+    this.panY = Math.max(0, Math.min(maxY, Math.round(this.panY)));
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Layout
+  // ─────────────────────────────────────────────────────────────────────────
+
+  getProtocol(): GraphicsProtocol {
+    return resolveGraphicsProtocol(this.ctx.getSetting('tui.imagePreview.protocol', 'auto'));
+  }
+
+  /**
+   * Cell rectangle the image is drawn in (below the header, left of the
+   * metadata panel).
+   */
+  private computeImageRect(): Rect {
+    const { x, y, width, height } = this.bounds;
+    const infoWidth = this.showInfo && width > INFO_PANEL_WIDTH + 10 ? INFO_PANEL_WIDTH : 0;
+    return { x, y: y + 1, width: Math.max(0, width - infoWidth), height: Math.max(0, height - 1) };
+  }
+
+  /**
+   * Size of the view in display pixels: two per cell vertically for
+   * half-blocks, the assumed cell pixel size for graphics protocols.
+   */
+  private getGridSize(): { width: number; height: number } {
+    const rect = this.computeImageRect();
+    if (this.getProtocol() === 'halfblock') {
+      return { width: Math.max(1, rect.width), height: Math.max(1, rect.height * 2) };
+    }
+    return { width: Math.max(1, rect.width * CELL_PIXEL_WIDTH), height: Math.max(1, rect.height * CELL_PIXEL_HEIGHT) };
+  }
+
+  /**
+   * Visible part of the scaled image: its size in display pixels and
+   * offset within the view (images smaller than the view are centered).
+   */
+  private getViewport(): { left: number; top: number; width: number; height: number; offsetX: number; offsetY: number } {
+    const grid = this.getGridSize();
+    const scale = this.getScale();
+    const scaledWidth = Math.max(1, Math.round(this.image!.width * scale));
+    const scaledHeight = Math.max(1, Math.round(this.image!.height * scale));
+    const width = Math.min(grid.width, scaledWidth);
+    const height = Math.min(grid.height, scaledHeight);
+    return {
+      left: this.panX,
+      top: this.panY,
+      width,
+      height,
+      offsetX: Math.floor((grid.width - width) / 2),
+      offsetY: Math.floor((grid.height - height) / 2),
+    };
+  }
+
+  /**
+   * Pixels of the visible region.
+   */
+  getVisiblePixels(): PixelBuffer | null {
+    if (!this.image) return null;
+    const viewport = this.getViewport();
+    const scale = this.getScale();
+    const source = this.getRasterFor(scale);
+    const sourceScale = scale * (this.image.width / source.width);
+    return resampleRegion(source, sourceScale, sourceScale, viewport.left, viewport.top, viewport.width, viewport.height);
+  }
+
+  /**
+   * The image to resample from. SVGs are re-rasterized when zoomed in so
+   * edges stay sharp.
+   */
+  private getRasterFor(scale: number): PixelBuffer {
+    const image = this.image!;
+    if (image.metadata.format !== 'svg' || scale <= 1 || !this.bytes) {
+      return image;
+    }
+    const rasterScale = Math.min(MAX_SVG_RASTER_SCALE, Math.ceil(scale));
+    if (this.svgRaster?.scale !== rasterScale) {
+      try {
+        this.svgRaster = { scale: rasterScale, image: decodeSvg(this.bytes, { scale: rasterScale }) };
+      } catch {
+        return image;
+      }
+    }
+    return this.svgRaster.image;
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Graphics Protocols
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /**
+   * The inline image to show over this element, or null when half-blocks
+   * are used (or there is nothing to show).
+   */
+  getGraphicsPlacement(): GraphicsPlacement | null {
+    if (!this.image || !this.visible || this.getProtocol() === 'halfblock') return null;
+    const rect = this.computeImageRect();
+    if (rect.width <= 0 || rect.height <= 0) return null;
+
+    this.clampPan();
+    const viewport = this.getViewport();
+    const cellRect: Rect = {
+      x: rect.x + Math.floor(viewport.offsetX / CELL_PIXEL_WIDTH),
+      y: rect.y + Math.floor(viewport.offsetY / CELL_PIXEL_HEIGHT),
+      width: Math.max(1, Math.min(rect.width, Math.ceil(viewport.width / CELL_PIXEL_WIDTH))),
+      height: Math.max(1, Math.min(rect.height, Math.ceil(viewport.height / CELL_PIXEL_HEIGHT))),
+    };
+    const bg = hexToRgb(this.ctx.getThemeColor('editor.background', '#1e1e1e')) ?? { r: 30, g: 30, b: 30 };
+
+    return {
+      key: this.id,
+      rect: cellRect,
+      signature: `${this.uri}:${this.fileSize}:${this.getScale()}:${this.panX}:${this.panY}:${viewport.width}x${viewport.height}`,
+      getPixels: () => this.getVisiblePixels()!,
+      background: bg,
+    };
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Metadata
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /**
+   * Metadata as label/value rows.
+   */
+  getMetadataRows(): Array<[string, string]> {
+    if (!this.image) return [];
+    const meta = this.image.metadata;
+    const rows: Array<[string, string]> = [
+      ['Format', meta.format.toUpperCase()],
+      ['Size', `${meta.width} × ${meta.height}`],
+    ];
+    if (meta.colorType) rows.push(['Color', meta.colorType]);
+    if (meta.bitDepth) rows.push(['Bit depth', String(meta.bitDepth)]);
+    rows.push(['Alpha', meta.hasAlpha ? 'yes' : 'no']);
+    if (meta.interlaced !== undefined) rows.push(['Interlaced', meta.interlaced ? 'yes' : 'no']);
+    if (meta.progressive !== undefined) rows.push(['Progressive', meta.progressive ? 'yes' : 'no']);
+    if (meta.frameCount !== undefined) rows.push(['Frames', String(meta.frameCount)]);
+    rows.push(['File size', formatBytes(this.fileSize)]);
+    rows.push(['Zoom', `${Math.round(this.getScale() * 100)}%${this.isFit() ? ' (fit)' : ''}`]);
+    rows.push(['Renderer', this.getProtocol()]);
+    return rows;
+  }
+
+  override getAccessibleDescription(): string {
+    const name = this.uri.split('/').pop() ?? this.title;
+    if (!this.image) {
+      return `Image viewer, ${name}${this.error ? `, ${this.error}` : ''}`;
+    }
+    const meta = this.image.metadata;
+    return `Image viewer, ${name}, ${meta.width} by ${meta.height} ${meta.format.toUpperCase()}`;
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Rendering
+  // ─────────────────────────────────────────────────────────────────────────
+
+  render(buffer: ScreenBuffer): void {
+    const { x, y, width, height } = this.bounds;
+    if (width === 0 || height === 0) return;
+
+    const bg = this.ctx.getThemeColor('editor.background', '#1e1e1e');
+    const fg = this.ctx.getThemeColor('editor.foreground', '#d4d4d4');
+    const headerBg = this.ctx.getThemeColor('sideBarSectionHeader.background', '#383838');
+    const headerFg = this.ctx.getThemeColor('sideBarSectionHeader.foreground', '#cccccc');
+    const dimFg = this.ctx.getThemeColor('descriptionForeground', '#888888');
+    const errorFg = this.ctx.getThemeColor('editorError.foreground', '#f44747');
+
+    // Header
+    let header = ` ${this.uri.split('/').pop() ?? ''}`;
+    if (this.image) {
+      const meta = this.image.metadata;
+      const zoom = `${Math.round(this.getScale() * 100)}%${this.isFit() ? ' fit' : ''}`;
+      header += ` · ${meta.width}×${meta.height} ${meta.format.toUpperCase()} · ${zoom}`;
+    }
+    buffer.writeString(x, y, truncate(header, width).padEnd(width, ' '), headerFg, headerBg);
+
+    const rect = this.computeImageRect();
+    for (let row = rect.y; row < rect.y + rect.height; row++) {
+      buffer.writeString(rect.x, row, ' '.repeat(rect.width), fg, bg);
+    }
+
+    if (!this.image) {
+      const message = this.error ? `Cannot display image: ${this.error}` : 'Loading…';
+      buffer.writeString(rect.x + 1, rect.y + 1, truncate(message, Math.max(0, rect.width - 2)), this.error ? errorFg : dimFg, bg);
+    } else if (this.getProtocol() === 'halfblock' && rect.width > 0 && rect.height > 0) {
+      this.clampPan();
+      const viewport = this.getViewport();
+      const pixels = this.getVisiblePixels()!;
+      const originX = rect.x + viewport.offsetX;
+      // Half-blocks pair rows, so start on an even display row
+      const offsetRows = Math.floor(viewport.offsetY / 2);
+      renderHalfBlocks(buffer, pixels, originX, rect.y + offsetRows, bg);
+    }
+
+    if (this.showInfo && rect.width < width) {
+      this.renderInfo(buffer, { x: rect.x + rect.width, y: rect.y, width: width - rect.width, height: rect.height });
+    }
+  }
+
+  private renderInfo(buffer: ScreenBuffer, rect: Rect): void {
+    const bg = this.ctx.getThemeColor('sideBar.background', '#252526');
+    const fg = this.ctx.getThemeColor('sideBar.foreground', '#cccccc');
+    const dimFg = this.ctx.getThemeColor('descriptionForeground', '#888888');
+    const rows = this.getMetadataRows();
+    const labelWidth = Math.max(...rows.map(([label]) => label.length), 0) + 1;
+
+    for (let i = 0; i < rect.height; i++) {
+      const row = rows[i];
+      const screenY = rect.y + i;
+      buffer.writeString(rect.x, screenY, ' '.repeat(rect.width), fg, bg);
+      if (!row) continue;
+      const [label, value] = row;
+      buffer.writeString(rect.x + 1, screenY, truncate(label, rect.width - 2), dimFg, bg);
+      const valueWidth = rect.width - labelWidth - 2;
+      if (valueWidth > 0) {
+        buffer.writeString(rect.x + 1 + labelWidth, screenY, truncate(value, valueWidth), fg, bg);
+      }
+    }
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Input Handling
+  // ─────────────────────────────────────────────────────────────────────────
+
+  override handleKey(event: KeyEvent): boolean {
+    if (event.ctrl || event.alt || event.meta) return false;
+
+    switch (event.key) {
+      case '+':
+      case '=':
+        this.zoomIn();
+        return true;
+      case '-':
+        this.zoomOut();
+        return true;
+      case '0':
+      case 'f':
+        this.fit();
+        return true;
+      case '1':
+        this.actualSize();
+        return true;
+      case 'ArrowLeft':
+      case 'h':
+        this.pan(-PAN_STEP, 0);
+        return true;
+      case 'ArrowRight':
+      case 'l':
+        this.pan(PAN_STEP, 0);
+        return true;
+      case 'ArrowUp':
+      case 'k':
+        this.pan(0, -PAN_STEP);
+        return true;
+      case 'ArrowDown':
+      case 'j':
+        this.pan(0, PAN_STEP);
+        return true;
+      case 'i':
+        this.toggleInfo();
+        return true;
+    }
+    return false;
+  }
+
+  override handleMouse(event: MouseEvent): boolean {
+    if (event.type === 'scroll') {
+      const direction = event.scrollDirection ?? 1;
+      if (event.ctrl) {
+        if (direction < 0) this.zoomIn();
+        else this.zoomOut();
+      } else {
+        this.pan(0, direction * PAN_STEP);
+      }
+      return true;
+    }
+    return false;
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // State
+  // ─────────────────────────────────────────────────────────────────────────
+
+  override getState(): ImageViewerState {
+    return { uri: this.uri, zoom: this.zoom, showInfo: this.showInfo };
+  }
+
+  override setState(state: unknown): void {
+    const s = state as Partial<ImageViewerState> | undefined;
+    if (s?.uri !== undefined) this.uri = s.uri;
+    if (s?.zoom !== undefined) this.zoom = s.zoom;
+    if (s?.showInfo !== undefined) this.showInfo = s.showInfo;
+    this.ctx.markDirty();
+  }
+}
+
+// ============================================
+// Half-Block Rendering
+// ============================================
+
+/**
+ * Draw pixels with '▀' cells: the foreground is the upper pixel and the
+ * background the lower one, so each cell shows two pixels. Translucent
+ * pixels are blended with `background`.
+ */
+export function renderHalfBlocks(
+  buffer: ScreenBuffer,
+  pixels: PixelBuffer,
+  x: number,
+  y: number,
+  background: string
+): void {
+  const bg = hexToRgb(background) ?? { r: 0, g: 0, b: 0 };
+  const colorAt = (px: number, py: number): string => {
+    if (py >= pixels.height) return background;
+    const i = (py * pixels.width + px) * 4;
+    const alpha = pixels.data[i + 3]! / 255;
+    if (alpha === 0) return background;
+    return toHex(
+      pixels.data[i]! * alpha + bg.r * (1 - alpha),
+      pixels.data[i + 1]! * alpha + bg.g * (1 - alpha),
+      pixels.data[i + 2]! * alpha + bg.b * (1 - alpha)
+    );
+  };
+
+  for (let row = 0; row * 2 < pixels.height; row++) {
+    for (let col = 0; col < pixels.width; col++) {
+      const top = colorAt(col, row * 2);
+      const bottom = colorAt(col, row * 2 + 1);
+      if (top === bottom) {
+        buffer.set(x + col, y + row, { char: ' ', fg: top, bg: bottom });
+      } else {
+        buffer.set(x + col, y + row, { char: '▀', fg: top, bg: bottom });
+      }
+    }
+  }
+}
+
+function toHex(r: number, g: number, b: number): string {
+  const hex = (v: number) => Math.max(0, Math.min(255, Math.round(v))).toString(16).padStart(2, '0');
+  return `#${hex(r)}${hex(g)}${hex(b)}`;
+}
+
+function formatBytes(bytes: number): string {
+  if (bytes < 1024) return `${bytes} B`;
+  if (bytes < 1024 * 1024) return `${(bytes / 1024).toFixed(1)} KB`;
+  return `${(bytes / (1024 * 1024)).toFixed(1)} MB`;
+}
+
+function truncate(text: string, width: number): string {
+  if (width <= 0) return '';
+  return text.length > width ? text.slice(0, Math.max(0, width - 1)) + '…' : text;
+}
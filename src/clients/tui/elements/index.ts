@@ -77,13 +77,18 @@ export {
   ClaudeTerminalChat,
   CodexTerminalChat,
   GeminiTerminalChat,
+  CustomTerminalChat,
   createAITerminalChat,
   createClaudeTerminalChat,
   createCodexTerminalChat,
   createGeminiTerminalChat,
+  createCustomTerminalChat,
+  expandProviderTemplate,
+  findCustomAIProvider,
   type AIProvider,
   type AITerminalChatState,
   type AITerminalChatCallbacks,
+  type CustomAIProviderConfig,
 } from './ai-terminal-chat.ts';
 
 export {
@@ -148,6 +153,42 @@ export {
   type PrimaryKeyDef,
 } from './row-details-panel.ts';
 
+export {
+  LSPTracePanel,
+  createLSPTracePanel,
+  type LSPTracePanelCallbacks,
+  type LSPTracePanelState,
+} from './lsp-trace-panel.ts';
+
+export {
+  LSPServerNotFoundPanel,
+  createLSPServerNotFoundPanel,
+  type LSPServerNotFoundPanelCallbacks,
+  type LSPServerNotFoundPanelState,
+} from './lsp-server-not-found-panel.ts';
+
+export {
+  TodoPanel,
+  createTodoPanel,
+  type TodoPanelCallbacks,
+  type TodoPanelState,
+} from './todo-panel.ts';
+
+export {
+  DependencyGraphViewer,
+  createDependencyGraphViewer,
+  type DependencyDirection,
+  type DependencyFilter,
+  type DependencyGraphViewerCallbacks,
+  type DependencyGraphViewerState,
+} from './dependency-graph.ts';
+
+export {
+  ImageViewer,
+  renderHalfBlocks,
+  type ImageViewerState,
+} from './image-viewer.ts';
+
 // ============================================
 // Element Registration
 // ============================================
@@ -166,6 +207,11 @@ import { GitTimelinePanel } from './git-timeline-panel.ts';
 import { SQLEditor } from './sql-editor.ts';
 import { QueryResults } from './query-results.ts';
 import { RowDetailsPanel } from './row-details-panel.ts';
+import { LSPTracePanel } from './lsp-trace-panel.ts';
+import { LSPServerNotFoundPanel } from './lsp-server-not-found-panel.ts';
+import { ImageViewer } from './image-viewer.ts';
+import { TodoPanel } from './todo-panel.ts';
+import { DependencyGraphViewer } from './dependency-graph.ts';
 
 /**
  * Register all built-in elements with the factory.
@@ -206,6 +252,7 @@ export function registerBuiltinElements(): void {
       provider: aiState?.provider,
       sessionId: aiState?.sessionId,
       cwd: aiState?.cwd,
+      customProviderId: aiState?.customProviderId,
     });
     return chat;
   });
@@ -257,4 +304,43 @@ export function registerBuiltinElements(): void {
     }
     return panel;
   });
+
+  registerElement('LSPTrace', (id, title, ctx, state) => {
+    const panel = new LSPTracePanel(id, title, ctx);
+    if (state && typeof state === 'object') {
+      panel.setState(state as import('./lsp-trace-panel.ts').LSPTracePanelState);
+    }
+    return panel;
+  });
+
+  registerElement('LSPServerNotFound', (id, title, ctx, state) => {
+    const panel = new LSPServerNotFoundPanel(id, title, ctx);
+    if (state && typeof state === 'object') {
+      panel.setState(state as import('./lsp-server-not-found-panel.ts').LSPServerNotFoundPanelState);
+    }
+    return panel;
+  });
+  registerElement('ImageViewer', (id, title, ctx, state) => {
+    const viewer = new ImageViewer(id, title, ctx);
+    if (state && typeof state === 'object') {
+      viewer.setState(state as import('./image-viewer.ts').ImageViewerState);
+    }
+    return viewer;
+  });
+
+  registerElement('TodoPanel', (id, title, ctx, state) => {
+    const panel = new TodoPanel(id, title, ctx);
+    if (state && typeof state === 'object') {
+      panel.setState(state as import('./todo-panel.ts').TodoPanelState);
+    }
+    return panel;
+  });
+
+  registerElement('DependencyGraph', (id, title, ctx, state) => {
+    const viewer = new DependencyGraphViewer(id, title, ctx);
+    if (state && typeof state === 'object') {
+      viewer.setState(state as import('./dependency-graph.ts').DependencyGraphViewerState);
+    }
+    return viewer;
+  });
 }
@@ -0,0 +1,276 @@
+/**
+ * LSP Server Not Found Panel
+ *
+ * Explains why a language server couldn't be started: where its command
+ * was looked for (project node_modules, go tool, toolchain and editor PATH,
+ * Go bin directories, common install locations), which version managers
+ * were detected, and how to install it.
+ *
+ * Keys:
+ * - Up/Down, j/k: scroll
+ * - r: look for the server again and restart it
+ */
+
+import { BaseElement, type ElementContext } from './base.ts';
+import type { KeyEvent, MouseEvent } from '../types.ts';
+import type { ScreenBuffer } from '../rendering/buffer.ts';
+import type {
+  ServerCandidateSource,
+  ServerDiscoveryResult,
+} from '../../../services/lsp/types.ts';
+
+// ============================================
+// Types
+// ============================================
+
+/**
+ * Callbacks for the panel.
+ */
+export interface LSPServerNotFoundPanelCallbacks {
+  /** Called when the user asks to look for the server again */
+  onRetry?: (languageId: string) => void;
+}
+
+/**
+ * Panel state for serialization.
+ */
+export interface LSPServerNotFoundPanelState {
+  languageId: string;
+  discovery: ServerDiscoveryResult | null;
+}
+
+/**
+ * A rendered line.
+ */
+interface PanelLine {
+  text: string;
+  style: 'normal' | 'dim' | 'found' | 'missing' | 'heading';
+}
+
+const SOURCE_LABELS: Record<ServerCandidateSource, string> = {
+  configured: 'configured',
+  project: 'node_modules/.bin',
+  goTool: 'go tool',
+  toolchain: 'toolchain PATH',
+  path: 'PATH',
+  goBin: 'GOBIN/GOPATH',
+  common: 'common',
+};
+
+// ============================================
+// LSP Server Not Found Panel Element
+// ============================================
+
+export class LSPServerNotFoundPanel extends BaseElement {
+  private languageId: string;
+  private discovery: ServerDiscoveryResult | null = null;
+  private callbacks: LSPServerNotFoundPanelCallbacks;
+
+  private lines: PanelLine[] = [];
+  private scrollTop = 0;
+
+  constructor(
+    id: string,
+    title: string,
+    ctx: ElementContext,
+    languageId = '',
+    callbacks: LSPServerNotFoundPanelCallbacks = {}
+  ) {
+    super('LSPServerNotFound', id, title || `Server Not Found: ${languageId}`, ctx);
+    this.languageId = languageId;
+    this.callbacks = callbacks;
+  }
+
+  setCallbacks(callbacks: LSPServerNotFoundPanelCallbacks): void {
+    this.callbacks = { ...this.callbacks, ...callbacks };
+  }
+
+  getLanguageId(): string {
+    return this.languageId;
+  }
+
+  /**
+   * Show a discovery result.
+   */
+  setDiscovery(discovery: ServerDiscoveryResult): void {
+    this.discovery = discovery;
+    this.rebuildLines();
+    this.ctx.markDirty();
+  }
+
+  getDiscovery(): ServerDiscoveryResult | null {
+    return this.discovery;
+  }
+
+  /**
+   * Get the display lines (for tests and copying).
+   */
+  getLines(): string[] {
+    return this.lines.map((line) => line.text);
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Layout
+  // ─────────────────────────────────────────────────────────────────────────
+
+  private rebuildLines(): void {
+    const d = this.discovery;
+    this.lines = [];
+    if (!d) return;
+
+    const push = (text: string, style: PanelLine['style'] = 'normal') => this.lines.push({ text, style });
+
+    if (d.resolved) {
+      push(`Found ${d.command}: ${[d.resolved.command, ...d.resolved.args].join(' ')}`, 'found');
+    } else {
+      push(`${d.command} was not found for ${this.languageId}`, 'missing');
+    }
+    push(`Searched from ${d.searchDir}`, 'dim');
+    push(`Toolchains: ${d.toolchains.length > 0 ? d.toolchains.join(', ') : 'none detected'}`, 'dim');
+    push('');
+
+    push('Checked', 'heading');
+    const labelWidth = Math.max(...Object.values(SOURCE_LABELS).map((label) => label.length));
+    for (const candidate of d.candidates) {
+      const mark = candidate.found ? '✓' : '✗';
+      push(`  ${mark} ${SOURCE_LABELS[candidate.source].padEnd(labelWidth)}  ${candidate.path}`, candidate.found ? 'found' : 'missing');
+    }
+
+    if (!d.resolved && d.installHint) {
+      push('');
+      push('Install', 'heading');
+      push(`  ${d.installHint}`);
+    }
+
+    push('');
+    push('r: look again and restart the server', 'dim');
+  }
+
+  private getViewportHeight(): number {
+    return Math.max(1, this.bounds.height - 1);
+  }
+
+  private scroll(delta: number): void {
+    const maxScroll = Math.max(0, this.lines.length - this.getViewportHeight());
+    this.scrollTop = Math.max(0, Math.min(this.scrollTop + delta, maxScroll));
+    this.ctx.markDirty();
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Rendering
+  // ─────────────────────────────────────────────────────────────────────────
+
+  render(buffer: ScreenBuffer): void {
+    const { x, y, width, height } = this.bounds;
+    if (height === 0 || width === 0) return;
+
+    const bg = this.ctx.getBackgroundForFocus('panel', this.focused);
+    const fg = this.ctx.getForegroundForFocus('panel', this.focused);
+    const headerBg = this.ctx.getThemeColor('sideBarSectionHeader.background', '#383838');
+    const headerFg = this.ctx.getThemeColor('sideBarSectionHeader.foreground', '#cccccc');
+    const dimFg = this.ctx.getThemeColor('descriptionForeground', '#888888');
+    const errorFg = this.ctx.getThemeColor('editorError.foreground', '#f44747');
+    const foundFg = this.ctx.getThemeColor('gitDecoration.addedResourceForeground', '#81b88b');
+
+    // Header
+    const header = ` ${this.languageId} · language server`;
+    buffer.writeString(x, y, truncate(header, width).padEnd(width, ' '), headerFg, headerBg);
+
+    const styles: Record<PanelLine['style'], string> = {
+      normal: fg,
+      dim: dimFg,
+      found: foundFg,
+      missing: errorFg,
+      heading: headerFg,
+    };
+
+    for (let row = 0; row < this.getViewportHeight() && row < height - 1; row++) {
+      const line = this.lines[this.scrollTop + row];
+      const text = line ? truncate(` ${line.text}`, width) : '';
+      buffer.writeString(x, y + 1 + row, text.padEnd(width, ' '), line ? styles[line.style] : fg, bg);
+    }
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Input Handling
+  // ─────────────────────────────────────────────────────────────────────────
+
+  override handleKey(event: KeyEvent): boolean {
+    if (event.ctrl || event.alt || event.meta) return false;
+
+    switch (event.key) {
+      case 'ArrowUp':
+      case 'k':
+        this.scroll(-1);
+        return true;
+      case 'ArrowDown':
+      case 'j':
+        this.scroll(1);
+        return true;
+      case 'PageUp':
+        this.scroll(-this.getViewportHeight());
+        return true;
+      case 'PageDown':
+        this.scroll(this.getViewportHeight());
+        return true;
+      case 'r':
+        this.callbacks.onRetry?.(this.languageId);
+        return true;
+    }
+
+    return false;
+  }
+
+  override handleMouse(event: MouseEvent): boolean {
+    if (event.type === 'scroll') {
+      this.scroll((event.scrollDirection ?? 1) * 3);
+      return true;
+    }
+
+    if (event.type === 'press' && event.button === 'left') {
+      this.ctx.requestFocus();
+      return true;
+    }
+
+    return false;
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // State Serialization
+  // ─────────────────────────────────────────────────────────────────────────
+
+  override getState(): LSPServerNotFoundPanelState {
+    return { languageId: this.languageId, discovery: this.discovery };
+  }
+
+  override setState(state: unknown): void {
+    const s = state as Partial<LSPServerNotFoundPanelState> | undefined;
+    if (s?.languageId) {
+      this.languageId = s.languageId;
+      this.setTitle(`Server Not Found: ${s.languageId}`);
+    }
+    if (s?.discovery) this.setDiscovery(s.discovery);
+    this.ctx.markDirty();
+  }
+}
+
+function truncate(text: string, width: number): string {
+  return text.length > width ? text.slice(0, Math.max(0, width - 1)) + '…' : text;
+}
+
+// ============================================
+// Factory Function
+// ============================================
+
+/**
+ * Create an LSP server not found panel element.
+ */
+export function createLSPServerNotFoundPanel(
+  id: string,
+  title: string,
+  ctx: ElementContext,
+  languageId: string,
+  callbacks?: LSPServerNotFoundPanelCallbacks
+): LSPServerNotFoundPanel {
+  return new LSPServerNotFoundPanel(id, title, ctx, languageId, callbacks);
+}
@@ -0,0 +1,386 @@
+/**
+ * LSP Trace Panel
+ *
+ * Shows JSON-RPC traffic, log messages and stderr for one language server,
+ * with round-trip timing for requests. Useful for diagnosing slow or
+ * misbehaving servers.
+ *
+ * Keys:
+ * - Up/Down, j/k: move selection
+ * - PageUp/PageDown, Home/End: jump (End resumes following new entries)
+ * - Enter: expand/collapse the selected entry's payload
+ * - f: toggle follow mode
+ * - c: clear the trace
+ */
+
+import { BaseElement, type ElementContext } from './base.ts';
+import type { KeyEvent, MouseEvent } from '../types.ts';
+import type { ScreenBuffer } from '../rendering/buffer.ts';
+import { formatTraceEntry, type LSPTraceEntry } from '../../../services/lsp/trace.ts';
+
+// ============================================
+// Types
+// ============================================
+
+/**
+ * Callbacks for the trace panel.
+ */
+export interface LSPTracePanelCallbacks {
+  /** Called when the user clears the trace */
+  onClear?: (languageId: string) => void;
+  /** Called when the panel is removed (to stop live updates) */
+  onClose?: () => void;
+}
+
+/**
+ * Trace panel state for serialization.
+ */
+export interface LSPTracePanelState {
+  languageId: string;
+  follow: boolean;
+}
+
+/**
+ * A rendered row: an entry line or one line of an expanded payload.
+ */
+interface TraceRow {
+  entryIndex: number;
+  text: string;
+  isPayload: boolean;
+}
+
+/** Responses slower than this are highlighted */
+const SLOW_RESPONSE_MS = 1000;
+
+// ============================================
+// LSP Trace Panel Element
+// ============================================
+
+export class LSPTracePanel extends BaseElement {
+  private languageId: string;
+  private entries: LSPTraceEntry[] = [];
+  private callbacks: LSPTracePanelCallbacks;
+
+  /** Expanded entries by sequence number */
+  private expanded = new Set<number>();
+
+  private rows: TraceRow[] = [];
+  private selectedIndex = 0;
+  private scrollTop = 0;
+
+  /** Keep the newest entry in view as entries arrive */
+  private follow = true;
+
+  constructor(
+    id: string,
+    title: string,
+    ctx: ElementContext,
+    languageId = '',
+    callbacks: LSPTracePanelCallbacks = {}
+  ) {
+    super('LSPTrace', id, title || `LSP Trace: ${languageId}`, ctx);
+    this.languageId = languageId;
+    this.callbacks = callbacks;
+  }
+
+  setCallbacks(callbacks: LSPTracePanelCallbacks): void {
+    this.callbacks = { ...this.callbacks, ...callbacks };
+  }
+
+  getLanguageId(): string {
+    return this.languageId;
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Entries
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /**
+   * Replace all entries.
+   */
+  setEntries(entries: LSPTraceEntry[]): void {
+    this.entries = [...entries];
+    this.expanded.clear();
+    this.rebuildRows();
+    if (this.follow) this.selectLast();
+    this.ctx.markDirty();
+  }
+
+  /**
+   * Append a new entry.
+   */
+  appendEntry(entry: LSPTraceEntry): void {
+    this.entries.push(entry);
+    this.rebuildRows();
+    if (this.follow) this.selectLast();
+    this.ctx.markDirty();
+  }
+
+  getEntries(): LSPTraceEntry[] {
+    return [...this.entries];
+  }
+
+  /**
+   * Clear the trace (also asks the owner to clear the server's log).
+   */
+  clear(): void {
+    this.entries = [];
+    this.expanded.clear();
+    this.rebuildRows();
+    this.selectedIndex = 0;
+    this.scrollTop = 0;
+    this.callbacks.onClear?.(this.languageId);
+    this.ctx.markDirty();
+  }
+
+  isFollowing(): boolean {
+    return this.follow;
+  }
+
+  setFollow(follow: boolean): void {
+    this.follow = follow;
+    if (follow) this.selectLast();
+    this.ctx.markDirty();
+  }
+
+  /**
+   * Toggle payload expansion for the selected entry.
+   */
+  toggleExpanded(): void {
+    const row = this.rows[this.selectedIndex];
+    const entry = row ? this.entries[row.entryIndex] : undefined;
+    if (!entry?.payload) return;
+
+    if (this.expanded.has(entry.seq)) {
+      this.expanded.delete(entry.seq);
+    } else {
+      this.expanded.add(entry.seq);
+    }
+    this.rebuildRows();
+    this.selectedIndex = this.rows.findIndex((r) => r.entryIndex === row!.entryIndex && !r.isPayload);
+    this.ensureVisible();
+    this.ctx.markDirty();
+  }
+
+  /**
+   * Get the display lines (for tests and copying).
+   */
+  getLines(): string[] {
+    return this.rows.map((r) => r.text);
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Layout
+  // ─────────────────────────────────────────────────────────────────────────
+
+  private rebuildRows(): void {
+    const width = Math.max(20, this.bounds.width - 4);
+    this.rows = [];
+    this.entries.forEach((entry, entryIndex) => {
+      this.rows.push({ entryIndex, text: formatTraceEntry(entry), isPayload: false });
+      if (entry.payload && this.expanded.has(entry.seq)) {
+        for (let i = 0; i < entry.payload.length; i += width) {
+          this.rows.push({ entryIndex, text: entry.payload.slice(i, i + width), isPayload: true });
+        }
+      }
+    });
+    this.selectedIndex = Math.min(this.selectedIndex, Math.max(0, this.rows.length - 1));
+  }
+
+  private getViewportHeight(): number {
+    return Math.max(1, this.bounds.height - 1);
+  }
+
+  private selectLast(): void {
+    this.selectedIndex = Math.max(0, this.rows.length - 1);
+    this.ensureVisible();
+  }
+
+  private ensureVisible(): void {
+    const viewportHeight = this.getViewportHeight();
+    if (this.selectedIndex < this.scrollTop) {
+      this.scrollTop = this.selectedIndex;
+    } else if (this.selectedIndex >= this.scrollTop + viewportHeight) {
+      this.scrollTop = this.selectedIndex - viewportHeight + 1;
+    }
+  }
+
+  private moveSelection(delta: number): void {
+    if (this.rows.length === 0) return;
+    this.selectedIndex = Math.max(0, Math.min(this.rows.length - 1, this.selectedIndex + delta));
+    // Moving away from the bottom stops following; reaching it resumes
+    this.follow = this.selectedIndex === this.rows.length - 1;
+    this.ensureVisible();
+    this.ctx.markDirty();
+  }
+
+  override onUnmount(): void {
+    super.onUnmount();
+    this.callbacks.onClose?.();
+  }
+
+  override onResize(size: { width: number; height: number }): void {
+    super.onResize(size);
+    this.rebuildRows();
+    this.ensureVisible();
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Rendering
+  // ─────────────────────────────────────────────────────────────────────────
+
+  render(buffer: ScreenBuffer): void {
+    const { x, y, width, height } = this.bounds;
+    if (height === 0 || width === 0) return;
+
+    const bg = this.ctx.getBackgroundForFocus('panel', this.focused);
+    const fg = this.ctx.getForegroundForFocus('panel', this.focused);
+    const headerBg = this.ctx.getThemeColor('sideBarSectionHeader.background', '#383838');
+    const headerFg = this.ctx.getThemeColor('sideBarSectionHeader.foreground', '#cccccc');
+    const selectedBg = this.ctx.getSelectionBackground('panel', this.focused);
+    const dimFg = this.ctx.getThemeColor('descriptionForeground', '#888888');
+    const errorFg = this.ctx.getThemeColor('editorError.foreground', '#f44747');
+    const warningFg = this.ctx.getThemeColor('editorWarning.foreground', '#cca700');
+
+    // Header
+    const follow = this.follow ? ' [follow]' : '';
+    const header = ` ${this.languageId} · ${this.entries.length} entries${follow}`;
+    buffer.writeString(x, y, truncate(header, width).padEnd(width, ' '), headerFg, headerBg);
+
+    const viewportHeight = this.getViewportHeight();
+    for (let row = 0; row < viewportHeight && row < height - 1; row++) {
+      const screenY = y + 1 + row;
+      const rowIndex = this.scrollTop + row;
+      const traceRow = this.rows[rowIndex];
+
+      if (!traceRow) {
+        buffer.writeString(x, screenY, ' '.repeat(width), fg, bg);
+        continue;
+      }
+
+      const entry = this.entries[traceRow.entryIndex]!;
+      const rowBg = rowIndex === this.selectedIndex ? selectedBg : bg;
+      let rowFg = fg;
+      if (traceRow.isPayload) {
+        rowFg = dimFg;
+      } else if (entry.isError || entry.logType === 1) {
+        rowFg = errorFg;
+      } else if (entry.logType === 2 || (entry.durationMs ?? 0) >= SLOW_RESPONSE_MS) {
+        rowFg = warningFg;
+      } else if (entry.direction === 'send' || entry.kind === 'stderr') {
+        rowFg = dimFg;
+      }
+
+      const marker = traceRow.isPayload
+        ? '   '
+        : entry.payload ? (this.expanded.has(entry.seq) ? ' ▾ ' : ' ▸ ') : '   ';
+      const text = truncate(`${marker}${traceRow.text}`, width);
+      buffer.writeString(x, screenY, text.padEnd(width, ' '), rowFg, rowBg);
+    }
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Input Handling
+  // ─────────────────────────────────────────────────────────────────────────
+
+  override handleKey(event: KeyEvent): boolean {
+    if (event.ctrl || event.alt || event.meta) return false;
+
+    switch (event.key) {
+      case 'ArrowUp':
+      case 'k':
+        this.moveSelection(-1);
+        return true;
+      case 'ArrowDown':
+      case 'j':
+        this.moveSelection(1);
+        return true;
+      case 'PageUp':
+        this.moveSelection(-this.getViewportHeight());
+        return true;
+      case 'PageDown':
+        this.moveSelection(this.getViewportHeight());
+        return true;
+      case 'Home':
+        this.moveSelection(-this.rows.length);
+        return true;
+      case 'End':
+        this.moveSelection(this.rows.length);
+        return true;
+      case 'Enter':
+        this.toggleExpanded();
+        return true;
+      case 'f':
+        this.setFollow(!this.follow);
+        return true;
+      case 'c':
+        this.clear();
+        return true;
+    }
+
+    return false;
+  }
+
+  override handleMouse(event: MouseEvent): boolean {
+    if (event.type === 'scroll') {
+      const delta = (event.scrollDirection ?? 1) * 3;
+      const maxScroll = Math.max(0, this.rows.length - this.getViewportHeight());
+      this.scrollTop = Math.max(0, Math.min(this.scrollTop + delta, maxScroll));
+      this.follow = this.scrollTop === maxScroll && this.selectedIndex === this.rows.length - 1;
+      this.ctx.markDirty();
+      return true;
+    }
+
+    if (event.type === 'press' && event.button === 'left') {
+      this.ctx.requestFocus();
+      const rowIndex = this.scrollTop + (event.y - this.bounds.y - 1);
+      if (rowIndex >= 0 && rowIndex < this.rows.length) {
+        this.selectedIndex = rowIndex;
+        this.follow = rowIndex === this.rows.length - 1;
+        this.ctx.markDirty();
+      }
+      return true;
+    }
+
+    return false;
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // State Serialization
+  // ─────────────────────────────────────────────────────────────────────────
+
+  override getState(): LSPTracePanelState {
+    return { languageId: this.languageId, follow: this.follow };
+  }
+
+  override setState(state: unknown): void {
+    const s = state as Partial<LSPTracePanelState> | undefined;
+    if (s?.languageId) {
+      this.languageId = s.languageId;
+      this.setTitle(`LSP Trace: ${s.languageId}`);
+    }
+    if (s?.follow !== undefined) this.follow = s.follow;
+    this.ctx.markDirty();
+  }
+}
+
+function truncate(text: string, width: number): string {
+  return text.length > width ? text.slice(0, Math.max(0, width - 1)) + '…' : text;
+}
+
+// ============================================
+// Factory Function
+// ============================================
+
+/**
+ * Create an LSP trace panel element.
+ */
+export function createLSPTracePanel(
+  id: string,
+  title: string,
+  ctx: ElementContext,
+  languageId: string,
+  callbacks?: LSPTracePanelCallbacks
+): LSPTracePanel {
+  return new LSPTracePanel(id, title, ctx, languageId, callbacks);
+}
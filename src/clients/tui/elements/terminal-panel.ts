@@ -6,7 +6,7 @@
  */
 
 import { BaseElement, type ElementContext } from './base.ts';
-import { TerminalSession, createTerminalSession } from './terminal-session.ts';
+import { TerminalSession, createTerminalSession, type TerminalSessionCallbacks } from './terminal-session.ts';
 import type { KeyEvent, MouseEvent } from '../types.ts';
 import type { ScreenBuffer } from '../rendering/buffer.ts';
 import type { PTYBackend } from '../../../terminal/pty-backend.ts';
@@ -64,10 +64,24 @@ export class TerminalPanel extends BaseElement {
     y: number
   ) => void;
 
+  /** Provides extra callbacks (watch alerts, notifications) for each session */
+  private sessionCallbacksProvider?: (session: TerminalSession) => TerminalSessionCallbacks;
+
   constructor(ctx: ElementContext) {
     super('TerminalPanel', 'terminal-panel', 'Terminal', ctx);
   }
 
+  /**
+   * Set a provider for additional session callbacks.
+   * Applied to existing sessions and to sessions created later.
+   */
+  setSessionCallbacksProvider(provider: (session: TerminalSession) => TerminalSessionCallbacks): void {
+    this.sessionCallbacksProvider = provider;
+    for (const tab of this.tabs) {
+      tab.session.setCallbacks(provider(tab.session));
+    }
+  }
+
   /**
    * Set callback for showing tab dropdown menu.
    */
@@ -103,6 +117,9 @@ export class TerminalPanel extends BaseElement {
         debugLog(`[TerminalPanel] Terminal ${id} exited with code ${code}`);
       },
     });
+    if (this.sessionCallbacksProvider) {
+      session.setCallbacks(this.sessionCallbacksProvider(session));
+    }
 
     // Create and attach PTY backend
     let pty: PTYBackend | null = null;
@@ -152,6 +169,7 @@ export class TerminalPanel extends BaseElement {
     if (tab.pty) {
       tab.pty.kill();
     }
+    tab.session.dispose();
 
     // Remove tab
     this.tabs.splice(index, 1);
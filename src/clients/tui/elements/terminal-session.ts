@@ -13,6 +13,12 @@ import type {
   TerminalCell,
   Unsubscribe,
 } from '../../../terminal/pty-backend.ts';
+import {
+  OutputWatcher,
+  type TerminalWatchRule,
+  type TerminalWatchMatch,
+  type CommandFinishedEvent,
+} from '../../../terminal/output-watcher.ts';
 
 // ============================================
 // Types
@@ -37,6 +43,12 @@ export interface TerminalSessionCallbacks {
   onTitleChange?: (title: string) => void;
   /** Called when terminal exits */
   onExit?: (code: number) => void;
+  /** Called when the running program sends an OSC 99 notification */
+  onNotification?: (message: string) => void;
+  /** Called when a watch rule matches a line of output */
+  onWatchMatch?: (match: TerminalWatchMatch) => void;
+  /** Called when an armed "notify when finished" watch fires */
+  onCommandFinished?: (event: CommandFinishedEvent) => void;
 }
 
 /**
@@ -45,6 +57,8 @@ export interface TerminalSessionCallbacks {
 export interface TerminalSessionState {
   cwd?: string;
   scrollTop: number;
+  /** Watch rules added to this terminal (excludes rules from settings) */
+  watchRules?: TerminalWatchRule[];
 }
 
 // ============================================
@@ -102,10 +116,34 @@ export class TerminalSession extends BaseElement {
   private pty: PTYBackend | null = null;
   private ptyUnsubscribes: Unsubscribe[] = [];
 
+  /** Output watcher for watch rules and command completion */
+  private watcher: OutputWatcher;
+
+  /** Rules from the tui.terminal.watchRules setting */
+  private settingsWatchRules: TerminalWatchRule[];
+
+  /** Rules added to this terminal only (persisted in session state) */
+  private sessionWatchRules: TerminalWatchRule[] = [];
+
   constructor(id: string, title: string, ctx: ElementContext, callbacks: TerminalSessionCallbacks = {}) {
     super('TerminalSession', id, title, ctx);
     this.callbacks = callbacks;
     this.initializeBuffer();
+
+    this.settingsWatchRules = ctx.getSetting<TerminalWatchRule[]>('tui.terminal.watchRules', []);
+    this.watcher = new OutputWatcher(this.settingsWatchRules, {
+      promptPattern: ctx.getSetting<string>('tui.terminal.promptPattern', ''),
+    });
+    this.watcher.onMatch((match) => {
+      if (match.rule.once) {
+        this.sessionWatchRules = this.sessionWatchRules.filter((r) => r.id !== match.rule.id);
+      }
+      this.callbacks.onWatchMatch?.(match);
+    });
+    this.watcher.onCommandFinished((event) => {
+      this.ctx.markDirty();
+      this.callbacks.onCommandFinished?.(event);
+    });
   }
 
   // ─────────────────────────────────────────────────────────────────────────
@@ -155,10 +193,23 @@ export class TerminalSession extends BaseElement {
       })
     );
 
+    this.ptyUnsubscribes.push(
+      pty.onData((data) => {
+        this.watcher.feed(data);
+      })
+    );
+
+    this.ptyUnsubscribes.push(
+      pty.onNotification((message) => {
+        this.callbacks.onNotification?.(message);
+      })
+    );
+
     this.ptyUnsubscribes.push(
       pty.onExit((code) => {
         this.exited = true;
         this.exitCode = code;
+        this.watcher.flush();
         this.callbacks.onExit?.(code);
         this.ctx.markDirty();
       })
@@ -657,6 +708,68 @@ export class TerminalSession extends BaseElement {
     this.alternateScreen = false;
   }
 
+  // ─────────────────────────────────────────────────────────────────────────
+  // Output Watching
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /**
+   * Add a watch rule to this terminal.
+   * @returns The rule ID, or null if the pattern is invalid
+   */
+  addWatchRule(rule: TerminalWatchRule): string | null {
+    const id = this.watcher.addRule(rule);
+    if (id) {
+      this.sessionWatchRules = this.sessionWatchRules.filter((r) => r.id !== id);
+      this.sessionWatchRules.push({ ...rule, id });
+    }
+    return id;
+  }
+
+  /**
+   * Remove a watch rule from this terminal.
+   */
+  removeWatchRule(id: string): boolean {
+    this.sessionWatchRules = this.sessionWatchRules.filter((r) => r.id !== id);
+    return this.watcher.removeRule(id);
+  }
+
+  /**
+   * Replace the rules added to this terminal (settings rules are kept).
+   */
+  setWatchRules(rules: TerminalWatchRule[]): void {
+    this.watcher.setRules(this.settingsWatchRules);
+    this.sessionWatchRules = [];
+    for (const rule of rules) {
+      this.addWatchRule(rule);
+    }
+  }
+
+  /**
+   * Get all active watch rules, including those from settings.
+   */
+  getWatchRules(): TerminalWatchRule[] {
+    return this.watcher.getRules();
+  }
+
+  /**
+   * Arm or cancel a one-shot notification for when the running command finishes.
+   */
+  setNotifyWhenFinished(enabled: boolean): void {
+    if (enabled) {
+      this.watcher.armCommandFinished();
+    } else {
+      this.watcher.disarmCommandFinished();
+    }
+    this.ctx.markDirty();
+  }
+
+  /**
+   * Check if a "notify when finished" watch is pending.
+   */
+  isNotifyWhenFinishedArmed(): boolean {
+    return this.watcher.isCommandFinishedArmed();
+  }
+
   // ─────────────────────────────────────────────────────────────────────────
   // State Management
   // ─────────────────────────────────────────────────────────────────────────
@@ -707,6 +820,12 @@ export class TerminalSession extends BaseElement {
   // Lifecycle
   // ─────────────────────────────────────────────────────────────────────────
 
+  override dispose(): void {
+    super.dispose();
+    this.watcher.dispose();
+    this.detachPty();
+  }
+
   override onResize(size: { width: number; height: number }): void {
     super.onResize(size);
 
@@ -761,6 +880,14 @@ export class TerminalSession extends BaseElement {
     // Render scrollbar
     this.renderScrollbar(buffer);
 
+    // Show pending "notify when finished" indicator in the top-right corner
+    if (this.watcher.isCommandFinishedArmed() && contentWidth > 12) {
+      const badge = ' ⏳ watching ';
+      const badgeFg = this.ctx.getThemeColor('statusBar.foreground', '#cccccc');
+      const badgeBg = this.ctx.getThemeColor('statusBar.background', '#007acc');
+      buffer.writeString(x + contentWidth - badge.length - 1, y, badge, badgeFg, badgeBg);
+    }
+
     // Show exit status if terminal has exited
     if (this.exited) {
       const msg = `[Process exited with code ${this.exitCode}]`;
@@ -1143,6 +1270,7 @@ export class TerminalSession extends BaseElement {
     return {
       cwd: this.cwd || undefined,
       scrollTop: this.scrollTop,
+      watchRules: this.sessionWatchRules.length > 0 ? [...this.sessionWatchRules] : undefined,
     };
   }
 
@@ -1154,6 +1282,9 @@ export class TerminalSession extends BaseElement {
     if (s.scrollTop !== undefined) {
       this.scrollTop = s.scrollTop;
     }
+    if (s.watchRules) {
+      this.setWatchRules(s.watchRules);
+    }
   }
 }
 
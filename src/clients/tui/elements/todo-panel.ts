@@ -0,0 +1,486 @@
+/**
+ * TODO Panel
+ *
+ * Lists comment tags (TODO, FIXME, HACK, XXX, ...) across the workspace,
+ * grouped by file, tag or blame author, optionally hiding tags younger
+ * than a minimum age taken from git blame.
+ *
+ * Keys:
+ * - Up/Down, j/k: move selection
+ * - PageUp/PageDown, Home/End: jump
+ * - Enter: open the tag in an editor, or toggle a group
+ * - Left/Right, h/l: collapse/expand the group
+ * - g: cycle grouping (file, tag, author)
+ * - a: cycle minimum age (any, 30, 90, 365 days)
+ * - r: rescan the workspace
+ */
+
+import { BaseElement, type ElementContext } from './base.ts';
+import type { KeyEvent, MouseEvent } from '../types.ts';
+import type { ScreenBuffer } from '../rendering/buffer.ts';
+import {
+  DEFAULT_COMMENT_TAGS,
+  applyBlame,
+  filterTagsByAge,
+  groupCommentTags,
+  tagAgeDays,
+  type CommentTag,
+  type CommentTagGrouping,
+} from '../../../services/search/comment-tags.ts';
+import type { GitBlame } from '../../../services/git/types.ts';
+
+// ============================================
+// Types
+// ============================================
+
+/**
+ * Callbacks for the TODO panel.
+ */
+export interface TodoPanelCallbacks {
+  /** Open a tag's location (path relative to workspace, 1-based line) */
+  onOpen?: (path: string, line: number, column: number) => void;
+  /** Rescan the workspace */
+  onRefresh?: () => void;
+  /** Load blame for files whose tags have no author yet */
+  onBlameNeeded?: (paths: string[]) => void;
+  /** The panel became visible (expanded or shown) */
+  onShow?: () => void;
+}
+
+/**
+ * TODO panel state for serialization.
+ */
+export interface TodoPanelState {
+  groupBy: CommentTagGrouping;
+  minAgeDays: number;
+  collapsedGroups: string[];
+}
+
+/**
+ * A rendered row: a group header or a tag.
+ */
+type TodoRow = { kind: 'group'; key: string; count: number } | { kind: 'tag'; tag: CommentTag };
+
+const GROUPINGS: CommentTagGrouping[] = ['file', 'tag', 'author'];
+
+/** Minimum ages cycled with `a` (0 = any) */
+const AGE_STEPS = [0, 30, 90, 365];
+
+/** Colors for the default tags */
+const TAG_COLORS: Record<string, string> = {
+  TODO: '#89b4fa',
+  FIXME: '#f38ba8',
+  HACK: '#fab387',
+  XXX: '#f9e2af',
+};
+
+// ============================================
+// TODO Panel Element
+// ============================================
+
+export class TodoPanel extends BaseElement {
+  private tags: CommentTag[] = [];
+  private tagOrder: string[] = DEFAULT_COMMENT_TAGS;
+  private callbacks: TodoPanelCallbacks;
+
+  private groupBy: CommentTagGrouping = 'file';
+  private minAgeDays = 0;
+  private collapsedGroups = new Set<string>();
+
+  /** Files blame was requested for since the last scan */
+  private blameRequested = new Set<string>();
+
+  private loading = false;
+  private rows: TodoRow[] = [];
+  private selectedIndex = 0;
+  private scrollTop = 0;
+
+  constructor(id: string, title: string, ctx: ElementContext, callbacks: TodoPanelCallbacks = {}) {
+    super('TodoPanel', id, title, ctx);
+    this.callbacks = callbacks;
+  }
+
+  setCallbacks(callbacks: TodoPanelCallbacks): void {
+    this.callbacks = { ...this.callbacks, ...callbacks };
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Tags
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /**
+   * Replace the indexed tags. `tagOrder` orders groups when grouping by tag.
+   */
+  setTags(tags: CommentTag[], tagOrder: string[] = this.tagOrder): void {
+    this.tags = tags;
+    this.tagOrder = tagOrder;
+    this.loading = false;
+    this.blameRequested.clear();
+    this.rebuildRows();
+    this.requestBlameIfNeeded();
+    this.ctx.markDirty();
+  }
+
+  /**
+   * Replace the tags of one file, keeping the rest of the index.
+   */
+  setFileTags(path: string, tags: CommentTag[]): void {
+    this.tags = [...this.tags.filter((tag) => tag.path !== path), ...tags];
+    this.blameRequested.delete(path);
+    this.rebuildRows();
+    this.requestBlameIfNeeded();
+    this.ctx.markDirty();
+  }
+
+  getTags(): CommentTag[] {
+    return this.tags;
+  }
+
+  setLoading(loading: boolean): void {
+    this.loading = loading;
+    this.ctx.markDirty();
+  }
+
+  /**
+   * Attach blame for one file.
+   */
+  setBlame(path: string, blame: readonly GitBlame[]): void {
+    applyBlame(this.tags, path, blame);
+    this.rebuildRows();
+    this.ctx.markDirty();
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Grouping and Filtering
+  // ─────────────────────────────────────────────────────────────────────────
+
+  getGroupBy(): CommentTagGrouping {
+    return this.groupBy;
+  }
+
+  setGroupBy(groupBy: CommentTagGrouping): void {
+    this.groupBy = groupBy;
+    this.collapsedGroups.clear();
+    this.selectedIndex = 0;
+    this.scrollTop = 0;
+    this.rebuildRows();
+    this.requestBlameIfNeeded();
+    this.ctx.markDirty();
+  }
+
+  cycleGroupBy(): void {
+    this.setGroupBy(GROUPINGS[(GROUPINGS.indexOf(this.groupBy) + 1) % GROUPINGS.length]!);
+  }
+
+  getMinAgeDays(): number {
+    return this.minAgeDays;
+  }
+
+  setMinAgeDays(days: number): void {
+    this.minAgeDays = Math.max(0, days);
+    this.rebuildRows();
+    this.requestBlameIfNeeded();
+    this.ctx.markDirty();
+  }
+
+  cycleMinAge(): void {
+    const index = AGE_STEPS.indexOf(this.minAgeDays);
+    this.setMinAgeDays(AGE_STEPS[(index + 1) % AGE_STEPS.length]!);
+  }
+
+  /**
+   * Ask for blame when the view depends on it.
+   */
+  private requestBlameIfNeeded(): void {
+    if (this.groupBy !== 'author' && this.minAgeDays === 0) return;
+    const paths = new Set<string>();
+    for (const tag of this.tags) {
+      if (!tag.date && !this.blameRequested.has(tag.path)) paths.add(tag.path);
+    }
+    if (paths.size === 0) return;
+    for (const path of paths) this.blameRequested.add(path);
+    this.callbacks.onBlameNeeded?.([...paths]);
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Rows
+  // ─────────────────────────────────────────────────────────────────────────
+
+  private rebuildRows(): void {
+    const visible = filterTagsByAge(this.tags, this.minAgeDays);
+    this.rows = [];
+    for (const group of groupCommentTags(visible, this.groupBy, this.tagOrder)) {
+      this.rows.push({ kind: 'group', key: group.key, count: group.tags.length });
+      if (this.collapsedGroups.has(group.key)) continue;
+      for (const tag of group.tags) this.rows.push({ kind: 'tag', tag });
+    }
+    this.selectedIndex = Math.min(this.selectedIndex, Math.max(0, this.rows.length - 1));
+
+    const count = visible.length;
+    this.setStatus(`${count} tag${count !== 1 ? 's' : ''}`);
+  }
+
+  /**
+   * Get the display lines (for tests).
+   */
+  getLines(): string[] {
+    return this.rows.map((row) => this.formatRow(row));
+  }
+
+  private formatRow(row: TodoRow): string {
+    if (row.kind === 'group') {
+      const arrow = this.collapsedGroups.has(row.key) ? '▸' : '▾';
+      return `${arrow} ${row.key} (${row.count})`;
+    }
+    const { tag } = row;
+    const location = this.groupBy === 'file' ? `${tag.line}` : `${tag.path.split('/').pop()}:${tag.line}`;
+    const age = tagAgeDays(tag);
+    const ageText = this.minAgeDays > 0 && age !== null ? ` · ${age}d` : '';
+    return `  ${tag.tag} ${location} ${tag.text}${ageText}`;
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Navigation
+  // ─────────────────────────────────────────────────────────────────────────
+
+  private getViewportHeight(): number {
+    return Math.max(1, this.bounds.height - 1);
+  }
+
+  private ensureVisible(): void {
+    const viewportHeight = this.getViewportHeight();
+    if (this.selectedIndex < this.scrollTop) {
+      this.scrollTop = this.selectedIndex;
+    } else if (this.selectedIndex >= this.scrollTop + viewportHeight) {
+      this.scrollTop = this.selectedIndex - viewportHeight + 1;
+    }
+  }
+
+  private moveSelection(delta: number): void {
+    if (this.rows.length === 0) return;
+    this.selectedIndex = Math.max(0, Math.min(this.rows.length - 1, this.selectedIndex + delta));
+    this.ensureVisible();
+    this.ctx.markDirty();
+  }
+
+  /**
+   * Key of the group containing the selected row.
+   */
+  private getSelectedGroupKey(): string | null {
+    for (let i = this.selectedIndex; i >= 0; i--) {
+      const row = this.rows[i];
+      if (row?.kind === 'group') return row.key;
+    }
+    return null;
+  }
+
+  private setGroupCollapsed(key: string, collapsed: boolean): void {
+    if (collapsed) this.collapsedGroups.add(key);
+    else this.collapsedGroups.delete(key);
+    this.rebuildRows();
+    this.selectedIndex = Math.max(0, this.rows.findIndex((r) => r.kind === 'group' && r.key === key));
+    this.ensureVisible();
+    this.ctx.markDirty();
+  }
+
+  /**
+   * Open the selected tag, or toggle the selected group.
+   */
+  activate(): void {
+    const row = this.rows[this.selectedIndex];
+    if (!row) return;
+    if (row.kind === 'group') {
+      this.setGroupCollapsed(row.key, !this.collapsedGroups.has(row.key));
+    } else {
+      this.callbacks.onOpen?.(row.tag.path, row.tag.line, row.tag.column);
+    }
+  }
+
+  /**
+   * Report becoming visible, so an index skipped while hidden can catch up.
+   */
+  override onVisibilityChange(visible: boolean): void {
+    const wasVisible = this.isVisible();
+    super.onVisibilityChange(visible);
+    if (visible && !wasVisible) {
+      this.callbacks.onShow?.();
+    }
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Rendering
+  // ─────────────────────────────────────────────────────────────────────────
+
+  render(buffer: ScreenBuffer): void {
+    const { x, y, width, height } = this.bounds;
+    if (height === 0 || width === 0) return;
+
+    const bg = this.ctx.getBackgroundForFocus('sidebar', this.focused);
+    const fg = this.ctx.getForegroundForFocus('sidebar', this.focused);
+    const selectedBg = this.ctx.getSelectionBackground('sidebar', this.focused);
+    const headerBg = this.ctx.getThemeColor('sideBarSectionHeader.background', '#383838');
+    const headerFg = this.ctx.getThemeColor('sideBarSectionHeader.foreground', '#cccccc');
+    const dimFg = this.ctx.getThemeColor('descriptionForeground', '#888888');
+    const warningFg = this.ctx.getThemeColor('editorWarning.foreground', '#cca700');
+
+    // Header: grouping and age filter
+    const age = this.minAgeDays > 0 ? ` · ≥${this.minAgeDays}d` : '';
+    const loading = this.loading ? ' · scanning…' : '';
+    const header = ` by ${this.groupBy}${age}${loading}`;
+    buffer.writeString(x, y, truncate(header, width).padEnd(width, ' '), headerFg, headerBg);
+
+    const viewportHeight = Math.min(this.getViewportHeight(), height - 1);
+    if (this.rows.length === 0) {
+      for (let row = 0; row < viewportHeight; row++) {
+        buffer.writeString(x, y + 1 + row, ' '.repeat(width), fg, bg);
+      }
+      const msg = this.loading ? 'Scanning…' : 'No tags found';
+      if (viewportHeight > 0) buffer.writeString(x + 1, y + 1, truncate(msg, width - 1), dimFg, bg);
+      return;
+    }
+
+    for (let row = 0; row < viewportHeight; row++) {
+      const screenY = y + 1 + row;
+      const rowIndex = this.scrollTop + row;
+      const todoRow = this.rows[rowIndex];
+      const rowBg = rowIndex === this.selectedIndex ? selectedBg : bg;
+      buffer.writeString(x, screenY, ' '.repeat(width), fg, rowBg);
+      if (!todoRow) continue;
+
+      const text = truncate(this.formatRow(todoRow), width);
+      if (todoRow.kind === 'group') {
+        buffer.writeString(x, screenY, text, fg, rowBg);
+        continue;
+      }
+
+      // Tag name in its color, rest in the normal color
+      const tagColor = TAG_COLORS[todoRow.tag.tag] ?? warningFg;
+      const tagEnd = 2 + todoRow.tag.tag.length;
+      buffer.writeString(x, screenY, text.slice(0, tagEnd), tagColor, rowBg);
+      buffer.writeString(x + tagEnd, screenY, text.slice(tagEnd), rowIndex === this.selectedIndex ? fg : dimFg, rowBg);
+    }
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Input Handling
+  // ─────────────────────────────────────────────────────────────────────────
+
+  override handleKey(event: KeyEvent): boolean {
+    if (event.ctrl || event.alt || event.meta) return false;
+
+    switch (event.key) {
+      case 'ArrowUp':
+      case 'k':
+        this.moveSelection(-1);
+        return true;
+      case 'ArrowDown':
+      case 'j':
+        this.moveSelection(1);
+        return true;
+      case 'PageUp':
+        this.moveSelection(-this.getViewportHeight());
+        return true;
+      case 'PageDown':
+        this.moveSelection(this.getViewportHeight());
+        return true;
+      case 'Home':
+        this.moveSelection(-this.rows.length);
+        return true;
+      case 'End':
+        this.moveSelection(this.rows.length);
+        return true;
+      case 'Enter':
+        this.activate();
+        return true;
+      case 'ArrowLeft':
+      case 'h': {
+        const key = this.getSelectedGroupKey();
+        if (key !== null) this.setGroupCollapsed(key, true);
+        return true;
+      }
+      case 'ArrowRight':
+      case 'l': {
+        const key = this.getSelectedGroupKey();
+        if (key !== null) this.setGroupCollapsed(key, false);
+        return true;
+      }
+      case 'g':
+        this.cycleGroupBy();
+        return true;
+      case 'a':
+        this.cycleMinAge();
+        return true;
+      case 'r':
+        this.callbacks.onRefresh?.();
+        return true;
+    }
+
+    return false;
+  }
+
+  override handleMouse(event: MouseEvent): boolean {
+    if (event.type === 'scroll') {
+      const delta = (event.scrollDirection ?? 1) * 3;
+      const maxScroll = Math.max(0, this.rows.length - this.getViewportHeight());
+      this.scrollTop = Math.max(0, Math.min(this.scrollTop + delta, maxScroll));
+      this.ctx.markDirty();
+      return true;
+    }
+
+    if (event.type === 'press' && event.button === 'left') {
+      this.ctx.requestFocus();
+      const rowIndex = this.scrollTop + (event.y - this.bounds.y - 1);
+      if (rowIndex >= 0 && rowIndex < this.rows.length) {
+        const wasSelected = rowIndex === this.selectedIndex;
+        this.selectedIndex = rowIndex;
+        // Click toggles groups; a second click on a tag opens it
+        if (this.rows[rowIndex]!.kind === 'group' || wasSelected) this.activate();
+        this.ctx.markDirty();
+      }
+      return true;
+    }
+
+    return false;
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // State Serialization
+  // ─────────────────────────────────────────────────────────────────────────
+
+  override getState(): TodoPanelState {
+    return {
+      groupBy: this.groupBy,
+      minAgeDays: this.minAgeDays,
+      collapsedGroups: [...this.collapsedGroups],
+    };
+  }
+
+  override setState(state: unknown): void {
+    const s = state as Partial<TodoPanelState> | undefined;
+    if (s?.groupBy && GROUPINGS.includes(s.groupBy)) this.groupBy = s.groupBy;
+    if (typeof s?.minAgeDays === 'number') this.minAgeDays = Math.max(0, s.minAgeDays);
+    if (Array.isArray(s?.collapsedGroups)) this.collapsedGroups = new Set(s.collapsedGroups);
+    this.rebuildRows();
+    this.ctx.markDirty();
+  }
+}
+
+function truncate(text: string, width: number): string {
+  return text.length > width ? text.slice(0, Math.max(0, width - 1)) + '…' : text;
+}
+
+// ============================================
+// Factory Function
+// ============================================
+
+/**
+ * Create a TODO panel element.
+ */
+export function createTodoPanel(
+  id: string,
+  title: string,
+  ctx: ElementContext,
+  callbacks?: TodoPanelCallbacks
+): TodoPanel {
+  return new TodoPanel(id, title, ctx, callbacks);
+}
@@ -0,0 +1,334 @@
+/**
+ * String Extraction
+ *
+ * Finds user-facing strings that bypass the message catalog. Sources are
+ * tokenized (comments, regular expressions and template expressions are
+ * skipped) and each string literal is checked: capitalized words and
+ * multi-word phrases are treated as UI text unless the line only uses the
+ * string for a comparison, logging, an import or an error for developers.
+ *
+ * The extraction test compares findings with `hardcoded-baseline.json`, so
+ * strings that predate the catalog are tolerated but new ones fail. Append
+ * `// i18n-ignore` to a line for text that must not be translated.
+ *
+ * List hardcoded strings with `bun run i18n:strings`; regenerate the
+ * baseline after moving strings into the catalog with `bun run i18n:baseline`.
+ */
+
+import * as fs from 'fs';
+import * as path from 'path';
+
+// ============================================
+// Types
+// ============================================
+
+/**
+ * A string literal found in source.
+ */
+export interface StringLiteral {
+  /** Literal text; template expressions are replaced by `{}` */
+  text: string;
+  /** 1-based line number */
+  line: number;
+}
+
+/**
+ * A hardcoded user-facing string.
+ */
+export interface HardcodedString extends StringLiteral {
+  /** Path relative to the repository root, with forward slashes */
+  file: string;
+}
+
+/**
+ * Hardcoded strings that predate the catalog, by file.
+ */
+export type HardcodedBaseline = Record<string, string[]>;
+
+// ============================================
+// Constants
+// ============================================
+
+/** Directories and files whose strings must go through the catalog */
+export const LOCALIZED_SOURCES = [
+  'src/clients/tui/overlays',
+  'src/clients/tui/status-bar',
+  'src/clients/tui/elements/git-panel.ts',
+];
+
+/** Baseline of existing hardcoded strings */
+export const BASELINE_PATH = 'src/clients/tui/i18n/hardcoded-baseline.json';
+
+/** Lines containing any of these use strings for code, not UI */
+const IGNORED_LINE = [
+  /\bimport\b.*\bfrom\b/,
+  /\bdebugLog\(/,
+  /\bconsole\.\w+\(/,
+  /\bthrow new \w*Error\(/,
+  /\bcase\s+['"`]/,
+  /[!=]==?\s*['"`]/,
+  /['"`]\s*[!=]==?/,
+  /\bgetThemeColor\(/,
+  /\/\/\s*i18n-ignore\b/,
+];
+
+// ============================================
+// Tokenizer
+// ============================================
+
+/** Characters after which `/` starts a regular expression */
+const REGEX_PRECEDERS = new Set(['', '(', ',', '=', ':', '[', '!', '&', '|', '?', '{', '}', ';', '+', '-', '*', '%', '<', '>', '~', '^']);
+
+/**
+ * Find all string literals in TypeScript source, skipping comments and
+ * regular expressions. Template literals are reported with `{}` in place of
+ * each `${…}`, and strings inside template expressions are reported too.
+ */
+export function findStringLiterals(source: string): StringLiteral[] {
+  const literals: StringLiteral[] = [];
+  let pos = 0;
+  let line = 1;
+  let lastSignificant = '';
+
+  const advance = (): string => {
+    const ch = source[pos++]!;
+    if (ch === '\n') line++;
+    return ch;
+  };
+
+  const readQuoted = (quote: string): void => {
+    const startLine = line;
+    let text = '';
+    while (pos < source.length) {
+      const ch = advance();
+      if (ch === '\\') {
+        text += advance();
+      } else if (ch === quote || ch === '\n') {
+        break;
+      } else {
+        text += ch;
+      }
+    }
+    literals.push({ text, line: startLine });
+  };
+
+  const readTemplate = (): void => {
+    const startLine = line;
+    let text = '';
+    while (pos < source.length) {
+      const ch = advance();
+      if (ch === '\\') {
+        text += advance();
+      } else if (ch === '`') {
+        break;
+      } else if (ch === '$' && source[pos] === '{') {
+        advance();
+        text += '{}';
+        scan(true);
+      } else {
+        text += ch;
+      }
+    }
+    literals.push({ text, line: startLine });
+  };
+
+  const skipRegex = (): void => {
+    let inClass = false;
+    while (pos < source.length) {
+      const ch = advance();
+      if (ch === '\\') {
+        advance();
+      } else if (ch === '[') {
+        inClass = true;
+      } else if (ch === ']') {
+        inClass = false;
+      } else if ((ch === '/' && !inClass) || ch === '\n') {
+        break;
+      }
+    }
+    while (pos < source.length && /[a-z]/.test(source[pos]!)) advance();
+  };
+
+  /** Scan code; inside a template expression, stop at its closing brace */
+  const scan = (inExpression: boolean): void => {
+    let depth = 0;
+    while (pos < source.length) {
+      const ch = source[pos]!;
+      const next = source[pos + 1];
+
+      if (ch === '/' && next === '/') {
+        while (pos < source.length && source[pos] !== '\n') advance();
+        continue;
+      }
+      if (ch === '/' && next === '*') {
+        advance();
+        advance();
+        while (pos < source.length && !(source[pos] === '*' && source[pos + 1] === '/')) advance();
+        advance();
+        advance();
+        continue;
+      }
+
+      advance();
+      if (/\s/.test(ch)) continue;
+
+      if (ch === "'" || ch === '"') {
+        readQuoted(ch);
+        lastSignificant = 'a';
+      } else if (ch === '`') {
+        readTemplate();
+        lastSignificant = 'a';
+      } else if (ch === '/' && REGEX_PRECEDERS.has(lastSignificant)) {
+        skipRegex();
+        lastSignificant = 'a';
+      } else {
+        if (ch === '{') depth++;
+        if (ch === '}') {
+          if (inExpression && depth === 0) return;
+          depth--;
+        }
+        lastSignificant = /\w/.test(ch) ? 'a' : ch;
+        // Keywords after which a regex may follow
+        if (/\w/.test(ch)) {
+          const word = /\w*$/.exec(source.slice(Math.max(0, pos - 12), pos))![0];
+          if (word === 'return' || word === 'typeof' || word === 'case') {
+            lastSignificant = '';
+          }
+        }
+      }
+    }
+  };
+
+  scan(false);
+  return literals;
+}
+
+// ============================================
+// Detection
+// ============================================
+
+/**
+ * Whether a literal reads as UI text: a capitalized word ("Cancel",
+ * "Loading…") or a phrase of two or more words ("no results", "type a command").
+ */
+export function isUserFacingText(text: string): boolean {
+  const words = text.replace(/\{\}/g, ' ').trim();
+  if (/^[A-Z][a-z]+[.:!?…]*$/.test(words)) return true;
+  const tokens = words.split(/\s+/).filter((token) => /[A-Za-z]{2,}/.test(token));
+  return tokens.length >= 2;
+}
+
+/**
+ * Find hardcoded user-facing strings in one source file.
+ */
+export function findHardcodedStrings(source: string, file: string): HardcodedString[] {
+  const lines = source.split('\n');
+  const found: HardcodedString[] = [];
+  for (const literal of findStringLiterals(source)) {
+    const lineText = lines[literal.line - 1] ?? '';
+    if (IGNORED_LINE.some((pattern) => pattern.test(lineText))) continue;
+    if (!isUserFacingText(literal.text)) continue;
+    found.push({ ...literal, file });
+  }
+  return found;
+}
+
+/**
+ * Message keys used via `t('key')` in a source file.
+ */
+export function findMessageKeys(source: string): string[] {
+  const keys: string[] = [];
+  const pattern = /\bt\(\s*['"]([\w.-]+)['"]/g;
+  let match: RegExpExecArray | null;
+  while ((match = pattern.exec(source)) !== null) {
+    keys.push(match[1]!);
+  }
+  return keys;
+}
+
+// ============================================
+// Files
+// ============================================
+
+/**
+ * List the TypeScript files under LOCALIZED_SOURCES.
+ */
+export function listLocalizedFiles(root: string): string[] {
+  const files: string[] = [];
+  const visit = (relative: string) => {
+    const absolute = path.join(root, relative);
+    if (!fs.existsSync(absolute)) return;
+    if (fs.statSync(absolute).isDirectory()) {
+      for (const entry of fs.readdirSync(absolute).sort()) {
+        visit(`${relative}/${entry}`);
+      }
+    } else if (relative.endsWith('.ts')) {
+      files.push(relative);
+    }
+  };
+  for (const source of LOCALIZED_SOURCES) visit(source);
+  return files;
+}
+
+/**
+ * Scan all localized files for hardcoded strings.
+ */
+export function scanHardcodedStrings(root: string): HardcodedString[] {
+  return listLocalizedFiles(root).flatMap((file) =>
+    findHardcodedStrings(fs.readFileSync(path.join(root, file), 'utf-8'), file)
+  );
+}
+
+/**
+ * Build a baseline from findings.
+ */
+export function createBaseline(found: HardcodedString[]): HardcodedBaseline {
+  const baseline: HardcodedBaseline = {};
+  for (const item of found) {
+    (baseline[item.file] ??= []).push(item.text);
+  }
+  for (const texts of Object.values(baseline)) texts.sort();
+  return baseline;
+}
+
+/**
+ * Findings not covered by the baseline. Each baseline entry covers one
+ * occurrence, so duplicating an existing string is also reported.
+ */
+export function diffAgainstBaseline(found: HardcodedString[], baseline: HardcodedBaseline): HardcodedString[] {
+  const remaining = new Map<string, string[]>();
+  for (const [file, texts] of Object.entries(baseline)) {
+    remaining.set(file, [...texts]);
+  }
+
+  const added: HardcodedString[] = [];
+  for (const item of found) {
+    const texts = remaining.get(item.file);
+    const index = texts?.indexOf(item.text) ?? -1;
+    if (index >= 0) {
+      texts!.splice(index, 1);
+    } else {
+      added.push(item);
+    }
+  }
+  return added;
+}
+
+// ============================================
+// CLI
+// ============================================
+
+if (import.meta.main) {
+  const root = path.resolve(import.meta.dir, '../../../..');
+  const found = scanHardcodedStrings(root);
+
+  if (process.argv.includes('--update-baseline')) {
+    fs.writeFileSync(path.join(root, BASELINE_PATH), JSON.stringify(createBaseline(found), null, 2) + '\n');
+    console.log(`Wrote ${found.length} baseline strings to ${BASELINE_PATH}`);
+  } else {
+    for (const item of found) {
+      console.log(`${item.file}:${item.line}  ${JSON.stringify(item.text)}`);
+    }
+    console.log(`${found.length} hardcoded strings`);
+  }
+}
@@ -0,0 +1,329 @@
+/**
+ * Message Format
+ *
+ * The subset of ICU MessageFormat used by the message catalogs:
+ *
+ *   {name}                                       argument, inserted as is
+ *   {count, number}                              number in the locale's format
+ *   {count, plural, =0 {none} one {# file} other {# files}}
+ *   {state, select, staged {Staged} other {Changed}}
+ *
+ * `#` inside a plural branch is the number. Plural categories come from
+ * Intl.PluralRules, so Japanese only needs `other` while German uses
+ * `one`/`other`. Two apostrophes are a literal apostrophe; an apostrophe
+ * before `{`, `}` or `#` quotes text up to the next apostrophe.
+ */
+
+// ============================================
+// Types
+// ============================================
+
+/**
+ * Values substituted into a message.
+ */
+export type MessageParams = Record<string, string | number>;
+
+/**
+ * A parsed message part.
+ */
+export type MessagePart =
+  | string
+  | { type: 'argument'; name: string; number: boolean }
+  | { type: 'plural'; name: string; offset: number; options: Map<string, MessagePart[]> }
+  | { type: 'select'; name: string; options: Map<string, MessagePart[]> }
+  | { type: 'pound' };
+
+/**
+ * Thrown for malformed messages.
+ */
+export class MessageFormatError extends Error {
+  constructor(message: string, source: string, position: number) {
+    super(`${message} at ${position} in "${source}"`);
+    this.name = 'MessageFormatError';
+  }
+}
+
+// ============================================
+// Parser
+// ============================================
+
+class MessageParser {
+  private source: string;
+  private pos = 0;
+
+  constructor(source: string) {
+    this.source = source;
+  }
+
+  parse(): MessagePart[] {
+    const parts = this.parseParts(false);
+    if (this.pos < this.source.length) {
+      this.fail('Unexpected "}"');
+    }
+    return parts;
+  }
+
+  /**
+   * Parse text and arguments up to an unmatched `}` or the end.
+   */
+  private parseParts(inPlural: boolean): MessagePart[] {
+    const parts: MessagePart[] = [];
+    let text = '';
+    const flush = () => {
+      if (text) parts.push(text);
+      text = '';
+    };
+
+    while (this.pos < this.source.length) {
+      const ch = this.source[this.pos]!;
+      if (ch === "'") {
+        text += this.parseQuoted();
+      } else if (ch === '{') {
+        flush();
+        parts.push(this.parseArgument(inPlural));
+      } else if (ch === '}') {
+        break;
+      } else if (ch === '#' && inPlural) {
+        flush();
+        parts.push({ type: 'pound' });
+        this.pos++;
+      } else {
+        text += ch;
+        this.pos++;
+      }
+    }
+
+    flush();
+    return parts;
+  }
+
+  private parseQuoted(): string {
+    const next = this.source[this.pos + 1];
+    if (next === "'") {
+      this.pos += 2;
+      return "'";
+    }
+    if (next !== '{' && next !== '}' && next !== '#') {
+      this.pos++;
+      return "'";
+    }
+
+    // Quoted literal: up to the next lone apostrophe
+    this.pos++;
+    let text = '';
+    while (this.pos < this.source.length) {
+      const ch = this.source[this.pos]!;
+      if (ch === "'") {
+        if (this.source[this.pos + 1] === "'") {
+          text += "'";
+          this.pos += 2;
+          continue;
+        }
+        this.pos++;
+        return text;
+      }
+      text += ch;
+      this.pos++;
+    }
+    return text;
+  }
+
+  private parseArgument(inPlural: boolean): MessagePart {
+    this.pos++; // {
+    const name = this.readWord();
+    if (!name) this.fail('Expected argument name');
+
+    this.skipSpace();
+    if (this.eat('}')) {
+      return { type: 'argument', name, number: false };
+    }
+    this.expect(',');
+
+    const kind = this.readWord();
+    this.skipSpace();
+
+    if (kind === 'number') {
+      this.expect('}');
+      return { type: 'argument', name, number: true };
+    }
+
+    if (kind !== 'plural' && kind !== 'select') {
+      this.fail(`Unknown argument type "${kind}"`);
+    }
+    this.expect(',');
+
+    let offset = 0;
+    this.skipSpace();
+    if (kind === 'plural' && this.source.startsWith('offset:', this.pos)) {
+      this.pos += 'offset:'.length;
+      offset = Number(this.readWord());
+      if (!Number.isFinite(offset)) this.fail('Invalid plural offset');
+    }
+
+    const options = new Map<string, MessagePart[]>();
+    while (true) {
+      this.skipSpace();
+      if (this.eat('}')) break;
+
+      let selector = this.readWord();
+      if (this.source[this.pos] === '=' && !selector) {
+        this.pos++;
+        selector = `=${this.readWord()}`;
+      }
+      if (!selector) this.fail('Expected selector');
+
+      this.skipSpace();
+      this.expect('{');
+      options.set(selector, this.parseParts(kind === 'plural' || inPlural));
+      this.expect('}');
+    }
+
+    if (!options.has('other')) {
+      this.fail(`Missing "other" in ${kind} for "${name}"`);
+    }
+
+    return kind === 'plural' ? { type: 'plural', name, offset, options } : { type: 'select', name, options };
+  }
+
+  private readWord(): string {
+    this.skipSpace();
+    const start = this.pos;
+    while (this.pos < this.source.length && /[\w-]/.test(this.source[this.pos]!)) {
+      this.pos++;
+    }
+    return this.source.slice(start, this.pos);
+  }
+
+  private skipSpace(): void {
+    while (this.pos < this.source.length && /\s/.test(this.source[this.pos]!)) {
+      this.pos++;
+    }
+  }
+
+  private eat(ch: string): boolean {
+    if (this.source[this.pos] === ch) {
+      this.pos++;
+      return true;
+    }
+    return false;
+  }
+
+  private expect(ch: string): void {
+    this.skipSpace();
+    if (!this.eat(ch)) this.fail(`Expected "${ch}"`);
+  }
+
+  private fail(message: string): never {
+    throw new MessageFormatError(message, this.source, this.pos);
+  }
+}
+
+/**
+ * Parse a message into parts. Throws MessageFormatError if malformed.
+ */
+export function parseMessage(source: string): MessagePart[] {
+  return new MessageParser(source).parse();
+}
+
+// ============================================
+// Formatting
+// ============================================
+
+const pluralRulesCache = new Map<string, Intl.PluralRules>();
+const numberFormatCache = new Map<string, Intl.NumberFormat>();
+
+function getPluralRules(locale: string): Intl.PluralRules {
+  let rules = pluralRulesCache.get(locale);
+  if (!rules) {
+    rules = new Intl.PluralRules(locale);
+    pluralRulesCache.set(locale, rules);
+  }
+  return rules;
+}
+
+function formatNumber(value: number, locale: string): string {
+  let format = numberFormatCache.get(locale);
+  if (!format) {
+    format = new Intl.NumberFormat(locale);
+    numberFormatCache.set(locale, format);
+  }
+  return format.format(value);
+}
+
+/**
+ * Format parsed parts. Missing arguments are left as `{name}` so a
+ * forgotten parameter is visible rather than silently blank.
+ */
+export function formatParts(
+  parts: MessagePart[],
+  params: MessageParams,
+  locale: string,
+  pound: number | null = null
+): string {
+  let result = '';
+  for (const part of parts) {
+    if (typeof part === 'string') {
+      result += part;
+      continue;
+    }
+
+    switch (part.type) {
+      case 'pound':
+        result += pound === null ? '#' : formatNumber(pound, locale);
+        break;
+
+      case 'argument': {
+        const value = params[part.name];
+        if (value === undefined) {
+          result += `{${part.name}}`;
+        } else if (part.number && typeof value === 'number') {
+          result += formatNumber(value, locale);
+        } else {
+          result += String(value);
+        }
+        break;
+      }
+
+      case 'plural': {
+        const n = Number(params[part.name] ?? 0);
+        const exact = part.options.get(`=${n}`);
+        const branch = exact ?? part.options.get(getPluralRules(locale).select(n - part.offset)) ?? part.options.get('other')!;
+        result += formatParts(branch, params, locale, n - part.offset);
+        break;
+      }
+
+      case 'select': {
+        const value = String(params[part.name] ?? '');
+        const branch = part.options.get(value) ?? part.options.get('other')!;
+        result += formatParts(branch, params, locale, pound);
+        break;
+      }
+    }
+  }
+  return result;
+}
+
+/**
+ * Parse and format a message in one step.
+ */
+export function formatMessage(source: string, params: MessageParams = {}, locale = 'en'): string {
+  return formatParts(parseMessage(source), params, locale);
+}
+
+/**
+ * Names of all arguments a message uses (including plural/select ones).
+ */
+export function getMessageArguments(source: string): Set<string> {
+  const names = new Set<string>();
+  const visit = (parts: MessagePart[]) => {
+    for (const part of parts) {
+      if (typeof part === 'string' || part.type === 'pound') continue;
+      names.add(part.name);
+      if (part.type === 'plural' || part.type === 'select') {
+        for (const branch of part.options.values()) visit(branch);
+      }
+    }
+  };
+  visit(parseMessage(source));
+  return names;
+}
@@ -0,0 +1,117 @@
+/**
+ * Localization
+ *
+ * Message catalogs for the TUI. UI code looks strings up with `t(key, params)`;
+ * messages use ICU MessageFormat (see format.ts). English is the source
+ * catalog and the fallback for keys a locale hasn't translated yet.
+ *
+ * The locale comes from the `workbench.locale` setting: a locale code, or
+ * `auto` to follow LC_ALL / LC_MESSAGES / LANG.
+ */
+
+import { en, type MessageKey } from './messages/en.ts';
+import { de } from './messages/de.ts';
+import { ja } from './messages/ja.ts';
+import { parseMessage, formatParts, MessageFormatError, type MessagePart, type MessageParams } from './format.ts';
+import { debugLog } from '../../../debug.ts';
+
+export type { MessageKey } from './messages/en.ts';
+export type { MessageParams, MessagePart } from './format.ts';
+export { formatMessage, parseMessage, getMessageArguments, MessageFormatError } from './format.ts';
+
+// ============================================
+// Catalogs
+// ============================================
+
+/**
+ * Translations for one locale. Missing keys fall back to English.
+ */
+export type MessageCatalog = Partial<Record<MessageKey, string>>;
+
+/** Source locale */
+export const DEFAULT_LOCALE = 'en';
+
+/** Catalogs by locale code */
+export const MESSAGE_CATALOGS: Record<string, MessageCatalog> = { en, de, ja };
+
+/** Locale codes with a catalog */
+export const AVAILABLE_LOCALES = Object.keys(MESSAGE_CATALOGS);
+
+// ============================================
+// State
+// ============================================
+
+let currentLocale = DEFAULT_LOCALE;
+const compiled = new Map<string, MessagePart[]>();
+const localeListeners = new Set<(locale: string) => void>();
+
+/**
+ * Map a `workbench.locale` value to an available locale. `auto` reads the
+ * environment; region and encoding are dropped (`de_AT.UTF-8` → `de`), and
+ * anything unknown falls back to English.
+ */
+export function resolveLocale(setting: string, env: Record<string, string | undefined> = process.env): string {
+  const requested = setting && setting !== 'auto' ? setting : env.LC_ALL || env.LC_MESSAGES || env.LANG || '';
+  const tag = requested.split('.')[0]!.replace('_', '-').toLowerCase();
+  if (AVAILABLE_LOCALES.includes(tag)) return tag;
+
+  const language = tag.split('-')[0]!;
+  return AVAILABLE_LOCALES.includes(language) ? language : DEFAULT_LOCALE;
+}
+
+/**
+ * Set the UI locale from a `workbench.locale` value.
+ */
+export function setLocale(setting: string): void {
+  const locale = resolveLocale(setting);
+  if (locale === currentLocale) return;
+  currentLocale = locale;
+  for (const listener of localeListeners) {
+    listener(locale);
+  }
+}
+
+/**
+ * Get the current UI locale.
+ */
+export function getLocale(): string {
+  return currentLocale;
+}
+
+/**
+ * Subscribe to locale changes. Returns an unsubscribe function.
+ */
+export function onLocaleChange(listener: (locale: string) => void): () => void {
+  localeListeners.add(listener);
+  return () => {
+    localeListeners.delete(listener);
+  };
+}
+
+// ============================================
+// Lookup
+// ============================================
+
+/**
+ * Look up and format a message in the current locale.
+ */
+export function t(key: MessageKey, params: MessageParams = {}): string {
+  const translated = MESSAGE_CATALOGS[currentLocale]?.[key];
+  const locale = translated !== undefined ? currentLocale : DEFAULT_LOCALE;
+  const source = translated ?? en[key] ?? key;
+
+  const cacheKey = `${locale}:${key}`;
+  let parts = compiled.get(cacheKey);
+  if (!parts) {
+    try {
+      parts = parseMessage(source);
+    } catch (error) {
+      if (!(error instanceof MessageFormatError)) throw error;
+      debugLog(`[i18n] ${locale} ${key}: ${error.message}`);
+      parts = [source];
+    }
+    compiled.set(cacheKey, parts);
+  }
+
+  return formatParts(parts, params, locale);
+}
@@ -0,0 +1,159 @@
+/**
+ * German Messages
+ */
+
+import type { MessageKey } from './en.ts';
+
+export const de: Partial<Record<MessageKey, string>> = {
+  // Common
+  'common.yes': 'Ja',
+  'common.no': 'Nein',
+  'common.cancel': 'Abbrechen',
+  'common.save': 'Speichern',
+  'common.loading': 'Wird geladen...',
+  'common.noResults': 'Keine Ergebnisse',
+  'common.errorWithMessage': 'Fehler: {message}',
+  'common.itemPosition': '{item}, {index} von {count}',
+
+  // Confirm dialog
+  'confirm.title': 'Bestätigen',
+  'confirm.hint': 'Tab/Pfeile: wechseln • Enter: auswählen',
+  'confirm.hintWithCancel': 'Tab/Pfeile: wechseln • Enter: auswählen • Esc: abbrechen',
+
+  // Input dialog
+  'input.title': 'Eingabe',
+  'input.accessibleTitle': '{title}, Textfeld',
+  'input.accessibleValue': 'Aktueller Wert: {value}',
+  'input.accessibleExample': 'Beispiel: {example}',
+
+  // Save before closing
+  'saveConfirm.title': 'Änderungen speichern',
+  'saveConfirm.message': 'Möchten Sie die Änderungen an {filename} speichern?\n\nIhre Änderungen gehen verloren, wenn Sie sie nicht speichern.',
+  'saveConfirm.discard': 'Verwerfen',
+
+  // Pickers
+  'commandPalette.title': 'Befehlspalette',
+  'commandPalette.placeholder': 'Befehl eingeben...',
+  'quickOpen.title': 'Schnell öffnen',
+  'quickOpen.placeholder': 'Dateien suchen...',
+  'symbolPicker.title': 'Gehe zu Symbol',
+  'symbolPicker.placeholder': 'Symbole suchen...',
+  'references.placeholder': 'Referenzen filtern...',
+  'references.previewUnavailable': '(Vorschau nicht verfügbar)',
+  'searchable.placeholder': 'Suchbegriff eingeben...',
+  'searchable.noMatches': 'Keine passenden Einträge',
+  'searchable.noItems': 'Keine Einträge vorhanden',
+
+  // Commit dialog
+  'commit.title': 'Git-Commit',
+  'commit.stagedChanges': 'Vorgemerkte Änderungen:',
+  'commit.moreFiles': '... und {count} weitere',
+  'commit.types': 'Typen: {types}',
+  'commit.message': 'Nachricht:',
+  'commit.messageAmending': 'Nachricht (ergänzen):',
+  'commit.footer': 'Strg+Enter: Commit  |  Strg+A: Ergänzen umschalten  |  Escape: abbrechen',
+
+  // Go to line
+  'gotoLine.title': 'Gehe zu Zeile',
+  'gotoLine.enterLine': 'Zeilennummer eingeben',
+  'gotoLine.enterLineExample': 'Zeilennummer eingeben (z. B. 42 oder 42:10)',
+  'gotoLine.invalidFormat': 'Ungültiges Format. Verwenden Sie: Zeile oder Zeile:Spalte',
+  'gotoLine.linePositive': 'Die Zeile muss eine positive Zahl sein',
+  'gotoLine.columnPositive': 'Die Spalte muss eine positive Zahl sein',
+  'gotoLine.lineAtLeastOne': 'Die Zeile muss mindestens 1 sein',
+  'gotoLine.columnAtLeastOne': 'Die Spalte muss mindestens 1 sein',
+  'gotoLine.lineExceedsLength': 'Zeile liegt hinter dem Dokumentende ({total})',
+  'gotoLine.currentLine': 'Aktuelle Zeile: {line} von {total}',
+  'gotoLine.placeholder': 'Zeile:Spalte (aktuell: {line})',
+  'gotoLine.format': 'Format: Zeile oder Zeile:Spalte',
+  'gotoLine.help': 'Enter: Gehe zu Zeile | Esc: Abbrechen',
+
+  // File dialogs
+  'fileBrowser.title': 'Datei öffnen',
+  'fileBrowser.emptyDirectory': 'Leeres Verzeichnis',
+  'fileBrowser.help': '↑↓:Navigation  ←:hoch  →/Enter:öffnen  .:versteckte  Esc:schließen',
+  'saveAs.title': 'Speichern unter',
+  'saveAs.name': 'Name:',
+  'saveAs.help': 'Tab:wechseln  ↑↓:Navigation  ←:hoch  Enter:speichern  Esc:abbrechen',
+  'saveAs.confirmOverwrite': 'Überschreiben bestätigen',
+  'saveAs.fileExists': 'Die Datei existiert bereits:',
+  'saveAs.overwritePrompt': 'Überschreiben? (Y) Ja / (N) Nein',
+
+  // Find and replace
+  'search.find': 'Suchen',
+  'search.findAndReplace': 'Suchen und Ersetzen',
+  'search.findLabel': 'Suchen:',
+  'search.replaceLabel': 'Ersetzen:',
+  'search.help': 'Tab: Wechseln | Enter: Ausführen | Esc: Schließen',
+
+  // Settings dialog
+  'settings.title': 'Einstellungen',
+  'settings.placeholder': 'Einstellungen suchen...',
+  'settings.invalidValue': 'Ungültiger Wert',
+  'settings.noMatches': 'Keine passenden Einstellungen',
+  'settings.noSettings': 'Keine Einstellungen vorhanden',
+  'settings.notSet': 'nicht gesetzt',
+  'settings.hintNumber': 'Enter: bestätigen | ↑/↓: ändern | Esc: abbrechen',
+  'settings.hintText': 'Enter: bestätigen | ←/→: Cursor bewegen | Esc: abbrechen',
+  'settings.hintEnum': 'Enter: bestätigen | ←/→: ändern | Esc: abbrechen',
+  'settings.hintEdit': 'Enter: bestätigen | Esc: abbrechen',
+  'settings.hintBrowse': 'Enter: bearbeiten | Leertaste: umschalten | R: zurücksetzen | Esc: schließen',
+
+  // Keybindings dialog
+  'keybindings.title': 'Tastenkombinationen',
+  'keybindings.placeholder': 'Tastenkombinationen suchen...',
+  'keybindings.conflict': '„{key}“ wird von „{command}“ verwendet. Enter zum Überschreiben, Escape zum Abbrechen.',
+  'keybindings.noMatches': 'Keine passenden Tastenkombinationen',
+  'keybindings.noKeybindings': 'Keine Tastenkombinationen vorhanden',
+  'keybindings.pressKey': 'Taste drücken...',
+  'keybindings.cmdNote': 'Hinweis: CMD-Taste = „meta“ auf dem Mac',
+  'keybindings.hintConflict': 'Enter: überschreiben | Esc: abbrechen',
+  'keybindings.hintCapture': 'Tastenkombination drücken... | Esc: abbrechen',
+  'keybindings.hintBrowse': 'Enter: ändern | R: zurücksetzen | Esc: schließen',
+
+  // Popups
+  'colorPicker.title': 'Farbe',
+  'colorPicker.hint': 'Enter übernehmen · Esc abbrechen',
+  'hover.moreLines': '... ({count, plural, one {# weitere Zeile} other {# weitere Zeilen}})',
+
+  // Notifications
+  'notifications.title': 'Benachrichtigungen',
+  'notifications.empty': 'Keine Benachrichtigungen',
+  'notifications.doNotShowAgain': 'Nicht mehr anzeigen',
+  'notifications.hint': '↑/↓: auswählen • 1-9: Aktion • x: abbrechen • Entf: verwerfen • d: nicht mehr anzeigen • c: leeren • Esc: schließen',
+
+  // Which-key popup
+  'whichKey.group': '{count, plural, one {+# Befehl} other {+# Befehle}}',
+  'whichKey.more': '+{count} weitere',
+
+  // Git panel
+  'git.stagedChanges': 'Vorgemerkte Änderungen',
+  'git.changes': 'Änderungen',
+  'git.untrackedFiles': 'Unversionierte Dateien',
+  'git.noChanges': 'Keine Änderungen',
+  'git.merging': 'MERGE',
+  'git.rebasing': 'REBASE',
+  'git.hintStage': 's:vormerken S:alle u:zurücknehmen d:verwerfen',
+  'git.hintCommit': 'c:Commit r:aktualisieren ↵:Diff o:öffnen',
+
+  // Status bar
+  'statusBar.position': 'Z. {line}, Sp. {column}',
+  'statusBar.selection': '{lines, plural, one {# Zeile} other {# Zeilen}}, {chars, plural, one {# Zeichen} other {# Zeichen}} ausgewählt',
+  'statusBar.spaces': 'Leerzeichen: {size}',
+  'statusBar.query': 'Abfrage {id}',
+
+  // Setting descriptions
+  'settingDescriptions.tui.imagePreview.protocol': 'Wie Bilder gezeichnet werden (standardmäßig automatisch erkannt)',
+  'settingDescriptions.tui.repl.commands': 'REPL-Befehlszeile pro Sprache (ersetzt die eingebaute REPL)',
+  'settingDescriptions.tui.todo.collapsedOnStartup': 'TODO-Bereich beim Start einklappen',
+  'settingDescriptions.tui.todo.tags': 'Vom TODO-Bereich erfasste Kommentar-Tags',
+  'settingDescriptions.tui.dependencyGraph.includeExternal': 'Importe aus der Standardbibliothek und von Drittanbietern im Abhängigkeitsgraphen anzeigen',
+  'settingDescriptions.tui.dependencyGraph.layers': 'Architekturschichten, oberste zuerst; eine Schicht darf nur sich selbst und darunterliegende Schichten importieren',
+  'settingDescriptions.tui.zenMode.width': 'Textbreite des zentrierten Editors im Zen-Modus (0 = volle Breite)',
+  'settingDescriptions.tui.zenMode.dimOutsideParagraph': 'Im Zen-Modus alle Zeilen außer dem aktuellen Absatz abdunkeln',
+  'settingDescriptions.tui.tabBar.enablePreview': 'Dateien aus Schnellöffnen, Dateibaum und Suchergebnissen als Vorschau-Tabs öffnen',
+  'settingDescriptions.tui.notifications.doNotShowAgain': 'Mit „Nicht mehr anzeigen“ ausgeblendete Benachrichtigungen',
+  'settingDescriptions.tui.keybindings.leader': 'Taste, für die „leader“ in Tastenbelegungen steht',
+  'settingDescriptions.tui.whichKey.enabled': 'Tasten anzeigen, die auf ein Akkord-Präfix folgen können',
+  'settingDescriptions.tui.whichKey.delay': 'Verzögerung, bevor das Which-Key-Popup erscheint (ms)',
+};
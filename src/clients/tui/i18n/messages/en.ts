@@ -0,0 +1,166 @@
+/**
+ * English Messages
+ *
+ * Source catalog: every key used with `t()` is defined here, and other
+ * locales fall back to these messages. Keys are grouped by the dialog or
+ * panel that shows them; `common.*` is shared.
+ */
+
+export const en = {
+  // Common
+  'common.yes': 'Yes',
+  'common.no': 'No',
+  'common.cancel': 'Cancel',
+  'common.save': 'Save',
+  'common.loading': 'Loading...',
+  'common.noResults': 'No results',
+  'common.errorWithMessage': 'Error: {message}',
+  'common.itemPosition': '{item}, {index} of {count}',
+
+  // Confirm dialog
+  'confirm.title': 'Confirm',
+  'confirm.hint': 'Tab/Arrow: navigate • Enter: select',
+  'confirm.hintWithCancel': 'Tab/Arrow: navigate • Enter: select • Esc: cancel',
+
+  // Input dialog
+  'input.title': 'Input',
+  'input.accessibleTitle': '{title}, edit text',
+  'input.accessibleValue': 'Current value: {value}',
+  'input.accessibleExample': 'Example: {example}',
+
+  // Save before closing
+  'saveConfirm.title': 'Save Changes',
+  'saveConfirm.message': "Do you want to save the changes you made to {filename}?\n\nYour changes will be lost if you don't save them.",
+  'saveConfirm.discard': 'Discard',
+
+  // Pickers
+  'commandPalette.title': 'Command Palette',
+  'commandPalette.placeholder': 'Type a command...',
+  'quickOpen.title': 'Quick Open',
+  'quickOpen.placeholder': 'Search files...',
+  'symbolPicker.title': 'Go to Symbol',
+  'symbolPicker.placeholder': 'Search symbols...',
+  'references.placeholder': 'Type to filter references...',
+  'references.previewUnavailable': '(unable to load preview)',
+  'searchable.placeholder': 'Type to search...',
+  'searchable.noMatches': 'No matching items',
+  'searchable.noItems': 'No items available',
+
+  // Commit dialog
+  'commit.title': 'Git Commit',
+  'commit.stagedChanges': 'Staged Changes:',
+  'commit.moreFiles': '... and {count} more',
+  'commit.types': 'Types: {types}',
+  'commit.message': 'Message:',
+  'commit.messageAmending': 'Message (amending):',
+  'commit.footer': 'Ctrl+Enter: commit  |  Ctrl+A: toggle amend  |  Escape: cancel',
+
+  // Go to line
+  'gotoLine.title': 'Go to Line',
+  'gotoLine.enterLine': 'Enter a line number',
+  'gotoLine.enterLineExample': 'Enter a line number (e.g., 42 or 42:10)',
+  'gotoLine.invalidFormat': 'Invalid format. Use: line or line:column',
+  'gotoLine.linePositive': 'Line must be a positive number',
+  'gotoLine.columnPositive': 'Column must be a positive number',
+  'gotoLine.lineAtLeastOne': 'Line must be at least 1',
+  'gotoLine.columnAtLeastOne': 'Column must be at least 1',
+  'gotoLine.lineExceedsLength': 'Line exceeds document length ({total})',
+  'gotoLine.currentLine': 'Current line: {line} of {total}',
+  'gotoLine.placeholder': 'line:column (current: {line})',
+  'gotoLine.format': 'Format: line or line:column',
+  'gotoLine.help': 'Enter: Go to line | Esc: Cancel',
+
+  // File dialogs
+  'fileBrowser.title': 'Open File',
+  'fileBrowser.emptyDirectory': 'Empty directory',
+  'fileBrowser.help': '↑↓:nav  ←:up  →/Enter:open  .:hidden  Esc:close',
+  'saveAs.title': 'Save As',
+  'saveAs.name': 'Name:',
+  'saveAs.help': 'Tab:switch  ↑↓:nav  ←:up  Enter:save  Esc:cancel',
+  'saveAs.confirmOverwrite': 'Confirm Overwrite',
+  'saveAs.fileExists': 'File already exists:',
+  'saveAs.overwritePrompt': 'Overwrite? (Y)es / (N)o',
+
+  // Find and replace
+  'search.find': 'Find',
+  'search.findAndReplace': 'Find and Replace',
+  'search.findLabel': 'Find:',
+  'search.replaceLabel': 'Replace:',
+  'search.help': 'Tab: Cycle | Enter: Activate | Esc: Close',
+
+  // Settings dialog
+  'settings.title': 'Settings',
+  'settings.placeholder': 'Search settings...',
+  'settings.invalidValue': 'Invalid value',
+  'settings.noMatches': 'No matching settings',
+  'settings.noSettings': 'No settings available',
+  'settings.notSet': 'not set',
+  'settings.hintNumber': 'Enter: confirm | ↑/↓: adjust | Esc: cancel',
+  'settings.hintText': 'Enter: confirm | ←/→: move cursor | Esc: cancel',
+  'settings.hintEnum': 'Enter: confirm | ←/→: change | Esc: cancel',
+  'settings.hintEdit': 'Enter: confirm | Esc: cancel',
+  'settings.hintBrowse': 'Enter: edit | Space: toggle | R: reset | Esc: close',
+
+  // Keybindings dialog
+  'keybindings.title': 'Keyboard Shortcuts',
+  'keybindings.placeholder': 'Search keybindings...',
+  'keybindings.conflict': '"{key}" is used by "{command}". Press Enter to override, Escape to cancel.',
+  'keybindings.noMatches': 'No matching keybindings',
+  'keybindings.noKeybindings': 'No keybindings available',
+  'keybindings.pressKey': 'Press key...',
+  'keybindings.cmdNote': 'Note: CMD key = "meta" on Mac',
+  'keybindings.hintConflict': 'Enter: override | Esc: cancel',
+  'keybindings.hintCapture': 'Press key combination... | Esc: cancel',
+  'keybindings.hintBrowse': 'Enter: change | R: reset | Esc: close',
+
+  // Popups
+  'colorPicker.title': 'Color',
+  'colorPicker.hint': 'Enter apply · Esc cancel',
+  'hover.moreLines': '... ({count, plural, one {# more line} other {# more lines}})',
+
+  // Notifications
+  'notifications.title': 'Notifications',
+  'notifications.empty': 'No notifications',
+  'notifications.doNotShowAgain': "Don't show again",
+  'notifications.hint': '↑/↓: select • 1-9: action • x: cancel • Del: dismiss • d: don\'t show again • c: clear • Esc: close',
+
+  // Which-key popup
+  'whichKey.group': '{count, plural, one {+# command} other {+# commands}}',
+  'whichKey.more': '+{count} more',
+
+  // Git panel
+  'git.stagedChanges': 'Staged Changes',
+  'git.changes': 'Changes',
+  'git.untrackedFiles': 'Untracked Files',
+  'git.noChanges': 'No changes',
+  'git.merging': 'MERGING',
+  'git.rebasing': 'REBASING',
+  'git.hintStage': 's:stage S:all u:unstage d:discard',
+  'git.hintCommit': 'c:commit r:refresh ↵:diff o:open',
+
+  // Status bar
+  'statusBar.position': 'Ln {line}, Col {column}',
+  'statusBar.selection': '{lines, plural, one {# line} other {# lines}}, {chars, plural, one {# char} other {# chars}} selected',
+  'statusBar.spaces': 'Spaces: {size}',
+  'statusBar.query': 'Query {id}',
+
+  // Setting descriptions
+  'settingDescriptions.tui.imagePreview.protocol': 'How images are drawn (auto-detected by default)',
+  'settingDescriptions.tui.repl.commands': 'REPL command line per language (overrides the built-in REPL)',
+  'settingDescriptions.tui.todo.collapsedOnStartup': 'Collapse TODO panel on startup',
+  'settingDescriptions.tui.todo.tags': 'Comment tags indexed by the TODO panel',
+  'settingDescriptions.tui.dependencyGraph.includeExternal': 'Show standard library and third-party imports in the dependency graph',
+  'settingDescriptions.tui.dependencyGraph.layers': 'Architecture layers, top first; a layer may only import itself and layers below it',
+  'settingDescriptions.tui.zenMode.width': 'Text width of the centered editor in zen mode (0 = full width)',
+  'settingDescriptions.tui.zenMode.dimOutsideParagraph': 'Dim every line except the current paragraph in zen mode',
+  'settingDescriptions.tui.tabBar.enablePreview': 'Open files from quick open, the file tree and search results as preview tabs',
+  'settingDescriptions.tui.notifications.doNotShowAgain': 'Notifications hidden with "Don\'t show again"',
+  'settingDescriptions.tui.keybindings.leader': 'Key that "leader" stands for in keybindings',
+  'settingDescriptions.tui.whichKey.enabled': 'Show the keys that can follow a chord prefix',
+  'settingDescriptions.tui.whichKey.delay': 'Delay before the which-key popup appears (ms)',
+};
+
+/**
+ * A message key.
+ */
+export type MessageKey = keyof typeof en;
@@ -0,0 +1,159 @@
+/**
+ * Japanese Messages
+ */
+
+import type { MessageKey } from './en.ts';
+
+export const ja: Partial<Record<MessageKey, string>> = {
+  // Common
+  'common.yes': 'はい',
+  'common.no': 'いいえ',
+  'common.cancel': 'キャンセル',
+  'common.save': '保存',
+  'common.loading': '読み込み中...',
+  'common.noResults': '結果なし',
+  'common.errorWithMessage': 'エラー: {message}',
+  'common.itemPosition': '{item}, {count} 件中 {index} 件目',
+
+  // Confirm dialog
+  'confirm.title': '確認',
+  'confirm.hint': 'Tab/矢印: 移動 • Enter: 選択',
+  'confirm.hintWithCancel': 'Tab/矢印: 移動 • Enter: 選択 • Esc: キャンセル',
+
+  // Input dialog
+  'input.title': '入力',
+  'input.accessibleTitle': '{title}, テキスト入力',
+  'input.accessibleValue': '現在の値: {value}',
+  'input.accessibleExample': '例: {example}',
+
+  // Save before closing
+  'saveConfirm.title': '変更を保存',
+  'saveConfirm.message': '{filename} への変更を保存しますか?\n\n保存しない場合、変更は失われます。',
+  'saveConfirm.discard': '破棄',
+
+  // Pickers
+  'commandPalette.title': 'コマンドパレット',
+  'commandPalette.placeholder': 'コマンドを入力...',
+  'quickOpen.title': 'クイックオープン',
+  'quickOpen.placeholder': 'ファイルを検索...',
+  'symbolPicker.title': 'シンボルへ移動',
+  'symbolPicker.placeholder': 'シンボルを検索...',
+  'references.placeholder': '参照を絞り込み...',
+  'references.previewUnavailable': '(プレビューを読み込めません)',
+  'searchable.placeholder': '入力して検索...',
+  'searchable.noMatches': '一致する項目はありません',
+  'searchable.noItems': '項目がありません',
+
+  // Commit dialog
+  'commit.title': 'Git コミット',
+  'commit.stagedChanges': 'ステージ済みの変更:',
+  'commit.moreFiles': '... 他 {count} 件',
+  'commit.types': '種類: {types}',
+  'commit.message': 'メッセージ:',
+  'commit.messageAmending': 'メッセージ (amend):',
+  'commit.footer': 'Ctrl+Enter: コミット  |  Ctrl+A: amend 切替  |  Escape: キャンセル',
+
+  // Go to line
+  'gotoLine.title': '行へ移動',
+  'gotoLine.enterLine': '行番号を入力してください',
+  'gotoLine.enterLineExample': '行番号を入力してください (例: 42 または 42:10)',
+  'gotoLine.invalidFormat': '形式が正しくありません。行 または 行:列 で指定してください',
+  'gotoLine.linePositive': '行は正の数で指定してください',
+  'gotoLine.columnPositive': '列は正の数で指定してください',
+  'gotoLine.lineAtLeastOne': '行は 1 以上で指定してください',
+  'gotoLine.columnAtLeastOne': '列は 1 以上で指定してください',
+  'gotoLine.lineExceedsLength': '行がドキュメントの長さ ({total}) を超えています',
+  'gotoLine.currentLine': '現在の行: {line} / {total}',
+  'gotoLine.placeholder': '行:列 (現在: {line})',
+  'gotoLine.format': '形式: 行 または 行:列',
+  'gotoLine.help': 'Enter: 行へ移動 | Esc: キャンセル',
+
+  // File dialogs
+  'fileBrowser.title': 'ファイルを開く',
+  'fileBrowser.emptyDirectory': '空のディレクトリ',
+  'fileBrowser.help': '↑↓:移動  ←:上へ  →/Enter:開く  .:隠しファイル  Esc:閉じる',
+  'saveAs.title': '名前を付けて保存',
+  'saveAs.name': '名前:',
+  'saveAs.help': 'Tab:切替  ↑↓:移動  ←:上へ  Enter:保存  Esc:キャンセル',
+  'saveAs.confirmOverwrite': '上書きの確認',
+  'saveAs.fileExists': 'ファイルは既に存在します:',
+  'saveAs.overwritePrompt': '上書きしますか? (Y) はい / (N) いいえ',
+
+  // Find and replace
+  'search.find': '検索',
+  'search.findAndReplace': '検索と置換',
+  'search.findLabel': '検索:',
+  'search.replaceLabel': '置換:',
+  'search.help': 'Tab: 切替 | Enter: 実行 | Esc: 閉じる',
+
+  // Settings dialog
+  'settings.title': '設定',
+  'settings.placeholder': '設定を検索...',
+  'settings.invalidValue': '無効な値です',
+  'settings.noMatches': '一致する設定はありません',
+  'settings.noSettings': '設定がありません',
+  'settings.notSet': '未設定',
+  'settings.hintNumber': 'Enter: 確定 | ↑/↓: 増減 | Esc: キャンセル',
+  'settings.hintText': 'Enter: 確定 | ←/→: カーソル移動 | Esc: キャンセル',
+  'settings.hintEnum': 'Enter: 確定 | ←/→: 変更 | Esc: キャンセル',
+  'settings.hintEdit': 'Enter: 確定 | Esc: キャンセル',
+  'settings.hintBrowse': 'Enter: 編集 | Space: 切替 | R: リセット | Esc: 閉じる',
+
+  // Keybindings dialog
+  'keybindings.title': 'キーボードショートカット',
+  'keybindings.placeholder': 'キーバインドを検索...',
+  'keybindings.conflict': '「{key}」は「{command}」で使用されています。Enter で上書き、Escape でキャンセルします。',
+  'keybindings.noMatches': '一致するキーバインドはありません',
+  'keybindings.noKeybindings': 'キーバインドがありません',
+  'keybindings.pressKey': 'キーを押してください...',
+  'keybindings.cmdNote': '注: Mac の CMD キーは "meta" です',
+  'keybindings.hintConflict': 'Enter: 上書き | Esc: キャンセル',
+  'keybindings.hintCapture': 'キーの組み合わせを押してください... | Esc: キャンセル',
+  'keybindings.hintBrowse': 'Enter: 変更 | R: リセット | Esc: 閉じる',
+
+  // Popups
+  'colorPicker.title': '色',
+  'colorPicker.hint': 'Enter 適用 · Esc キャンセル',
+  'hover.moreLines': '... (他 {count, plural, other {# 行}})',
+
+  // Notifications
+  'notifications.title': '通知',
+  'notifications.empty': '通知はありません',
+  'notifications.doNotShowAgain': '今後表示しない',
+  'notifications.hint': '↑/↓: 選択 • 1-9: 操作 • x: キャンセル • Del: 削除 • d: 今後表示しない • c: すべて消去 • Esc: 閉じる',
+
+  // Which-key popup
+  'whichKey.group': '{count, plural, other {+# 個のコマンド}}',
+  'whichKey.more': 'ほか {count} 件',
+
+  // Git panel
+  'git.stagedChanges': 'ステージ済みの変更',
+  'git.changes': '変更',
+  'git.untrackedFiles': '未追跡のファイル',
+  'git.noChanges': '変更はありません',
+  'git.merging': 'マージ中',
+  'git.rebasing': 'リベース中',
+  'git.hintStage': 's:ステージ S:すべて u:取消 d:破棄',
+  'git.hintCommit': 'c:コミット r:更新 ↵:差分 o:開く',
+
+  // Status bar
+  'statusBar.position': '{line} 行、{column} 列',
+  'statusBar.selection': '{lines, plural, other {# 行}}、{chars, plural, other {# 文字}}を選択',
+  'statusBar.spaces': 'スペース: {size}',
+  'statusBar.query': 'クエリ {id}',
+
+  // Setting descriptions
+  'settingDescriptions.tui.imagePreview.protocol': '画像の描画方法（既定では自動検出）',
+  'settingDescriptions.tui.repl.commands': '言語ごとの REPL コマンドライン（組み込みの REPL を上書き）',
+  'settingDescriptions.tui.todo.collapsedOnStartup': '起動時に TODO パネルを折りたたむ',
+  'settingDescriptions.tui.todo.tags': 'TODO パネルが収集するコメントタグ',
+  'settingDescriptions.tui.dependencyGraph.includeExternal': '依存関係グラフに標準ライブラリとサードパーティのインポートを表示',
+  'settingDescriptions.tui.dependencyGraph.layers': 'アーキテクチャのレイヤー（上位から順）。各レイヤーは自身と下位のレイヤーのみインポート可能',
+  'settingDescriptions.tui.zenMode.width': 'Zen モードで中央に表示するエディターの幅（0 = 全幅）',
+  'settingDescriptions.tui.zenMode.dimOutsideParagraph': 'Zen モードで現在の段落以外の行を暗く表示',
+  'settingDescriptions.tui.tabBar.enablePreview': 'クイックオープン、ファイルツリー、検索結果から開いたファイルをプレビュータブにする',
+  'settingDescriptions.tui.notifications.doNotShowAgain': '「今後表示しない」で非表示にした通知',
+  'settingDescriptions.tui.keybindings.leader': 'キーバインドの "leader" が表すキー',
+  'settingDescriptions.tui.whichKey.enabled': 'キーの組み合わせの続きに押せるキーを表示',
+  'settingDescriptions.tui.whichKey.delay': 'which-key ポップアップが表示されるまでの遅延（ms）',
+};
@@ -57,6 +57,11 @@ export {
   type RendererOptions,
 } from './rendering/renderer.ts';
 
+export {
+  GraphicsLayer,
+  type GraphicsPlacement,
+} from './rendering/graphics-layer.ts';
+
 // ============================================
 // Elements
 // ============================================
@@ -119,6 +124,11 @@ export {
   type GitPanelCallbacks,
 } from './elements/git-panel.ts';
 
+export {
+  ImageViewer,
+  type ImageViewerState,
+} from './elements/image-viewer.ts';
+
 // ============================================
 // Layout
 // ============================================
@@ -148,6 +158,32 @@ export {
   type HistoryEntry,
 } from './status-bar/status-bar.ts';
 
+// ============================================
+// Accessibility
+// ============================================
+
+export {
+  Announcer,
+  ScreenReaderTracker,
+  ReaderBridge,
+  type Announcement,
+  type AccessibilitySnapshot,
+} from './accessibility/index.ts';
+
+// ============================================
+// Localization
+// ============================================
+
+export {
+  t,
+  setLocale,
+  getLocale,
+  resolveLocale,
+  AVAILABLE_LOCALES,
+  type MessageKey,
+  type MessageParams,
+} from './i18n/index.ts';
+
 // ============================================
 // Overlays
 // ============================================
@@ -106,6 +106,10 @@ export class PaneContainer implements FocusResolver {
   /** Reserved height at the bottom for terminal panel (only affects non-accordion panes) */
   private bottomReservedHeight = 0;
 
+  /** Pane shown alone (zen mode), and its width (0 = full width) */
+  private zoomedPaneId: string | null = null;
+  private zoomWidth = 0;
+
   constructor(callbacks: PaneContainerCallbacks) {
     this.callbacks = callbacks;
   }
@@ -238,9 +242,7 @@ export class PaneContainer implements FocusResolver {
    */
   setBounds(bounds: Rect): void {
     this.bounds = { ...bounds };
-    if (this.root) {
-      this.layoutNode(this.root, bounds);
-    }
+    this.layout();
   }
 
   /**
@@ -257,9 +259,7 @@ export class PaneContainer implements FocusResolver {
   setBottomReservedHeight(height: number): void {
     if (this.bottomReservedHeight === height) return;
     this.bottomReservedHeight = height;
-    if (this.root) {
-      this.layoutNode(this.root, this.bounds);
-    }
+    this.layout();
   }
 
   /**
@@ -269,6 +269,15 @@ export class PaneContainer implements FocusResolver {
     return this.bottomReservedHeight;
   }
 
+  private layout(): void {
+    const zoomed = this.getZoomedPane();
+    if (zoomed) {
+      zoomed.setBounds(this.getZoomBounds());
+    } else if (this.root) {
+      this.layoutNode(this.root, this.bounds);
+    }
+  }
+
   private layoutNode(node: LayoutNode, bounds: Rect): void {
     if (node instanceof Pane) {
       // For accordion panes (sidebar), use full height
@@ -308,6 +317,51 @@ export class PaneContainer implements FocusResolver {
     });
   }
 
+  // ─────────────────────────────────────────────────────────────────────────
+  // Zoom
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /**
+   * Show one pane alone, centered at `width` columns (0 = full width), or
+   * pass null to show the whole layout again. Other panes keep their
+   * elements and are laid out as before when the zoom ends.
+   */
+  setZoomedPane(paneId: string | null, width = 0): boolean {
+    if (paneId !== null && !this.panes.has(paneId)) return false;
+    this.zoomedPaneId = paneId;
+    this.zoomWidth = Math.max(0, width);
+    this.layout();
+    this.callbacks.onDirty();
+    return true;
+  }
+
+  getZoomedPane(): Pane | null {
+    return this.zoomedPaneId ? (this.panes.get(this.zoomedPaneId) ?? null) : null;
+  }
+
+  private getZoomBounds(): Rect {
+    const width = this.zoomWidth > 0 ? Math.min(this.zoomWidth, this.bounds.width) : this.bounds.width;
+    return {
+      x: this.bounds.x + Math.floor((this.bounds.width - width) / 2),
+      y: this.bounds.y,
+      width,
+      height: Math.max(1, this.bounds.height - this.bottomReservedHeight),
+    };
+  }
+
+  /**
+   * Restore split ratios from a serialized layout (e.g. one captured
+   * before a temporary layout change). Splits that no longer exist are
+   * skipped.
+   */
+  restoreRatios(config: PaneConfig | SplitConfig): void {
+    if ('mode' in config) return;
+    this.adjustRatios(config.id, config.ratios);
+    for (const child of config.children) {
+      this.restoreRatios(child);
+    }
+  }
+
   // ─────────────────────────────────────────────────────────────────────────
   // Splitting
   // ─────────────────────────────────────────────────────────────────────────
@@ -352,7 +406,7 @@ export class PaneContainer implements FocusResolver {
     }
 
     // Re-layout
-    this.layoutNode(this.root!, this.bounds);
+    this.layout();
     this.callbacks.onDirty();
 
     return newPane.id;
@@ -405,7 +459,7 @@ export class PaneContainer implements FocusResolver {
       this.focusManager.focusPane(firstPane.id);
     }
 
-    this.layoutNode(this.root!, this.bounds);
+    this.layout();
     this.callbacks.onDirty();
     return true;
   }
@@ -423,7 +477,7 @@ export class PaneContainer implements FocusResolver {
     const total = ratios.reduce((a, b) => a + b, 0);
     split.ratios = ratios.map((r) => r / total);
 
-    this.layoutNode(this.root!, this.bounds);
+    this.layout();
     this.callbacks.onDirty();
     return true;
   }
@@ -441,7 +495,7 @@ export class PaneContainer implements FocusResolver {
     split.ratios.reverse();
 
     // Re-layout to apply changes
-    this.layoutNode(this.root!, this.bounds);
+    this.layout();
     this.callbacks.onDirty();
     return true;
   }
@@ -507,11 +561,30 @@ export class PaneContainer implements FocusResolver {
    * Render the pane container.
    */
   render(buffer: ScreenBuffer): void {
-    if (this.root) {
+    const zoomed = this.getZoomedPane();
+    if (zoomed) {
+      this.renderZoomMargins(zoomed, buffer);
+      zoomed.render(buffer);
+    } else if (this.root) {
       this.renderNode(this.root, buffer);
     }
   }
 
+  /**
+   * Fill the area around a zoomed pane with the editor background.
+   */
+  private renderZoomMargins(pane: Pane, buffer: ScreenBuffer): void {
+    const bg = this.callbacks.getThemeColor('editor.background', '#1e1e1e');
+    const inner = pane.getBounds();
+    const { x, y, width, height } = this.bounds;
+    for (let row = y; row < y + height; row++) {
+      for (let col = x; col < x + width; col++) {
+        const inside = col >= inner.x && col < inner.x + inner.width && row >= inner.y && row < inner.y + inner.height;
+        if (!inside) buffer.set(col, row, { char: ' ', fg: bg, bg });
+      }
+    }
+  }
+
   private renderNode(node: LayoutNode, buffer: ScreenBuffer): void {
     if (node instanceof Pane) {
       node.render(buffer);
@@ -597,6 +670,7 @@ export class PaneContainer implements FocusResolver {
     }
     this.panes.clear();
 
+    this.zoomedPaneId = null;
     this.root = this.deserializeNode(config);
     this.layoutNode(this.root, this.bounds);
   }
@@ -656,6 +730,8 @@ export class PaneContainer implements FocusResolver {
    * Find the pane at a specific position (for mouse clicks).
    */
   findPaneAtPoint(x: number, y: number): Pane | null {
+    const zoomed = this.getZoomedPane();
+    if (zoomed) return this.findPaneAtPointInNode(zoomed, x, y);
     if (!this.root) return null;
     return this.findPaneAtPointInNode(this.root, x, y);
   }
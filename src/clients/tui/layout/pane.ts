@@ -107,6 +107,25 @@ export class Pane {
   /** Tab scroll offset (number of tabs scrolled from left) */
   private tabScrollOffset = 0;
 
+  /** Whether the tab bar is hidden (zen mode) */
+  private tabBarHidden = false;
+
+  /** Preview tab, replaced by the next preview open until it is kept */
+  private previewElementId: string | null = null;
+
+  /** Pinned tabs, kept at the left of the tab bar */
+  private pinnedElementIds: Set<string> = new Set();
+
+  /** Last use of each tab, for most-recently-used ordering */
+  private lastUsed: Map<string, number> = new Map();
+
+  /** Use counter shared by all panes, so tabs can be ordered across panes */
+  private static useCounter = 0;
+
+  /** Last tab click, for double-click detection */
+  private lastTabClickId: string | null = null;
+  private lastTabClickTime = 0;
+
   /** Callback for showing tab dropdown menu */
   private onShowTabDropdown?: (tabs: Array<{ id: string; title: string; isActive: boolean }>, x: number, y: number) => void;
 
@@ -177,6 +196,7 @@ export class Pane {
         this.elements[this.activeElementIndex]?.onVisibilityChange(false);
       }
       this.activeElementIndex = this.elements.length - 1;
+      this.markActiveUsed();
     }
 
     // For accordion, expand it
@@ -203,6 +223,7 @@ export class Pane {
     element.onUnmount();
     this.elements.splice(idx, 1);
     this.expandedElementIds.delete(elementId);
+    this.forgetElement(elementId);
 
     // Adjust active index
     if (this.mode === 'tabs') {
@@ -215,6 +236,7 @@ export class Pane {
         );
         if (this.activeElementIndex >= 0) {
           this.elements[this.activeElementIndex]?.onVisibilityChange(true);
+          this.markActiveUsed();
         }
       }
     }
@@ -253,12 +275,14 @@ export class Pane {
     element.onVisibilityChange(false);
     this.elements.splice(idx, 1);
     this.expandedElementIds.delete(elementId);
+    this.forgetElement(elementId);
 
     // Adjust active index for tabs
     if (this.mode === 'tabs' && idx <= this.activeElementIndex) {
       this.activeElementIndex = Math.max(0, this.activeElementIndex - 1);
       if (this.elements[this.activeElementIndex]) {
         this.elements[this.activeElementIndex]!.onVisibilityChange(true);
+        this.markActiveUsed();
       }
     }
 
@@ -282,6 +306,7 @@ export class Pane {
         this.elements[this.activeElementIndex]?.onVisibilityChange(false);
       }
       this.activeElementIndex = this.elements.length - 1;
+      this.markActiveUsed();
     }
 
     if (this.mode === 'accordion') {
@@ -331,6 +356,9 @@ export class Pane {
     }
     this.elements = [];
     this.expandedElementIds.clear();
+    this.previewElementId = null;
+    this.pinnedElementIds.clear();
+    this.lastUsed.clear();
     this.activeElementIndex = 0;
   }
 
@@ -361,12 +389,16 @@ export class Pane {
   setActiveElement(elementId: string): boolean {
     const idx = this.elements.findIndex((e) => e.id === elementId);
     if (idx === -1) return false;
-    if (idx === this.activeElementIndex) return true;
+    if (idx === this.activeElementIndex) {
+      this.markActiveUsed();
+      return true;
+    }
 
     // Hide previous
     this.elements[this.activeElementIndex]?.onVisibilityChange(false);
 
     this.activeElementIndex = idx;
+    this.markActiveUsed();
 
     // Show new
     this.elements[idx]!.onVisibilityChange(true);
@@ -386,6 +418,7 @@ export class Pane {
     prev?.onVisibilityChange(false);
 
     this.activeElementIndex = (this.activeElementIndex + 1) % this.elements.length;
+    this.markActiveUsed();
 
     const next = this.elements[this.activeElementIndex]!;
     next.onVisibilityChange(true);
@@ -405,6 +438,7 @@ export class Pane {
 
     this.activeElementIndex =
       (this.activeElementIndex - 1 + this.elements.length) % this.elements.length;
+    this.markActiveUsed();
 
     const next = this.elements[this.activeElementIndex]!;
     next.onVisibilityChange(true);
@@ -515,6 +549,133 @@ export class Pane {
     }));
   }
 
+  /**
+   * Move a tab to a new index.
+   */
+  moveElement(elementId: string, index: number): boolean {
+    const from = this.elements.findIndex((e) => e.id === elementId);
+    if (from === -1) return false;
+
+    const active = this.elements[this.activeElementIndex];
+    const [element] = this.elements.splice(from, 1);
+    const to = Math.max(0, Math.min(index, this.elements.length));
+    this.elements.splice(to, 0, element!);
+    if (active) {
+      this.activeElementIndex = this.elements.indexOf(active);
+    }
+
+    this.ensureActiveTabVisible();
+    this.markDirty();
+    return true;
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Preview, Pinned and Recent Tabs
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /**
+   * Mark a tab as the pane's preview tab (null for none). Pinned tabs
+   * can't be previews.
+   */
+  setPreviewElement(elementId: string | null): void {
+    if (elementId !== null && (!this.hasElement(elementId) || this.pinnedElementIds.has(elementId))) {
+      return;
+    }
+    if (this.previewElementId === elementId) return;
+    this.previewElementId = elementId;
+    this.markDirty();
+  }
+
+  /**
+   * Get the preview tab's element ID.
+   */
+  getPreviewElementId(): string | null {
+    return this.previewElementId;
+  }
+
+  /**
+   * Turn a preview tab into a permanent one.
+   * @returns true if the tab was a preview
+   */
+  keepElement(elementId: string): boolean {
+    if (this.previewElementId !== elementId) return false;
+    this.previewElementId = null;
+    this.markDirty();
+    return true;
+  }
+
+  /**
+   * Pin a tab. It moves after the other pinned tabs and stops being a
+   * preview.
+   */
+  pinElement(elementId: string): boolean {
+    if (!this.hasElement(elementId)) return false;
+    if (this.pinnedElementIds.has(elementId)) return true;
+
+    this.keepElement(elementId);
+    this.moveElement(elementId, this.pinnedElementIds.size);
+    this.pinnedElementIds.add(elementId);
+    this.markDirty();
+    return true;
+  }
+
+  /**
+   * Unpin a tab. It moves to the first unpinned position.
+   */
+  unpinElement(elementId: string): boolean {
+    if (!this.pinnedElementIds.delete(elementId)) return false;
+    this.moveElement(elementId, this.pinnedElementIds.size);
+    this.markDirty();
+    return true;
+  }
+
+  /**
+   * Check if a tab is pinned.
+   */
+  isElementPinned(elementId: string): boolean {
+    return this.pinnedElementIds.has(elementId);
+  }
+
+  /**
+   * Record a tab as just used. Called when a tab is activated and when
+   * focus moves into it.
+   */
+  markElementUsed(elementId: string): void {
+    if (this.hasElement(elementId)) {
+      this.lastUsed.set(elementId, ++Pane.useCounter);
+    }
+  }
+
+  /**
+   * Get when a tab was last used, comparable across panes (0 if never).
+   */
+  getElementLastUsed(elementId: string): number {
+    return this.lastUsed.get(elementId) ?? 0;
+  }
+
+  /**
+   * Get elements ordered by most recent use. Never-used tabs keep tab
+   * order at the end.
+   */
+  getElementsByRecentUse(): BaseElement[] {
+    return [...this.elements].sort((a, b) => this.getElementLastUsed(b.id) - this.getElementLastUsed(a.id));
+  }
+
+  private markActiveUsed(): void {
+    const active = this.elements[this.activeElementIndex];
+    if (this.mode === 'tabs' && active) {
+      this.markElementUsed(active.id);
+    }
+  }
+
+  private forgetElement(elementId: string): void {
+    if (this.previewElementId === elementId) {
+      this.previewElementId = null;
+    }
+    this.pinnedElementIds.delete(elementId);
+    this.lastUsed.delete(elementId);
+  }
+
   // ─────────────────────────────────────────────────────────────────────────
   // Accordion Operations
   // ─────────────────────────────────────────────────────────────────────────
@@ -576,6 +737,24 @@ export class Pane {
   // Layout
   // ─────────────────────────────────────────────────────────────────────────
 
+  /**
+   * Hide or show the tab bar. Content takes its row while hidden.
+   */
+  setTabBarHidden(hidden: boolean): void {
+    if (this.tabBarHidden === hidden) return;
+    this.tabBarHidden = hidden;
+    this.layoutElements();
+    this.markDirty();
+  }
+
+  isTabBarHidden(): boolean {
+    return this.tabBarHidden;
+  }
+
+  private getTabBarHeight(): number {
+    return this.tabBarHidden ? 0 : Pane.TAB_BAR_HEIGHT;
+  }
+
   /**
    * Set pane bounds.
    */
@@ -598,9 +777,9 @@ export class Pane {
     if (this.mode === 'tabs') {
       return {
         x: this.bounds.x,
-        y: this.bounds.y + Pane.TAB_BAR_HEIGHT,
+        y: this.bounds.y + this.getTabBarHeight(),
         width: this.bounds.width,
-        height: Math.max(0, this.bounds.height - Pane.TAB_BAR_HEIGHT),
+        height: Math.max(0, this.bounds.height - this.getTabBarHeight()),
       };
     }
     // Accordion content bounds depend on which sections are expanded
@@ -672,7 +851,9 @@ export class Pane {
   }
 
   private renderTabs(buffer: ScreenBuffer): void {
-    this.renderTabBar(buffer);
+    if (!this.tabBarHidden) {
+      this.renderTabBar(buffer);
+    }
 
     // Render active element only
     const activeElement = this.getActiveElement();
@@ -690,8 +871,8 @@ export class Pane {
     const fg = this.callbacks.getForegroundForFocus('editor', isPaneFocused);
 
     // Fill content area with background
-    const contentY = this.bounds.y + Pane.TAB_BAR_HEIGHT;
-    const contentHeight = this.bounds.height - Pane.TAB_BAR_HEIGHT;
+    const contentY = this.bounds.y + this.getTabBarHeight();
+    const contentHeight = this.bounds.height - this.getTabBarHeight();
 
     for (let y = contentY; y < contentY + contentHeight; y++) {
       for (let x = this.bounds.x; x < this.bounds.x + this.bounds.width; x++) {
@@ -799,12 +980,24 @@ export class Pane {
       }
       x += 1;
 
-      // Draw title and close button
-      const restContent = `${title} × `;
-      for (let j = 0; j < restContent.length; j++) {
-        buffer.set(x + j, y, { char: restContent[j]!, fg, bg });
+      // Draw title (italic for the preview tab)
+      const italic = element.id === this.previewElementId;
+      for (let j = 0; j < title.length; j++) {
+        buffer.set(x + j, y, { char: title[j]!, fg, bg, italic });
       }
-      x += restContent.length;
+      x += title.length;
+
+      // Draw close button, or the pin for pinned tabs
+      if (this.pinnedElementIds.has(element.id)) {
+        buffer.set(x, y, { char: ' ', fg, bg });
+        buffer.set(x + 1, y, { char: '📌', fg, bg });
+        buffer.set(x + 2, y, { char: '', fg, bg });
+      } else {
+        buffer.set(x, y, { char: ' ', fg, bg });
+        buffer.set(x + 1, y, { char: '×', fg, bg });
+        buffer.set(x + 2, y, { char: ' ', fg, bg });
+      }
+      x += 3;
 
       // Separator
       if (i < visibleEndIdx && i < this.elements.length - 1) {
@@ -933,7 +1126,7 @@ export class Pane {
    */
   private handleTabBarClick(event: { x: number; y: number }): boolean {
     // Tab bar is at the top row of the pane
-    if (event.y !== this.bounds.y) return false;
+    if (this.tabBarHidden || event.y !== this.bounds.y) return false;
 
     // Calculate layout info (same as renderTabBar)
     const tabWidths = this.elements.map((el) => {
@@ -1001,9 +1194,22 @@ export class Pane {
         // Check if click is on the close button (last 2 characters: "× ")
         const closeButtonStart = x + tabWidth - 2;
         if (event.x >= closeButtonStart) {
-          // Close this tab - use async callback if provided
-          this.requestElementClose(element);
+          if (this.pinnedElementIds.has(element.id)) {
+            // Pinned tabs show a pin instead; clicking it unpins
+            this.unpinElement(element.id);
+          } else {
+            // Close this tab - use async callback if provided
+            this.requestElementClose(element);
+          }
         } else {
+          // Double-click keeps a preview tab
+          const now = Date.now();
+          if (element.id === this.lastTabClickId && now - this.lastTabClickTime < 300) {
+            this.keepElement(element.id);
+          }
+          this.lastTabClickId = element.id;
+          this.lastTabClickTime = now;
+
           // Switch to this tab and focus the element
           this.setActiveElement(element.id);
           this.callbacks.onFocusRequest(element.id);
@@ -1075,6 +1281,8 @@ export class Pane {
         this.mode === 'tabs' ? this.elements[this.activeElementIndex]?.id : undefined,
       expandedElementIds:
         this.mode === 'accordion' ? Array.from(this.expandedElementIds) : undefined,
+      previewElementId: this.previewElementId ?? undefined,
+      pinnedElementIds: this.pinnedElementIds.size > 0 ? Array.from(this.pinnedElementIds) : undefined,
     };
   }
 
@@ -1107,11 +1315,19 @@ export class Pane {
         this.activeElementIndex = idx;
       }
     }
+    this.markActiveUsed();
 
     if (config.expandedElementIds) {
       this.expandedElementIds = new Set(config.expandedElementIds);
     }
 
+    for (const id of config.pinnedElementIds ?? []) {
+      if (this.hasElement(id)) this.pinnedElementIds.add(id);
+    }
+    if (config.previewElementId) {
+      this.setPreviewElement(config.previewElementId);
+    }
+
     // Layout and set visibility
     this.layoutElements();
 
@@ -1170,6 +1386,8 @@ export class Pane {
       SQLEditor: 'Query',
       QueryResults: 'Results',
       RowDetailsPanel: 'Row Details',
+      LSPTrace: 'LSP Trace',
+      LSPServerNotFound: 'LSP Server',
     };
     return titles[type] ?? type;
   }
@@ -2,7 +2,9 @@
  * Autocomplete Popup
  *
  * Displays LSP completion suggestions near the cursor.
- * Supports filtering, navigation, and selection.
+ * Supports filtering, navigation, and selection. A documentation panel
+ * beside the list shows the selected item's detail and docs, resolved
+ * lazily through completionItem/resolve.
  */
 
 import type { Overlay, OverlayManagerCallbacks } from './overlay-manager.ts';
@@ -11,6 +13,8 @@ import { isKeyEvent, isMouseEvent } from '../types.ts';
 import type { ScreenBuffer } from '../rendering/buffer.ts';
 import type { LSPCompletionItem } from '../../../services/lsp/types.ts';
 import { CompletionItemKind } from '../../../services/lsp/types.ts';
+import { renderDocumentation, type MarkdownLine } from './markdown-lines.ts';
+import { t } from '../i18n/index.ts';
 
 // ============================================
 // Types
@@ -39,6 +43,11 @@ export type CompletionSelectCallback = (item: LSPCompletionItem, prefix: string,
  */
 export type CompletionDismissCallback = () => void;
 
+/**
+ * Resolves lazily computed item details (documentation, detail, edits).
+ */
+export type CompletionResolver = (item: LSPCompletionItem) => Promise<LSPCompletionItem>;
+
 // ============================================
 // Completion Kind Icons
 // ============================================
@@ -72,6 +81,14 @@ const COMPLETION_KIND_TEXT: Record<number, string> = {
   [CompletionItemKind.TypeParameter]: 'typ',
 };
 
+/** Spoken names for completion kinds ("EnumMember" -> "enum member") */
+const COMPLETION_KIND_NAMES: Record<number, string> = Object.fromEntries(
+  Object.entries(CompletionItemKind).map(([name, kind]) => [
+    kind,
+    name.replace(/([a-z])([A-Z])/g, '$1 $2').toLowerCase(),
+  ])
+);
+
 // ============================================
 // Autocomplete Popup
 // ============================================
@@ -92,6 +109,10 @@ export class AutocompletePopup implements Overlay {
   private maxVisibleItems = 10;
   /** Popup width in characters */
   private popupWidth = 50;
+  /** Documentation panel width in characters */
+  private docsWidth = 50;
+  /** Maximum documentation panel height */
+  private maxDocsHeight = 16;
 
   /** Current prefix being typed */
   private prefix = '';
@@ -103,6 +124,12 @@ export class AutocompletePopup implements Overlay {
   /** Popup bounds */
   private bounds: Rect = { x: 0, y: 0, width: 0, height: 0 };
 
+  /** Documentation panel */
+  private showDocs = true;
+  private resolver: CompletionResolver | null = null;
+  /** Items already resolved (or being resolved) */
+  private resolving = new WeakSet<LSPCompletionItem>();
+
   /** Callbacks */
   private callbacks: OverlayManagerCallbacks;
   private onSelectCallback: CompletionSelectCallback | null = null;
@@ -153,6 +180,7 @@ export class AutocompletePopup implements Overlay {
 
     // Calculate bounds
     this.calculateBounds(x, y);
+    this.resolveSelected();
     this.callbacks.onDirty();
   }
 
@@ -166,6 +194,7 @@ export class AutocompletePopup implements Overlay {
     if (this.items.length === 0) {
       this.hide();
     } else {
+      this.resolveSelected();
       this.callbacks.onDirty();
     }
   }
@@ -198,6 +227,21 @@ export class AutocompletePopup implements Overlay {
     this.onDismissCallback = callback;
   }
 
+  /**
+   * Set the resolver used to fill in documentation for the selected item.
+   */
+  setResolver(resolver: CompletionResolver | null): void {
+    this.resolver = resolver;
+  }
+
+  /**
+   * Enable or disable the documentation panel.
+   */
+  setShowDocumentation(show: boolean): void {
+    this.showDocs = show;
+    this.callbacks.onDirty();
+  }
+
   /**
    * Accept the currently selected item.
    */
@@ -221,6 +265,17 @@ export class AutocompletePopup implements Overlay {
     return null;
   }
 
+  /**
+   * The selected item for screen readers: label, kind, detail and position.
+   */
+  getAccessibleText(): string {
+    const item = this.getSelectedItem();
+    if (!item) return '';
+    const kind = item.kind !== undefined ? COMPLETION_KIND_NAMES[item.kind] : undefined;
+    const parts = [item.label, kind, item.detail?.split('\n')[0]].filter(Boolean);
+    return t('common.itemPosition', { item: parts.join(', '), index: this.selectedIndex + 1, count: this.items.length });
+  }
+
   // ─────────────────────────────────────────────────────────────────────────
   // Overlay Interface
   // ─────────────────────────────────────────────────────────────────────────
@@ -351,6 +406,108 @@ export class AutocompletePopup implements Overlay {
     if (this.items.length > this.maxVisibleItems) {
       this.renderScrollbar(buffer, x + width - 1, y + 1, height - 2);
     }
+
+    if (this.showDocs) {
+      this.renderDocs(buffer);
+    }
+  }
+
+  /**
+   * Draw the documentation panel beside the list.
+   */
+  private renderDocs(buffer: ScreenBuffer): void {
+    const item = this.getSelectedItem();
+    const docsBounds = item ? this.getDocsBounds(item) : null;
+    if (!item || !docsBounds) return;
+
+    const { x, y, width, height } = docsBounds;
+    const bgColor = this.callbacks.getThemeColor('editorSuggestWidget.background', '#252526');
+    const fgColor = this.callbacks.getThemeColor('editorSuggestWidget.foreground', '#bbbbbb');
+    const borderColor = this.callbacks.getThemeColor('editorSuggestWidget.border', '#454545');
+    const codeColor = this.callbacks.getThemeColor('textPreformat.foreground', '#d7ba7d');
+    const headingColor = this.callbacks.getThemeColor('editorSuggestWidget.highlightForeground', '#18a3ff');
+    const dimColor = this.callbacks.getThemeColor('descriptionForeground', '#717171');
+
+    const colors: Record<MarkdownLine['kind'], string> = {
+      text: fgColor,
+      code: codeColor,
+      heading: headingColor,
+      quote: dimColor,
+      rule: borderColor,
+    };
+
+    buffer.writeString(x, y, '┌' + '─'.repeat(width - 2) + '┐', borderColor, bgColor);
+    buffer.writeString(x, y + height - 1, '└' + '─'.repeat(width - 2) + '┘', borderColor, bgColor);
+
+    const lines = this.getDocLines(item, width - 4);
+    for (let row = 0; row < height - 2; row++) {
+      const line = lines[row];
+      const rowY = y + 1 + row;
+      buffer.writeString(x, rowY, '│', borderColor, bgColor);
+      buffer.writeString(x + 1, rowY, ' ' + (line?.text ?? '').padEnd(width - 3, ' '), line ? colors[line.kind] : fgColor, bgColor);
+      buffer.writeString(x + width - 1, rowY, '│', borderColor, bgColor);
+    }
+  }
+
+  /**
+   * Documentation lines for an item: detail first, then documentation.
+   */
+  getDocLines(item: LSPCompletionItem, width: number): MarkdownLine[] {
+    const lines: MarkdownLine[] = [];
+    if (item.detail) {
+      for (const detailLine of item.detail.split('\n')) {
+        lines.push({ text: detailLine.length > width ? detailLine.slice(0, width - 1) + '…' : detailLine, kind: 'code' });
+      }
+    }
+    const docs = renderDocumentation(item.documentation, width);
+    if (lines.length > 0 && docs.length > 0) {
+      lines.push({ text: '', kind: 'text' });
+    }
+    lines.push(...docs);
+    return lines;
+  }
+
+  /**
+   * Bounds of the documentation panel: right of the list, else left of it.
+   * Null when there's nothing to show or no room.
+   */
+  private getDocsBounds(item: LSPCompletionItem): Rect | null {
+    const screen = this.callbacks.getScreenSize();
+    const lines = this.getDocLines(item, this.docsWidth - 4);
+    if (lines.length === 0) return null;
+
+    const width = this.docsWidth;
+    let x: number;
+    if (this.bounds.x + this.bounds.width + width <= screen.width) {
+      x = this.bounds.x + this.bounds.width;
+    } else if (this.bounds.x >= width) {
+      x = this.bounds.x - width;
+    } else {
+      return null;
+    }
+
+    const height = Math.min(lines.length + 2, Math.max(this.maxDocsHeight, this.bounds.height));
+    const y = Math.max(0, Math.min(this.bounds.y, screen.height - height));
+    return { x, y, width, height };
+  }
+
+  /**
+   * Resolve the selected item in the background and redraw when done.
+   */
+  private resolveSelected(): void {
+    const item = this.getSelectedItem();
+    if (!item || !this.resolver || this.resolving.has(item)) return;
+
+    this.resolving.add(item);
+    this.resolver(item).then((resolved) => {
+      if (resolved === item) return;
+      this.resolving.add(resolved);
+      this.allItems = this.allItems.map((i) => (i === item ? resolved : i));
+      this.items = this.items.map((i) => (i === item ? resolved : i));
+      if (this.visible) this.callbacks.onDirty();
+    }).catch(() => {
+      // Keep the unresolved item; the list still works without docs
+    });
   }
 
   private renderScrollbar(buffer: ScreenBuffer, x: number, y: number, height: number): void {
@@ -437,9 +594,21 @@ export class AutocompletePopup implements Overlay {
         this.hide();
         return true;
 
-      default:
+      default: {
+        // Commit characters accept the item; the editor then types the character
+        const item = this.getSelectedItem();
+        if (
+          item?.commitCharacters?.includes(event.key) &&
+          event.key.length === 1 &&
+          !event.ctrl &&
+          !event.alt &&
+          !event.meta
+        ) {
+          this.acceptSelected();
+        }
         // Don't consume other keys - let them be handled by editor
         return false;
+      }
     }
   }
 
@@ -478,6 +647,7 @@ export class AutocompletePopup implements Overlay {
         this.scrollOffset = this.selectedIndex - this.maxVisibleItems + 1;
       }
 
+      this.resolveSelected();
       this.callbacks.onDirty();
     }
   }
@@ -495,7 +665,7 @@ export class AutocompletePopup implements Overlay {
       // Filter items that match the prefix
       this.items = this.allItems.filter((item) => {
         const label = item.label.toLowerCase();
-        const filterText = (item.insertText || item.label).toLowerCase();
+        const filterText = (item.filterText || item.insertText || item.label).toLowerCase();
         return (
           label.startsWith(lowerPrefix) ||
           filterText.startsWith(lowerPrefix) ||
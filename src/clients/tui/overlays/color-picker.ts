@@ -0,0 +1,313 @@
+/**
+ * Color Picker
+ *
+ * Small inline overlay for editing a colour value reported by a language
+ * server (documentColor). Channels are adjusted with the keyboard or by
+ * clicking a channel bar; Tab cycles the server's presentation formats.
+ *
+ * Keys:
+ * - Up/Down: select channel
+ * - Left/Right: adjust by 1 (Shift: by 16)
+ * - Tab: next presentation format
+ * - Enter: apply, Escape: cancel
+ */
+
+import type { Overlay, OverlayManagerCallbacks } from './overlay-manager.ts';
+import type { Rect, InputEvent } from '../types.ts';
+import { isKeyEvent, isMouseEvent } from '../types.ts';
+import type { ScreenBuffer } from '../rendering/buffer.ts';
+import type { LSPColor } from '../../../services/lsp/types.ts';
+import { rgbToHex, isLightColor } from '../../../core/colors.ts';
+import { t } from '../i18n/index.ts';
+
+// ============================================
+// Types
+// ============================================
+
+/**
+ * Called when the user applies a colour, with the chosen format index.
+ */
+export type ColorPickerCommitCallback = (color: LSPColor, formatIndex: number) => void;
+
+const CHANNELS = ['R', 'G', 'B', 'A'] as const;
+
+/** Width of a channel bar in cells */
+const BAR_WIDTH = 20;
+
+// ============================================
+// Color Picker
+// ============================================
+
+export class ColorPicker implements Overlay {
+  readonly id: string;
+  zIndex = 260; // Above hover, below signature help
+
+  private visible = false;
+  private bounds: Rect = { x: 0, y: 0, width: 0, height: 0 };
+  private callbacks: OverlayManagerCallbacks;
+
+  /** Channel values 0-255 (alpha included) */
+  private values: [number, number, number, number] = [0, 0, 0, 255];
+  private selectedChannel = 0;
+
+  /** Presentation labels from the server (e.g. "#ff0000", "rgb(255, 0, 0)") */
+  private formats: string[] = [];
+  private formatIndex = 0;
+
+  private onCommit: ColorPickerCommitCallback | null = null;
+
+  constructor(id: string, callbacks: OverlayManagerCallbacks) {
+    this.id = id;
+    this.callbacks = callbacks;
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Public API
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /**
+   * Show the picker for a colour below the given screen position.
+   */
+  showPicker(color: LSPColor, x: number, y: number, onCommit: ColorPickerCommitCallback): void {
+    this.values = [
+      toByte(color.red),
+      toByte(color.green),
+      toByte(color.blue),
+      toByte(color.alpha),
+    ];
+    this.selectedChannel = 0;
+    this.formats = [];
+    this.formatIndex = 0;
+    this.onCommit = onCommit;
+    this.visible = true;
+    this.calculateBounds(x, y);
+    this.callbacks.onDirty();
+  }
+
+  /**
+   * Set the available presentation formats (labels for the original colour).
+   */
+  setFormats(formats: string[]): void {
+    this.formats = formats;
+    this.formatIndex = Math.min(this.formatIndex, Math.max(0, formats.length - 1));
+    this.callbacks.onDirty();
+  }
+
+  getColor(): LSPColor {
+    const [r, g, b, a] = this.values;
+    return { red: r / 255, green: g / 255, blue: b / 255, alpha: a / 255 };
+  }
+
+  getHex(): string {
+    return rgbToHex({ r: this.values[0], g: this.values[1], b: this.values[2] });
+  }
+
+  getSelectedChannel(): number {
+    return this.selectedChannel;
+  }
+
+  getFormatIndex(): number {
+    return this.formatIndex;
+  }
+
+  /**
+   * Adjust the selected channel by a delta (clamped to 0-255).
+   */
+  adjust(delta: number): void {
+    const i = this.selectedChannel;
+    this.values[i] = Math.max(0, Math.min(255, this.values[i]! + delta));
+    this.callbacks.onDirty();
+  }
+
+  /**
+   * Apply the current colour and close.
+   */
+  commit(): void {
+    const onCommit = this.onCommit;
+    const color = this.getColor();
+    const formatIndex = this.formatIndex;
+    this.hide();
+    onCommit?.(color, formatIndex);
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Overlay Interface
+  // ─────────────────────────────────────────────────────────────────────────
+
+  isVisible(): boolean {
+    return this.visible;
+  }
+
+  show(): void {
+    this.visible = true;
+    this.callbacks.onDirty();
+  }
+
+  hide(): void {
+    if (this.visible) {
+      this.visible = false;
+      this.onCommit = null;
+      this.callbacks.onDirty();
+    }
+  }
+
+  setBounds(bounds: Rect): void {
+    this.bounds = bounds;
+  }
+
+  getBounds(): Rect {
+    return this.bounds;
+  }
+
+  onDismiss(): void {
+    this.hide();
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Rendering
+  // ─────────────────────────────────────────────────────────────────────────
+
+  render(buffer: ScreenBuffer): void {
+    if (!this.visible) return;
+
+    const { x, y, width, height } = this.bounds;
+    const bg = this.callbacks.getThemeColor('editorWidget.background', '#252526');
+    const fg = this.callbacks.getThemeColor('editorWidget.foreground', '#cccccc');
+    const border = this.callbacks.getThemeColor('editorWidget.border', '#454545');
+    const dim = this.callbacks.getThemeColor('descriptionForeground', '#888888');
+    const accent = this.callbacks.getThemeColor('focusBorder', '#007fd4');
+
+    // Border and background
+    const inner = width - 2;
+    buffer.writeString(x, y, `┌${'─'.repeat(inner)}┐`, border, bg);
+    for (let row = 1; row < height - 1; row++) {
+      buffer.writeString(x, y + row, '│', border, bg);
+      buffer.writeString(x + 1, y + row, ' '.repeat(inner), fg, bg);
+      buffer.writeString(x + width - 1, y + row, '│', border, bg);
+    }
+    buffer.writeString(x, y + height - 1, `└${'─'.repeat(inner)}┘`, border, bg);
+    buffer.writeString(x + 2, y, ` ${t('colorPicker.title')} `, fg, bg);
+
+    // Swatch and value
+    const hex = this.getHex();
+    const swatchFg = isLightColor(hex) ? '#000000' : '#ffffff';
+    const alpha = Math.round((this.values[3] / 255) * 100);
+    buffer.writeString(x + 2, y + 1, `  ${hex}  `, swatchFg, hex);
+    buffer.writeString(x + 15, y + 1, `${alpha}%`, dim, bg);
+
+    // Channel bars
+    CHANNELS.forEach((name, i) => {
+      const rowY = y + 2 + i;
+      const selected = i === this.selectedChannel;
+      const value = this.values[i]!;
+      const filled = Math.round((value / 255) * BAR_WIDTH);
+      buffer.writeString(x + 2, rowY, `${selected ? '▸' : ' '} ${name} `, selected ? accent : fg, bg);
+      buffer.writeString(x + 6, rowY, '█'.repeat(filled), selected ? accent : fg, bg);
+      buffer.writeString(x + 6 + filled, rowY, '░'.repeat(BAR_WIDTH - filled), dim, bg);
+      buffer.writeString(x + 7 + BAR_WIDTH, rowY, String(value).padStart(3, ' '), fg, bg);
+    });
+
+    // Presentation format
+    const format = this.formats[this.formatIndex];
+    const formatText = format
+      ? `${format}${this.formats.length > 1 ? `  (Tab ${this.formatIndex + 1}/${this.formats.length})` : ''}`
+      : t('colorPicker.hint');
+    buffer.writeString(x + 2, y + 6, truncate(formatText, inner - 2), dim, bg);
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Input Handling
+  // ─────────────────────────────────────────────────────────────────────────
+
+  handleInput(event: InputEvent): boolean {
+    if (!this.visible) return false;
+
+    if (isKeyEvent(event)) {
+      switch (event.key) {
+        case 'Escape':
+          this.hide();
+          return true;
+        case 'Enter':
+          this.commit();
+          return true;
+        case 'ArrowUp':
+          this.selectedChannel = (this.selectedChannel + CHANNELS.length - 1) % CHANNELS.length;
+          this.callbacks.onDirty();
+          return true;
+        case 'ArrowDown':
+          this.selectedChannel = (this.selectedChannel + 1) % CHANNELS.length;
+          this.callbacks.onDirty();
+          return true;
+        case 'ArrowLeft':
+          this.adjust(event.shift ? -16 : -1);
+          return true;
+        case 'ArrowRight':
+          this.adjust(event.shift ? 16 : 1);
+          return true;
+        case 'Tab':
+          if (this.formats.length > 0) {
+            this.formatIndex = (this.formatIndex + 1) % this.formats.length;
+            this.callbacks.onDirty();
+          }
+          return true;
+      }
+      // Swallow other keys so typing doesn't edit the colour underneath
+      return true;
+    }
+
+    if (isMouseEvent(event) && event.type === 'press') {
+      const { x, y, width, height } = this.bounds;
+      if (event.x < x || event.x >= x + width || event.y < y || event.y >= y + height) {
+        this.hide();
+        return false;
+      }
+
+      // Click on a channel bar sets its value
+      const channel = event.y - (y + 2);
+      const barX = event.x - (x + 6);
+      if (channel >= 0 && channel < CHANNELS.length && barX >= 0 && barX < BAR_WIDTH) {
+        this.selectedChannel = channel;
+        this.values[channel] = Math.round(((barX + 1) / BAR_WIDTH) * 255);
+        this.callbacks.onDirty();
+      }
+      return true;
+    }
+
+    return false;
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Positioning
+  // ─────────────────────────────────────────────────────────────────────────
+
+  private calculateBounds(anchorX: number, anchorY: number): void {
+    const screen = this.callbacks.getScreenSize();
+    const width = BAR_WIDTH + 13;
+    const height = 8;
+
+    let x = Math.min(anchorX, Math.max(0, screen.width - width));
+    let y = anchorY + 1;
+    if (y + height > screen.height) {
+      // Not enough room below the colour: open above it
+      y = Math.max(0, anchorY - height);
+    }
+    x = Math.max(0, x);
+
+    this.bounds = { x, y, width, height };
+  }
+}
+
+function toByte(channel: number): number {
+  return Math.max(0, Math.min(255, Math.round(channel * 255)));
+}
+
+function truncate(text: string, width: number): string {
+  return text.length > width ? text.slice(0, Math.max(0, width - 1)) + '…' : text;
+}
+
+/**
+ * Create a color picker instance.
+ */
+export function createColorPicker(id: string, callbacks: OverlayManagerCallbacks): ColorPicker {
+  return new ColorPicker(id, callbacks);
+}
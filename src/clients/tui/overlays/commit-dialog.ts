@@ -9,6 +9,7 @@ import { PromiseDialog, type DialogConfig, type DialogResult } from './promise-d
 import type { OverlayManagerCallbacks } from './overlay-manager.ts';
 import type { KeyEvent, MouseEvent, InputEvent } from '../types.ts';
 import type { ScreenBuffer } from '../rendering/buffer.ts';
+import { t } from '../i18n/index.ts';
 
 // ============================================
 // Types
@@ -42,6 +43,9 @@ export interface CommitResult {
   amend?: boolean;
 }
 
+/** Conventional commit types (not translated) */
+const CONVENTIONAL_TYPES = ['feat', 'fix', 'docs', 'style', 'refactor', 'test', 'chore'];
+
 // ============================================
 // Commit Dialog
 // ============================================
@@ -104,7 +108,7 @@ export class CommitDialog extends PromiseDialog<CommitResult> {
     const totalHeight = 4 + stagedHeight + hintsHeight + messageHeight;
 
     return this.showAsync({
-      title: options.title ?? 'Git Commit',
+      title: options.title ?? t('commit.title'),
       width: options.width ?? 70,
       height: options.height ?? totalHeight,
       ...options,
@@ -386,7 +390,7 @@ export class CommitDialog extends PromiseDialog<CommitResult> {
 
     // Staged files section
     if (this.stagedFiles.length > 0) {
-      buffer.writeString(content.x, y, 'Staged Changes:', dimFg, bg);
+      buffer.writeString(content.x, y, t('commit.stagedChanges'), dimFg, bg);
       y++;
 
       const maxFiles = Math.min(4, this.stagedFiles.length);
@@ -401,7 +405,7 @@ export class CommitDialog extends PromiseDialog<CommitResult> {
       }
 
       if (this.stagedFiles.length > maxFiles) {
-        buffer.writeString(content.x + 2, y, `... and ${this.stagedFiles.length - maxFiles} more`, dimFg, bg);
+        buffer.writeString(content.x + 2, y, t('commit.moreFiles', { count: this.stagedFiles.length - maxFiles }), dimFg, bg);
         y++;
       }
       y++;
@@ -409,13 +413,13 @@ export class CommitDialog extends PromiseDialog<CommitResult> {
 
     // Conventional commit hints
     if (this.showConventionalHints) {
-      const hints = 'Types: feat fix docs style refactor test chore';
+      const hints = t('commit.types', { types: CONVENTIONAL_TYPES.join(' ') });
       buffer.writeString(content.x, y, hints, dimFg, bg);
       y += 2;
     }
 
     // Message label with amend indicator
-    const labelText = this.amendMode ? 'Message (amending):' : 'Message:';
+    const labelText = this.amendMode ? t('commit.messageAmending') : t('commit.message');
     const amendColor = this.amendMode ? this.callbacks.getThemeColor('editorWarning.foreground', '#cca700') : dimFg;
     buffer.writeString(content.x, y, labelText, amendColor, bg);
     y++;
@@ -459,7 +463,7 @@ export class CommitDialog extends PromiseDialog<CommitResult> {
     y += this.maxVisibleLines + 1;
 
     // Footer with instructions
-    const footer = 'Ctrl+Enter: commit  |  Ctrl+A: toggle amend  |  Escape: cancel';
+    const footer = t('commit.footer');
     const footerTruncated = footer.slice(0, content.width);
     buffer.writeString(content.x, y, footerTruncated, dimFg, bg);
   }
@@ -8,6 +8,7 @@ import { PromiseDialog, type DialogConfig, type DialogResult } from './promise-d
 import type { OverlayManagerCallbacks } from './overlay-manager.ts';
 import type { KeyEvent } from '../types.ts';
 import type { ScreenBuffer } from '../rendering/buffer.ts';
+import { t } from '../i18n/index.ts';
 
 // ============================================
 // Types
@@ -45,13 +46,13 @@ export class ConfirmDialog extends PromiseDialog<boolean> {
   private message: string = '';
 
   /** Confirm button text */
-  private confirmText: string = 'Yes';
+  private confirmText: string = t('common.yes');
 
   /** Decline button text */
-  private declineText: string = 'No';
+  private declineText: string = t('common.no');
 
   /** Cancel button text */
-  private cancelText: string = 'Cancel';
+  private cancelText: string = t('common.cancel');
 
   /** Whether to show cancel button */
   private showCancel: boolean = false;
@@ -75,9 +76,9 @@ export class ConfirmDialog extends PromiseDialog<boolean> {
    */
   showWithOptions(options: ConfirmDialogOptions): Promise<DialogResult<boolean>> {
     this.message = options.message;
-    this.confirmText = options.confirmText ?? 'Yes';
-    this.declineText = options.declineText ?? 'No';
-    this.cancelText = options.cancelText ?? 'Cancel';
+    this.confirmText = options.confirmText ?? t('common.yes');
+    this.declineText = options.declineText ?? t('common.no');
+    this.cancelText = options.cancelText ?? t('common.cancel');
     this.showCancel = options.showCancel ?? false;
     this.destructive = options.destructive ?? false;
     this.focusedButton = options.defaultButton ?? 'decline';
@@ -87,7 +88,7 @@ export class ConfirmDialog extends PromiseDialog<boolean> {
     const height = Math.max(7, lines + 5);
 
     return this.showAsync({
-      title: options.title ?? 'Confirm',
+      title: options.title ?? t('confirm.title'),
       width: options.width ?? 50,
       height: options.height ?? height,
       ...options,
@@ -235,8 +236,8 @@ export class ConfirmDialog extends PromiseDialog<boolean> {
   private renderHints(buffer: ScreenBuffer, x: number, y: number, width: number, bg: string): void {
     const hintFg = this.callbacks.getThemeColor('descriptionForeground', '#888888');
     const hint = this.showCancel
-      ? 'Tab/Arrow: navigate • Enter: select • Esc: cancel'
-      : 'Tab/Arrow: navigate • Enter: select';
+      ? t('confirm.hintWithCancel')
+      : t('confirm.hint');
     const hintX = x + Math.floor((width - hint.length) / 2);
     buffer.writeString(hintX, y, hint, hintFg, bg);
   }
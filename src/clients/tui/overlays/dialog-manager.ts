@@ -16,6 +16,8 @@ import { GotoLineDialog, type GotoLineResult } from './goto-line-dialog.ts';
 import { CommitDialog, type CommitDialogOptions, type CommitResult, type StagedFile } from './commit-dialog.ts';
 import { SettingsDialog, type SettingsDialogOptions, type SettingItem } from './settings-dialog.ts';
 import { KeybindingsDialog, type KeybindingsDialogOptions, type KeybindingItem } from './keybindings-dialog.ts';
+import { NotificationCenterDialog } from './notification-center.ts';
+import { t } from '../i18n/index.ts';
 
 // ============================================
 // Types
@@ -125,6 +127,7 @@ export class DialogManager {
   private commitDialog: CommitDialog | null = null;
   private settingsDialog: SettingsDialog | null = null;
   private keybindingsDialog: KeybindingsDialog | null = null;
+  private notificationCenterDialog: NotificationCenterDialog | null = null;
 
   /** Currently active dialog ID */
   private activeDialogId: string | null = null;
@@ -217,11 +220,11 @@ export class DialogManager {
    */
   async showSaveConfirm(filename: string): Promise<DialogResult<boolean>> {
     return this.showConfirm({
-      title: 'Save Changes',
-      message: `Do you want to save the changes you made to ${filename}?\n\nYour changes will be lost if you don't save them.`,
-      confirmText: 'Save',
-      declineText: 'Discard',
-      cancelText: 'Cancel',
+      title: t('saveConfirm.title'),
+      message: t('saveConfirm.message', { filename }),
+      confirmText: t('common.save'),
+      declineText: t('saveConfirm.discard'),
+      cancelText: t('common.cancel'),
       showCancel: true,
       defaultButton: 'confirm',
     });
@@ -245,8 +248,8 @@ export class DialogManager {
     try {
       return await this.commandPaletteDialog.showWithItems(
         {
-          title: options.title ?? 'Command Palette',
-          placeholder: options.placeholder ?? 'Type a command...',
+          title: options.title ?? t('commandPalette.title'),
+          placeholder: options.placeholder ?? t('commandPalette.placeholder'),
           width: 60,
           height: 20,
         },
@@ -281,8 +284,8 @@ export class DialogManager {
     try {
       return await this.filePickerDialog.showWithItems(
         {
-          title: options.title ?? 'Quick Open',
-          placeholder: options.placeholder ?? 'Search files...',
+          title: options.title ?? t('quickOpen.title'),
+          placeholder: options.placeholder ?? t('quickOpen.placeholder'),
           width: 70,
           height: 20,
         },
@@ -322,8 +325,8 @@ export class DialogManager {
     try {
       return await this.symbolPickerDialog.showWithItems(
         {
-          title: options.title ?? 'Go to Symbol',
-          placeholder: options.placeholder ?? 'Search symbols...',
+          title: options.title ?? t('symbolPicker.title'),
+          placeholder: options.placeholder ?? t('symbolPicker.placeholder'),
           width: 70,
           height: 20,
         },
@@ -422,6 +425,32 @@ export class DialogManager {
     }
   }
 
+  // ─────────────────────────────────────────────────────────────────────────
+  // Notification Center
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /**
+   * Show the notification center. Resolves when closed.
+   */
+  async showNotificationCenter(): Promise<DialogResult<void>> {
+    if (!this.notificationCenterDialog) {
+      this.notificationCenterDialog = new NotificationCenterDialog(
+        'dialog-notification-center',
+        this.callbacks,
+        this.overlayManager
+      );
+      this.overlayManager.addOverlay(this.notificationCenterDialog);
+    }
+
+    this.activeDialogId = 'dialog-notification-center';
+
+    try {
+      return await this.notificationCenterDialog.showCenter();
+    } finally {
+      this.activeDialogId = null;
+    }
+  }
+
   // ─────────────────────────────────────────────────────────────────────────
   // Cleanup
   // ─────────────────────────────────────────────────────────────────────────
@@ -466,6 +495,10 @@ export class DialogManager {
       this.overlayManager.removeOverlay('dialog-keybindings');
       this.keybindingsDialog = null;
     }
+    if (this.notificationCenterDialog) {
+      this.overlayManager.removeOverlay('dialog-notification-center');
+      this.notificationCenterDialog = null;
+    }
     this.activeDialogId = null;
   }
 }
@@ -11,6 +11,7 @@ import type { KeyEvent, MouseEvent, InputEvent } from '../types.ts';
 import type { ScreenBuffer } from '../rendering/buffer.ts';
 import type { FileService } from '../../../services/file/index.ts';
 import * as path from 'path';
+import { t } from '../i18n/index.ts';
 
 // ============================================
 // Types
@@ -91,7 +92,7 @@ export class FileBrowserDialog extends PromiseDialog<string> {
     await this.loadDirectory();
 
     return this.showAsync({
-      title: config.title ?? 'Open File',
+      title: config.title ?? t('fileBrowser.title'),
       width: config.width ?? 70,
       height: config.height ?? 25,
     });
@@ -412,7 +413,7 @@ export class FileBrowserDialog extends PromiseDialog<string> {
     const dirFg = this.callbacks.getThemeColor('terminal.ansiBrightBlue', '#61afef');
 
     if (this.entries.length === 0) {
-      buffer.writeString(x + 2, y + 1, 'Empty directory', dimFg, bg);
+      buffer.writeString(x + 2, y + 1, t('fileBrowser.emptyDirectory'), dimFg, bg);
       return;
     }
 
@@ -468,7 +469,7 @@ export class FileBrowserDialog extends PromiseDialog<string> {
     const dimFg = this.callbacks.getThemeColor('descriptionForeground', '#888888');
     const bg = this.callbacks.getThemeColor('editorWidget.background', '#252526');
 
-    const helpText = '\u2191\u2193:nav  \u2190:up  \u2192/Enter:open  .:hidden  Esc:close';
+    const helpText = t('fileBrowser.help');
     const truncatedHelp = helpText.slice(0, width - 2);
     buffer.writeString(x + 1, y, truncatedHelp, dimFg, bg);
 
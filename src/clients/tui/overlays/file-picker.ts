@@ -8,6 +8,7 @@
 import { SearchableDialog, type ItemDisplay } from './searchable-dialog.ts';
 import type { OverlayManagerCallbacks } from './overlay-manager.ts';
 import type { ScreenBuffer } from '../rendering/buffer.ts';
+import { t } from '../i18n/index.ts';
 
 // ============================================
 // Types
@@ -235,7 +236,7 @@ export class FilePickerDialog extends SearchableDialog<FileEntry> {
 
     // Loading indicator
     if (this.isLoading) {
-      buffer.writeString(x, y, 'Loading...', dimFg, bg);
+      buffer.writeString(x, y, t('common.loading'), dimFg, bg);
     }
 
     // Item count (right aligned)
@@ -9,6 +9,7 @@ import { PromiseDialog, type DialogConfig, type DialogResult } from './promise-d
 import type { OverlayManagerCallbacks } from './overlay-manager.ts';
 import type { KeyEvent } from '../types.ts';
 import type { ScreenBuffer } from '../rendering/buffer.ts';
+import { t } from '../i18n/index.ts';
 
 // ============================================
 // Types
@@ -59,7 +60,7 @@ export class GotoLineDialog extends PromiseDialog<GotoLineResult> {
     this.error = null;
 
     return this.showAsync({
-      title: 'Go to Line',
+      title: t('gotoLine.title'),
       width: 40,
       height: 5,
     });
@@ -72,14 +73,14 @@ export class GotoLineDialog extends PromiseDialog<GotoLineResult> {
   private parseInput(): GotoLineResult | null {
     const trimmed = this.value.trim();
     if (!trimmed) {
-      this.error = 'Enter a line number';
+      this.error = t('gotoLine.enterLine');
       return null;
     }
 
     // Format: line or line:column or :column
     const match = trimmed.match(/^(\d*):?(\d*)$/);
     if (!match) {
-      this.error = 'Invalid format. Use: line or line:column';
+      this.error = t('gotoLine.invalidFormat');
       return null;
     }
 
@@ -90,7 +91,7 @@ export class GotoLineDialog extends PromiseDialog<GotoLineResult> {
     if (lineStr) {
       line = parseInt(lineStr, 10);
       if (isNaN(line) || line < 1) {
-        this.error = 'Line must be a positive number';
+        this.error = t('gotoLine.linePositive');
         return null;
       }
     } else {
@@ -101,7 +102,7 @@ export class GotoLineDialog extends PromiseDialog<GotoLineResult> {
     if (colStr) {
       column = parseInt(colStr, 10);
       if (isNaN(column) || column < 1) {
-        this.error = 'Column must be a positive number';
+        this.error = t('gotoLine.columnPositive');
         return null;
       }
     }
@@ -222,7 +223,7 @@ export class GotoLineDialog extends PromiseDialog<GotoLineResult> {
     }
 
     // Placeholder or value
-    const placeholder = `line:column (current: ${this.currentLine})`;
+    const placeholder = t('gotoLine.placeholder', { line: this.currentLine });
     const displayText = this.value || placeholder;
     const displayFg = this.value ? fg : dimFg;
     const maxDisplay = inputWidth - 2;
@@ -247,7 +248,7 @@ export class GotoLineDialog extends PromiseDialog<GotoLineResult> {
     if (this.error) {
       buffer.writeString(content.x, hintY, this.error, errorFg, bg);
     } else {
-      const hint = 'Format: line or line:column';
+      const hint = t('gotoLine.format');
       buffer.writeString(content.x, hintY, hint, dimFg, bg);
     }
   }
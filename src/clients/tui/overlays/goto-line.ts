@@ -7,6 +7,7 @@
 import { BaseDialog, type OverlayManagerCallbacks } from './overlay-manager.ts';
 import type { InputEvent, KeyEvent, Rect } from '../types.ts';
 import type { ScreenBuffer } from '../rendering/buffer.ts';
+import { t } from '../i18n/index.ts';
 
 // ============================================
 // Types
@@ -106,22 +107,22 @@ export class GotoLineDialog extends BaseDialog {
     }
 
     if (!parsed) {
-      this.errorMessage = 'Enter a line number (e.g., 42 or 42:10)';
+      this.errorMessage = t('gotoLine.enterLineExample');
       return;
     }
 
     if (parsed.line < 1) {
-      this.errorMessage = 'Line must be at least 1';
+      this.errorMessage = t('gotoLine.lineAtLeastOne');
       return;
     }
 
     if (parsed.line > this.totalLines) {
-      this.errorMessage = `Line exceeds document length (${this.totalLines})`;
+      this.errorMessage = t('gotoLine.lineExceedsLength', { total: this.totalLines });
       return;
     }
 
     if (parsed.column !== undefined && parsed.column < 1) {
-      this.errorMessage = 'Column must be at least 1';
+      this.errorMessage = t('gotoLine.columnAtLeastOne');
       return;
     }
 
@@ -182,13 +183,13 @@ export class GotoLineDialog extends BaseDialog {
     const errorFg = this.callbacks.getThemeColor('errorForeground', '#f48771');
 
     // Draw dialog box
-    this.drawDialogBox(buffer, 'Go to Line');
+    this.drawDialogBox(buffer, t('gotoLine.title'));
 
     const contentX = x + 2;
     let rowY = y + 1;
 
     // Prompt text
-    const promptText = `Current line: ${this.currentLine} of ${this.totalLines}`;
+    const promptText = t('gotoLine.currentLine', { line: this.currentLine, total: this.totalLines });
     buffer.writeString(contentX, rowY, promptText, dimFg, bg);
     rowY++;
 
@@ -227,7 +228,7 @@ export class GotoLineDialog extends BaseDialog {
       buffer.writeString(contentX, rowY, this.errorMessage, errorFg, bg);
     } else {
       // Format hint
-      const hintText = 'Format: line or line:column';
+      const hintText = t('gotoLine.format');
       buffer.writeString(contentX, rowY, hintText, dimFg, bg);
     }
 
@@ -240,7 +241,7 @@ export class GotoLineDialog extends BaseDialog {
 
     // Help text at bottom
     const helpY = y + height - 1;
-    const helpText = 'Enter: Go to line | Esc: Cancel';
+    const helpText = t('gotoLine.help');
     buffer.writeString(x + 2, helpY, helpText, dimFg, bg);
   }
 
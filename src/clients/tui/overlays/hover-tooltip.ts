@@ -10,6 +10,7 @@ import { isKeyEvent, isMouseEvent } from '../types.ts';
 import type { ScreenBuffer } from '../rendering/buffer.ts';
 import type { LSPHover } from '../../../services/lsp/types.ts';
 import { debugLog } from '../../../debug.ts';
+import { t } from '../i18n/index.ts';
 
 // ============================================
 // Hover Tooltip
@@ -201,7 +202,7 @@ export class HoverTooltip implements Overlay {
 
     // Draw scroll indicator if content is truncated
     if (this.content.length > height - 2) {
-      const moreText = `... (${this.content.length - (height - 2)} more lines)`;
+      const moreText = t('hover.moreLines', { count: this.content.length - (height - 2) });
       const moreY = y + height - 2;
       const dimColor = this.callbacks.getThemeColor('descriptionForeground', '#717171');
 
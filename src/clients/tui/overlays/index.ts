@@ -11,8 +11,13 @@ export {
   createOverlayManager,
   type Overlay,
   type OverlayManagerCallbacks,
+  formatProgressBar,
   type NotificationType,
   type Notification,
+  type NotificationButton,
+  type NotificationCloseReason,
+  type NotificationOptions,
+  type NotificationProgressState,
 } from './overlay-manager.ts';
 
 // Promise-based dialog base classes
@@ -95,6 +100,7 @@ export {
   ENUM_OPTIONS,
   MULTILINE_SETTINGS,
   SETTING_DESCRIPTIONS,
+  LOCALIZED_SETTING_DESCRIPTIONS,
   type SettingType,
 } from './settings-utils.ts';
 
@@ -145,6 +151,12 @@ export {
   createHoverTooltip,
 } from './hover-tooltip.ts';
 
+export {
+  ColorPicker,
+  createColorPicker,
+  type ColorPickerCommitCallback,
+} from './color-picker.ts';
+
 export {
   SignatureHelpOverlay,
   createSignatureHelp,
@@ -177,3 +189,17 @@ export {
   type SchemaBrowserConfig,
   type SchemaBrowserResult,
 } from './schema-browser.ts';
+
+export {
+  NotificationCenterDialog,
+  formatNotificationTime,
+} from './notification-center.ts';
+
+export {
+  WhichKeyPopup,
+  createWhichKeyPopup,
+  getWhichKeyEntries,
+  describeWhichKeyEntry,
+  type WhichKeyBinding,
+  type WhichKeyEntry,
+} from './which-key.ts';
@@ -6,8 +6,9 @@
 
 import { PromiseDialog, type DialogConfig, type DialogResult } from './promise-dialog.ts';
 import type { OverlayManagerCallbacks } from './overlay-manager.ts';
-import type { KeyEvent } from '../types.ts';
+import type { KeyEvent, Position } from '../types.ts';
 import type { ScreenBuffer } from '../rendering/buffer.ts';
+import { t } from '../i18n/index.ts';
 
 // ============================================
 // Types
@@ -55,6 +56,12 @@ export class InputDialog extends PromiseDialog<string> {
   /** Validation function */
   private validate: ((value: string) => string | null) | null = null;
 
+  /** Value the dialog was opened with */
+  private initialValue: string = '';
+
+  /** Screen position of the text cursor in the last render */
+  private cursorScreen: Position | null = null;
+
   constructor(id: string, callbacks: OverlayManagerCallbacks) {
     super(id, callbacks);
   }
@@ -70,6 +77,7 @@ export class InputDialog extends PromiseDialog<string> {
     this.prompt = options.prompt ?? '';
     this.placeholder = options.placeholder ?? '';
     this.value = options.initialValue ?? '';
+    this.initialValue = this.value;
     this.cursorPos = this.value.length;
     this.validate = options.validate ?? null;
     this.error = null;
@@ -82,7 +90,7 @@ export class InputDialog extends PromiseDialog<string> {
     }
 
     return this.showAsync({
-      title: options.title ?? 'Input',
+      title: options.title ?? t('input.title'),
       width: options.width ?? 50,
       height: options.height ?? (this.prompt ? 7 : 5),
       ...options,
@@ -219,6 +227,26 @@ export class InputDialog extends PromiseDialog<string> {
     this.selectionStart = -1;
   }
 
+  // ─────────────────────────────────────────────────────────────────────────
+  // Accessibility
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /**
+   * Title, prompt, initial value and validation error. Edits aren't
+   * included: the screen reader echoes typed characters itself.
+   */
+  getAccessibleText(): string {
+    const lines = [t('input.accessibleTitle', { title: this.title }), this.prompt];
+    if (this.initialValue) lines.push(t('input.accessibleValue', { value: this.initialValue }));
+    else if (this.placeholder) lines.push(t('input.accessibleExample', { example: this.placeholder }));
+    if (this.error) lines.push(t('common.errorWithMessage', { message: this.error }));
+    return lines.filter(Boolean).join('\n');
+  }
+
+  getCursorPosition(): Position | null {
+    return this.cursorScreen;
+  }
+
   // ─────────────────────────────────────────────────────────────────────────
   // Rendering
   // ─────────────────────────────────────────────────────────────────────────
@@ -277,6 +305,7 @@ export class InputDialog extends PromiseDialog<string> {
 
     // Cursor
     const cursorScreenX = content.x + 1 + this.cursorPos - scrollOffset;
+    this.cursorScreen = { x: Math.min(cursorScreenX, content.x + inputWidth - 1), y };
     if (cursorScreenX < content.x + inputWidth - 1 && this.value) {
       buffer.set(cursorScreenX, y, {
         char: this.value[this.cursorPos] ?? ' ',
@@ -11,6 +11,7 @@ import type { DialogResult } from './promise-dialog.ts';
 import type { KeyEvent } from '../types.ts';
 import type { ScreenBuffer } from '../rendering/buffer.ts';
 import type { KeyBinding } from '../../../services/session/types.ts';
+import { t } from '../i18n/index.ts';
 
 // ============================================
 // Types
@@ -99,8 +100,8 @@ export class KeybindingsDialog extends SearchableDialog<KeybindingItem> {
     return this.showWithItems(
       {
         ...options,
-        title: options.title ?? 'Keyboard Shortcuts',
-        placeholder: options.placeholder ?? 'Search keybindings...',
+        title: options.title ?? t('keybindings.title'),
+        placeholder: options.placeholder ?? t('keybindings.placeholder'),
         width: options.width ?? 80,
         height: options.height ?? 25,
       },
@@ -207,7 +208,7 @@ export class KeybindingsDialog extends SearchableDialog<KeybindingItem> {
     const conflict = this.findConflict(keyString, selected.command);
 
     if (conflict) {
-      this.conflictMessage = `"${keyString}" is used by "${conflict.label}". Press Enter to override, Escape to cancel.`;
+      this.conflictMessage = t('keybindings.conflict', { key: keyString, command: conflict.label });
       this.capturedKeyParts = [keyString];
       this.callbacks.onDirty();
     } else {
@@ -385,7 +386,7 @@ export class KeybindingsDialog extends SearchableDialog<KeybindingItem> {
     this.maxVisibleResults = Math.max(1, height);
 
     if (this.filteredItems.length === 0) {
-      const msg = this.query ? 'No matching keybindings' : 'No keybindings available';
+      const msg = this.query ? t('keybindings.noMatches') : t('keybindings.noKeybindings');
       buffer.writeString(x + 1, y + 1, msg, dimFg, bg);
       return;
     }
@@ -421,7 +422,7 @@ export class KeybindingsDialog extends SearchableDialog<KeybindingItem> {
         const captureText =
           this.capturedKeyParts.length > 0
             ? this.capturedKeyParts.join(' ')
-            : 'Press key...';
+            : t('keybindings.pressKey');
         buffer.writeString(x + 3, rowY, captureText, captureHighlight, rowBg);
       } else {
         const keyText = item.key || '(none)';
@@ -459,7 +460,7 @@ export class KeybindingsDialog extends SearchableDialog<KeybindingItem> {
     if (this.conflictMessage) {
       buffer.writeString(x, y, this.conflictMessage.slice(0, width - 2), warningFg, bg);
     } else if (this.captureMode) {
-      buffer.writeString(x, y, 'Note: CMD key = "meta" on Mac', dimFg, bg);
+      buffer.writeString(x, y, t('keybindings.cmdNote'), dimFg, bg);
     } else {
       const selected = this.getSelectedItem();
       if (selected?.when) {
@@ -473,12 +474,12 @@ export class KeybindingsDialog extends SearchableDialog<KeybindingItem> {
 
     if (this.captureMode) {
       if (this.conflictMessage) {
-        hints = 'Enter: override | Esc: cancel';
+        hints = t('keybindings.hintConflict');
       } else {
-        hints = 'Press key combination... | Esc: cancel';
+        hints = t('keybindings.hintCapture');
       }
     } else {
-      hints = 'Enter: change | R: reset | Esc: close';
+      hints = t('keybindings.hintBrowse');
     }
 
     const count = `${this.filteredItems.length}/${this.items.length}`;
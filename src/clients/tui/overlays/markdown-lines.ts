@@ -0,0 +1,199 @@
+/**
+ * Markdown Lines
+ *
+ * Renders LSP documentation (Markdown or plain text) into wrapped,
+ * styled lines for terminal overlays. Supports the subset servers
+ * actually emit: fenced code, headings, lists, quotes, rules,
+ * emphasis, inline code and links.
+ */
+
+// ============================================
+// Types
+// ============================================
+
+/**
+ * Style of a rendered line.
+ */
+export type MarkdownLineKind = 'text' | 'code' | 'heading' | 'quote' | 'rule';
+
+/**
+ * A single rendered line.
+ */
+export interface MarkdownLine {
+  text: string;
+  kind: MarkdownLineKind;
+}
+
+// ============================================
+// Rendering
+// ============================================
+
+/**
+ * Render Markdown to lines no wider than `width`.
+ * Code blocks are truncated rather than wrapped so indentation survives.
+ */
+export function renderMarkdownLines(markdown: string, width: number): MarkdownLine[] {
+  const lines: MarkdownLine[] = [];
+  const maxWidth = Math.max(1, width);
+  let inFence = false;
+  let fence = '';
+
+  const pushBlank = (): void => {
+    if (lines.length > 0 && lines[lines.length - 1]!.text !== '') {
+      lines.push({ text: '', kind: 'text' });
+    }
+  };
+
+  for (const rawLine of markdown.replace(/\r\n?/g, '\n').split('\n')) {
+    const fenceMatch = rawLine.match(/^\s*(`{3,}|~{3,})/);
+
+    if (inFence) {
+      if (fenceMatch && fenceMatch[1]!.startsWith(fence)) {
+        inFence = false;
+        pushBlank();
+      } else {
+        lines.push({ text: truncate(rawLine.replace(/\t/g, '  '), maxWidth), kind: 'code' });
+      }
+      continue;
+    }
+
+    if (fenceMatch) {
+      inFence = true;
+      fence = fenceMatch[1]!;
+      continue;
+    }
+
+    const trimmed = rawLine.trim();
+    if (trimmed === '') {
+      pushBlank();
+      continue;
+    }
+
+    // Horizontal rule
+    if (/^([-*_])(\s*\1){2,}$/.test(trimmed)) {
+      pushBlank();
+      lines.push({ text: '─'.repeat(maxWidth), kind: 'rule' });
+      continue;
+    }
+
+    // Heading
+    const heading = trimmed.match(/^#{1,6}\s+(.*)$/);
+    if (heading) {
+      for (const line of wrapText(stripInline(heading[1]!), maxWidth)) {
+        lines.push({ text: line, kind: 'heading' });
+      }
+      continue;
+    }
+
+    // Block quote
+    const quote = trimmed.match(/^>\s?(.*)$/);
+    if (quote) {
+      for (const line of wrapText(stripInline(quote[1]!), maxWidth - 2)) {
+        lines.push({ text: `│ ${line}`, kind: 'quote' });
+      }
+      continue;
+    }
+
+    // List item (keeps a hanging indent)
+    const list = rawLine.match(/^(\s*)([-*+]|\d+[.)])\s+(.*)$/);
+    if (list) {
+      const indent = ' '.repeat(Math.min(list[1]!.length, 8));
+      const bullet = /^\d/.test(list[2]!) ? `${list[2]} ` : '• ';
+      const hanging = indent + ' '.repeat(bullet.length);
+      const wrapped = wrapText(stripInline(list[3]!), maxWidth - hanging.length);
+      wrapped.forEach((line, i) => {
+        lines.push({ text: (i === 0 ? indent + bullet : hanging) + line, kind: 'text' });
+      });
+      continue;
+    }
+
+    // Indented code (4 spaces)
+    if (/^( {4}|\t)/.test(rawLine)) {
+      lines.push({ text: truncate(rawLine.replace(/^( {4}|\t)/, '').replace(/\t/g, '  '), maxWidth), kind: 'code' });
+      continue;
+    }
+
+    for (const line of wrapText(stripInline(trimmed), maxWidth)) {
+      lines.push({ text: line, kind: 'text' });
+    }
+  }
+
+  while (lines.length > 0 && lines[lines.length - 1]!.text === '') {
+    lines.pop();
+  }
+
+  return lines;
+}
+
+/**
+ * Render LSP documentation (plain string or MarkupContent).
+ */
+export function renderDocumentation(
+  documentation: string | { kind: string; value: string } | undefined,
+  width: number
+): MarkdownLine[] {
+  if (!documentation) return [];
+  if (typeof documentation === 'string' || documentation.kind !== 'markdown') {
+    const text = typeof documentation === 'string' ? documentation : documentation.value;
+    return text
+      .replace(/\r\n?/g, '\n')
+      .split('\n')
+      .flatMap((line) => (line.trim() === '' ? [''] : wrapText(line.trimEnd(), Math.max(1, width))))
+      .map((line) => ({ text: line, kind: 'text' as const }));
+  }
+  return renderMarkdownLines(documentation.value, width);
+}
+
+// ============================================
+// Helpers
+// ============================================
+
+/**
+ * Strip inline Markdown syntax, keeping the text.
+ */
+function stripInline(text: string): string {
+  return text
+    .replace(/!?\[([^\]]*)\]\([^)]*\)/g, '$1')  // links and images
+    .replace(/`([^`]+)`/g, '$1')                // inline code
+    .replace(/\*\*([^*]+)\*\*/g, '$1')          // bold
+    .replace(/__([^_]+)__/g, '$1')
+    .replace(/(^|[^\w*])\*([^*\s][^*]*)\*(?!\w)/g, '$1$2')  // italic
+    .replace(/(^|[^\w_])_([^_\s][^_]*)_(?!\w)/g, '$1$2')   // italic, not snake_case
+    .replace(/\\([\\`*_{}[\]()#+\-.!<>])/g, '$1');         // escapes
+}
+
+/**
+ * Word-wrap a line, hard-breaking words longer than the width.
+ */
+function wrapText(text: string, width: number): string[] {
+  const maxWidth = Math.max(1, width);
+  const lines: string[] = [];
+  let current = '';
+
+  for (const word of text.split(/\s+/).filter(Boolean)) {
+    let rest = word;
+    while (rest.length > maxWidth) {
+      if (current) {
+        lines.push(current);
+        current = '';
+      }
+      lines.push(rest.slice(0, maxWidth));
+      rest = rest.slice(maxWidth);
+    }
+    if (!current) {
+      current = rest;
+    } else if (current.length + 1 + rest.length <= maxWidth) {
+      current += ' ' + rest;
+    } else {
+      lines.push(current);
+      current = rest;
+    }
+  }
+
+  if (current) lines.push(current);
+  return lines;
+}
+
+function truncate(text: string, width: number): string {
+  return text.length > width ? text.slice(0, Math.max(0, width - 1)) + '…' : text;
+}
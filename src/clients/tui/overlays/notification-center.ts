@@ -0,0 +1,248 @@
+/**
+ * Notification Center
+ *
+ * Lists shown notifications, newest first, with timestamps. Open
+ * notifications can be acted on from here: run an action, cancel progress,
+ * dismiss, or choose "do not show again".
+ */
+
+import { PromiseDialog, type DialogResult } from './promise-dialog.ts';
+import {
+  formatProgressBar,
+  type Notification,
+  type NotificationType,
+  type OverlayManager,
+  type OverlayManagerCallbacks,
+} from './overlay-manager.ts';
+import type { KeyEvent } from '../types.ts';
+import type { ScreenBuffer } from '../rendering/buffer.ts';
+import { t } from '../i18n/index.ts';
+
+// ============================================
+// Helpers
+// ============================================
+
+/**
+ * Format a timestamp as local HH:MM:SS.
+ */
+export function formatNotificationTime(time: number): string {
+  const date = new Date(time);
+  return [date.getHours(), date.getMinutes(), date.getSeconds()]
+    .map((part) => String(part).padStart(2, '0'))
+    .join(':');
+}
+
+const ICONS: Record<NotificationType, string> = {
+  error: '✗',
+  warning: '⚠',
+  success: '✓',
+  info: 'ℹ',
+};
+
+// ============================================
+// Notification Center
+// ============================================
+
+export class NotificationCenterDialog extends PromiseDialog<void> {
+  /** Selected row */
+  private selectedIndex = 0;
+
+  /** First visible row */
+  private scrollTop = 0;
+
+  constructor(
+    id: string,
+    callbacks: OverlayManagerCallbacks,
+    private readonly overlayManager: OverlayManager
+  ) {
+    super(id, callbacks);
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Public API
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /**
+   * Show the notification center. Resolves when closed.
+   */
+  showCenter(): Promise<DialogResult<void>> {
+    this.selectedIndex = 0;
+    this.scrollTop = 0;
+
+    const screen = this.callbacks.getScreenSize();
+    return this.showAsync({
+      title: t('notifications.title'),
+      width: Math.max(40, Math.min(90, screen.width - 4)),
+      height: Math.max(8, Math.min(20, screen.height - 4)),
+    });
+  }
+
+  /**
+   * Get the selected notification.
+   */
+  getSelected(): Notification | null {
+    return this.getEntries()[this.selectedIndex] ?? null;
+  }
+
+  getAccessibleText(): string {
+    const entries = this.getEntries();
+    const selected = entries[this.selectedIndex];
+    if (!selected) {
+      return `${this.title}\n${t('notifications.empty')}`;
+    }
+    const item = `${formatNotificationTime(selected.createdAt)} ${selected.message}`;
+    return `${this.title}\n${t('common.itemPosition', { item, index: this.selectedIndex + 1, count: entries.length })}`;
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Input Handling
+  // ─────────────────────────────────────────────────────────────────────────
+
+  protected override handleKeyInput(event: KeyEvent): boolean {
+    const entries = this.getEntries();
+    const selected = entries[this.selectedIndex];
+
+    switch (event.key) {
+      case 'ArrowUp':
+        this.select(this.selectedIndex - 1);
+        return true;
+      case 'ArrowDown':
+        this.select(this.selectedIndex + 1);
+        return true;
+      case 'PageUp':
+        this.select(this.selectedIndex - this.getListHeight());
+        return true;
+      case 'PageDown':
+        this.select(this.selectedIndex + this.getListHeight());
+        return true;
+      case 'Home':
+        this.select(0);
+        return true;
+      case 'End':
+        this.select(entries.length - 1);
+        return true;
+      case 'Enter':
+        if (selected) this.overlayManager.invokeNotificationAction(selected.id, 0);
+        return true;
+      case 'Delete':
+        if (selected) this.overlayManager.closeNotification(selected.id, 'dismissed');
+        return true;
+      case 'x':
+        if (selected) this.overlayManager.cancelNotification(selected.id);
+        return true;
+      case 'd':
+        if (selected) this.overlayManager.doNotShowAgain(selected.id);
+        return true;
+      case 'c':
+        this.overlayManager.clearNotificationHistory();
+        this.select(0);
+        return true;
+    }
+
+    // 1-9 run the selected notification's actions
+    if (selected && /^[1-9]$/.test(event.key) && !event.ctrl && !event.alt) {
+      this.overlayManager.invokeNotificationAction(selected.id, Number(event.key) - 1);
+      return true;
+    }
+
+    return false;
+  }
+
+  private select(index: number): void {
+    const count = this.getEntries().length;
+    this.selectedIndex = Math.max(0, Math.min(index, count - 1));
+
+    const listHeight = this.getListHeight();
+    if (this.selectedIndex < this.scrollTop) {
+      this.scrollTop = this.selectedIndex;
+    } else if (this.selectedIndex >= this.scrollTop + listHeight) {
+      this.scrollTop = this.selectedIndex - listHeight + 1;
+    }
+    this.callbacks.onDirty();
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Rendering
+  // ─────────────────────────────────────────────────────────────────────────
+
+  protected override renderContent(buffer: ScreenBuffer): void {
+    const content = this.getContentBounds();
+    const bg = this.callbacks.getThemeColor('editorWidget.background', '#252526');
+    const fg = this.callbacks.getThemeColor('editorWidget.foreground', '#cccccc');
+    const dimFg = this.callbacks.getThemeColor('descriptionForeground', '#888888');
+    const selectedBg = this.callbacks.getThemeColor('list.activeSelectionBackground', '#094771');
+    const selectedFg = this.callbacks.getThemeColor('list.activeSelectionForeground', '#ffffff');
+
+    const entries = this.getEntries();
+    if (this.selectedIndex >= entries.length) {
+      this.selectedIndex = Math.max(0, entries.length - 1);
+    }
+
+    if (entries.length === 0) {
+      buffer.writeString(content.x + 1, content.y, t('notifications.empty'), dimFg, bg);
+    }
+
+    const listHeight = this.getListHeight();
+    for (let row = 0; row < listHeight; row++) {
+      const entry = entries[this.scrollTop + row];
+      if (!entry) break;
+
+      const isSelected = this.scrollTop + row === this.selectedIndex;
+      const rowFg = isSelected ? selectedFg : entry.closedAt === undefined ? fg : dimFg;
+      const rowBg = isSelected ? selectedBg : bg;
+      const source = entry.source ? ` ${entry.source} ` : '';
+      const open = entry.closedAt === undefined ? '●' : ' ';
+      let text = ` ${formatNotificationTime(entry.createdAt)} ${open} ${ICONS[entry.type]} ${entry.message}`;
+      const textWidth = content.width - source.length;
+      if (text.length > textWidth) {
+        text = text.slice(0, textWidth - 1) + '…';
+      }
+
+      buffer.writeString(content.x, content.y + row, (text.padEnd(textWidth) + source).slice(0, content.width), rowFg, rowBg);
+    }
+
+    // Details of the selected notification
+    const selected = entries[this.selectedIndex];
+    const detailY = content.y + content.height - 2;
+    if (selected) {
+      buffer.writeString(content.x + 1, detailY, this.describeControls(selected).slice(0, content.width - 2), fg, bg);
+    }
+
+    // Keyboard hints
+    const hint = t('notifications.hint');
+    buffer.writeString(content.x + 1, content.y + content.height - 1, hint.slice(0, content.width - 2), dimFg, bg);
+  }
+
+  /**
+   * Describe what can be done with a notification, e.g. `1 Retry  d Don't show again`.
+   */
+  private describeControls(notification: Notification): string {
+    const parts: string[] = [];
+    const open = notification.closedAt === undefined;
+
+    if (open && notification.progress) {
+      parts.push(formatProgressBar(notification.progress.percent, 20));
+      if (notification.progress.onCancel) {
+        parts.push(`x ${t('common.cancel')}`);
+      }
+    }
+    if (open) {
+      notification.actions?.slice(0, 9).forEach((action, index) => {
+        parts.push(`${index + 1} ${action.label}`);
+      });
+    }
+    if (notification.doNotShowAgainKey) {
+      parts.push(`d ${t('notifications.doNotShowAgain')}`);
+    }
+
+    return parts.join('  ');
+  }
+
+  private getEntries(): Notification[] {
+    return this.overlayManager.getNotificationHistory();
+  }
+
+  private getListHeight(): number {
+    return Math.max(1, this.getContentBounds().height - 3);
+  }
+}
@@ -4,8 +4,9 @@
  * Manages z-ordered overlay components like dialogs, command palette, and notifications.
  */
 
-import type { Rect, Size, KeyEvent, MouseEvent, InputEvent } from '../types.ts';
+import type { Rect, Size, Position, KeyEvent, MouseEvent, InputEvent } from '../types.ts';
 import type { ScreenBuffer } from '../rendering/buffer.ts';
+import { t } from '../i18n/index.ts';
 
 // ============================================
 // Types
@@ -35,6 +36,10 @@ export interface Overlay {
   handleInput(event: InputEvent): boolean;
   /** Called when overlay is dismissed */
   onDismiss?(): void;
+  /** Text for screen readers (defaults to the text rendered in its bounds) */
+  getAccessibleText?(): string;
+  /** Screen position of the overlay's text cursor, if it has one */
+  getCursorPosition?(): Position | null;
 }
 
 /**
@@ -54,6 +59,47 @@ export interface OverlayManagerCallbacks {
  */
 export type NotificationType = 'info' | 'warning' | 'error' | 'success';
 
+/**
+ * Why a notification closed.
+ */
+export type NotificationCloseReason = 'dismissed' | 'action' | 'cancelled' | 'done' | 'expired';
+
+/**
+ * A button on a notification.
+ */
+export interface NotificationButton {
+  label: string;
+  run: () => void;
+}
+
+/**
+ * Progress shown on a notification.
+ */
+export interface NotificationProgressState {
+  /** Completion (0-100), undefined while indeterminate */
+  percent?: number;
+  /** Called when the user cancels; omit for progress that can't be cancelled */
+  onCancel?: () => void;
+}
+
+/**
+ * Options for notify().
+ */
+export interface NotificationOptions {
+  /** Time on screen in ms; 0 keeps it until closed. Defaults to 0 with actions or progress */
+  duration?: number;
+  /** Who raised it */
+  source?: string;
+  /** Action buttons */
+  actions?: NotificationButton[];
+  /** Offer "do not show again"; suppressed keys are not shown */
+  doNotShowAgainKey?: string;
+  /** Show a progress bar */
+  progress?: NotificationProgressState;
+  /** Called once when it closes */
+  onClose?: (reason: NotificationCloseReason) => void;
+}
+
 /**
  * A toast notification.
  */
@@ -61,8 +107,28 @@ export interface Notification {
   id: string;
   message: string;
   type: NotificationType;
+  /** Time on screen in ms (0 = until closed) */
   duration: number;
   createdAt: number;
+  source?: string;
+  actions?: NotificationButton[];
+  doNotShowAgainKey?: string;
+  progress?: NotificationProgressState;
+  /** Set once closed */
+  closedAt?: number;
+  closeReason?: NotificationCloseReason;
+  onClose?: (reason: NotificationCloseReason) => void;
+}
+
+/**
+ * Clickable area of a toast.
+ */
+interface NotificationHitArea {
+  x: number;
+  y: number;
+  width: number;
+  /** Undefined for the toast body, which only swallows clicks */
+  onClick?: () => void;
 }
 
 // ============================================
@@ -76,20 +142,38 @@ export class OverlayManager {
   /** Active notifications */
   private notifications: Notification[] = [];
 
+  /** Shown notifications, oldest first, including active ones */
+  private notificationHistory: Notification[] = [];
+
+  /** Suppressed "do not show again" keys */
+  private suppressedNotifications = new Set<string>();
+
+  /** Listeners for "do not show again" choices */
+  private doNotShowAgainListeners = new Set<(key: string) => void>();
+
+  /** Clickable toast areas from the last render */
+  private notificationHitAreas: NotificationHitArea[] = [];
+
   /** Callbacks */
   private callbacks: OverlayManagerCallbacks;
 
   /** Notification ID counter */
   private notificationIdCounter = 0;
 
+  /** Listeners for shown notifications */
+  private notificationListeners = new Set<(notification: Notification) => void>();
+
   /** Default notification duration (ms) */
   private static readonly DEFAULT_NOTIFICATION_DURATION = 3000;
 
   /** Max visible notifications */
   private static readonly MAX_NOTIFICATIONS = 5;
 
-  /** Notification height */
-  private static readonly NOTIFICATION_HEIGHT = 1;
+  /** Notifications kept in history */
+  private static readonly MAX_NOTIFICATION_HISTORY = 100;
+
+  /** Width of a progress bar */
+  private static readonly PROGRESS_BAR_WIDTH = 20;
 
   constructor(callbacks: OverlayManagerCallbacks) {
     this.callbacks = callbacks;
@@ -199,46 +283,134 @@ export class OverlayManager {
     type: NotificationType = 'info',
     duration = OverlayManager.DEFAULT_NOTIFICATION_DURATION
   ): string {
-    const id = `notification-${++this.notificationIdCounter}`;
+    return this.notify(message, type, { duration })!;
+  }
+
+  /**
+   * Show a notification with actions, progress or "do not show again".
+   * Notifications with actions or progress stay until closed unless a
+   * duration is given.
+   *
+   * @returns Notification ID, or null if its "do not show again" key is suppressed
+   */
+  notify(
+    message: string,
+    type: NotificationType = 'info',
+    options: NotificationOptions = {}
+  ): string | null {
+    if (options.doNotShowAgainKey && this.suppressedNotifications.has(options.doNotShowAgainKey)) {
+      return null;
+    }
 
-    this.notifications.push({
+    const sticky = (options.actions?.length ?? 0) > 0 || options.progress !== undefined;
+    const duration = options.duration ?? (sticky ? 0 : OverlayManager.DEFAULT_NOTIFICATION_DURATION);
+    const id = `notification-${++this.notificationIdCounter}`;
+    const notification: Notification = {
       id,
       message,
       type,
       duration,
       createdAt: Date.now(),
-    });
+      source: options.source,
+      actions: options.actions,
+      doNotShowAgainKey: options.doNotShowAgainKey,
+      progress: options.progress ? { ...options.progress } : undefined,
+      onClose: options.onClose,
+    };
+
+    this.notifications.push(notification);
+    this.notificationHistory.push(notification);
+    if (this.notificationHistory.length > OverlayManager.MAX_NOTIFICATION_HISTORY) {
+      this.notificationHistory.shift();
+    }
+    for (const listener of this.notificationListeners) {
+      listener(notification);
+    }
 
-    // Trim to max
+    // Trim to max, dropping toasts that expire on their own first
     while (this.notifications.length > OverlayManager.MAX_NOTIFICATIONS) {
-      this.notifications.shift();
+      const oldest = this.notifications.find((n) => n.duration > 0) ?? this.notifications[0]!;
+      this.finishNotification(oldest, 'expired');
     }
 
     // Schedule removal
-    setTimeout(() => {
-      this.removeNotification(id);
-    }, duration);
+    if (duration > 0) {
+      setTimeout(() => {
+        this.closeNotification(id, 'expired');
+      }, duration);
+    }
 
     this.callbacks.onDirty();
     return id;
   }
 
   /**
-   * Remove a notification.
+   * Show a progress notification. It stays until closed with 'done'.
    */
-  removeNotification(id: string): boolean {
-    const idx = this.notifications.findIndex((n) => n.id === id);
-    if (idx === -1) return false;
+  showProgress(
+    message: string,
+    options: NotificationProgressState & { source?: string; onClose?: NotificationOptions['onClose'] } = {}
+  ): string {
+    const { source, onClose, ...progress } = options;
+    return this.notify(message, 'info', { source, onClose, progress })!;
+  }
+
+  /**
+   * Update an active notification's message, type or progress.
+   */
+  updateNotification(
+    id: string,
+    update: { message?: string; type?: NotificationType; percent?: number }
+  ): boolean {
+    const notification = this.notifications.find((n) => n.id === id);
+    if (!notification) return false;
+
+    if (update.message !== undefined) notification.message = update.message;
+    if (update.type !== undefined) notification.type = update.type;
+    if (update.percent !== undefined && notification.progress) {
+      notification.progress.percent = Math.max(0, Math.min(100, update.percent));
+    }
+    this.callbacks.onDirty();
+    return true;
+  }
+
+  /**
+   * Listen for notifications as they are shown (e.g. to announce them).
+   * Returns an unsubscribe function.
+   */
+  onNotification(listener: (notification: Notification) => void): () => void {
+    this.notificationListeners.add(listener);
+    return () => {
+      this.notificationListeners.delete(listener);
+    };
+  }
 
-    this.notifications.splice(idx, 1);
+  /**
+   * Close an active notification.
+   */
+  closeNotification(id: string, reason: NotificationCloseReason = 'dismissed'): boolean {
+    const notification = this.notifications.find((n) => n.id === id);
+    if (!notification) return false;
+
+    this.finishNotification(notification, reason);
     this.callbacks.onDirty();
     return true;
   }
 
+  /**
+   * Remove a notification.
+   */
+  removeNotification(id: string): boolean {
+    return this.closeNotification(id, 'dismissed');
+  }
+
   /**
    * Clear all notifications.
    */
   clearNotifications(): void {
+    for (const notification of [...this.notifications]) {
+      this.finishNotification(notification, 'dismissed');
+    }
     this.notifications = [];
     this.callbacks.onDirty();
   }
@@ -250,6 +422,123 @@ export class OverlayManager {
     return [...this.notifications];
   }
 
+  /**
+   * Run one of an active notification's actions. Closes the notification.
+   */
+  invokeNotificationAction(id: string, index: number): boolean {
+    const notification = this.notifications.find((n) => n.id === id);
+    const action = notification?.actions?.[index];
+    if (!notification || !action) return false;
+
+    this.closeNotification(id, 'action');
+    action.run();
+    return true;
+  }
+
+  /**
+   * Cancel an active, cancellable progress notification.
+   */
+  cancelNotification(id: string): boolean {
+    const notification = this.notifications.find((n) => n.id === id);
+    const onCancel = notification?.progress?.onCancel;
+    if (!notification || !onCancel) return false;
+
+    this.closeNotification(id, 'cancelled');
+    onCancel();
+    return true;
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Notification History
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /**
+   * Get shown notifications, newest first.
+   */
+  getNotificationHistory(): Notification[] {
+    return [...this.notificationHistory].reverse();
+  }
+
+  /**
+   * Forget closed notifications.
+   */
+  clearNotificationHistory(): void {
+    this.notificationHistory = this.notificationHistory.filter((n) => n.closedAt === undefined);
+    this.callbacks.onDirty();
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Do Not Show Again
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /**
+   * Stop showing a notification's kind: suppress its key and close it.
+   */
+  doNotShowAgain(id: string): boolean {
+    const notification = this.notificationHistory.find((n) => n.id === id);
+    const key = notification?.doNotShowAgainKey;
+    if (!notification || !key) return false;
+
+    this.suppressNotificationKey(key);
+    return true;
+  }
+
+  /**
+   * Suppress a "do not show again" key and close active notifications with it.
+   */
+  suppressNotificationKey(key: string): void {
+    if (this.suppressedNotifications.has(key)) return;
+    this.suppressedNotifications.add(key);
+
+    for (const notification of this.notifications.filter((n) => n.doNotShowAgainKey === key)) {
+      this.finishNotification(notification, 'dismissed');
+    }
+    for (const listener of this.doNotShowAgainListeners) {
+      listener(key);
+    }
+    this.callbacks.onDirty();
+  }
+
+  /**
+   * Replace the suppressed keys (e.g. from settings).
+   */
+  setSuppressedNotificationKeys(keys: string[]): void {
+    this.suppressedNotifications = new Set(keys);
+  }
+
+  /**
+   * Get the suppressed keys.
+   */
+  getSuppressedNotificationKeys(): string[] {
+    return [...this.suppressedNotifications];
+  }
+
+  /**
+   * Listen for "do not show again" choices (e.g. to persist them).
+   * Returns an unsubscribe function.
+   */
+  onDoNotShowAgain(listener: (key: string) => void): () => void {
+    this.doNotShowAgainListeners.add(listener);
+    return () => {
+      this.doNotShowAgainListeners.delete(listener);
+    };
+  }
+
+  /**
+   * Mark a notification closed and take it off screen, without re-rendering.
+   */
+  private finishNotification(notification: Notification, reason: NotificationCloseReason): void {
+    const idx = this.notifications.indexOf(notification);
+    if (idx !== -1) {
+      this.notifications.splice(idx, 1);
+    }
+    if (notification.closedAt !== undefined) return;
+
+    notification.closedAt = Date.now();
+    notification.closeReason = reason;
+    notification.onClose?.(reason);
+  }
+
   // ─────────────────────────────────────────────────────────────────────────
   // Input Handling
   // ─────────────────────────────────────────────────────────────────────────
@@ -269,6 +558,23 @@ export class OverlayManager {
     return false;
   }
 
+  /**
+   * Handle a mouse event on a toast: buttons run, the rest of the toast
+   * swallows the click.
+   * @returns true if the event hit a toast
+   */
+  handleNotificationMouse(event: MouseEvent): boolean {
+    const hit = this.notificationHitAreas.find(
+      (area) => event.y === area.y && event.x >= area.x && event.x < area.x + area.width
+    );
+    if (!hit) return false;
+
+    if (event.type === 'press' && event.button === 'left') {
+      hit.onClick?.();
+    }
+    return true;
+  }
+
   // ─────────────────────────────────────────────────────────────────────────
   // Rendering
   // ─────────────────────────────────────────────────────────────────────────
@@ -289,30 +595,41 @@ export class OverlayManager {
   }
 
   private renderNotifications(buffer: ScreenBuffer): void {
+    this.notificationHitAreas = [];
     if (this.notifications.length === 0) return;
 
     const size = this.callbacks.getScreenSize();
     const now = Date.now();
 
     // Remove expired notifications
-    this.notifications = this.notifications.filter(
-      (n) => now - n.createdAt < n.duration
-    );
+    for (const notification of [...this.notifications]) {
+      if (notification.duration > 0 && now - notification.createdAt >= notification.duration) {
+        this.finishNotification(notification, 'expired');
+      }
+    }
 
-    // Render from bottom-right
-    const startY = 1; // Below top edge
+    // Render from the top-right
     const maxWidth = Math.min(60, Math.floor(size.width * 0.6));
+    const x = size.width - maxWidth - 2;
+    let y = 1; // Below top edge
 
-    for (let i = 0; i < this.notifications.length; i++) {
-      const notification = this.notifications[i]!;
-      const y = startY + i;
-
-      if (y >= size.height - 1) break;
+    for (const notification of this.notifications) {
+      const height = this.hasNotificationControls(notification) ? 2 : 1;
+      if (y + height > size.height - 1) break;
 
-      this.renderNotification(buffer, notification, size.width - maxWidth - 2, y, maxWidth);
+      this.renderNotification(buffer, notification, x, y, maxWidth);
+      y += height;
     }
   }
 
+  private hasNotificationControls(notification: Notification): boolean {
+    return (
+      (notification.actions?.length ?? 0) > 0 ||
+      notification.progress !== undefined ||
+      notification.doNotShowAgainKey !== undefined
+    );
+  }
+
   private renderNotification(
     buffer: ScreenBuffer,
     notification: Notification,
@@ -322,26 +639,82 @@ export class OverlayManager {
   ): void {
     const colors = this.getNotificationColors(notification.type);
     const icon = this.getNotificationIcon(notification.type);
+    const closable = notification.duration <= 0;
 
-    // Build message
+    // Build message, leaving room for the close button on sticky toasts
+    const textWidth = closable ? maxWidth - 2 : maxWidth;
     let content = ` ${icon} ${notification.message} `;
-    if (content.length > maxWidth) {
-      content = content.slice(0, maxWidth - 1) + '…';
+    if (content.length > textWidth) {
+      content = content.slice(0, textWidth - 1) + '…';
     }
-
-    // Pad to maxWidth
-    while (content.length < maxWidth) {
-      content += ' ';
+    content = content.padEnd(textWidth);
+    if (closable) {
+      content += '× ';
     }
 
-    // Render
-    for (let i = 0; i < content.length; i++) {
-      buffer.set(x + i, y, {
-        char: content[i]!,
-        fg: colors.fg,
-        bg: colors.bg,
+    buffer.writeString(x, y, content, colors.fg, colors.bg);
+    this.notificationHitAreas.push({ x, y, width: maxWidth });
+    if (closable) {
+      this.notificationHitAreas.unshift({
+        x: x + maxWidth - 2,
+        y,
+        width: 1,
+        onClick: () => this.closeNotification(notification.id, 'dismissed'),
       });
     }
+
+    if (this.hasNotificationControls(notification)) {
+      this.renderNotificationControls(buffer, notification, x, y + 1, maxWidth, colors);
+    }
+  }
+
+  /**
+   * Draw the second toast line: progress bar and buttons.
+   */
+  private renderNotificationControls(
+    buffer: ScreenBuffer,
+    notification: Notification,
+    x: number,
+    y: number,
+    maxWidth: number,
+    colors: { fg: string; bg: string }
+  ): void {
+    buffer.writeString(x, y, ' '.repeat(maxWidth), colors.fg, colors.bg);
+    this.notificationHitAreas.push({ x, y, width: maxWidth });
+
+    let col = x + 3;
+    const progress = notification.progress;
+    if (progress) {
+      const bar = formatProgressBar(progress.percent, OverlayManager.PROGRESS_BAR_WIDTH);
+      buffer.writeString(col, y, bar, colors.fg, colors.bg);
+      col += bar.length + 1;
+    }
+
+    const buttons: NotificationHitArea[] = [];
+    const labels: string[] = [];
+    notification.actions?.forEach((action, index) => {
+      labels.push(action.label);
+      buttons.push({ x: 0, y, width: 0, onClick: () => this.invokeNotificationAction(notification.id, index) });
+    });
+    if (progress?.onCancel) {
+      labels.push(t('common.cancel'));
+      buttons.push({ x: 0, y, width: 0, onClick: () => this.cancelNotification(notification.id) });
+    }
+    if (notification.doNotShowAgainKey) {
+      labels.push(t('notifications.doNotShowAgain'));
+      buttons.push({ x: 0, y, width: 0, onClick: () => this.doNotShowAgain(notification.id) });
+    }
+
+    const buttonFg = this.callbacks.getThemeColor('button.foreground', '#ffffff');
+    const buttonBg = this.callbacks.getThemeColor('button.background', '#0e639c');
+    for (let i = 0; i < labels.length; i++) {
+      const text = ` ${labels[i]} `;
+      if (col + text.length > x + maxWidth - 1) break;
+
+      buffer.writeString(col, y, text, buttonFg, buttonBg);
+      this.notificationHitAreas.unshift({ ...buttons[i]!, x: col, width: text.length });
+      col += text.length + 1;
+    }
   }
 
   private getNotificationColors(type: NotificationType): { fg: string; bg: string } {
@@ -403,6 +776,23 @@ export class OverlayManager {
   }
 }
 
+// ============================================
+// Helpers
+// ============================================
+
+/**
+ * Format a progress bar, e.g. `█████░░░░░ 50%`. Indeterminate progress
+ * (no percentage) is drawn as an empty bar with an ellipsis.
+ */
+export function formatProgressBar(percent: number | undefined, width: number): string {
+  if (percent === undefined) {
+    return `${'░'.repeat(width)} …`;
+  }
+  const clamped = Math.max(0, Math.min(100, percent));
+  const filled = Math.round((clamped / 100) * width);
+  return `${'█'.repeat(filled)}${'░'.repeat(width - filled)} ${Math.round(clamped)}%`;
+}
+
 // ============================================
 // Base Dialog Implementation
 // ============================================
@@ -13,6 +13,7 @@ import {
 import type { OverlayManagerCallbacks } from './overlay-manager.ts';
 import type { LSPLocation } from '../../../services/lsp/types.ts';
 import type { DialogResult } from './promise-dialog.ts';
+import { t } from '../i18n/index.ts';
 
 // ============================================
 // Types
@@ -82,7 +83,7 @@ export class ReferencesPicker extends SearchableDialog<ReferenceItem> {
         title: `References (${items.length})`,
         width: 80,
         height: 20,
-        placeholder: 'Type to filter references...',
+        placeholder: t('references.placeholder'),
         showSearchInput: true,
         maxResults: 15,
       } as SearchableDialogConfig,
@@ -142,7 +143,7 @@ export class ReferencesPicker extends SearchableDialog<ReferenceItem> {
         try {
           preview = await previewLoader(loc.uri, loc.range.start.line);
         } catch {
-          preview = '(unable to load preview)';
+          preview = t('references.previewUnavailable');
         }
       }
 
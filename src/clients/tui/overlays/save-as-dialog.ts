@@ -11,6 +11,7 @@ import type { KeyEvent, MouseEvent, InputEvent } from '../types.ts';
 import type { ScreenBuffer } from '../rendering/buffer.ts';
 import type { FileService } from '../../../services/file/index.ts';
 import * as path from 'path';
+import { t } from '../i18n/index.ts';
 
 // ============================================
 // Types
@@ -118,7 +119,7 @@ export class SaveAsDialog extends PromiseDialog<string> {
     await this.loadDirectory();
 
     return this.showAsync({
-      title: config.title ?? 'Save As',
+      title: config.title ?? t('saveAs.title'),
       width: config.width ?? 70,
       height: config.height ?? 25,
     });
@@ -600,7 +601,7 @@ export class SaveAsDialog extends PromiseDialog<string> {
     }
 
     // Label
-    const label = 'Name: ';
+    const label = `${t('saveAs.name')} `;
     buffer.writeString(x + 1, y, label, dimFg, inputBg);
 
     // Filename with cursor-aware display
@@ -652,7 +653,7 @@ export class SaveAsDialog extends PromiseDialog<string> {
     const dirFg = this.callbacks.getThemeColor('terminal.ansiBrightBlue', '#61afef');
 
     if (this.entries.length === 0) {
-      buffer.writeString(x + 2, y + 1, 'Empty directory', dimFg, bg);
+      buffer.writeString(x + 2, y + 1, t('fileBrowser.emptyDirectory'), dimFg, bg);
       return;
     }
 
@@ -720,7 +721,7 @@ export class SaveAsDialog extends PromiseDialog<string> {
     const dimFg = this.callbacks.getThemeColor('descriptionForeground', '#888888');
     const bg = this.callbacks.getThemeColor('editorWidget.background', '#252526');
 
-    const helpText = 'Tab:switch  \u2191\u2193:nav  \u2190:up  Enter:save  Esc:cancel';
+    const helpText = t('saveAs.help');
     const truncatedHelp = helpText.slice(0, width - 2);
     buffer.writeString(x + 1, y, truncatedHelp, dimFg, bg);
   }
@@ -748,19 +749,19 @@ export class SaveAsDialog extends PromiseDialog<string> {
     buffer.drawBox({ x: dialogX, y: dialogY, width: dialogWidth, height: dialogHeight }, border, bg, 'rounded');
 
     // Title
-    const title = ' Confirm Overwrite ';
+    const title = ` ${t('saveAs.confirmOverwrite')} `;
     const titleX = dialogX + Math.floor((dialogWidth - title.length) / 2);
     buffer.writeString(titleX, dialogY, title, border, bg);
 
     // Message
     const filename = path.basename(this.confirmPath);
-    const msg1 = 'File already exists:';
+    const msg1 = t('saveAs.fileExists');
     const msg2 = filename.length > dialogWidth - 6 ? filename.slice(0, dialogWidth - 9) + '...' : filename;
     buffer.writeString(dialogX + 2, dialogY + 2, msg1, fg, bg);
     buffer.writeString(dialogX + 2, dialogY + 3, msg2, warnFg, bg);
 
     // Options
-    const options = 'Overwrite? (Y)es / (N)o';
+    const options = t('saveAs.overwritePrompt');
     const optX = dialogX + Math.floor((dialogWidth - options.length) / 2);
     buffer.writeString(optX, dialogY + 5, options, successFg, bg);
   }
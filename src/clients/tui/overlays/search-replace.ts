@@ -7,6 +7,7 @@
 import { BaseDialog, type OverlayManagerCallbacks } from './overlay-manager.ts';
 import type { InputEvent, KeyEvent, MouseEvent, Rect } from '../types.ts';
 import type { ScreenBuffer } from '../rendering/buffer.ts';
+import { t } from '../i18n/index.ts';
 
 // ============================================
 // Types
@@ -293,7 +294,7 @@ export class SearchReplaceDialog extends BaseDialog {
     const warningFg = this.callbacks.getThemeColor('editorWarning.foreground', '#cca700');
 
     // Draw dialog box
-    const title = this.replaceMode ? 'Find and Replace' : 'Find';
+    const title = this.replaceMode ? t('search.findAndReplace') : t('search.find');
     this.drawDialogBox(buffer, title);
 
     const contentX = x + 2;
@@ -304,7 +305,7 @@ export class SearchReplaceDialog extends BaseDialog {
 
     // Search field
     const labelWidth = 10;
-    buffer.writeString(contentX, rowY, 'Find:', fg, bg);
+    buffer.writeString(contentX, rowY, t('search.findLabel'), fg, bg);
 
     const inputWidth = width - labelWidth - 4;
     const searchInputX = contentX + labelWidth;
@@ -334,7 +335,7 @@ export class SearchReplaceDialog extends BaseDialog {
     // Match count
     const matchText = this.matches.length > 0
       ? `${this.currentMatchIndex + 1}/${this.matches.length}`
-      : 'No results';
+      : t('common.noResults');
     const matchX = x + width - 2 - matchText.length;
     buffer.writeString(matchX, rowY, matchText, this.matches.length > 0 ? fg : warningFg, bg);
 
@@ -342,7 +343,7 @@ export class SearchReplaceDialog extends BaseDialog {
 
     // Replace field (if enabled)
     if (this.replaceMode) {
-      buffer.writeString(contentX, rowY, 'Replace:', fg, bg);
+      buffer.writeString(contentX, rowY, t('search.replaceLabel'), fg, bg);
 
       const replaceInputX = contentX + labelWidth;
       const replaceFieldBg = this.focusedElement === 'replace' ? inputBg : inputBg;
@@ -379,7 +380,7 @@ export class SearchReplaceDialog extends BaseDialog {
     let optX = optionsX;
 
     // Case sensitive toggle
-    const caseLabel = 'Aa';
+    const caseLabel = 'Aa'; // i18n-ignore
     const caseFocused = this.focusedElement === 'caseSensitive';
     const caseBg = this.options.caseSensitive ? activeOptionBg : (caseFocused ? focusBorder : inputBg);
     const caseFg = this.options.caseSensitive || caseFocused ? activeOptionFg : dimFg;
@@ -430,7 +431,7 @@ export class SearchReplaceDialog extends BaseDialog {
 
     // Help text at bottom
     const helpY = y + height - 1;
-    const helpText = 'Tab: Cycle | Enter: Activate | Esc: Close';
+    const helpText = t('search.help');
     buffer.writeString(x + 2, helpY, helpText, dimFg, bg);
   }
 
@@ -7,8 +7,9 @@
 
 import { PromiseDialog, type DialogConfig, type DialogResult } from './promise-dialog.ts';
 import type { OverlayManagerCallbacks } from './overlay-manager.ts';
-import type { KeyEvent, MouseEvent } from '../types.ts';
+import type { KeyEvent, MouseEvent, Position } from '../types.ts';
 import type { ScreenBuffer } from '../rendering/buffer.ts';
+import { t } from '../i18n/index.ts';
 
 // ============================================
 // Types
@@ -74,7 +75,7 @@ export abstract class SearchableDialog<T> extends PromiseDialog<T> {
   // Configuration
   protected maxVisibleResults: number = 15;
   protected showSearchInput: boolean = true;
-  protected placeholder: string = 'Type to search...';
+  protected placeholder: string = t('searchable.placeholder');
   protected searchDialogConfig: SearchableDialogConfig | null = null;
 
   // Highlight tracking
@@ -122,7 +123,7 @@ export abstract class SearchableDialog<T> extends PromiseDialog<T> {
     this.selectedIndex = 0;
     this.scrollOffset = 0;
     this.showSearchInput = config.showSearchInput !== false;
-    this.placeholder = config.placeholder ?? 'Type to search...';
+    this.placeholder = config.placeholder ?? t('searchable.placeholder');
     this.maxVisibleResults = config.maxResults ?? 15;
     this.searchDialogConfig = config;
 
@@ -393,6 +394,29 @@ export abstract class SearchableDialog<T> extends PromiseDialog<T> {
   // Rendering
   // ─────────────────────────────────────────────────────────────────────────
 
+  // ─────────────────────────────────────────────────────────────────────────
+  // Accessibility
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /**
+   * Title and the selected item with its position in the results.
+   */
+  getAccessibleText(): string {
+    const selected = this.filteredItems[this.selectedIndex];
+    if (!selected) {
+      return `${this.title}\n${t('common.noResults')}`;
+    }
+    const display = this.getItemDisplay(selected.item, true);
+    const item = display.secondary ? `${display.text}, ${display.secondary}` : display.text;
+    return `${this.title}\n${t('common.itemPosition', { item, index: this.selectedIndex + 1, count: this.filteredItems.length })}`;
+  }
+
+  getCursorPosition(): Position | null {
+    if (!this.showSearchInput) return null;
+    const content = this.getContentBounds();
+    return { x: Math.min(content.x + 2 + this.query.length, content.x + content.width - 1), y: content.y };
+  }
+
   protected override renderContent(buffer: ScreenBuffer): void {
     const content = this.getContentBounds();
 
@@ -478,7 +502,7 @@ export abstract class SearchableDialog<T> extends PromiseDialog<T> {
     this.maxVisibleResults = Math.max(1, height);
 
     if (this.filteredItems.length === 0) {
-      const msg = this.query ? 'No matching items' : 'No items available';
+      const msg = this.query ? t('searchable.noMatches') : t('searchable.noItems');
       buffer.writeString(x + 1, y + 1, msg, dimFg, bg);
       return;
     }
@@ -16,6 +16,7 @@ import {
   isMultilineSetting,
   parseSettingValue,
 } from './settings-utils.ts';
+import { t } from '../i18n/index.ts';
 
 // ============================================
 // Types
@@ -127,8 +128,8 @@ export class SettingsDialog extends SearchableDialog<SettingItem> {
     return this.showWithItems(
       {
         ...options,
-        title: options.title ?? 'Settings',
-        placeholder: options.placeholder ?? 'Search settings...',
+        title: options.title ?? t('settings.title'),
+        placeholder: options.placeholder ?? t('settings.placeholder'),
         width: options.width ?? 80,
         height: options.height ?? 25,
       },
@@ -240,7 +241,7 @@ export class SettingsDialog extends SearchableDialog<SettingItem> {
         newValue = parseSettingValue(selected.key, this.editValue, 'number');
         const validation = validateNumberSetting(selected.key, newValue as number);
         if (!validation.valid) {
-          this.editError = validation.message ?? 'Invalid value';
+          this.editError = validation.message ?? t('settings.invalidValue');
           this.callbacks.onDirty();
           return;
         }
@@ -616,7 +617,7 @@ export class SettingsDialog extends SearchableDialog<SettingItem> {
     this.maxVisibleResults = Math.max(1, height);
 
     if (this.filteredItems.length === 0) {
-      const msg = this.query ? 'No matching settings' : 'No settings available';
+      const msg = this.query ? t('settings.noMatches') : t('settings.noSettings');
       buffer.writeString(x + 1, y + 1, msg, dimFg, bg);
       return;
     }
@@ -751,20 +752,20 @@ export class SettingsDialog extends SearchableDialog<SettingItem> {
       // Edit mode hints
       switch (this.editMode) {
         case 'number':
-          hints = 'Enter: confirm | ↑/↓: adjust | Esc: cancel';
+          hints = t('settings.hintNumber');
           break;
         case 'string':
-          hints = 'Enter: confirm | ←/→: move cursor | Esc: cancel';
+          hints = t('settings.hintText');
           break;
         case 'enum':
-          hints = 'Enter: confirm | ←/→: change | Esc: cancel';
+          hints = t('settings.hintEnum');
           break;
         default:
-          hints = 'Enter: confirm | Esc: cancel';
+          hints = t('settings.hintEdit');
       }
     } else {
       // Normal mode hints
-      hints = 'Enter: edit | Space: toggle | R: reset | Esc: close';
+      hints = t('settings.hintBrowse');
     }
 
     const count = `${this.filteredItems.length}/${this.items.length}`;
@@ -780,7 +781,7 @@ export class SettingsDialog extends SearchableDialog<SettingItem> {
 
   private formatValue(item: SettingItem): string {
     if (item.value === undefined || item.value === null) {
-      return 'not set';
+      return t('settings.notSet');
     }
 
     switch (item.type) {
@@ -5,6 +5,7 @@
  */
 
 import type { SettingItem } from './settings-dialog.ts';
+import { t } from '../i18n/index.ts';
 
 // ============================================
 // Type Definitions
@@ -19,7 +20,9 @@ export type SettingType = 'string' | 'number' | 'boolean' | 'enum' | 'object';
 export const ENUM_OPTIONS: Record<string, string[]> = {
   // Editor enums
   'editor.autoIndent': ['none', 'keep', 'full'],
+  'editor.foldingStrategy': ['auto', 'indentation'],
   'editor.autoClosingBrackets': ['always', 'languageDefined', 'beforeWhitespace', 'never'],
+  'editor.autoSurround': ['languageDefined', 'quotes', 'brackets', 'never'],
   'editor.wordWrap': ['off', 'on', 'wordWrapColumn', 'bounded'],
   'editor.lineNumbers': ['on', 'off', 'relative'],
   'editor.minimap.showSlider': ['always', 'mouseover'],
@@ -31,6 +34,7 @@ export const ENUM_OPTIONS: Record<string, string[]> = {
   'files.watchFiles': ['onFocus', 'always', 'off'],
 
   // Workbench enums
+  'workbench.locale': ['auto', 'en', 'de', 'ja'],
   'tui.sidebar.location': ['left', 'right'],
 
   // Terminal enums
@@ -44,10 +48,13 @@ export const ENUM_OPTIONS: Record<string, string[]> = {
 
   // LSP enums
   'lsp.signatureHelp.display': ['popup', 'inline', 'statusBar'],
+  'lsp.trace': ['off', 'messages', 'verbose'],
 
   // TUI enums
   'tui.diffViewer.editMode': ['stage-modified', 'save-only', 'auto-stage'],
   'tui.timeline.mode': ['file', 'repo'],
+  'tui.accessibility.announcementChannel': ['statusLine', 'ecp', 'both'],
+  'tui.imagePreview.protocol': ['auto', 'kitty', 'sixel', 'halfblock'],
 };
 
 /**
@@ -68,9 +75,15 @@ export const SETTING_DESCRIPTIONS: Record<string, string> = {
   'editor.insertSpaces': 'Use spaces instead of tabs',
   'editor.autoIndent': 'Auto-indent mode',
   'editor.autoClosingBrackets': 'Auto-close brackets mode',
+  'editor.autoClosingTags': 'Auto-close HTML/JSX tags',
+  'editor.autoSurround': 'Surround selections with typed brackets/quotes',
+  'editor.emmet.triggerExpansionOnTab': 'Expand Emmet abbreviations with Tab',
   'editor.wordWrap': 'Word wrap mode',
   'editor.lineNumbers': 'Line numbers mode',
   'editor.folding': 'Enable code folding',
+  'editor.foldingStrategy': 'Folding ranges: from the language server when available, or by indentation',
+  'editor.links': 'Underline links from the language server (Ctrl+click to open)',
+  'editor.colorDecorators': 'Show colour swatches for colour values',
   'editor.renderWhitespace': 'Show whitespace mode',
   'editor.mouseWheelScrollSensitivity': 'Scroll speed multiplier (1-10)',
   'editor.cursorBlinkRate': 'Cursor blink interval in milliseconds',
@@ -93,6 +106,7 @@ export const SETTING_DESCRIPTIONS: Record<string, string> = {
   // Workbench
   'workbench.colorTheme': 'Color theme name',
   'workbench.startupEditor': 'File to open on startup (empty for none)',
+  'workbench.locale': 'UI language (auto follows LANG)',
 
   // TUI Sidebar
   'tui.sidebar.width': 'Sidebar width in characters',
@@ -101,6 +115,9 @@ export const SETTING_DESCRIPTIONS: Record<string, string> = {
   'tui.sidebar.focusedBackground': 'Sidebar focused item background color',
   'tui.terminal.height': 'Terminal panel height in rows',
   'tui.terminal.scrollback': 'Terminal scrollback buffer size in lines',
+  'tui.terminal.watchRules': 'Output watch rules applied to every terminal',
+  'tui.terminal.promptPattern': 'Regex matching your shell prompt (empty for default)',
+  'tui.terminal.finishedActions': 'Actions when a watched command finishes',
   'tui.tabBar.scrollAmount': 'Number of tabs to scroll when using scroll buttons',
 
   // TUI Diff Viewer
@@ -117,6 +134,11 @@ export const SETTING_DESCRIPTIONS: Record<string, string> = {
   'tui.timeline.mode': 'Timeline mode: file (current file) or repo (all commits)',
   'tui.timeline.commitCount': 'Number of commits to show in timeline',
 
+  // TUI Accessibility
+  'tui.accessibility.screenReaderMode': 'Screen reader mode (announcements, cursor at focus)',
+  'tui.accessibility.announcementChannel': 'Where screen reader announcements are sent',
+  'tui.accessibility.bridgeSocket': 'Unix socket of an external screen reader bridge',
+
   // Git
   'git.statusInterval': 'Git status refresh interval in milliseconds',
   'git.panel.location': 'Git panel location',
@@ -127,6 +149,7 @@ export const SETTING_DESCRIPTIONS: Record<string, string> = {
 
   // AI
   'ai.defaultProvider': 'Default AI provider',
+  'ai.customProviders': 'Custom AI chat providers (command, args, env, resume and session ID capture)',
   'ai.panel.defaultWidth': 'AI panel width in characters',
   'ai.panel.maxWidthPercent': 'Maximum AI panel width as percentage of screen',
   'ai.panel.openOnStartup': 'Open AI panel on startup',
@@ -147,6 +170,7 @@ export const SETTING_DESCRIPTIONS: Record<string, string> = {
   'lsp.enabled': 'Enable language server features',
   'lsp.completionDebounceMs': 'Delay before triggering completion (ms)',
   'lsp.triggerCharacters': 'Characters that trigger completion',
+  'lsp.completion.showDocumentation': 'Show documentation beside the completion list',
   'lsp.hover.enabled': 'Show hover information',
   'lsp.signatureHelp.enabled': 'Show function signature help',
   'lsp.signatureHelp.display': 'Signature display mode',
@@ -154,6 +178,27 @@ export const SETTING_DESCRIPTIONS: Record<string, string> = {
   'lsp.diagnostics.showInGutter': 'Show diagnostic icons in gutter',
   'lsp.diagnostics.underlineErrors': 'Underline errors in editor',
   'lsp.diagnostics.delay': 'Delay before showing diagnostics (ms)',
+  'lsp.trace': 'Trace level for LSP traffic (off, messages, verbose)',
+};
+
+/**
+ * Setting descriptions that go through the message catalog. These take
+ * precedence over SETTING_DESCRIPTIONS.
+ */
+export const LOCALIZED_SETTING_DESCRIPTIONS: Record<string, () => string> = {
+  'tui.imagePreview.protocol': () => t('settingDescriptions.tui.imagePreview.protocol'),
+  'tui.repl.commands': () => t('settingDescriptions.tui.repl.commands'),
+  'tui.todo.collapsedOnStartup': () => t('settingDescriptions.tui.todo.collapsedOnStartup'),
+  'tui.todo.tags': () => t('settingDescriptions.tui.todo.tags'),
+  'tui.dependencyGraph.includeExternal': () => t('settingDescriptions.tui.dependencyGraph.includeExternal'),
+  'tui.dependencyGraph.layers': () => t('settingDescriptions.tui.dependencyGraph.layers'),
+  'tui.zenMode.width': () => t('settingDescriptions.tui.zenMode.width'),
+  'tui.zenMode.dimOutsideParagraph': () => t('settingDescriptions.tui.zenMode.dimOutsideParagraph'),
+  'tui.tabBar.enablePreview': () => t('settingDescriptions.tui.tabBar.enablePreview'),
+  'tui.notifications.doNotShowAgain': () => t('settingDescriptions.tui.notifications.doNotShowAgain'),
+  'tui.keybindings.leader': () => t('settingDescriptions.tui.keybindings.leader'),
+  'tui.whichKey.enabled': () => t('settingDescriptions.tui.whichKey.enabled'),
+  'tui.whichKey.delay': () => t('settingDescriptions.tui.whichKey.delay'),
 };
 
 // ============================================
@@ -196,7 +241,7 @@ export function getEnumOptions(key: string): string[] | null {
  * Get description for a setting key.
  */
 export function getSettingDescription(key: string): string | undefined {
-  return SETTING_DESCRIPTIONS[key];
+  return LOCALIZED_SETTING_DESCRIPTIONS[key]?.() ?? SETTING_DESCRIPTIONS[key];
 }
 
 /**
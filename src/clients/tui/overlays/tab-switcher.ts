@@ -3,10 +3,12 @@
  *
  * Searchable dialog for switching between open tabs.
  * Can show tabs from current pane only or all panes.
+ * Tabs are listed most recently used first.
  */
 
 import { SearchableDialog, type SearchableDialogConfig, type ItemDisplay } from './searchable-dialog.ts';
 import type { OverlayManagerCallbacks } from './overlay-manager.ts';
+import type { DialogResult } from './promise-dialog.ts';
 
 // ============================================
 // Types
@@ -28,6 +30,16 @@ export interface TabInfo {
   isModified?: boolean;
   /** Optional file path for display */
   filePath?: string;
+  /** When the tab was last used (higher is more recent) */
+  lastUsed?: number;
+}
+
+/**
+ * Order tabs most recently used first. Tabs without a use time keep
+ * their order at the end.
+ */
+export function orderTabsByRecentUse(tabs: readonly TabInfo[]): TabInfo[] {
+  return [...tabs].sort((a, b) => (b.lastUsed ?? 0) - (a.lastUsed ?? 0));
 }
 
 // ============================================
@@ -42,6 +54,16 @@ export class TabSwitcherDialog extends SearchableDialog<TabInfo> {
     super('tab-switcher', callbacks);
   }
 
+  /**
+   * Show tabs most recently used first, with the previously used tab
+   * selected so Enter switches back to it.
+   */
+  showTabs(config: SearchableDialogConfig, tabs: readonly TabInfo[]): Promise<DialogResult<TabInfo>> {
+    const ordered = orderTabsByRecentUse(tabs);
+    const previous = ordered.find((tab) => !tab.isActive) ?? ordered[0];
+    return this.showWithItems(config, ordered, previous?.id);
+  }
+
   // ─────────────────────────────────────────────────────────────────────────
   // SearchableDialog Implementation
   // ─────────────────────────────────────────────────────────────────────────
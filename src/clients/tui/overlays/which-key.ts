@@ -0,0 +1,282 @@
+/**
+ * Which-Key Popup
+ *
+ * Lists the keys that can follow a pending chord prefix (e.g. `ctrl+k` or
+ * the leader key) together with the titles of the commands they run.
+ * Non-modal: keys pass through to the window's keybinding handler.
+ */
+
+import type { Overlay, OverlayManagerCallbacks } from './overlay-manager.ts';
+import type { Rect, InputEvent } from '../types.ts';
+import type { ScreenBuffer } from '../rendering/buffer.ts';
+import { t } from '../i18n/index.ts';
+
+// ============================================
+// Types
+// ============================================
+
+/**
+ * A keybinding as seen by the popup: a normalized key sequence
+ * (e.g. `ctrl+k ctrl+c`) and the title of its command.
+ */
+export interface WhichKeyBinding {
+  key: string;
+  title: string;
+}
+
+/**
+ * A possible continuation of a chord prefix.
+ */
+export interface WhichKeyEntry {
+  /** Next key to press */
+  key: string;
+  /** Command title, when the key completes a binding */
+  title?: string;
+  /** Number of bindings reachable through the key, when it starts a longer sequence */
+  groupSize?: number;
+}
+
+// ============================================
+// Helpers
+// ============================================
+
+/**
+ * Compute the keys that can follow `prefix`, sorted by key. A key that both
+ * completes a binding and starts a longer one is listed as a group, since
+ * the window waits for the longer sequence. The first binding for a key wins,
+ * matching the order the window runs them in.
+ */
+export function getWhichKeyEntries(prefix: string, bindings: WhichKeyBinding[]): WhichKeyEntry[] {
+  const entries = new Map<string, WhichKeyEntry>();
+  const start = prefix + ' ';
+
+  for (const binding of bindings) {
+    if (!binding.key.startsWith(start)) continue;
+
+    const rest = binding.key.slice(start.length).split(' ');
+    const next = rest[0]!;
+    const entry = entries.get(next) ?? { key: next };
+    if (rest.length > 1) {
+      entry.groupSize = (entry.groupSize ?? 0) + 1;
+    } else if (entry.title === undefined) {
+      entry.title = binding.title;
+    }
+    entries.set(next, entry);
+  }
+
+  return [...entries.values()].sort((a, b) => a.key.localeCompare(b.key));
+}
+
+/**
+ * Describe an entry, e.g. `Toggle Zen Mode` or `+3 commands`.
+ */
+export function describeWhichKeyEntry(entry: WhichKeyEntry): string {
+  if (entry.groupSize !== undefined) {
+    return t('whichKey.group', { count: entry.groupSize });
+  }
+  return entry.title ?? '';
+}
+
+// ============================================
+// Which-Key Popup
+// ============================================
+
+export class WhichKeyPopup implements Overlay {
+  readonly id: string;
+  zIndex = 280; // Above hover and signature help, below autocomplete
+
+  /** Pending chord prefix */
+  private prefix = '';
+  /** Continuations of the prefix */
+  private entries: WhichKeyEntry[] = [];
+  /** Row just above the status bar */
+  private bottom = 0;
+
+  /** Visibility state */
+  private visible = false;
+  /** Overlay bounds */
+  private bounds: Rect = { x: 0, y: 0, width: 0, height: 0 };
+
+  /** Callbacks */
+  private callbacks: OverlayManagerCallbacks;
+
+  /** Called when the popup is dismissed with Escape */
+  private dismissCallback: (() => void) | null = null;
+
+  constructor(id: string, callbacks: OverlayManagerCallbacks) {
+    this.id = id;
+    this.callbacks = callbacks;
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Public API
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /**
+   * Show the continuations of a chord prefix.
+   *
+   * @param prefix Keys pressed so far
+   * @param entries Possible next keys
+   * @param bottom Screen row the popup sits on top of (usually the status bar)
+   */
+  showEntries(prefix: string, entries: WhichKeyEntry[], bottom: number): void {
+    this.prefix = prefix;
+    this.entries = entries;
+    this.bottom = bottom;
+    this.visible = entries.length > 0;
+    this.callbacks.onDirty();
+  }
+
+  /**
+   * Get the listed continuations.
+   */
+  getEntries(): WhichKeyEntry[] {
+    return this.entries;
+  }
+
+  /**
+   * Get the prefix the popup was shown for.
+   */
+  getPrefix(): string {
+    return this.prefix;
+  }
+
+  /**
+   * Set the callback for dismissal with Escape.
+   */
+  onDismissed(callback: () => void): void {
+    this.dismissCallback = callback;
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Overlay Interface
+  // ─────────────────────────────────────────────────────────────────────────
+
+  isVisible(): boolean {
+    return this.visible;
+  }
+
+  show(): void {
+    this.visible = true;
+    this.callbacks.onDirty();
+  }
+
+  hide(): void {
+    if (this.visible) {
+      this.visible = false;
+      this.callbacks.onDirty();
+    }
+  }
+
+  setBounds(bounds: Rect): void {
+    this.bounds = bounds;
+  }
+
+  getBounds(): Rect {
+    return this.bounds;
+  }
+
+  onDismiss(): void {
+    this.dismissCallback?.();
+  }
+
+  getAccessibleText(): string {
+    const items = this.entries.map((entry) => `${entry.key}: ${describeWhichKeyEntry(entry)}`);
+    return [this.prefix, ...items].join('\n');
+  }
+
+  /**
+   * Never consumes input; the window completes or cancels the chord.
+   */
+  handleInput(_event: InputEvent): boolean {
+    return false;
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Rendering
+  // ─────────────────────────────────────────────────────────────────────────
+
+  render(buffer: ScreenBuffer): void {
+    if (!this.visible || this.entries.length === 0) return;
+
+    const bg = this.callbacks.getThemeColor('editorWidget.background', '#252526');
+    const fg = this.callbacks.getThemeColor('editorWidget.foreground', '#cccccc');
+    const border = this.callbacks.getThemeColor('editorWidget.border', '#454545');
+    const keyFg = this.callbacks.getThemeColor('textLink.foreground', '#3794ff');
+    const groupFg = this.callbacks.getThemeColor('descriptionForeground', '#888888');
+
+    const layout = this.calculateLayout();
+    this.bounds = layout.bounds;
+    const { x, y, width, height } = layout.bounds;
+
+    for (let row = y; row < y + height; row++) {
+      for (let col = x; col < x + width; col++) {
+        buffer.set(col, row, { char: ' ', fg, bg });
+      }
+    }
+    buffer.drawBox(layout.bounds, border, bg, 'rounded');
+    buffer.writeString(x + 2, y, ` ${this.prefix} … `, fg, bg);
+
+    const visible = layout.rows * layout.columns;
+    for (let i = 0; i < Math.min(visible, this.entries.length); i++) {
+      const entry = this.entries[i]!;
+      const column = Math.floor(i / layout.rows);
+      const cellX = x + 2 + column * layout.cellWidth;
+      const cellY = y + 1 + (i % layout.rows);
+
+      const keyText = entry.key.padStart(layout.keyWidth);
+      buffer.writeString(cellX, cellY, keyText, keyFg, bg);
+      buffer.writeString(cellX + layout.keyWidth, cellY, ' → ', groupFg, bg);
+
+      const titleWidth = layout.cellWidth - layout.keyWidth - 5;
+      let title = describeWhichKeyEntry(entry);
+      if (title.length > titleWidth) {
+        title = title.slice(0, Math.max(0, titleWidth - 1)) + '…';
+      }
+      buffer.writeString(cellX + layout.keyWidth + 3, cellY, title, entry.groupSize !== undefined ? groupFg : fg, bg);
+    }
+
+    // Entries that don't fit
+    const hidden = this.entries.length - visible;
+    if (hidden > 0) {
+      const more = ` ${t('whichKey.more', { count: hidden })} `;
+      buffer.writeString(x + width - more.length - 2, y + height - 1, more, groupFg, bg);
+    }
+  }
+
+  /**
+   * Lay out the entries column by column across the full width,
+   * using at most half of the rows above the status bar.
+   */
+  private calculateLayout(): { bounds: Rect; rows: number; columns: number; keyWidth: number; cellWidth: number } {
+    const screen = this.callbacks.getScreenSize();
+    const width = screen.width;
+    const keyWidth = Math.max(...this.entries.map((e) => e.key.length));
+    const titleWidth = Math.min(32, Math.max(...this.entries.map((e) => describeWhichKeyEntry(e).length)));
+    const cellWidth = keyWidth + 3 + titleWidth + 2;
+
+    const columns = Math.max(1, Math.floor((width - 4) / cellWidth));
+    const maxRows = Math.max(1, Math.floor(this.bottom / 2) - 2);
+    const rows = Math.min(maxRows, Math.ceil(this.entries.length / columns));
+    const height = rows + 2;
+
+    return {
+      bounds: { x: 0, y: Math.max(0, this.bottom - height), width, height },
+      rows,
+      columns,
+      keyWidth,
+      cellWidth: Math.max(cellWidth, Math.floor((width - 4) / columns)),
+    };
+  }
+}
+
+// ============================================
+// Factory Function
+// ============================================
+
+/**
+ * Create a which-key popup.
+ */
+export function createWhichKeyPopup(id: string, callbacks: OverlayManagerCallbacks): WhichKeyPopup {
+  return new WhichKeyPopup(id, callbacks);
+}
@@ -0,0 +1,162 @@
+/**
+ * Graphics Layer
+ *
+ * Keeps inline images (Kitty or sixel) in sync with the cell grid. Images
+ * live outside the screen buffer, so after each frame the client hands the
+ * layer the placements that should be visible; the layer sends only what
+ * changed and removes images that went away.
+ *
+ * Kitty images float above the text and persist until deleted. Sixel
+ * pixels are overwritten by any cell drawn on top, so a sixel image is
+ * redrawn whenever cells under it were repainted, and removing one just
+ * repaints the cells it covered.
+ */
+
+import type { Rect } from '../types.ts';
+import type { ScreenBuffer } from './buffer.ts';
+import type { PixelBuffer } from '../../../core/image/index.ts';
+import { cursorRestore, cursorSave, cursorToZero } from '../ansi/sequences.ts';
+import { kittyDelete, kittyImage, sixelImage, type GraphicsProtocol } from '../ansi/graphics.ts';
+
+// ============================================
+// Types
+// ============================================
+
+/**
+ * An image to show in a rectangle of cells.
+ */
+export interface GraphicsPlacement {
+  /** Stable key of the owner (e.g. element id) */
+  key: string;
+  /** Cell rectangle the image covers */
+  rect: Rect;
+  /** Changes whenever the pixels change (e.g. path, zoom, pan) */
+  signature: string;
+  /** Produces the pixels; only called when the image must be (re)sent */
+  getPixels: () => PixelBuffer;
+  /** Background for compositing translucent pixels (sixel) */
+  background: { r: number; g: number; b: number };
+}
+
+interface ActivePlacement {
+  id: number;
+  rect: Rect;
+  signature: string;
+}
+
+// ============================================
+// Graphics Layer
+// ============================================
+
+export class GraphicsLayer {
+  private protocol: GraphicsProtocol;
+  private active = new Map<string, ActivePlacement>();
+  private nextId = 1;
+
+  constructor(protocol: GraphicsProtocol) {
+    this.protocol = protocol;
+  }
+
+  getProtocol(): GraphicsProtocol {
+    return this.protocol;
+  }
+
+  /**
+   * Switch protocols. Returns output that removes the current images.
+   */
+  setProtocol(protocol: GraphicsProtocol, buffer: ScreenBuffer): string {
+    if (protocol === this.protocol) return '';
+    const output = this.clear(buffer);
+    this.protocol = protocol;
+    return output;
+  }
+
+  /**
+   * Reconcile the visible placements with what's on screen. Call before
+   * flushing the frame (sixel removals mark cells dirty so the flush
+   * repaints them) and write the returned output after the flush.
+   */
+  update(placements: GraphicsPlacement[], buffer: ScreenBuffer): string {
+    if (this.protocol === 'halfblock') {
+      return this.clear(buffer);
+    }
+
+    let output = '';
+    const seen = new Set<string>();
+
+    for (const placement of placements) {
+      seen.add(placement.key);
+      const previous = this.active.get(placement.key);
+      const moved = !previous || !sameRect(previous.rect, placement.rect);
+      const changed = moved || previous.signature !== placement.signature;
+      const repainted = this.protocol === 'sixel' && isRegionDirty(buffer, placement.rect);
+      if (!changed && !repainted) continue;
+
+      if (previous && moved) {
+        output += this.remove(previous, buffer);
+      }
+      const id = previous?.id ?? this.nextId++;
+      output += this.draw(id, placement);
+      this.active.set(placement.key, { id, rect: { ...placement.rect }, signature: placement.signature });
+    }
+
+    for (const [key, previous] of this.active) {
+      if (!seen.has(key)) {
+        output += this.remove(previous, buffer);
+        this.active.delete(key);
+      }
+    }
+
+    return output;
+  }
+
+  /**
+   * Remove all images. Returns the output to write after the next flush.
+   */
+  clear(buffer: ScreenBuffer): string {
+    let output = '';
+    for (const placement of this.active.values()) {
+      output += this.remove(placement, buffer);
+    }
+    this.active.clear();
+    return output;
+  }
+
+  /**
+   * Forget placements without removing them (the screen was cleared).
+   */
+  reset(): void {
+    this.active.clear();
+  }
+
+  private draw(id: number, placement: GraphicsPlacement): string {
+    const pixels = placement.getPixels();
+    const { x, y, width, height } = placement.rect;
+    const image =
+      this.protocol === 'kitty'
+        ? kittyImage(pixels, { id, columns: width, rows: height })
+        : sixelImage(pixels, placement.background);
+    return cursorSave() + cursorToZero(y, x) + image + cursorRestore();
+  }
+
+  private remove(placement: ActivePlacement, buffer: ScreenBuffer): string {
+    if (this.protocol === 'kitty') {
+      return kittyDelete(placement.id);
+    }
+    buffer.markDirty(placement.rect);
+    return '';
+  }
+}
+
+function sameRect(a: Rect, b: Rect): boolean {
+  return a.x === b.x && a.y === b.y && a.width === b.width && a.height === b.height;
+}
+
+function isRegionDirty(buffer: ScreenBuffer, rect: Rect): boolean {
+  for (let y = rect.y; y < rect.y + rect.height; y++) {
+    for (let x = rect.x; x < rect.x + rect.width; x++) {
+      if (buffer.isDirty(x, y)) return true;
+    }
+  }
+  return false;
+}
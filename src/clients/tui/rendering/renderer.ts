@@ -290,6 +290,14 @@ export class Renderer {
     this.output(cursorHide());
   }
 
+  /**
+   * Write a raw control sequence to the terminal (bell, notifications).
+   * Must not move the cursor or change cell contents.
+   */
+  writeRaw(data: string): void {
+    this.output(data);
+  }
+
   // ─────────────────────────────────────────────────────────────────────────
   // State Queries
   // ─────────────────────────────────────────────────────────────────────────
@@ -82,6 +82,9 @@ export class StatusBar {
   /** Scroll offset in expanded view */
   private scrollOffset = 0;
 
+  /** Speech line shown instead of the items in screen-reader mode */
+  private speechLine: string | null = null;
+
   /** Callbacks */
   private callbacks: StatusBarCallbacks;
 
@@ -96,6 +99,7 @@ export class StatusBar {
     this.addItem({ id: 'sync', content: '', align: 'left', priority: 2 });
     this.addItem({ id: 'file', content: '', align: 'left', priority: 3 });
     this.addItem({ id: 'command', content: '', align: 'left', priority: 4 });
+    this.addItem({ id: 'lspProgress', content: '', align: 'left', priority: 5 });
 
     // Default items - Right side
     this.addItem({ id: 'position', content: '', align: 'right', priority: 1 });
@@ -118,6 +122,21 @@ export class StatusBar {
     }, duration);
   }
 
+  /**
+   * Replace the collapsed bar with a single line of plain text (the last
+   * screen-reader announcement), or restore the items with null.
+   */
+  setSpeechLine(text: string | null): void {
+    this.speechLine = text;
+  }
+
+  /**
+   * Get the speech line, or null if the items are shown.
+   */
+  getSpeechLine(): string | null {
+    return this.speechLine;
+  }
+
   // ─────────────────────────────────────────────────────────────────────────
   // Layout
   // ─────────────────────────────────────────────────────────────────────────
@@ -363,6 +382,12 @@ export class StatusBar {
       buffer.set(x, y, emptyCell);
     }
 
+    // Speech line: plain text without separators or icons
+    if (this.speechLine !== null) {
+      buffer.writeString(startX, y, ` ${this.speechLine}`.slice(0, width), fg, bg);
+      return;
+    }
+
     // Build left side string with separators (compact: single space between items)
     const leftItems = this.getItemsSorted('left');
     let leftStr = '';
@@ -48,7 +48,12 @@ export type ElementType =
   | 'GitTimelinePanel'
   | 'SQLEditor'
   | 'QueryResults'
-  | 'RowDetailsPanel';
+  | 'RowDetailsPanel'
+  | 'LSPTrace'
+  | 'LSPServerNotFound'
+  | 'ImageViewer'
+  | 'TodoPanel'
+  | 'DependencyGraph';
 
 export interface ElementConfig {
   type: ElementType;
@@ -71,6 +76,8 @@ export interface PaneConfig {
   elements: ElementConfig[];
   activeElementId?: string; // For tabs
   expandedElementIds?: string[]; // For accordion
+  previewElementId?: string; // Preview tab
+  pinnedElementIds?: string[]; // Pinned tabs, in tab order
 }
 
 export interface SplitConfig {
@@ -10,7 +10,17 @@ import type { ScreenBuffer } from './rendering/buffer.ts';
 import { createScreenBuffer } from './rendering/buffer.ts';
 import { PaneContainer, createPaneContainer, type PaneContainerCallbacks, type FocusableElementType, type TabDropdownInfo } from './layout/index.ts';
 import { StatusBar, createStatusBar, type StatusBarCallbacks } from './status-bar/index.ts';
-import { OverlayManager, createOverlayManager, type OverlayManagerCallbacks, type NotificationType } from './overlays/index.ts';
+import {
+  OverlayManager,
+  createOverlayManager,
+  type OverlayManagerCallbacks,
+  type NotificationType,
+  type NotificationOptions,
+  WhichKeyPopup,
+  createWhichKeyPopup,
+  getWhichKeyEntries,
+  type WhichKeyBinding,
+} from './overlays/index.ts';
 import { FocusManager, createFocusManager, type FocusChangeCallback } from './input/index.ts';
 import type { BaseElement } from './elements/index.ts';
 import type { Pane } from './layout/pane.ts';
@@ -57,6 +67,20 @@ export interface KeyBinding {
   handler: () => boolean;
   /** Optional context condition */
   when?: () => boolean;
+  /** Title shown in the which-key popup */
+  title?: string;
+}
+
+/**
+ * Which-key popup options.
+ */
+export interface WhichKeyOptions {
+  /** Show continuations of a pending chord */
+  enabled: boolean;
+  /** Delay before the popup appears (ms) */
+  delay: number;
+  /** How long the popup waits for the next key before the chord expires (ms) */
+  timeout?: number;
 }
 
 // ============================================
@@ -100,7 +124,7 @@ export class Window {
   /** Whether window is active */
   private active = false;
 
-  /** Pending chord prefix (first key of a chord sequence) */
+  /** Pending chord prefix (keys of a chord sequence pressed so far) */
   private pendingChord: string | null = null;
 
   /** Chord timeout handle */
@@ -109,9 +133,27 @@ export class Window {
   /** Chord timeout duration (ms) */
   private readonly CHORD_TIMEOUT = 500;
 
+  /** Chord timeout once the which-key popup is showing (ms) */
+  private readonly WHICH_KEY_CHORD_TIMEOUT = 5000;
+
+  /** Key that `leader` in binding keys stands for ('' disables leader bindings) */
+  private leaderKey = '';
+
+  /** Which-key popup options */
+  private whichKeyOptions: WhichKeyOptions = { enabled: false, delay: 300 };
+
+  /** Popup listing chord continuations */
+  private whichKeyPopup: WhichKeyPopup;
+
+  /** Which-key delay handle */
+  private whichKeyTimeout: ReturnType<typeof setTimeout> | null = null;
+
   /** Status bar height (1 collapsed, more when expanded) */
   private statusBarHeight = 1;
 
+  /** Whether the status bar is shown (hidden in zen mode) */
+  private statusBarVisible = true;
+
   /** Bottom panel height (terminal panel, etc.) */
   private bottomPanelHeight = 0;
 
@@ -166,6 +208,11 @@ export class Window {
     };
     this.overlayManager = createOverlayManager(overlayCallbacks);
 
+    // Which-key popup (Escape dismisses it and cancels the chord)
+    this.whichKeyPopup = createWhichKeyPopup('which-key', overlayCallbacks);
+    this.whichKeyPopup.onDismissed(() => this.clearChord());
+    this.overlayManager.addOverlay(this.whichKeyPopup);
+
     // Apply initial layout
     this.updateLayout();
   }
@@ -207,6 +254,9 @@ export class Window {
     this.size = { ...size };
     this.buffer = createScreenBuffer(this.size);
     this.updateLayout();
+    if (this.whichKeyPopup.isVisible()) {
+      this.showWhichKey();
+    }
     this.markDirty();
   }
 
@@ -228,7 +278,7 @@ export class Window {
       x: 0,
       y: 0,
       width: this.size.width,
-      height: this.size.height - this.statusBarHeight,
+      height: this.size.height - this.getVisibleStatusBarHeight(),
     };
     this.paneContainer.setBounds(paneContainerBounds);
 
@@ -238,13 +288,32 @@ export class Window {
     // Position status bar at bottom (below bottom panel if present)
     const statusBarBounds: Rect = {
       x: 0,
-      y: this.size.height - this.statusBarHeight,
+      y: this.size.height - this.getVisibleStatusBarHeight(),
       width: this.size.width,
-      height: this.statusBarHeight,
+      height: this.getVisibleStatusBarHeight(),
     };
     this.statusBar.setBounds(statusBarBounds);
   }
 
+  private getVisibleStatusBarHeight(): number {
+    return this.statusBarVisible ? this.statusBarHeight : 0;
+  }
+
+  /**
+   * Show or hide the status bar. The pane container takes its rows while
+   * it is hidden.
+   */
+  setStatusBarVisible(visible: boolean): void {
+    if (this.statusBarVisible === visible) return;
+    this.statusBarVisible = visible;
+    this.updateLayout();
+    this.markDirty();
+  }
+
+  isStatusBarVisible(): boolean {
+    return this.statusBarVisible;
+  }
+
   /**
    * Set the bottom panel height (for terminal panel, etc.).
    * This shrinks the pane container to make room for the bottom panel.
@@ -281,6 +350,11 @@ export class Window {
    * @returns true if the event was handled
    */
   handleInput(event: InputEvent): boolean {
+    // Toasts are drawn above everything, so clicks on them come first
+    if (isMouseEvent(event) && this.overlayManager.handleNotificationMouse(event)) {
+      return true;
+    }
+
     // 1. Overlays get first shot
     if (this.overlayManager.hasVisibleOverlays()) {
       if (this.overlayManager.handleInput(event)) {
@@ -354,74 +428,124 @@ export class Window {
 
   /**
    * Check global keybindings.
-   * Supports chord sequences (e.g., "ctrl+k ctrl+c").
+   * Supports chord sequences of any length (e.g., "ctrl+k ctrl+c").
    */
   private handleKeybinding(event: KeyEvent): boolean {
     const keyStr = this.normalizeKey(event);
 
     // Check for chord continuation
     if (this.pendingChord) {
-      const chordKey = `${this.pendingChord} ${keyStr}`;
+      const sequence = `${this.pendingChord} ${keyStr}`;
       this.clearChord();
 
+      // Escape cancels the chord
+      if (keyStr === 'escape') {
+        return true;
+      }
+
+      // The sequence may itself start a longer chord
+      if (this.hasChordContinuation(sequence)) {
+        this.startChord(sequence);
+        return true;
+      }
+
       // Look for full chord match
-      for (const binding of this.keybindings) {
-        const normalizedBinding = this.normalizeBindingKey(binding.key);
-        if (normalizedBinding === chordKey) {
-          if (binding.when && !binding.when()) {
-            continue;
-          }
-          if (binding.handler()) {
-            return true;
-          }
-        }
+      if (this.runBinding(sequence)) {
+        return true;
       }
 
       // Chord didn't complete - fall through to check for new chord or direct binding
     }
 
-    // Check if this key could start a chord
-    const chordPrefix = keyStr + ' ';
-    const hasChordContinuation = this.keybindings.some((b) => {
-      const normalized = this.normalizeBindingKey(b.key);
-      return normalized.startsWith(chordPrefix);
-    });
+    // If this key starts a chord, wait for the chord instead of executing
+    if (this.hasChordContinuation(keyStr)) {
+      this.startChord(keyStr);
+      return true; // Consume the key to wait for chord
+    }
 
     // Check for direct binding
-    for (const binding of this.keybindings) {
-      const normalizedBinding = this.normalizeBindingKey(binding.key);
-      if (normalizedBinding === keyStr) {
-        if (binding.when && !binding.when()) {
-          continue;
-        }
-
-        // If this key also starts a chord, wait for chord instead of executing
-        if (hasChordContinuation) {
-          break;
-        }
+    return this.runBinding(keyStr);
+  }
 
-        if (binding.handler()) {
-          return true;
-        }
+  /**
+   * Run the first active binding for a normalized key sequence.
+   */
+  private runBinding(sequence: string): boolean {
+    for (const binding of this.keybindings) {
+      if (this.normalizeBindingKey(binding.key) !== sequence) continue;
+      if (binding.when && !binding.when()) continue;
+      if (binding.handler()) {
+        return true;
       }
     }
+    return false;
+  }
 
-    if (hasChordContinuation) {
-      // Start chord timeout
-      this.pendingChord = keyStr;
+  /**
+   * Check if any active binding continues a key sequence.
+   */
+  private hasChordContinuation(prefix: string): boolean {
+    return this.keybindings.some((binding) => {
+      return this.normalizeBindingKey(binding.key).startsWith(prefix + ' ') && (!binding.when || binding.when());
+    });
+  }
 
-      // Show chord indicator in status bar
-      this.showStatusCommand(`${keyStr} ...`);
+  /**
+   * Normalized keys and titles of the bindings whose context applies.
+   */
+  private getActiveBindings(): WhichKeyBinding[] {
+    const active: WhichKeyBinding[] = [];
+    for (const binding of this.keybindings) {
+      const key = this.normalizeBindingKey(binding.key);
+      if (!key || (binding.when && !binding.when())) continue;
+      active.push({ key, title: binding.title ?? key });
+    }
+    return active;
+  }
 
-      this.chordTimeout = setTimeout(() => {
-        this.clearChord();
-        this.markDirty();
-      }, this.CHORD_TIMEOUT);
+  /**
+   * Wait for the next key of a chord. With which-key enabled the popup
+   * appears after its delay and the chord timeout starts from there, so
+   * there is time to read it.
+   */
+  private startChord(prefix: string): void {
+    this.pendingChord = prefix;
+
+    // Show chord indicator in status bar
+    this.showStatusCommand(`${prefix} ...`);
+
+    if (this.whichKeyOptions.enabled) {
+      this.whichKeyTimeout = setTimeout(() => {
+        this.whichKeyTimeout = null;
+        this.showWhichKey();
+        this.startChordTimeout(this.whichKeyOptions.timeout ?? this.WHICH_KEY_CHORD_TIMEOUT);
+      }, this.whichKeyOptions.delay);
+    } else {
+      this.startChordTimeout(this.CHORD_TIMEOUT);
+    }
+  }
 
-      return true; // Consume the key to wait for chord
+  /**
+   * Expire the pending chord after a timeout.
+   */
+  private startChordTimeout(ms: number): void {
+    if (this.chordTimeout) {
+      clearTimeout(this.chordTimeout);
     }
+    this.chordTimeout = setTimeout(() => {
+      this.chordTimeout = null;
+      this.clearChord();
+      this.markDirty();
+    }, ms);
+  }
 
-    return false;
+  /**
+   * Show the continuations of the pending chord.
+   */
+  private showWhichKey(): void {
+    if (!this.pendingChord) return;
+    const entries = getWhichKeyEntries(this.pendingChord, this.getActiveBindings());
+    this.whichKeyPopup.showEntries(this.pendingChord, entries, this.size.height - this.getVisibleStatusBarHeight());
   }
 
   /**
@@ -432,10 +556,15 @@ export class Window {
       clearTimeout(this.chordTimeout);
       this.chordTimeout = null;
     }
+    if (this.whichKeyTimeout) {
+      clearTimeout(this.whichKeyTimeout);
+      this.whichKeyTimeout = null;
+    }
     if (this.pendingChord) {
       // Clear the chord indicator from status bar
       this.statusBar.setItemContent('command', '');
     }
+    this.whichKeyPopup.hide();
     this.pendingChord = null;
   }
 
@@ -453,6 +582,38 @@ export class Window {
     return this.pendingChord;
   }
 
+  /**
+   * Set the key that `leader` stands for in binding keys
+   * (e.g. "alt+space" makes "leader g" mean "alt+space g").
+   */
+  setLeaderKey(key: string): void {
+    this.leaderKey = key.trim() ? this.normalizeBindingKey(key.trim()) : '';
+  }
+
+  /**
+   * Get the normalized leader key ('' when unset).
+   */
+  getLeaderKey(): string {
+    return this.leaderKey;
+  }
+
+  /**
+   * Configure the which-key popup.
+   */
+  setWhichKeyOptions(options: WhichKeyOptions): void {
+    this.whichKeyOptions = { ...options, delay: Math.max(0, options.delay) };
+    if (!options.enabled) {
+      this.whichKeyPopup.hide();
+    }
+  }
+
+  /**
+   * Get the which-key popup.
+   */
+  getWhichKeyPopup(): WhichKeyPopup {
+    return this.whichKeyPopup;
+  }
+
   /**
    * Normalize a key event to a string like "ctrl+s".
    */
@@ -462,18 +623,27 @@ export class Window {
     if (event.alt) parts.push('alt');
     if (event.shift) parts.push('shift');
     if (event.meta) parts.push('meta');
-    parts.push(event.key.toLowerCase());
+    parts.push(event.key === ' ' ? 'space' : event.key.toLowerCase());
     return parts.join('+');
   }
 
   /**
    * Normalize a binding key string to match normalizeKey output.
-   * Handles space-separated chord sequences like "ctrl+k ctrl+c".
+   * Handles space-separated chord sequences like "ctrl+k ctrl+c", and
+   * `leader`, which stands for the leader key. Leader bindings normalize
+   * to '' (never match) while no leader key is set.
    */
   private normalizeBindingKey(keyString: string): string {
     // Split by space for chord sequences
     const chordParts = keyString.trim().split(/\s+/);
 
+    if (chordParts.some((part) => part.toLowerCase() === 'leader')) {
+      if (!this.leaderKey) return '';
+      return chordParts
+        .map((part) => (part.toLowerCase() === 'leader' ? this.leaderKey : this.normalizeBindingKey(part)))
+        .join(' ');
+    }
+
     // Normalize each part of the chord
     const normalized = chordParts.map((part) => {
       const segments = part.toLowerCase().split('+');
@@ -674,6 +844,14 @@ export class Window {
     return this.overlayManager.showNotification(message, type, duration);
   }
 
+  /**
+   * Show a notification with actions, progress or "do not show again".
+   * Returns null if its "do not show again" key is suppressed.
+   */
+  notify(message: string, type?: NotificationType, options?: NotificationOptions): string | null {
+    return this.overlayManager.notify(message, type, options);
+  }
+
   /**
    * Remove a notification.
    */
@@ -717,7 +895,9 @@ export class Window {
     this.paneContainer.render(this.buffer);
 
     // Render status bar
-    this.statusBar.render(this.buffer);
+    if (this.statusBarVisible) {
+      this.statusBar.render(this.buffer);
+    }
 
     // Render overlays (on top of everything)
     this.overlayManager.render(this.buffer);
@@ -167,6 +167,30 @@ export const defaultKeybindings: KeyBinding[] = [
     "key": "ctrl+[",
     "command": "editor.previousTab"
   },
+  {
+    "key": "ctrl+e",
+    "command": "editor.switchTab"
+  },
+  {
+    "key": "ctrl+k enter",
+    "command": "editor.keepTab"
+  },
+  {
+    "key": "ctrl+k shift+enter",
+    "command": "editor.togglePinTab"
+  },
+  {
+    "key": "ctrl+k w",
+    "command": "editor.closeOtherTabs"
+  },
+  {
+    "key": "ctrl+k u",
+    "command": "editor.closeSavedTabs"
+  },
+  {
+    "key": "ctrl+k shift+t",
+    "command": "editor.reopenClosedTab"
+  },
   {
     "key": "ctrl+shift+b",
     "command": "workbench.toggleSidebar"
@@ -215,6 +239,14 @@ export const defaultKeybindings: KeyBinding[] = [
     "key": "ctrl+shift+t",
     "command": "terminal.newInPane"
   },
+  {
+    "key": "alt+enter",
+    "command": "terminal.sendToRepl"
+  },
+  {
+    "key": "alt+shift+enter",
+    "command": "terminal.sendBlockToRepl"
+  },
   {
     "key": "ctrl+shift+g",
     "command": "git.focusPanel"
@@ -634,6 +666,10 @@ export const defaultKeybindings: KeyBinding[] = [
     "key": "ctrl+k ctrl+o",
     "command": "session.open"
   },
+  {
+    "key": "ctrl+k z",
+    "command": "view.toggleZenMode"
+  },
   {
     "key": "ctrl+shift+[",
     "command": "editor.fold"
@@ -650,6 +686,50 @@ export const defaultKeybindings: KeyBinding[] = [
     "key": "ctrl+k ctrl+j",
     "command": "editor.unfoldAll"
   },
+  {
+    "key": "ctrl+k ctrl+1",
+    "command": "editor.foldLevel1"
+  },
+  {
+    "key": "ctrl+k ctrl+2",
+    "command": "editor.foldLevel2"
+  },
+  {
+    "key": "ctrl+k ctrl+3",
+    "command": "editor.foldLevel3"
+  },
+  {
+    "key": "ctrl+k ctrl+4",
+    "command": "editor.foldLevel4"
+  },
+  {
+    "key": "ctrl+k ctrl+5",
+    "command": "editor.foldLevel5"
+  },
+  {
+    "key": "ctrl+k ctrl+6",
+    "command": "editor.foldLevel6"
+  },
+  {
+    "key": "ctrl+k ctrl+7",
+    "command": "editor.foldLevel7"
+  },
+  {
+    "key": "ctrl+k ctrl+/",
+    "command": "editor.foldAllComments"
+  },
+  {
+    "key": "ctrl+k ctrl+-",
+    "command": "editor.foldAllExceptSelected"
+  },
+  {
+    "key": "ctrl+k ctrl+,",
+    "command": "editor.createFoldFromSelection"
+  },
+  {
+    "key": "ctrl+k ctrl+.",
+    "command": "editor.removeManualFolds"
+  },
   {
     "key": "ctrl+k ctrl+c",
     "command": "editor.commentLine"
@@ -662,6 +742,18 @@ export const defaultKeybindings: KeyBinding[] = [
     "key": "ctrl+/",
     "command": "editor.toggleComment"
   },
+  {
+    "key": "ctrl+k ctrl+r",
+    "command": "workbench.toggleScreenReaderMode"
+  },
+  {
+    "key": "ctrl+k ctrl+a",
+    "command": "workbench.repeatLastAnnouncement"
+  },
+  {
+    "key": "ctrl+k n",
+    "command": "workbench.showNotifications"
+  },
   {
     "key": "ctrl+shift+k",
     "command": "lsp.goToDefinition"
@@ -686,9 +778,15 @@ export const defaultSettings: Record<string, any> = {
   "editor.insertSpaces": true,
   "editor.autoIndent": "full",
   "editor.autoClosingBrackets": "always",
+  "editor.autoClosingTags": true,
+  "editor.autoSurround": "languageDefined",
+  "editor.emmet.triggerExpansionOnTab": true,
   "editor.wordWrap": "on",
   "editor.lineNumbers": "on",
   "editor.folding": true,
+  "editor.foldingStrategy": "auto",
+  "editor.links": true,
+  "editor.colorDecorators": true,
   "editor.renderWhitespace": "selection",
   "editor.mouseWheelScrollSensitivity": 3,
   "editor.cursorBlinkRate": 500,
@@ -709,14 +807,23 @@ export const defaultSettings: Record<string, any> = {
   },
   "workbench.colorTheme": "catppuccin-frappe",
   "workbench.startupEditor": "",
+  "workbench.locale": "auto",
   "tui.sidebar.width": 36,
   "tui.sidebar.visible": true,
   "tui.sidebar.location": "left",
   "tui.sidebar.focusedBackground": "#2d3139",
   "tui.terminal.height": 24,
   "tui.terminal.scrollback": 1000,
+  "tui.terminal.watchRules": [],
+  "tui.terminal.promptPattern": "",
+  "tui.terminal.finishedActions": [
+    "notify",
+    "bell"
+  ],
+  "tui.repl.commands": {},
   "tui.fileTree.showGitStatus": true,
   "tui.tabBar.scrollAmount": 1,
+  "tui.tabBar.enablePreview": true,
   "tui.filePicker.maxFiles": 10000,
   "tui.diffViewer.autoRefresh": true,
   "tui.diffViewer.showDiagnostics": true,
@@ -726,6 +833,25 @@ export const defaultSettings: Record<string, any> = {
   "tui.timeline.collapsedOnStartup": true,
   "tui.timeline.mode": "file",
   "tui.timeline.commitCount": 50,
+  "tui.todo.collapsedOnStartup": true,
+  "tui.todo.tags": [
+    "TODO",
+    "FIXME",
+    "HACK",
+    "XXX"
+  ],
+  "tui.zenMode.width": 100,
+  "tui.zenMode.dimOutsideParagraph": false,
+  "tui.dependencyGraph.includeExternal": false,
+  "tui.dependencyGraph.layers": [],
+  "tui.accessibility.screenReaderMode": false,
+  "tui.accessibility.announcementChannel": "statusLine",
+  "tui.accessibility.bridgeSocket": "",
+  "tui.notifications.doNotShowAgain": [],
+  "tui.keybindings.leader": "",
+  "tui.whichKey.enabled": true,
+  "tui.whichKey.delay": 300,
+  "tui.imagePreview.protocol": "auto",
   "git.statusInterval": 500,
   "git.panel.location": "sidebar-bottom",
   "git.panel.openOnStartup": true,
@@ -733,6 +859,7 @@ export const defaultSettings: Record<string, any> = {
   "git.inlineDiff.maxHeight": 15,
   "git.inlineDiff.contextLines": 3,
   "ai.defaultProvider": "claude-code",
+  "ai.customProviders": [],
   "ai.panel.defaultWidth": 80,
   "ai.panel.maxWidthPercent": 50,
   "ai.panel.openOnStartup": false,
@@ -749,13 +876,15 @@ export const defaultSettings: Record<string, any> = {
   "lsp.enabled": true,
   "lsp.completionDebounceMs": 250,
   "lsp.triggerCharacters": ".:/<@(",
+  "lsp.completion.showDocumentation": true,
   "lsp.hover.enabled": true,
   "lsp.signatureHelp.enabled": true,
   "lsp.signatureHelp.display": "popup",
   "lsp.diagnostics.enabled": true,
   "lsp.diagnostics.showInGutter": true,
   "lsp.diagnostics.underlineErrors": true,
-  "lsp.diagnostics.delay": 500
+  "lsp.diagnostics.delay": 500,
+  "lsp.trace": "messages"
 };
 
 export const defaultThemes: Record<string, Theme> = {
@@ -10,9 +10,15 @@ export interface EditorSettings {
   'editor.insertSpaces': boolean;
   'editor.autoIndent': 'none' | 'keep' | 'full';
   'editor.autoClosingBrackets': 'always' | 'languageDefined' | 'beforeWhitespace' | 'never';
+  'editor.autoClosingTags': boolean;
+  'editor.autoSurround': 'languageDefined' | 'quotes' | 'brackets' | 'never';
+  'editor.emmet.triggerExpansionOnTab': boolean;
   'editor.wordWrap': 'off' | 'on' | 'wordWrapColumn' | 'bounded';
   'editor.lineNumbers': 'on' | 'off' | 'relative';
   'editor.folding': boolean;
+  'editor.foldingStrategy': 'auto' | 'indentation';
+  'editor.links': boolean;
+  'editor.colorDecorators': boolean;
   'editor.minimap.enabled': boolean;
   'editor.minimap.width': number;
   'editor.minimap.showSlider': 'always' | 'mouseover';
@@ -29,12 +35,14 @@ export interface EditorSettings {
   'files.exclude': Record<string, boolean>;
   'workbench.colorTheme': string;
   'workbench.startupEditor': string;
+  'workbench.locale': 'auto' | 'en' | 'de' | 'ja';
   'tui.sidebar.width': number;
   'tui.sidebar.visible': boolean;
   'tui.sidebar.location': 'left' | 'right';
   'tui.sidebar.focusedBackground': string;
   'tui.terminal.height': number;
   'tui.tabBar.scrollAmount': number;
+  'tui.tabBar.enablePreview': boolean;
   // TUI Outline panel settings
   'tui.outline.autoFollow': boolean;
   'tui.outline.collapsedOnStartup': boolean;
@@ -78,6 +86,8 @@ export interface EditorSettings {
   'lsp.completionDebounceMs': number;
   /** Characters that trigger completion immediately */
   'lsp.triggerCharacters': string;
+  /** Show documentation beside the completion list */
+  'lsp.completion.showDocumentation': boolean;
   /** Enable signature help */
   'lsp.signatureHelp.enabled': boolean;
   /** Where to display signature help */
@@ -92,6 +102,8 @@ export interface EditorSettings {
   'lsp.diagnostics.delay': number;
   /** Enable hover information */
   'lsp.hover.enabled': boolean;
+  /** Language server trace level for the trace panel */
+  'lsp.trace': 'off' | 'messages' | 'verbose';
 }
 
 const defaultSettings: EditorSettings = {
@@ -100,9 +112,15 @@ const defaultSettings: EditorSettings = {
   'editor.insertSpaces': true,
   'editor.autoIndent': 'full',
   'editor.autoClosingBrackets': 'always',
+  'editor.autoClosingTags': true,
+  'editor.autoSurround': 'languageDefined',
+  'editor.emmet.triggerExpansionOnTab': true,
   'editor.wordWrap': 'off',
   'editor.lineNumbers': 'on',
   'editor.folding': true,
+  'editor.foldingStrategy': 'auto',
+  'editor.links': true,
+  'editor.colorDecorators': true,
   'editor.minimap.enabled': true,
   'editor.minimap.width': 10,
   'editor.minimap.showSlider': 'always',
@@ -123,12 +141,14 @@ const defaultSettings: EditorSettings = {
   },
   'workbench.colorTheme': 'catppuccin-frappe',
   'workbench.startupEditor': '~/.ultra/BOOT.md',
+  'workbench.locale': 'auto',
   'tui.sidebar.width': 36,
   'tui.sidebar.visible': true,
   'tui.sidebar.location': 'left',
   'tui.sidebar.focusedBackground': '#2d3139',
   'tui.terminal.height': 10,
   'tui.tabBar.scrollAmount': 1,
+  'tui.tabBar.enablePreview': true,
   // TUI Outline panel settings
   'tui.outline.autoFollow': true,
   'tui.outline.collapsedOnStartup': true,
@@ -165,13 +185,15 @@ const defaultSettings: EditorSettings = {
   'lsp.enabled': true,
   'lsp.completionDebounceMs': 250,
   'lsp.triggerCharacters': '.:/<@(',
+  'lsp.completion.showDocumentation': true,
   'lsp.signatureHelp.enabled': true,
   'lsp.signatureHelp.display': 'popup',
   'lsp.diagnostics.enabled': true,
   'lsp.diagnostics.showInGutter': true,
   'lsp.diagnostics.underlineErrors': true,
   'lsp.diagnostics.delay': 500,
-  'lsp.hover.enabled': true
+  'lsp.hover.enabled': true,
+  'lsp.trace': 'messages'
 };
 
 export class Settings {
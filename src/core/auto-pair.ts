@@ -2,7 +2,9 @@
  * Auto-Pairing Logic
  * 
  * Automatically inserts closing brackets, quotes, and other pairs
- * when the user types an opening character.
+ * when the user types an opening character. Also closes HTML/JSX tags
+ * and finds, adds, changes or deletes the pair surrounding a position
+ * (vim-surround style).
  */
 
 export interface PairConfig {
@@ -94,3 +96,272 @@ export function shouldDeletePair(
   const pair = PAIR_MAP.get(charBeforeCursor);
   return pair !== undefined && pair.close === charAfterCursor;
 }
+
+// ============================================
+// Tag Auto-Closing
+// ============================================
+
+/** HTML elements that never have a closing tag */
+const VOID_ELEMENTS = new Set([
+  'area', 'base', 'br', 'col', 'embed', 'hr', 'img', 'input',
+  'link', 'meta', 'param', 'source', 'track', 'wbr',
+]);
+
+/**
+ * An opening tag (with attributes) at the end of a line. The tag must not
+ * follow an identifier or closing bracket, so `Array<string>` and `a < b > c`
+ * don't count. Attribute values may contain `>` inside quotes or braces.
+ */
+const OPEN_TAG_AT_END = /(?:^|[^\w$\])])<([A-Za-z][\w:.-]*)((?:\s(?:[^<>"'{}]|"[^"]*"|'[^']*'|\{[^{}]*\})*)?)>$/;
+
+/** A JSX fragment opener at the end of a line */
+const FRAGMENT_AT_END = /(?:^|[^\w$\])])<>$/;
+
+/**
+ * Get the closing tag to insert after the user types `>`.
+ * `textBefore` is the line up to and including the `>`, `textAfter` the rest.
+ * Returns null for self-closing and void elements, or when already closed.
+ */
+export function getAutoCloseTag(
+  textBefore: string,
+  textAfter: string,
+  options: { jsx?: boolean } = {}
+): string | null {
+  if (options.jsx && FRAGMENT_AT_END.test(textBefore)) {
+    return textAfter.startsWith('</>') ? null : '</>';
+  }
+
+  const match = OPEN_TAG_AT_END.exec(textBefore);
+  if (!match) return null;
+
+  const name = match[1]!;
+  const attributes = match[2] ?? '';
+  if (attributes.trimEnd().endsWith('/')) return null;
+  if (!options.jsx && VOID_ELEMENTS.has(name.toLowerCase())) return null;
+
+  const close = `</${name}>`;
+  return textAfter.startsWith(close) ? null : close;
+}
+
+// ============================================
+// Surround
+// ============================================
+
+/**
+ * An opening/closing pair used to surround text.
+ */
+export interface SurroundPair {
+  open: string;
+  close: string;
+}
+
+/**
+ * The open and close delimiters of a surrounding pair, as text offsets.
+ */
+export interface SurroundingPairRange {
+  openStart: number;
+  openEnd: number;
+  closeStart: number;
+  closeEnd: number;
+}
+
+/**
+ * A replacement of the text between two offsets.
+ */
+export interface TextChange {
+  start: number;
+  end: number;
+  text: string;
+}
+
+const BRACKETS: Record<string, SurroundPair> = {
+  '(': { open: '(', close: ')' },
+  '[': { open: '[', close: ']' },
+  '{': { open: '{', close: '}' },
+  '<': { open: '<', close: '>' },
+};
+
+/** Closing bracket -> opening bracket */
+const BRACKET_OPENERS: Record<string, string> = { ')': '(', ']': '[', '}': '{', '>': '<' };
+
+/** vim-surround aliases */
+const SURROUND_ALIASES: Record<string, string> = { b: '(', B: '{', r: '[', a: '<' };
+
+const QUOTES = new Set(['"', "'", '`']);
+
+const TAG_NAME = /^[A-Za-z][\w:.-]*$/;
+
+/**
+ * Get the pair to surround text with. `target` is a bracket or quote
+ * (either side, or a vim alias: `b` `B` `r` `a`), any other punctuation
+ * character (used on both sides), or a tag: `div` or `<div class="x">`.
+ */
+export function getSurroundPair(target: string): SurroundPair | null {
+  const char = SURROUND_ALIASES[target] ?? BRACKET_OPENERS[target] ?? target;
+
+  if (BRACKETS[char]) return BRACKETS[char]!;
+  if (char.length === 1 && !/[\w\s]/.test(char)) return { open: char, close: char };
+
+  const tag = parseTagTarget(target);
+  if (!tag) return null;
+  const open = target.startsWith('<')
+    ? (target.endsWith('>') ? target : `${target}>`)
+    : `<${target}>`;
+  return { open, close: `</${tag}>` };
+}
+
+/**
+ * Find the innermost pair of `target` around `offset`. `target` is a
+ * bracket, quote or alias as for getSurroundPair, `t` for any tag, or a
+ * tag name. Quotes only pair within a line.
+ */
+export function findSurroundingPair(text: string, offset: number, target: string): SurroundingPairRange | null {
+  if (target === 't') return findSurroundingTag(text, offset, null);
+
+  const char = SURROUND_ALIASES[target] ?? BRACKET_OPENERS[target] ?? target;
+  if (BRACKETS[char]) return findSurroundingBracket(text, offset, BRACKETS[char]!);
+  if (QUOTES.has(char)) return findSurroundingQuote(text, offset, char);
+
+  const tag = parseTagTarget(target);
+  return tag ? findSurroundingTag(text, offset, tag) : null;
+}
+
+/**
+ * Changes that delete the pair of `target` around `offset`.
+ */
+export function deleteSurroundingPair(text: string, offset: number, target: string): TextChange[] | null {
+  const range = findSurroundingPair(text, offset, target);
+  if (!range) return null;
+
+  return [
+    { start: range.openStart, end: range.openEnd, text: '' },
+    { start: range.closeStart, end: range.closeEnd, text: '' },
+  ];
+}
+
+/**
+ * Changes that replace the pair of `target` around `offset` with
+ * `replacement`. Renaming a tag to a bare tag name keeps its attributes.
+ */
+export function changeSurroundingPair(
+  text: string,
+  offset: number,
+  target: string,
+  replacement: string
+): TextChange[] | null {
+  const range = findSurroundingPair(text, offset, target);
+  const pair = getSurroundPair(replacement);
+  if (!range || !pair) return null;
+
+  let open = pair.open;
+  const openText = text.slice(range.openStart, range.openEnd);
+  const oldTag = /^<([A-Za-z][\w:.-]*)/.exec(openText);
+  if (oldTag && TAG_NAME.test(replacement)) {
+    open = `<${replacement}${openText.slice(oldTag[0].length)}`;
+  }
+
+  return [
+    { start: range.openStart, end: range.openEnd, text: open },
+    { start: range.closeStart, end: range.closeEnd, text: pair.close },
+  ];
+}
+
+function parseTagTarget(target: string): string | null {
+  if (target.startsWith('<')) {
+    return /^<\s*([A-Za-z][\w:.-]*)/.exec(target)?.[1] ?? null;
+  }
+  return TAG_NAME.test(target) && target !== 't' && !SURROUND_ALIASES[target] ? target : null;
+}
+
+function findSurroundingBracket(text: string, offset: number, pair: SurroundPair): SurroundingPairRange | null {
+  // A cursor on the opening bracket counts as inside it
+  let open = text[offset] === pair.open ? offset : -1;
+  if (open === -1) {
+    let depth = 0;
+    for (let i = offset - 1; i >= 0; i--) {
+      const ch = text[i];
+      if (ch === pair.close) {
+        depth++;
+      } else if (ch === pair.open) {
+        if (depth === 0) {
+          open = i;
+          break;
+        }
+        depth--;
+      }
+    }
+  }
+  if (open === -1) return null;
+
+  let depth = 0;
+  for (let i = open + 1; i < text.length; i++) {
+    const ch = text[i];
+    if (ch === pair.open) {
+      depth++;
+    } else if (ch === pair.close) {
+      if (depth === 0) {
+        return { openStart: open, openEnd: open + 1, closeStart: i, closeEnd: i + 1 };
+      }
+      depth--;
+    }
+  }
+  return null;
+}
+
+function findSurroundingQuote(text: string, offset: number, quote: string): SurroundingPairRange | null {
+  const lineStart = text.lastIndexOf('\n', offset - 1) + 1;
+  const newline = text.indexOf('\n', offset);
+  const lineEnd = newline === -1 ? text.length : newline;
+
+  const quotes: number[] = [];
+  for (let i = lineStart; i < lineEnd; i++) {
+    if (text[i] === '\\') {
+      i++;
+    } else if (text[i] === quote) {
+      quotes.push(i);
+    }
+  }
+
+  for (let i = 0; i + 1 < quotes.length; i += 2) {
+    const open = quotes[i]!;
+    const close = quotes[i + 1]!;
+    if (open <= offset && offset <= close) {
+      return { openStart: open, openEnd: open + 1, closeStart: close, closeEnd: close + 1 };
+    }
+  }
+  return null;
+}
+
+/** Opening, closing and self-closing tags */
+const TAG_PATTERN = /<(\/?)([A-Za-z][\w:.-]*)(?:[^<>"'{}]|"[^"]*"|'[^']*'|\{[^{}]*\})*?(\/?)>/g;
+
+function findSurroundingTag(text: string, offset: number, name: string | null): SurroundingPairRange | null {
+  const open: Array<{ name: string; start: number; end: number }> = [];
+  let best: SurroundingPairRange | null = null;
+
+  for (const match of text.matchAll(TAG_PATTERN)) {
+    const start = match.index!;
+    const end = start + match[0].length;
+    const tagName = match[2]!;
+
+    if (match[3] || (!match[1] && VOID_ELEMENTS.has(tagName.toLowerCase()))) continue;
+    if (!match[1]) {
+      open.push({ name: tagName, start, end });
+      continue;
+    }
+
+    // Closing tag: pop to its opener, dropping unclosed tags in between
+    const index = open.map(t => t.name).lastIndexOf(tagName);
+    if (index === -1) continue;
+    const opener = open[index]!;
+    open.length = index;
+
+    const contains = opener.start <= offset && offset < end;
+    const matchesName = name === null || name === tagName;
+    if (contains && matchesName && (!best || opener.start > best.openStart)) {
+      best = { openStart: opener.start, openEnd: opener.end, closeStart: start, closeEnd: end };
+    }
+  }
+
+  return best;
+}
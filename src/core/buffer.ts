@@ -1,12 +1,28 @@
 /**
- * Piece Table Buffer Implementation
+ * Piece Tree Buffer Implementation
  *
  * A piece table is an efficient data structure for text editors that stores:
  * - Original content (immutable)
  * - Added content (append-only)
- * - A list of "pieces" that reference spans in either buffer
+ * - A sequence of "pieces" that reference spans in either buffer
  *
- * This allows O(1) insert/delete operations on average, with efficient undo/redo.
+ * The pieces are kept in a balanced binary tree (a treap) rather than a flat
+ * list. Every node caches the total length and line-break count of its
+ * subtree, so offset↔position lookups, line access and edits are O(log n) in
+ * the number of pieces instead of degrading as edits accumulate.
+ *
+ * Line breaks inside a piece are counted with per-source line-start tables
+ * (binary search), so splitting a piece never rescans its text.
+ *
+ * Tree nodes are immutable: edits copy the O(log n) nodes on the path they
+ * touch and share the rest. That makes `snapshot()` O(1) — a snapshot holds
+ * the root of the tree at that moment and stays valid while the buffer keeps
+ * changing, which lets background consumers (LSP sync, syntax, search) read
+ * a consistent version without copying the whole content.
+ *
+ * Every edit is also recorded in a bounded change log, so positions
+ * computed against an older version (e.g. on a snapshot) can be remapped to
+ * the current one with `getChangesSince()` and `transformPosition()`.
  *
  * ## Version Tracking (Performance Optimization)
  *
@@ -35,210 +51,380 @@ export interface Range {
   end: Position;
 }
 
+/**
+ * One recorded edit: [start, end) of the previous version was replaced by
+ * text ending at `newEnd` in the new version.
+ */
+export interface BufferChange {
+  /** Buffer version after the change */
+  version: number;
+  start: Position;
+  end: Position;
+  newEnd: Position;
+}
+
+/**
+ * Which side of an insertion a position at the insertion point sticks to.
+ */
+export type PositionBias = 'left' | 'right';
+
+/** Changes kept for remapping positions from older versions */
+const MAX_CHANGE_HISTORY = 1000;
+
 interface Piece {
   source: 'original' | 'add';
   start: number;   // Start offset in the source buffer
   length: number;  // Length of this piece
 }
 
-interface LineInfo {
-  pieceIndex: number;
-  offsetInPiece: number;
-  lineStartOffset: number;  // Absolute offset from start of document
+/**
+ * Immutable tree node. `length` and `lineBreaks` cover the whole subtree.
+ */
+interface PieceNode {
+  readonly piece: Piece;
+  /** Line breaks within this node's piece */
+  readonly pieceLineBreaks: number;
+  readonly priority: number;
+  readonly left: PieceNode | null;
+  readonly right: PieceNode | null;
+  readonly length: number;
+  readonly lineBreaks: number;
 }
 
-export class Buffer {
-  private originalBuffer: string;
-  private addBuffer: string;
-  private pieces: Piece[];
-  private lineCache: LineInfo[] | null = null;
-  private _length: number = 0;
+/**
+ * Text sources a tree reads from. `addLineStarts` is append-only and may be
+ * shared with newer versions; entries beyond a version's add buffer are
+ * never reached because lookups are bounded by piece ranges.
+ */
+interface PieceSources {
+  readonly original: string;
+  readonly originalLineStarts: number[];
+  readonly add: string;
+  readonly addLineStarts: number[];
+}
 
-  /**
-   * Version counter for O(1) change detection.
-   * Increments on every buffer modification (insert, delete, restore).
-   * Use this instead of comparing content strings to detect changes.
-   */
-  private _version: number = 0;
+// ============================================
+// Read-only view
+// ============================================
 
-  constructor(initialContent: string = '') {
-    this.originalBuffer = initialContent;
-    this.addBuffer = '';
-    
-    if (initialContent.length > 0) {
-      this.pieces = [{
-        source: 'original',
-        start: 0,
-        length: initialContent.length
-      }];
-      this._length = initialContent.length;
-    } else {
-      this.pieces = [];
-      this._length = 0;
-    }
-    
-    this.invalidateLineCache();
-  }
+/**
+ * Read operations shared by the live buffer and its snapshots.
+ */
+abstract class PieceTreeReader {
+  protected root: PieceNode | null = null;
+  protected sources: PieceSources = {
+    original: '',
+    originalLineStarts: [],
+    add: '',
+    addLineStarts: [],
+  };
 
   /**
    * Get total length of the buffer content
    */
   get length(): number {
-    return this._length;
+    return this.root?.length ?? 0;
   }
 
   /**
    * Get the number of lines in the buffer
    */
   get lineCount(): number {
-    this.ensureLineCache();
-    return this.lineCache!.length;
-  }
-
-  /**
-   * Get the buffer version number.
-   * This increments on every modification and can be used for O(1) change detection.
-   * Compare versions instead of content strings to check if buffer has changed.
-   */
-  get version(): number {
-    return this._version;
+    return (this.root?.lineBreaks ?? 0) + 1;
   }
 
   /**
    * Get the full content of the buffer
    */
   getContent(): string {
-    let result = '';
-    for (const piece of this.pieces) {
-      const source = piece.source === 'original' ? this.originalBuffer : this.addBuffer;
-      result += source.slice(piece.start, piece.start + piece.length);
-    }
-    return result;
+    const parts: string[] = [];
+    this.collect(this.root, 0, this.length, 0, parts);
+    return parts.join('');
   }
 
   /**
    * Get content of a specific line (without line ending)
    */
   getLine(lineNumber: number): string {
-    this.ensureLineCache();
-    
-    if (lineNumber < 0 || lineNumber >= this.lineCache!.length) {
+    if (lineNumber < 0 || lineNumber >= this.lineCount) {
       return '';
     }
-    
+
     const lineStart = this.getLineStartOffset(lineNumber);
-    const lineEnd = lineNumber + 1 < this.lineCache!.length 
-      ? this.getLineStartOffset(lineNumber + 1) - 1  // -1 to exclude newline
-      : this._length;
-    
-    return this.getRange(lineStart, lineEnd);
+    return this.getRange(lineStart, lineStart + this.getLineLength(lineNumber));
   }
 
   /**
    * Get content in a range specified by absolute offsets
    */
   getRange(start: number, end: number): string {
-    if (start >= end || start < 0 || end > this._length) {
-      if (start >= this._length) return '';
-      end = Math.min(end, this._length);
+    start = Math.max(0, start);
+    end = Math.min(end, this.length);
+    if (start >= end) return '';
+
+    const parts: string[] = [];
+    this.collect(this.root, start, end, 0, parts);
+    return parts.join('');
+  }
+
+  /**
+   * Get content in a range specified by Position objects
+   */
+  getRangeByPosition(start: Position, end: Position): string {
+    const startOffset = this.positionToOffset(start);
+    const endOffset = this.positionToOffset(end);
+    return this.getRange(startOffset, endOffset);
+  }
+
+  /**
+   * Convert a Position to an absolute offset
+   */
+  positionToOffset(position: Position): number {
+    const { line, column } = position;
+
+    if (line < 0) return 0;
+    if (line >= this.lineCount) {
+      return this.length;
     }
 
-    let result = '';
-    let currentOffset = 0;
+    const lineStartOffset = this.getLineStartOffset(line);
+    const lineLength = this.getLineLength(line);
+
+    return lineStartOffset + Math.min(column, lineLength);
+  }
 
-    for (const piece of this.pieces) {
-      const pieceEnd = currentOffset + piece.length;
-      
-      if (pieceEnd <= start) {
-        currentOffset = pieceEnd;
+  /**
+   * Convert an absolute offset to a Position
+   */
+  offsetToPosition(offset: number): Position {
+    if (offset <= 0) return { line: 0, column: 0 };
+    if (offset >= this.length) {
+      const lastLine = this.lineCount - 1;
+      return { line: lastLine, column: this.getLineLength(lastLine) };
+    }
+
+    const line = this.lineBreaksBefore(offset);
+    return { line, column: offset - this.getLineStartOffset(line) };
+  }
+
+  /**
+   * Get the length of a specific line (excluding line ending)
+   */
+  getLineLength(lineNumber: number): number {
+    if (lineNumber < 0 || lineNumber >= this.lineCount) {
+      return 0;
+    }
+
+    const lineStart = this.getLineStartOffset(lineNumber);
+    const lineEnd = lineNumber + 1 < this.lineCount
+      ? this.getLineStartOffset(lineNumber + 1) - 1  // -1 for newline
+      : this.length;
+
+    return Math.max(0, lineEnd - lineStart);
+  }
+
+  /**
+   * Get the start offset of a line in O(log n).
+   */
+  protected getLineStartOffset(lineNumber: number): number {
+    if (lineNumber <= 0) return 0;
+    if (lineNumber >= this.lineCount) return this.length;
+
+    // Find the offset just after the `lineNumber`-th line break
+    let remaining = lineNumber;
+    let offset = 0;
+    let node = this.root;
+
+    while (node) {
+      const leftBreaks = node.left?.lineBreaks ?? 0;
+      if (remaining <= leftBreaks) {
+        node = node.left;
         continue;
       }
-      
-      if (currentOffset >= end) {
-        break;
+
+      remaining -= leftBreaks;
+      offset += node.left?.length ?? 0;
+
+      if (remaining <= node.pieceLineBreaks) {
+        const { piece } = node;
+        const lineStarts = lineStartsFor(this.sources, piece);
+        const first = upperBound(lineStarts, piece.start);
+        return offset + lineStarts[first + remaining - 1]! - piece.start;
       }
 
-      const source = piece.source === 'original' ? this.originalBuffer : this.addBuffer;
-      const sliceStart = Math.max(0, start - currentOffset);
-      const sliceEnd = Math.min(piece.length, end - currentOffset);
-      
-      result += source.slice(piece.start + sliceStart, piece.start + sliceEnd);
-      currentOffset = pieceEnd;
+      remaining -= node.pieceLineBreaks;
+      offset += node.piece.length;
+      node = node.right;
     }
 
-    return result;
+    return this.length;
   }
 
   /**
-   * Get content in a range specified by Position objects
+   * Count line breaks before an offset in O(log n).
    */
-  getRangeByPosition(start: Position, end: Position): string {
-    const startOffset = this.positionToOffset(start);
-    const endOffset = this.positionToOffset(end);
-    return this.getRange(startOffset, endOffset);
+  protected lineBreaksBefore(offset: number): number {
+    let count = 0;
+    let node = this.root;
+
+    while (node) {
+      const leftLength = node.left?.length ?? 0;
+      if (offset < leftLength) {
+        node = node.left;
+        continue;
+      }
+
+      count += node.left?.lineBreaks ?? 0;
+      offset -= leftLength;
+
+      if (offset <= node.piece.length) {
+        const { piece } = node;
+        return count + lineBreaksIn(this.sources, { ...piece, length: offset });
+      }
+
+      count += node.pieceLineBreaks;
+      offset -= node.piece.length;
+      node = node.right;
+    }
+
+    return count;
   }
 
   /**
-   * Insert text at a specific offset
+   * Append the text of [start, end) within a subtree at `base` to `parts`.
    */
-  insert(offset: number, text: string): void {
-    if (text.length === 0) return;
-    if (offset < 0) offset = 0;
-    if (offset > this._length) offset = this._length;
+  private collect(node: PieceNode | null, start: number, end: number, base: number, parts: string[]): void {
+    if (!node || start >= base + node.length || end <= base) return;
+
+    const leftLength = node.left?.length ?? 0;
+    this.collect(node.left, start, end, base, parts);
+
+    const pieceStart = base + leftLength;
+    const pieceEnd = pieceStart + node.piece.length;
+    if (pieceEnd > start && pieceStart < end) {
+      const { piece } = node;
+      const text = piece.source === 'original' ? this.sources.original : this.sources.add;
+      const sliceStart = Math.max(0, start - pieceStart);
+      const sliceEnd = Math.min(piece.length, end - pieceStart);
+      parts.push(text.slice(piece.start + sliceStart, piece.start + sliceEnd));
+    }
 
-    // Add text to add buffer
-    const addStart = this.addBuffer.length;
-    this.addBuffer += text;
+    this.collect(node.right, start, end, pieceEnd, parts);
+  }
 
-    const newPiece: Piece = {
-      source: 'add',
-      start: addStart,
-      length: text.length
+  /**
+   * Pieces in document order.
+   */
+  protected getPieces(): Piece[] {
+    const pieces: Piece[] = [];
+    const visit = (node: PieceNode | null): void => {
+      if (!node) return;
+      visit(node.left);
+      pieces.push({ ...node.piece });
+      visit(node.right);
     };
+    visit(this.root);
+    return pieces;
+  }
+}
 
-    // Find the piece containing the offset
-    let currentOffset = 0;
-    let pieceIndex = 0;
+// ============================================
+// Snapshot
+// ============================================
 
-    for (; pieceIndex < this.pieces.length; pieceIndex++) {
-      const piece = this.pieces[pieceIndex]!;
-      if (currentOffset + piece.length >= offset) {
-        break;
-      }
-      currentOffset += piece.length;
+/**
+ * Immutable view of a buffer at one version. Cheap to create and safe to
+ * read while the buffer is being edited.
+ */
+export class BufferSnapshot extends PieceTreeReader {
+  readonly version: number;
+
+  /** @internal Created by Buffer.snapshot() */
+  constructor(root: PieceNode | null, sources: PieceSources, version: number) {
+    super();
+    this.root = root;
+    this.sources = sources;
+    this.version = version;
+  }
+}
+
+// ============================================
+// Buffer
+// ============================================
+
+export class Buffer extends PieceTreeReader {
+  /**
+   * Version counter for O(1) change detection.
+   * Increments on every buffer modification (insert, delete, restore).
+   * Use this instead of comparing content strings to detect changes.
+   */
+  private _version: number = 0;
+
+  /** Recent changes, oldest first */
+  private changes: BufferChange[] = [];
+
+  /** Oldest version that changes can be replayed from */
+  private historyStart = 0;
+
+  constructor(initialContent: string = '') {
+    super();
+    this.sources = {
+      original: initialContent,
+      originalLineStarts: computeLineStarts(initialContent),
+      add: '',
+      addLineStarts: [],
+    };
+
+    if (initialContent.length > 0) {
+      this.root = this.createNode({
+        source: 'original',
+        start: 0,
+        length: initialContent.length
+      });
     }
 
-    if (pieceIndex >= this.pieces.length) {
-      // Insert at end
-      this.pieces.push(newPiece);
-    } else {
-      const piece = this.pieces[pieceIndex]!;
-      const offsetInPiece = offset - currentOffset;
-
-      if (offsetInPiece === 0) {
-        // Insert before this piece
-        this.pieces.splice(pieceIndex, 0, newPiece);
-      } else if (offsetInPiece === piece.length) {
-        // Insert after this piece
-        this.pieces.splice(pieceIndex + 1, 0, newPiece);
-      } else {
-        // Split the piece
-        const beforePiece: Piece = {
-          source: piece.source,
-          start: piece.start,
-          length: offsetInPiece
-        };
-        const afterPiece: Piece = {
-          source: piece.source,
-          start: piece.start + offsetInPiece,
-          length: piece.length - offsetInPiece
-        };
-        this.pieces.splice(pieceIndex, 1, beforePiece, newPiece, afterPiece);
-      }
+    this.markModified();
+    this.historyStart = this._version;
+  }
+
+  /**
+   * Get the buffer version number.
+   * This increments on every modification and can be used for O(1) change detection.
+   * Compare versions instead of content strings to check if buffer has changed.
+   */
+  get version(): number {
+    return this._version;
+  }
+
+  /**
+   * Insert text at a specific offset
+   */
+  insert(offset: number, text: string): void {
+    if (text.length === 0) return;
+    if (offset < 0) offset = 0;
+    if (offset > this.length) offset = this.length;
+
+    const start = this.offsetToPosition(offset);
+
+    // Add text to add buffer
+    const addStart = this.sources.add.length;
+    const lineStarts = this.sources.addLineStarts;
+    for (let i = text.indexOf('\n'); i !== -1; i = text.indexOf('\n', i + 1)) {
+      lineStarts.push(addStart + i + 1);
     }
+    this.sources = { ...this.sources, add: this.sources.add + text };
+
+    const [before, after] = split(this.root, offset, this.sources);
 
-    this._length += text.length;
-    this.invalidateLineCache();
+    // Typing appends to the add buffer, so the previous piece can usually
+    // be extended instead of adding a node per keystroke
+    const extended = extendLast(before, addStart, text.length, this.sources);
+    this.root = extended
+      ? merge(extended, after)
+      : merge(merge(before, this.createNode({ source: 'add', start: addStart, length: text.length })), after);
+
+    this.markModified();
+    this.recordChange(start, start, endOfInsertion(start, text));
   }
 
   /**
@@ -254,63 +440,19 @@ export class Buffer {
    */
   delete(start: number, end: number): string {
     if (start >= end || start < 0) return '';
-    if (end > this._length) end = this._length;
-    if (start >= this._length) return '';
+    if (end > this.length) end = this.length;
+    if (start >= this.length) return '';
 
     const deletedText = this.getRange(start, end);
+    const startPosition = this.offsetToPosition(start);
+    const endPosition = this.offsetToPosition(end);
 
-    // Find and modify pieces affected by deletion
-    let currentOffset = 0;
-    let newPieces: Piece[] = [];
-
-    for (const piece of this.pieces) {
-      const pieceStart = currentOffset;
-      const pieceEnd = currentOffset + piece.length;
-
-      if (pieceEnd <= start || pieceStart >= end) {
-        // Piece is entirely outside deletion range
-        newPieces.push(piece);
-      } else if (pieceStart >= start && pieceEnd <= end) {
-        // Piece is entirely inside deletion range - skip it
-      } else if (pieceStart < start && pieceEnd > end) {
-        // Deletion is entirely inside this piece - split it
-        const beforeLength = start - pieceStart;
-        const afterStart = end - pieceStart;
-        
-        newPieces.push({
-          source: piece.source,
-          start: piece.start,
-          length: beforeLength
-        });
-        newPieces.push({
-          source: piece.source,
-          start: piece.start + afterStart,
-          length: piece.length - afterStart
-        });
-      } else if (pieceStart < start) {
-        // Deletion starts in this piece
-        newPieces.push({
-          source: piece.source,
-          start: piece.start,
-          length: start - pieceStart
-        });
-      } else {
-        // Deletion ends in this piece
-        const keepStart = end - pieceStart;
-        newPieces.push({
-          source: piece.source,
-          start: piece.start + keepStart,
-          length: piece.length - keepStart
-        });
-      }
-
-      currentOffset = pieceEnd;
-    }
+    const [before, rest] = split(this.root, start, this.sources);
+    const [, after] = split(rest, end - start, this.sources);
+    this.root = merge(before, after);
+    this.markModified();
+    this.recordChange(startPosition, endPosition, startPosition);
 
-    this.pieces = newPieces;
-    this._length -= (end - start);
-    this.invalidateLineCache();
-    
     return deletedText;
   }
 
@@ -342,140 +484,78 @@ export class Buffer {
   }
 
   /**
-   * Convert a Position to an absolute offset
+   * Get an immutable view of the current content in O(1).
+   * The snapshot is unaffected by later edits.
    */
-  positionToOffset(position: Position): number {
-    this.ensureLineCache();
-    
-    const { line, column } = position;
-    
-    if (line < 0) return 0;
-    if (line >= this.lineCache!.length) {
-      return this._length;
-    }
-
-    const lineStartOffset = this.getLineStartOffset(line);
-    const lineLength = this.getLineLength(line);
-    
-    return lineStartOffset + Math.min(column, lineLength);
+  snapshot(): BufferSnapshot {
+    return new BufferSnapshot(this.root, this.sources, this._version);
   }
 
   /**
-   * Convert an absolute offset to a Position
+   * Replace the whole content (e.g. after reloading from disk).
+   * The version keeps increasing, but older positions can't be remapped.
    */
-  offsetToPosition(offset: number): Position {
-    this.ensureLineCache();
-    
-    if (offset <= 0) return { line: 0, column: 0 };
-    if (offset >= this._length) {
-      const lastLine = Math.max(0, this.lineCache!.length - 1);
-      return { line: lastLine, column: this.getLineLength(lastLine) };
-    }
-
-    // Binary search for the line
-    let low = 0;
-    let high = this.lineCache!.length - 1;
-
-    while (low < high) {
-      const mid = Math.floor((low + high + 1) / 2);
-      const lineStart = this.getLineStartOffset(mid);
-      
-      if (lineStart <= offset) {
-        low = mid;
-      } else {
-        high = mid - 1;
-      }
-    }
-
-    const lineStart = this.getLineStartOffset(low);
-    return { line: low, column: offset - lineStart };
+  reset(content: string): void {
+    this.sources = {
+      original: content,
+      originalLineStarts: computeLineStarts(content),
+      add: '',
+      addLineStarts: [],
+    };
+    this.root = content.length > 0
+      ? this.createNode({ source: 'original', start: 0, length: content.length })
+      : null;
+    this.markModified();
+    this.clearHistory();
   }
 
   /**
-   * Get the length of a specific line (excluding line ending)
+   * Get the changes made after `version`, oldest first.
+   * Returns null if the version is unknown or older than the change history.
    */
-  getLineLength(lineNumber: number): number {
-    this.ensureLineCache();
-    
-    if (lineNumber < 0 || lineNumber >= this.lineCache!.length) {
-      return 0;
-    }
-
-    const lineStart = this.getLineStartOffset(lineNumber);
-    const lineEnd = lineNumber + 1 < this.lineCache!.length
-      ? this.getLineStartOffset(lineNumber + 1) - 1  // -1 for newline
-      : this._length;
-
-    return Math.max(0, lineEnd - lineStart);
+  getChangesSince(version: number): BufferChange[] | null {
+    if (version < this.historyStart || version > this._version) return null;
+    return this.changes.filter(c => c.version > version);
   }
 
   /**
-   * Get the start offset of a line
+   * Increment the version counter (call after any modification).
    */
-  private getLineStartOffset(lineNumber: number): number {
-    this.ensureLineCache();
-    
-    if (lineNumber <= 0) return 0;
-    if (lineNumber >= this.lineCache!.length) return this._length;
-    
-    return this.lineCache![lineNumber]!.lineStartOffset;
+  private markModified(): void {
+    this._version++;
   }
 
-  /**
-   * Invalidate the line cache (call after any modification).
-   * Also increments the version counter for change detection.
-   */
-  private invalidateLineCache(): void {
-    this.lineCache = null;
-    this._version++;
+  private recordChange(start: Position, end: Position, newEnd: Position): void {
+    this.changes.push({ version: this._version, start, end, newEnd });
+    if (this.changes.length > MAX_CHANGE_HISTORY) {
+      const dropped = this.changes.shift()!;
+      this.historyStart = dropped.version;
+    }
+  }
+
+  private clearHistory(): void {
+    this.changes = [];
+    this.historyStart = this._version;
   }
 
   /**
-   * Build the line cache if needed
+   * Create a leaf node for a piece with a random priority.
    */
-  private ensureLineCache(): void {
-    if (this.lineCache !== null) return;
-
-    this.lineCache = [];
-    let currentOffset = 0;
-    let lineStartOffset = 0;
-
-    // First line always starts at 0
-    this.lineCache.push({
-      pieceIndex: 0,
-      offsetInPiece: 0,
-      lineStartOffset: 0
-    });
-
-    for (let pieceIndex = 0; pieceIndex < this.pieces.length; pieceIndex++) {
-      const piece = this.pieces[pieceIndex]!;
-      const source = piece.source === 'original' ? this.originalBuffer : this.addBuffer;
-      
-      for (let i = 0; i < piece.length; i++) {
-        const char = source[piece.start + i];
-        if (char === '\n') {
-          lineStartOffset = currentOffset + i + 1;
-          this.lineCache.push({
-            pieceIndex,
-            offsetInPiece: i + 1,
-            lineStartOffset
-          });
-        }
-      }
-      
-      currentOffset += piece.length;
-    }
+  private createNode(piece: Piece): PieceNode {
+    return makeNode(piece, lineBreaksIn(this.sources, piece), Math.random(), null, null);
   }
 
   /**
-   * Clone the buffer state (for undo/redo)
+   * Clone the buffer state (for undo/redo).
+   * Tree nodes are immutable, so the clone shares them.
    */
   clone(): Buffer {
     const cloned = new Buffer();
-    cloned.originalBuffer = this.originalBuffer;
-    cloned.addBuffer = this.addBuffer;
-    cloned.pieces = this.pieces.map(p => ({ ...p }));
-    cloned._length = this._length;
+    cloned.sources = {
+      ...this.sources,
+      addLineStarts: [...this.sources.addLineStarts],
+    };
+    cloned.root = this.root;
     return cloned;
   }
 
@@ -484,8 +564,8 @@ export class Buffer {
    */
   getSnapshot(): { pieces: Piece[]; addBuffer: string } {
     return {
-      pieces: this.pieces.map(p => ({ ...p })),
-      addBuffer: this.addBuffer
+      pieces: this.getPieces(),
+      addBuffer: this.sources.add
     };
   }
 
@@ -493,11 +573,246 @@ export class Buffer {
    * Restore from a snapshot
    */
   restoreSnapshot(snapshot: { pieces: Piece[]; addBuffer: string }): void {
-    this.pieces = snapshot.pieces.map(p => ({ ...p }));
-    this.addBuffer = snapshot.addBuffer;
-    this._length = this.pieces.reduce((sum, p) => sum + p.length, 0);
-    this.invalidateLineCache();
+    // A fresh line-start table: snapshots of this buffer may still share the old one
+    this.sources = {
+      original: this.sources.original,
+      originalLineStarts: this.sources.originalLineStarts,
+      add: snapshot.addBuffer,
+      addLineStarts: computeLineStarts(snapshot.addBuffer),
+    };
+    this.root = buildTree(
+      snapshot.pieces.filter(p => p.length > 0).map(p => this.createNode({ ...p }))
+    );
+    this.markModified();
+    this.clearHistory();
+  }
+}
+
+// ============================================
+// Position transforms
+// ============================================
+
+/**
+ * Map a position through a sequence of changes (from getChangesSince).
+ * Positions inside a replaced range move to its start (bias 'left') or to
+ * the end of the new text (bias 'right').
+ */
+export function transformPosition(
+  position: Position,
+  changes: readonly BufferChange[],
+  bias: PositionBias = 'right'
+): Position {
+  let { line, column } = position;
+
+  for (const change of changes) {
+    const { start, end, newEnd } = change;
+    const isInsertion = comparePositions(start, end) === 0;
+    const toStart = comparePositions({ line, column }, start);
+
+    if (toStart < 0 || (toStart === 0 && (bias === 'left' || !isInsertion))) {
+      continue;
+    }
+
+    if (comparePositions({ line, column }, end) < 0) {
+      // Inside the replaced range
+      ({ line, column } = bias === 'left' ? start : newEnd);
+      continue;
+    }
+
+    // After the change: shift by the size difference
+    if (line === end.line) {
+      column = newEnd.column + (column - end.column);
+    }
+    line += newEnd.line - end.line;
+  }
+
+  return { line, column };
+}
+
+/**
+ * Map a range through a sequence of changes. Text inserted at either edge
+ * is kept outside the range.
+ */
+export function transformRange(range: Range, changes: readonly BufferChange[]): Range {
+  const start = transformPosition(range.start, changes, 'right');
+  const end = transformPosition(range.end, changes, 'left');
+  return comparePositions(end, start) < 0 ? { start, end: start } : { start, end };
+}
+
+function comparePositions(a: Position, b: Position): number {
+  return a.line !== b.line ? a.line - b.line : a.column - b.column;
+}
+
+/**
+ * Position just after `text` when inserted at `start`.
+ */
+function endOfInsertion(start: Position, text: string): Position {
+  const lastBreak = text.lastIndexOf('\n');
+  if (lastBreak === -1) {
+    return { line: start.line, column: start.column + text.length };
+  }
+  let lineBreaks = 0;
+  for (let i = text.indexOf('\n'); i !== -1; i = text.indexOf('\n', i + 1)) lineBreaks++;
+  return { line: start.line + lineBreaks, column: text.length - lastBreak - 1 };
+}
+
+// ============================================
+// Tree operations
+// ============================================
+
+function makeNode(
+  piece: Piece,
+  pieceLineBreaks: number,
+  priority: number,
+  left: PieceNode | null,
+  right: PieceNode | null
+): PieceNode {
+  return {
+    piece,
+    pieceLineBreaks,
+    priority,
+    left,
+    right,
+    length: (left?.length ?? 0) + piece.length + (right?.length ?? 0),
+    lineBreaks: (left?.lineBreaks ?? 0) + pieceLineBreaks + (right?.lineBreaks ?? 0),
+  };
+}
+
+function withChildren(node: PieceNode, left: PieceNode | null, right: PieceNode | null): PieceNode {
+  return makeNode(node.piece, node.pieceLineBreaks, node.priority, left, right);
+}
+
+/**
+ * Split a tree into the first `offset` characters and the rest, splitting
+ * the piece that straddles the offset if needed.
+ */
+function split(node: PieceNode | null, offset: number, sources: PieceSources): [PieceNode | null, PieceNode | null] {
+  if (!node) return [null, null];
+
+  const leftLength = node.left?.length ?? 0;
+  if (offset <= leftLength) {
+    const [a, b] = split(node.left, offset, sources);
+    return [a, withChildren(node, b, node.right)];
+  }
+
+  const pieceEnd = leftLength + node.piece.length;
+  if (offset >= pieceEnd) {
+    const [a, b] = split(node.right, offset - pieceEnd, sources);
+    return [withChildren(node, node.left, a), b];
+  }
+
+  // The offset falls inside this node's piece
+  const cut = offset - leftLength;
+  const { piece } = node;
+  const head: Piece = { source: piece.source, start: piece.start, length: cut };
+  const tail: Piece = { source: piece.source, start: piece.start + cut, length: piece.length - cut };
+  const headBreaks = lineBreaksIn(sources, head);
+
+  return [
+    makeNode(head, headBreaks, node.priority, node.left, null),
+    makeNode(tail, node.pieceLineBreaks - headBreaks, node.priority, null, node.right),
+  ];
+}
+
+/**
+ * Concatenate two trees (every offset in `a` precedes `b`).
+ */
+function merge(a: PieceNode | null, b: PieceNode | null): PieceNode | null {
+  if (!a) return b;
+  if (!b) return a;
+
+  if (a.priority > b.priority) {
+    return withChildren(a, a.left, merge(a.right, b));
+  }
+  return withChildren(b, merge(a, b.left), b.right);
+}
+
+/**
+ * Extend the last piece of a tree if it ends where newly appended add-buffer
+ * text starts. Returns null when the last piece can't be extended.
+ */
+function extendLast(node: PieceNode | null, addStart: number, length: number, sources: PieceSources): PieceNode | null {
+  if (!node) return null;
+
+  if (node.right) {
+    const right = extendLast(node.right, addStart, length, sources);
+    return right ? withChildren(node, node.left, right) : null;
+  }
+
+  const { piece } = node;
+  if (piece.source !== 'add' || piece.start + piece.length !== addStart) return null;
+
+  const extended: Piece = { source: 'add', start: piece.start, length: piece.length + length };
+  return makeNode(extended, lineBreaksIn(sources, extended), node.priority, node.left, null);
+}
+
+/**
+ * Build a treap from nodes in document order in O(n) (Cartesian tree).
+ */
+function buildTree(nodes: PieceNode[]): PieceNode | null {
+  // Build on mutable records, then freeze bottom-up into PieceNodes
+  interface Draft { node: PieceNode; left: Draft | null; right: Draft | null }
+  const stack: Draft[] = [];
+
+  for (const node of nodes) {
+    const draft: Draft = { node, left: null, right: null };
+    let last: Draft | null = null;
+    while (stack.length > 0 && stack[stack.length - 1]!.node.priority < node.priority) {
+      last = stack.pop()!;
+    }
+    draft.left = last;
+    if (stack.length > 0) stack[stack.length - 1]!.right = draft;
+    stack.push(draft);
+  }
+
+  const freeze = (draft: Draft | null): PieceNode | null =>
+    draft ? withChildren(draft.node, freeze(draft.left), freeze(draft.right)) : null;
+
+  return freeze(stack[0] ?? null);
+}
+
+// ============================================
+// Helpers
+// ============================================
+
+/**
+ * Offsets just after each '\n' in a string.
+ */
+function computeLineStarts(text: string): number[] {
+  const lineStarts: number[] = [];
+  for (let i = text.indexOf('\n'); i !== -1; i = text.indexOf('\n', i + 1)) {
+    lineStarts.push(i + 1);
+  }
+  return lineStarts;
+}
+
+function lineStartsFor(sources: PieceSources, piece: Piece): number[] {
+  return piece.source === 'original' ? sources.originalLineStarts : sources.addLineStarts;
+}
+
+/**
+ * Count line breaks within a piece.
+ */
+function lineBreaksIn(sources: PieceSources, piece: Piece): number {
+  const lineStarts = lineStartsFor(sources, piece);
+  return upperBound(lineStarts, piece.start + piece.length) - upperBound(lineStarts, piece.start);
+}
+
+/**
+ * Number of entries in a sorted array that are <= value.
+ */
+function upperBound(values: number[], value: number): number {
+  let low = 0;
+  let high = values.length;
+  while (low < high) {
+    const mid = (low + high) >>> 1;
+    if (values[mid]! <= value) {
+      low = mid + 1;
+    } else {
+      high = mid;
+    }
   }
+  return low;
 }
 
 export default Buffer;
@@ -0,0 +1,117 @@
+/**
+ * Dependency Graph Export
+ *
+ * Renders a graph as Graphviz DOT or a Mermaid flowchart. Edges on import
+ * cycles are drawn red and layer violations dashed orange.
+ */
+
+import { edgeKey, findCycles, getCycleEdges } from './graph.ts';
+import type { DependencyGraph, LayerViolation } from './types.ts';
+
+/**
+ * Export format.
+ */
+export type DependencyGraphFormat = 'dot' | 'mermaid';
+
+/**
+ * What to highlight in an export.
+ */
+export interface DependencyExportOptions {
+  /** Layer violations to mark (from checkLayers) */
+  violations?: readonly LayerViolation[];
+}
+
+interface EdgeStyle {
+  cycle: boolean;
+  violation: boolean;
+}
+
+function collectEdges(
+  graph: DependencyGraph,
+  options: DependencyExportOptions
+): Array<{ from: string; to: string; style: EdgeStyle }> {
+  const cycleEdges = getCycleEdges(graph, findCycles(graph));
+  const violationEdges = new Set((options.violations ?? []).map((v) => edgeKey(v.from, v.to)));
+  const edges: Array<{ from: string; to: string; style: EdgeStyle }> = [];
+
+  for (const from of [...graph.imports.keys()].sort()) {
+    for (const to of [...graph.imports.get(from)!].sort()) {
+      const key = edgeKey(from, to);
+      edges.push({ from, to, style: { cycle: cycleEdges.has(key), violation: violationEdges.has(key) } });
+    }
+  }
+  return edges;
+}
+
+// ============================================
+// DOT
+// ============================================
+
+function dotString(value: string): string {
+  return `"${value.replace(/\\/g, '\\\\').replace(/"/g, '\\"')}"`;
+}
+
+/**
+ * Render a graph as Graphviz DOT.
+ */
+export function toDot(graph: DependencyGraph, options: DependencyExportOptions = {}): string {
+  const lines = ['digraph dependencies {', '  rankdir=LR;', '  node [shape=box, fontname="monospace"];'];
+
+  for (const node of [...graph.nodes.values()].sort((a, b) => a.id.localeCompare(b.id))) {
+    const attrs = [`label=${dotString(node.label)}`];
+    if (node.external) attrs.push('style=dashed', 'color=gray');
+    lines.push(`  ${dotString(node.id)} [${attrs.join(', ')}];`);
+  }
+
+  for (const { from, to, style } of collectEdges(graph, options)) {
+    const attrs: string[] = [];
+    if (style.cycle) attrs.push('color=red');
+    if (style.violation) attrs.push('style=dashed', style.cycle ? 'penwidth=2' : 'color=orange');
+    const suffix = attrs.length > 0 ? ` [${attrs.join(', ')}]` : '';
+    lines.push(`  ${dotString(from)} -> ${dotString(to)}${suffix};`);
+  }
+
+  lines.push('}');
+  return lines.join('\n') + '\n';
+}
+
+// ============================================
+// Mermaid
+// ============================================
+
+/**
+ * Render a graph as a Mermaid flowchart. Node ids are replaced with
+ * `n0`, `n1`, ... since Mermaid ids can't contain path characters.
+ */
+export function toMermaid(graph: DependencyGraph, options: DependencyExportOptions = {}): string {
+  const lines = ['flowchart LR'];
+  const ids = new Map<string, string>();
+
+  for (const node of [...graph.nodes.values()].sort((a, b) => a.id.localeCompare(b.id))) {
+    const id = `n${ids.size}`;
+    ids.set(node.id, id);
+    const label = node.label.replace(/"/g, '#quot;');
+    lines.push(node.external ? `  ${id}(["${label}"])` : `  ${id}["${label}"]`);
+  }
+
+  const edges = collectEdges(graph, options);
+  const styles: string[] = [];
+  edges.forEach(({ from, to, style }, index) => {
+    lines.push(`  ${ids.get(from)} ${style.violation ? '-.->' : '-->'} ${ids.get(to)}`);
+    if (style.cycle) styles.push(`  linkStyle ${index} stroke:red`);
+    else if (style.violation) styles.push(`  linkStyle ${index} stroke:orange`);
+  });
+
+  return [...lines, ...styles].join('\n') + '\n';
+}
+
+/**
+ * Render a graph in the given format.
+ */
+export function exportDependencyGraph(
+  graph: DependencyGraph,
+  format: DependencyGraphFormat,
+  options: DependencyExportOptions = {}
+): string {
+  return format === 'dot' ? toDot(graph, options) : toMermaid(graph, options);
+}
@@ -0,0 +1,122 @@
+/**
+ * Go Package Graph
+ *
+ * Builds the package import graph of a Go module from `go list -json`.
+ */
+
+import { relative } from 'path';
+import { addDependency, addDependencyNode, createDependencyGraph } from './graph.ts';
+import type { DependencyGraph, DependencyGraphOptions } from './types.ts';
+
+/**
+ * The `go list -json` fields used to build the graph.
+ */
+interface GoListPackage {
+  ImportPath?: string;
+  Dir?: string;
+  Imports?: string[];
+  Module?: { Path?: string };
+}
+
+// ============================================
+// Parsing
+// ============================================
+
+/**
+ * Split `go list -json` output (a stream of JSON objects, not an array)
+ * into the individual objects.
+ */
+export function splitJsonStream(output: string): string[] {
+  const objects: string[] = [];
+  let depth = 0;
+  let start = -1;
+  let inString = false;
+
+  for (let i = 0; i < output.length; i++) {
+    const char = output[i];
+    if (inString) {
+      if (char === '\\') i++;
+      else if (char === '"') inString = false;
+    } else if (char === '"') {
+      inString = true;
+    } else if (char === '{') {
+      if (depth === 0) start = i;
+      depth++;
+    } else if (char === '}' && depth > 0) {
+      depth--;
+      if (depth === 0) objects.push(output.slice(start, i + 1));
+    }
+  }
+
+  return objects;
+}
+
+/**
+ * Build a package graph from `go list -json` output. Labels are import
+ * paths relative to the module path (the root package keeps the module
+ * path), so layer patterns read like `internal/**`.
+ */
+export function parseGoList(output: string, workspaceRoot: string, options: DependencyGraphOptions = {}): DependencyGraph {
+  const packages: GoListPackage[] = [];
+  for (const json of splitJsonStream(output)) {
+    try {
+      packages.push(JSON.parse(json) as GoListPackage);
+    } catch {
+      // Skip malformed entries
+    }
+  }
+
+  const graph = createDependencyGraph('go');
+  for (const pkg of packages) {
+    if (!pkg.ImportPath) continue;
+    addDependencyNode(graph, {
+      id: pkg.ImportPath,
+      label: goLabel(pkg.ImportPath, pkg.Module?.Path),
+      path: pkg.Dir ? relative(workspaceRoot, pkg.Dir) || '.' : undefined,
+    });
+  }
+
+  for (const pkg of packages) {
+    if (!pkg.ImportPath) continue;
+    for (const imported of pkg.Imports ?? []) {
+      if (!graph.nodes.has(imported)) {
+        if (!options.includeExternal || imported === 'C') continue;
+        addDependencyNode(graph, { id: imported, label: imported, external: true });
+      }
+      addDependency(graph, pkg.ImportPath, imported);
+    }
+  }
+
+  return graph;
+}
+
+function goLabel(importPath: string, modulePath: string | undefined): string {
+  if (modulePath && importPath.startsWith(`${modulePath}/`)) {
+    return importPath.slice(modulePath.length + 1);
+  }
+  return importPath;
+}
+
+// ============================================
+// Loading
+// ============================================
+
+/**
+ * Run `go list` in a workspace and build its package graph.
+ */
+export async function loadGoGraph(workspaceRoot: string, options: DependencyGraphOptions = {}): Promise<DependencyGraph> {
+  const proc = Bun.spawn(['go', 'list', '-e', '-json', './...'], {
+    cwd: workspaceRoot,
+    stdout: 'pipe',
+    stderr: 'pipe',
+  });
+
+  const output = await new Response(proc.stdout).text();
+  const exitCode = await proc.exited;
+  if (exitCode !== 0 && !output.trim()) {
+    const stderr = await new Response(proc.stderr).text();
+    throw new Error(stderr.trim() || `go list exited with code ${exitCode}`);
+  }
+
+  return parseGoList(output, workspaceRoot, options);
+}
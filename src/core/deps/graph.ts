@@ -0,0 +1,190 @@
+/**
+ * Dependency Graph
+ *
+ * Graph construction, reverse dependencies, cycle detection and layer
+ * checks shared by the Go and TypeScript graph builders.
+ */
+
+import type { DependencyGraph, DependencyGraphKind, DependencyLayer, DependencyNode, LayerViolation } from './types.ts';
+
+// ============================================
+// Construction
+// ============================================
+
+/**
+ * Create an empty graph.
+ */
+export function createDependencyGraph(kind: DependencyGraphKind): DependencyGraph {
+  return { kind, nodes: new Map(), imports: new Map() };
+}
+
+/**
+ * Add a node, keeping an existing node with the same id.
+ */
+export function addDependencyNode(graph: DependencyGraph, node: DependencyNode): void {
+  if (!graph.nodes.has(node.id)) {
+    graph.nodes.set(node.id, node);
+    graph.imports.set(node.id, new Set());
+  }
+}
+
+/**
+ * Add an import edge. Both nodes must already exist.
+ */
+export function addDependency(graph: DependencyGraph, from: string, to: string): void {
+  graph.imports.get(from)?.add(to);
+}
+
+/**
+ * Ids a node imports, sorted.
+ */
+export function getDependencies(graph: DependencyGraph, id: string): string[] {
+  return [...(graph.imports.get(id) ?? [])].sort();
+}
+
+/**
+ * Build the reverse graph: for each node, the ids that import it.
+ */
+export function reverseDependencies(graph: DependencyGraph): Map<string, Set<string>> {
+  const reverse = new Map<string, Set<string>>();
+  for (const id of graph.nodes.keys()) {
+    reverse.set(id, new Set());
+  }
+  for (const [from, targets] of graph.imports) {
+    for (const to of targets) {
+      reverse.get(to)?.add(from);
+    }
+  }
+  return reverse;
+}
+
+// ============================================
+// Cycles
+// ============================================
+
+/**
+ * Find import cycles: strongly connected components with more than one
+ * node, plus nodes that import themselves. Each cycle is sorted, and
+ * cycles are ordered by their first id.
+ */
+export function findCycles(graph: DependencyGraph): string[][] {
+  // Iterative Tarjan, so deep graphs don't overflow the stack
+  const index = new Map<string, number>();
+  const lowLink = new Map<string, number>();
+  const onStack = new Set<string>();
+  const stack: string[] = [];
+  const cycles: string[][] = [];
+  let counter = 0;
+
+  for (const root of graph.nodes.keys()) {
+    if (index.has(root)) continue;
+
+    const work: Array<{ id: string; targets: Iterator<string> }> = [];
+    const visit = (id: string) => {
+      index.set(id, counter);
+      lowLink.set(id, counter);
+      counter++;
+      stack.push(id);
+      onStack.add(id);
+      work.push({ id, targets: (graph.imports.get(id) ?? new Set<string>()).values() });
+    };
+    visit(root);
+
+    while (work.length > 0) {
+      const frame = work[work.length - 1]!;
+      const next = frame.targets.next();
+      if (!next.done) {
+        const target = next.value;
+        if (!index.has(target)) {
+          visit(target);
+        } else if (onStack.has(target)) {
+          lowLink.set(frame.id, Math.min(lowLink.get(frame.id)!, index.get(target)!));
+        }
+        continue;
+      }
+
+      work.pop();
+      const parent = work[work.length - 1];
+      if (parent) {
+        lowLink.set(parent.id, Math.min(lowLink.get(parent.id)!, lowLink.get(frame.id)!));
+      }
+
+      if (lowLink.get(frame.id) === index.get(frame.id)) {
+        const component: string[] = [];
+        let member: string;
+        do {
+          member = stack.pop()!;
+          onStack.delete(member);
+          component.push(member);
+        } while (member !== frame.id);
+
+        if (component.length > 1 || graph.imports.get(frame.id)?.has(frame.id)) {
+          cycles.push(component.sort());
+        }
+      }
+    }
+  }
+
+  return cycles.sort((a, b) => a[0]!.localeCompare(b[0]!));
+}
+
+/**
+ * Edges that lie on a cycle, as `from\0to` keys.
+ */
+export function getCycleEdges(graph: DependencyGraph, cycles: readonly string[][]): Set<string> {
+  const edges = new Set<string>();
+  for (const cycle of cycles) {
+    const members = new Set(cycle);
+    for (const from of cycle) {
+      for (const to of graph.imports.get(from) ?? []) {
+        if (members.has(to)) edges.add(edgeKey(from, to));
+      }
+    }
+  }
+  return edges;
+}
+
+/**
+ * Key for an edge in edge sets.
+ */
+export function edgeKey(from: string, to: string): string {
+  return `${from}\0${to}`;
+}
+
+// ============================================
+// Layers
+// ============================================
+
+/**
+ * Index of the first layer whose patterns match a label, or -1.
+ */
+export function matchLayer(label: string, layers: readonly DependencyLayer[]): number {
+  return layers.findIndex((layer) => layer.include.some((pattern) => new Bun.Glob(pattern).match(label)));
+}
+
+/**
+ * Find imports from a layer into a layer above it. Nodes outside every
+ * layer (including external ones) are not checked.
+ */
+export function checkLayers(graph: DependencyGraph, layers: readonly DependencyLayer[]): LayerViolation[] {
+  if (layers.length === 0) return [];
+
+  const layerOf = new Map<string, number>();
+  for (const node of graph.nodes.values()) {
+    if (!node.external) layerOf.set(node.id, matchLayer(node.label, layers));
+  }
+
+  const violations: LayerViolation[] = [];
+  for (const [from, targets] of graph.imports) {
+    const fromLayer = layerOf.get(from) ?? -1;
+    if (fromLayer < 0) continue;
+    for (const to of targets) {
+      const toLayer = layerOf.get(to) ?? -1;
+      if (toLayer >= 0 && toLayer < fromLayer) {
+        violations.push({ from, to, fromLayer: layers[fromLayer]!.name, toLayer: layers[toLayer]!.name });
+      }
+    }
+  }
+
+  return violations.sort((a, b) => a.from.localeCompare(b.from) || a.to.localeCompare(b.to));
+}
@@ -0,0 +1,66 @@
+/**
+ * Dependency Graphs
+ *
+ * Package and module import graphs for Go and TypeScript workspaces, with
+ * cycle detection, reverse dependencies, layer checks and DOT/Mermaid
+ * export.
+ */
+
+import { existsSync } from 'fs';
+import { join } from 'path';
+import { loadGoGraph } from './go.ts';
+import { loadTypeScriptGraph } from './typescript.ts';
+import type { DependencyGraph, DependencyGraphKind, DependencyGraphOptions } from './types.ts';
+
+export type {
+  DependencyGraph,
+  DependencyGraphKind,
+  DependencyGraphOptions,
+  DependencyLayer,
+  DependencyNode,
+  LayerViolation,
+} from './types.ts';
+export {
+  addDependency,
+  addDependencyNode,
+  checkLayers,
+  createDependencyGraph,
+  edgeKey,
+  findCycles,
+  getCycleEdges,
+  getDependencies,
+  matchLayer,
+  reverseDependencies,
+} from './graph.ts';
+export { loadGoGraph, parseGoList, splitJsonStream } from './go.ts';
+export { buildTypeScriptGraph, loadTypeScriptGraph, packageName, parseImportSpecifiers, resolveImport } from './typescript.ts';
+export {
+  exportDependencyGraph,
+  toDot,
+  toMermaid,
+  type DependencyExportOptions,
+  type DependencyGraphFormat,
+} from './export.ts';
+
+/**
+ * Detect which graph a workspace supports: Go if it has a go.mod,
+ * TypeScript if it has a package.json or tsconfig.json.
+ */
+export function detectGraphKind(workspaceRoot: string): DependencyGraphKind | null {
+  if (existsSync(join(workspaceRoot, 'go.mod'))) return 'go';
+  if (existsSync(join(workspaceRoot, 'package.json')) || existsSync(join(workspaceRoot, 'tsconfig.json'))) {
+    return 'typescript';
+  }
+  return null;
+}
+
+/**
+ * Build the dependency graph of a workspace.
+ */
+export function loadDependencyGraph(
+  workspaceRoot: string,
+  kind: DependencyGraphKind,
+  options: DependencyGraphOptions = {}
+): Promise<DependencyGraph> {
+  return kind === 'go' ? loadGoGraph(workspaceRoot, options) : loadTypeScriptGraph(workspaceRoot, options);
+}
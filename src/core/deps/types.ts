@@ -0,0 +1,70 @@
+/**
+ * Dependency Graph Types
+ */
+
+// ============================================
+// Graph
+// ============================================
+
+/**
+ * Language a graph was built from.
+ */
+export type DependencyGraphKind = 'go' | 'typescript';
+
+/**
+ * A package (Go) or module (TypeScript) in the graph.
+ */
+export interface DependencyNode {
+  /** Import path (Go) or workspace-relative file path (TypeScript) */
+  id: string;
+  /** Display label */
+  label: string;
+  /** Workspace-relative file or directory to open */
+  path?: string;
+  /** Outside the workspace (standard library or third-party) */
+  external?: boolean;
+}
+
+/**
+ * A directed import graph. `imports` maps each node id to the ids it
+ * imports; every id in it has an entry in `nodes`.
+ */
+export interface DependencyGraph {
+  kind: DependencyGraphKind;
+  nodes: Map<string, DependencyNode>;
+  imports: Map<string, Set<string>>;
+}
+
+/**
+ * Options for building a graph.
+ */
+export interface DependencyGraphOptions {
+  /** Include standard library / third-party imports as external nodes */
+  includeExternal?: boolean;
+}
+
+// ============================================
+// Layering
+// ============================================
+
+/**
+ * An architecture layer. Layers are listed from the top (e.g. `cmd`) to
+ * the bottom (e.g. `internal/util`); a layer may import itself and the
+ * layers below it, never those above.
+ */
+export interface DependencyLayer {
+  /** Layer name shown in violations */
+  name: string;
+  /** Glob patterns matched against node labels */
+  include: string[];
+}
+
+/**
+ * An import that goes up the layer stack.
+ */
+export interface LayerViolation {
+  from: string;
+  to: string;
+  fromLayer: string;
+  toLayer: string;
+}
@@ -0,0 +1,183 @@
+/**
+ * TypeScript Module Graph
+ *
+ * Builds the module import graph of a TypeScript/JavaScript workspace by
+ * parsing import and export statements, dynamic imports and require()
+ * calls. Relative specifiers are resolved to workspace files; bare
+ * specifiers become external package nodes.
+ */
+
+import { posix } from 'path';
+import { addDependency, addDependencyNode, createDependencyGraph } from './graph.ts';
+import type { DependencyGraph, DependencyGraphOptions } from './types.ts';
+
+/** Source file extensions, in resolution order */
+const SOURCE_EXTENSIONS = ['.ts', '.tsx', '.mts', '.cts', '.js', '.jsx', '.mjs', '.cjs'];
+
+/** Emitted extensions that may refer to a TypeScript source */
+const EMITTED_TO_SOURCE: Record<string, string[]> = {
+  '.js': ['.ts', '.tsx'],
+  '.jsx': ['.tsx'],
+  '.mjs': ['.mts'],
+  '.cjs': ['.cts'],
+};
+
+/** Directories never scanned */
+const IGNORED_DIRECTORIES = new Set(['node_modules', '.git', 'dist', 'build', 'out', 'coverage']);
+
+/** Upper bound on files read when loading a workspace */
+const MAX_FILES = 5000;
+
+// ============================================
+// Parsing
+// ============================================
+
+/**
+ * Blank out comments so commented-out imports are ignored, leaving
+ * strings intact.
+ */
+function stripComments(source: string): string {
+  let result = '';
+  let i = 0;
+  while (i < source.length) {
+    const char = source[i]!;
+    if (char === '"' || char === "'" || char === '`') {
+      let j = i + 1;
+      while (j < source.length && source[j] !== char) {
+        if (source[j] === '\\') j++;
+        j++;
+      }
+      result += source.slice(i, j + 1);
+      i = j + 1;
+    } else if (source.startsWith('//', i)) {
+      const end = source.indexOf('\n', i);
+      i = end < 0 ? source.length : end;
+    } else if (source.startsWith('/*', i)) {
+      const end = source.indexOf('*/', i + 2);
+      const comment = source.slice(i, end < 0 ? source.length : end + 2);
+      result += comment.replace(/[^\n]/g, ' ');
+      i += comment.length;
+    } else {
+      result += char;
+      i++;
+    }
+  }
+  return result;
+}
+
+const IMPORT_PATTERNS = [
+  // import x from '...', import { a } from '...', export * from '...'
+  /\b(?:import|export)\s+(?:type\s+)?[\w\s{},*$]*?\bfrom\s*(['"])([^'"\n]+)\1/g,
+  // import '...'
+  /\bimport\s*(['"])([^'"\n]+)\1/g,
+  // import('...'), require('...')
+  /\b(?:import|require)\s*\(\s*(['"])([^'"\n]+)\1\s*\)/g,
+];
+
+/**
+ * Extract the module specifiers a source file imports, in order of
+ * first appearance.
+ */
+export function parseImportSpecifiers(source: string): string[] {
+  const code = stripComments(source);
+  const found: Array<{ index: number; specifier: string }> = [];
+  for (const pattern of IMPORT_PATTERNS) {
+    for (const match of code.matchAll(pattern)) {
+      found.push({ index: match.index!, specifier: match[2]! });
+    }
+  }
+  found.sort((a, b) => a.index - b.index);
+  return [...new Set(found.map((f) => f.specifier))];
+}
+
+// ============================================
+// Resolution
+// ============================================
+
+/**
+ * Resolve a relative specifier to a workspace file, trying extensions and
+ * index files the way TypeScript's bundler resolution does. Returns null
+ * for bare specifiers and unresolvable paths.
+ */
+export function resolveImport(fromPath: string, specifier: string, files: ReadonlySet<string>): string | null {
+  if (!specifier.startsWith('./') && !specifier.startsWith('../') && specifier !== '.' && specifier !== '..') {
+    return null;
+  }
+
+  const base = posix.normalize(posix.join(posix.dirname(fromPath), specifier));
+  const candidates = [base];
+
+  const ext = posix.extname(base);
+  for (const sourceExt of EMITTED_TO_SOURCE[ext] ?? []) {
+    candidates.push(base.slice(0, -ext.length) + sourceExt);
+  }
+  for (const sourceExt of SOURCE_EXTENSIONS) {
+    candidates.push(base + sourceExt);
+  }
+  for (const sourceExt of SOURCE_EXTENSIONS) {
+    candidates.push(posix.join(base, `index${sourceExt}`));
+  }
+
+  return candidates.find((candidate) => files.has(candidate)) ?? null;
+}
+
+/**
+ * Package name of a bare specifier (`@scope/pkg/sub` -> `@scope/pkg`).
+ */
+export function packageName(specifier: string): string {
+  const parts = specifier.split('/');
+  return specifier.startsWith('@') && parts.length > 1 ? `${parts[0]}/${parts[1]}` : parts[0]!;
+}
+
+// ============================================
+// Graph
+// ============================================
+
+/**
+ * Build a module graph from workspace-relative paths and their sources.
+ */
+export function buildTypeScriptGraph(
+  sources: ReadonlyMap<string, string>,
+  options: DependencyGraphOptions = {}
+): DependencyGraph {
+  const graph = createDependencyGraph('typescript');
+  const files = new Set(sources.keys());
+
+  for (const path of [...files].sort()) {
+    addDependencyNode(graph, { id: path, label: path, path });
+  }
+
+  for (const [path, source] of sources) {
+    for (const specifier of parseImportSpecifiers(source)) {
+      const resolved = resolveImport(path, specifier, files);
+      if (resolved) {
+        addDependency(graph, path, resolved);
+      } else if (options.includeExternal && !specifier.startsWith('.')) {
+        const name = packageName(specifier);
+        addDependencyNode(graph, { id: name, label: name, external: true });
+        addDependency(graph, path, name);
+      }
+    }
+  }
+
+  return graph;
+}
+
+/**
+ * Read the workspace's source files and build their module graph.
+ */
+export async function loadTypeScriptGraph(
+  workspaceRoot: string,
+  options: DependencyGraphOptions = {}
+): Promise<DependencyGraph> {
+  const glob = new Bun.Glob(`**/*{${SOURCE_EXTENSIONS.join(',')}}`);
+  const sources = new Map<string, string>();
+
+  for await (const path of glob.scan({ cwd: workspaceRoot, onlyFiles: true })) {
+    if (path.endsWith('.d.ts') || path.split('/').some((part) => IGNORED_DIRECTORIES.has(part))) continue;
+    sources.set(path, await Bun.file(posix.join(workspaceRoot, path)).text());
+    if (sources.size >= MAX_FILES) break;
+  }
+
+  return buildTypeScriptGraph(sources, options);
+}
@@ -5,7 +5,7 @@
  * and cursor state. Handles file I/O operations.
  */
 
-import { Buffer, type Position, type Range } from './buffer.ts';
+import { Buffer, transformPosition, type BufferChange, type BufferSnapshot, type Position, type Range } from './buffer.ts';
 import { CursorManager, type Cursor, type Selection, clonePosition } from './cursor.ts';
 import { UndoManager, type EditOperation } from './undo.ts';
 import { 
@@ -116,8 +116,8 @@ export class Document {
         this._lineEnding = 'crlf';
       }
       
-      // Reset buffer with new content
-      this._buffer = new Buffer(normalized);
+      // Reset buffer with new content (keeps the version increasing)
+      this._buffer.reset(normalized);
       this._savedContent = normalized;
       this._isDirty = false;
       
@@ -187,6 +187,22 @@ export class Document {
     return this._buffer.getLine(lineNumber);
   }
 
+  /**
+   * Get an immutable O(1) view of the current content.
+   * Background work can read it while the document keeps changing.
+   */
+  snapshot(): BufferSnapshot {
+    return this._buffer.snapshot();
+  }
+
+  /**
+   * Get the changes made since a version, for remapping positions computed
+   * on an older snapshot. Null if the version is outside the change history.
+   */
+  getChangesSince(version: number): BufferChange[] | null {
+    return this._buffer.getChangesSince(version);
+  }
+
   getLineLength(lineNumber: number): number {
     return this._buffer.getLineLength(lineNumber);
   }
@@ -540,6 +556,53 @@ export class Document {
       });
   }
 
+  /**
+   * Replace several ranges as a single undo step (e.g. replace-all from
+   * project search). Ranges refer to the content before any of the edits
+   * and must not overlap. Cursors follow the edited text.
+   */
+  applyEdits(edits: Array<{ range: Range; text: string }>): void {
+    if (edits.length === 0) return;
+
+    const cursorsBefore = this._cursorManager.getSnapshot();
+    const versionBefore = this._buffer.version;
+
+    // Apply from the end so earlier ranges stay valid
+    const sorted = [...edits].sort(
+      (a, b) => this._buffer.positionToOffset(b.range.start) - this._buffer.positionToOffset(a.range.start)
+    );
+
+    const operations: EditOperation[] = [];
+    for (const { range, text } of sorted) {
+      const deleted = this._buffer.deleteRange(range.start, range.end);
+      if (deleted) {
+        operations.push({ type: 'delete', position: clonePosition(range.start), text: deleted });
+      }
+      if (text) {
+        this._buffer.insertAt(range.start, text);
+        operations.push({ type: 'insert', position: clonePosition(range.start), text });
+      }
+    }
+
+    const changes = this._buffer.getChangesSince(versionBefore) ?? [];
+    for (const cursor of this.cursors) {
+      cursor.position = transformPosition(cursor.position, changes);
+      cursor.desiredColumn = cursor.position.column;
+      cursor.selection = null;
+    }
+
+    // Never merge with typing before or after
+    this._undoManager.breakUndoGroup();
+    this._undoManager.push({
+      operations,
+      cursorsBefore,
+      cursorsAfter: this._cursorManager.getSnapshot(),
+    });
+    this._undoManager.breakUndoGroup();
+
+    this.markDirty();
+  }
+
   // Undo/Redo
 
   undo(): void {
@@ -0,0 +1,630 @@
+/**
+ * Emmet Abbreviations
+ *
+ * Expands Emmet abbreviations (`ul>li.item$*3`, `m10-20`) into HTML, JSX
+ * or CSS. The syntax comes from the language and the scope at the cursor:
+ * CSS inside `<style>` blocks, nothing inside strings, comments or tags.
+ *
+ * Supported: child `>`, sibling `+`, climb-up `^`, groups `()`, `#id`,
+ * `.class`, `[attr=value]`, `{text}`, `*N` with `$` numbering, implicit
+ * tag names, `!` (HTML5 document) and common CSS property abbreviations.
+ */
+
+export type EmmetSyntax = 'html' | 'jsx' | 'css';
+
+/**
+ * Result of expanding an abbreviation.
+ */
+export interface EmmetExpansion {
+  text: string;
+  /** Cursor offset in `text`: the first empty attribute or element */
+  cursor: number;
+}
+
+// ============================================
+// Scope Detection
+// ============================================
+
+const HTML_LANGUAGES = new Set(['html', 'vue', 'svelte', 'php', 'astro']);
+const JSX_LANGUAGES = new Set(['javascriptreact', 'typescriptreact']);
+const CSS_LANGUAGES = new Set(['css', 'scss', 'less', 'sass']);
+
+/**
+ * Get the Emmet syntax at an offset, or null where abbreviations shouldn't
+ * expand (strings, comments, inside a tag, `<script>` blocks, CSS outside a
+ * rule block, unsupported languages).
+ */
+export function getEmmetSyntax(languageId: string, text: string, offset: number): EmmetSyntax | null {
+  const before = text.slice(0, offset);
+
+  if (HTML_LANGUAGES.has(languageId)) {
+    if (isInside(before, '<!--', '-->')) return null;
+
+    const style = findUnclosed(before, /<style\b[^>]*>/gi, /<\/style\s*>/gi);
+    if (style !== -1) {
+      return isInCssBlock(before.slice(style)) ? 'css' : null;
+    }
+    if (findUnclosed(before, /<script\b[^>]*>/gi, /<\/script\s*>/gi) !== -1) return null;
+
+    // Inside a tag's attributes
+    return before.lastIndexOf('<') > before.lastIndexOf('>') ? null : 'html';
+  }
+
+  if (JSX_LANGUAGES.has(languageId)) {
+    return scanCode(before).inCode ? 'jsx' : null;
+  }
+
+  if (CSS_LANGUAGES.has(languageId)) {
+    return isInCssBlock(before) ? 'css' : null;
+  }
+
+  return null;
+}
+
+/**
+ * Whether the text ends inside an open `open ... close` section.
+ */
+function isInside(text: string, open: string, close: string): boolean {
+  return text.lastIndexOf(open) > text.lastIndexOf(close);
+}
+
+/**
+ * End offset of the last `open` match not followed by a `close` match, or -1.
+ */
+function findUnclosed(text: string, open: RegExp, close: RegExp): number {
+  let lastOpen = -1;
+  for (const match of text.matchAll(open)) lastOpen = match.index! + match[0].length;
+  if (lastOpen === -1) return -1;
+
+  let lastClose = -1;
+  for (const match of text.matchAll(close)) lastClose = match.index!;
+  return lastClose >= lastOpen ? -1 : lastOpen;
+}
+
+/**
+ * Scan code for string/comment state and brace depth at its end.
+ * Single and double quoted strings end at a newline.
+ */
+function scanCode(text: string): { inCode: boolean; depth: number } {
+  let depth = 0;
+  let state: 'code' | 'line' | 'block' | '"' | "'" | '`' = 'code';
+
+  for (let i = 0; i < text.length; i++) {
+    const ch = text[i]!;
+    const next = text[i + 1];
+
+    switch (state) {
+      case 'code':
+        if (ch === '/' && next === '/') state = 'line';
+        else if (ch === '/' && next === '*') { state = 'block'; i++; }
+        else if (ch === '"' || ch === "'" || ch === '`') state = ch;
+        else if (ch === '{') depth++;
+        else if (ch === '}') depth = Math.max(0, depth - 1);
+        break;
+      case 'line':
+        if (ch === '\n') state = 'code';
+        break;
+      case 'block':
+        if (ch === '*' && next === '/') { state = 'code'; i++; }
+        break;
+      default:
+        if (ch === '\\') i++;
+        else if (ch === state || (ch === '\n' && state !== '`')) state = 'code';
+    }
+  }
+
+  return { inCode: state === 'code', depth };
+}
+
+function isInCssBlock(text: string): boolean {
+  const { inCode, depth } = scanCode(text);
+  return inCode && depth > 0;
+}
+
+// ============================================
+// Abbreviation Extraction
+// ============================================
+
+/** Characters allowed in an abbreviation outside brackets */
+const ABBREVIATION_CHAR = /[\w\-:$@!#.*+>^()%]/;
+
+/**
+ * Extract the abbreviation ending at the end of `lineBefore` (the line up
+ * to the cursor). Returns its start column, or null if there is none.
+ */
+export function extractAbbreviation(
+  lineBefore: string,
+  syntax: EmmetSyntax
+): { abbreviation: string; start: number } | null {
+  let start = lineBefore.length;
+  let depth = 0;
+
+  while (start > 0) {
+    const ch = lineBefore[start - 1]!;
+    if (ch === ']' || ch === '}') {
+      depth++;
+    } else if (ch === '[' || ch === '{') {
+      if (depth === 0) break;
+      depth--;
+    } else if (depth === 0 && !ABBREVIATION_CHAR.test(ch)) {
+      break;
+    }
+    start--;
+  }
+  if (depth !== 0) return null;
+
+  let abbreviation = lineBefore.slice(start);
+
+  // Skip a tag the abbreviation follows directly (`<div>ul>li`)
+  if (lineBefore[start - 1] === '<' || lineBefore[start - 1] === '/') {
+    const tagEnd = abbreviation.indexOf('>');
+    if (tagEnd === -1) return null;
+    abbreviation = abbreviation.slice(tagEnd + 1);
+    start += tagEnd + 1;
+  }
+  while (abbreviation.startsWith('>')) {
+    abbreviation = abbreviation.slice(1);
+    start++;
+  }
+
+  // Drop unmatched opening parentheses (e.g. `return (div`)
+  while (abbreviation.startsWith('(') && !hasBalancedParens(abbreviation)) {
+    abbreviation = abbreviation.slice(1);
+    start++;
+  }
+  if (!abbreviation) return null;
+
+  const preceding = lineBefore.slice(0, start).trimEnd();
+  if (syntax === 'css') {
+    // Only at the start of a declaration
+    if (preceding && !/[{;]$/.test(preceding)) return null;
+    if (!/^[a-z]/.test(abbreviation)) return null;
+  } else if (!/^[A-Za-z.#([!]/.test(abbreviation)) {
+    return null;
+  }
+
+  return { abbreviation, start };
+}
+
+function hasBalancedParens(text: string): boolean {
+  let depth = 0;
+  for (const ch of text) {
+    if (ch === '(') depth++;
+    else if (ch === ')' && --depth < 0) return false;
+  }
+  return depth === 0;
+}
+
+// ============================================
+// Expansion
+// ============================================
+
+/**
+ * Expand an abbreviation. `indent` is one level of indentation. Returns
+ * null if the abbreviation isn't valid or looks like ordinary text.
+ */
+export function expandAbbreviation(
+  abbreviation: string,
+  syntax: EmmetSyntax,
+  indent = '  '
+): EmmetExpansion | null {
+  const text = syntax === 'css'
+    ? expandCss(abbreviation)
+    : expandMarkup(abbreviation, syntax === 'jsx', indent);
+  if (text === null) return null;
+
+  const cursor = text.indexOf(STOP);
+  return {
+    text: text.split(STOP).join(''),
+    cursor: cursor === -1 ? text.length : cursor,
+  };
+}
+
+/** Placeholder for a tab stop while rendering */
+const STOP = '\u0000';
+
+// ─────────────────────────────────────────────────────────────────────────
+// Markup
+// ─────────────────────────────────────────────────────────────────────────
+
+interface EmmetNode {
+  name: string;
+  id?: string;
+  classes: string[];
+  attributes: Array<{ name: string; value: string | null }>;
+  text?: string;
+  repeat: number;
+  children: EmmetNode[];
+  /** A parenthesised group: only its children are rendered */
+  group: boolean;
+}
+
+const KNOWN_TAGS = new Set([
+  'a', 'abbr', 'address', 'area', 'article', 'aside', 'audio', 'b', 'blockquote', 'body', 'br',
+  'button', 'canvas', 'caption', 'code', 'col', 'colgroup', 'dd', 'del', 'details', 'dialog',
+  'div', 'dl', 'dt', 'em', 'embed', 'fieldset', 'figcaption', 'figure', 'footer', 'form',
+  'h1', 'h2', 'h3', 'h4', 'h5', 'h6', 'head', 'header', 'hr', 'html', 'i', 'iframe', 'img',
+  'input', 'ins', 'kbd', 'label', 'legend', 'li', 'link', 'main', 'mark', 'meta', 'nav',
+  'noscript', 'ol', 'optgroup', 'option', 'output', 'p', 'picture', 'pre', 'progress', 'q',
+  's', 'script', 'section', 'select', 'small', 'source', 'span', 'strong', 'style', 'sub',
+  'summary', 'sup', 'svg', 'table', 'tbody', 'td', 'template', 'textarea', 'tfoot', 'th',
+  'thead', 'time', 'title', 'tr', 'u', 'ul', 'video',
+]);
+
+const VOID_TAGS = new Set([
+  'area', 'base', 'br', 'col', 'embed', 'hr', 'img', 'input', 'link', 'meta', 'source', 'track', 'wbr',
+]);
+
+/** Attributes added when not given */
+const DEFAULT_ATTRIBUTES: Record<string, Array<{ name: string; value: string | null }>> = {
+  a: [{ name: 'href', value: null }],
+  img: [{ name: 'src', value: null }, { name: 'alt', value: null }],
+  input: [{ name: 'type', value: 'text' }],
+  link: [{ name: 'rel', value: 'stylesheet' }, { name: 'href', value: null }],
+  label: [{ name: 'for', value: null }],
+  form: [{ name: 'action', value: null }],
+  iframe: [{ name: 'src', value: null }],
+};
+
+/** Implicit child tag names by parent */
+const IMPLICIT_CHILD: Record<string, string> = {
+  ul: 'li', ol: 'li', table: 'tr', tbody: 'tr', thead: 'tr', tfoot: 'tr', tr: 'td', select: 'option',
+};
+
+const JSX_ATTRIBUTES: Record<string, string> = { class: 'className', for: 'htmlFor' };
+
+const HTML5_DOCUMENT = [
+  '<!DOCTYPE html>',
+  '<html lang="en">',
+  '<head>',
+  '\t<meta charset="UTF-8">',
+  '\t<meta name="viewport" content="width=device-width, initial-scale=1.0">',
+  '\t<title>Document</title>',
+  '</head>',
+  '<body>',
+  `\t${STOP}`,
+  '</body>',
+  '</html>',
+];
+
+function expandMarkup(abbreviation: string, jsx: boolean, indent: string): string | null {
+  if (abbreviation === '!') {
+    return jsx ? null : HTML5_DOCUMENT.map(line => line.replace(/\t/g, indent)).join('\n');
+  }
+
+  const parser = new MarkupParser(abbreviation);
+  const nodes = parser.parse();
+  if (!nodes) return null;
+
+  // Names must look like tags, so ordinary code (`console.log`) doesn't
+  // expand; a lone word must be an HTML tag or custom element
+  if (!nodes.every(isTagLike)) return null;
+  if (/^[A-Za-z][\w-]*$/.test(abbreviation) && !isHtmlTag(abbreviation)) return null;
+
+  const lines: string[] = [];
+  for (const node of nodes) {
+    renderNode(node, '', 1, 0, jsx, indent, lines);
+  }
+  return lines.join('\n');
+}
+
+function isHtmlTag(name: string): boolean {
+  return KNOWN_TAGS.has(name.toLowerCase()) || name.includes('-');
+}
+
+/**
+ * Whether a node and its descendants have tag-like names: HTML tags,
+ * custom elements, components (`Button`) or numbered names (`h$`).
+ */
+function isTagLike(node: EmmetNode): boolean {
+  const name = node.name;
+  const valid = !name || isHtmlTag(name) || /^[A-Z]/.test(name) || name.includes('$');
+  return valid && node.children.every(isTagLike);
+}
+
+/**
+ * Recursive-descent parser for markup abbreviations.
+ */
+class MarkupParser {
+  private pos = 0;
+
+  constructor(private readonly input: string) {}
+
+  parse(): EmmetNode[] | null {
+    const nodes = this.parseSequence();
+    return nodes && this.pos === this.input.length ? nodes : null;
+  }
+
+  /**
+   * Parse elements joined by `>`, `+` and `^` until `)` or the end.
+   */
+  private parseSequence(): EmmetNode[] | null {
+    const root: EmmetNode[] = [];
+    const parents: EmmetNode[][] = [root];
+    let last: EmmetNode | null = null;
+
+    while (this.pos < this.input.length && this.peek() !== ')') {
+      const node = this.parseElement();
+      if (!node) return null;
+      parents[parents.length - 1]!.push(node);
+      last = node;
+
+      const op = this.peek();
+      if (op === '>') {
+        if (last.group) return null;
+        parents.push(last.children);
+        this.pos++;
+      } else if (op === '+') {
+        this.pos++;
+      } else if (op === '^') {
+        while (this.peek() === '^') {
+          if (parents.length > 1) parents.pop();
+          this.pos++;
+        }
+      } else if (op !== undefined && op !== ')') {
+        return null;
+      }
+    }
+
+    return root.length > 0 ? root : null;
+  }
+
+  private parseElement(): EmmetNode | null {
+    const node: EmmetNode = { name: '', classes: [], attributes: [], repeat: 1, children: [], group: false };
+
+    if (this.peek() === '(') {
+      this.pos++;
+      const children = this.parseSequence();
+      if (!children || this.peek() !== ')') return null;
+      this.pos++;
+      node.group = true;
+      node.children = children;
+    } else {
+      node.name = this.readWhile(/[\w\-:$@]/);
+    }
+
+    for (;;) {
+      const ch = this.peek();
+      if (ch === '#' && !node.group) {
+        this.pos++;
+        node.id = this.readWhile(/[\w\-:$@]/);
+        if (!node.id) return null;
+      } else if (ch === '.' && !node.group) {
+        this.pos++;
+        const name = this.readWhile(/[\w\-:$@]/);
+        if (!name) return null;
+        node.classes.push(name);
+      } else if (ch === '[' && !node.group) {
+        const body = this.readBracketed('[', ']');
+        if (body === null) return null;
+        node.attributes.push(...parseAttributes(body));
+      } else if (ch === '{' && !node.group) {
+        const text = this.readBracketed('{', '}');
+        if (text === null) return null;
+        node.text = text;
+      } else if (ch === '*') {
+        this.pos++;
+        const count = this.readWhile(/\d/);
+        node.repeat = count ? Math.min(parseInt(count, 10), 1000) : 1;
+      } else {
+        break;
+      }
+    }
+
+    const empty = !node.group && !node.name && !node.id && node.classes.length === 0
+      && node.attributes.length === 0 && node.text === undefined;
+    return empty ? null : node;
+  }
+
+  private peek(): string | undefined {
+    return this.input[this.pos];
+  }
+
+  private readWhile(pattern: RegExp): string {
+    const start = this.pos;
+    while (this.pos < this.input.length && pattern.test(this.input[this.pos]!)) this.pos++;
+    return this.input.slice(start, this.pos);
+  }
+
+  /**
+   * Read a bracketed section (nesting allowed), returning its body.
+   */
+  private readBracketed(open: string, close: string): string | null {
+    let depth = 0;
+    for (let i = this.pos; i < this.input.length; i++) {
+      if (this.input[i] === open) depth++;
+      else if (this.input[i] === close && --depth === 0) {
+        const body = this.input.slice(this.pos + 1, i);
+        this.pos = i + 1;
+        return body;
+      }
+    }
+    return null;
+  }
+}
+
+/**
+ * Parse `name=value name2="value 2" flag` attribute lists.
+ */
+function parseAttributes(body: string): Array<{ name: string; value: string | null }> {
+  const attributes: Array<{ name: string; value: string | null }> = [];
+  const pattern = /([^\s=]+)(?:=(?:"([^"]*)"|'([^']*)'|(\S*)))?/g;
+  for (const match of body.matchAll(pattern)) {
+    const value = match[2] ?? match[3] ?? match[4];
+    attributes.push({ name: match[1]!, value: value || null });
+  }
+  return attributes;
+}
+
+/**
+ * Render a node (repeated as needed) into lines.
+ */
+function renderNode(
+  node: EmmetNode,
+  parentName: string,
+  inheritedIndex: number,
+  depth: number,
+  jsx: boolean,
+  indent: string,
+  lines: string[]
+): void {
+  for (let i = 1; i <= node.repeat; i++) {
+    const index = node.repeat > 1 ? i : inheritedIndex;
+
+    if (node.group) {
+      for (const child of node.children) {
+        renderNode(child, parentName, index, depth, jsx, indent, lines);
+      }
+      continue;
+    }
+
+    const name = number(node.name, index) || IMPLICIT_CHILD[parentName] || 'div';
+    const pad = indent.repeat(depth);
+    const open = `<${name}${renderAttributes(node, name, index, jsx)}`;
+
+    if (VOID_TAGS.has(name.toLowerCase()) && node.children.length === 0 && node.text === undefined) {
+      lines.push(`${pad}${open}${jsx ? ' />' : '>'}`);
+    } else if (node.children.length === 0) {
+      const text = node.text !== undefined ? number(node.text, index) : STOP;
+      lines.push(`${pad}${open}>${text}</${name}>`);
+    } else {
+      lines.push(`${pad}${open}>${node.text !== undefined ? number(node.text, index) : ''}`);
+      for (const child of node.children) {
+        renderNode(child, name, index, depth + 1, jsx, indent, lines);
+      }
+      lines.push(`${pad}</${name}>`);
+    }
+  }
+}
+
+function renderAttributes(node: EmmetNode, name: string, index: number, jsx: boolean): string {
+  const attributes: Array<{ name: string; value: string | null }> = [];
+  if (node.id) attributes.push({ name: 'id', value: node.id });
+  if (node.classes.length > 0) attributes.push({ name: 'class', value: node.classes.join(' ') });
+  attributes.push(...node.attributes);
+
+  for (const attr of DEFAULT_ATTRIBUTES[name.toLowerCase()] ?? []) {
+    if (!attributes.some(a => a.name === attr.name)) attributes.push(attr);
+  }
+
+  return attributes
+    .map(attr => {
+      const attrName = jsx ? JSX_ATTRIBUTES[attr.name] ?? attr.name : attr.name;
+      const value = attr.value === null ? STOP : number(attr.value, index);
+      return ` ${attrName}="${value}"`;
+    })
+    .join('');
+}
+
+/**
+ * Replace `$` runs with the (zero-padded) repeat index.
+ */
+function number(text: string, index: number): string {
+  return text.replace(/\$+/g, run => String(index).padStart(run.length, '0'));
+}
+
+// ─────────────────────────────────────────────────────────────────────────
+// CSS
+// ─────────────────────────────────────────────────────────────────────────
+
+const CSS_PROPERTIES: Record<string, string> = {
+  m: 'margin', mt: 'margin-top', mr: 'margin-right', mb: 'margin-bottom', ml: 'margin-left',
+  p: 'padding', pt: 'padding-top', pr: 'padding-right', pb: 'padding-bottom', pl: 'padding-left',
+  w: 'width', h: 'height', maw: 'max-width', mah: 'max-height', miw: 'min-width', mih: 'min-height',
+  t: 'top', r: 'right', b: 'bottom', l: 'left', z: 'z-index',
+  d: 'display', pos: 'position', fl: 'float', ov: 'overflow', v: 'visibility', cur: 'cursor',
+  c: 'color', bg: 'background', bgc: 'background-color', bgi: 'background-image',
+  bd: 'border', bdt: 'border-top', bdb: 'border-bottom', br: 'border-radius', bxsh: 'box-shadow',
+  op: 'opacity', o: 'outline',
+  ff: 'font-family', fz: 'font-size', fw: 'font-weight', fs: 'font-style', lh: 'line-height',
+  ta: 'text-align', td: 'text-decoration', tt: 'text-transform', ws: 'white-space', lts: 'letter-spacing',
+  fx: 'flex', fxd: 'flex-direction', fxw: 'flex-wrap', fxg: 'flex-grow', fxs: 'flex-shrink',
+  jc: 'justify-content', ai: 'align-items', ac: 'align-content', as: 'align-self',
+  g: 'gap', gtc: 'grid-template-columns', gtr: 'grid-template-rows',
+  trf: 'transform', trs: 'transition', anim: 'animation', con: 'content', bxz: 'box-sizing',
+};
+
+/** Whole-declaration abbreviations */
+const CSS_KEYWORDS: Record<string, string> = {
+  db: 'display: block', dib: 'display: inline-block', di: 'display: inline', dn: 'display: none',
+  df: 'display: flex', dif: 'display: inline-flex', dg: 'display: grid',
+  posa: 'position: absolute', posr: 'position: relative', posf: 'position: fixed', poss: 'position: static',
+  tac: 'text-align: center', tal: 'text-align: left', tar: 'text-align: right',
+  jcc: 'justify-content: center', jcsb: 'justify-content: space-between', aic: 'align-items: center',
+  fxdc: 'flex-direction: column', fxdr: 'flex-direction: row', fww: 'flex-wrap: wrap',
+  fwb: 'font-weight: bold', fsi: 'font-style: italic', tdn: 'text-decoration: none',
+  ovh: 'overflow: hidden', ova: 'overflow: auto', curp: 'cursor: pointer', bxzbb: 'box-sizing: border-box',
+};
+
+/** Keyword values after `:` that depend on the property */
+const CSS_PROPERTY_VALUE_ALIASES: Record<string, string> = {
+  'position:a': 'absolute', 'position:r': 'relative', 'position:f': 'fixed', 'position:s': 'static',
+};
+
+/** Keyword values after `:` (e.g. `ta:c`, `d:f`) */
+const CSS_VALUE_ALIASES: Record<string, string> = {
+  a: 'auto', c: 'center', l: 'left', r: 'right', n: 'none', b: 'block', f: 'flex', i: 'inline',
+  ib: 'inline-block', g: 'grid', h: 'hidden', s: 'solid', bb: 'border-box', p: 'pointer',
+};
+
+/** Properties whose bare numbers have no unit */
+const UNITLESS = new Set(['z-index', 'opacity', 'font-weight', 'line-height', 'flex-grow', 'flex-shrink', 'flex']);
+
+const UNIT_ALIASES: Record<string, string> = { p: '%', e: 'em', r: 'rem', x: 'ex' };
+
+function expandCss(abbreviation: string): string | null {
+  const keyword = CSS_KEYWORDS[abbreviation];
+  if (keyword) return `${keyword};`;
+
+  const colon = abbreviation.indexOf(':');
+  if (colon !== -1) {
+    const property = CSS_PROPERTIES[abbreviation.slice(0, colon)];
+    const alias = abbreviation.slice(colon + 1);
+    if (!property || !alias) return null;
+    const value = CSS_PROPERTY_VALUE_ALIASES[`${property}:${alias}`] ?? CSS_VALUE_ALIASES[alias] ?? alias;
+    return `${property}: ${value};`;
+  }
+
+  // Longest property abbreviation followed by a value
+  const match = /^([a-z]+?)(-?\d.*|#.*)?$/.exec(abbreviation);
+  if (!match) return null;
+  const property = CSS_PROPERTIES[match[1]!];
+  if (!property) return null;
+
+  const rawValue = match[2];
+  if (!rawValue) return `${property}: ${STOP};`;
+
+  const value = rawValue.startsWith('#')
+    ? expandCssColor(rawValue)
+    : expandCssNumbers(rawValue, property);
+  return value === null ? null : `${property}: ${value};`;
+}
+
+function expandCssColor(value: string): string | null {
+  const hex = value.slice(1);
+  if (!/^[0-9a-fA-F]{1,6}$/.test(hex)) return null;
+  return hex.length <= 2 ? `#${hex.repeat(3)}` : `#${hex}`;
+}
+
+/**
+ * Expand `10-20p` style values: numbers separated by `-`, with unit aliases.
+ */
+function expandCssNumbers(value: string, property: string): string | null {
+  // A `-` separates values; `--` (or a leading `-`) makes the next one negative
+  const pattern = /(-?\d*\.?\d+)([a-z%]*)(?:-|$)/y;
+  const values: string[] = [];
+
+  while (pattern.lastIndex < value.length) {
+    const match = pattern.exec(value);
+    if (!match) return null;
+    const number = match[1]!;
+    const unit = match[2]!;
+    if (unit) {
+      values.push(number + (UNIT_ALIASES[unit] ?? unit));
+    } else {
+      values.push(number === '0' || UNITLESS.has(property) ? number : `${number}px`);
+    }
+  }
+
+  return values.length > 0 ? values.join(' ') : null;
+}
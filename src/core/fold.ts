@@ -2,25 +2,109 @@
  * Code Folding
  * 
  * Handles detection of foldable regions and fold state management.
- * Supports indentation-based folding and bracket-based folding.
+ * Supports indentation-based folding and bracket-based folding, plus
+ * language-aware region markers (`// #region`, `<!-- #region -->`, ...),
+ * comment blocks, import blocks and manual regions created from a selection.
  */
 
+/**
+ * What a fold region contains, when known.
+ */
+export type FoldRegionKind = 'comment' | 'imports' | 'region' | 'manual';
+
 export interface FoldRegion {
   startLine: number;
   endLine: number;
   indent: number;
   isFolded: boolean;
+  kind?: FoldRegionKind;
+}
+
+/**
+ * Persisted fold state: the start line of a folded region, or a folded
+ * manual region (which can't be recomputed from content).
+ */
+export type FoldState = number | { startLine: number; endLine: number };
+
+/**
+ * Start/end patterns for `#region` style markers.
+ */
+interface RegionMarkers {
+  start: RegExp;
+  end: RegExp;
 }
 
+const SLASH_MARKERS: RegionMarkers = { start: /^\s*\/\/\s*#?region\b/, end: /^\s*\/\/\s*#?endregion\b/ };
+const HASH_MARKERS: RegionMarkers = { start: /^\s*#\s*region\b/, end: /^\s*#\s*endregion\b/ };
+const HTML_MARKERS: RegionMarkers = { start: /^\s*<!--\s*#?region\b/, end: /^\s*<!--\s*#?endregion\b/ };
+const BLOCK_MARKERS: RegionMarkers = { start: /^\s*\/\*\s*#?region\b/, end: /^\s*\/\*\s*#?endregion\b/ };
+const PRAGMA_MARKERS: RegionMarkers = { start: /^\s*#pragma\s+region\b/, end: /^\s*#pragma\s+endregion\b/ };
+const JSX_MARKERS: RegionMarkers = { start: /^\s*\{\s*\/\*\s*#?region\b/, end: /^\s*\{\s*\/\*\s*#?endregion\b/ };
+
+/**
+ * Comment and import syntax used for comment/import folding.
+ */
+interface LanguageFolding {
+  markers: RegionMarkers[];
+  lineComment: string[];
+  imports: RegExp | null;
+}
+
+const C_LIKE: LanguageFolding = { markers: [SLASH_MARKERS, BLOCK_MARKERS], lineComment: ['//'], imports: /^import\b/ };
+const JS_LIKE: LanguageFolding = { markers: [SLASH_MARKERS, BLOCK_MARKERS], lineComment: ['//'], imports: /^import\b/ };
+const JSX_LIKE: LanguageFolding = { ...JS_LIKE, markers: [SLASH_MARKERS, BLOCK_MARKERS, JSX_MARKERS] };
+const HASH_LIKE: LanguageFolding = { markers: [HASH_MARKERS], lineComment: ['#'], imports: null };
+const MARKUP: LanguageFolding = { markers: [HTML_MARKERS], lineComment: [], imports: null };
+
+const LANGUAGE_FOLDING: Record<string, LanguageFolding> = {
+  typescript: JS_LIKE,
+  javascript: JS_LIKE,
+  typescriptreact: JSX_LIKE,
+  javascriptreact: JSX_LIKE,
+  go: { ...C_LIKE, markers: [SLASH_MARKERS] },
+  rust: { ...C_LIKE, imports: /^(pub\s+)?use\s/ },
+  java: C_LIKE,
+  kotlin: C_LIKE,
+  csharp: { markers: [HASH_MARKERS, SLASH_MARKERS], lineComment: ['//'], imports: /^using\s/ },
+  c: { markers: [PRAGMA_MARKERS, SLASH_MARKERS], lineComment: ['//'], imports: /^#\s*include\b/ },
+  cpp: { markers: [PRAGMA_MARKERS, SLASH_MARKERS], lineComment: ['//'], imports: /^#\s*include\b/ },
+  jsonc: { markers: [SLASH_MARKERS], lineComment: ['//'], imports: null },
+  css: { markers: [BLOCK_MARKERS], lineComment: [], imports: /^@import\b/ },
+  scss: { markers: [BLOCK_MARKERS, SLASH_MARKERS], lineComment: ['//'], imports: /^@(import|use|forward)\b/ },
+  less: { markers: [BLOCK_MARKERS, SLASH_MARKERS], lineComment: ['//'], imports: /^@import\b/ },
+  python: { ...HASH_LIKE, imports: /^(import\s|from\s+\S+\s+import\b)/ },
+  ruby: { ...HASH_LIKE, imports: /^require(_relative)?\b/ },
+  shellscript: HASH_LIKE,
+  bash: HASH_LIKE,
+  yaml: HASH_LIKE,
+  toml: HASH_LIKE,
+  html: MARKUP,
+  xml: MARKUP,
+  markdown: MARKUP,
+};
+
+/** Used when the language is unknown */
+const DEFAULT_FOLDING: LanguageFolding = {
+  markers: [SLASH_MARKERS, HASH_MARKERS, HTML_MARKERS, BLOCK_MARKERS],
+  lineComment: ['//', '#'],
+  imports: /^(import\b|from\s+\S+\s+import\b|use\s|using\s|#\s*include\b|require\b)/,
+};
+
+/** Go build constraint lines, grouped into one region */
+const GO_BUILD_CONSTRAINT = /^\/\/(go:build\b|\s*\+build\b)/;
+
 export class FoldManager {
   private regions: FoldRegion[] = [];
   private foldedLines: Set<number> = new Set();  // Lines that are hidden due to folding
   private foldStartLines: Set<number> = new Set();  // Lines that start a fold (for gutter icons)
+  private manualRegions: Array<{ startLine: number; endLine: number }> = [];
+  private lines: string[] = [];  // Content regions were last computed from
   
   /**
-   * Compute foldable regions from document content
+   * Compute foldable regions from document content.
+   * The language selects region marker, comment and import syntax.
    */
-  computeRegions(lines: string[]): void {
+  computeRegions(lines: string[], languageId?: string): void {
     this.regions = [];
     this.foldStartLines.clear();
     
@@ -88,6 +172,9 @@ export class FoldManager {
         this.foldStartLines.add(start.line);
       }
     }
+
+    this.addLanguageRegions(lines, languageId);
+    this.addManualRegions(lines);
     
     // Sort regions by start line
     this.regions.sort((a, b) => a.startLine - b.startLine);
@@ -96,6 +183,208 @@ export class FoldManager {
     this.recomputeFoldedLines();
   }
   
+  /**
+   * Use fold regions supplied by a language server instead of computing them.
+   * Ranges past the end of the document are clamped; regions that still
+   * start on a previously folded line stay folded.
+   */
+  setRegions(ranges: Array<{ startLine: number; endLine: number; kind?: string }>, lines: string[]): void {
+    const previouslyFolded = new Set(this.getFoldedLines());
+    const lastLine = lines.length - 1;
+    const seen = new Set<number>();
+
+    this.regions = [];
+    this.foldStartLines.clear();
+
+    for (const range of ranges) {
+      const startLine = range.startLine;
+      const endLine = Math.min(range.endLine, lastLine);
+      // One region per start line (the outermost), and only if it hides something
+      if (startLine < 0 || endLine <= startLine) continue;
+      if (seen.has(startLine)) {
+        const existing = this.regions.find(r => r.startLine === startLine)!;
+        existing.endLine = Math.max(existing.endLine, endLine);
+        continue;
+      }
+      seen.add(startLine);
+
+      const text = lines[startLine] ?? '';
+      this.regions.push({
+        startLine,
+        endLine,
+        indent: text.length - text.trimStart().length,
+        isFolded: previouslyFolded.has(startLine),
+        kind: isFoldRegionKind(range.kind) ? range.kind : undefined,
+      });
+      this.foldStartLines.add(startLine);
+    }
+
+    this.addManualRegions(lines);
+    this.regions.sort((a, b) => a.startLine - b.startLine);
+    this.recomputeFoldedLines();
+  }
+
+  /**
+   * Add region-marker, build-constraint, comment and import regions.
+   */
+  private addLanguageRegions(lines: string[], languageId?: string): void {
+    const folding = (languageId && LANGUAGE_FOLDING[languageId]) || DEFAULT_FOLDING;
+
+    // #region / #endregion markers (may nest)
+    const markerStack: number[] = [];
+    for (let i = 0; i < lines.length; i++) {
+      const line = lines[i]!;
+      if (folding.markers.some(m => m.start.test(line))) {
+        markerStack.push(i);
+      } else if (folding.markers.some(m => m.end.test(line))) {
+        const start = markerStack.pop();
+        if (start !== undefined) this.addRegion(start, i, lines, 'region');
+      }
+    }
+
+    const isMarker = (line: string): boolean =>
+      folding.markers.some(m => m.start.test(line) || m.end.test(line));
+    const isBuildConstraint = (line: string): boolean =>
+      (languageId === 'go' || !languageId) && GO_BUILD_CONSTRAINT.test(line.trimStart());
+
+    // Go build constraint blocks (//go:build plus legacy // +build lines)
+    for (let i = 0; i < lines.length; i++) {
+      if (!isBuildConstraint(lines[i]!)) continue;
+      let end = i;
+      while (end + 1 < lines.length && isBuildConstraint(lines[end + 1]!)) end++;
+      if (end > i) this.addRegion(i, end, lines, 'region');
+      i = end;
+    }
+
+    // Comment blocks: runs of line comments and multi-line block comments
+    const isLineComment = (line: string): boolean => {
+      const trimmed = line.trimStart();
+      return folding.lineComment.some(prefix => trimmed.startsWith(prefix))
+        && !isMarker(line) && !isBuildConstraint(line);
+    };
+    for (let i = 0; i < lines.length; i++) {
+      const trimmed = lines[i]!.trimStart();
+
+      if (isLineComment(lines[i]!)) {
+        let end = i;
+        while (end + 1 < lines.length && isLineComment(lines[end + 1]!)) end++;
+        if (end > i) this.addRegion(i, end, lines, 'comment');
+        i = end;
+        continue;
+      }
+
+      const block = trimmed.startsWith('/*') ? '*/' : trimmed.startsWith('<!--') ? '-->' : null;
+      if (block && !isMarker(lines[i]!) && !trimmed.includes(block, 2)) {
+        let end = i + 1;
+        while (end < lines.length && !lines[end]!.includes(block)) end++;
+        if (end < lines.length) {
+          this.addRegion(i, end, lines, 'comment');
+          i = end;
+        }
+      }
+    }
+
+    // The import block at the top of the file
+    if (folding.imports) {
+      const start = lines.findIndex(line => folding.imports!.test(line));
+      if (start !== -1) {
+        let last = start;
+        for (let i = start + 1; i < lines.length; i++) {
+          const line = lines[i]!;
+          const trimmed = line.trim();
+          if (trimmed === '' || isLineComment(line)) continue;
+          // Import statements and continuation lines of multi-line imports
+          if (folding.imports.test(line) || /^\s/.test(line) || /^[})\]]/.test(trimmed)) {
+            last = i;
+            continue;
+          }
+          break;
+        }
+        if (last > start) this.addRegion(start, last, lines, 'imports');
+      }
+    }
+  }
+
+  /**
+   * Add manual regions (clamped to the document) over computed ones,
+   * after moving them along with lines inserted or removed since the
+   * last computation.
+   */
+  private addManualRegions(lines: string[]): void {
+    this.shiftManualRegions(this.lines, lines);
+    this.lines = lines;
+
+    const lastLine = lines.length - 1;
+    this.manualRegions = this.manualRegions.filter(m => m.startLine < lastLine);
+
+    for (const manual of this.manualRegions) {
+      const endLine = Math.min(manual.endLine, lastLine);
+      const existing = this.regions.find(r => r.startLine === manual.startLine);
+      if (existing) {
+        existing.endLine = endLine;
+        existing.kind = 'manual';
+      } else {
+        this.regions.push({ startLine: manual.startLine, endLine, indent: 0, isFolded: false, kind: 'manual' });
+        this.foldStartLines.add(manual.startLine);
+      }
+    }
+  }
+
+  /**
+   * Move manual regions from old content line numbers to new ones. Lines
+   * outside the changed span (between the common prefix and suffix) keep
+   * their content, so regions there shift by the line count difference.
+   */
+  private shiftManualRegions(oldLines: string[], newLines: string[]): void {
+    if (this.manualRegions.length === 0 || oldLines.length === 0) return;
+
+    let prefix = 0;
+    const maxPrefix = Math.min(oldLines.length, newLines.length);
+    while (prefix < maxPrefix && oldLines[prefix] === newLines[prefix]) prefix++;
+    let suffix = 0;
+    const maxSuffix = maxPrefix - prefix;
+    while (suffix < maxSuffix && oldLines[oldLines.length - 1 - suffix] === newLines[newLines.length - 1 - suffix]) suffix++;
+
+    const oldEnd = oldLines.length - suffix;
+    const newEnd = newLines.length - suffix;
+    const delta = newEnd - oldEnd;
+    if (delta === 0) return;
+
+    const map = (line: number): number => {
+      if (line < prefix) return line;
+      if (line >= oldEnd) return line + delta;
+      // Inside the changed span: stay within what replaced it
+      return Math.max(prefix, Math.min(line, newEnd - 1));
+    };
+    this.manualRegions = this.manualRegions
+      .map(m => ({ startLine: map(m.startLine), endLine: map(m.endLine) }))
+      .filter(m => m.endLine > m.startLine);
+  }
+
+  /**
+   * Add a region, merging with any region that starts on the same line.
+   */
+  private addRegion(startLine: number, endLine: number, lines: string[], kind: FoldRegionKind): void {
+    if (endLine <= startLine) return;
+
+    const existing = this.regions.find(r => r.startLine === startLine);
+    if (existing) {
+      existing.endLine = Math.max(existing.endLine, endLine);
+      existing.kind ??= kind;
+      return;
+    }
+
+    const text = lines[startLine] ?? '';
+    this.regions.push({
+      startLine,
+      endLine,
+      indent: text.length - text.trimStart().length,
+      isFolded: false,
+      kind,
+    });
+    this.foldStartLines.add(startLine);
+  }
+
   private findNextNonEmptyLine(lines: string[], start: number): number {
     for (let i = start; i < lines.length; i++) {
       if (lines[i]!.trim().length > 0) {
@@ -196,6 +485,118 @@ export class FoldManager {
     this.recomputeFoldedLines();
   }
   
+  /**
+   * Get all regions, sorted by start line.
+   */
+  getRegions(): readonly FoldRegion[] {
+    return this.regions;
+  }
+
+  /**
+   * Nesting level of each region (1 = outermost).
+   */
+  getRegionLevels(): Map<FoldRegion, number> {
+    const levels = new Map<FoldRegion, number>();
+    const open: FoldRegion[] = [];
+
+    for (const region of this.regions) {
+      while (open.length > 0 && open[open.length - 1]!.endLine < region.startLine) {
+        open.pop();
+      }
+      levels.set(region, open.length + 1);
+      open.push(region);
+    }
+
+    return levels;
+  }
+
+  /**
+   * Fold every region at `level`, unfolding shallower ones so the first
+   * `level` levels stay visible. Regions containing any of `exceptLines`
+   * (e.g. cursor lines) are left unfolded.
+   */
+  foldToLevel(level: number, exceptLines: number[] = []): void {
+    for (const [region, regionLevel] of this.getRegionLevels()) {
+      if (regionLevel < level || this.containsAny(region, exceptLines)) {
+        region.isFolded = false;
+      } else if (regionLevel === level) {
+        region.isFolded = true;
+      }
+    }
+    this.recomputeFoldedLines();
+  }
+
+  /**
+   * Fold all regions of a kind (e.g. all comments). Returns how many were folded.
+   */
+  foldKind(kind: FoldRegionKind, exceptLines: number[] = []): number {
+    let count = 0;
+    for (const region of this.regions) {
+      if (region.kind === kind && !region.isFolded && !this.containsAny(region, exceptLines)) {
+        region.isFolded = true;
+        count++;
+      }
+    }
+    this.recomputeFoldedLines();
+    return count;
+  }
+
+  /**
+   * Fold every region except those containing any of the given lines,
+   * which are unfolded.
+   */
+  foldAllExcept(lines: number[]): void {
+    for (const region of this.regions) {
+      region.isFolded = !this.containsAny(region, lines);
+    }
+    this.recomputeFoldedLines();
+  }
+
+  /**
+   * Create a folded manual region (e.g. from a selection).
+   * Manual regions survive recomputation until removed.
+   */
+  addManualRegion(startLine: number, endLine: number): boolean {
+    if (startLine < 0 || endLine <= startLine) return false;
+
+    this.manualRegions = this.manualRegions.filter(m => m.startLine !== startLine);
+    this.manualRegions.push({ startLine, endLine });
+
+    const existing = this.regions.find(r => r.startLine === startLine);
+    if (existing) {
+      existing.endLine = endLine;
+      existing.kind = 'manual';
+      existing.isFolded = true;
+    } else {
+      this.regions.push({ startLine, endLine, indent: 0, isFolded: true, kind: 'manual' });
+      this.regions.sort((a, b) => a.startLine - b.startLine);
+      this.foldStartLines.add(startLine);
+    }
+    this.recomputeFoldedLines();
+    return true;
+  }
+
+  /**
+   * Remove manual regions containing any of the given lines (all if omitted).
+   * Returns how many were removed.
+   */
+  removeManualRegions(lines?: number[]): number {
+    const isRemoved = (r: { startLine: number; endLine: number }): boolean =>
+      !lines || lines.some(l => l >= r.startLine && l <= r.endLine);
+
+    const before = this.manualRegions.length;
+    this.manualRegions = this.manualRegions.filter(m => !isRemoved(m));
+
+    this.regions = this.regions.filter(r => r.kind !== 'manual' || !isRemoved(r));
+    this.foldStartLines = new Set(this.regions.map(r => r.startLine));
+    this.recomputeFoldedLines();
+    return before - this.manualRegions.length;
+  }
+
+  private containsAny(region: FoldRegion, lines: number[]): boolean {
+    return lines.some(l => l >= region.startLine && l <= region.endLine);
+  }
+
   /**
    * Find the fold region containing a given line (for folding the block cursor is in)
    */
@@ -243,6 +644,30 @@ export class FoldManager {
       .map(r => r.startLine);
   }
   
+  /**
+   * Get fold state for serialization: folded start lines, with folded
+   * manual regions saved as ranges so they can be recreated.
+   */
+  getFoldState(): FoldState[] {
+    return this.regions
+      .filter(r => r.isFolded)
+      .map(r => r.kind === 'manual' ? { startLine: r.startLine, endLine: r.endLine } : r.startLine);
+  }
+
+  /**
+   * Restore state from getFoldState() (plain line numbers from older
+   * sessions also work).
+   */
+  restoreFoldState(state: FoldState[]): void {
+    for (const entry of state) {
+      if (typeof entry === 'number') {
+        this.fold(entry);
+      } else {
+        this.addManualRegion(entry.startLine, entry.endLine);
+      }
+    }
+  }
+  
   /**
    * Recompute which lines are hidden due to folding
    */
@@ -323,7 +748,13 @@ export class FoldManager {
    */
   clear(): void {
     this.regions = [];
+    this.manualRegions = [];
+    this.lines = [];
     this.foldedLines.clear();
     this.foldStartLines.clear();
   }
 }
+
+function isFoldRegionKind(kind: string | undefined): kind is FoldRegionKind {
+  return kind === 'comment' || kind === 'imports' || kind === 'region';
+}
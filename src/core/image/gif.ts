@@ -0,0 +1,346 @@
+/**
+ * GIF Decoder
+ *
+ * Decodes the first frame of a GIF (87a/89a) onto the logical screen:
+ * global and local color tables, LZW image data, interlacing and the
+ * transparent color from the graphic control extension. The remaining
+ * frames are only counted, so animated GIFs show their first frame.
+ */
+
+import { ImageDecodeError, checkDimensions, type DecodedImage } from './types.ts';
+
+// ============================================
+// Constants
+// ============================================
+
+const EXTENSION_INTRODUCER = 0x21;
+const IMAGE_SEPARATOR = 0x2c;
+const TRAILER = 0x3b;
+const GRAPHIC_CONTROL_LABEL = 0xf9;
+
+/** Largest LZW code (codes are at most 12 bits) */
+const MAX_CODES = 4096;
+
+/**
+ * Check the GIF signature.
+ */
+export function isGif(bytes: Uint8Array): boolean {
+  return (
+    bytes.length >= 6 &&
+    bytes[0] === 0x47 &&
+    bytes[1] === 0x49 &&
+    bytes[2] === 0x46 &&
+    bytes[3] === 0x38 &&
+    (bytes[4] === 0x37 || bytes[4] === 0x39) &&
+    bytes[5] === 0x61
+  );
+}
+
+// ============================================
+// Decoder
+// ============================================
+
+interface Frame {
+  left: number;
+  top: number;
+  width: number;
+  height: number;
+  interlaced: boolean;
+  colorTable: Uint8Array;
+  transparentIndex: number;
+  indices: Uint8Array;
+}
+
+/**
+ * Decode the first frame of a GIF file.
+ */
+export function decodeGif(bytes: Uint8Array): DecodedImage {
+  if (!isGif(bytes)) {
+    throw new ImageDecodeError('missing signature', 'gif');
+  }
+  if (bytes.length < 13) {
+    throw new ImageDecodeError('truncated header', 'gif');
+  }
+
+  const reader = new ByteReader(bytes, 6);
+  const width = reader.uint16();
+  const height = reader.uint16();
+  const packed = reader.byte();
+  reader.byte(); // background color index (transparent pixels are left transparent)
+  reader.byte(); // pixel aspect ratio
+  checkDimensions(width, height, 'gif');
+
+  const bitDepth = (packed & 0x07) + 1;
+  const globalTable = packed & 0x80 ? reader.bytes(3 * (1 << bitDepth)) : null;
+
+  let firstFrame: Frame | null = null;
+  let frameCount = 0;
+  let transparentIndex = -1;
+
+  while (!reader.done()) {
+    const block = reader.byte();
+    if (block === TRAILER) break;
+
+    if (block === EXTENSION_INTRODUCER) {
+      const label = reader.byte();
+      if (label === GRAPHIC_CONTROL_LABEL) {
+        const size = reader.byte();
+        const control = reader.bytes(size);
+        transparentIndex = control[0]! & 0x01 ? control[3]! : -1;
+        reader.skipSubBlocks();
+      } else {
+        reader.skipSubBlocks();
+      }
+      continue;
+    }
+
+    if (block !== IMAGE_SEPARATOR) {
+      throw new ImageDecodeError(`unexpected block 0x${block.toString(16)}`, 'gif');
+    }
+
+    frameCount++;
+    const left = reader.uint16();
+    const top = reader.uint16();
+    const frameWidth = reader.uint16();
+    const frameHeight = reader.uint16();
+    const framePacked = reader.byte();
+    const localTable = framePacked & 0x80 ? reader.bytes(3 * (1 << ((framePacked & 0x07) + 1))) : null;
+    const minCodeSize = reader.byte();
+
+    if (firstFrame) {
+      reader.skipSubBlocks();
+      continue;
+    }
+
+    const colorTable = localTable ?? globalTable;
+    if (!colorTable) {
+      throw new ImageDecodeError('frame has no color table', 'gif');
+    }
+    firstFrame = {
+      left,
+      top,
+      width: frameWidth,
+      height: frameHeight,
+      interlaced: (framePacked & 0x40) !== 0,
+      colorTable,
+      transparentIndex,
+      indices: decodeLzw(reader.subBlocks(), minCodeSize, frameWidth * frameHeight),
+    };
+  }
+
+  if (!firstFrame) {
+    throw new ImageDecodeError('no image data', 'gif');
+  }
+
+  return {
+    width,
+    height,
+    data: compose(firstFrame, width, height),
+    metadata: {
+      format: 'gif',
+      width,
+      height,
+      bitDepth,
+      colorType: 'Indexed',
+      hasAlpha: firstFrame.transparentIndex >= 0 || !frameCovers(firstFrame, width, height),
+      interlaced: firstFrame.interlaced,
+      frameCount,
+    },
+  };
+}
+
+/**
+ * Paint a frame onto a transparent logical screen.
+ */
+function compose(frame: Frame, width: number, height: number): Uint8ClampedArray {
+  const data = new Uint8ClampedArray(width * height * 4);
+  const rows = frame.interlaced ? interlacedRowOrder(frame.height) : null;
+
+  for (let i = 0; i < frame.height; i++) {
+    const y = frame.top + (rows ? rows[i]! : i);
+    if (y >= height) continue;
+    for (let fx = 0; fx < frame.width; fx++) {
+      const x = frame.left + fx;
+      if (x >= width) continue;
+      const index = frame.indices[i * frame.width + fx]!;
+      if (index === frame.transparentIndex) continue;
+      const c = index * 3;
+      const t = (y * width + x) * 4;
+      data[t] = frame.colorTable[c] ?? 0;
+      data[t + 1] = frame.colorTable[c + 1] ?? 0;
+      data[t + 2] = frame.colorTable[c + 2] ?? 0;
+      data[t + 3] = 255;
+    }
+  }
+  return data;
+}
+
+function frameCovers(frame: Frame, width: number, height: number): boolean {
+  return frame.left === 0 && frame.top === 0 && frame.width >= width && frame.height >= height;
+}
+
+/**
+ * Image row for each stored row of an interlaced frame
+ * (rows 0,8,16.. then 4,12.. then 2,6.. then 1,3..).
+ */
+function interlacedRowOrder(height: number): number[] {
+  const order: number[] = [];
+  for (const [start, step] of [[0, 8], [4, 8], [2, 4], [1, 2]] as const) {
+    for (let y = start; y < height; y += step) {
+      order.push(y);
+    }
+  }
+  return order;
+}
+
+// ============================================
+// LZW
+// ============================================
+
+/**
+ * Decode GIF LZW data into `pixelCount` color indices. Missing data is
+ * left as index 0 rather than failing, as browsers do.
+ */
+export function decodeLzw(data: Uint8Array, minCodeSize: number, pixelCount: number): Uint8Array {
+  if (minCodeSize < 2 || minCodeSize > 11) {
+    throw new ImageDecodeError(`invalid LZW code size ${minCodeSize}`, 'gif');
+  }
+
+  const output = new Uint8Array(pixelCount);
+  const clearCode = 1 << minCodeSize;
+  const endCode = clearCode + 1;
+
+  // Dictionary as prefix links: each code is (prefix code, last byte)
+  const prefix = new Int16Array(MAX_CODES);
+  const suffix = new Uint8Array(MAX_CODES);
+  const firstByte = new Uint8Array(MAX_CODES);
+  const lengths = new Uint16Array(MAX_CODES);
+  for (let i = 0; i < clearCode; i++) {
+    prefix[i] = -1;
+    suffix[i] = i;
+    firstByte[i] = i;
+    lengths[i] = 1;
+  }
+
+  let codeSize = minCodeSize + 1;
+  let nextCode = endCode + 1;
+  let previous = -1;
+  let out = 0;
+  let bitBuffer = 0;
+  let bitCount = 0;
+  let position = 0;
+
+  while (out < pixelCount) {
+    while (bitCount < codeSize && position < data.length) {
+      bitBuffer |= data[position++]! << bitCount;
+      bitCount += 8;
+    }
+    if (bitCount < codeSize) break;
+
+    const code = bitBuffer & ((1 << codeSize) - 1);
+    bitBuffer >>>= codeSize;
+    bitCount -= codeSize;
+
+    if (code === clearCode) {
+      codeSize = minCodeSize + 1;
+      nextCode = endCode + 1;
+      previous = -1;
+      continue;
+    }
+    if (code === endCode) break;
+
+    let entry: number;
+    if (code < nextCode) {
+      entry = code;
+    } else if (code === nextCode && previous !== -1 && nextCode < MAX_CODES) {
+      // KwKwK case: the code being defined is previous + first byte of previous
+      entry = -1;
+    } else {
+      throw new ImageDecodeError(`invalid LZW code ${code}`, 'gif');
+    }
+
+    if (previous !== -1 && nextCode < MAX_CODES) {
+      prefix[nextCode] = previous;
+      suffix[nextCode] = entry === -1 ? firstByte[previous]! : firstByte[entry]!;
+      firstByte[nextCode] = firstByte[previous]!;
+      lengths[nextCode] = lengths[previous]! + 1;
+      if (entry === -1) entry = nextCode;
+      nextCode++;
+      if (nextCode === 1 << codeSize && codeSize < 12) {
+        codeSize++;
+      }
+    }
+
+    // Write the string for `entry` back to front
+    const length = lengths[entry]!;
+    let pos = out + length - 1;
+    for (let c = entry; c !== -1; c = prefix[c]!) {
+      if (pos < pixelCount) output[pos] = suffix[c]!;
+      pos--;
+    }
+    out += length;
+    previous = entry;
+  }
+
+  return output;
+}
+
+// ============================================
+// Byte Reader
+// ============================================
+
+class ByteReader {
+  constructor(
+    private data: Uint8Array,
+    private offset: number
+  ) {}
+
+  done(): boolean {
+    return this.offset >= this.data.length;
+  }
+
+  byte(): number {
+    if (this.offset >= this.data.length) {
+      throw new ImageDecodeError('unexpected end of file', 'gif');
+    }
+    return this.data[this.offset++]!;
+  }
+
+  uint16(): number {
+    const low = this.byte();
+    return low | (this.byte() << 8);
+  }
+
+  bytes(length: number): Uint8Array {
+    if (this.offset + length > this.data.length) {
+      throw new ImageDecodeError('unexpected end of file', 'gif');
+    }
+    const result = this.data.subarray(this.offset, this.offset + length);
+    this.offset += length;
+    return result;
+  }
+
+  /** Concatenate data sub-blocks up to the zero-length terminator */
+  subBlocks(): Uint8Array {
+    const parts: Uint8Array[] = [];
+    let total = 0;
+    for (let size = this.byte(); size > 0; size = this.done() ? 0 : this.byte()) {
+      const part = this.bytes(Math.min(size, this.data.length - this.offset));
+      parts.push(part);
+      total += part.length;
+    }
+    const result = new Uint8Array(total);
+    let offset = 0;
+    for (const part of parts) {
+      result.set(part, offset);
+      offset += part.length;
+    }
+    return result;
+  }
+
+  skipSubBlocks(): void {
+    for (let size = this.byte(); size > 0; size = this.byte()) {
+      this.bytes(size);
+    }
+  }
+}
@@ -0,0 +1,177 @@
+/**
+ * Image Decoding
+ *
+ * Format detection, decoding and resampling for the image viewer. All
+ * decoding is done in-process (no external tools or native modules).
+ */
+
+import { extname } from 'path';
+import { decodePng, isPng } from './png.ts';
+import { decodeJpeg, isJpeg } from './jpeg.ts';
+import { decodeGif, isGif } from './gif.ts';
+import { decodeSvg, isSvg, type SvgRenderOptions } from './svg.ts';
+import { ImageDecodeError, type DecodedImage, type ImageFormat } from './types.ts';
+
+export { ImageDecodeError, MAX_IMAGE_PIXELS, type DecodedImage, type ImageFormat, type ImageMetadata } from './types.ts';
+export { decodePng } from './png.ts';
+export { decodeJpeg } from './jpeg.ts';
+export { decodeGif, decodeLzw } from './gif.ts';
+export { decodeSvg, parseXml, parseTransform, flattenPath, type SvgRenderOptions } from './svg.ts';
+
+// ============================================
+// Formats
+// ============================================
+
+/** File extensions opened in the image viewer */
+const IMAGE_EXTENSIONS: Record<string, ImageFormat> = {
+  '.png': 'png',
+  '.jpg': 'jpeg',
+  '.jpeg': 'jpeg',
+  '.jpe': 'jpeg',
+  '.jfif': 'jpeg',
+  '.gif': 'gif',
+  '.svg': 'svg',
+};
+
+/**
+ * Check whether a path or URI has an image extension.
+ */
+export function isImagePath(path: string): boolean {
+  return extname(path).toLowerCase() in IMAGE_EXTENSIONS;
+}
+
+/**
+ * Detect the format of image bytes from their signature.
+ */
+export function detectImageFormat(bytes: Uint8Array): ImageFormat | null {
+  if (isPng(bytes)) return 'png';
+  if (isJpeg(bytes)) return 'jpeg';
+  if (isGif(bytes)) return 'gif';
+  if (isSvg(bytes)) return 'svg';
+  return null;
+}
+
+/**
+ * Decode image bytes, detecting the format from the content (falling back
+ * to the file extension when a path is given).
+ */
+export function decodeImage(bytes: Uint8Array, path?: string, options: SvgRenderOptions = {}): DecodedImage {
+  const format = detectImageFormat(bytes) ?? (path ? IMAGE_EXTENSIONS[extname(path).toLowerCase()] : undefined);
+  switch (format) {
+    case 'png':
+      return decodePng(bytes);
+    case 'jpeg':
+      return decodeJpeg(bytes);
+    case 'gif':
+      return decodeGif(bytes);
+    case 'svg':
+      return decodeSvg(bytes, options);
+    default:
+      throw new ImageDecodeError('unrecognized image format');
+  }
+}
+
+// ============================================
+// Resampling
+// ============================================
+
+/**
+ * An RGBA pixel buffer (a decoded or resampled image).
+ */
+export interface PixelBuffer {
+  width: number;
+  height: number;
+  data: Uint8ClampedArray;
+}
+
+/**
+ * Resample an image to the given size. Downscaling averages the source
+ * pixels under each target pixel (alpha-weighted, so transparent pixels
+ * don't darken edges); upscaling uses nearest neighbour to keep pixel art
+ * crisp.
+ */
+export function scaleImage(image: PixelBuffer, width: number, height: number): PixelBuffer {
+  width = Math.max(1, Math.round(width));
+  height = Math.max(1, Math.round(height));
+  if (width === image.width && height === image.height) {
+    return image;
+  }
+  return resampleRegion(image, width / image.width, height / image.height, 0, 0, width, height);
+}
+
+/**
+ * Resample part of an image: the `width` x `height` window at
+ * (`left`, `top`) of the image scaled by (`scaleX`, `scaleY`). Only the
+ * window is computed, so zoomed-in views of large images stay cheap.
+ * Pixels outside the scaled image are transparent.
+ */
+export function resampleRegion(
+  image: PixelBuffer,
+  scaleX: number,
+  scaleY: number,
+  left: number,
+  top: number,
+  width: number,
+  height: number
+): PixelBuffer {
+  const data = new Uint8ClampedArray(width * height * 4);
+
+  for (let y = 0; y < height; y++) {
+    const y0 = Math.floor((top + y) / scaleY);
+    if (y0 < 0 || y0 >= image.height) continue;
+    const y1 = Math.max(y0 + 1, Math.min(image.height, Math.floor((top + y + 1) / scaleY)));
+
+    for (let x = 0; x < width; x++) {
+      const x0 = Math.floor((left + x) / scaleX);
+      if (x0 < 0 || x0 >= image.width) continue;
+      const x1 = Math.max(x0 + 1, Math.min(image.width, Math.floor((left + x + 1) / scaleX)));
+
+      let r = 0;
+      let g = 0;
+      let b = 0;
+      let a = 0;
+      let count = 0;
+      for (let yy = y0; yy < y1; yy++) {
+        for (let xx = x0; xx < x1; xx++) {
+          const s = (yy * image.width + xx) * 4;
+          const alpha = image.data[s + 3]!;
+          r += image.data[s]! * alpha;
+          g += image.data[s + 1]! * alpha;
+          b += image.data[s + 2]! * alpha;
+          a += alpha;
+          count++;
+        }
+      }
+
+      const t = (y * width + x) * 4;
+      if (a > 0) {
+        data[t] = r / a;
+        data[t + 1] = g / a;
+        data[t + 2] = b / a;
+      }
+      data[t + 3] = a / count;
+    }
+  }
+
+  return { width, height, data };
+}
+
+/**
+ * Size that fits an image inside a box, preserving aspect ratio.
+ * Images smaller than the box are not enlarged unless `enlarge` is set.
+ */
+export function fitSize(
+  width: number,
+  height: number,
+  boxWidth: number,
+  boxHeight: number,
+  enlarge = false
+): { width: number; height: number; scale: number } {
+  let scale = Math.min(boxWidth / width, boxHeight / height);
+  if (!enlarge) scale = Math.min(scale, 1);
+  return {
+    width: Math.max(1, Math.round(width * scale)),
+    height: Math.max(1, Math.round(height * scale)),
+    scale,
+  };
+}
@@ -0,0 +1,781 @@
+/**
+ * JPEG Decoder
+ *
+ * Decodes baseline and progressive Huffman-coded JPEGs (SOF0, SOF1, SOF2)
+ * with 8-bit precision: any chroma subsampling, restart intervals,
+ * grayscale, YCbCr, RGB and Adobe CMYK/YCCK. Arithmetic coding, lossless
+ * and 12-bit JPEGs are rejected. Chroma is upsampled by pixel replication.
+ */
+
+import { ImageDecodeError, checkDimensions, type DecodedImage } from './types.ts';
+
+// ============================================
+// Constants
+// ============================================
+
+/** Zigzag index -> natural (row-major) index */
+const ZIGZAG = new Int32Array([
+  0, 1, 8, 16, 9, 2, 3, 10, 17, 24, 32, 25, 18, 11, 4, 5, 12, 19, 26, 33, 40, 48, 41, 34, 27, 20, 13, 6, 7, 14, 21,
+  28, 35, 42, 49, 56, 57, 50, 43, 36, 29, 22, 15, 23, 30, 37, 44, 51, 58, 59, 52, 45, 38, 31, 39, 46, 53, 60, 61,
+  54, 47, 55, 62, 63,
+]);
+
+/** IDCT basis: IDCT_TABLE[x * 8 + u] = C(u) / 2 * cos((2x + 1)uπ / 16) */
+const IDCT_TABLE = (() => {
+  const table = new Float64Array(64);
+  for (let x = 0; x < 8; x++) {
+    for (let u = 0; u < 8; u++) {
+      const cu = u === 0 ? Math.SQRT1_2 : 1;
+      table[x * 8 + u] = (cu / 2) * Math.cos(((2 * x + 1) * u * Math.PI) / 16);
+    }
+  }
+  return table;
+})();
+
+const MARKER_SOI = 0xffd8;
+const MARKER_EOI = 0xffd9;
+const MARKER_SOS = 0xffda;
+const MARKER_DQT = 0xffdb;
+const MARKER_DRI = 0xffdd;
+const MARKER_DHT = 0xffc4;
+const MARKER_APP14 = 0xffee;
+
+/**
+ * Check the JPEG signature (SOI followed by another marker).
+ */
+export function isJpeg(bytes: Uint8Array): boolean {
+  return bytes.length >= 3 && bytes[0] === 0xff && bytes[1] === 0xd8 && bytes[2] === 0xff;
+}
+
+// ============================================
+// Types
+// ============================================
+
+interface HuffmanTable {
+  /** Largest code of each length (index 1..16), -1 if none */
+  maxCode: Int32Array;
+  /** Smallest code of each length */
+  minCode: Int32Array;
+  /** Index into `values` of the first code of each length */
+  valuePointer: Int32Array;
+  values: Uint8Array;
+}
+
+interface Component {
+  id: number;
+  h: number;
+  v: number;
+  quantizationId: number;
+  blocksPerLine: number;
+  blocksPerColumn: number;
+  /** Blocks per line/column padded to whole MCUs */
+  blocksPerLineForMcu: number;
+  blocksPerColumnForMcu: number;
+  /** 64 coefficients per block, natural order */
+  coefficients: Int16Array;
+  dcTable?: HuffmanTable;
+  acTable?: HuffmanTable;
+  predictor: number;
+}
+
+interface Frame {
+  progressive: boolean;
+  width: number;
+  height: number;
+  maxH: number;
+  maxV: number;
+  mcusPerLine: number;
+  mcusPerColumn: number;
+  components: Component[];
+}
+
+// ============================================
+// Decoder
+// ============================================
+
+/**
+ * Decode a JPEG file.
+ */
+export function decodeJpeg(bytes: Uint8Array): DecodedImage {
+  if (!isJpeg(bytes)) {
+    throw new ImageDecodeError('missing SOI marker', 'jpeg');
+  }
+
+  const quantizationTables: Int32Array[] = [];
+  const dcTables: HuffmanTable[] = [];
+  const acTables: HuffmanTable[] = [];
+  let frame: Frame | null = null;
+  let restartInterval = 0;
+  let adobeTransform: number | null = null;
+  let sawJfif = false;
+
+  let offset = 2;
+  while (offset < bytes.length) {
+    // Skip fill bytes and anything that isn't a marker
+    if (bytes[offset] !== 0xff || offset + 1 >= bytes.length) {
+      offset++;
+      continue;
+    }
+    const marker = (0xff00 | bytes[offset + 1]!) >>> 0;
+    offset += 2;
+    if (marker === 0xffff || marker === 0xff00) {
+      offset--;
+      continue;
+    }
+    if (marker === MARKER_EOI) break;
+    if (marker === MARKER_SOI || (marker >= 0xffd0 && marker <= 0xffd7)) continue;
+
+    if (offset + 2 > bytes.length) {
+      throw new ImageDecodeError('truncated segment', 'jpeg');
+    }
+    const length = (bytes[offset]! << 8) | bytes[offset + 1]!;
+    const segment = bytes.subarray(offset + 2, offset + length);
+    if (offset + length > bytes.length) {
+      throw new ImageDecodeError('truncated segment', 'jpeg');
+    }
+
+    switch (marker) {
+      case MARKER_DQT:
+        readQuantizationTables(segment, quantizationTables);
+        break;
+      case MARKER_DHT:
+        readHuffmanTables(segment, dcTables, acTables);
+        break;
+      case MARKER_DRI:
+        restartInterval = (segment[0]! << 8) | segment[1]!;
+        break;
+      case 0xffe0:
+        sawJfif ||= segment[0] === 0x4a && segment[1] === 0x46 && segment[2] === 0x49 && segment[3] === 0x46;
+        break;
+      case MARKER_APP14:
+        // "Adobe" + version(2) + flags0(2) + flags1(2) + transform(1)
+        if (segment[0] === 0x41 && segment[1] === 0x64 && segment[2] === 0x6f && segment.length >= 12) {
+          adobeTransform = segment[11]!;
+        }
+        break;
+      case 0xffc0:
+      case 0xffc1:
+      case 0xffc2:
+        if (frame) {
+          throw new ImageDecodeError('multiple frames are not supported', 'jpeg');
+        }
+        frame = readFrame(segment, marker === 0xffc2);
+        break;
+      case 0xffc3:
+      case 0xffc5:
+      case 0xffc6:
+      case 0xffc7:
+      case 0xffc9:
+      case 0xffca:
+      case 0xffcb:
+      case 0xffcd:
+      case 0xffce:
+      case 0xffcf:
+        throw new ImageDecodeError('lossless, hierarchical and arithmetic-coded JPEGs are not supported', 'jpeg');
+      case MARKER_SOS: {
+        if (!frame) {
+          throw new ImageDecodeError('scan before frame header', 'jpeg');
+        }
+        const scan = readScanHeader(segment, frame, dcTables, acTables);
+        offset = decodeScan(bytes, offset + length, frame, scan, restartInterval);
+        continue;
+      }
+    }
+
+    offset += length;
+  }
+
+  if (!frame) {
+    throw new ImageDecodeError('missing frame header', 'jpeg');
+  }
+
+  for (const component of frame.components) {
+    const table = quantizationTables[component.quantizationId];
+    if (!table) {
+      throw new ImageDecodeError(`missing quantization table ${component.quantizationId}`, 'jpeg');
+    }
+    dequantizeAndTransform(component, table);
+  }
+
+  const colorType = describeColor(frame, adobeTransform, sawJfif);
+  return {
+    width: frame.width,
+    height: frame.height,
+    data: toRgba(frame, colorType),
+    metadata: {
+      format: 'jpeg',
+      width: frame.width,
+      height: frame.height,
+      bitDepth: 8,
+      colorType,
+      hasAlpha: false,
+      progressive: frame.progressive,
+    },
+  };
+}
+
+// ============================================
+// Segments
+// ============================================
+
+function readQuantizationTables(segment: Uint8Array, tables: Int32Array[]): void {
+  let p = 0;
+  while (p < segment.length) {
+    const precision = segment[p]! >> 4;
+    const id = segment[p]! & 0x0f;
+    p++;
+    const table = new Int32Array(64);
+    for (let k = 0; k < 64; k++) {
+      if (precision === 0) {
+        table[ZIGZAG[k]!] = segment[p++]!;
+      } else {
+        table[ZIGZAG[k]!] = (segment[p]! << 8) | segment[p + 1]!;
+        p += 2;
+      }
+    }
+    tables[id] = table;
+  }
+}
+
+function readHuffmanTables(segment: Uint8Array, dcTables: HuffmanTable[], acTables: HuffmanTable[]): void {
+  let p = 0;
+  while (p < segment.length) {
+    const tableClass = segment[p]! >> 4;
+    const id = segment[p]! & 0x0f;
+    p++;
+    const counts = segment.subarray(p, p + 16);
+    p += 16;
+    const total = counts.reduce((sum, n) => sum + n, 0);
+    const values = segment.slice(p, p + total);
+    p += total;
+    (tableClass === 0 ? dcTables : acTables)[id] = buildHuffmanTable(counts, values);
+  }
+}
+
+/**
+ * Build decoding tables from code length counts (JPEG spec F.2.2.3).
+ */
+function buildHuffmanTable(counts: Uint8Array, values: Uint8Array): HuffmanTable {
+  const maxCode = new Int32Array(18).fill(-1);
+  const minCode = new Int32Array(17);
+  const valuePointer = new Int32Array(17);
+  let code = 0;
+  let index = 0;
+  for (let length = 1; length <= 16; length++) {
+    const count = counts[length - 1]!;
+    if (count > 0) {
+      valuePointer[length] = index;
+      minCode[length] = code;
+      code += count;
+      index += count;
+      maxCode[length] = code - 1;
+    }
+    code <<= 1;
+  }
+  return { maxCode, minCode, valuePointer, values };
+}
+
+function readFrame(segment: Uint8Array, progressive: boolean): Frame {
+  const precision = segment[0]!;
+  if (precision !== 8) {
+    throw new ImageDecodeError(`${precision}-bit precision is not supported`, 'jpeg');
+  }
+  const height = (segment[1]! << 8) | segment[2]!;
+  const width = (segment[3]! << 8) | segment[4]!;
+  if (height === 0) {
+    throw new ImageDecodeError('images with DNL-defined height are not supported', 'jpeg');
+  }
+  checkDimensions(width, height, 'jpeg');
+
+  const count = segment[5]!;
+  const specs: Array<{ id: number; h: number; v: number; quantizationId: number }> = [];
+  for (let i = 0; i < count; i++) {
+    const p = 6 + i * 3;
+    specs.push({
+      id: segment[p]!,
+      h: Math.max(1, segment[p + 1]! >> 4),
+      v: Math.max(1, segment[p + 1]! & 0x0f),
+      quantizationId: segment[p + 2]!,
+    });
+  }
+
+  const maxH = Math.max(...specs.map((s) => s.h));
+  const maxV = Math.max(...specs.map((s) => s.v));
+  const mcusPerLine = Math.ceil(width / (8 * maxH));
+  const mcusPerColumn = Math.ceil(height / (8 * maxV));
+
+  const components = specs.map((spec): Component => {
+    const blocksPerLineForMcu = mcusPerLine * spec.h;
+    const blocksPerColumnForMcu = mcusPerColumn * spec.v;
+    return {
+      ...spec,
+      blocksPerLine: Math.ceil(Math.ceil((width * spec.h) / maxH) / 8),
+      blocksPerColumn: Math.ceil(Math.ceil((height * spec.v) / maxV) / 8),
+      blocksPerLineForMcu,
+      blocksPerColumnForMcu,
+      coefficients: new Int16Array(blocksPerLineForMcu * blocksPerColumnForMcu * 64),
+      predictor: 0,
+    };
+  });
+
+  return { progressive, width, height, maxH, maxV, mcusPerLine, mcusPerColumn, components };
+}
+
+interface ScanHeader {
+  components: Component[];
+  spectralStart: number;
+  spectralEnd: number;
+  successiveHigh: number;
+  successiveLow: number;
+}
+
+function readScanHeader(
+  segment: Uint8Array,
+  frame: Frame,
+  dcTables: HuffmanTable[],
+  acTables: HuffmanTable[]
+): ScanHeader {
+  const count = segment[0]!;
+  const components: Component[] = [];
+  for (let i = 0; i < count; i++) {
+    const id = segment[1 + i * 2]!;
+    const tables = segment[2 + i * 2]!;
+    const component = frame.components.find((c) => c.id === id);
+    if (!component) {
+      throw new ImageDecodeError(`scan references unknown component ${id}`, 'jpeg');
+    }
+    component.dcTable = dcTables[tables >> 4];
+    component.acTable = acTables[tables & 0x0f];
+    components.push(component);
+  }
+  const p = 1 + count * 2;
+  return {
+    components,
+    spectralStart: segment[p]!,
+    spectralEnd: segment[p + 1]!,
+    successiveHigh: segment[p + 2]! >> 4,
+    successiveLow: segment[p + 2]! & 0x0f,
+  };
+}
+
+// ============================================
+// Entropy Decoding
+// ============================================
+
+/**
+ * Decode one scan starting at `offset`. Returns the offset of the marker
+ * that follows the scan.
+ */
+function decodeScan(bytes: Uint8Array, offset: number, frame: Frame, scan: ScanHeader, restartInterval: number): number {
+  const { components, spectralStart, spectralEnd, successiveHigh, successiveLow } = scan;
+  const reader = new BitReader(bytes, offset);
+
+  const receiveAndExtend = (length: number): number => {
+    if (length === 0) return 0;
+    if (length === 1) return reader.bit() ? 1 : -1;
+    const value = reader.bits(length);
+    return value >= 1 << (length - 1) ? value : value - (1 << length) + 1;
+  };
+
+  const decodeHuffman = (table: HuffmanTable | undefined): number => {
+    if (!table) {
+      throw new ImageDecodeError('scan uses an undefined Huffman table', 'jpeg');
+    }
+    let code = 0;
+    for (let length = 1; length <= 16; length++) {
+      code = (code << 1) | reader.bit();
+      if (code <= table.maxCode[length]!) {
+        return table.values[table.valuePointer[length]! + code - table.minCode[length]!]!;
+      }
+    }
+    throw new ImageDecodeError('invalid Huffman code', 'jpeg');
+  };
+
+  let eobRun = 0;
+  let refineState = 0;
+  let refineValue = 0;
+
+  const decodeBaseline = (c: Component, offset: number): void => {
+    const coefficients = c.coefficients;
+    const t = decodeHuffman(c.dcTable);
+    c.predictor += t === 0 ? 0 : receiveAndExtend(t);
+    coefficients[offset] = c.predictor;
+    let k = 1;
+    while (k < 64) {
+      const rs = decodeHuffman(c.acTable);
+      const s = rs & 15;
+      const r = rs >> 4;
+      if (s === 0) {
+        if (r < 15) break;
+        k += 16;
+        continue;
+      }
+      k += r;
+      if (k > 63) break;
+      coefficients[offset + ZIGZAG[k]!] = receiveAndExtend(s);
+      k++;
+    }
+  };
+
+  const decodeDcFirst = (c: Component, offset: number): void => {
+    const t = decodeHuffman(c.dcTable);
+    c.predictor += t === 0 ? 0 : receiveAndExtend(t) * (1 << successiveLow);
+    c.coefficients[offset] = c.predictor;
+  };
+
+  const decodeDcRefine = (c: Component, offset: number): void => {
+    if (reader.bit()) {
+      c.coefficients[offset] = c.coefficients[offset]! | (1 << successiveLow);
+    }
+  };
+
+  const decodeAcFirst = (c: Component, offset: number): void => {
+    if (eobRun > 0) {
+      eobRun--;
+      return;
+    }
+    let k = spectralStart;
+    while (k <= spectralEnd) {
+      const rs = decodeHuffman(c.acTable);
+      const s = rs & 15;
+      const r = rs >> 4;
+      if (s === 0) {
+        if (r < 15) {
+          eobRun = reader.bits(r) + (1 << r) - 1;
+          break;
+        }
+        k += 16;
+        continue;
+      }
+      k += r;
+      if (k > 63) break;
+      c.coefficients[offset + ZIGZAG[k]!] = receiveAndExtend(s) * (1 << successiveLow);
+      k++;
+    }
+  };
+
+  // Refinement walks the band once, correcting nonzero coefficients and
+  // placing new ±1 values after runs of zeros (states: 0 read code,
+  // 1/2 skip zeros, 3 place value, 4 end of band).
+  const decodeAcRefine = (c: Component, offset: number): void => {
+    const coefficients = c.coefficients;
+    let k = spectralStart;
+    let r = 0;
+    while (k <= spectralEnd) {
+      const z = offset + ZIGZAG[k]!;
+      const sign = coefficients[z]! < 0 ? -1 : 1;
+      switch (refineState) {
+        case 0: {
+          const rs = decodeHuffman(c.acTable);
+          const s = rs & 15;
+          r = rs >> 4;
+          if (s === 0) {
+            if (r < 15) {
+              eobRun = reader.bits(r) + (1 << r);
+              refineState = 4;
+            } else {
+              r = 16;
+              refineState = 1;
+            }
+          } else {
+            if (s !== 1) {
+              throw new ImageDecodeError('invalid AC refinement code', 'jpeg');
+            }
+            refineValue = receiveAndExtend(s);
+            refineState = r ? 2 : 3;
+          }
+          continue;
+        }
+        case 1:
+        case 2:
+          if (coefficients[z]) {
+            coefficients[z] = coefficients[z]! + sign * (reader.bit() << successiveLow);
+          } else {
+            r--;
+            if (r === 0) refineState = refineState === 2 ? 3 : 0;
+          }
+          break;
+        case 3:
+          if (coefficients[z]) {
+            coefficients[z] = coefficients[z]! + sign * (reader.bit() << successiveLow);
+          } else {
+            coefficients[z] = refineValue * (1 << successiveLow);
+            refineState = 0;
+          }
+          break;
+        case 4:
+          if (coefficients[z]) {
+            coefficients[z] = coefficients[z]! + sign * (reader.bit() << successiveLow);
+          }
+          break;
+      }
+      k++;
+    }
+    if (refineState === 4) {
+      eobRun--;
+      if (eobRun === 0) refineState = 0;
+    }
+  };
+
+  let decodeBlock: (c: Component, offset: number) => void;
+  if (!frame.progressive) {
+    decodeBlock = decodeBaseline;
+  } else if (spectralStart === 0) {
+    decodeBlock = successiveHigh === 0 ? decodeDcFirst : decodeDcRefine;
+  } else {
+    decodeBlock = successiveHigh === 0 ? decodeAcFirst : decodeAcRefine;
+  }
+
+  const blockOffset = (c: Component, row: number, col: number): number => 64 * (row * c.blocksPerLineForMcu + col);
+
+  // A single-component scan covers the component's own blocks, not whole MCUs
+  const single = components.length === 1 ? components[0]! : null;
+  const totalUnits = single ? single.blocksPerLine * single.blocksPerColumn : frame.mcusPerLine * frame.mcusPerColumn;
+
+  let unit = 0;
+  while (unit < totalUnits) {
+    for (const c of components) c.predictor = 0;
+    eobRun = 0;
+    refineState = 0;
+
+    const end = restartInterval > 0 ? Math.min(unit + restartInterval, totalUnits) : totalUnits;
+    for (; unit < end; unit++) {
+      if (single) {
+        const row = Math.floor(unit / single.blocksPerLine);
+        const col = unit % single.blocksPerLine;
+        decodeBlock(single, blockOffset(single, row, col));
+        continue;
+      }
+      const mcuRow = Math.floor(unit / frame.mcusPerLine);
+      const mcuCol = unit % frame.mcusPerLine;
+      for (const c of components) {
+        for (let v = 0; v < c.v; v++) {
+          for (let h = 0; h < c.h; h++) {
+            decodeBlock(c, blockOffset(c, mcuRow * c.v + v, mcuCol * c.h + h));
+          }
+        }
+      }
+    }
+
+    if (reader.hitMarker() && unit < totalUnits) {
+      // Data ran out mid-interval; keep whatever was decoded
+      break;
+    }
+    if (unit < totalUnits && !reader.skipRestartMarker()) {
+      break;
+    }
+  }
+
+  return reader.nextMarkerOffset();
+}
+
+/**
+ * Reads entropy-coded bits, undoing 0xFF00 byte stuffing. Stops at the
+ * first marker and returns zero bits past it.
+ */
+class BitReader {
+  private buffer = 0;
+  private count = 0;
+  private markerReached = false;
+
+  constructor(
+    private data: Uint8Array,
+    private position: number
+  ) {}
+
+  bit(): number {
+    if (this.count === 0) {
+      this.buffer = this.nextByte();
+      this.count = 8;
+    }
+    this.count--;
+    return (this.buffer >> this.count) & 1;
+  }
+
+  bits(length: number): number {
+    let value = 0;
+    for (let i = 0; i < length; i++) {
+      value = (value << 1) | this.bit();
+    }
+    return value;
+  }
+
+  hitMarker(): boolean {
+    return this.markerReached;
+  }
+
+  /**
+   * Drop the partial byte and consume an RSTn marker if one follows.
+   */
+  skipRestartMarker(): boolean {
+    this.count = 0;
+    this.markerReached = false;
+    const p = this.nextMarkerOffset();
+    const marker = this.data[p + 1];
+    if (marker !== undefined && marker >= 0xd0 && marker <= 0xd7) {
+      this.position = p + 2;
+      return true;
+    }
+    this.position = p;
+    return false;
+  }
+
+  /**
+   * Offset of the next marker (other than stuffed bytes) at or after the
+   * read position.
+   */
+  nextMarkerOffset(): number {
+    let p = this.position;
+    while (p + 1 < this.data.length) {
+      if (this.data[p] === 0xff && this.data[p + 1] !== 0x00 && this.data[p + 1] !== 0xff) {
+        return p;
+      }
+      p++;
+    }
+    return this.data.length;
+  }
+
+  private nextByte(): number {
+    const byte = this.data[this.position];
+    if (byte === undefined) {
+      this.markerReached = true;
+      return 0;
+    }
+    if (byte === 0xff) {
+      const next = this.data[this.position + 1];
+      if (next === 0x00) {
+        this.position += 2;
+        return 0xff;
+      }
+      this.markerReached = true;
+      return 0;
+    }
+    this.position++;
+    return byte;
+  }
+}
+
+// ============================================
+// Reconstruction
+// ============================================
+
+/**
+ * Dequantize and inverse-DCT every block of a component in place; the
+ * coefficients array then holds 8x8 sample blocks (0..255).
+ */
+function dequantizeAndTransform(component: Component, table: Int32Array): void {
+  const block = new Float64Array(64);
+  const temp = new Float64Array(64);
+  const coefficients = component.coefficients;
+
+  for (let offset = 0; offset < coefficients.length; offset += 64) {
+    for (let i = 0; i < 64; i++) {
+      block[i] = coefficients[offset + i]! * table[i]!;
+    }
+
+    // Rows: temp[y][x] = sum_u block[y][u] * basis(x, u)
+    for (let y = 0; y < 8; y++) {
+      for (let x = 0; x < 8; x++) {
+        let sum = 0;
+        for (let u = 0; u < 8; u++) {
+          sum += block[y * 8 + u]! * IDCT_TABLE[x * 8 + u]!;
+        }
+        temp[y * 8 + x] = sum;
+      }
+    }
+    // Columns
+    for (let x = 0; x < 8; x++) {
+      for (let y = 0; y < 8; y++) {
+        let sum = 0;
+        for (let v = 0; v < 8; v++) {
+          sum += temp[v * 8 + x]! * IDCT_TABLE[y * 8 + v]!;
+        }
+        const sample = Math.round(sum + 128);
+        coefficients[offset + y * 8 + x] = sample < 0 ? 0 : sample > 255 ? 255 : sample;
+      }
+    }
+  }
+}
+
+function describeColor(frame: Frame, adobeTransform: number | null, sawJfif: boolean): string {
+  const count = frame.components.length;
+  if (count === 1) return 'Grayscale';
+  if (count === 4) return adobeTransform === 2 ? 'YCCK' : 'CMYK';
+  if (count !== 3) {
+    throw new ImageDecodeError(`${count} color components are not supported`, 'jpeg');
+  }
+  if (adobeTransform === 0) return 'RGB';
+  if (adobeTransform === null && !sawJfif) {
+    const ids = frame.components.map((c) => c.id);
+    if (ids[0] === 0x52 && ids[1] === 0x47 && ids[2] === 0x42) return 'RGB';
+  }
+  return 'YCbCr';
+}
+
+/**
+ * Upsample the component planes and convert to RGBA.
+ */
+function toRgba(frame: Frame, colorType: string): Uint8ClampedArray {
+  const { width, height, maxH, maxV, components } = frame;
+  const data = new Uint8ClampedArray(width * height * 4);
+  const samples = new Int32Array(components.length);
+
+  // Precompute, per component, the block offset and in-block index for each x
+  const columnOffsets = components.map((c) => {
+    const offsets = new Int32Array(width);
+    for (let x = 0; x < width; x++) {
+      const sx = Math.floor((x * c.h) / maxH);
+      offsets[x] = (sx >> 3) * 64 + (sx & 7);
+    }
+    return offsets;
+  });
+
+  for (let y = 0; y < height; y++) {
+    const rowOffsets = components.map((c) => {
+      const sy = Math.floor((y * c.v) / maxV);
+      return (sy >> 3) * c.blocksPerLineForMcu * 64 + (sy & 7) * 8;
+    });
+
+    for (let x = 0; x < width; x++) {
+      for (let i = 0; i < components.length; i++) {
+        samples[i] = components[i]!.coefficients[rowOffsets[i]! + columnOffsets[i]![x]!]!;
+      }
+
+      let r: number, g: number, b: number;
+      if (colorType === 'Grayscale') {
+        r = g = b = samples[0]!;
+      } else if (colorType === 'RGB') {
+        r = samples[0]!;
+        g = samples[1]!;
+        b = samples[2]!;
+      } else {
+        if (colorType === 'CMYK') {
+          r = samples[0]!;
+          g = samples[1]!;
+          b = samples[2]!;
+        } else {
+          [r, g, b] = ycbcrToRgb(samples[0]!, samples[1]!, samples[2]!);
+        }
+        if (colorType !== 'YCbCr') {
+          // Adobe CMYK is stored inverted: these are 255 - C/M/Y and 255 - K
+          const k = samples[3]! / 255;
+          r *= k;
+          g *= k;
+          b *= k;
+        }
+      }
+
+      const t = (y * width + x) * 4;
+      data[t] = r;
+      data[t + 1] = g;
+      data[t + 2] = b;
+      data[t + 3] = 255;
+    }
+  }
+
+  return data;
+}
+
+function ycbcrToRgb(y: number, cb: number, cr: number): [number, number, number] {
+  return [y + 1.402 * (cr - 128), y - 0.344136 * (cb - 128) - 0.714136 * (cr - 128), y + 1.772 * (cb - 128)];
+}
@@ -0,0 +1,408 @@
+/**
+ * PNG Decoder
+ *
+ * Decodes PNG files to RGBA: all color types and bit depths, palettes with
+ * tRNS transparency, the five scanline filters and Adam7 interlacing.
+ * Ancillary chunks other than tRNS (gamma, color profiles, text) are
+ * ignored. Only the first image of an APNG is decoded.
+ */
+
+import { inflateSync } from 'zlib';
+import { ImageDecodeError, checkDimensions, type DecodedImage } from './types.ts';
+
+// ============================================
+// Constants
+// ============================================
+
+const PNG_SIGNATURE = [0x89, 0x50, 0x4e, 0x47, 0x0d, 0x0a, 0x1a, 0x0a];
+
+/** Channels per color type */
+const CHANNELS: Record<number, number> = { 0: 1, 2: 3, 3: 1, 4: 2, 6: 4 };
+
+/** Valid bit depths per color type */
+const BIT_DEPTHS: Record<number, number[]> = {
+  0: [1, 2, 4, 8, 16],
+  2: [8, 16],
+  3: [1, 2, 4, 8],
+  4: [8, 16],
+  6: [8, 16],
+};
+
+const COLOR_TYPE_NAMES: Record<number, string> = {
+  0: 'Grayscale',
+  2: 'RGB',
+  3: 'Indexed',
+  4: 'Grayscale + Alpha',
+  6: 'RGBA',
+};
+
+/** Adam7 passes: [startX, startY, stepX, stepY] */
+const ADAM7: Array<[number, number, number, number]> = [
+  [0, 0, 8, 8],
+  [4, 0, 8, 8],
+  [0, 4, 4, 8],
+  [2, 0, 4, 4],
+  [0, 2, 2, 4],
+  [1, 0, 2, 2],
+  [0, 1, 1, 2],
+];
+
+// ============================================
+// Header
+// ============================================
+
+interface PngHeader {
+  width: number;
+  height: number;
+  bitDepth: number;
+  colorType: number;
+  interlaced: boolean;
+}
+
+/**
+ * Check the PNG signature.
+ */
+export function isPng(bytes: Uint8Array): boolean {
+  return bytes.length >= 8 && PNG_SIGNATURE.every((b, i) => bytes[i] === b);
+}
+
+function readUint32(bytes: Uint8Array, offset: number): number {
+  return ((bytes[offset]! << 24) | (bytes[offset + 1]! << 16) | (bytes[offset + 2]! << 8) | bytes[offset + 3]!) >>> 0;
+}
+
+// ============================================
+// Decoder
+// ============================================
+
+/**
+ * Decode a PNG file.
+ */
+export function decodePng(bytes: Uint8Array): DecodedImage {
+  if (!isPng(bytes)) {
+    throw new ImageDecodeError('missing signature', 'png');
+  }
+
+  let header: PngHeader | null = null;
+  let palette: Uint8Array | null = null;
+  let transparency: Uint8Array | null = null;
+  const idat: Uint8Array[] = [];
+
+  let offset = 8;
+  while (offset + 8 <= bytes.length) {
+    const length = readUint32(bytes, offset);
+    const type = String.fromCharCode(bytes[offset + 4]!, bytes[offset + 5]!, bytes[offset + 6]!, bytes[offset + 7]!);
+    const dataStart = offset + 8;
+    const dataEnd = dataStart + length;
+    if (dataEnd > bytes.length) {
+      throw new ImageDecodeError(`truncated ${type} chunk`, 'png');
+    }
+    const data = bytes.subarray(dataStart, dataEnd);
+
+    if (type === 'IHDR') {
+      header = parseHeader(data);
+    } else if (type === 'PLTE') {
+      palette = data;
+    } else if (type === 'tRNS') {
+      transparency = data;
+    } else if (type === 'IDAT') {
+      idat.push(data);
+    } else if (type === 'IEND') {
+      break;
+    }
+
+    // Skip the CRC
+    offset = dataEnd + 4;
+  }
+
+  if (!header) {
+    throw new ImageDecodeError('missing IHDR chunk', 'png');
+  }
+  if (idat.length === 0) {
+    throw new ImageDecodeError('missing IDAT chunk', 'png');
+  }
+  if (header.colorType === 3 && !palette) {
+    throw new ImageDecodeError('indexed image without PLTE chunk', 'png');
+  }
+
+  // Stop inflating at the size the header allows, so a small file can't
+  // expand into gigabytes
+  let raw: Uint8Array;
+  try {
+    raw = new Uint8Array(inflateSync(concat(idat), { maxOutputLength: dataLength(header) }));
+  } catch (error) {
+    if ((error as NodeJS.ErrnoException).code === 'ERR_BUFFER_TOO_LARGE') {
+      throw new ImageDecodeError('image data is larger than the image dimensions', 'png');
+    }
+    throw new ImageDecodeError(`corrupt image data (${error instanceof Error ? error.message : String(error)})`, 'png');
+  }
+
+  const { width, height, bitDepth, colorType, interlaced } = header;
+  const data = new Uint8ClampedArray(width * height * 4);
+  const writer = createPixelWriter(header, palette, transparency);
+
+  if (interlaced) {
+    let rawOffset = 0;
+    for (const [startX, startY, stepX, stepY] of ADAM7) {
+      const passWidth = Math.ceil((width - startX) / stepX);
+      const passHeight = Math.ceil((height - startY) / stepY);
+      if (passWidth <= 0 || passHeight <= 0) continue;
+      const pass = unfilter(raw, rawOffset, passWidth, passHeight, header);
+      rawOffset += passHeight * (1 + rowBytes(passWidth, header));
+      for (let py = 0; py < passHeight; py++) {
+        for (let px = 0; px < passWidth; px++) {
+          const target = ((startY + py * stepY) * width + startX + px * stepX) * 4;
+          writer(pass[py]!, px, data, target);
+        }
+      }
+    }
+  } else {
+    const image = unfilter(raw, 0, width, height, header);
+    for (let y = 0; y < height; y++) {
+      const row = image[y]!;
+      for (let x = 0; x < width; x++) {
+        writer(row, x, data, (y * width + x) * 4);
+      }
+    }
+  }
+
+  return {
+    width,
+    height,
+    data,
+    metadata: {
+      format: 'png',
+      width,
+      height,
+      bitDepth,
+      colorType: COLOR_TYPE_NAMES[colorType],
+      hasAlpha: colorType === 4 || colorType === 6 || transparency !== null,
+      interlaced,
+    },
+  };
+}
+
+function parseHeader(data: Uint8Array): PngHeader {
+  if (data.length < 13) {
+    throw new ImageDecodeError('short IHDR chunk', 'png');
+  }
+  const width = readUint32(data, 0);
+  const height = readUint32(data, 4);
+  const bitDepth = data[8]!;
+  const colorType = data[9]!;
+  const interlace = data[12]!;
+
+  checkDimensions(width, height, 'png');
+  if (!BIT_DEPTHS[colorType]) {
+    throw new ImageDecodeError(`unknown color type ${colorType}`, 'png');
+  }
+  if (!BIT_DEPTHS[colorType]!.includes(bitDepth)) {
+    throw new ImageDecodeError(`invalid bit depth ${bitDepth} for color type ${colorType}`, 'png');
+  }
+  if (interlace > 1) {
+    throw new ImageDecodeError(`unknown interlace method ${interlace}`, 'png');
+  }
+  return { width, height, bitDepth, colorType, interlaced: interlace === 1 };
+}
+
+// ============================================
+// Filtering
+// ============================================
+
+function bitsPerPixel(header: PngHeader): number {
+  return CHANNELS[header.colorType]! * header.bitDepth;
+}
+
+function rowBytes(width: number, header: PngHeader): number {
+  return Math.ceil((width * bitsPerPixel(header)) / 8);
+}
+
+/**
+ * Length of the decompressed image data: a filter byte and the pixels of
+ * each row, for the whole image or each Adam7 pass.
+ */
+function dataLength(header: PngHeader): number {
+  const { width, height } = header;
+  if (!header.interlaced) {
+    return height * (1 + rowBytes(width, header));
+  }
+
+  let length = 0;
+  for (const [startX, startY, stepX, stepY] of ADAM7) {
+    const passWidth = Math.ceil((width - startX) / stepX);
+    const passHeight = Math.ceil((height - startY) / stepY);
+    if (passWidth > 0 && passHeight > 0) {
+      length += passHeight * (1 + rowBytes(passWidth, header));
+    }
+  }
+  return length;
+}
+
+/**
+ * Undo scanline filters for one (sub)image starting at `offset`.
+ */
+function unfilter(
+  raw: Uint8Array,
+  offset: number,
+  width: number,
+  height: number,
+  header: PngHeader
+): Uint8Array[] {
+  const stride = rowBytes(width, header);
+  const bpp = Math.max(1, bitsPerPixel(header) >> 3);
+  const rows: Uint8Array[] = [];
+  let previous = new Uint8Array(stride);
+
+  for (let y = 0; y < height; y++) {
+    const start = offset + y * (stride + 1);
+    if (start + stride + 1 > raw.length) {
+      throw new ImageDecodeError('image data is shorter than expected', 'png');
+    }
+    const filter = raw[start]!;
+    const line = raw.slice(start + 1, start + 1 + stride);
+
+    for (let i = 0; i < stride; i++) {
+      const left = i >= bpp ? line[i - bpp]! : 0;
+      const up = previous[i]!;
+      const upLeft = i >= bpp ? previous[i - bpp]! : 0;
+      let predictor: number;
+      switch (filter) {
+        case 0:
+          predictor = 0;
+          break;
+        case 1:
+          predictor = left;
+          break;
+        case 2:
+          predictor = up;
+          break;
+        case 3:
+          predictor = (left + up) >> 1;
+          break;
+        case 4:
+          predictor = paeth(left, up, upLeft);
+          break;
+        default:
+          throw new ImageDecodeError(`unknown filter type ${filter}`, 'png');
+      }
+      line[i] = (line[i]! + predictor) & 0xff;
+    }
+
+    rows.push(line);
+    previous = line;
+  }
+
+  return rows;
+}
+
+function paeth(a: number, b: number, c: number): number {
+  const p = a + b - c;
+  const pa = Math.abs(p - a);
+  const pb = Math.abs(p - b);
+  const pc = Math.abs(p - c);
+  if (pa <= pb && pa <= pc) return a;
+  if (pb <= pc) return b;
+  return c;
+}
+
+// ============================================
+// Pixel Conversion
+// ============================================
+
+type PixelWriter = (row: Uint8Array, x: number, out: Uint8ClampedArray, target: number) => void;
+
+/**
+ * Build a function that converts pixel `x` of an unfiltered row to RGBA.
+ */
+function createPixelWriter(
+  header: PngHeader,
+  palette: Uint8Array | null,
+  transparency: Uint8Array | null
+): PixelWriter {
+  const { bitDepth, colorType } = header;
+  const maxValue = (1 << bitDepth) - 1;
+
+  /** Read sample `index` of a row (bit depths below 8 are packed MSB first) */
+  const sample = (row: Uint8Array, index: number): number => {
+    switch (bitDepth) {
+      case 16:
+        return (row[index * 2]! << 8) | row[index * 2 + 1]!;
+      case 8:
+        return row[index]!;
+      default: {
+        const bit = index * bitDepth;
+        const shift = 8 - bitDepth - (bit & 7);
+        return (row[bit >> 3]! >> shift) & maxValue;
+      }
+    }
+  };
+  /** Scale a sample to 8 bits */
+  const to8 = (value: number): number => (bitDepth === 16 ? value >> 8 : Math.round((value * 255) / maxValue));
+
+  switch (colorType) {
+    case 0: {
+      const key = transparency && transparency.length >= 2 ? (transparency[0]! << 8) | transparency[1]! : -1;
+      return (row, x, out, t) => {
+        const value = sample(row, x);
+        const gray = to8(value);
+        out[t] = out[t + 1] = out[t + 2] = gray;
+        out[t + 3] = value === key ? 0 : 255;
+      };
+    }
+    case 2: {
+      const key =
+        transparency && transparency.length >= 6
+          ? [
+              (transparency[0]! << 8) | transparency[1]!,
+              (transparency[2]! << 8) | transparency[3]!,
+              (transparency[4]! << 8) | transparency[5]!,
+            ]
+          : null;
+      return (row, x, out, t) => {
+        const r = sample(row, x * 3);
+        const g = sample(row, x * 3 + 1);
+        const b = sample(row, x * 3 + 2);
+        out[t] = to8(r);
+        out[t + 1] = to8(g);
+        out[t + 2] = to8(b);
+        out[t + 3] = key && r === key[0] && g === key[1] && b === key[2] ? 0 : 255;
+      };
+    }
+    case 3: {
+      const entries = palette!;
+      const alphas = transparency;
+      return (row, x, out, t) => {
+        const index = sample(row, x);
+        const p = index * 3;
+        out[t] = entries[p] ?? 0;
+        out[t + 1] = entries[p + 1] ?? 0;
+        out[t + 2] = entries[p + 2] ?? 0;
+        out[t + 3] = alphas && index < alphas.length ? alphas[index]! : 255;
+      };
+    }
+    case 4:
+      return (row, x, out, t) => {
+        const gray = to8(sample(row, x * 2));
+        out[t] = out[t + 1] = out[t + 2] = gray;
+        out[t + 3] = to8(sample(row, x * 2 + 1));
+      };
+    default:
+      return (row, x, out, t) => {
+        out[t] = to8(sample(row, x * 4));
+        out[t + 1] = to8(sample(row, x * 4 + 1));
+        out[t + 2] = to8(sample(row, x * 4 + 2));
+        out[t + 3] = to8(sample(row, x * 4 + 3));
+      };
+  }
+}
+
+function concat(parts: Uint8Array[]): Uint8Array {
+  if (parts.length === 1) return parts[0]!;
+  const total = parts.reduce((sum, part) => sum + part.length, 0);
+  const result = new Uint8Array(total);
+  let offset = 0;
+  for (const part of parts) {
+    result.set(part, offset);
+    offset += part.length;
+  }
+  return result;
+}
@@ -0,0 +1,1227 @@
+/**
+ * SVG Rasterizer
+ *
+ * Renders the static geometry of an SVG document to RGBA: rect, circle,
+ * ellipse, line, polyline, polygon and path (all commands, including
+ * arcs), grouped with <g>/<use> and transformed. Fill and stroke support
+ * solid colors, opacity, fill-rule, stroke width, caps and joins.
+ * Gradients and patterns are approximated by their average stop color;
+ * text, filters, masks, clipping and CSS style sheets are ignored.
+ */
+
+import { ImageDecodeError, checkDimensions, type DecodedImage } from './types.ts';
+
+// ============================================
+// Types
+// ============================================
+
+/**
+ * A parsed XML element.
+ */
+export interface XmlElement {
+  name: string;
+  attributes: Record<string, string>;
+  children: XmlElement[];
+}
+
+export interface SvgRenderOptions {
+  /** Scale factor applied to the intrinsic size (default 1) */
+  scale?: number;
+}
+
+/** 2D affine matrix [a, b, c, d, e, f] (x' = ax + cy + e, y' = bx + dy + f) */
+type Matrix = [number, number, number, number, number, number];
+
+interface Point {
+  x: number;
+  y: number;
+}
+
+/** A flattened subpath */
+interface Polyline {
+  points: Point[];
+  closed: boolean;
+}
+
+interface Paint {
+  r: number;
+  g: number;
+  b: number;
+  a: number;
+}
+
+/** Inherited presentation attributes */
+interface Style {
+  fill: Paint | null;
+  stroke: Paint | null;
+  strokeWidth: number;
+  opacity: number;
+  fillOpacity: number;
+  strokeOpacity: number;
+  fillRule: 'nonzero' | 'evenodd';
+  lineCap: 'butt' | 'round' | 'square';
+  lineJoin: 'miter' | 'round' | 'bevel';
+  miterLimit: number;
+  color: Paint;
+  display: boolean;
+}
+
+/** Default size of an SVG without width, height or viewBox (as in browsers) */
+const DEFAULT_WIDTH = 300;
+const DEFAULT_HEIGHT = 150;
+
+/** Largest rasterized dimension */
+const MAX_DIMENSION = 4096;
+
+/** Sub-scanlines per pixel row for anti-aliasing */
+const SUBSAMPLES = 4;
+
+const IDENTITY: Matrix = [1, 0, 0, 1, 0, 0];
+
+const BLACK: Paint = { r: 0, g: 0, b: 0, a: 1 };
+
+/**
+ * Check whether bytes look like an SVG document.
+ */
+export function isSvg(bytes: Uint8Array): boolean {
+  const head = new TextDecoder().decode(bytes.subarray(0, 1024)).replace(/^\uFEFF/, '').trimStart();
+  if (!head.startsWith('<')) return false;
+  return /<svg[\s/>]/i.test(head) || (head.startsWith('<?xml') && /<svg/i.test(new TextDecoder().decode(bytes.subarray(0, 8192))));
+}
+
+// ============================================
+// Decoder
+// ============================================
+
+/**
+ * Rasterize an SVG document.
+ */
+export function decodeSvg(bytes: Uint8Array, options: SvgRenderOptions = {}): DecodedImage {
+  const text = new TextDecoder().decode(bytes);
+  const root = findSvgRoot(parseXml(text));
+  if (!root) {
+    throw new ImageDecodeError('no <svg> element', 'svg');
+  }
+
+  const viewBox = parseNumberList(root.attributes.viewBox ?? '');
+  const hasViewBox = viewBox.length === 4 && viewBox[2]! > 0 && viewBox[3]! > 0;
+  let width = parseLength(root.attributes.width);
+  let height = parseLength(root.attributes.height);
+  if (hasViewBox) {
+    const aspect = viewBox[2]! / viewBox[3]!;
+    if (width === null && height === null) {
+      width = viewBox[2]!;
+      height = viewBox[3]!;
+    } else if (width === null) {
+      width = height! * aspect;
+    } else if (height === null) {
+      height = width / aspect;
+    }
+  }
+  width ??= DEFAULT_WIDTH;
+  height ??= DEFAULT_HEIGHT;
+
+  const scale = Math.min(options.scale ?? 1, MAX_DIMENSION / Math.max(width, height));
+  const pixelWidth = Math.max(1, Math.round(width * scale));
+  const pixelHeight = Math.max(1, Math.round(height * scale));
+  checkDimensions(pixelWidth, pixelHeight, 'svg');
+
+  let matrix: Matrix = [scale, 0, 0, scale, 0, 0];
+  if (hasViewBox) {
+    matrix = multiply(matrix, viewBoxTransform(viewBox, width, height, root.attributes.preserveAspectRatio));
+  }
+
+  const canvas = new Canvas(pixelWidth, pixelHeight);
+  const ids = new Map<string, XmlElement>();
+  collectIds(root, ids);
+  const renderer = new SvgRenderer(canvas, ids);
+  renderer.renderChildren(root, matrix, applyStyle(defaultStyle(), root.attributes), 0);
+
+  return {
+    width: pixelWidth,
+    height: pixelHeight,
+    data: canvas.data,
+    metadata: {
+      format: 'svg',
+      width: pixelWidth,
+      height: pixelHeight,
+      colorType: 'Vector',
+      hasAlpha: true,
+    },
+  };
+}
+
+function findSvgRoot(nodes: XmlElement[]): XmlElement | null {
+  for (const node of nodes) {
+    if (localName(node.name) === 'svg') return node;
+    const nested = findSvgRoot(node.children);
+    if (nested) return nested;
+  }
+  return null;
+}
+
+function collectIds(element: XmlElement, ids: Map<string, XmlElement>): void {
+  if (element.attributes.id) ids.set(element.attributes.id, element);
+  for (const child of element.children) collectIds(child, ids);
+}
+
+function localName(name: string): string {
+  const colon = name.indexOf(':');
+  return colon >= 0 ? name.slice(colon + 1) : name;
+}
+
+/**
+ * Map the viewBox onto the viewport (preserveAspectRatio, default xMidYMid meet).
+ */
+function viewBoxTransform(viewBox: number[], width: number, height: number, aspect = ''): Matrix {
+  const [minX, minY, vbWidth, vbHeight] = viewBox as [number, number, number, number];
+  let sx = width / vbWidth;
+  let sy = height / vbHeight;
+  const [align = 'xMidYMid', meetOrSlice = 'meet'] = aspect.trim().split(/\s+/);
+
+  if (align !== 'none') {
+    const s = meetOrSlice === 'slice' ? Math.max(sx, sy) : Math.min(sx, sy);
+    sx = sy = s;
+  }
+  let tx = -minX * sx;
+  let ty = -minY * sy;
+  if (align !== 'none') {
+    const extraX = width - vbWidth * sx;
+    const extraY = height - vbHeight * sy;
+    if (align.includes('xMid')) tx += extraX / 2;
+    if (align.includes('xMax')) tx += extraX;
+    if (align.includes('YMid')) ty += extraY / 2;
+    if (align.includes('YMax')) ty += extraY;
+  }
+  return [sx, 0, 0, sy, tx, ty];
+}
+
+// ============================================
+// XML
+// ============================================
+
+const ENTITIES: Record<string, string> = { lt: '<', gt: '>', amp: '&', quot: '"', apos: "'" };
+
+function decodeEntities(value: string): string {
+  return value.replace(/&(#x[0-9a-f]+|#\d+|\w+);/gi, (match, entity: string) => {
+    if (entity[0] === '#') {
+      const code = entity[1] === 'x' || entity[1] === 'X' ? parseInt(entity.slice(2), 16) : parseInt(entity.slice(1), 10);
+      return Number.isFinite(code) ? String.fromCodePoint(code) : match;
+    }
+    return ENTITIES[entity] ?? match;
+  });
+}
+
+/**
+ * Parse XML into an element tree. Text, comments, CDATA, processing
+ * instructions and doctypes are skipped; unbalanced tags are tolerated.
+ */
+export function parseXml(text: string): XmlElement[] {
+  const roots: XmlElement[] = [];
+  const stack: XmlElement[] = [];
+  let i = 0;
+
+  while (i < text.length) {
+    const open = text.indexOf('<', i);
+    if (open < 0) break;
+
+    if (text.startsWith('<!--', open)) {
+      const end = text.indexOf('-->', open + 4);
+      i = end < 0 ? text.length : end + 3;
+      continue;
+    }
+    if (text.startsWith('<![CDATA[', open)) {
+      const end = text.indexOf(']]>', open);
+      i = end < 0 ? text.length : end + 3;
+      continue;
+    }
+    if (text[open + 1] === '?' || text[open + 1] === '!') {
+      i = skipDeclaration(text, open);
+      continue;
+    }
+
+    if (text[open + 1] === '/') {
+      const end = text.indexOf('>', open);
+      const name = text.slice(open + 2, end < 0 ? text.length : end).trim();
+      // Pop to the matching element (tolerates missing close tags)
+      const index = stack.map((e) => e.name).lastIndexOf(name);
+      if (index >= 0) stack.length = index;
+      i = end < 0 ? text.length : end + 1;
+      continue;
+    }
+
+    const tag = readTag(text, open);
+    if (!tag) {
+      i = open + 1;
+      continue;
+    }
+    const element: XmlElement = { name: tag.name, attributes: tag.attributes, children: [] };
+    const parent = stack[stack.length - 1];
+    if (parent) {
+      parent.children.push(element);
+    } else {
+      roots.push(element);
+    }
+    if (!tag.selfClosing) stack.push(element);
+    i = tag.end;
+  }
+
+  return roots;
+}
+
+function skipDeclaration(text: string, start: number): number {
+  // Doctypes can contain an internal subset in brackets
+  let depth = 0;
+  for (let i = start + 2; i < text.length; i++) {
+    const ch = text[i];
+    if (ch === '[') depth++;
+    else if (ch === ']') depth--;
+    else if (ch === '>' && depth <= 0) return i + 1;
+  }
+  return text.length;
+}
+
+function readTag(
+  text: string,
+  start: number
+): { name: string; attributes: Record<string, string>; selfClosing: boolean; end: number } | null {
+  const nameMatch = /^<([A-Za-z_][\w:.-]*)/.exec(text.slice(start, start + 256));
+  if (!nameMatch) return null;
+
+  const attributes: Record<string, string> = {};
+  let i = start + nameMatch[0].length;
+  const attributePattern = /\s*([A-Za-z_][\w:.-]*)\s*(?:=\s*("[^"]*"|'[^']*'|[^\s"'>/]+))?/y;
+
+  while (i < text.length) {
+    while (i < text.length && /\s/.test(text[i]!)) i++;
+    if (text[i] === '>') {
+      return { name: nameMatch[1]!, attributes, selfClosing: false, end: i + 1 };
+    }
+    if (text[i] === '/' && text[i + 1] === '>') {
+      return { name: nameMatch[1]!, attributes, selfClosing: true, end: i + 2 };
+    }
+    attributePattern.lastIndex = i;
+    const match = attributePattern.exec(text);
+    if (!match || match[0].length === 0) {
+      i++;
+      continue;
+    }
+    let value = match[2] ?? '';
+    if (value[0] === '"' || value[0] === "'") value = value.slice(1, -1);
+    attributes[match[1]!] = decodeEntities(value);
+    i = attributePattern.lastIndex;
+  }
+  return null;
+}
+
+// ============================================
+// Style
+// ============================================
+
+function defaultStyle(): Style {
+  return {
+    fill: BLACK,
+    stroke: null,
+    strokeWidth: 1,
+    opacity: 1,
+    fillOpacity: 1,
+    strokeOpacity: 1,
+    fillRule: 'nonzero',
+    lineCap: 'butt',
+    lineJoin: 'miter',
+    miterLimit: 4,
+    color: BLACK,
+    display: true,
+  };
+}
+
+/**
+ * Apply an element's presentation attributes and `style` declarations
+ * on top of the inherited style. `opacity` multiplies down the tree.
+ */
+function applyStyle(parent: Style, attributes: Record<string, string>, ids?: Map<string, XmlElement>): Style {
+  const declarations: Record<string, string> = { ...attributes };
+  for (const part of (attributes.style ?? '').split(';')) {
+    const colon = part.indexOf(':');
+    if (colon > 0) {
+      declarations[part.slice(0, colon).trim()] = part.slice(colon + 1).trim();
+    }
+  }
+
+  const style: Style = { ...parent, opacity: parent.opacity };
+  const get = (key: string): string | undefined => {
+    const value = declarations[key]?.trim();
+    return value && value !== 'inherit' ? value : undefined;
+  };
+
+  const color = get('color');
+  if (color) style.color = parseColor(color, parent.color) ?? parent.color;
+
+  const fill = get('fill');
+  if (fill) style.fill = parsePaint(fill, style.color, ids, parent.fill);
+  const stroke = get('stroke');
+  if (stroke) style.stroke = parsePaint(stroke, style.color, ids, parent.stroke);
+
+  const strokeWidth = get('stroke-width');
+  if (strokeWidth) style.strokeWidth = parseLength(strokeWidth) ?? style.strokeWidth;
+  const opacity = get('opacity');
+  if (opacity) style.opacity = parent.opacity * clampUnit(parseFloat(opacity));
+  else style.opacity = parent.opacity;
+  const fillOpacity = get('fill-opacity');
+  if (fillOpacity) style.fillOpacity = clampUnit(parseFloat(fillOpacity));
+  const strokeOpacity = get('stroke-opacity');
+  if (strokeOpacity) style.strokeOpacity = clampUnit(parseFloat(strokeOpacity));
+
+  const fillRule = get('fill-rule');
+  if (fillRule === 'evenodd' || fillRule === 'nonzero') style.fillRule = fillRule;
+  const lineCap = get('stroke-linecap');
+  if (lineCap === 'butt' || lineCap === 'round' || lineCap === 'square') style.lineCap = lineCap;
+  const lineJoin = get('stroke-linejoin');
+  if (lineJoin === 'miter' || lineJoin === 'round' || lineJoin === 'bevel') style.lineJoin = lineJoin;
+  const miterLimit = get('stroke-miterlimit');
+  if (miterLimit) style.miterLimit = Math.max(1, parseFloat(miterLimit) || 4);
+
+  const display = get('display');
+  const visibility = get('visibility');
+  style.display = display !== 'none' && visibility !== 'hidden' && visibility !== 'collapse';
+  return style;
+}
+
+function clampUnit(value: number): number {
+  return Number.isFinite(value) ? Math.max(0, Math.min(1, value)) : 1;
+}
+
+function parsePaint(
+  value: string,
+  currentColor: Paint,
+  ids: Map<string, XmlElement> | undefined,
+  fallback: Paint | null
+): Paint | null {
+  if (value === 'none' || value === 'transparent') return null;
+  if (value === 'currentColor') return currentColor;
+  const url = /^url\(\s*['"]?#([^'")]+)['"]?\s*\)\s*(.*)$/.exec(value);
+  if (url) {
+    const target = ids?.get(url[1]!);
+    return (target && averageStopColor(target, ids!)) ?? (url[2] ? parsePaint(url[2], currentColor, ids, fallback) : null);
+  }
+  return parseColor(value, currentColor) ?? fallback;
+}
+
+/**
+ * Approximate a gradient by the average of its stop colors.
+ */
+function averageStopColor(gradient: XmlElement, ids: Map<string, XmlElement>, depth = 0): Paint | null {
+  const stops = gradient.children.filter((c) => localName(c.name) === 'stop');
+  const href = gradient.attributes.href ?? gradient.attributes['xlink:href'];
+  if (stops.length === 0 && href?.startsWith('#') && depth < 4) {
+    const target = ids.get(href.slice(1));
+    return target ? averageStopColor(target, ids, depth + 1) : null;
+  }
+  if (stops.length === 0) return null;
+
+  const total = { r: 0, g: 0, b: 0, a: 0 };
+  for (const stop of stops) {
+    const declarations = { ...stop.attributes };
+    for (const part of (stop.attributes.style ?? '').split(';')) {
+      const colon = part.indexOf(':');
+      if (colon > 0) declarations[part.slice(0, colon).trim()] = part.slice(colon + 1).trim();
+    }
+    const color = parseColor(declarations['stop-color'] ?? 'black') ?? BLACK;
+    const opacity = declarations['stop-opacity'] !== undefined ? clampUnit(parseFloat(declarations['stop-opacity'])) : 1;
+    total.r += color.r;
+    total.g += color.g;
+    total.b += color.b;
+    total.a += color.a * opacity;
+  }
+  return { r: total.r / stops.length, g: total.g / stops.length, b: total.b / stops.length, a: total.a / stops.length };
+}
+
+/**
+ * Parse a CSS color: #rgb, #rgba, #rrggbb, #rrggbbaa, rgb()/rgba(),
+ * hsl()/hsla() or a named color.
+ */
+export function parseColor(value: string, currentColor: Paint = BLACK): Paint | null {
+  const v = value.trim().toLowerCase();
+  if (v === 'currentcolor') return currentColor;
+  if (v === 'none' || v === 'transparent') return null;
+
+  if (v[0] === '#') {
+    const hex = v.slice(1);
+    if (!/^[0-9a-f]+$/.test(hex)) return null;
+    if (hex.length === 3 || hex.length === 4) {
+      const [r, g, b, a = 'f'] = hex.split('');
+      return {
+        r: parseInt(r! + r!, 16),
+        g: parseInt(g! + g!, 16),
+        b: parseInt(b! + b!, 16),
+        a: parseInt(a + a, 16) / 255,
+      };
+    }
+    if (hex.length === 6 || hex.length === 8) {
+      return {
+        r: parseInt(hex.slice(0, 2), 16),
+        g: parseInt(hex.slice(2, 4), 16),
+        b: parseInt(hex.slice(4, 6), 16),
+        a: hex.length === 8 ? parseInt(hex.slice(6, 8), 16) / 255 : 1,
+      };
+    }
+    return null;
+  }
+
+  const fn = /^(rgba?|hsla?)\(([^)]*)\)$/.exec(v);
+  if (fn) {
+    const parts = fn[2]!.split(/[\s,/]+/).filter(Boolean);
+    const channel = (part: string | undefined, max: number): number =>
+      part === undefined ? max : part.endsWith('%') ? (parseFloat(part) / 100) * max : parseFloat(part);
+    const alpha = parts[3] !== undefined ? clampUnit(channel(parts[3], 1)) : 1;
+    if (fn[1]!.startsWith('rgb')) {
+      return { r: channel(parts[0], 255), g: channel(parts[1], 255), b: channel(parts[2], 255), a: alpha };
+    }
+    const [r, g, b] = hslToRgb(parseFloat(parts[0] ?? '0'), channel(parts[1], 1), channel(parts[2], 1));
+    return { r, g, b, a: alpha };
+  }
+
+  const named = CSS_COLORS[v];
+  if (named === undefined) return null;
+  return { r: (named >> 16) & 0xff, g: (named >> 8) & 0xff, b: named & 0xff, a: 1 };
+}
+
+function hslToRgb(h: number, s: number, l: number): [number, number, number] {
+  const hue = (((h % 360) + 360) % 360) / 60;
+  const c = (1 - Math.abs(2 * l - 1)) * s;
+  const x = c * (1 - Math.abs((hue % 2) - 1));
+  const m = l - c / 2;
+  const [r, g, b] =
+    hue < 1 ? [c, x, 0] : hue < 2 ? [x, c, 0] : hue < 3 ? [0, c, x] : hue < 4 ? [0, x, c] : hue < 5 ? [x, 0, c] : [c, 0, x];
+  return [(r + m) * 255, (g + m) * 255, (b + m) * 255];
+}
+
+/** CSS named colors */
+const CSS_COLORS: Record<string, number> = {
+  aliceblue: 0xf0f8ff, antiquewhite: 0xfaebd7, aqua: 0x00ffff, aquamarine: 0x7fffd4, azure: 0xf0ffff,
+  beige: 0xf5f5dc, bisque: 0xffe4c4, black: 0x000000, blanchedalmond: 0xffebcd, blue: 0x0000ff,
+  blueviolet: 0x8a2be2, brown: 0xa52a2a, burlywood: 0xdeb887, cadetblue: 0x5f9ea0, chartreuse: 0x7fff00,
+  chocolate: 0xd2691e, coral: 0xff7f50, cornflowerblue: 0x6495ed, cornsilk: 0xfff8dc, crimson: 0xdc143c,
+  cyan: 0x00ffff, darkblue: 0x00008b, darkcyan: 0x008b8b, darkgoldenrod: 0xb8860b, darkgray: 0xa9a9a9,
+  darkgreen: 0x006400, darkgrey: 0xa9a9a9, darkkhaki: 0xbdb76b, darkmagenta: 0x8b008b, darkolivegreen: 0x556b2f,
+  darkorange: 0xff8c00, darkorchid: 0x9932cc, darkred: 0x8b0000, darksalmon: 0xe9967a, darkseagreen: 0x8fbc8f,
+  darkslateblue: 0x483d8b, darkslategray: 0x2f4f4f, darkslategrey: 0x2f4f4f, darkturquoise: 0x00ced1,
+  darkviolet: 0x9400d3, deeppink: 0xff1493, deepskyblue: 0x00bfff, dimgray: 0x696969, dimgrey: 0x696969,
+  dodgerblue: 0x1e90ff, firebrick: 0xb22222, floralwhite: 0xfffaf0, forestgreen: 0x228b22, fuchsia: 0xff00ff,
+  gainsboro: 0xdcdcdc, ghostwhite: 0xf8f8ff, gold: 0xffd700, goldenrod: 0xdaa520, gray: 0x808080,
+  green: 0x008000, greenyellow: 0xadff2f, grey: 0x808080, honeydew: 0xf0fff0, hotpink: 0xff69b4,
+  indianred: 0xcd5c5c, indigo: 0x4b0082, ivory: 0xfffff0, khaki: 0xf0e68c, lavender: 0xe6e6fa,
+  lavenderblush: 0xfff0f5, lawngreen: 0x7cfc00, lemonchiffon: 0xfffacd, lightblue: 0xadd8e6, lightcoral: 0xf08080,
+  lightcyan: 0xe0ffff, lightgoldenrodyellow: 0xfafad2, lightgray: 0xd3d3d3, lightgreen: 0x90ee90,
+  lightgrey: 0xd3d3d3, lightpink: 0xffb6c1, lightsalmon: 0xffa07a, lightseagreen: 0x20b2aa, lightskyblue: 0x87cefa,
+  lightslategray: 0x778899, lightslategrey: 0x778899, lightsteelblue: 0xb0c4de, lightyellow: 0xffffe0,
+  lime: 0x00ff00, limegreen: 0x32cd32, linen: 0xfaf0e6, magenta: 0xff00ff, maroon: 0x800000,
+  mediumaquamarine: 0x66cdaa, mediumblue: 0x0000cd, mediumorchid: 0xba55d3, mediumpurple: 0x9370db,
+  mediumseagreen: 0x3cb371, mediumslateblue: 0x7b68ee, mediumspringgreen: 0x00fa9a, mediumturquoise: 0x48d1cc,
+  mediumvioletred: 0xc71585, midnightblue: 0x191970, mintcream: 0xf5fffa, mistyrose: 0xffe4e1,
+  moccasin: 0xffe4b5, navajowhite: 0xffdead, navy: 0x000080, oldlace: 0xfdf5e6, olive: 0x808000,
+  olivedrab: 0x6b8e23, orange: 0xffa500, orangered: 0xff4500, orchid: 0xda70d6, palegoldenrod: 0xeee8aa,
+  palegreen: 0x98fb98, paleturquoise: 0xafeeee, palevioletred: 0xdb7093, papayawhip: 0xffefd5,
+  peachpuff: 0xffdab9, peru: 0xcd853f, pink: 0xffc0cb, plum: 0xdda0dd, powderblue: 0xb0e0e6,
+  purple: 0x800080, rebeccapurple: 0x663399, red: 0xff0000, rosybrown: 0xbc8f8f, royalblue: 0x4169e1,
+  saddlebrown: 0x8b4513, salmon: 0xfa8072, sandybrown: 0xf4a460, seagreen: 0x2e8b57, seashell: 0xfff5ee,
+  sienna: 0xa0522d, silver: 0xc0c0c0, skyblue: 0x87ceeb, slateblue: 0x6a5acd, slategray: 0x708090,
+  slategrey: 0x708090, snow: 0xfffafa, springgreen: 0x00ff7f, steelblue: 0x4682b4, tan: 0xd2b48c,
+  teal: 0x008080, thistle: 0xd8bfd8, tomato: 0xff6347, turquoise: 0x40e0d0, violet: 0xee82ee,
+  wheat: 0xf5deb3, white: 0xffffff, whitesmoke: 0xf5f5f5, yellow: 0xffff00, yellowgreen: 0x9acd32,
+};
+
+// ============================================
+// Numbers, Lengths and Transforms
+// ============================================
+
+function parseNumberList(value: string): number[] {
+  return (value.match(/[-+]?(?:\d*\.\d+|\d+\.?)(?:[eE][-+]?\d+)?/g) ?? []).map(Number);
+}
+
+const UNIT_SCALE: Record<string, number> = { px: 1, pt: 4 / 3, pc: 16, mm: 96 / 25.4, cm: 96 / 2.54, in: 96, em: 16, ex: 8 };
+
+/**
+ * Parse a length in user units. Percentages return null (treated as unset).
+ */
+function parseLength(value: string | undefined): number | null {
+  if (!value) return null;
+  const match = /^\s*([-+]?(?:\d*\.\d+|\d+\.?)(?:[eE][-+]?\d+)?)\s*([a-z%]*)\s*$/i.exec(value);
+  if (!match || match[2] === '%') return null;
+  const number = parseFloat(match[1]!);
+  return number * (UNIT_SCALE[match[2]!.toLowerCase()] ?? 1);
+}
+
+function multiply(m: Matrix, n: Matrix): Matrix {
+  return [
+    m[0] * n[0] + m[2] * n[1],
+    m[1] * n[0] + m[3] * n[1],
+    m[0] * n[2] + m[2] * n[3],
+    m[1] * n[2] + m[3] * n[3],
+    m[0] * n[4] + m[2] * n[5] + m[4],
+    m[1] * n[4] + m[3] * n[5] + m[5],
+  ];
+}
+
+function applyMatrix(m: Matrix, p: Point): Point {
+  return { x: m[0] * p.x + m[2] * p.y + m[4], y: m[1] * p.x + m[3] * p.y + m[5] };
+}
+
+/**
+ * Parse a `transform` attribute into a matrix.
+ */
+export function parseTransform(value: string | undefined): Matrix {
+  let result: Matrix = [...IDENTITY];
+  if (!value) return result;
+
+  const pattern = /(matrix|translate|scale|rotate|skewX|skewY)\s*\(([^)]*)\)/g;
+  for (let match = pattern.exec(value); match; match = pattern.exec(value)) {
+    const args = parseNumberList(match[2]!);
+    let m: Matrix = [...IDENTITY];
+    switch (match[1]) {
+      case 'matrix':
+        if (args.length === 6) m = args as Matrix;
+        break;
+      case 'translate':
+        m = [1, 0, 0, 1, args[0] ?? 0, args[1] ?? 0];
+        break;
+      case 'scale':
+        m = [args[0] ?? 1, 0, 0, args[1] ?? args[0] ?? 1, 0, 0];
+        break;
+      case 'rotate': {
+        const angle = ((args[0] ?? 0) * Math.PI) / 180;
+        const cos = Math.cos(angle);
+        const sin = Math.sin(angle);
+        m = [cos, sin, -sin, cos, 0, 0];
+        if (args.length >= 3) {
+          const [cx, cy] = [args[1]!, args[2]!];
+          m = multiply(multiply([1, 0, 0, 1, cx, cy], m), [1, 0, 0, 1, -cx, -cy]);
+        }
+        break;
+      }
+      case 'skewX':
+        m = [1, 0, Math.tan(((args[0] ?? 0) * Math.PI) / 180), 1, 0, 0];
+        break;
+      case 'skewY':
+        m = [1, Math.tan(((args[0] ?? 0) * Math.PI) / 180), 0, 1, 0, 0];
+        break;
+    }
+    result = multiply(result, m);
+  }
+  return result;
+}
+
+// ============================================
+// Geometry
+// ============================================
+
+/** Segments used to flatten a curve spanning `length` device pixels */
+function segmentCount(length: number): number {
+  return Math.max(4, Math.min(128, Math.ceil(Math.sqrt(length) * 2)));
+}
+
+function distance(a: Point, b: Point): number {
+  return Math.hypot(b.x - a.x, b.y - a.y);
+}
+
+/**
+ * Flatten path data into polylines in user space. `scale` is the
+ * approximate device scale, used to pick curve subdivision.
+ */
+export function flattenPath(d: string, scale = 1): Polyline[] {
+  const tokens = d.match(/[a-df-z]|[-+]?(?:\d*\.\d+|\d+\.?)(?:[eE][-+]?\d+)?/gi) ?? [];
+  const polylines: Polyline[] = [];
+  let current: Polyline | null = null;
+  let position: Point = { x: 0, y: 0 };
+  let start: Point = { x: 0, y: 0 };
+  let lastControl: Point | null = null;
+  let lastCommand = '';
+  let i = 0;
+
+  const isCommand = (token: string | undefined): boolean => token !== undefined && /^[a-z]$/i.test(token);
+  const number = (): number => {
+    const token = tokens[i];
+    if (token === undefined || isCommand(token)) throw new ImageDecodeError('malformed path data', 'svg');
+    i++;
+    return parseFloat(token);
+  };
+  // Arc flags may be written without separators ("a1 1 0 00 1 1")
+  const flag = (): number => {
+    const token = tokens[i];
+    if (token === undefined || isCommand(token)) throw new ImageDecodeError('malformed path data', 'svg');
+    if (token.length > 1 && (token[0] === '0' || token[0] === '1')) {
+      tokens[i] = token.slice(1);
+      return token[0] === '1' ? 1 : 0;
+    }
+    i++;
+    return parseFloat(token) ? 1 : 0;
+  };
+  const lineTo = (p: Point): void => {
+    if (!current) {
+      current = { points: [position], closed: false };
+      polylines.push(current);
+    }
+    current.points.push(p);
+    position = p;
+  };
+  const curve = (points: (t: number) => Point, approxLength: number): void => {
+    const n = segmentCount(approxLength * scale);
+    for (let k = 1; k <= n; k++) lineTo(points(k / n));
+  };
+
+  let command = '';
+  while (i < tokens.length) {
+    if (isCommand(tokens[i])) {
+      command = tokens[i++]!;
+    } else if (!command) {
+      throw new ImageDecodeError('path data must start with a command', 'svg');
+    }
+    const relative = command === command.toLowerCase();
+    const base = relative ? position : { x: 0, y: 0 };
+    const upper = command.toUpperCase();
+
+    switch (upper) {
+      case 'M': {
+        const p = { x: base.x + number(), y: base.y + number() };
+        current = { points: [p], closed: false };
+        polylines.push(current);
+        position = start = p;
+        // Further coordinate pairs are implicit line-tos
+        command = relative ? 'l' : 'L';
+        lastControl = null;
+        break;
+      }
+      case 'L':
+        lineTo({ x: base.x + number(), y: base.y + number() });
+        lastControl = null;
+        break;
+      case 'H':
+        lineTo({ x: (relative ? position.x : 0) + number(), y: position.y });
+        lastControl = null;
+        break;
+      case 'V':
+        lineTo({ x: position.x, y: (relative ? position.y : 0) + number() });
+        lastControl = null;
+        break;
+      case 'C':
+      case 'S': {
+        const p0 = position;
+        let c1: Point;
+        if (upper === 'C') {
+          c1 = { x: base.x + number(), y: base.y + number() };
+        } else {
+          c1 = lastControl && /[CS]/i.test(lastCommand) ? { x: 2 * p0.x - lastControl.x, y: 2 * p0.y - lastControl.y } : p0;
+        }
+        const c2 = { x: base.x + number(), y: base.y + number() };
+        const p3 = { x: base.x + number(), y: base.y + number() };
+        const length = distance(p0, c1) + distance(c1, c2) + distance(c2, p3);
+        curve((t) => {
+          const mt = 1 - t;
+          return {
+            x: mt * mt * mt * p0.x + 3 * mt * mt * t * c1.x + 3 * mt * t * t * c2.x + t * t * t * p3.x,
+            y: mt * mt * mt * p0.y + 3 * mt * mt * t * c1.y + 3 * mt * t * t * c2.y + t * t * t * p3.y,
+          };
+        }, length);
+        lastControl = c2;
+        break;
+      }
+      case 'Q':
+      case 'T': {
+        const p0 = position;
+        let c: Point;
+        if (upper === 'Q') {
+          c = { x: base.x + number(), y: base.y + number() };
+        } else {
+          c = lastControl && /[QT]/i.test(lastCommand) ? { x: 2 * p0.x - lastControl.x, y: 2 * p0.y - lastControl.y } : p0;
+        }
+        const p2 = { x: base.x + number(), y: base.y + number() };
+        curve((t) => {
+          const mt = 1 - t;
+          return {
+            x: mt * mt * p0.x + 2 * mt * t * c.x + t * t * p2.x,
+            y: mt * mt * p0.y + 2 * mt * t * c.y + t * t * p2.y,
+          };
+        }, distance(p0, c) + distance(c, p2));
+        lastControl = c;
+        break;
+      }
+      case 'A': {
+        const rx = Math.abs(number());
+        const ry = Math.abs(number());
+        const rotation = number();
+        const largeArc = flag();
+        const sweep = flag();
+        const end = { x: base.x + number(), y: base.y + number() };
+        arcTo(position, end, rx, ry, rotation, largeArc === 1, sweep === 1, lineTo, scale);
+        lastControl = null;
+        break;
+      }
+      case 'Z':
+        if (current) {
+          current.closed = true;
+          position = start;
+          current = null;
+        }
+        lastControl = null;
+        break;
+      default:
+        throw new ImageDecodeError(`unknown path command ${command}`, 'svg');
+    }
+    lastCommand = upper === 'M' ? 'M' : command;
+    if (upper === 'Z') command = '';
+  }
+
+  return polylines.filter((p) => p.points.length > 1 || p.closed);
+}
+
+/**
+ * Flatten an elliptical arc (SVG implementation notes F.6.5).
+ */
+function arcTo(
+  from: Point,
+  to: Point,
+  rx: number,
+  ry: number,
+  rotationDegrees: number,
+  largeArc: boolean,
+  sweep: boolean,
+  lineTo: (p: Point) => void,
+  scale: number
+): void {
+  if (rx === 0 || ry === 0 || (from.x === to.x && from.y === to.y)) {
+    lineTo(to);
+    return;
+  }
+  const phi = (rotationDegrees * Math.PI) / 180;
+  const cos = Math.cos(phi);
+  const sin = Math.sin(phi);
+  const dx = (from.x - to.x) / 2;
+  const dy = (from.y - to.y) / 2;
+  const x1 = cos * dx + sin * dy;
+  const y1 = -sin * dx + cos * dy;
+
+  // Scale radii up if they can't span the endpoints
+  const lambda = (x1 * x1) / (rx * rx) + (y1 * y1) / (ry * ry);
+  if (lambda > 1) {
+    rx *= Math.sqrt(lambda);
+    ry *= Math.sqrt(lambda);
+  }
+
+  const numerator = rx * rx * ry * ry - rx * rx * y1 * y1 - ry * ry * x1 * x1;
+  const denominator = rx * rx * y1 * y1 + ry * ry * x1 * x1;
+  let factor = Math.sqrt(Math.max(0, numerator / denominator));
+  if (largeArc === sweep) factor = -factor;
+  const cx1 = (factor * rx * y1) / ry;
+  const cy1 = (-factor * ry * x1) / rx;
+  const cx = cos * cx1 - sin * cy1 + (from.x + to.x) / 2;
+  const cy = sin * cx1 + cos * cy1 + (from.y + to.y) / 2;
+
+  const angle = (ux: number, uy: number, vx: number, vy: number): number =>
+    Math.atan2(ux * vy - uy * vx, ux * vx + uy * vy);
+  const theta1 = angle(1, 0, (x1 - cx1) / rx, (y1 - cy1) / ry);
+  let delta = angle((x1 - cx1) / rx, (y1 - cy1) / ry, (-x1 - cx1) / rx, (-y1 - cy1) / ry);
+  if (!sweep && delta > 0) delta -= 2 * Math.PI;
+  if (sweep && delta < 0) delta += 2 * Math.PI;
+
+  const n = segmentCount(Math.abs(delta) * Math.max(rx, ry) * scale);
+  for (let k = 1; k <= n; k++) {
+    const theta = theta1 + (delta * k) / n;
+    const ex = rx * Math.cos(theta);
+    const ey = ry * Math.sin(theta);
+    lineTo(k === n ? to : { x: cos * ex - sin * ey + cx, y: sin * ex + cos * ey + cy });
+  }
+}
+
+function ellipsePolyline(cx: number, cy: number, rx: number, ry: number, scale: number): Polyline {
+  const n = segmentCount(2 * Math.PI * Math.max(rx, ry) * scale);
+  const points: Point[] = [];
+  for (let k = 0; k < n; k++) {
+    const theta = (2 * Math.PI * k) / n;
+    points.push({ x: cx + rx * Math.cos(theta), y: cy + ry * Math.sin(theta) });
+  }
+  return { points, closed: true };
+}
+
+/**
+ * Geometry of a basic shape element, in user space.
+ */
+function shapeGeometry(element: XmlElement, scale: number): Polyline[] {
+  const a = element.attributes;
+  const num = (key: string, fallback = 0): number => parseLength(a[key]) ?? fallback;
+
+  switch (localName(element.name)) {
+    case 'path':
+      return flattenPath(a.d ?? '', scale);
+    case 'rect': {
+      const x = num('x');
+      const y = num('y');
+      const width = num('width');
+      const height = num('height');
+      if (width <= 0 || height <= 0) return [];
+      let rx = parseLength(a.rx);
+      let ry = parseLength(a.ry);
+      rx ??= ry ?? 0;
+      ry ??= rx;
+      rx = Math.min(rx, width / 2);
+      ry = Math.min(ry, height / 2);
+      if (rx <= 0 || ry <= 0) {
+        return [{ points: [{ x, y }, { x: x + width, y }, { x: x + width, y: y + height }, { x, y: y + height }], closed: true }];
+      }
+      const d =
+        `M${x + rx},${y} H${x + width - rx} A${rx},${ry} 0 0 1 ${x + width},${y + ry} V${y + height - ry} ` +
+        `A${rx},${ry} 0 0 1 ${x + width - rx},${y + height} H${x + rx} A${rx},${ry} 0 0 1 ${x},${y + height - ry} ` +
+        `V${y + ry} A${rx},${ry} 0 0 1 ${x + rx},${y} Z`;
+      return flattenPath(d, scale);
+    }
+    case 'circle': {
+      const r = num('r');
+      return r > 0 ? [ellipsePolyline(num('cx'), num('cy'), r, r, scale)] : [];
+    }
+    case 'ellipse': {
+      const rx = num('rx');
+      const ry = num('ry');
+      return rx > 0 && ry > 0 ? [ellipsePolyline(num('cx'), num('cy'), rx, ry, scale)] : [];
+    }
+    case 'line':
+      return [{ points: [{ x: num('x1'), y: num('y1') }, { x: num('x2'), y: num('y2') }], closed: false }];
+    case 'polyline':
+    case 'polygon': {
+      const values = parseNumberList(a.points ?? '');
+      const points: Point[] = [];
+      for (let k = 0; k + 1 < values.length; k += 2) points.push({ x: values[k]!, y: values[k + 1]! });
+      return points.length > 1 ? [{ points, closed: localName(element.name) === 'polygon' }] : [];
+    }
+    default:
+      return [];
+  }
+}
+
+// ============================================
+// Stroking
+// ============================================
+
+/**
+ * Convert stroked polylines (device space) into polygons to fill with the
+ * nonzero rule. Every polygon is emitted with the same orientation so
+ * overlapping pieces union instead of cancelling.
+ */
+function strokePolygons(polylines: Polyline[], width: number, style: Style): Point[][] {
+  const half = width / 2;
+  const polygons: Point[][] = [];
+  const add = (polygon: Point[]): void => {
+    polygons.push(signedArea(polygon) < 0 ? polygon.reverse() : polygon);
+  };
+  const circle = (center: Point): void => {
+    add(ellipsePolyline(center.x, center.y, half, half, 1).points);
+  };
+
+  for (const polyline of polylines) {
+    const points = dedupe(polyline.points);
+    if (points.length === 1) {
+      // Zero-length subpaths only paint round and square caps
+      if (style.lineCap === 'round') circle(points[0]!);
+      if (style.lineCap === 'square') {
+        const p = points[0]!;
+        add([{ x: p.x - half, y: p.y - half }, { x: p.x + half, y: p.y - half }, { x: p.x + half, y: p.y + half }, { x: p.x - half, y: p.y + half }]);
+      }
+      continue;
+    }
+    const closed = polyline.closed && points.length > 2;
+    if (closed) points.push(points[0]!);
+
+    for (let k = 0; k + 1 < points.length; k++) {
+      let a = points[k]!;
+      let b = points[k + 1]!;
+      const length = distance(a, b);
+      const dx = (b.x - a.x) / length;
+      const dy = (b.y - a.y) / length;
+
+      if (!closed && style.lineCap === 'square') {
+        if (k === 0) a = { x: a.x - dx * half, y: a.y - dy * half };
+        if (k === points.length - 2) b = { x: b.x + dx * half, y: b.y + dy * half };
+      }
+      const nx = -dy * half;
+      const ny = dx * half;
+      add([
+        { x: a.x + nx, y: a.y + ny },
+        { x: b.x + nx, y: b.y + ny },
+        { x: b.x - nx, y: b.y - ny },
+        { x: a.x - nx, y: a.y - ny },
+      ]);
+    }
+
+    // Joins at interior vertices (and the closing vertex)
+    const first = closed ? 0 : 1;
+    const last = points.length - 2;
+    for (let k = first; k <= last; k++) {
+      const previous = k === 0 ? points[points.length - 2]! : points[k - 1]!;
+      const vertex = points[k]!;
+      const next = points[k + 1]!;
+      addJoin(previous, vertex, next, half, style, add, circle);
+    }
+
+    if (!closed && style.lineCap === 'round') {
+      circle(points[0]!);
+      circle(points[points.length - 1]!);
+    }
+  }
+
+  return polygons;
+}
+
+function addJoin(
+  previous: Point,
+  vertex: Point,
+  next: Point,
+  half: number,
+  style: Style,
+  add: (polygon: Point[]) => void,
+  circle: (center: Point) => void
+): void {
+  if (style.lineJoin === 'round') {
+    circle(vertex);
+    return;
+  }
+  const l1 = distance(previous, vertex);
+  const l2 = distance(vertex, next);
+  const d1 = { x: (vertex.x - previous.x) / l1, y: (vertex.y - previous.y) / l1 };
+  const d2 = { x: (next.x - vertex.x) / l2, y: (next.y - vertex.y) / l2 };
+  const cross = d1.x * d2.y - d1.y * d2.x;
+  if (Math.abs(cross) < 1e-9) return;
+
+  // Normals on the outer side of the turn
+  const side = cross > 0 ? -1 : 1;
+  const n1 = { x: -d1.y * side, y: d1.x * side };
+  const n2 = { x: -d2.y * side, y: d2.x * side };
+  const a = { x: vertex.x + n1.x * half, y: vertex.y + n1.y * half };
+  const b = { x: vertex.x + n2.x * half, y: vertex.y + n2.y * half };
+
+  const cosine = n1.x * n2.x + n1.y * n2.y;
+  const miterRatio = 2 / Math.sqrt(Math.max(1e-12, 2 * (1 + cosine)));
+  if (style.lineJoin === 'miter' && miterRatio <= style.miterLimit) {
+    const k = half / (1 + cosine);
+    const m = { x: vertex.x + (n1.x + n2.x) * k, y: vertex.y + (n1.y + n2.y) * k };
+    add([vertex, a, m, b]);
+  } else {
+    add([vertex, a, b]);
+  }
+}
+
+function dedupe(points: Point[]): Point[] {
+  const result: Point[] = [];
+  for (const p of points) {
+    const last = result[result.length - 1];
+    if (!last || Math.abs(last.x - p.x) > 1e-9 || Math.abs(last.y - p.y) > 1e-9) result.push(p);
+  }
+  return result;
+}
+
+function signedArea(polygon: Point[]): number {
+  let area = 0;
+  for (let k = 0; k < polygon.length; k++) {
+    const p = polygon[k]!;
+    const q = polygon[(k + 1) % polygon.length]!;
+    area += p.x * q.y - q.x * p.y;
+  }
+  return area / 2;
+}
+
+// ============================================
+// Rendering
+// ============================================
+
+class SvgRenderer {
+  constructor(
+    private canvas: Canvas,
+    private ids: Map<string, XmlElement>
+  ) {}
+
+  renderChildren(element: XmlElement, matrix: Matrix, style: Style, depth: number): void {
+    for (const child of element.children) {
+      this.renderElement(child, matrix, style, depth + 1);
+    }
+  }
+
+  private renderElement(element: XmlElement, parentMatrix: Matrix, parentStyle: Style, depth: number): void {
+    // Guards against <use> cycles
+    if (depth > 64) return;
+    const name = localName(element.name);
+    if (NON_RENDERED.has(name)) return;
+
+    const style = applyStyle(parentStyle, element.attributes, this.ids);
+    if (!style.display) return;
+    let matrix = multiply(parentMatrix, parseTransform(element.attributes.transform));
+
+    switch (name) {
+      case 'svg':
+      case 'g':
+      case 'a':
+      case 'switch':
+        if (name === 'svg') {
+          matrix = multiply(matrix, [1, 0, 0, 1, parseLength(element.attributes.x) ?? 0, parseLength(element.attributes.y) ?? 0]);
+        }
+        this.renderChildren(element, matrix, style, depth);
+        return;
+      case 'use': {
+        const href = element.attributes.href ?? element.attributes['xlink:href'];
+        const target = href?.startsWith('#') ? this.ids.get(href.slice(1)) : undefined;
+        if (!target) return;
+        matrix = multiply(matrix, [1, 0, 0, 1, parseLength(element.attributes.x) ?? 0, parseLength(element.attributes.y) ?? 0]);
+        if (localName(target.name) === 'symbol') {
+          this.renderChildren(target, matrix, applyStyle(style, target.attributes, this.ids), depth);
+        } else {
+          this.renderElement(target, matrix, style, depth + 1);
+        }
+        return;
+      }
+      default:
+        this.renderShape(element, matrix, style);
+    }
+  }
+
+  private renderShape(element: XmlElement, matrix: Matrix, style: Style): void {
+    const scale = Math.sqrt(Math.abs(matrix[0] * matrix[3] - matrix[1] * matrix[2])) || 1;
+    const geometry = shapeGeometry(element, scale);
+    if (geometry.length === 0) return;
+
+    const device = geometry.map((polyline) => ({
+      points: polyline.points.map((p) => applyMatrix(matrix, p)),
+      closed: polyline.closed,
+    }));
+
+    if (style.fill && localName(element.name) !== 'line') {
+      this.canvas.fill(
+        device.map((p) => p.points),
+        style.fillRule,
+        withAlpha(style.fill, style.fillOpacity * style.opacity)
+      );
+    }
+    if (style.stroke && style.strokeWidth > 0) {
+      this.canvas.fill(
+        strokePolygons(device, style.strokeWidth * scale, style),
+        'nonzero',
+        withAlpha(style.stroke, style.strokeOpacity * style.opacity)
+      );
+    }
+  }
+}
+
+/** Elements whose content is only rendered by reference (or not at all) */
+const NON_RENDERED = new Set([
+  'defs', 'symbol', 'clipPath', 'mask', 'pattern', 'marker', 'linearGradient', 'radialGradient',
+  'filter', 'style', 'script', 'title', 'desc', 'metadata', 'text', 'foreignObject', 'image',
+]);
+
+function withAlpha(paint: Paint, alpha: number): Paint {
+  return { ...paint, a: paint.a * alpha };
+}
+
+// ============================================
+// Canvas
+// ============================================
+
+/**
+ * RGBA canvas with anti-aliased scanline polygon filling.
+ */
+class Canvas {
+  readonly data: Uint8ClampedArray;
+  private coverage: Float32Array;
+
+  constructor(
+    readonly width: number,
+    readonly height: number
+  ) {
+    this.data = new Uint8ClampedArray(width * height * 4);
+    this.coverage = new Float32Array(width + 2);
+  }
+
+  /**
+   * Fill polygons (implicitly closed) with a color.
+   */
+  fill(polygons: Point[][], rule: 'nonzero' | 'evenodd', paint: Paint): void {
+    if (paint.a <= 0) return;
+
+    type Edge = { x0: number; y0: number; x1: number; y1: number; dir: number };
+    const edges: Edge[] = [];
+    let minY = Infinity;
+    let maxY = -Infinity;
+    for (const polygon of polygons) {
+      for (let k = 0; k < polygon.length; k++) {
+        const p = polygon[k]!;
+        const q = polygon[(k + 1) % polygon.length]!;
+        if (p.y === q.y) continue;
+        const dir = q.y > p.y ? 1 : -1;
+        const [top, bottom] = dir === 1 ? [p, q] : [q, p];
+        edges.push({ x0: top.x, y0: top.y, x1: bottom.x, y1: bottom.y, dir });
+        minY = Math.min(minY, top.y);
+        maxY = Math.max(maxY, bottom.y);
+      }
+    }
+    if (edges.length === 0) return;
+
+    const startRow = Math.max(0, Math.floor(minY));
+    const endRow = Math.min(this.height - 1, Math.ceil(maxY));
+    const crossings: Array<{ x: number; dir: number }> = [];
+
+    for (let row = startRow; row <= endRow; row++) {
+      this.coverage.fill(0);
+      let touched = false;
+
+      for (let s = 0; s < SUBSAMPLES; s++) {
+        const y = row + (s + 0.5) / SUBSAMPLES;
+        crossings.length = 0;
+        for (const e of edges) {
+          if (y < e.y0 || y >= e.y1) continue;
+          crossings.push({ x: e.x0 + ((y - e.y0) * (e.x1 - e.x0)) / (e.y1 - e.y0), dir: e.dir });
+        }
+        if (crossings.length < 2) continue;
+        crossings.sort((a, b) => a.x - b.x);
+
+        let winding = 0;
+        for (let k = 0; k < crossings.length - 1; k++) {
+          winding += rule === 'nonzero' ? crossings[k]!.dir : 1;
+          const inside = rule === 'nonzero' ? winding !== 0 : winding % 2 === 1;
+          if (inside) {
+            this.addSpan(crossings[k]!.x, crossings[k + 1]!.x);
+            touched = true;
+          }
+        }
+      }
+
+      if (touched) this.blendRow(row, paint);
+    }
+  }
+
+  /** Accumulate horizontal coverage of [x0, x1) for one sub-scanline */
+  private addSpan(x0: number, x1: number): void {
+    const left = Math.max(0, x0);
+    const right = Math.min(this.width, x1);
+    if (right <= left) return;
+    const first = Math.floor(left);
+    const last = Math.floor(right);
+    const coverage = this.coverage;
+    if (first === last) {
+      coverage[first] = coverage[first]! + right - left;
+      return;
+    }
+    coverage[first] = coverage[first]! + first + 1 - left;
+    for (let x = first + 1; x < last; x++) coverage[x] = coverage[x]! + 1;
+    if (last < this.width) coverage[last] = coverage[last]! + right - last;
+  }
+
+  private blendRow(row: number, paint: Paint): void {
+    for (let x = 0; x < this.width; x++) {
+      const cover = Math.min(1, this.coverage[x]! / SUBSAMPLES);
+      if (cover <= 0) continue;
+      const alpha = paint.a * cover;
+      const t = (row * this.width + x) * 4;
+      const dstAlpha = this.data[t + 3]! / 255;
+      const outAlpha = alpha + dstAlpha * (1 - alpha);
+      if (outAlpha <= 0) continue;
+      this.data[t] = (paint.r * alpha + this.data[t]! * dstAlpha * (1 - alpha)) / outAlpha;
+      this.data[t + 1] = (paint.g * alpha + this.data[t + 1]! * dstAlpha * (1 - alpha)) / outAlpha;
+      this.data[t + 2] = (paint.b * alpha + this.data[t + 2]! * dstAlpha * (1 - alpha)) / outAlpha;
+      this.data[t + 3] = outAlpha * 255;
+    }
+  }
+}
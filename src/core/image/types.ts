@@ -0,0 +1,77 @@
+/**
+ * Image Types
+ *
+ * Shared types for the in-process image decoders. Every decoder produces
+ * 8-bit RGBA pixels, row-major, top to bottom, so renderers don't need to
+ * know where an image came from.
+ */
+
+// ============================================
+// Types
+// ============================================
+
+export type ImageFormat = 'png' | 'jpeg' | 'gif' | 'svg';
+
+/**
+ * Format details shown by the image viewer.
+ */
+export interface ImageMetadata {
+  format: ImageFormat;
+  /** Intrinsic size in pixels */
+  width: number;
+  height: number;
+  /** Bits per sample (PNG, GIF palette bits, JPEG precision) */
+  bitDepth?: number;
+  /** Color model, e.g. "RGBA", "Indexed", "YCbCr", "Grayscale" */
+  colorType?: string;
+  hasAlpha: boolean;
+  interlaced?: boolean;
+  progressive?: boolean;
+  /** Number of frames (animated GIF); only the first frame is decoded */
+  frameCount?: number;
+}
+
+/**
+ * A decoded image: RGBA pixels plus metadata.
+ */
+export interface DecodedImage {
+  width: number;
+  height: number;
+  /** width * height * 4 bytes, non-premultiplied RGBA */
+  data: Uint8ClampedArray;
+  metadata: ImageMetadata;
+}
+
+// ============================================
+// Errors
+// ============================================
+
+/**
+ * Thrown when image data is malformed or uses an unsupported feature.
+ */
+export class ImageDecodeError extends Error {
+  constructor(
+    message: string,
+    public readonly format?: ImageFormat
+  ) {
+    super(format ? `${format.toUpperCase()}: ${message}` : message);
+    this.name = 'ImageDecodeError';
+  }
+}
+
+/**
+ * Largest image (in pixels) the decoders will allocate.
+ */
+export const MAX_IMAGE_PIXELS = 64 * 1024 * 1024;
+
+/**
+ * Validate dimensions read from a header.
+ */
+export function checkDimensions(width: number, height: number, format: ImageFormat): void {
+  if (width <= 0 || height <= 0) {
+    throw new ImageDecodeError(`invalid size ${width}x${height}`, format);
+  }
+  if (width * height > MAX_IMAGE_PIXELS) {
+    throw new ImageDecodeError(`image too large (${width}x${height})`, format);
+  }
+}
@@ -18,6 +18,11 @@ export type {
   NotificationListener,
   ECPServerOptions,
   ECPServerState,
+  ECPPeerInfo,
+  ECPClientCapabilities,
+  ECPServerCapabilities,
+  ECPInitializeParams,
+  ECPInitializeResult,
   Unsubscribe,
 } from './types.ts';
 
@@ -30,5 +35,13 @@ export {
   createNotification,
 } from './types.ts';
 
+// Handshake
+export {
+  ECP_PROTOCOL_VERSION,
+  ECP_MIN_PROTOCOL_VERSION,
+  negotiateProtocolVersion,
+  matchesNotificationFilter,
+} from './initialize.ts';
+
 // Server
 export { ECPServer, createECPServer } from './server.ts';
@@ -0,0 +1,49 @@
+/**
+ * ECP Initialize Handshake
+ *
+ * Protocol versions and helpers for negotiating them and filtering
+ * notifications by client capabilities.
+ *
+ * Versions:
+ * - 1: Original protocol. Clients that never send `initialize`, or send
+ *   it without a version, are treated as version 1.
+ * - 2: `initialize` handshake with client and server capabilities.
+ */
+
+// ============================================
+// Constants
+// ============================================
+
+/** Protocol version spoken by this server */
+export const ECP_PROTOCOL_VERSION = 2;
+
+/** Oldest protocol version this server still supports */
+export const ECP_MIN_PROTOCOL_VERSION = 1;
+
+// ============================================
+// Helpers
+// ============================================
+
+/**
+ * Pick the protocol version to use with a client: the lower of the
+ * client's and the server's. A client that doesn't state a version
+ * speaks the oldest one. Returns null if the client is too old.
+ */
+export function negotiateProtocolVersion(clientVersion: number | undefined): number | null {
+  if (clientVersion === undefined) return ECP_MIN_PROTOCOL_VERSION;
+  if (!Number.isInteger(clientVersion) || clientVersion < ECP_MIN_PROTOCOL_VERSION) return null;
+  return Math.min(clientVersion, ECP_PROTOCOL_VERSION);
+}
+
+/**
+ * Check a notification method against a client's filter (method names or
+ * `service/*` patterns). No filter matches everything.
+ */
+export function matchesNotificationFilter(method: string, filter: string[] | undefined): boolean {
+  if (!filter) return true;
+  return filter.some((pattern) => {
+    if (pattern === '*') return true;
+    if (pattern.endsWith('/*')) return method.startsWith(pattern.slice(0, -1));
+    return pattern === method;
+  });
+}
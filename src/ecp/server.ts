@@ -5,26 +5,39 @@
  */
 
 import { debugLog as globalDebugLog } from '../debug.ts';
+import packageJson from '../../package.json' with { type: 'json' };
 
 // Services
 import { LocalDocumentService } from '../services/document/local.ts';
-import { DocumentServiceAdapter } from '../services/document/adapter.ts';
+import { DocumentServiceAdapter, DOCUMENT_NOTIFICATIONS } from '../services/document/adapter.ts';
 import { FileServiceImpl } from '../services/file/service.ts';
-import { FileServiceAdapter } from '../services/file/adapter.ts';
+import { FileServiceAdapter, FILE_NOTIFICATIONS } from '../services/file/adapter.ts';
 import { GitCliService } from '../services/git/cli.ts';
 import { GitServiceAdapter } from '../services/git/adapter.ts';
 import { LocalSessionService } from '../services/session/local.ts';
 import { SessionServiceAdapter } from '../services/session/adapter.ts';
 import { LocalLSPService } from '../services/lsp/service.ts';
-import { LSPServiceAdapter } from '../services/lsp/adapter.ts';
+import { LSPServiceAdapter, LSP_NOTIFICATIONS } from '../services/lsp/adapter.ts';
 import { LocalSyntaxService } from '../services/syntax/service.ts';
 import { SyntaxServiceAdapter } from '../services/syntax/adapter.ts';
 import { LocalTerminalService } from '../services/terminal/service.ts';
-import { TerminalServiceAdapter } from '../services/terminal/adapter.ts';
+import { TerminalServiceAdapter, TERMINAL_NOTIFICATIONS } from '../services/terminal/adapter.ts';
 import { LocalSecretService } from '../services/secret/local.ts';
 import { SecretServiceAdapter } from '../services/secret/adapter.ts';
 import { LocalDatabaseService } from '../services/database/local.ts';
 import { DatabaseServiceAdapter } from '../services/database/adapter.ts';
+import { LocalNotificationService } from '../services/notification/local.ts';
+import { NotificationServiceAdapter, NOTIFICATION_NOTIFICATIONS } from '../services/notification/adapter.ts';
+import { LocalSearchService } from '../services/search/local.ts';
+import { SearchServiceAdapter } from '../services/search/adapter.ts';
+
+// Handshake
+import {
+  ECP_PROTOCOL_VERSION,
+  ECP_MIN_PROTOCOL_VERSION,
+  negotiateProtocolVersion,
+  matchesNotificationFilter,
+} from './initialize.ts';
 
 // Types
 import {
@@ -35,11 +48,38 @@ import {
   type NotificationListener,
   type Unsubscribe,
   type HandlerResult,
+  type ECPPeerInfo,
+  type ECPClientCapabilities,
+  type ECPServerCapabilities,
+  type ECPInitializeParams,
+  type ECPInitializeResult,
   ECPErrorCodes,
   createErrorResponse,
   createSuccessResponse,
 } from './types.ts';
 
+/** Server name and version reported in the initialize handshake */
+const SERVER_INFO: ECPPeerInfo = { name: 'ultra', version: packageJson.version };
+
+/**
+ * What the server supports, reported in the initialize handshake.
+ */
+const SERVER_CAPABILITIES: ECPServerCapabilities = {
+  services: [
+    'document', 'file', 'git', 'config', 'session', 'keybindings', 'theme',
+    'lsp', 'syntax', 'terminal', 'secret', 'database', 'notification', 'search',
+  ],
+  documentSync: { incremental: true, full: true },
+  // Everything the adapters wired up in setupNotificationHandlers() send
+  notifications: [
+    ...DOCUMENT_NOTIFICATIONS,
+    ...FILE_NOTIFICATIONS,
+    ...LSP_NOTIFICATIONS,
+    ...TERMINAL_NOTIFICATIONS,
+    ...NOTIFICATION_NOTIFICATIONS,
+  ],
+};
+
 /**
  * ECP Server.
  *
@@ -61,6 +101,8 @@ export class ECPServer {
   private terminalService: LocalTerminalService;
   private secretService: LocalSecretService;
   private databaseService: LocalDatabaseService;
+  private notificationService: LocalNotificationService;
+  private searchService: LocalSearchService;
 
   // Adapters
   private documentAdapter: DocumentServiceAdapter;
@@ -72,6 +114,8 @@ export class ECPServer {
   private terminalAdapter: TerminalServiceAdapter;
   private secretAdapter: SecretServiceAdapter;
   private databaseAdapter: DatabaseServiceAdapter;
+  private notificationAdapter: NotificationServiceAdapter;
+  private searchAdapter: SearchServiceAdapter;
 
   // Notification listeners
   private notificationListeners: Set<NotificationListener> = new Set();
@@ -79,6 +123,16 @@ export class ECPServer {
   // Request ID counter for internal requests
   private requestIdCounter = 0;
 
+  // Async service initialization (shared by repeated initialize calls)
+  private servicesReady: Promise<void> | null = null;
+
+  // Client from the initialize handshake (null for clients that skip it)
+  private client: {
+    info: ECPPeerInfo | null;
+    capabilities: ECPClientCapabilities;
+    protocolVersion: number;
+  } | null = null;
+
   constructor(options: ECPServerOptions = {}) {
     this.workspaceRoot = options.workspaceRoot ?? process.cwd();
 
@@ -104,6 +158,10 @@ export class ECPServer {
     this.terminalService = new LocalTerminalService();
     this.secretService = new LocalSecretService();
     this.databaseService = new LocalDatabaseService();
+    this.notificationService = new LocalNotificationService();
+    this.searchService = new LocalSearchService();
+    this.searchService.setWorkspaceRoot(this.workspaceRoot);
+    this.searchService.setDocumentService(this.documentService);
 
     // Initialize adapters
     this.documentAdapter = new DocumentServiceAdapter(this.documentService);
@@ -115,6 +173,8 @@ export class ECPServer {
     this.terminalAdapter = new TerminalServiceAdapter(this.terminalService);
     this.secretAdapter = new SecretServiceAdapter(this.secretService);
     this.databaseAdapter = new DatabaseServiceAdapter(this.databaseService);
+    this.notificationAdapter = new NotificationServiceAdapter(this.notificationService);
+    this.searchAdapter = new SearchServiceAdapter(this.searchService);
 
     // Set up notification forwarding
     this.setupNotificationHandlers();
@@ -214,14 +274,45 @@ export class ECPServer {
   }
 
   /**
-   * Initialize async services.
+   * Initialize async services and, when params are given, perform the
+   * handshake (also available as the `initialize` request).
    * Call this before using session-related methods.
+   *
+   * @param params Client version, info and capabilities
+   * @returns Negotiated protocol version and server capabilities
+   * @throws Error if the client's protocol version is unsupported or the
+   *   handshake was already done
    */
-  async initialize(): Promise<void> {
-    await this.sessionService.init(this.workspaceRoot);
-    await this.secretService.init();
-    await this.databaseService.init(this.workspaceRoot);
-    this.debugLog('Async initialization complete');
+  async initialize(params?: ECPInitializeParams): Promise<ECPInitializeResult> {
+    const response = await this.handleInitialize(params);
+
+    if ('error' in response) {
+      throw new Error(`ECP Error [${response.error.code}]: ${response.error.message}`);
+    }
+
+    return response.result as ECPInitializeResult;
+  }
+
+  /**
+   * Get the client's info from the handshake.
+   */
+  getClientInfo(): ECPPeerInfo | null {
+    return this.client?.info ?? null;
+  }
+
+  /**
+   * Get the client's capabilities from the handshake.
+   */
+  getClientCapabilities(): ECPClientCapabilities | null {
+    return this.client?.capabilities ?? null;
+  }
+
+  /**
+   * Get the negotiated protocol version. Clients that skip the handshake
+   * speak the oldest supported version.
+   */
+  getProtocolVersion(): number {
+    return this.client?.protocolVersion ?? ECP_MIN_PROTOCOL_VERSION;
   }
 
   /**
@@ -241,6 +332,9 @@ export class ECPServer {
       await this.documentService.close(doc.documentId);
     }
 
+    // Stop any running search
+    this.searchService.cancel();
+
     // Dispose file service resources
     this.fileService.dispose();
     this.fileAdapter.dispose();
@@ -270,7 +364,9 @@ export class ECPServer {
    * Use this sparingly - prefer using request() for most operations.
    */
   getService<T>(
-    name: 'document' | 'file' | 'git' | 'session' | 'lsp' | 'syntax' | 'terminal' | 'secret' | 'database'
+    name:
+      | 'document' | 'file' | 'git' | 'session' | 'lsp' | 'syntax' | 'terminal'
+      | 'secret' | 'database' | 'notification' | 'search'
   ): T {
     switch (name) {
       case 'document':
@@ -291,6 +387,10 @@ export class ECPServer {
         return this.secretService as unknown as T;
       case 'database':
         return this.databaseService as unknown as T;
+      case 'notification':
+        return this.notificationService as unknown as T;
+      case 'search':
+        return this.searchService as unknown as T;
       default:
         throw new Error(`Unknown service: ${name}`);
     }
@@ -307,6 +407,11 @@ export class ECPServer {
     method: string,
     params: unknown
   ): Promise<HandlerResult> {
+    // Handshake
+    if (method === 'initialize') {
+      return this.handleInitialize(params as ECPInitializeParams | undefined);
+    }
+
     // Document service
     if (method.startsWith('document/')) {
       return this.handleDocumentRequest(method, params);
@@ -357,6 +462,16 @@ export class ECPServer {
       return { result: await this.databaseAdapter.handleRequest(method, params) };
     }
 
+    // Notification service
+    if (method.startsWith('notification/')) {
+      return this.notificationAdapter.handleRequest(method, params);
+    }
+
+    // Search service
+    if (method.startsWith('search/')) {
+      return this.searchAdapter.handleRequest(method, params);
+    }
+
     // Method not found
     return {
       error: {
@@ -366,6 +481,64 @@ export class ECPServer {
     };
   }
 
+  /**
+   * Handle the initialize handshake. Without params only the services are
+   * initialized, keeping the client on the oldest protocol version.
+   */
+  private async handleInitialize(params: ECPInitializeParams | undefined): Promise<HandlerResult> {
+    if (params) {
+      if (this.client) {
+        return {
+          error: {
+            code: ECPErrorCodes.InvalidRequest,
+            message: 'Server is already initialized',
+          },
+        };
+      }
+
+      const protocolVersion = negotiateProtocolVersion(params.protocolVersion);
+      if (protocolVersion === null) {
+        return {
+          error: {
+            code: ECPErrorCodes.UnsupportedProtocolVersion,
+            message: `Unsupported protocol version: ${params.protocolVersion}`,
+            data: { min: ECP_MIN_PROTOCOL_VERSION, max: ECP_PROTOCOL_VERSION },
+          },
+        };
+      }
+
+      this.client = {
+        info: params.clientInfo ?? null,
+        capabilities: params.capabilities ?? {},
+        protocolVersion,
+      };
+      const name = params.clientInfo ? `${params.clientInfo.name} ${params.clientInfo.version ?? ''}`.trim() : 'unknown client';
+      this.debugLog(`Handshake with ${name} (protocol ${protocolVersion})`);
+    }
+
+    if (!this.servicesReady) {
+      this.servicesReady = this.initializeServices();
+    }
+    await this.servicesReady;
+
+    const result: ECPInitializeResult = {
+      protocolVersion: this.getProtocolVersion(),
+      serverInfo: SERVER_INFO,
+      capabilities: SERVER_CAPABILITIES,
+    };
+    return { result };
+  }
+
+  /**
+   * Initialize async services.
+   */
+  private async initializeServices(): Promise<void> {
+    await this.sessionService.init(this.workspaceRoot);
+    await this.secretService.init();
+    await this.databaseService.init(this.workspaceRoot);
+    this.debugLog('Async initialization complete');
+  }
+
   /**
    * Handle document service requests.
    * DocumentServiceAdapter has a different interface (takes full ECPRequest).
@@ -419,9 +592,15 @@ export class ECPServer {
    */
   private setupNotificationHandlers(): void {
     const forwardNotification = (notification: ECPNotification | { method: string; params: unknown }) => {
+      const capabilities = this.client?.capabilities;
+      if (!matchesNotificationFilter(notification.method, capabilities?.notifications)) {
+        return;
+      }
+
+      const params = this.adaptNotificationParams(notification.method, notification.params);
       for (const listener of this.notificationListeners) {
         try {
-          listener(notification.method, notification.params);
+          listener(notification.method, params);
         } catch (error) {
           this.debugLog(`Notification listener error: ${error}`);
         }
@@ -439,6 +618,23 @@ export class ECPServer {
 
     // Terminal adapter
     this.terminalAdapter.setNotificationHandler(forwardNotification);
+
+    // Notification adapter
+    this.notificationAdapter.setNotificationHandler(forwardNotification);
+  }
+
+  /**
+   * Add the full content to `document/didChange` for clients that can't
+   * apply incremental changes.
+   */
+  private adaptNotificationParams(method: string, params: unknown): unknown {
+    if (method !== 'document/didChange' || this.client?.capabilities.documentSync?.incremental !== false) {
+      return params;
+    }
+
+    const event = params as { documentId: string };
+    const content = this.documentService.getContent(event.documentId);
+    return content ? { ...event, content: content.content } : params;
   }
 }
 
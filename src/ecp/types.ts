@@ -75,6 +75,7 @@ export const ECPErrorCodes = {
   ServerError: -32000,
   ServerNotInitialized: -32001,
   ServerShuttingDown: -32002,
+  UnsupportedProtocolVersion: -32003,
 } as const;
 
 export type ECPErrorCode = (typeof ECPErrorCodes)[keyof typeof ECPErrorCodes];
@@ -127,6 +128,72 @@ export interface ECPServerOptions {
  */
 export type ECPServerState = 'uninitialized' | 'running' | 'shutdown';
 
+// ─────────────────────────────────────────────────────────────────────────────
+// Initialize Handshake
+// ─────────────────────────────────────────────────────────────────────────────
+
+/**
+ * Name and version of a client or server.
+ */
+export interface ECPPeerInfo {
+  name: string;
+  version?: string;
+}
+
+/**
+ * Features a client supports or wants.
+ */
+export interface ECPClientCapabilities {
+  documentSync?: {
+    /**
+     * Whether the client applies the `changes` in `document/didChange`.
+     * When false, notifications also carry the full `content`. Default true.
+     */
+    incremental?: boolean;
+  };
+  /**
+   * Notifications the client wants, as method names or `service/*`
+   * patterns. All notifications are sent when omitted.
+   */
+  notifications?: string[];
+}
+
+/**
+ * Features the server supports.
+ */
+export interface ECPServerCapabilities {
+  /** Method namespaces the server handles (e.g. `document`, `lsp`) */
+  services: string[];
+  documentSync: {
+    /** `document/didChange` carries incremental `changes` */
+    incremental: boolean;
+    /** `document/didChange` can carry the full `content` */
+    full: boolean;
+  };
+  /** Notifications the server may send */
+  notifications: string[];
+}
+
+/**
+ * Parameters of the `initialize` request.
+ */
+export interface ECPInitializeParams {
+  /** Highest protocol version the client speaks */
+  protocolVersion?: number;
+  clientInfo?: ECPPeerInfo;
+  capabilities?: ECPClientCapabilities;
+}
+
+/**
+ * Result of the `initialize` request.
+ */
+export interface ECPInitializeResult {
+  /** Protocol version both sides use: the lower of the client's and the server's */
+  protocolVersion: number;
+  serverInfo: ECPPeerInfo;
+  capabilities: ECPServerCapabilities;
+}
+
 // ─────────────────────────────────────────────────────────────────────────────
 // Utility Types
 // ─────────────────────────────────────────────────────────────────────────────
@@ -16,9 +16,11 @@ import type {
   InsertOptions,
   DeleteOptions,
   ReplaceOptions,
+  ApplyEditsOptions,
   MoveCursorsOptions,
   MoveDirection,
   MoveUnit,
+  PositionBias,
 } from './types.ts';
 
 /**
@@ -39,6 +41,16 @@ export const ECPErrorCodes = {
   InvalidRange: -32004,
 } as const;
 
+/**
+ * Notifications sent by this adapter.
+ */
+export const DOCUMENT_NOTIFICATIONS = [
+  'document/didChange',
+  'document/didChangeCursors',
+  'document/didOpen',
+  'document/didClose',
+] as const;
+
 /**
  * ECP error response.
  */
@@ -194,6 +206,8 @@ export class DocumentServiceAdapter {
         return this.handleDelete(params);
       case 'document/replace':
         return this.handleReplace(params);
+      case 'document/applyEdits':
+        return this.handleApplyEdits(params);
       case 'document/setContent':
         return this.handleSetContent(params);
 
@@ -238,6 +252,8 @@ export class DocumentServiceAdapter {
         return this.handleOffsetToPosition(params);
       case 'document/wordAtPosition':
         return this.handleWordAtPosition(params);
+      case 'document/transform':
+        return this.handleTransform(params);
 
       default:
         return {
@@ -438,6 +454,21 @@ export class DocumentServiceAdapter {
     return { result };
   }
 
+  private handleApplyEdits(params: unknown): HandlerResult<unknown> {
+    const p = params as { documentId: string; edits: ApplyEditsOptions['edits'] };
+    if (!p?.documentId || !Array.isArray(p.edits)) {
+      return { error: { code: ECPErrorCodes.InvalidParams, message: 'documentId and edits are required' } };
+    }
+
+    const options: ApplyEditsOptions = {
+      documentId: p.documentId,
+      edits: p.edits,
+    };
+
+    const result = this.service.applyEdits(options);
+    return { result };
+  }
+
   private handleSetContent(params: unknown): HandlerResult<unknown> {
     const p = params as { documentId: string; content: string };
     if (!p?.documentId || typeof p.content !== 'string') {
@@ -667,6 +698,44 @@ export class DocumentServiceAdapter {
     return { result: word };
   }
 
+  private handleTransform(params: unknown): HandlerResult<unknown> {
+    const p = params as {
+      documentId: string;
+      version: number;
+      positions?: Position[];
+      ranges?: Range[];
+      bias?: PositionBias;
+    };
+    if (!p?.documentId || typeof p.version !== 'number') {
+      return { error: { code: ECPErrorCodes.InvalidParams, message: 'documentId and version are required' } };
+    }
+
+    const version = this.service.getVersion(p.documentId);
+    if (version === null) {
+      return { error: { code: ECPErrorCodes.DocumentNotFound, message: 'Document not found' } };
+    }
+
+    const positions: Position[] = [];
+    for (const position of p.positions ?? []) {
+      const transformed = this.service.transformPosition(p.documentId, position, p.version, p.bias);
+      if (!transformed) {
+        return { error: { code: ECPErrorCodes.InvalidParams, message: `Version ${p.version} is outside the change history` } };
+      }
+      positions.push(transformed);
+    }
+
+    const ranges: Range[] = [];
+    for (const range of p.ranges ?? []) {
+      const transformed = this.service.transformRange(p.documentId, range, p.version);
+      if (!transformed) {
+        return { error: { code: ECPErrorCodes.InvalidParams, message: `Version ${p.version} is outside the change history` } };
+      }
+      ranges.push(transformed);
+    }
+
+    return { result: { version, positions, ranges } };
+  }
+
   // ─────────────────────────────────────────────────────────────────────────
   // Event Handlers
   // ─────────────────────────────────────────────────────────────────────────
@@ -693,7 +762,7 @@ export class DocumentServiceAdapter {
     });
   }
 
-  private sendNotification(method: string, params: unknown): void {
+  private sendNotification(method: (typeof DOCUMENT_NOTIFICATIONS)[number], params: unknown): void {
     if (this.notificationHandler) {
       this.notificationHandler({
         jsonrpc: '2.0',
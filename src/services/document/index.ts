@@ -19,6 +19,7 @@ export type {
   InsertOptions,
   DeleteOptions,
   ReplaceOptions,
+  ApplyEditsOptions,
   EditResult,
   SetCursorsOptions,
   MoveDirection,
@@ -27,6 +28,8 @@ export type {
   UndoRedoResult,
   DocumentContent,
   DocumentLine,
+  DocumentSnapshot,
+  PositionBias,
   DocumentChangeEvent,
   TextChange,
   CursorChangeEvent,
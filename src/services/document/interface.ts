@@ -20,9 +20,12 @@ import type {
   DocumentOpenResult,
   DocumentContent,
   DocumentLine,
+  DocumentSnapshot,
+  PositionBias,
   InsertOptions,
   DeleteOptions,
   ReplaceOptions,
+  ApplyEditsOptions,
   EditResult,
   SetCursorsOptions,
   MoveCursorsOptions,
@@ -147,6 +150,39 @@ export interface DocumentService {
    */
   getVersion(documentId: string): number | null;
 
+  /**
+   * Get an immutable snapshot of a document's current version.
+   * Snapshots are O(1) and can be read while the document is edited.
+   *
+   * @param documentId - Document ID
+   * @returns Snapshot or null if not found
+   */
+  getSnapshot(documentId: string): DocumentSnapshot | null;
+
+  /**
+   * Remap a position computed on an older version to the current version.
+   *
+   * @param documentId - Document ID
+   * @param position - Position in the older version
+   * @param fromVersion - Version the position refers to
+   * @param bias - Side to keep when text was inserted at the position (default 'right')
+   * @returns Current position, or null if the document is not found or the
+   *          version is outside the change history
+   */
+  transformPosition(documentId: string, position: Position, fromVersion: number, bias?: PositionBias): Position | null;
+
+  /**
+   * Remap a range computed on an older version to the current version.
+   * Text inserted at either edge stays outside the range.
+   *
+   * @param documentId - Document ID
+   * @param range - Range in the older version
+   * @param fromVersion - Version the range refers to
+   * @returns Current range, or null if the document is not found or the
+   *          version is outside the change history
+   */
+  transformRange(documentId: string, range: Range, fromVersion: number): Range | null;
+
   // ─────────────────────────────────────────────────────────────────────────
   // Text Editing
   // ─────────────────────────────────────────────────────────────────────────
@@ -175,6 +211,14 @@ export interface DocumentService {
    */
   replace(options: ReplaceOptions): EditResult;
 
+  /**
+   * Replace several ranges as a single undoable edit.
+   *
+   * @param options - Document and edits
+   * @returns Edit result with new version
+   */
+  applyEdits(options: ApplyEditsOptions): EditResult;
+
   /**
    * Set the full content of a document.
    * This creates a single undo action for the entire change.
@@ -7,6 +7,7 @@
  */
 
 import { Document } from '../../core/document.ts';
+import { transformPosition, transformRange } from '../../core/buffer.ts';
 import { debugLog } from '../../debug.ts';
 import type { DocumentService } from './interface.ts';
 import type {
@@ -18,9 +19,12 @@ import type {
   DocumentOpenResult,
   DocumentContent,
   DocumentLine,
+  DocumentSnapshot,
+  PositionBias,
   InsertOptions,
   DeleteOptions,
   ReplaceOptions,
+  ApplyEditsOptions,
   EditResult,
   SetCursorsOptions,
   MoveCursorsOptions,
@@ -276,11 +280,7 @@ export class LocalDocumentService implements DocumentService {
     const entry = this.documents.get(documentId);
     if (!entry) return null;
 
-    const startOffset = entry.document.buffer.positionToOffset(range.start);
-    const endOffset = entry.document.buffer.positionToOffset(range.end);
-    const content = entry.document.content;
-
-    return content.slice(startOffset, endOffset);
+    return entry.document.buffer.getRangeByPosition(range.start, range.end);
   }
 
   getVersion(documentId: string): number | null {
@@ -289,6 +289,43 @@ export class LocalDocumentService implements DocumentService {
     return entry.document.version;
   }
 
+  getSnapshot(documentId: string): DocumentSnapshot | null {
+    const entry = this.documents.get(documentId);
+    if (!entry) return null;
+
+    const snapshot = entry.document.snapshot();
+    return {
+      documentId: entry.id,
+      uri: entry.uri,
+      version: snapshot.version,
+      lineCount: snapshot.lineCount,
+      length: snapshot.length,
+      getLine: (lineNumber) => snapshot.getLine(lineNumber),
+      getText: (range) => range
+        ? snapshot.getRangeByPosition(range.start, range.end)
+        : snapshot.getContent(),
+      positionToOffset: (position) => snapshot.positionToOffset(position),
+      offsetToPosition: (offset) => snapshot.offsetToPosition(offset),
+    };
+  }
+
+  transformPosition(
+    documentId: string,
+    position: Position,
+    fromVersion: number,
+    bias: PositionBias = 'right'
+  ): Position | null {
+    const changes = this.documents.get(documentId)?.document.getChangesSince(fromVersion);
+    if (!changes) return null;
+    return transformPosition(position, changes, bias);
+  }
+
+  transformRange(documentId: string, range: Range, fromVersion: number): Range | null {
+    const changes = this.documents.get(documentId)?.document.getChangesSince(fromVersion);
+    if (!changes) return null;
+    return transformRange(range, changes);
+  }
+
   // ─────────────────────────────────────────────────────────────────────────
   // Text Editing
   // ─────────────────────────────────────────────────────────────────────────
@@ -364,6 +401,28 @@ export class LocalDocumentService implements DocumentService {
     return { success: true, version: doc.version };
   }
 
+  applyEdits(options: ApplyEditsOptions): EditResult {
+    const { documentId, edits } = options;
+    const entry = this.documents.get(documentId);
+
+    if (!entry) {
+      return { success: false, version: 0, error: 'Document not found' };
+    }
+
+    if (entry.isReadOnly) {
+      return { success: false, version: entry.document.version, error: 'Document is read-only' };
+    }
+
+    const doc = entry.document;
+    const versionBefore = doc.version;
+
+    doc.applyEdits(edits);
+
+    this.notifyContentChange(entry, versionBefore);
+
+    return { success: true, version: doc.version };
+  }
+
   setContent(documentId: string, content: string): EditResult {
     const entry = this.documents.get(documentId);
 
@@ -378,10 +437,26 @@ export class LocalDocumentService implements DocumentService {
     const doc = entry.document;
     const versionBefore = doc.version;
 
-    // Select all and replace
-    doc.cursorManager.setPosition({ line: 0, column: 0 });
-    doc.selectAll();
-    doc.insert(content);
+    // Replace only the span between the common prefix and suffix, so
+    // positions outside it can still be remapped from older versions
+    const current = doc.buffer.getContent();
+    let start = 0;
+    const maxPrefix = Math.min(current.length, content.length);
+    while (start < maxPrefix && current[start] === content[start]) start++;
+    let end = 0;
+    const maxSuffix = maxPrefix - start;
+    while (end < maxSuffix && current[current.length - 1 - end] === content[content.length - 1 - end]) end++;
+    if (start === current.length && start === content.length) {
+      return { success: true, version: doc.version };
+    }
+
+    doc.applyEdits([{
+      range: {
+        start: doc.buffer.offsetToPosition(start),
+        end: doc.buffer.offsetToPosition(current.length - end),
+      },
+      text: content.slice(start, content.length - end),
+    }]);
 
     this.notifyContentChange(entry, versionBefore);
 
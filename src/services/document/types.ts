@@ -178,6 +178,17 @@ export interface ReplaceOptions {
   groupWithPrevious?: boolean;
 }
 
+/**
+ * Options for replacing several ranges as one undo step.
+ */
+export interface ApplyEditsOptions {
+  /** Document ID */
+  documentId: string;
+
+  /** Non-overlapping edits, with ranges in the current content */
+  edits: { range: Range; text: string }[];
+}
+
 /**
  * Result of an edit operation.
  */
@@ -261,6 +272,47 @@ export interface DocumentContent {
   lineCount: number;
 }
 
+/**
+ * Immutable view of a document at one version.
+ * Cheap to create; reads are unaffected by later edits, so background
+ * work (syntax, LSP sync, search, auto-save) can hold one without copying
+ * the whole content.
+ */
+export interface DocumentSnapshot {
+  /** Document ID */
+  documentId: string;
+
+  /** Document URI */
+  uri: string;
+
+  /** Document version this snapshot was taken at */
+  version: number;
+
+  /** Line count */
+  lineCount: number;
+
+  /** Content length */
+  length: number;
+
+  /** Get a line (without line ending), or '' if out of range */
+  getLine(lineNumber: number): string;
+
+  /** Get text in a range, or the whole content if no range is given */
+  getText(range?: Range): string;
+
+  /** Convert a position to an absolute offset */
+  positionToOffset(position: Position): number;
+
+  /** Convert an absolute offset to a position */
+  offsetToPosition(offset: number): Position;
+}
+
+/**
+ * Which side of an insertion a position at the insertion point sticks to
+ * when remapped to a newer version.
+ */
+export type PositionBias = 'left' | 'right';
+
 /**
  * A line of text with its line number.
  */
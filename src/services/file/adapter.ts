@@ -44,6 +44,15 @@ export const ECPErrorCodes = {
   IOError: -32109,
 } as const;
 
+/**
+ * Notifications sent by this adapter.
+ */
+export const FILE_NOTIFICATIONS = [
+  'file/didChange',
+  'file/didCreate',
+  'file/didDelete',
+] as const;
+
 /**
  * ECP error response.
  */
@@ -591,7 +600,7 @@ export class FileServiceAdapter {
     });
   }
 
-  private sendNotification(method: string, params: unknown): void {
+  private sendNotification(method: (typeof FILE_NOTIFICATIONS)[number], params: unknown): void {
     if (this.notificationHandler) {
       this.notificationHandler({
         jsonrpc: '2.0',
@@ -52,6 +52,15 @@ export interface FileProvider {
    */
   read(uri: string): Promise<FileContent>;
 
+  /**
+   * Read raw file bytes (for binary files such as images).
+   *
+   * @param uri - File URI
+   * @returns File bytes
+   * @throws FileError if file doesn't exist or can't be read
+   */
+  readBytes(uri: string): Promise<Uint8Array>;
+
   /**
    * Write content to a file.
    *
@@ -226,6 +235,14 @@ export interface FileService {
    */
   read(uri: string): Promise<FileContent>;
 
+  /**
+   * Read raw file bytes.
+   *
+   * @param uri - File URI
+   * @returns File bytes
+   */
+  readBytes(uri: string): Promise<Uint8Array>;
+
   /**
    * Write content to a file.
    *
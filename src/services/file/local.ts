@@ -112,6 +112,25 @@ export class LocalFileProvider implements FileProvider {
     }
   }
 
+  async readBytes(uri: string): Promise<Uint8Array> {
+    const filePath = uriToPath(uri);
+
+    try {
+      if (!await pathExists(filePath)) {
+        throw FileError.notFound(uri);
+      }
+
+      const stats = await fsStat(filePath);
+      if (stats.isDirectory()) {
+        throw FileError.isDirectory(uri);
+      }
+
+      return new Uint8Array(await Bun.file(filePath).arrayBuffer());
+    } catch (error) {
+      throw FileError.wrap(uri, error);
+    }
+  }
+
   async write(uri: string, content: string, options?: WriteOptions): Promise<WriteResult> {
     const filePath = uriToPath(uri);
 
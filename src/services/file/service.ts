@@ -96,6 +96,11 @@ export class FileServiceImpl implements FileService {
     return provider.read(uri);
   }
 
+  async readBytes(uri: string): Promise<Uint8Array> {
+    const provider = this.requireProvider(uri);
+    return provider.readBytes(uri);
+  }
+
   async write(uri: string, content: string, options?: WriteOptions): Promise<WriteResult> {
     const provider = this.requireProvider(uri);
     return provider.write(uri, content, options);
@@ -6,7 +6,14 @@
 
 import type { LSPService } from './interface.ts';
 import { LSPError } from './errors.ts';
-import type { LSPPosition, LSPDiagnostic } from './types.ts';
+import type {
+  LSPPosition,
+  LSPRange,
+  LSPDiagnostic,
+  LSPCompletionItem,
+  LSPDocumentLink,
+  LSPColor,
+} from './types.ts';
 
 /**
  * ECP error codes (JSON-RPC 2.0 compatible).
@@ -30,6 +37,16 @@ export const LSPECPErrorCodes = {
   Disabled: -32409,
 } as const;
 
+/**
+ * Notifications sent by this adapter.
+ */
+export const LSP_NOTIFICATIONS = [
+  'lsp/didPublishDiagnostics',
+  'lsp/serverStatusChanged',
+  'lsp/progress',
+  'lsp/serverMessage',
+] as const;
+
 /**
  * JSON-RPC error response.
  */
@@ -67,6 +84,14 @@ export class LSPServiceAdapter {
     this.service.onServerStatusChange((status) => {
       this.emitNotification('lsp/serverStatusChanged', status);
     });
+
+    // Forward server progress and messages
+    this.service.onProgress((progress) => {
+      this.emitNotification('lsp/progress', progress);
+    });
+    this.service.onServerMessage((message) => {
+      this.emitNotification('lsp/serverMessage', message);
+    });
   }
 
   /**
@@ -93,6 +118,8 @@ export class LSPServiceAdapter {
           return await this.lspStop(params);
         case 'lsp/status':
           return this.lspStatus(params);
+        case 'lsp/discover':
+          return await this.lspDiscover(params);
 
         // Document sync
         case 'lsp/documentOpen':
@@ -107,6 +134,8 @@ export class LSPServiceAdapter {
         // Code intelligence
         case 'lsp/completion':
           return await this.completion(params);
+        case 'lsp/completionResolve':
+          return await this.completionResolve(params);
         case 'lsp/hover':
           return await this.hover(params);
         case 'lsp/signatureHelp':
@@ -117,6 +146,16 @@ export class LSPServiceAdapter {
           return await this.references(params);
         case 'lsp/documentSymbol':
           return await this.documentSymbol(params);
+        case 'lsp/foldingRange':
+          return await this.foldingRange(params);
+        case 'lsp/documentLink':
+          return await this.documentLink(params);
+        case 'lsp/documentLinkResolve':
+          return await this.documentLinkResolve(params);
+        case 'lsp/documentColor':
+          return await this.documentColor(params);
+        case 'lsp/colorPresentation':
+          return await this.colorPresentation(params);
         case 'lsp/rename':
           return await this.rename(params);
 
@@ -127,6 +166,8 @@ export class LSPServiceAdapter {
           return this.allDiagnostics();
         case 'lsp/diagnosticsSummary':
           return this.diagnosticsSummary();
+        case 'lsp/pullDiagnostics':
+          return await this.pullDiagnostics(params);
 
         // Configuration
         case 'lsp/setServerConfig':
@@ -138,6 +179,10 @@ export class LSPServiceAdapter {
         case 'lsp/hasServerFor':
           return this.hasServerFor(params);
 
+        // Tracing
+        case 'lsp/trace':
+          return this.trace(params);
+
         default:
           return {
             error: {
@@ -156,7 +201,7 @@ export class LSPServiceAdapter {
   // ─────────────────────────────────────────────────────────────────────────
 
   private async lspStart(params: unknown): Promise<HandlerResult<unknown>> {
-    const p = params as { languageId: string; workspaceUri: string };
+    const p = params as { languageId: string; workspaceUri: string; documentUri?: string };
     if (!p?.languageId || !p?.workspaceUri) {
       return {
         error: {
@@ -166,7 +211,7 @@ export class LSPServiceAdapter {
       };
     }
 
-    const info = await this.service.startServer(p.languageId, p.workspaceUri);
+    const info = await this.service.startServer(p.languageId, p.workspaceUri, p.documentUri);
     return { result: { success: true, ...info } };
   }
 
@@ -188,6 +233,23 @@ export class LSPServiceAdapter {
     return { result: { servers } };
   }
 
+  private async lspDiscover(params: unknown): Promise<HandlerResult<unknown>> {
+    const p = params as { languageId: string; documentUri?: string };
+    if (!p?.languageId) {
+      return {
+        error: { code: LSPECPErrorCodes.InvalidParams, message: 'languageId is required' },
+      };
+    }
+
+    const discovery = await this.service.discoverServer(p.languageId, p.documentUri);
+    if (!discovery) {
+      return {
+        error: { code: LSPECPErrorCodes.ServerNotFound, message: `No language server configured for: ${p.languageId}` },
+      };
+    }
+    return { result: discovery };
+  }
+
   // ─────────────────────────────────────────────────────────────────────────
   // Document sync handlers
   // ─────────────────────────────────────────────────────────────────────────
@@ -255,6 +317,18 @@ export class LSPServiceAdapter {
     return { result: { items } };
   }
 
+  private async completionResolve(params: unknown): Promise<HandlerResult<unknown>> {
+    const p = params as { uri: string; item: LSPCompletionItem };
+    if (!p?.uri || !p?.item) {
+      return {
+        error: { code: LSPECPErrorCodes.InvalidParams, message: 'uri and item are required' },
+      };
+    }
+
+    const item = await this.service.resolveCompletionItem(p.uri, p.item);
+    return { result: item };
+  }
+
   private async hover(params: unknown): Promise<HandlerResult<unknown>> {
     const p = params as { uri: string; position: LSPPosition };
     if (!p?.uri || !p?.position) {
@@ -317,6 +391,61 @@ export class LSPServiceAdapter {
     return { result: { symbols } };
   }
 
+  private async foldingRange(params: unknown): Promise<HandlerResult<unknown>> {
+    const p = params as { uri: string };
+    if (!p?.uri) {
+      return { error: { code: LSPECPErrorCodes.InvalidParams, message: 'uri is required' } };
+    }
+
+    const ranges = await this.service.getFoldingRanges(p.uri);
+    return { result: { ranges } };
+  }
+
+  private async documentLink(params: unknown): Promise<HandlerResult<unknown>> {
+    const p = params as { uri: string };
+    if (!p?.uri) {
+      return { error: { code: LSPECPErrorCodes.InvalidParams, message: 'uri is required' } };
+    }
+
+    const links = await this.service.getDocumentLinks(p.uri);
+    return { result: { links } };
+  }
+
+  private async documentLinkResolve(params: unknown): Promise<HandlerResult<unknown>> {
+    const p = params as { uri: string; link: LSPDocumentLink };
+    if (!p?.uri || !p?.link) {
+      return { error: { code: LSPECPErrorCodes.InvalidParams, message: 'uri and link are required' } };
+    }
+
+    const link = await this.service.resolveDocumentLink(p.uri, p.link);
+    return { result: { link } };
+  }
+
+  private async documentColor(params: unknown): Promise<HandlerResult<unknown>> {
+    const p = params as { uri: string };
+    if (!p?.uri) {
+      return { error: { code: LSPECPErrorCodes.InvalidParams, message: 'uri is required' } };
+    }
+
+    const colors = await this.service.getDocumentColors(p.uri);
+    return { result: { colors } };
+  }
+
+  private async colorPresentation(params: unknown): Promise<HandlerResult<unknown>> {
+    const p = params as { uri: string; color: LSPColor; range: LSPRange };
+    if (!p?.uri || !p?.color || !p?.range) {
+      return {
+        error: {
+          code: LSPECPErrorCodes.InvalidParams,
+          message: 'uri, color, and range are required',
+        },
+      };
+    }
+
+    const presentations = await this.service.getColorPresentations(p.uri, p.color, p.range);
+    return { result: { presentations } };
+  }
+
   private async rename(params: unknown): Promise<HandlerResult<unknown>> {
     const p = params as { uri: string; position: LSPPosition; newName: string };
     if (!p?.uri || !p?.position || !p?.newName) {
@@ -360,6 +489,18 @@ export class LSPServiceAdapter {
     return { result: summary };
   }
 
+  private async pullDiagnostics(params: unknown): Promise<HandlerResult<{ success: boolean }>> {
+    const p = params as { uri?: string; languageId?: string } | undefined;
+
+    // With a URI, pull that document; otherwise pull workspace diagnostics
+    if (p?.uri) {
+      await this.service.pullDiagnostics(p.uri);
+    } else {
+      await this.service.pullWorkspaceDiagnostics(p?.languageId);
+    }
+    return { result: { success: true } };
+  }
+
   // ─────────────────────────────────────────────────────────────────────────
   // Configuration handlers
   // ─────────────────────────────────────────────────────────────────────────
@@ -413,11 +554,24 @@ export class LSPServiceAdapter {
     return { result: { available } };
   }
 
+  // ─────────────────────────────────────────────────────────────────────────
+  // Tracing handlers
+  // ─────────────────────────────────────────────────────────────────────────
+
+  private trace(params: unknown): HandlerResult<unknown> {
+    const p = params as { languageId?: string } | undefined;
+    if (!p?.languageId) {
+      return { result: { servers: this.service.getTracedServers() } };
+    }
+
+    return { result: { entries: this.service.getTrace(p.languageId) } };
+  }
+
   // ─────────────────────────────────────────────────────────────────────────
   // Helpers
   // ─────────────────────────────────────────────────────────────────────────
 
-  private emitNotification(method: string, params: unknown): void {
+  private emitNotification(method: (typeof LSP_NOTIFICATIONS)[number], params: unknown): void {
     if (this.notificationHandler) {
       this.notificationHandler({ method, params });
     }
@@ -7,6 +7,7 @@
 
 import type { Subprocess } from 'bun';
 import { isDebugEnabled } from '../../debug.ts';
+import { LSPTraceLog } from './trace.ts';
 
 // LSP Types
 export interface LSPPosition {
@@ -32,19 +33,79 @@ export interface LSPDiagnostic {
   code?: string | number;
 }
 
+export interface LSPTextEdit {
+  range: LSPRange;
+  newText: string;
+}
+
 export interface LSPCompletionItem {
   label: string;
   kind?: number;
   detail?: string;
   documentation?: string | { kind: string; value: string };
+  sortText?: string;
+  filterText?: string;
   insertText?: string;
   insertTextFormat?: number;
-  textEdit?: {
-    range: LSPRange;
-    newText: string;
+  textEdit?: LSPTextEdit;
+  /** Text for the default edit range (used with list itemDefaults) */
+  textEditText?: string;
+  /** Edits applied alongside the main edit (e.g. auto-imports) */
+  additionalTextEdits?: LSPTextEdit[];
+  /** Characters that accept the item when typed */
+  commitCharacters?: string[];
+  /** Opaque server data, preserved for completionItem/resolve */
+  data?: unknown;
+}
+
+export interface LSPCompletionList {
+  isIncomplete?: boolean;
+  items: LSPCompletionItem[];
+  /** Defaults for fields omitted from items (LSP 3.17) */
+  itemDefaults?: {
+    commitCharacters?: string[];
+    editRange?: LSPRange | { insert: LSPRange; replace: LSPRange };
+    data?: unknown;
   };
 }
 
+/**
+ * Pull diagnostics report for one document (LSP 3.17).
+ * 'unchanged' means the diagnostics for previousResultId are still current.
+ */
+export interface LSPDocumentDiagnosticReport {
+  kind: 'full' | 'unchanged';
+  resultId?: string;
+  items?: LSPDiagnostic[];
+  /** Reports for other documents affected by this one */
+  relatedDocuments?: Record<string, { kind: 'full' | 'unchanged'; resultId?: string; items?: LSPDiagnostic[] }>;
+}
+
+/**
+ * Pull diagnostics report for the whole workspace.
+ */
+export interface LSPWorkspaceDiagnosticReport {
+  items: Array<{
+    uri: string;
+    /** Document version the report is for (null for unopened files) */
+    version: number | null;
+    kind: 'full' | 'unchanged';
+    resultId?: string;
+    items?: LSPDiagnostic[];
+  }>;
+}
+
+/**
+ * Server options for pull diagnostics.
+ */
+export interface LSPDiagnosticOptions {
+  identifier?: string;
+  /** Diagnostics of one document can change when another changes */
+  interFileDependencies: boolean;
+  /** Server supports workspace/diagnostic */
+  workspaceDiagnostics: boolean;
+}
+
 export interface LSPHover {
   contents: string | { kind: string; value: string } | Array<string | { kind: string; value: string }>;
   range?: LSPRange;
@@ -113,6 +174,43 @@ export interface LSPSymbolInformation {
   containerName?: string;
 }
 
+export interface LSPFoldingRange {
+  startLine: number;
+  endLine: number;
+  startCharacter?: number;
+  endCharacter?: number;
+  /** 'comment', 'imports' or 'region' */
+  kind?: string;
+}
+
+export interface LSPDocumentLink {
+  range: LSPRange;
+  /** Link target URI (may be filled in by documentLink/resolve) */
+  target?: string;
+  tooltip?: string;
+  data?: unknown;
+}
+
+/** RGBA colour with channels in [0, 1] */
+export interface LSPColor {
+  red: number;
+  green: number;
+  blue: number;
+  alpha: number;
+}
+
+export interface LSPColorInformation {
+  range: LSPRange;
+  color: LSPColor;
+}
+
+export interface LSPColorPresentation {
+  /** Text of the presentation, e.g. "#ff0000" or "rgb(255, 0, 0)" */
+  label: string;
+  textEdit?: LSPTextEdit;
+  additionalTextEdits?: LSPTextEdit[];
+}
+
 export interface LSPTextDocumentIdentifier {
   uri: string;
 }
@@ -157,6 +255,20 @@ type JSONRPCMessage = JSONRPCRequest | JSONRPCNotification | JSONRPCResponse;
 
 export type NotificationHandler = (method: string, params: unknown) => void;
 
+/**
+ * Handler for server-to-client requests.
+ * Return a promise to answer the request, or undefined to use the default response.
+ */
+export type ServerRequestHandler = (method: string, params: unknown) => Promise<unknown> | undefined;
+
+/**
+ * Working directory and extra environment for the server process.
+ */
+export interface LSPSpawnOptions {
+  cwd?: string;
+  env?: Record<string, string>;
+}
+
 /**
  * LSP Client for a single language server
  */
@@ -173,7 +285,9 @@ export class LSPClient {
   private initialized = false;
   private workspaceRoot: string;
   private notificationHandler: NotificationHandler | null = null;
+  private serverRequestHandler: ServerRequestHandler | null = null;
   private serverCapabilities: Record<string, unknown> = {};
+  private trace = new LSPTraceLog();
   // Debug logging is controlled globally via --debug flag
 
   /**
@@ -211,9 +325,14 @@ export class LSPClient {
   constructor(
     private command: string,
     private args: string[],
-    workspaceRoot: string
+    workspaceRoot: string,
+    trace?: LSPTraceLog,
+    private spawnOptions: LSPSpawnOptions = {}
   ) {
     this.workspaceRoot = workspaceRoot;
+    if (trace) {
+      this.trace = trace;
+    }
   }
 
   private debugLog(msg: string): void {
@@ -235,6 +354,20 @@ export class LSPClient {
     this.notificationHandler = handler;
   }
 
+  /**
+   * Set handler for server requests (e.g. window/showMessageRequest)
+   */
+  onServerRequest(handler: ServerRequestHandler): void {
+    this.serverRequestHandler = handler;
+  }
+
+  /**
+   * Get the JSON-RPC trace log for this server
+   */
+  getTrace(): LSPTraceLog {
+    return this.trace;
+  }
+
   /**
    * Start the language server and initialize
    */
@@ -244,6 +377,8 @@ export class LSPClient {
       this.debugLog(`Workspace root: ${this.workspaceRoot}`);
       
       this.process = Bun.spawn([this.command, ...this.args], {
+        cwd: this.spawnOptions.cwd,
+        env: this.spawnOptions.env ? { ...process.env, ...this.spawnOptions.env } : undefined,
         stdin: 'pipe',
         stdout: 'pipe',
         stderr: 'pipe',
@@ -276,7 +411,14 @@ export class LSPClient {
             completion: {
               completionItem: {
                 snippetSupport: false,
+                commitCharactersSupport: true,
                 documentationFormat: ['plaintext', 'markdown'],
+                resolveSupport: {
+                  properties: ['documentation', 'detail', 'additionalTextEdits'],
+                },
+              },
+              completionList: {
+                itemDefaults: ['commitCharacters', 'editRange', 'data'],
               },
             },
             hover: {
@@ -288,9 +430,29 @@ export class LSPClient {
             publishDiagnostics: {
               relatedInformation: true,
             },
+            diagnostic: {
+              dynamicRegistration: false,
+              relatedDocumentSupport: true,
+            },
+            foldingRange: {
+              lineFoldingOnly: true,
+            },
+            documentLink: {
+              tooltipSupport: true,
+            },
+            colorProvider: {},
           },
           workspace: {
             workspaceFolders: true,
+            diagnostics: {
+              refreshSupport: true,
+            },
+          },
+          window: {
+            workDoneProgress: true,
+            showMessage: {
+              messageActionItem: { additionalPropertiesSupport: false },
+            },
           },
         },
         workspaceFolders: [
@@ -333,6 +495,7 @@ export class LSPClient {
         const text = decoder.decode(value, { stream: true });
         if (text.trim()) {
           this.debugLog(`stderr: ${text.trim()}`);
+          this.trace.recordStderr(text);
         }
       }
     } catch (error) {
@@ -451,7 +614,8 @@ export class LSPClient {
    */
   private handleMessage(msg: JSONRPCMessage): void {
     this.debugLog(`handleMessage: ${JSON.stringify(msg).substring(0, 200)}`);
-    
+    this.trace.recordMessage('receive', msg);
+
     if ('id' in msg && msg.id !== undefined) {
       if ('method' in msg) {
         // Server request (we need to respond)
@@ -481,6 +645,15 @@ export class LSPClient {
    * Handle server request (respond to it)
    */
   private handleServerRequest(request: JSONRPCRequest): void {
+    // Let the owner answer requests that need user interaction
+    const handled = this.serverRequestHandler?.(request.method, request.params);
+    if (handled !== undefined) {
+      handled
+        .then((value) => this.sendResponse(request.id, value ?? null))
+        .catch((error) => this.sendResponse(request.id, null, { code: -32603, message: String(error) }));
+      return;
+    }
+
     // Handle common server requests
     let result: unknown = null;
 
@@ -547,6 +720,7 @@ export class LSPClient {
         if (this.pending.has(id)) {
           this.pending.delete(id);
           this.debugLog(`request[${id}]: TIMEOUT after 30s`);
+          this.trace.forgetRequest(id);
           reject(new Error(`LSP request '${method}' timed out`));
         }
       }, 30000);
@@ -575,6 +749,8 @@ export class LSPClient {
     const stdin = this.process.stdin;
     if (typeof stdin === 'number') return;  // Not a writable stream
 
+    this.trace.recordMessage('send', message);
+
     const content = JSON.stringify(message);
     const header = `Content-Length: ${Buffer.byteLength(content)}\r\n\r\n`;
     
@@ -666,7 +842,7 @@ export class LSPClient {
    */
   async getCompletions(uri: string, position: LSPPosition): Promise<LSPCompletionItem[]> {
     try {
-      const result = await this.request<{ items: LSPCompletionItem[] } | LSPCompletionItem[] | null>(
+      const result = await this.request<LSPCompletionList | LSPCompletionItem[] | null>(
         'textDocument/completion',
         {
           textDocument: { uri },
@@ -675,14 +851,47 @@ export class LSPClient {
       );
 
       if (!result) return [];
-      if (Array.isArray(result)) return result;
-      return result.items || [];
+      const items = Array.isArray(result) ? result : result.items || [];
+      const defaults = Array.isArray(result) ? undefined : result.itemDefaults;
+      const provider = this.serverCapabilities.completionProvider as
+        | { allCommitCharacters?: string[] }
+        | undefined;
+      const commitCharacters = defaults?.commitCharacters ?? provider?.allCommitCharacters;
+
+      // Fill in list-level defaults so callers only deal with items
+      return items.map((item) => {
+        const filled = { ...item };
+        if (!filled.commitCharacters && commitCharacters) filled.commitCharacters = commitCharacters;
+        if (filled.data === undefined && defaults?.data !== undefined) filled.data = defaults.data;
+        if (!filled.textEdit && defaults?.editRange) {
+          const range = 'insert' in defaults.editRange ? defaults.editRange.insert : defaults.editRange;
+          filled.textEdit = { range, newText: filled.textEditText ?? filled.insertText ?? filled.label };
+        }
+        return filled;
+      });
     } catch (error) {
       this.debugLog(`getCompletions error: ${error}`);
       return [];
     }
   }
 
+  /**
+   * Resolve additional details (documentation, auto-import edits) for a completion item.
+   * Returns the item unchanged if the server doesn't support resolve.
+   */
+  async resolveCompletionItem(item: LSPCompletionItem): Promise<LSPCompletionItem> {
+    const provider = this.serverCapabilities.completionProvider as { resolveProvider?: boolean } | undefined;
+    if (!provider?.resolveProvider) return item;
+
+    try {
+      const resolved = await this.request<LSPCompletionItem | null>('completionItem/resolve', item);
+      return resolved ? { ...item, ...resolved } : item;
+    } catch (error) {
+      this.debugLog(`resolveCompletionItem error: ${error}`);
+      return item;
+    }
+  }
+
   /**
    * Get hover info at position
    */
@@ -715,6 +924,58 @@ export class LSPClient {
     }
   }
 
+  /**
+   * Get the server's pull diagnostics options, or null if it only pushes.
+   */
+  getDiagnosticOptions(): LSPDiagnosticOptions | null {
+    const provider = this.serverCapabilities.diagnosticProvider as Partial<LSPDiagnosticOptions> | undefined;
+    if (!provider) return null;
+    return {
+      identifier: provider.identifier,
+      interFileDependencies: provider.interFileDependencies ?? false,
+      workspaceDiagnostics: provider.workspaceDiagnostics ?? false,
+    };
+  }
+
+  /**
+   * Pull diagnostics for a document (textDocument/diagnostic).
+   */
+  async pullDocumentDiagnostics(uri: string, previousResultId?: string): Promise<LSPDocumentDiagnosticReport | null> {
+    const options = this.getDiagnosticOptions();
+    if (!options) return null;
+
+    try {
+      return await this.request<LSPDocumentDiagnosticReport>('textDocument/diagnostic', {
+        textDocument: { uri },
+        identifier: options.identifier,
+        previousResultId,
+      });
+    } catch (error) {
+      this.debugLog(`pullDocumentDiagnostics error: ${error}`);
+      return null;
+    }
+  }
+
+  /**
+   * Pull diagnostics for the whole workspace (workspace/diagnostic).
+   */
+  async pullWorkspaceDiagnostics(
+    previousResultIds: Array<{ uri: string; value: string }>
+  ): Promise<LSPWorkspaceDiagnosticReport | null> {
+    const options = this.getDiagnosticOptions();
+    if (!options?.workspaceDiagnostics) return null;
+
+    try {
+      return await this.request<LSPWorkspaceDiagnosticReport>('workspace/diagnostic', {
+        identifier: options.identifier,
+        previousResultIds,
+      });
+    } catch (error) {
+      this.debugLog(`pullWorkspaceDiagnostics error: ${error}`);
+      return null;
+    }
+  }
+
   /**
    * Get document symbols (outline)
    */
@@ -730,6 +991,89 @@ export class LSPClient {
     }
   }
 
+  /**
+   * Get folding ranges, or null if the server doesn't provide them
+   * (callers fall back to indentation-based folding).
+   */
+  async getFoldingRanges(uri: string): Promise<LSPFoldingRange[] | null> {
+    if (!this.serverCapabilities.foldingRangeProvider) return null;
+
+    try {
+      return await this.request<LSPFoldingRange[] | null>(
+        'textDocument/foldingRange',
+        { textDocument: { uri } }
+      );
+    } catch {
+      return null;
+    }
+  }
+
+  /**
+   * Get document links (import paths, URLs in comments, ...)
+   */
+  async getDocumentLinks(uri: string): Promise<LSPDocumentLink[]> {
+    if (!this.serverCapabilities.documentLinkProvider) return [];
+
+    try {
+      const result = await this.request<LSPDocumentLink[] | null>(
+        'textDocument/documentLink',
+        { textDocument: { uri } }
+      );
+      return result || [];
+    } catch {
+      return [];
+    }
+  }
+
+  /**
+   * Resolve a document link's target (documentLink/resolve)
+   */
+  async resolveDocumentLink(link: LSPDocumentLink): Promise<LSPDocumentLink> {
+    const provider = this.serverCapabilities.documentLinkProvider as { resolveProvider?: boolean } | undefined;
+    if (link.target || !provider?.resolveProvider) return link;
+
+    try {
+      const result = await this.request<LSPDocumentLink | null>('documentLink/resolve', link);
+      return result || link;
+    } catch {
+      return link;
+    }
+  }
+
+  /**
+   * Get colour values in a document (CSS, theme JSON, ...)
+   */
+  async getDocumentColors(uri: string): Promise<LSPColorInformation[]> {
+    if (!this.serverCapabilities.colorProvider) return [];
+
+    try {
+      const result = await this.request<LSPColorInformation[] | null>(
+        'textDocument/documentColor',
+        { textDocument: { uri } }
+      );
+      return result || [];
+    } catch {
+      return [];
+    }
+  }
+
+  /**
+   * Get the ways a colour can be written at a range
+   */
+  async getColorPresentations(uri: string, color: LSPColor, range: LSPRange): Promise<LSPColorPresentation[]> {
+    if (!this.serverCapabilities.colorProvider) return [];
+
+    try {
+      const result = await this.request<LSPColorPresentation[] | null>(
+        'textDocument/colorPresentation',
+        { textDocument: { uri }, color, range }
+      );
+      return result || [];
+    } catch {
+      return [];
+    }
+  }
+
   /**
    * Get workspace symbols matching a query
    */
@@ -0,0 +1,562 @@
+/**
+ * Language Server Discovery
+ *
+ * Resolves a language server command to an executable. Project-local and
+ * version-managed installs are tried first, since repositories often pin
+ * their own gopls or TypeScript versions:
+ *
+ * 1. `node_modules/.bin`, nearest package first
+ * 2. `go tool <command>` when go.mod (or a go.work module) declares the tool
+ * 3. PATH of the mise/asdf/direnv environment, captured from the login shell
+ * 4. PATH
+ * 5. `go env GOBIN` and `$GOPATH/bin`
+ * 6. Common install directories (~/.cargo/bin, Homebrew, ...)
+ */
+
+import * as fs from 'fs';
+import * as os from 'os';
+import * as path from 'path';
+
+// ============================================
+// Types
+// ============================================
+
+/**
+ * Where a candidate executable comes from.
+ */
+export type ServerCandidateSource =
+  | 'configured'
+  | 'project'
+  | 'goTool'
+  | 'toolchain'
+  | 'path'
+  | 'goBin'
+  | 'common';
+
+/**
+ * Version managers detected from config files above the search directory.
+ */
+export type ToolchainManager = 'mise' | 'asdf' | 'direnv';
+
+/**
+ * A location checked for the server.
+ */
+export interface ServerCandidate {
+  source: ServerCandidateSource;
+  /** Executable path checked (the bare command for PATH lookups, `go tool <command>` for Go tools) */
+  path: string;
+  found: boolean;
+}
+
+/**
+ * How to start a discovered server.
+ */
+export interface ResolvedServerCommand {
+  /** Executable to spawn */
+  command: string;
+  /** Arguments placed before the server's own (e.g. `tool gopls`) */
+  args: string[];
+  source: ServerCandidateSource;
+  /** Working directory for the server process */
+  cwd: string;
+  /** Environment for the server process, when captured from a toolchain */
+  env?: Record<string, string>;
+}
+
+/**
+ * Result of discovering a server command.
+ */
+export interface ServerDiscoveryResult {
+  /** Configured command */
+  command: string;
+  /** Directory the search started from */
+  searchDir: string;
+  /** Version managers with config files above searchDir */
+  toolchains: ToolchainManager[];
+  /** Locations checked, in order */
+  candidates: ServerCandidate[];
+  /** How to install the server, when known */
+  installHint?: string;
+  /** null if the server wasn't found */
+  resolved: ResolvedServerCommand | null;
+}
+
+/**
+ * Output of a helper command.
+ */
+export interface CommandResult {
+  exitCode: number;
+  stdout: string;
+}
+
+/**
+ * Runs helper commands (login shell, `go env`, `direnv export`, ...).
+ */
+export type CommandRunner = (
+  argv: string[],
+  options: { cwd: string; env: Record<string, string> }
+) => Promise<CommandResult>;
+
+/**
+ * Discovery options (overridable for tests).
+ */
+export interface ServerDiscoveryOptions {
+  /** Environment of the editor process */
+  env?: Record<string, string | undefined>;
+  /** Home directory */
+  homeDir?: string;
+  /** Helper command runner */
+  run?: CommandRunner;
+}
+
+// ============================================
+// Constants
+// ============================================
+
+/** Helper commands (login shells in particular) are killed after this */
+const COMMAND_TIMEOUT_MS = 5000;
+
+/** Marks the environment in login shell output, which may include motd or prompt noise */
+const ENV_MARKER = '__ULTRA_ENV__';
+
+/** Config files that enable each version manager */
+const TOOLCHAIN_FILES: Array<[string, ToolchainManager]> = [
+  ['mise.toml', 'mise'],
+  ['.mise.toml', 'mise'],
+  ['.tool-versions', 'asdf'],
+  ['.envrc', 'direnv'],
+];
+
+/** How to install the default servers */
+const INSTALL_HINTS: Record<string, string> = {
+  'typescript-language-server': 'npm install -D typescript-language-server typescript',
+  'gopls': 'go install golang.org/x/tools/gopls@latest',
+  'rust-analyzer': 'rustup component add rust-analyzer',
+  'pylsp': 'pip install python-lsp-server',
+  'solargraph': 'gem install solargraph',
+  'vscode-json-language-server': 'npm install -g vscode-langservers-extracted',
+  'vscode-html-language-server': 'npm install -g vscode-langservers-extracted',
+  'vscode-css-language-server': 'npm install -g vscode-langservers-extracted',
+};
+
+// ============================================
+// Helpers
+// ============================================
+
+/**
+ * Run a helper command, killing it after COMMAND_TIMEOUT_MS.
+ */
+const runCommand: CommandRunner = async (argv, { cwd, env }) => {
+  try {
+    const proc = Bun.spawn(argv, { cwd, env, stdin: 'ignore', stdout: 'pipe', stderr: 'ignore' });
+    const timer = setTimeout(() => proc.kill(), COMMAND_TIMEOUT_MS);
+    const stdout = await new Response(proc.stdout).text();
+    const exitCode = await proc.exited;
+    clearTimeout(timer);
+    return { exitCode, stdout };
+  } catch {
+    return { exitCode: -1, stdout: '' };
+  }
+};
+
+/**
+ * Check whether a path is an executable file.
+ */
+export async function isExecutable(file: string): Promise<boolean> {
+  try {
+    const stat = await fs.promises.stat(file);
+    if (!stat.isFile()) return false;
+    await fs.promises.access(file, fs.constants.X_OK);
+    return true;
+  } catch {
+    return false;
+  }
+}
+
+/**
+ * Find a command in a PATH value.
+ */
+export async function findInPath(command: string, pathValue: string | undefined): Promise<string | null> {
+  for (const dir of (pathValue ?? '').split(path.delimiter)) {
+    if (!dir) continue;
+    const file = path.join(dir, command);
+    if (await isExecutable(file)) return file;
+  }
+  return null;
+}
+
+/**
+ * Directories from `dir` up to the filesystem root.
+ */
+function ancestors(dir: string): string[] {
+  const dirs: string[] = [];
+  let current = path.resolve(dir);
+  while (true) {
+    dirs.push(current);
+    const parent = path.dirname(current);
+    if (parent === current) return dirs;
+    current = parent;
+  }
+}
+
+/**
+ * Find the nearest file with one of the given names, searching upward.
+ */
+function findUp(dir: string, names: string[]): string | null {
+  for (const current of ancestors(dir)) {
+    for (const name of names) {
+      const file = path.join(current, name);
+      if (fs.existsSync(file)) return file;
+    }
+  }
+  return null;
+}
+
+/**
+ * Parse the arguments of a go.mod / go.work directive, in both the
+ * single-line and block forms (e.g. `tool` in go.mod, `use` in go.work).
+ */
+export function parseGoDirectives(content: string, directive: string): string[] {
+  const values: string[] = [];
+  let inBlock = false;
+  for (const raw of content.split('\n')) {
+    const line = raw.replace(/\/\/.*$/, '').trim();
+    if (inBlock) {
+      if (line === ')') inBlock = false;
+      else if (line) values.push(line);
+    } else if (line === `${directive} (`) {
+      inBlock = true;
+    } else if (line.startsWith(`${directive} `)) {
+      values.push(line.slice(directive.length + 1).trim());
+    }
+  }
+  return values;
+}
+
+/**
+ * Parse `env` output (KEY=VALUE lines) between ENV_MARKER lines.
+ * Returns null if the markers are missing.
+ */
+export function parseEnvOutput(output: string): Record<string, string> | null {
+  const start = output.indexOf(ENV_MARKER);
+  const end = output.lastIndexOf(ENV_MARKER);
+  if (start === -1 || end <= start) return null;
+
+  const env: Record<string, string> = {};
+  for (const line of output.slice(start + ENV_MARKER.length, end).split('\n')) {
+    const match = /^([A-Za-z_][A-Za-z0-9_]*)=(.*)$/.exec(line);
+    if (match) env[match[1]!] = match[2]!;
+  }
+  return env;
+}
+
+// ============================================
+// Server Discovery
+// ============================================
+
+/**
+ * Resolves language server commands. The login shell environment is
+ * captured once; toolchain environments are cached per project directory
+ * and `go env` output per directory, until clearCache().
+ */
+export class ServerDiscovery {
+  private env: Record<string, string>;
+  private homeDir: string;
+  private run: CommandRunner;
+
+  private loginEnv: Promise<Record<string, string>> | null = null;
+  private toolchainEnvs = new Map<string, Promise<Record<string, string>>>();
+  private goEnvs = new Map<string, Promise<string[]>>();
+
+  constructor(options: ServerDiscoveryOptions = {}) {
+    this.env = {};
+    for (const [key, value] of Object.entries(options.env ?? process.env)) {
+      if (value !== undefined) this.env[key] = value;
+    }
+    this.homeDir = options.homeDir ?? os.homedir();
+    this.run = options.run ?? runCommand;
+  }
+
+  /**
+   * Forget captured environments (e.g. after installing a toolchain).
+   */
+  clearCache(): void {
+    this.loginEnv = null;
+    this.toolchainEnvs.clear();
+    this.goEnvs.clear();
+  }
+
+  /**
+   * Resolve `command`, searching from `searchDir` (usually the directory of
+   * the document that needs the server).
+   */
+  async discover(command: string, searchDir: string): Promise<ServerDiscoveryResult> {
+    const dir = path.resolve(searchDir);
+    const toolchains = this.detectToolchains(dir);
+    const env = toolchains.length > 0 ? await this.getToolchainEnv(dir, toolchains) : this.env;
+    const toolchainEnv = toolchains.length > 0 ? env : undefined;
+
+    const result: ServerDiscoveryResult = {
+      command,
+      searchDir: dir,
+      toolchains,
+      candidates: [],
+      installHint: INSTALL_HINTS[command],
+      resolved: null,
+    };
+
+    const check = async (source: ServerCandidateSource, file: string, cwd: string): Promise<boolean> => {
+      const found = await isExecutable(file);
+      result.candidates.push({ source, path: file, found });
+      if (found) result.resolved = { command: file, args: [], source, cwd, env: toolchainEnv };
+      return found;
+    };
+
+    // 1. Explicit path
+    if (command.includes('/')) {
+      await check('configured', path.resolve(dir, command), dir);
+      return result;
+    }
+
+    // 2. node_modules/.bin, nearest first (monorepos hoist to the root)
+    for (const current of ancestors(dir)) {
+      const binDir = path.join(current, 'node_modules', '.bin');
+      if (!fs.existsSync(binDir)) continue;
+      if (await check('project', path.join(binDir, command), current)) return result;
+    }
+
+    // 3. Tool declared in go.mod / go.work
+    if (await this.checkGoTool(result, command, dir, env)) return result;
+
+    // 4. PATH of the toolchain environment
+    if (toolchainEnv) {
+      const found = await findInPath(command, toolchainEnv['PATH']);
+      result.candidates.push({ source: 'toolchain', path: found ?? command, found: found !== null });
+      if (found) {
+        result.resolved = { command: found, args: [], source: 'toolchain', cwd: dir, env: toolchainEnv };
+        return result;
+      }
+    }
+
+    // 5. PATH of the editor
+    const onPath = await findInPath(command, this.env['PATH']);
+    result.candidates.push({ source: 'path', path: onPath ?? command, found: onPath !== null });
+    if (onPath) {
+      result.resolved = { command: onPath, args: [], source: 'path', cwd: dir, env: toolchainEnv };
+      return result;
+    }
+
+    // 6. GOBIN and GOPATH/bin
+    for (const binDir of await this.getGoBinDirs(dir, env)) {
+      if (await check('goBin', path.join(binDir, command), dir)) return result;
+    }
+
+    // 7. Common install locations that might not be in a bundled binary's PATH
+    const commonDirs = [
+      path.join(this.homeDir, '.cargo', 'bin'),   // Rust tools via rustup
+      path.join(this.homeDir, '.local', 'bin'),   // User-local binaries
+      '/opt/homebrew/bin',                         // Homebrew on Apple Silicon
+      '/usr/local/bin',                            // Homebrew on Intel Mac
+      path.join(this.homeDir, '.bun', 'bin'),     // Bun global packages
+    ];
+    for (const binDir of commonDirs) {
+      if (await check('common', path.join(binDir, command), dir)) return result;
+    }
+
+    return result;
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Toolchains
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /**
+   * Version managers configured in `dir` or above.
+   */
+  private detectToolchains(dir: string): ToolchainManager[] {
+    const found = new Set<ToolchainManager>();
+    for (const current of ancestors(dir)) {
+      for (const [file, manager] of TOOLCHAIN_FILES) {
+        if (fs.existsSync(path.join(current, file))) found.add(manager);
+      }
+    }
+    return [...found];
+  }
+
+  /**
+   * Nearest directory at or above `dir` with a toolchain file. Documents
+   * below it share one toolchain environment.
+   */
+  private findToolchainRoot(dir: string): string {
+    for (const current of ancestors(dir)) {
+      for (const [file] of TOOLCHAIN_FILES) {
+        if (fs.existsSync(path.join(current, file))) return current;
+      }
+    }
+    return dir;
+  }
+
+  private getToolchainEnv(dir: string, toolchains: ToolchainManager[]): Promise<Record<string, string>> {
+    const root = this.findToolchainRoot(dir);
+    let env = this.toolchainEnvs.get(root);
+    if (!env) {
+      env = this.captureToolchainEnv(root, toolchains);
+      this.toolchainEnvs.set(root, env);
+    }
+    return env;
+  }
+
+  /**
+   * Capture the login shell's environment. The shell runs non-interactively
+   * (profile files only, no rc files or prompt hooks) with stdin closed,
+   * from the home directory, once per discovery instance.
+   */
+  private getLoginEnv(): Promise<Record<string, string>> {
+    if (!this.loginEnv) {
+      const shell = this.env['SHELL'] || '/bin/sh';
+      this.loginEnv = this.run(
+        [shell, '-l', '-c', `echo ${ENV_MARKER}; env; echo ${ENV_MARKER}`],
+        { cwd: this.homeDir, env: this.env }
+      ).then((login) => ({ ...this.env, ...(parseEnvOutput(login.stdout) ?? {}) }));
+    }
+    return this.loginEnv;
+  }
+
+  /**
+   * Apply direnv and mise for `dir` on top of the login shell environment
+   * (their shell hooks only run at an interactive prompt). asdf shims pick
+   * the version from the working directory, so they only need to be on PATH.
+   */
+  private async captureToolchainEnv(dir: string, toolchains: ToolchainManager[]): Promise<Record<string, string>> {
+    const env = { ...(await this.getLoginEnv()) };
+
+    if (toolchains.includes('direnv') && (await findInPath('direnv', env['PATH']))) {
+      const direnv = await this.run(['direnv', 'export', 'json'], { cwd: dir, env });
+      applyEnvJson(env, direnv);
+    }
+
+    if (toolchains.includes('mise') && (await findInPath('mise', env['PATH']))) {
+      const mise = await this.run(['mise', 'env', '--json'], { cwd: dir, env });
+      applyEnvJson(env, mise);
+    }
+
+    if (toolchains.includes('asdf')) {
+      const shims = path.join(env['ASDF_DATA_DIR'] || path.join(this.homeDir, '.asdf'), 'shims');
+      const entries = (env['PATH'] ?? '').split(path.delimiter);
+      if (!entries.includes(shims) && fs.existsSync(shims)) {
+        env['PATH'] = [shims, ...entries].filter(Boolean).join(path.delimiter);
+      }
+    }
+
+    return env;
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Go
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /**
+   * Use `go tool <command>` when the nearest go.mod, or a module of the
+   * nearest go.work, declares the command as a tool. Adds a candidate only
+   * when a module declares it.
+   */
+  private async checkGoTool(
+    result: ServerDiscoveryResult,
+    command: string,
+    dir: string,
+    env: Record<string, string>
+  ): Promise<boolean> {
+    const goMod = findUp(dir, ['go.mod']);
+    const goWork = findUp(dir, ['go.work']);
+    if (!goMod && !goWork) return false;
+
+    const modules = goMod ? [path.dirname(goMod)] : [];
+    if (goWork) {
+      const workDir = path.dirname(goWork);
+      for (const use of parseGoDirectives(readFile(goWork), 'use')) {
+        const moduleDir = path.resolve(workDir, use);
+        if (!modules.includes(moduleDir)) modules.push(moduleDir);
+      }
+    }
+
+    const declared = modules.some((moduleDir) => {
+      const tools = parseGoDirectives(readFile(path.join(moduleDir, 'go.mod')), 'tool');
+      return tools.some((tool) => tool.split('/').pop() === command);
+    });
+    if (!declared) return false;
+
+    const go = await findInPath('go', env['PATH']);
+    result.candidates.push({ source: 'goTool', path: `go tool ${command}`, found: go !== null });
+    if (!go) return false;
+
+    // go.work takes precedence so gopls sees the whole workspace
+    const cwd = goWork ? path.dirname(goWork) : path.dirname(goMod!);
+    result.resolved = {
+      command: go,
+      args: ['tool', command],
+      source: 'goTool',
+      cwd,
+      env: result.toolchains.length > 0 ? env : undefined,
+    };
+    return true;
+  }
+
+  /**
+   * GOBIN and GOPATH/bin, from the environment or `go env`.
+   */
+  private getGoBinDirs(dir: string, env: Record<string, string>): Promise<string[]> {
+    let dirs = this.goEnvs.get(dir);
+    if (!dirs) {
+      dirs = this.readGoBinDirs(dir, env);
+      this.goEnvs.set(dir, dirs);
+    }
+    return dirs;
+  }
+
+  private async readGoBinDirs(dir: string, env: Record<string, string>): Promise<string[]> {
+    let gobin = env['GOBIN'] ?? '';
+    let gopath = env['GOPATH'] ?? '';
+
+    if (await findInPath('go', env['PATH'])) {
+      const output = await this.run(['go', 'env', 'GOBIN', 'GOPATH'], { cwd: dir, env });
+      if (output.exitCode === 0) {
+        const [bin, gp] = output.stdout.split('\n');
+        gobin = gobin || (bin ?? '').trim();
+        gopath = gopath || (gp ?? '').trim();
+      }
+    }
+
+    const dirs: string[] = [];
+    if (gobin) dirs.push(gobin);
+    const gopaths = gopath ? gopath.split(path.delimiter).filter(Boolean) : [path.join(this.homeDir, 'go')];
+    for (const entry of gopaths) {
+      const bin = path.join(entry, 'bin');
+      if (!dirs.includes(bin)) dirs.push(bin);
+    }
+    return dirs;
+  }
+}
+
+/**
+ * Apply `direnv export json` / `mise env --json` output (null unsets).
+ */
+function applyEnvJson(env: Record<string, string>, output: CommandResult): void {
+  if (output.exitCode !== 0 || !output.stdout.trim()) return;
+  try {
+    const changes = JSON.parse(output.stdout) as Record<string, string | null>;
+    for (const [key, value] of Object.entries(changes)) {
+      if (value === null) delete env[key];
+      else env[key] = value;
+    }
+  } catch {
+    // Ignore malformed output
+  }
+}
+
+function readFile(file: string): string {
+  try {
+    return fs.readFileSync(file, 'utf8');
+  } catch {
+    return '';
+  }
+}
@@ -11,12 +11,22 @@ export type {
   LSPLocation,
   LSPDiagnostic,
   LSPCompletionItem,
+  LSPCompletionList,
+  LSPTextEdit,
+  LSPDocumentDiagnosticReport,
+  LSPWorkspaceDiagnosticReport,
+  LSPDiagnosticOptions,
   LSPHover,
   LSPSignatureHelp,
   LSPSignatureInformation,
   LSPParameterInformation,
   LSPDocumentSymbol,
   LSPSymbolInformation,
+  LSPFoldingRange,
+  LSPDocumentLink,
+  LSPColor,
+  LSPColorInformation,
+  LSPColorPresentation,
   ServerConfig,
   ServerStatus,
   ServerStatusState,
@@ -26,6 +36,20 @@ export type {
   WorkspaceEdit,
   DiagnosticsCallback,
   ServerStatusCallback,
+  LSPProgress,
+  LSPServerMessage,
+  ProgressCallback,
+  ServerMessageCallback,
+  MessageRequestHandler,
+  TraceCallback,
+  LSPTraceLevel,
+  LSPTraceKind,
+  LSPTraceEntry,
+  ServerCandidateSource,
+  ServerCandidate,
+  ToolchainManager,
+  ResolvedServerCommand,
+  ServerDiscoveryResult,
   Unsubscribe,
 } from './types.ts';
 
@@ -33,10 +57,17 @@ export {
   SymbolKind,
   CompletionItemKind,
   DiagnosticSeverity,
+  MessageType,
   EXTENSION_TO_LANGUAGE,
   DEFAULT_SERVERS,
 } from './types.ts';
 
+// Tracing
+export { LSPTraceLog, formatTraceEntry } from './trace.ts';
+
+// Server discovery
+export { ServerDiscovery, type ServerDiscoveryOptions, type CommandRunner } from './discovery.ts';
+
 // Errors
 export { LSPError, LSPErrorCode } from './errors.ts';
 
@@ -13,12 +13,26 @@ import type {
   LSPSignatureHelp,
   LSPDocumentSymbol,
   LSPSymbolInformation,
+  LSPRange,
+  LSPFoldingRange,
+  LSPDocumentLink,
+  LSPColor,
+  LSPColorInformation,
+  LSPColorPresentation,
   ServerConfig,
   ServerStatus,
   ServerInfo,
   WorkspaceEdit,
   DiagnosticsCallback,
   ServerStatusCallback,
+  LSPProgress,
+  ProgressCallback,
+  ServerMessageCallback,
+  MessageRequestHandler,
+  TraceCallback,
+  LSPTraceEntry,
+  LSPTraceLevel,
+  ServerDiscoveryResult,
   Unsubscribe,
 } from './types.ts';
 
@@ -37,9 +51,11 @@ export interface LSPService {
    *
    * @param languageId The language ID (e.g., 'typescript', 'rust')
    * @param workspaceUri The workspace root URI
+   * @param documentUri Document that needs the server; the server command is
+   *   looked for from its directory (defaults to the workspace root)
    * @returns Server info if started successfully
    */
-  startServer(languageId: string, workspaceUri: string): Promise<ServerInfo>;
+  startServer(languageId: string, workspaceUri: string, documentUri?: string): Promise<ServerInfo>;
 
   /**
    * Stop a language server.
@@ -56,6 +72,16 @@ export interface LSPService {
    */
   getServerStatus(languageId?: string): ServerStatus[];
 
+  /**
+   * Look for a language's server command again, forgetting captured
+   * toolchain environments. A failed server can then be restarted.
+   *
+   * @param languageId The language ID
+   * @param documentUri Document to search from (defaults to the workspace root)
+   * @returns Discovery result, or null if no server is configured
+   */
+  discoverServer(languageId: string, documentUri?: string): Promise<ServerDiscoveryResult | null>;
+
   /**
    * Check if LSP is enabled.
    */
@@ -116,6 +142,16 @@ export interface LSPService {
    */
   getCompletions(uri: string, position: LSPPosition): Promise<LSPCompletionItem[]>;
 
+  /**
+   * Resolve lazily computed details of a completion item
+   * (documentation, detail, auto-import edits).
+   *
+   * @param uri Document URI the completion was requested for
+   * @param item Item returned by getCompletions
+   * @returns The resolved item, or the original if resolve isn't supported
+   */
+  resolveCompletionItem(uri: string, item: LSPCompletionItem): Promise<LSPCompletionItem>;
+
   /**
    * Get hover information at position.
    *
@@ -165,6 +201,49 @@ export interface LSPService {
    */
   getDocumentSymbols(uri: string): Promise<LSPDocumentSymbol[] | LSPSymbolInformation[]>;
 
+  /**
+   * Get folding ranges.
+   *
+   * @param uri Document URI
+   * @returns Folding ranges, or null if the server doesn't provide them
+   */
+  getFoldingRanges(uri: string): Promise<LSPFoldingRange[] | null>;
+
+  /**
+   * Get document links (import paths, URLs).
+   *
+   * @param uri Document URI
+   * @returns Array of links (targets may need resolving)
+   */
+  getDocumentLinks(uri: string): Promise<LSPDocumentLink[]>;
+
+  /**
+   * Resolve a document link's target.
+   *
+   * @param uri Document URI the link belongs to
+   * @param link Link from getDocumentLinks
+   * @returns The link with its target filled in, if the server could resolve it
+   */
+  resolveDocumentLink(uri: string, link: LSPDocumentLink): Promise<LSPDocumentLink>;
+
+  /**
+   * Get colour values in a document.
+   *
+   * @param uri Document URI
+   * @returns Colours with their ranges
+   */
+  getDocumentColors(uri: string): Promise<LSPColorInformation[]>;
+
+  /**
+   * Get textual presentations for a colour.
+   *
+   * @param uri Document URI
+   * @param color Colour to present
+   * @param range Range the colour will replace
+   * @returns Presentations, preferred first
+   */
+  getColorPresentations(uri: string, color: LSPColor, range: LSPRange): Promise<LSPColorPresentation[]>;
+
   /**
    * Rename a symbol.
    *
@@ -209,6 +288,23 @@ export interface LSPService {
    */
   onDiagnostics(callback: DiagnosticsCallback): Unsubscribe;
 
+  /**
+   * Pull diagnostics for an open document (LSP 3.17 pull model).
+   * No-op if the server does not support pull diagnostics.
+   * Results are delivered through onDiagnostics.
+   *
+   * @param uri Document URI
+   */
+  pullDiagnostics(uri: string): Promise<void>;
+
+  /**
+   * Pull workspace-wide diagnostics, including files that are not open.
+   * No-op for servers without workspace diagnostics support.
+   *
+   * @param languageId Server to pull from (all running servers if omitted)
+   */
+  pullWorkspaceDiagnostics(languageId?: string): Promise<void>;
+
   // ─────────────────────────────────────────────────────────────────────────
   // Configuration
   // ─────────────────────────────────────────────────────────────────────────
@@ -271,6 +367,68 @@ export interface LSPService {
    */
   onServerStatusChange(callback: ServerStatusCallback): Unsubscribe;
 
+  /**
+   * Subscribe to $/progress updates from servers.
+   *
+   * @param callback Callback for progress begin/report/end
+   * @returns Unsubscribe function
+   */
+  onProgress(callback: ProgressCallback): Unsubscribe;
+
+  /**
+   * Get progress operations that have not ended.
+   *
+   * @param languageId Optional language filter
+   */
+  getActiveProgress(languageId?: string): LSPProgress[];
+
+  /**
+   * Subscribe to window/showMessage messages from servers.
+   *
+   * @param callback Callback for messages
+   * @returns Unsubscribe function
+   */
+  onServerMessage(callback: ServerMessageCallback): Unsubscribe;
+
+  /**
+   * Set the handler that answers window/showMessageRequest.
+   * Without a handler, requests are surfaced as messages and answered with null.
+   */
+  setMessageRequestHandler(handler: MessageRequestHandler | null): void;
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Tracing
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /**
+   * Set trace verbosity for all servers.
+   */
+  setTraceLevel(level: LSPTraceLevel): void;
+
+  /**
+   * Get recorded JSON-RPC traffic, log messages and stderr for a server.
+   * Traces are kept after a server stops so failed starts can be inspected.
+   */
+  getTrace(languageId: string): LSPTraceEntry[];
+
+  /**
+   * Clear the trace for a server.
+   */
+  clearTrace(languageId: string): void;
+
+  /**
+   * Get language IDs that have a trace.
+   */
+  getTracedServers(): string[];
+
+  /**
+   * Subscribe to new trace entries.
+   *
+   * @param callback Callback for each entry
+   * @returns Unsubscribe function
+   */
+  onTrace(callback: TraceCallback): Unsubscribe;
+
   // ─────────────────────────────────────────────────────────────────────────
   // Lifecycle
   // ─────────────────────────────────────────────────────────────────────────
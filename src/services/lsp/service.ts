@@ -7,6 +7,8 @@
 
 import { debugLog as globalDebugLog } from '../../debug.ts';
 import { LSPClient } from './client.ts';
+import { LSPTraceLog, type LSPTraceLevel, type LSPTraceEntry } from './trace.ts';
+import { ServerDiscovery } from './discovery.ts';
 import type { LSPService } from './interface.ts';
 import { LSPError, LSPErrorCode } from './errors.ts';
 import {
@@ -18,17 +20,33 @@ import {
   type LSPSignatureHelp,
   type LSPDocumentSymbol,
   type LSPSymbolInformation,
+  type LSPRange,
+  type LSPFoldingRange,
+  type LSPDocumentLink,
+  type LSPColor,
+  type LSPColorInformation,
+  type LSPColorPresentation,
   type ServerConfig,
   type ServerStatus,
   type ServerInfo,
   type WorkspaceEdit,
   type DiagnosticsCallback,
   type ServerStatusCallback,
+  type LSPProgress,
+  type LSPServerMessage,
+  type ProgressCallback,
+  type ServerMessageCallback,
+  type MessageRequestHandler,
+  type TraceCallback,
+  type ServerDiscoveryResult,
   type Unsubscribe,
   EXTENSION_TO_LANGUAGE,
   DEFAULT_SERVERS,
 } from './types.ts';
 
+/** Debounce for document diagnostic pulls after edits */
+const PULL_DIAGNOSTICS_DELAY_MS = 250;
+
 /**
  * Local LSP Service.
  *
@@ -44,13 +62,33 @@ export class LocalLSPService implements LSPService {
   private failedServers = new Set<string>();
   private customConfigs = new Map<string, ServerConfig>();
 
+  // Server command discovery (kept for servers that weren't found)
+  private discovery = new ServerDiscovery();
+  private missingServers = new Map<string, ServerDiscoveryResult>();
+
   // Diagnostics
   private diagnosticsStore = new Map<string, LSPDiagnostic[]>();
   private diagnosticsCallbacks = new Set<DiagnosticsCallback>();
 
+  // Pull diagnostics: last result ID per URI (with owning server), pending pulls
+  private diagnosticResultIds = new Map<string, { languageId: string; resultId: string }>();
+  private pullTimers = new Map<string, ReturnType<typeof setTimeout>>();
+  private workspacePullsInFlight = new Set<string>();
+
   // Status events
   private statusCallbacks = new Set<ServerStatusCallback>();
 
+  // Progress and messages
+  private progress = new Map<string, LSPProgress>();
+  private progressCallbacks = new Set<ProgressCallback>();
+  private messageCallbacks = new Set<ServerMessageCallback>();
+  private messageRequestHandler: MessageRequestHandler | null = null;
+
+  // Tracing (kept across restarts, by language)
+  private traces = new Map<string, LSPTraceLog>();
+  private traceCallbacks = new Set<TraceCallback>();
+  private traceLevel: LSPTraceLevel = 'messages';
+
   constructor() {
     this.debugLog('Initialized');
   }
@@ -63,7 +101,7 @@ export class LocalLSPService implements LSPService {
   // Server Lifecycle
   // ─────────────────────────────────────────────────────────────────────────
 
-  async startServer(languageId: string, workspaceUri: string): Promise<ServerInfo> {
+  async startServer(languageId: string, workspaceUri: string, documentUri?: string): Promise<ServerInfo> {
     if (!this.enabled) {
       throw LSPError.disabled();
     }
@@ -84,32 +122,44 @@ export class LocalLSPService implements LSPService {
       throw LSPError.serverNotFound(languageId);
     }
 
-    // Find the full path to the command
-    const commandPath = await this.findCommand(config.command);
-    if (!commandPath) {
+    // Extract workspace path from URI
+    const workspacePath = workspaceUri.replace(/^file:\/\//, '');
+
+    // Find the server, preferring project-local and toolchain installs
+    const discovery = await this.discovery.discover(config.command, this.getSearchDir(workspacePath, documentUri));
+    const resolved = discovery.resolved;
+    if (!resolved) {
       this.failedServers.add(languageId);
+      this.missingServers.set(languageId, discovery);
       this.emitStatusChange({
         languageId,
         status: 'error',
         error: `Command not found: ${config.command}`,
+        discovery,
       });
       throw LSPError.serverStartFailed(languageId, `Command not found: ${config.command}`);
     }
-
-    // Extract workspace path from URI
-    const workspacePath = workspaceUri.replace(/^file:\/\//, '');
+    this.missingServers.delete(languageId);
+    this.debugLog(`Found ${config.command} (${resolved.source}): ${resolved.command} ${resolved.args.join(' ')}`);
 
     // Emit starting status
     this.emitStatusChange({ languageId, status: 'starting' });
 
-    // Start the client with the resolved command path
+    // Start the client with the resolved command
     // Debug logging is controlled globally via --debug flag
-    const client = new LSPClient(commandPath, config.args, workspacePath);
+    const client = new LSPClient(
+      resolved.command,
+      [...resolved.args, ...config.args],
+      workspacePath,
+      this.getOrCreateTrace(languageId),
+      { cwd: resolved.cwd, env: resolved.env || config.env ? { ...resolved.env, ...config.env } : undefined }
+    );
 
-    // Set up notification handler
+    // Set up notification and server request handlers
     client.onNotification((method, params) => {
       this.handleNotification(languageId, method, params);
     });
+    client.onServerRequest((method, params) => this.handleServerRequest(languageId, method, params));
 
     const started = await client.start();
     if (!started) {
@@ -132,6 +182,9 @@ export class LocalLSPService implements LSPService {
       capabilities: client.getCapabilities(),
     });
 
+    // Servers with workspace diagnostics report unopened files too
+    void this.pullWorkspaceDiagnostics(languageId);
+
     return {
       languageId,
       ready: true,
@@ -144,6 +197,10 @@ export class LocalLSPService implements LSPService {
     if (client) {
       await client.shutdown();
       this.clients.delete(languageId);
+      this.endProgress(languageId);
+      for (const [uri, entry] of this.diagnosticResultIds) {
+        if (entry.languageId === languageId) this.diagnosticResultIds.delete(uri);
+      }
       this.debugLog(`Stopped server for ${languageId}`);
 
       // Emit stopped status
@@ -168,6 +225,7 @@ export class LocalLSPService implements LSPService {
           languageId,
           status: 'error',
           error: 'Server failed to start',
+          discovery: this.missingServers.get(languageId),
         });
       } else {
         statuses.push({
@@ -191,6 +249,7 @@ export class LocalLSPService implements LSPService {
             languageId: lang,
             status: 'error',
             error: 'Server failed to start',
+            discovery: this.missingServers.get(lang),
           });
         }
       }
@@ -199,6 +258,21 @@ export class LocalLSPService implements LSPService {
     return statuses;
   }
 
+  async discoverServer(languageId: string, documentUri?: string): Promise<ServerDiscoveryResult | null> {
+    const config = this.getServerConfig(languageId);
+    if (!config) return null;
+
+    this.discovery.clearCache();
+    const discovery = await this.discovery.discover(config.command, this.getSearchDir(this.workspaceRoot, documentUri));
+    if (discovery.resolved) {
+      this.missingServers.delete(languageId);
+      this.failedServers.delete(languageId);
+    } else {
+      this.missingServers.set(languageId, discovery);
+    }
+    return discovery;
+  }
+
   isEnabled(): boolean {
     return this.enabled;
   }
@@ -221,7 +295,7 @@ export class LocalLSPService implements LSPService {
     let client = this.clients.get(languageId);
     if (!client && !this.failedServers.has(languageId)) {
       try {
-        await this.startServer(languageId, `file://${this.workspaceRoot}`);
+        await this.startServer(languageId, `file://${this.workspaceRoot}`, uri);
         client = this.clients.get(languageId);
       } catch {
         // Server failed to start, continue without LSP
@@ -238,6 +312,7 @@ export class LocalLSPService implements LSPService {
     this.documentLanguages.set(uri, languageId);
 
     client.didOpen(uri, languageId, version, content);
+    this.schedulePullDiagnostics(uri, 0);
   }
 
   async documentChanged(uri: string, content: string, version: number): Promise<void> {
@@ -253,6 +328,16 @@ export class LocalLSPService implements LSPService {
 
     this.documentVersions.set(uri, version);
     client.didChange(uri, version, content);
+    this.schedulePullDiagnostics(uri);
+
+    // Other open documents may depend on this one
+    if (client.getDiagnosticOptions()?.interFileDependencies) {
+      for (const [other, otherLanguage] of this.documentLanguages) {
+        if (other !== uri && otherLanguage === languageId) {
+          this.schedulePullDiagnostics(other);
+        }
+      }
+    }
   }
 
   async documentSaved(uri: string, content?: string): Promise<void> {
@@ -267,6 +352,7 @@ export class LocalLSPService implements LSPService {
     }
 
     client.didSave(uri, content);
+    void this.pullWorkspaceDiagnostics(languageId);
   }
 
   async documentClosed(uri: string): Promise<void> {
@@ -283,6 +369,8 @@ export class LocalLSPService implements LSPService {
     this.documentVersions.delete(uri);
     this.documentLanguages.delete(uri);
     this.diagnosticsStore.delete(uri);
+    this.diagnosticResultIds.delete(uri);
+    this.cancelPullDiagnostics(uri);
   }
 
   // ─────────────────────────────────────────────────────────────────────────
@@ -303,6 +391,15 @@ export class LocalLSPService implements LSPService {
     }
   }
 
+  async resolveCompletionItem(uri: string, item: LSPCompletionItem): Promise<LSPCompletionItem> {
+    const client = this.getClientForDocument(uri);
+    if (!client) {
+      return item;
+    }
+
+    return client.resolveCompletionItem(item);
+  }
+
   async getHover(uri: string, position: LSPPosition): Promise<LSPHover | null> {
     const client = this.getClientForDocument(uri);
     if (!client) {
@@ -381,6 +478,76 @@ export class LocalLSPService implements LSPService {
     }
   }
 
+  async getFoldingRanges(uri: string): Promise<LSPFoldingRange[] | null> {
+    const client = this.getClientForDocument(uri);
+    if (!client) {
+      return null;
+    }
+
+    try {
+      return await client.getFoldingRanges(uri);
+    } catch (error) {
+      this.debugLog(`getFoldingRanges error: ${error}`);
+      return null;
+    }
+  }
+
+  async getDocumentLinks(uri: string): Promise<LSPDocumentLink[]> {
+    const client = this.getClientForDocument(uri);
+    if (!client) {
+      return [];
+    }
+
+    try {
+      return await client.getDocumentLinks(uri);
+    } catch (error) {
+      this.debugLog(`getDocumentLinks error: ${error}`);
+      return [];
+    }
+  }
+
+  async resolveDocumentLink(uri: string, link: LSPDocumentLink): Promise<LSPDocumentLink> {
+    const client = this.getClientForDocument(uri);
+    if (!client) {
+      return link;
+    }
+
+    try {
+      return await client.resolveDocumentLink(link);
+    } catch (error) {
+      this.debugLog(`resolveDocumentLink error: ${error}`);
+      return link;
+    }
+  }
+
+  async getDocumentColors(uri: string): Promise<LSPColorInformation[]> {
+    const client = this.getClientForDocument(uri);
+    if (!client) {
+      return [];
+    }
+
+    try {
+      return await client.getDocumentColors(uri);
+    } catch (error) {
+      this.debugLog(`getDocumentColors error: ${error}`);
+      return [];
+    }
+  }
+
+  async getColorPresentations(uri: string, color: LSPColor, range: LSPRange): Promise<LSPColorPresentation[]> {
+    const client = this.getClientForDocument(uri);
+    if (!client) {
+      return [];
+    }
+
+    try {
+      return await client.getColorPresentations(uri, color, range);
+    } catch (error) {
+      this.debugLog(`getColorPresentations error: ${error}`);
+      return [];
+    }
+  }
+
   async getWorkspaceSymbols(query: string): Promise<LSPSymbolInformation[]> {
     // Query all running clients and merge results
     const allSymbols: LSPSymbolInformation[] = [];
@@ -447,6 +614,52 @@ export class LocalLSPService implements LSPService {
     };
   }
 
+  async pullDiagnostics(uri: string): Promise<void> {
+    this.cancelPullDiagnostics(uri);
+
+    const languageId = this.documentLanguages.get(uri);
+    const client = languageId ? this.clients.get(languageId) : undefined;
+    if (!languageId || !client?.getDiagnosticOptions()) return;
+
+    const version = this.documentVersions.get(uri);
+    const report = await client.pullDocumentDiagnostics(uri, this.diagnosticResultIds.get(uri)?.resultId);
+    if (!report) return;
+
+    // Drop reports that raced with a newer edit; a pull for that edit is pending
+    if (this.documentVersions.get(uri) !== version) return;
+
+    this.applyDiagnosticReport(languageId, uri, report);
+    for (const [relatedUri, related] of Object.entries(report.relatedDocuments ?? {})) {
+      this.applyDiagnosticReport(languageId, relatedUri, related);
+    }
+  }
+
+  async pullWorkspaceDiagnostics(languageId?: string): Promise<void> {
+    const languageIds = languageId ? [languageId] : Array.from(this.clients.keys());
+
+    await Promise.all(languageIds.map(async (id) => {
+      const client = this.clients.get(id);
+      if (!client?.getDiagnosticOptions()?.workspaceDiagnostics || this.workspacePullsInFlight.has(id)) return;
+
+      this.workspacePullsInFlight.add(id);
+      try {
+        const previousResultIds = Array.from(this.diagnosticResultIds)
+          .filter(([, entry]) => entry.languageId === id)
+          .map(([uri, entry]) => ({ uri, value: entry.resultId }));
+        const report = await client.pullWorkspaceDiagnostics(previousResultIds);
+
+        for (const item of report?.items ?? []) {
+          // Open documents are kept current by document pulls
+          const openVersion = this.documentVersions.get(item.uri);
+          if (openVersion !== undefined && item.version !== openVersion) continue;
+          this.applyDiagnosticReport(id, item.uri, item);
+        }
+      } finally {
+        this.workspacePullsInFlight.delete(id);
+      }
+    }));
+  }
+
   // ─────────────────────────────────────────────────────────────────────────
   // Configuration
   // ─────────────────────────────────────────────────────────────────────────
@@ -521,6 +734,60 @@ export class LocalLSPService implements LSPService {
     };
   }
 
+  onProgress(callback: ProgressCallback): Unsubscribe {
+    this.progressCallbacks.add(callback);
+    return () => {
+      this.progressCallbacks.delete(callback);
+    };
+  }
+
+  getActiveProgress(languageId?: string): LSPProgress[] {
+    return Array.from(this.progress.values())
+      .filter((p) => !languageId || p.languageId === languageId)
+      .map((p) => ({ ...p }));
+  }
+
+  onServerMessage(callback: ServerMessageCallback): Unsubscribe {
+    this.messageCallbacks.add(callback);
+    return () => {
+      this.messageCallbacks.delete(callback);
+    };
+  }
+
+  setMessageRequestHandler(handler: MessageRequestHandler | null): void {
+    this.messageRequestHandler = handler;
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Tracing
+  // ─────────────────────────────────────────────────────────────────────────
+
+  setTraceLevel(level: LSPTraceLevel): void {
+    this.traceLevel = level;
+    for (const trace of this.traces.values()) {
+      trace.setLevel(level);
+    }
+  }
+
+  getTrace(languageId: string): LSPTraceEntry[] {
+    return this.traces.get(languageId)?.getEntries() ?? [];
+  }
+
+  clearTrace(languageId: string): void {
+    this.traces.get(languageId)?.clear();
+  }
+
+  getTracedServers(): string[] {
+    return Array.from(this.traces.keys()).sort();
+  }
+
+  onTrace(callback: TraceCallback): Unsubscribe {
+    this.traceCallbacks.add(callback);
+    return () => {
+      this.traceCallbacks.delete(callback);
+    };
+  }
+
   // ─────────────────────────────────────────────────────────────────────────
   // Lifecycle
   // ─────────────────────────────────────────────────────────────────────────
@@ -543,7 +810,12 @@ export class LocalLSPService implements LSPService {
     this.documentVersions.clear();
     this.documentLanguages.clear();
     this.diagnosticsStore.clear();
+    this.diagnosticResultIds.clear();
+    for (const timer of this.pullTimers.values()) clearTimeout(timer);
+    this.pullTimers.clear();
     this.failedServers.clear();
+    this.missingServers.clear();
+    this.progress.clear();
 
     this.debugLog('Shutdown complete');
   }
@@ -561,56 +833,15 @@ export class LocalLSPService implements LSPService {
   }
 
   /**
-   * Find the full path to a command.
-   * Returns the full path if found, null otherwise.
+   * Directory to look for a server from: the document's directory when it
+   * is inside the workspace, otherwise the workspace root.
    */
-  private async findCommand(command: string): Promise<string | null> {
-    // First try `which` with the current PATH
-    try {
-      const proc = Bun.spawn(['which', command], {
-        stdout: 'pipe',
-        stderr: 'pipe',
-      });
-      await proc.exited;
-      if (proc.exitCode === 0) {
-        const output = await new Response(proc.stdout).text();
-        const resolvedPath = output.trim();
-        if (resolvedPath) {
-          this.debugLog(`Found ${command} via which: ${resolvedPath}`);
-          return resolvedPath;
-        }
-      }
-    } catch {
-      // Continue to check additional paths
-    }
-
-    // Check common additional paths that might not be in bundled binary's PATH
-    const os = await import('os');
-    const path = await import('path');
-    const fs = await import('fs');
-    const homeDir = os.homedir();
-
-    const additionalPaths = [
-      path.join(homeDir, '.cargo', 'bin'),      // Rust tools via rustup
-      path.join(homeDir, '.local', 'bin'),      // User-local binaries
-      '/opt/homebrew/bin',                       // Homebrew on Apple Silicon
-      '/usr/local/bin',                          // Homebrew on Intel Mac
-      path.join(homeDir, 'go', 'bin'),          // Go tools
-      path.join(homeDir, '.bun', 'bin'),        // Bun global packages
-    ];
-
-    for (const dir of additionalPaths) {
-      const fullPath = path.join(dir, command);
-      try {
-        await fs.promises.access(fullPath, fs.constants.X_OK);
-        this.debugLog(`Found ${command} at ${fullPath}`);
-        return fullPath;
-      } catch {
-        // Not found or not executable, continue
-      }
-    }
-
-    return null;
+  private getSearchDir(workspacePath: string, documentUri?: string): string {
+    if (!documentUri?.startsWith('file://')) return workspacePath;
+    const documentPath = decodeURIComponent(documentUri.slice('file://'.length));
+    const documentDir = documentPath.slice(0, documentPath.lastIndexOf('/')) || '/';
+    const inWorkspace = documentDir === workspacePath || documentDir.startsWith(`${workspacePath}/`);
+    return inWorkspace ? documentDir : workspacePath;
   }
 
   private handleNotification(languageId: string, method: string, params: unknown): void {
@@ -618,17 +849,204 @@ export class LocalLSPService implements LSPService {
 
     if (method === 'textDocument/publishDiagnostics') {
       const { uri, diagnostics } = params as { uri: string; diagnostics: LSPDiagnostic[] };
-      this.diagnosticsStore.set(uri, diagnostics);
+      this.setDiagnostics(uri, diagnostics);
+    } else if (method === '$/progress') {
+      this.handleProgress(languageId, params);
+    } else if (method === 'window/showMessage') {
+      const { type, message } = (params ?? {}) as { type?: number; message?: string };
+      this.emitServerMessage({ languageId, type: type ?? 3, message: message ?? '' });
+    }
+  }
 
-      // Notify callbacks
-      for (const callback of this.diagnosticsCallbacks) {
-        try {
-          callback(uri, diagnostics);
-        } catch (error) {
-          this.debugLog(`Diagnostics callback error: ${error}`);
-        }
+  private handleServerRequest(languageId: string, method: string, params: unknown): Promise<unknown> | undefined {
+    if (method === 'workspace/diagnostic/refresh') {
+      this.refreshDiagnostics(languageId);
+      return Promise.resolve(null);
+    }
+
+    if (method !== 'window/showMessageRequest') {
+      return undefined;
+    }
+
+    const p = (params ?? {}) as { type?: number; message?: string; actions?: Array<{ title: string }> };
+    const actions = p.actions ?? [];
+    const message: LSPServerMessage = {
+      languageId,
+      type: p.type ?? 3,
+      message: p.message ?? '',
+      actions: actions.map((a) => a.title),
+    };
+
+    if (!this.messageRequestHandler) {
+      this.emitServerMessage(message);
+      return Promise.resolve(null);
+    }
+
+    return this.messageRequestHandler(message).then(
+      (title) => actions.find((a) => a.title === title) ?? null
+    );
+  }
+
+  /**
+   * Store diagnostics for a URI and notify listeners.
+   */
+  private setDiagnostics(uri: string, diagnostics: LSPDiagnostic[]): void {
+    this.diagnosticsStore.set(uri, diagnostics);
+
+    for (const callback of this.diagnosticsCallbacks) {
+      try {
+        callback(uri, diagnostics);
+      } catch (error) {
+        this.debugLog(`Diagnostics callback error: ${error}`);
+      }
+    }
+  }
+
+  /**
+   * Apply a full or unchanged pull report for one document.
+   */
+  private applyDiagnosticReport(
+    languageId: string,
+    uri: string,
+    report: { kind: 'full' | 'unchanged'; resultId?: string; items?: LSPDiagnostic[] }
+  ): void {
+    if (report.resultId) {
+      this.diagnosticResultIds.set(uri, { languageId, resultId: report.resultId });
+    } else {
+      this.diagnosticResultIds.delete(uri);
+    }
+
+    if (report.kind === 'full') {
+      this.setDiagnostics(uri, report.items ?? []);
+    }
+  }
+
+  /**
+   * Server asked for diagnostics to be re-pulled (workspace/diagnostic/refresh).
+   */
+  private refreshDiagnostics(languageId: string): void {
+    for (const [uri, openLanguage] of this.documentLanguages) {
+      if (openLanguage === languageId) {
+        this.schedulePullDiagnostics(uri, 0);
       }
     }
+    void this.pullWorkspaceDiagnostics(languageId);
+  }
+
+  /**
+   * Debounce document pulls so fast typing sends one request.
+   */
+  private schedulePullDiagnostics(uri: string, delayMs = PULL_DIAGNOSTICS_DELAY_MS): void {
+    this.cancelPullDiagnostics(uri);
+    this.pullTimers.set(uri, setTimeout(() => {
+      this.pullTimers.delete(uri);
+      void this.pullDiagnostics(uri);
+    }, delayMs));
+  }
+
+  private cancelPullDiagnostics(uri: string): void {
+    const timer = this.pullTimers.get(uri);
+    if (timer) {
+      clearTimeout(timer);
+      this.pullTimers.delete(uri);
+    }
+  }
+
+  private handleProgress(languageId: string, params: unknown): void {
+    const { token, value } = (params ?? {}) as {
+      token?: string | number;
+      value?: { kind?: string; title?: string; message?: string; percentage?: number };
+    };
+    if (token === undefined || !value?.kind) return;
+
+    const key = `${languageId}:${token}`;
+    const existing = this.progress.get(key);
+    let update: LSPProgress;
+
+    switch (value.kind) {
+      case 'begin':
+        update = {
+          languageId,
+          token,
+          title: value.title ?? '',
+          message: value.message,
+          percentage: value.percentage,
+          done: false,
+        };
+        this.progress.set(key, update);
+        break;
+      case 'report':
+        if (!existing) return;
+        if (value.message !== undefined) existing.message = value.message;
+        if (value.percentage !== undefined) existing.percentage = value.percentage;
+        update = existing;
+        break;
+      case 'end':
+        this.progress.delete(key);
+        update = {
+          languageId,
+          token,
+          title: existing?.title ?? '',
+          message: value.message ?? existing?.message,
+          percentage: existing?.percentage,
+          done: true,
+        };
+        break;
+      default:
+        return;
+    }
+
+    this.emitProgress(update);
+  }
+
+  /**
+   * End all progress for a server (e.g. when it stops).
+   */
+  private endProgress(languageId: string): void {
+    for (const [key, progress] of this.progress) {
+      if (progress.languageId === languageId) {
+        this.progress.delete(key);
+        this.emitProgress({ ...progress, done: true });
+      }
+    }
+  }
+
+  private emitProgress(progress: LSPProgress): void {
+    for (const callback of this.progressCallbacks) {
+      try {
+        callback({ ...progress });
+      } catch (error) {
+        this.debugLog(`Progress callback error: ${error}`);
+      }
+    }
+  }
+
+  private emitServerMessage(message: LSPServerMessage): void {
+    for (const callback of this.messageCallbacks) {
+      try {
+        callback(message);
+      } catch (error) {
+        this.debugLog(`Message callback error: ${error}`);
+      }
+    }
+  }
+
+  private getOrCreateTrace(languageId: string): LSPTraceLog {
+    let trace = this.traces.get(languageId);
+    if (!trace) {
+      trace = new LSPTraceLog(this.traceLevel);
+      this.traces.set(languageId, trace);
+      trace.onEntry((entry) => {
+        for (const callback of this.traceCallbacks) {
+          try {
+            callback(languageId, entry);
+          } catch (error) {
+            this.debugLog(`Trace callback error: ${error}`);
+          }
+        }
+      });
+    }
+    return trace;
   }
 
   private emitStatusChange(status: ServerStatus): void {
@@ -0,0 +1,245 @@
+/**
+ * LSP Trace Log
+ *
+ * Bounded record of JSON-RPC traffic, server log messages and stderr
+ * output for a single language server. Used by the LSP trace panel to
+ * diagnose slow or misbehaving servers.
+ */
+
+// ============================================
+// Types
+// ============================================
+
+/**
+ * Trace verbosity.
+ * - off: nothing is recorded
+ * - messages: method, id and timing only
+ * - verbose: also records JSON payloads
+ */
+export type LSPTraceLevel = 'off' | 'messages' | 'verbose';
+
+/**
+ * Kind of traced event.
+ */
+export type LSPTraceKind = 'request' | 'response' | 'notification' | 'log' | 'stderr';
+
+/**
+ * A single trace entry.
+ */
+export interface LSPTraceEntry {
+  /** Sequence number (monotonic per log) */
+  seq: number;
+  /** Milliseconds since epoch */
+  timestamp: number;
+  /** 'send' = client to server, 'receive' = server to client */
+  direction: 'send' | 'receive';
+  kind: LSPTraceKind;
+  /** JSON-RPC method (for responses, the method of the request) */
+  method?: string;
+  /** JSON-RPC request ID */
+  id?: number | string;
+  /** Round-trip time for responses */
+  durationMs?: number;
+  /** Whether the response was an error */
+  isError?: boolean;
+  /** Log text (log/stderr) or error message */
+  text?: string;
+  /** window/logMessage type (1=Error, 2=Warning, 3=Info, 4=Log) */
+  logType?: number;
+  /** JSON payload (verbose only, truncated) */
+  payload?: string;
+}
+
+export type LSPTraceListener = (entry: LSPTraceEntry) => void;
+
+/** Maximum entries kept per server */
+const DEFAULT_MAX_ENTRIES = 2000;
+
+/** Maximum payload length kept per entry */
+const MAX_PAYLOAD_LENGTH = 4000;
+
+// ============================================
+// Trace Log
+// ============================================
+
+export class LSPTraceLog {
+  private entries: LSPTraceEntry[] = [];
+  private seq = 0;
+  private listeners = new Set<LSPTraceListener>();
+  private level: LSPTraceLevel;
+  private maxEntries: number;
+
+  /** Outstanding outgoing requests: id -> method and start time */
+  private outgoing = new Map<number | string, { method: string; startedAt: number }>();
+
+  /** Outstanding incoming (server -> client) requests */
+  private incoming = new Map<number | string, { method: string; startedAt: number }>();
+
+  constructor(level: LSPTraceLevel = 'messages', maxEntries = DEFAULT_MAX_ENTRIES) {
+    this.level = level;
+    this.maxEntries = maxEntries;
+  }
+
+  setLevel(level: LSPTraceLevel): void {
+    this.level = level;
+  }
+
+  getLevel(): LSPTraceLevel {
+    return this.level;
+  }
+
+  /**
+   * Record a JSON-RPC message.
+   */
+  recordMessage(direction: 'send' | 'receive', message: unknown, now = Date.now()): void {
+    if (this.level === 'off' || !message || typeof message !== 'object') return;
+
+    const msg = message as {
+      id?: number | string;
+      method?: string;
+      params?: unknown;
+      result?: unknown;
+      error?: { message: string };
+    };
+    const payload = this.level === 'verbose'
+      ? truncatePayload(msg.method ? msg.params : (msg.error ?? msg.result))
+      : undefined;
+
+    // Server log messages are shown as log lines rather than raw notifications
+    if (direction === 'receive' && msg.method === 'window/logMessage' && msg.id === undefined) {
+      const params = (msg.params ?? {}) as { type?: number; message?: string };
+      this.push({ timestamp: now, direction, kind: 'log', method: msg.method, text: params.message ?? '', logType: params.type });
+      return;
+    }
+
+    if (msg.method !== undefined && msg.id !== undefined) {
+      // Request: remember start time to compute the response duration
+      const pending = direction === 'send' ? this.outgoing : this.incoming;
+      pending.set(msg.id, { method: msg.method, startedAt: now });
+      this.push({ timestamp: now, direction, kind: 'request', method: msg.method, id: msg.id, payload });
+    } else if (msg.method !== undefined) {
+      this.push({ timestamp: now, direction, kind: 'notification', method: msg.method, payload });
+    } else if (msg.id !== undefined) {
+      // Response: to our request when receiving, to a server request when sending
+      const pending = direction === 'receive' ? this.outgoing : this.incoming;
+      const request = pending.get(msg.id);
+      pending.delete(msg.id);
+      this.push({
+        timestamp: now,
+        direction,
+        kind: 'response',
+        method: request?.method,
+        id: msg.id,
+        durationMs: request ? now - request.startedAt : undefined,
+        isError: msg.error !== undefined,
+        text: msg.error?.message,
+        payload,
+      });
+    }
+  }
+
+  /**
+   * Record server stderr output.
+   */
+  recordStderr(text: string, now = Date.now()): void {
+    if (this.level === 'off') return;
+    for (const line of text.split('\n')) {
+      if (line.trim()) {
+        this.push({ timestamp: now, direction: 'receive', kind: 'stderr', text: line.trimEnd() });
+      }
+    }
+  }
+
+  /**
+   * Forget an outgoing request that will never get a response (e.g. timeout).
+   */
+  forgetRequest(id: number | string): void {
+    this.outgoing.delete(id);
+  }
+
+  getEntries(): LSPTraceEntry[] {
+    return [...this.entries];
+  }
+
+  clear(): void {
+    this.entries = [];
+  }
+
+  onEntry(listener: LSPTraceListener): () => void {
+    this.listeners.add(listener);
+    return () => {
+      this.listeners.delete(listener);
+    };
+  }
+
+  private push(fields: Omit<LSPTraceEntry, 'seq'>): void {
+    const entry: LSPTraceEntry = { seq: ++this.seq, ...fields };
+    this.entries.push(entry);
+    if (this.entries.length > this.maxEntries) {
+      this.entries.splice(0, this.entries.length - this.maxEntries);
+    }
+    for (const listener of this.listeners) {
+      listener(entry);
+    }
+  }
+}
+
+// ============================================
+// Formatting
+// ============================================
+
+const LOG_TYPE_LABELS: Record<number, string> = {
+  1: 'error',
+  2: 'warn',
+  3: 'info',
+  4: 'log',
+};
+
+/**
+ * Format a trace entry as a single display line.
+ *
+ * @example
+ * "12:03:04.120 → request #5 textDocument/hover"
+ * "12:03:04.162 ← response #5 textDocument/hover (42ms)"
+ */
+export function formatTraceEntry(entry: LSPTraceEntry): string {
+  const time = formatTime(entry.timestamp);
+  const arrow = entry.direction === 'send' ? '→' : '←';
+
+  switch (entry.kind) {
+    case 'log': {
+      const label = LOG_TYPE_LABELS[entry.logType ?? 4] ?? 'log';
+      return `${time} [${label}] ${entry.text ?? ''}`;
+    }
+    case 'stderr':
+      return `${time} [stderr] ${entry.text ?? ''}`;
+    case 'request':
+      return `${time} ${arrow} request #${entry.id} ${entry.method}`;
+    case 'notification':
+      return `${time} ${arrow} notification ${entry.method}`;
+    case 'response': {
+      const method = entry.method ? ` ${entry.method}` : '';
+      const duration = entry.durationMs !== undefined ? ` (${entry.durationMs}ms)` : '';
+      const error = entry.isError ? ` error: ${entry.text ?? ''}` : '';
+      return `${time} ${arrow} response #${entry.id}${method}${duration}${error}`;
+    }
+  }
+}
+
+function formatTime(timestamp: number): string {
+  const d = new Date(timestamp);
+  const pad = (n: number, width = 2) => String(n).padStart(width, '0');
+  return `${pad(d.getHours())}:${pad(d.getMinutes())}:${pad(d.getSeconds())}.${pad(d.getMilliseconds(), 3)}`;
+}
+
+function truncatePayload(value: unknown): string | undefined {
+  if (value === undefined) return undefined;
+  let json: string;
+  try {
+    json = JSON.stringify(value);
+  } catch {
+    return undefined;
+  }
+  if (json === undefined) return undefined;
+  return json.length > MAX_PAYLOAD_LENGTH ? `${json.slice(0, MAX_PAYLOAD_LENGTH)}…` : json;
+}
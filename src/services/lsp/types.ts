@@ -12,18 +12,43 @@ export type {
   LSPLocation,
   LSPDiagnostic,
   LSPCompletionItem,
+  LSPCompletionList,
+  LSPTextEdit,
+  LSPDocumentDiagnosticReport,
+  LSPWorkspaceDiagnosticReport,
+  LSPDiagnosticOptions,
   LSPHover,
   LSPSignatureHelp,
   LSPSignatureInformation,
   LSPParameterInformation,
   LSPDocumentSymbol,
   LSPSymbolInformation,
+  LSPFoldingRange,
+  LSPDocumentLink,
+  LSPColor,
+  LSPColorInformation,
+  LSPColorPresentation,
   LSPTextDocumentIdentifier,
   LSPVersionedTextDocumentIdentifier,
   LSPTextDocumentItem,
   NotificationHandler,
+  ServerRequestHandler,
 } from './client.ts';
 
+export type {
+  LSPTraceLevel,
+  LSPTraceKind,
+  LSPTraceEntry,
+} from './trace.ts';
+
+export type {
+  ServerCandidateSource,
+  ServerCandidate,
+  ToolchainManager,
+  ResolvedServerCommand,
+  ServerDiscoveryResult,
+} from './discovery.ts';
+
 export { SymbolKind } from './client.ts';
 
 // ─────────────────────────────────────────────────────────────────────────────
@@ -73,6 +98,9 @@ export interface ServerStatus {
 
   /** Process ID (if running) */
   pid?: number;
+
+  /** Where the server command was looked for (if it wasn't found) */
+  discovery?: import('./discovery.ts').ServerDiscoveryResult;
 }
 
 /**
@@ -237,6 +265,60 @@ export const DEFAULT_SERVERS: Record<string, ServerConfig> = {
   sql: { command: 'postgres-language-server', args: ['lsp-proxy'] },
 };
 
+// ─────────────────────────────────────────────────────────────────────────────
+// Window Features (progress and messages)
+// ─────────────────────────────────────────────────────────────────────────────
+
+/**
+ * Message types for window/showMessage and window/logMessage (LSP spec).
+ */
+export const MessageType = {
+  Error: 1,
+  Warning: 2,
+  Info: 3,
+  Log: 4,
+} as const;
+
+/**
+ * Work done progress reported by a server via $/progress.
+ */
+export interface LSPProgress {
+  /** Language ID of the reporting server */
+  languageId: string;
+
+  /** Progress token */
+  token: string | number;
+
+  /** Title from the begin notification (e.g. "Loading packages") */
+  title: string;
+
+  /** Latest progress message */
+  message?: string;
+
+  /** Percentage 0-100, if the server reports it */
+  percentage?: number;
+
+  /** Whether the operation has ended */
+  done: boolean;
+}
+
+/**
+ * A message from window/showMessage or window/showMessageRequest.
+ */
+export interface LSPServerMessage {
+  /** Language ID of the sending server */
+  languageId: string;
+
+  /** Message type (see MessageType) */
+  type: number;
+
+  /** Message text */
+  message: string;
+
+  /** Action titles offered by a showMessageRequest */
+  actions?: string[];
+}
+
 // ─────────────────────────────────────────────────────────────────────────────
 // Events
 // ─────────────────────────────────────────────────────────────────────────────
@@ -251,6 +333,27 @@ export type DiagnosticsCallback = (uri: string, diagnostics: import('./client.ts
  */
 export type ServerStatusCallback = (status: ServerStatus) => void;
 
+/**
+ * Progress callback for $/progress updates.
+ */
+export type ProgressCallback = (progress: LSPProgress) => void;
+
+/**
+ * Callback for window/showMessage (and unanswered showMessageRequest) messages.
+ */
+export type ServerMessageCallback = (message: LSPServerMessage) => void;
+
+/**
+ * Handler for window/showMessageRequest.
+ * Resolves with the chosen action title, or null if dismissed.
+ */
+export type MessageRequestHandler = (message: LSPServerMessage) => Promise<string | null>;
+
+/**
+ * Trace callback receiving JSON-RPC traffic per server.
+ */
+export type TraceCallback = (languageId: string, entry: import('./trace.ts').LSPTraceEntry) => void;
+
 /**
  * Unsubscribe function returned by event subscriptions.
  */
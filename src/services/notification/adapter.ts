@@ -0,0 +1,311 @@
+/**
+ * Notification Service ECP Adapter
+ *
+ * Maps ECP JSON-RPC calls to NotificationService methods, and forwards
+ * notification events so services and extensions can react to the
+ * user's choices.
+ */
+
+import type { NotificationService } from './interface.ts';
+import type {
+  NotificationInfo,
+  NotificationOptions,
+  NotificationUpdate,
+  NotificationCloseReason,
+  ProgressOptions,
+} from './types.ts';
+
+/**
+ * ECP error codes (JSON-RPC 2.0 compatible).
+ */
+export const NotificationECPErrorCodes = {
+  // Standard JSON-RPC errors
+  MethodNotFound: -32601,
+  InvalidParams: -32602,
+  InternalError: -32603,
+
+  // Notification service errors (-32800 to -32899)
+  NotificationNotFound: -32800,
+  ActionNotFound: -32801,
+  NotCancellable: -32802,
+} as const;
+
+/**
+ * Notifications sent by this adapter.
+ */
+export const NOTIFICATION_NOTIFICATIONS = [
+  'notification/shown',
+  'notification/updated',
+  'notification/closed',
+  'notification/action',
+] as const;
+
+/**
+ * JSON-RPC error response.
+ */
+interface JsonRpcError {
+  code: number;
+  message: string;
+  data?: unknown;
+}
+
+/**
+ * Handler result type.
+ */
+type HandlerResult<T> = { result: T } | { error: JsonRpcError };
+
+/**
+ * Notification handler type.
+ */
+type NotificationHandler = (notification: {
+  method: string;
+  params: unknown;
+}) => void;
+
+const CLOSE_REASONS: NotificationCloseReason[] = ['dismissed', 'action', 'cancelled', 'done', 'expired'];
+
+/**
+ * Notification Service Adapter for ECP protocol.
+ */
+export class NotificationServiceAdapter {
+  private notificationHandler?: NotificationHandler;
+
+  constructor(private readonly service: NotificationService) {
+    // Subscribe to events and forward as notifications
+    this.service.onShown((notification) => {
+      this.sendNotification('notification/shown', notification);
+    });
+
+    this.service.onUpdated((notification) => {
+      this.sendNotification('notification/updated', notification);
+    });
+
+    this.service.onClosed((event) => {
+      this.sendNotification('notification/closed', event);
+    });
+
+    this.service.onAction((event) => {
+      this.sendNotification('notification/action', event);
+    });
+  }
+
+  /**
+   * Set notification handler.
+   */
+  setNotificationHandler(handler: NotificationHandler): void {
+    this.notificationHandler = handler;
+  }
+
+  /**
+   * Send a notification.
+   */
+  private sendNotification(method: (typeof NOTIFICATION_NOTIFICATIONS)[number], params: unknown): void {
+    if (this.notificationHandler) {
+      this.notificationHandler({ method, params });
+    }
+  }
+
+  /**
+   * Handle an ECP request.
+   *
+   * @param method The method name (e.g., "notification/show")
+   * @param params The request parameters
+   * @returns The method result
+   */
+  async handleRequest(method: string, params: unknown): Promise<HandlerResult<unknown>> {
+    try {
+      switch (method) {
+        case 'notification/show':
+          return this.show(params);
+        case 'notification/progress':
+          return this.progress(params);
+        case 'notification/update':
+          return this.update(params);
+        case 'notification/close':
+          return this.close(params);
+        case 'notification/action':
+          return this.action(params);
+        case 'notification/cancel':
+          return this.cancel(params);
+        case 'notification/list':
+          return this.list(params);
+        case 'notification/clear':
+          return this.clear();
+        case 'notification/suppress':
+          return this.suppress(params);
+        case 'notification/unsuppress':
+          return this.unsuppress(params);
+        case 'notification/suppressed':
+          return { result: { keys: this.service.getSuppressed() } };
+
+        default:
+          return {
+            error: {
+              code: NotificationECPErrorCodes.MethodNotFound,
+              message: `Method not found: ${method}`,
+            },
+          };
+      }
+    } catch (error) {
+      return {
+        error: {
+          code: NotificationECPErrorCodes.InternalError,
+          message: error instanceof Error ? error.message : String(error),
+        },
+      };
+    }
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Handlers
+  // ─────────────────────────────────────────────────────────────────────────
+
+  private show(params: unknown): HandlerResult<{ id: string | null; suppressed: boolean }> {
+    const p = params as (NotificationOptions & { message: string }) | undefined;
+    if (!p?.message) {
+      return this.invalidParams('message is required');
+    }
+    if (p.actions && !p.actions.every((action) => action?.id && action?.label)) {
+      return this.invalidParams('actions need an id and a label');
+    }
+
+    const id = this.service.show(p.message, {
+      severity: p.severity,
+      source: p.source,
+      actions: p.actions,
+      timeout: p.timeout,
+      doNotShowAgainKey: p.doNotShowAgainKey,
+    });
+    return { result: { id, suppressed: id === null } };
+  }
+
+  private progress(params: unknown): HandlerResult<{ id: string }> {
+    const p = params as (ProgressOptions & { message: string }) | undefined;
+    if (!p?.message) {
+      return this.invalidParams('message is required');
+    }
+
+    const id = this.service.startProgress(p.message, {
+      source: p.source,
+      cancellable: p.cancellable,
+      percent: p.percent,
+    });
+    return { result: { id } };
+  }
+
+  private update(params: unknown): HandlerResult<{ success: boolean }> {
+    const p = params as (NotificationUpdate & { id: string }) | undefined;
+    if (!p?.id) {
+      return this.invalidParams('id is required');
+    }
+
+    if (!this.service.update(p.id, { message: p.message, severity: p.severity, percent: p.percent })) {
+      return this.notFound(p.id);
+    }
+    return { result: { success: true } };
+  }
+
+  private close(params: unknown): HandlerResult<{ success: boolean }> {
+    const p = params as { id: string; reason?: NotificationCloseReason } | undefined;
+    if (!p?.id) {
+      return this.invalidParams('id is required');
+    }
+    if (p.reason !== undefined && !CLOSE_REASONS.includes(p.reason)) {
+      return this.invalidParams(`reason must be one of: ${CLOSE_REASONS.join(', ')}`);
+    }
+
+    if (!this.service.close(p.id, p.reason)) {
+      return this.notFound(p.id);
+    }
+    return { result: { success: true } };
+  }
+
+  private action(params: unknown): HandlerResult<{ success: boolean }> {
+    const p = params as { id: string; actionId: string } | undefined;
+    if (!p?.id || !p?.actionId) {
+      return this.invalidParams('id and actionId are required');
+    }
+
+    const notification = this.service.get(p.id);
+    if (!notification || notification.closedAt !== undefined) {
+      return this.notFound(p.id);
+    }
+    if (!this.service.invokeAction(p.id, p.actionId)) {
+      return {
+        error: {
+          code: NotificationECPErrorCodes.ActionNotFound,
+          message: `Action not found: ${p.actionId}`,
+        },
+      };
+    }
+    return { result: { success: true } };
+  }
+
+  private cancel(params: unknown): HandlerResult<{ success: boolean }> {
+    const p = params as { id: string } | undefined;
+    if (!p?.id) {
+      return this.invalidParams('id is required');
+    }
+
+    const notification = this.service.get(p.id);
+    if (!notification || notification.closedAt !== undefined) {
+      return this.notFound(p.id);
+    }
+    if (!this.service.cancel(p.id)) {
+      return {
+        error: {
+          code: NotificationECPErrorCodes.NotCancellable,
+          message: `Notification is not cancellable: ${p.id}`,
+        },
+      };
+    }
+    return { result: { success: true } };
+  }
+
+  private list(params: unknown): HandlerResult<{ notifications: NotificationInfo[] }> {
+    const p = params as { activeOnly?: boolean } | undefined;
+    return { result: { notifications: this.service.list(p?.activeOnly ?? false) } };
+  }
+
+  private clear(): HandlerResult<{ success: boolean }> {
+    this.service.clearHistory();
+    return { result: { success: true } };
+  }
+
+  private suppress(params: unknown): HandlerResult<{ success: boolean }> {
+    const p = params as { key: string } | undefined;
+    if (!p?.key) {
+      return this.invalidParams('key is required');
+    }
+
+    this.service.suppress(p.key);
+    return { result: { success: true } };
+  }
+
+  private unsuppress(params: unknown): HandlerResult<{ success: boolean }> {
+    const p = params as { key: string } | undefined;
+    if (!p?.key) {
+      return this.invalidParams('key is required');
+    }
+
+    this.service.unsuppress(p.key);
+    return { result: { success: true } };
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Helpers
+  // ─────────────────────────────────────────────────────────────────────────
+
+  private invalidParams(message: string): { error: JsonRpcError } {
+    return { error: { code: NotificationECPErrorCodes.InvalidParams, message } };
+  }
+
+  private notFound(id: string): { error: JsonRpcError } {
+    return {
+      error: {
+        code: NotificationECPErrorCodes.NotificationNotFound,
+        message: `Notification not found: ${id}`,
+      },
+    };
+  }
+}
@@ -0,0 +1,37 @@
+/**
+ * Notification Service
+ *
+ * Lets services and extensions raise notifications with actions and
+ * progress, and reports the user's choices back.
+ */
+
+// Interface
+export type { NotificationService } from './interface.ts';
+
+// Types
+export type {
+  NotificationSeverity,
+  NotificationAction,
+  NotificationCloseReason,
+  NotificationOptions,
+  ProgressOptions,
+  NotificationProgress,
+  NotificationUpdate,
+  NotificationInfo,
+  NotificationClosedEvent,
+  NotificationActionEvent,
+  NotificationCallback,
+  NotificationClosedCallback,
+  NotificationActionCallback,
+  SuppressedChangeCallback,
+  Unsubscribe,
+} from './types.ts';
+
+// Implementation
+export { LocalNotificationService, localNotificationService } from './local.ts';
+
+// Adapter
+export { NotificationServiceAdapter, NotificationECPErrorCodes } from './adapter.ts';
+
+// Default export
+export { localNotificationService as default } from './local.ts';
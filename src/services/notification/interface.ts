@@ -0,0 +1,152 @@
+/**
+ * Notification Service Interface
+ *
+ * Defines the contract for raising user notifications from services and
+ * extensions. Clients render them as toasts and report user choices back.
+ */
+
+import type {
+  NotificationOptions,
+  ProgressOptions,
+  NotificationUpdate,
+  NotificationInfo,
+  NotificationCloseReason,
+  NotificationCallback,
+  NotificationClosedCallback,
+  NotificationActionCallback,
+  SuppressedChangeCallback,
+  Unsubscribe,
+} from './types.ts';
+
+/**
+ * Notification Service interface.
+ */
+export interface NotificationService {
+  // ─────────────────────────────────────────────────────────────────────────
+  // Notifications
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /**
+   * Show a notification.
+   *
+   * @returns Notification ID, or null if its "do not show again" key is suppressed
+   */
+  show(message: string, options?: NotificationOptions): string | null;
+
+  /**
+   * Start a progress notification. It stays open until closed with 'done'.
+   *
+   * @returns Notification ID
+   */
+  startProgress(message: string, options?: ProgressOptions): string;
+
+  /**
+   * Update an open notification.
+   *
+   * @returns false if it is not open
+   */
+  update(id: string, update: NotificationUpdate): boolean;
+
+  /**
+   * Close an open notification.
+   *
+   * @returns false if it is not open
+   */
+  close(id: string, reason?: NotificationCloseReason): boolean;
+
+  /**
+   * Choose one of a notification's actions. Closes the notification.
+   *
+   * @returns false if it is not open or has no such action
+   */
+  invokeAction(id: string, actionId: string): boolean;
+
+  /**
+   * Cancel a cancellable progress notification.
+   *
+   * @returns false if it is not open or not cancellable
+   */
+  cancel(id: string): boolean;
+
+  /**
+   * Wait for a notification to close.
+   *
+   * @returns The chosen action ID, or null if closed without one
+   */
+  waitForAction(id: string): Promise<string | null>;
+
+  /**
+   * Get a notification by ID (open or in history).
+   */
+  get(id: string): NotificationInfo | null;
+
+  /**
+   * List notifications, oldest first.
+   *
+   * @param activeOnly Only list open notifications
+   */
+  list(activeOnly?: boolean): NotificationInfo[];
+
+  /**
+   * Remove closed notifications from history.
+   */
+  clearHistory(): void;
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Do Not Show Again
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /**
+   * Stop showing notifications with this key. Closes open ones.
+   */
+  suppress(key: string): void;
+
+  /**
+   * Show notifications with this key again.
+   */
+  unsuppress(key: string): void;
+
+  /**
+   * Check if a key is suppressed.
+   */
+  isSuppressed(key: string): boolean;
+
+  /**
+   * Get all suppressed keys.
+   */
+  getSuppressed(): string[];
+
+  /**
+   * Replace the suppressed keys (e.g. from settings).
+   */
+  setSuppressed(keys: string[]): void;
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Events
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /**
+   * Subscribe to shown notifications.
+   */
+  onShown(callback: NotificationCallback): Unsubscribe;
+
+  /**
+   * Subscribe to notification updates.
+   */
+  onUpdated(callback: NotificationCallback): Unsubscribe;
+
+  /**
+   * Subscribe to closed notifications.
+   */
+  onClosed(callback: NotificationClosedCallback): Unsubscribe;
+
+  /**
+   * Subscribe to chosen actions.
+   */
+  onAction(callback: NotificationActionCallback): Unsubscribe;
+
+  /**
+   * Subscribe to changes of the suppressed keys.
+   */
+  onSuppressedChange(callback: SuppressedChangeCallback): Unsubscribe;
+}
@@ -0,0 +1,311 @@
+/**
+ * Local Notification Service Implementation
+ *
+ * Keeps open notifications and a bounded history in memory, and tracks
+ * "do not show again" keys. Rendering is left to clients, which subscribe
+ * to the events and report dismissals, actions and cancellations back.
+ */
+
+import { debugLog } from '../../debug.ts';
+import type { NotificationService } from './interface.ts';
+import type {
+  NotificationOptions,
+  ProgressOptions,
+  NotificationUpdate,
+  NotificationInfo,
+  NotificationCloseReason,
+  NotificationCallback,
+  NotificationClosedCallback,
+  NotificationClosedEvent,
+  NotificationActionCallback,
+  NotificationActionEvent,
+  SuppressedChangeCallback,
+  Unsubscribe,
+} from './types.ts';
+
+/** Closed notifications kept in history */
+const MAX_HISTORY = 100;
+
+/**
+ * Local Notification Service.
+ */
+export class LocalNotificationService implements NotificationService {
+  /** Open and closed notifications, oldest first */
+  private notifications: NotificationInfo[] = [];
+
+  /** Suppressed "do not show again" keys */
+  private suppressed = new Set<string>();
+
+  /** Pending waitForAction() calls by notification ID */
+  private waiters = new Map<string, Array<(actionId: string | null) => void>>();
+
+  private idCounter = 0;
+
+  private shownCallbacks = new Set<NotificationCallback>();
+  private updatedCallbacks = new Set<NotificationCallback>();
+  private closedCallbacks = new Set<NotificationClosedCallback>();
+  private actionCallbacks = new Set<NotificationActionCallback>();
+  private suppressedCallbacks = new Set<SuppressedChangeCallback>();
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Notifications
+  // ─────────────────────────────────────────────────────────────────────────
+
+  show(message: string, options: NotificationOptions = {}): string | null {
+    if (options.doNotShowAgainKey && this.suppressed.has(options.doNotShowAgainKey)) {
+      debugLog(`[NotificationService] Suppressed: ${options.doNotShowAgainKey}`);
+      return null;
+    }
+
+    const actions = options.actions ?? [];
+    return this.add({
+      id: this.nextId(),
+      message,
+      severity: options.severity ?? 'info',
+      source: options.source,
+      actions: actions.map((action) => ({ ...action })),
+      timeout: options.timeout ?? (actions.length > 0 ? 0 : undefined),
+      doNotShowAgainKey: options.doNotShowAgainKey,
+      createdAt: Date.now(),
+    });
+  }
+
+  startProgress(message: string, options: ProgressOptions = {}): string {
+    return this.add({
+      id: this.nextId(),
+      message,
+      severity: 'info',
+      source: options.source,
+      actions: [],
+      timeout: 0,
+      progress: {
+        percent: clampPercent(options.percent),
+        cancellable: options.cancellable ?? false,
+      },
+      createdAt: Date.now(),
+    });
+  }
+
+  update(id: string, update: NotificationUpdate): boolean {
+    const notification = this.findOpen(id);
+    if (!notification) return false;
+
+    if (update.message !== undefined) notification.message = update.message;
+    if (update.severity !== undefined) notification.severity = update.severity;
+    if (update.percent !== undefined && notification.progress) {
+      notification.progress.percent = clampPercent(update.percent);
+    }
+
+    this.emit(this.updatedCallbacks, copyInfo(notification));
+    return true;
+  }
+
+  close(id: string, reason: NotificationCloseReason = 'dismissed'): boolean {
+    return this.finish(id, reason);
+  }
+
+  invokeAction(id: string, actionId: string): boolean {
+    const notification = this.findOpen(id);
+    if (!notification || !notification.actions.some((action) => action.id === actionId)) {
+      return false;
+    }
+
+    const event: NotificationActionEvent = { id, actionId };
+    this.emit(this.actionCallbacks, event);
+    return this.finish(id, 'action', actionId);
+  }
+
+  cancel(id: string): boolean {
+    const notification = this.findOpen(id);
+    if (!notification?.progress?.cancellable) return false;
+    return this.finish(id, 'cancelled');
+  }
+
+  waitForAction(id: string): Promise<string | null> {
+    const notification = this.notifications.find((n) => n.id === id);
+    if (!notification || notification.closedAt !== undefined) {
+      return Promise.resolve(notification?.actionId ?? null);
+    }
+
+    return new Promise((resolve) => {
+      const waiting = this.waiters.get(id) ?? [];
+      waiting.push(resolve);
+      this.waiters.set(id, waiting);
+    });
+  }
+
+  get(id: string): NotificationInfo | null {
+    const notification = this.notifications.find((n) => n.id === id);
+    return notification ? copyInfo(notification) : null;
+  }
+
+  list(activeOnly = false): NotificationInfo[] {
+    return this.notifications
+      .filter((n) => !activeOnly || n.closedAt === undefined)
+      .map(copyInfo);
+  }
+
+  clearHistory(): void {
+    this.notifications = this.notifications.filter((n) => n.closedAt === undefined);
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Do Not Show Again
+  // ─────────────────────────────────────────────────────────────────────────
+
+  suppress(key: string): void {
+    if (this.suppressed.has(key)) return;
+    this.suppressed.add(key);
+
+    for (const notification of this.notifications) {
+      if (notification.closedAt === undefined && notification.doNotShowAgainKey === key) {
+        this.finish(notification.id, 'dismissed');
+      }
+    }
+
+    this.emit(this.suppressedCallbacks, this.getSuppressed());
+  }
+
+  unsuppress(key: string): void {
+    if (this.suppressed.delete(key)) {
+      this.emit(this.suppressedCallbacks, this.getSuppressed());
+    }
+  }
+
+  isSuppressed(key: string): boolean {
+    return this.suppressed.has(key);
+  }
+
+  getSuppressed(): string[] {
+    return [...this.suppressed].sort();
+  }
+
+  setSuppressed(keys: string[]): void {
+    this.suppressed = new Set(keys);
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Events
+  // ─────────────────────────────────────────────────────────────────────────
+
+  onShown(callback: NotificationCallback): Unsubscribe {
+    this.shownCallbacks.add(callback);
+    return () => {
+      this.shownCallbacks.delete(callback);
+    };
+  }
+
+  onUpdated(callback: NotificationCallback): Unsubscribe {
+    this.updatedCallbacks.add(callback);
+    return () => {
+      this.updatedCallbacks.delete(callback);
+    };
+  }
+
+  onClosed(callback: NotificationClosedCallback): Unsubscribe {
+    this.closedCallbacks.add(callback);
+    return () => {
+      this.closedCallbacks.delete(callback);
+    };
+  }
+
+  onAction(callback: NotificationActionCallback): Unsubscribe {
+    this.actionCallbacks.add(callback);
+    return () => {
+      this.actionCallbacks.delete(callback);
+    };
+  }
+
+  onSuppressedChange(callback: SuppressedChangeCallback): Unsubscribe {
+    this.suppressedCallbacks.add(callback);
+    return () => {
+      this.suppressedCallbacks.delete(callback);
+    };
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Helpers
+  // ─────────────────────────────────────────────────────────────────────────
+
+  private nextId(): string {
+    return `notification-${++this.idCounter}`;
+  }
+
+  private add(notification: NotificationInfo): string {
+    this.notifications.push(notification);
+    this.trimHistory();
+    this.emit(this.shownCallbacks, copyInfo(notification));
+    return notification.id;
+  }
+
+  private findOpen(id: string): NotificationInfo | undefined {
+    return this.notifications.find((n) => n.id === id && n.closedAt === undefined);
+  }
+
+  private finish(id: string, reason: NotificationCloseReason, actionId?: string): boolean {
+    const notification = this.findOpen(id);
+    if (!notification) return false;
+
+    notification.closedAt = Date.now();
+    notification.closeReason = reason;
+    notification.actionId = actionId;
+
+    const event: NotificationClosedEvent = { id, reason, actionId };
+    this.emit(this.closedCallbacks, event);
+
+    const waiting = this.waiters.get(id);
+    if (waiting) {
+      this.waiters.delete(id);
+      for (const resolve of waiting) {
+        resolve(actionId ?? null);
+      }
+    }
+
+    this.trimHistory();
+    return true;
+  }
+
+  /**
+   * Drop the oldest closed notifications beyond the history limit.
+   */
+  private trimHistory(): void {
+    let closed = this.notifications.filter((n) => n.closedAt !== undefined).length;
+    if (closed <= MAX_HISTORY) return;
+
+    this.notifications = this.notifications.filter((n) => {
+      if (n.closedAt === undefined || closed <= MAX_HISTORY) return true;
+      closed--;
+      return false;
+    });
+  }
+
+  private emit<T>(callbacks: Set<(value: T) => void>, value: T): void {
+    for (const callback of callbacks) {
+      try {
+        callback(value);
+      } catch (error) {
+        debugLog(`[NotificationService] Error in callback: ${error}`);
+      }
+    }
+  }
+}
+
+/**
+ * Clamp a progress percentage to 0-100.
+ */
+function clampPercent(percent: number | undefined): number | undefined {
+  if (percent === undefined || Number.isNaN(percent)) return undefined;
+  return Math.max(0, Math.min(100, percent));
+}
+
+function copyInfo(notification: NotificationInfo): NotificationInfo {
+  return {
+    ...notification,
+    actions: notification.actions.map((action) => ({ ...action })),
+    progress: notification.progress ? { ...notification.progress } : undefined,
+  };
+}
+
+// Singleton instance
+export const localNotificationService = new LocalNotificationService();
+export default localNotificationService;
@@ -0,0 +1,155 @@
+/**
+ * Notification Service Types
+ *
+ * Type definitions for the Notification Service.
+ */
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Notification Types
+// ─────────────────────────────────────────────────────────────────────────────
+
+/**
+ * Notification severity.
+ */
+export type NotificationSeverity = 'info' | 'warning' | 'error' | 'success';
+
+/**
+ * A button shown on a notification.
+ */
+export interface NotificationAction {
+  /** Action ID, reported back when the button is chosen */
+  id: string;
+
+  /** Button label */
+  label: string;
+}
+
+/**
+ * Why a notification closed.
+ */
+export type NotificationCloseReason =
+  | 'dismissed'
+  | 'action'
+  | 'cancelled'
+  | 'done'
+  | 'expired';
+
+/**
+ * Options for showing a notification.
+ */
+export interface NotificationOptions {
+  /** Severity (default: info) */
+  severity?: NotificationSeverity;
+
+  /** Who raised it (service or extension name) */
+  source?: string;
+
+  /** Action buttons */
+  actions?: NotificationAction[];
+
+  /**
+   * How long the toast stays up in ms. 0 keeps it until closed.
+   * Defaults to 0 for notifications with actions, otherwise left to the client.
+   */
+  timeout?: number;
+
+  /**
+   * Key for "do not show again". Once the user suppresses a key, notifications
+   * with that key are no longer shown.
+   */
+  doNotShowAgainKey?: string;
+}
+
+/**
+ * Options for starting a progress notification.
+ */
+export interface ProgressOptions {
+  /** Who raised it */
+  source?: string;
+
+  /** Whether the user can cancel it */
+  cancellable?: boolean;
+
+  /** Initial completion (0-100); omit for indeterminate progress */
+  percent?: number;
+}
+
+/**
+ * Progress state of a notification.
+ */
+export interface NotificationProgress {
+  /** Completion (0-100), undefined while indeterminate */
+  percent?: number;
+
+  /** Whether the user can cancel it */
+  cancellable: boolean;
+}
+
+/**
+ * Changes to an open notification.
+ */
+export interface NotificationUpdate {
+  message?: string;
+  severity?: NotificationSeverity;
+  /** New completion for progress notifications (0-100) */
+  percent?: number;
+}
+
+/**
+ * A notification, open or closed.
+ */
+export interface NotificationInfo {
+  id: string;
+  message: string;
+  severity: NotificationSeverity;
+  source?: string;
+  actions: NotificationAction[];
+  /** Toast timeout in ms (0 = until closed, undefined = client default) */
+  timeout?: number;
+  doNotShowAgainKey?: string;
+  /** Set for progress notifications */
+  progress?: NotificationProgress;
+  /** When it was shown (ms since epoch) */
+  createdAt: number;
+  /** When it closed (ms since epoch) */
+  closedAt?: number;
+  /** Why it closed */
+  closeReason?: NotificationCloseReason;
+  /** The action chosen, when closed by one */
+  actionId?: string;
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Event Types
+// ─────────────────────────────────────────────────────────────────────────────
+
+/**
+ * Notification closed event.
+ */
+export interface NotificationClosedEvent {
+  id: string;
+  reason: NotificationCloseReason;
+  /** The action chosen, when closed by one */
+  actionId?: string;
+}
+
+/**
+ * Notification action event.
+ */
+export interface NotificationActionEvent {
+  id: string;
+  actionId: string;
+}
+
+/**
+ * Callback types.
+ */
+export type NotificationCallback = (notification: NotificationInfo) => void;
+export type NotificationClosedCallback = (event: NotificationClosedEvent) => void;
+export type NotificationActionCallback = (event: NotificationActionEvent) => void;
+export type SuppressedChangeCallback = (keys: string[]) => void;
+
+/**
+ * Unsubscribe function.
+ */
+export type Unsubscribe = () => void;
@@ -0,0 +1,123 @@
+/**
+ * Search Service ECP Adapter
+ *
+ * Maps ECP JSON-RPC calls to SearchService methods.
+ */
+
+import type { SearchService } from './interface.ts';
+import type { SearchOptions, SearchResult, ReplaceResult } from './types.ts';
+
+/**
+ * ECP error codes (JSON-RPC 2.0 compatible).
+ */
+export const SearchECPErrorCodes = {
+  MethodNotFound: -32601,
+  InvalidParams: -32602,
+  InternalError: -32603,
+} as const;
+
+/**
+ * JSON-RPC error response.
+ */
+interface JsonRpcError {
+  code: number;
+  message: string;
+  data?: unknown;
+}
+
+/**
+ * Handler result type.
+ */
+type HandlerResult<T> = { result: T } | { error: JsonRpcError };
+
+/**
+ * Files and match ranges for `search/replaceInFiles`.
+ */
+type ReplaceFiles = { path: string; matches: { line: number; column: number; length: number }[]; version?: number }[];
+
+/**
+ * Search Service Adapter for ECP protocol.
+ *
+ * Open documents of the service's document service are searched and
+ * replaced in memory, so results reflect unsaved edits.
+ */
+export class SearchServiceAdapter {
+  constructor(private readonly service: SearchService) {}
+
+  /**
+   * Handle an ECP request.
+   *
+   * @param method The method name (e.g., "search/find")
+   * @param params The request parameters
+   * @returns The method result
+   */
+  async handleRequest(method: string, params: unknown): Promise<HandlerResult<unknown>> {
+    try {
+      switch (method) {
+        case 'search/find':
+          return await this.find(params);
+        case 'search/replace':
+          return await this.replace(params);
+        case 'search/replaceInFiles':
+          return await this.replaceInFiles(params);
+        case 'search/cancel':
+          this.service.cancel();
+          return { result: { success: true } };
+
+        default:
+          return {
+            error: {
+              code: SearchECPErrorCodes.MethodNotFound,
+              message: `Method not found: ${method}`,
+            },
+          };
+      }
+    } catch (error) {
+      return {
+        error: {
+          code: SearchECPErrorCodes.InternalError,
+          message: error instanceof Error ? error.message : String(error),
+        },
+      };
+    }
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Handlers
+  // ─────────────────────────────────────────────────────────────────────────
+
+  private async find(params: unknown): Promise<HandlerResult<SearchResult>> {
+    const p = params as { query: string; options?: SearchOptions };
+    if (typeof p?.query !== 'string') {
+      return this.invalidParams('query is required');
+    }
+
+    return { result: await this.service.search(p.query, p.options) };
+  }
+
+  private async replace(params: unknown): Promise<HandlerResult<ReplaceResult>> {
+    const p = params as { query: string; replacement: string; options?: SearchOptions };
+    if (typeof p?.query !== 'string' || typeof p.replacement !== 'string') {
+      return this.invalidParams('query and replacement are required');
+    }
+
+    return { result: await this.service.replace(p.query, p.replacement, p.options) };
+  }
+
+  private async replaceInFiles(params: unknown): Promise<HandlerResult<ReplaceResult>> {
+    const p = params as { files: ReplaceFiles; query: string; replacement: string; options?: SearchOptions };
+    if (!Array.isArray(p?.files) || typeof p.query !== 'string' || typeof p.replacement !== 'string') {
+      return this.invalidParams('files, query and replacement are required');
+    }
+
+    return { result: await this.service.replaceInFiles(p.files, p.query, p.replacement, p.options) };
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Helpers
+  // ─────────────────────────────────────────────────────────────────────────
+
+  private invalidParams(message: string): { error: JsonRpcError } {
+    return { error: { code: SearchECPErrorCodes.InvalidParams, message } };
+  }
+}
@@ -0,0 +1,169 @@
+/**
+ * Buffer Search
+ *
+ * Searches the in-memory content of open documents, whose unsaved edits
+ * ripgrep can't see, and merges those matches over the on-disk results.
+ */
+
+import * as path from 'path';
+import type { SearchOptions, SearchResult, SearchMatchResult, SearchFileResult } from './types.ts';
+import type { DocumentSnapshot } from '../document/types.ts';
+import { isInComment } from './comment-scope.ts';
+
+// ============================================
+// Types
+// ============================================
+
+/**
+ * The part of a document snapshot that searching reads.
+ */
+export type SearchSnapshot = Pick<DocumentSnapshot, 'version' | 'lineCount' | 'getLine'>;
+
+/**
+ * An open document to search instead of its file on disk.
+ */
+export interface SearchBuffer {
+  /** File path relative to the workspace */
+  path: string;
+  /** Snapshot of the document's in-memory content */
+  snapshot: SearchSnapshot;
+}
+
+// ============================================
+// Matching
+// ============================================
+
+/**
+ * Build the regex ripgrep would use for a query.
+ */
+export function buildSearchRegex(query: string, options: SearchOptions): RegExp {
+  let source = options.regex ? query : query.replace(/[.*+?^${}()|[\]\\]/g, '\\$&');
+  if (options.wholeWord) {
+    source = `\\b(?:${source})\\b`;
+  }
+  return new RegExp(source, options.caseSensitive ? 'g' : 'gi');
+}
+
+/**
+ * Find matches in content, line by line, as ripgrep reports them.
+ * Stops after `maxResults` matches (ripgrep's per-file `-m`).
+ */
+export function searchContent(
+  filePath: string,
+  content: string,
+  query: string,
+  options: SearchOptions = {}
+): SearchMatchResult[] {
+  const lines = content.split('\n');
+  return searchLines(filePath, lines.length, (i) => lines[i]!, query, options);
+}
+
+/**
+ * Find matches in a document snapshot, reading it line by line instead
+ * of copying its content.
+ */
+export function searchSnapshot(
+  filePath: string,
+  snapshot: SearchSnapshot,
+  query: string,
+  options: SearchOptions = {}
+): SearchMatchResult[] {
+  return searchLines(filePath, snapshot.lineCount, (i) => snapshot.getLine(i), query, options);
+}
+
+function searchLines(
+  filePath: string,
+  lineCount: number,
+  getLine: (lineNumber: number) => string,
+  query: string,
+  options: SearchOptions
+): SearchMatchResult[] {
+  if (!query) return [];
+
+  let regex: RegExp;
+  try {
+    regex = buildSearchRegex(query, options);
+  } catch {
+    return [];
+  }
+
+  const matches: SearchMatchResult[] = [];
+  for (let i = 0; i < lineCount; i++) {
+    const lineText = getLine(i).replace(/\r$/, '');
+    regex.lastIndex = 0;
+
+    let match: RegExpExecArray | null;
+    while ((match = regex.exec(lineText)) !== null) {
+      if (match[0].length === 0) {
+        regex.lastIndex++;
+        continue;
+      }
+      if (!options.commentsOnly || isInComment(filePath, lineText, match.index)) {
+        matches.push({ line: i + 1, column: match.index, length: match[0].length, lineText });
+        if (options.maxResults && matches.length >= options.maxResults) return matches;
+      }
+    }
+  }
+
+  return matches;
+}
+
+/**
+ * Check a path against an include/exclude glob. Like ripgrep, globs
+ * without a `/` match the file name at any depth.
+ */
+export function matchesSearchGlob(filePath: string, glob: string): boolean {
+  const matcher = new Bun.Glob(glob);
+  if (matcher.match(filePath) || new Bun.Glob(`**/${glob}`).match(filePath)) return true;
+  return !glob.includes('/') && matcher.match(path.basename(filePath));
+}
+
+// ============================================
+// Merging
+// ============================================
+
+/**
+ * Replace the on-disk results for open documents with matches in their
+ * buffers. Files whose buffer no longer matches are dropped, and buffers
+ * that match but weren't found on disk are added. Buffer results carry
+ * the document version they were found at.
+ */
+export function mergeBufferResults(
+  result: SearchResult,
+  buffers: SearchBuffer[],
+  options: SearchOptions = {}
+): SearchResult {
+  const bufferFiles = new Map<string, SearchFileResult>();
+  for (const buffer of buffers) {
+    if (options.includeGlob && !matchesSearchGlob(buffer.path, options.includeGlob)) continue;
+    if (options.excludeGlob && matchesSearchGlob(buffer.path, options.excludeGlob)) continue;
+    bufferFiles.set(buffer.path, {
+      path: buffer.path,
+      matches: searchSnapshot(buffer.path, buffer.snapshot, result.query, options),
+      version: buffer.snapshot.version,
+    });
+  }
+  if (bufferFiles.size === 0) return result;
+
+  const files: SearchFileResult[] = [];
+  for (const file of result.files) {
+    const bufferFile = bufferFiles.get(file.path);
+    if (!bufferFile) {
+      files.push(file);
+    } else if (bufferFile.matches.length > 0) {
+      files.push(bufferFile);
+    }
+    bufferFiles.delete(file.path);
+  }
+  for (const bufferFile of bufferFiles.values()) {
+    if (bufferFile.matches.length > 0) files.push(bufferFile);
+  }
+
+  const totalMatches = files.reduce((sum, file) => sum + file.matches.length, 0);
+  return {
+    ...result,
+    files,
+    totalMatches,
+    truncated: totalMatches >= (options.maxResults ?? 1000),
+  };
+}
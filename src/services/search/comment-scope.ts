@@ -0,0 +1,121 @@
+/**
+ * Comment Scope
+ *
+ * Decides whether a match on a line falls inside a comment, using the
+ * comment syntax of the file's language. Works line by line, so it
+ * recognises line comments, block comments that open on the line and
+ * block-comment continuation lines starting with `*`; lines in the middle
+ * of a block comment without that prefix are not detected.
+ */
+
+// ============================================
+// Types
+// ============================================
+
+/**
+ * Comment markers for a language.
+ */
+export interface CommentSyntax {
+  /** Line comment markers (e.g. `//`, `#`) */
+  line: string[];
+  /** Block comment openers (e.g. `/*`, `<!--`) */
+  blockStart: string[];
+  /** String delimiters, inside which markers are ignored */
+  quotes: string;
+  /** Whether `*`-prefixed lines are block comment continuations */
+  starContinuation?: boolean;
+}
+
+// ============================================
+// Languages
+// ============================================
+
+const C_STYLE: CommentSyntax = { line: ['//'], blockStart: ['/*'], quotes: '"\'`', starContinuation: true };
+const HASH: CommentSyntax = { line: ['#'], blockStart: [], quotes: '"\'' };
+const DASHES: CommentSyntax = { line: ['--'], blockStart: [], quotes: '"\'' };
+const SQL: CommentSyntax = { line: ['--'], blockStart: ['/*'], quotes: '"\'', starContinuation: true };
+const MARKUP: CommentSyntax = { line: [], blockStart: ['<!--'], quotes: '' };
+const CSS: CommentSyntax = { line: [], blockStart: ['/*'], quotes: '"\'', starContinuation: true };
+const LISP: CommentSyntax = { line: [';'], blockStart: [], quotes: '"' };
+
+const SYNTAX_BY_EXTENSION: Record<string, CommentSyntax> = {
+  ts: C_STYLE, tsx: C_STYLE, mts: C_STYLE, cts: C_STYLE,
+  js: C_STYLE, jsx: C_STYLE, mjs: C_STYLE, cjs: C_STYLE,
+  go: C_STYLE, rs: C_STYLE, java: C_STYLE, kt: C_STYLE, scala: C_STYLE,
+  c: C_STYLE, h: C_STYLE, cc: C_STYLE, cpp: C_STYLE, hpp: C_STYLE, cs: C_STYLE,
+  swift: C_STYLE, dart: C_STYLE, php: C_STYLE, zig: C_STYLE, jsonc: C_STYLE,
+  scss: C_STYLE, less: C_STYLE,
+  css: CSS,
+  py: HASH, rb: HASH, sh: HASH, bash: HASH, zsh: HASH, fish: HASH,
+  yaml: HASH, yml: HASH, toml: HASH, pl: HASH, r: HASH, ex: HASH, exs: HASH,
+  nix: HASH, conf: HASH, ini: HASH, mk: HASH,
+  sql: SQL, pgsql: SQL, psql: SQL,
+  lua: DASHES, hs: DASHES, elm: DASHES,
+  html: MARKUP, htm: MARKUP, xml: MARKUP, svg: MARKUP, md: MARKUP, vue: MARKUP, svelte: MARKUP,
+  clj: LISP, cljs: LISP, el: LISP, lisp: LISP, scm: LISP,
+};
+
+const SYNTAX_BY_FILENAME: Record<string, CommentSyntax> = {
+  Dockerfile: HASH,
+  Makefile: HASH,
+  Gemfile: HASH,
+  Rakefile: HASH,
+};
+
+/**
+ * Get the comment syntax for a file path, or null for unknown languages.
+ */
+export function getCommentSyntax(path: string): CommentSyntax | null {
+  const name = path.split('/').pop() ?? path;
+  const byName = SYNTAX_BY_FILENAME[name];
+  if (byName) return byName;
+  const dot = name.lastIndexOf('.');
+  if (dot < 0) return null;
+  return SYNTAX_BY_EXTENSION[name.slice(dot + 1).toLowerCase()] ?? null;
+}
+
+// ============================================
+// Scanning
+// ============================================
+
+/**
+ * Find the column where a comment starts on a line, skipping markers
+ * inside string literals. Returns -1 if the line has no comment.
+ */
+export function findCommentStart(lineText: string, syntax: CommentSyntax): number {
+  if (syntax.starContinuation && /^\s*\*/.test(lineText)) {
+    return lineText.indexOf('*');
+  }
+
+  const markers = [...syntax.line, ...syntax.blockStart];
+  let quote: string | null = null;
+
+  for (let i = 0; i < lineText.length; i++) {
+    const char = lineText[i]!;
+    if (quote) {
+      if (char === '\\') i++;
+      else if (char === quote) quote = null;
+      continue;
+    }
+    if (syntax.quotes.includes(char)) {
+      quote = char;
+      continue;
+    }
+    for (const marker of markers) {
+      if (lineText.startsWith(marker, i)) return i;
+    }
+  }
+
+  return -1;
+}
+
+/**
+ * Check whether the text at `column` on a line is inside a comment.
+ * Files of unknown languages are not filtered.
+ */
+export function isInComment(path: string, lineText: string, column: number): boolean {
+  const syntax = getCommentSyntax(path);
+  if (!syntax) return true;
+  const start = findCommentStart(lineText, syntax);
+  return start >= 0 && column >= start;
+}
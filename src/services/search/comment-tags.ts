@@ -0,0 +1,202 @@
+/**
+ * Comment Tags
+ *
+ * Indexes comment tags such as TODO and FIXME across the workspace using
+ * the search service with comment-scope filtering, and groups and filters
+ * them by file, tag, blame author and blame age.
+ */
+
+import type { SearchService } from './interface.ts';
+import type { SearchResult } from './types.ts';
+import type { GitBlame } from '../git/types.ts';
+
+// ============================================
+// Types
+// ============================================
+
+/**
+ * A tagged comment.
+ */
+export interface CommentTag {
+  /** File path relative to workspace */
+  path: string;
+  /** Line number (1-based) */
+  line: number;
+  /** Column of the tag (0-based) */
+  column: number;
+  /** Tag as written (e.g. "TODO") */
+  tag: string;
+  /** Comment text after the tag */
+  text: string;
+  /** Blame author, once blame is loaded */
+  author?: string;
+  /** Blame date (YYYY-MM-DD), once blame is loaded */
+  date?: string;
+}
+
+/**
+ * How tags are grouped.
+ */
+export type CommentTagGrouping = 'file' | 'tag' | 'author';
+
+/**
+ * A group of tags.
+ */
+export interface CommentTagGroup {
+  /** File path, tag or author */
+  key: string;
+  tags: CommentTag[];
+}
+
+/** Tags indexed by default */
+export const DEFAULT_COMMENT_TAGS = ['TODO', 'FIXME', 'HACK', 'XXX'];
+
+/** Group key for tags whose blame is not loaded */
+export const UNKNOWN_AUTHOR = 'Unknown';
+
+const MS_PER_DAY = 24 * 60 * 60 * 1000;
+
+// ============================================
+// Scanning
+// ============================================
+
+/**
+ * Build a regex matching any of the tags as whole words.
+ */
+export function buildTagPattern(tags: readonly string[]): string {
+  const escaped = tags.map((tag) => tag.replace(/[.*+?^${}()|[\]\\]/g, '\\$&'));
+  return `\\b(?:${escaped.join('|')})\\b`;
+}
+
+/**
+ * Turn tag search matches into comment tags.
+ */
+export function extractCommentTags(result: SearchResult, tags: readonly string[]): CommentTag[] {
+  const known = new Set(tags);
+  const found: CommentTag[] = [];
+
+  for (const file of result.files) {
+    for (const match of file.matches) {
+      const tag = match.lineText.slice(match.column, match.column + match.length);
+      if (!known.has(tag)) continue;
+      found.push({
+        path: file.path,
+        line: match.line,
+        column: match.column,
+        tag,
+        text: cleanTagText(match.lineText.slice(match.column + match.length)),
+      });
+    }
+  }
+
+  return found;
+}
+
+/**
+ * Strip the owner, separator and any block comment closer from the text
+ * after a tag, so `(alice): fix this` becomes `fix this`.
+ */
+function cleanTagText(rest: string): string {
+  return rest
+    .replace(/^\([^)]*\)/, '')
+    .replace(/^\s*[:\-]?\s*/, '')
+    .replace(/\s*(?:\*\/|-->)\s*$/, '')
+    .trim();
+}
+
+/**
+ * Search the workspace, or only one file in it (a workspace-relative
+ * path), for comment tags.
+ */
+export async function scanCommentTags(
+  searchService: SearchService,
+  tags: readonly string[] = DEFAULT_COMMENT_TAGS,
+  path?: string
+): Promise<CommentTag[]> {
+  if (tags.length === 0) return [];
+  const result = await searchService.search(buildTagPattern(tags), {
+    regex: true,
+    caseSensitive: true,
+    commentsOnly: true,
+    maxResults: 10000,
+    includeGlob: path?.replace(/[*?[\]{}!\\]/g, '\\$&'),
+  });
+  const found = extractCommentTags(result, tags);
+  return path === undefined ? found : found.filter((tag) => tag.path === path);
+}
+
+// ============================================
+// Blame
+// ============================================
+
+/**
+ * Fill in author and date for the tags in one file from its blame.
+ */
+export function applyBlame(tags: CommentTag[], path: string, blame: readonly GitBlame[]): void {
+  const byLine = new Map(blame.map((b) => [b.line, b]));
+  for (const tag of tags) {
+    if (tag.path !== path) continue;
+    const entry = byLine.get(tag.line);
+    if (entry) {
+      tag.author = entry.author;
+      tag.date = entry.date;
+    }
+  }
+}
+
+/**
+ * Age of a tag in whole days, or null if its blame is not loaded.
+ */
+export function tagAgeDays(tag: CommentTag, now: number = Date.now()): number | null {
+  if (!tag.date) return null;
+  const time = Date.parse(tag.date);
+  if (Number.isNaN(time)) return null;
+  return Math.max(0, Math.floor((now - time) / MS_PER_DAY));
+}
+
+/**
+ * Keep tags at least `minAgeDays` old. Tags without blame are dropped
+ * unless the filter is off (0).
+ */
+export function filterTagsByAge(tags: readonly CommentTag[], minAgeDays: number, now: number = Date.now()): CommentTag[] {
+  if (minAgeDays <= 0) return [...tags];
+  return tags.filter((tag) => (tagAgeDays(tag, now) ?? -1) >= minAgeDays);
+}
+
+// ============================================
+// Grouping
+// ============================================
+
+/**
+ * Group tags. File and author groups are sorted by name (unknown authors
+ * last); tag groups follow `tagOrder`. Tags within a group are sorted by
+ * file and line.
+ */
+export function groupCommentTags(
+  tags: readonly CommentTag[],
+  by: CommentTagGrouping,
+  tagOrder: readonly string[] = DEFAULT_COMMENT_TAGS
+): CommentTagGroup[] {
+  const groups = new Map<string, CommentTag[]>();
+  for (const tag of tags) {
+    const key = by === 'file' ? tag.path : by === 'tag' ? tag.tag : (tag.author ?? UNKNOWN_AUTHOR);
+    const group = groups.get(key);
+    if (group) group.push(tag);
+    else groups.set(key, [tag]);
+  }
+
+  const rank = (key: string): number => {
+    if (by === 'tag') {
+      const index = tagOrder.indexOf(key);
+      return index < 0 ? tagOrder.length : index;
+    }
+    return by === 'author' && key === UNKNOWN_AUTHOR ? 1 : 0;
+  };
+
+  return [...groups.entries()]
+    .sort(([a], [b]) => rank(a) - rank(b) || a.localeCompare(b))
+    .map(([key, items]) => ({
+      key,
+      tags: items.sort((a, b) => a.path.localeCompare(b.path) || a.line - b.line || a.column - b.column),
+    }));
+}
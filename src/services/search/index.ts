@@ -21,3 +21,32 @@ export type { SearchService } from './interface.ts';
 
 // Implementation
 export { LocalSearchService, localSearchService } from './local.ts';
+
+// ECP Adapter
+export { SearchServiceAdapter, SearchECPErrorCodes } from './adapter.ts';
+
+// Open document search
+export {
+  buildSearchRegex,
+  searchContent,
+  matchesSearchGlob,
+  mergeBufferResults,
+  type SearchBuffer,
+} from './buffer-search.ts';
+
+// Comment tags
+export { getCommentSyntax, findCommentStart, isInComment, type CommentSyntax } from './comment-scope.ts';
+export {
+  DEFAULT_COMMENT_TAGS,
+  UNKNOWN_AUTHOR,
+  buildTagPattern,
+  extractCommentTags,
+  scanCommentTags,
+  applyBlame,
+  tagAgeDays,
+  filterTagsByAge,
+  groupCommentTags,
+  type CommentTag,
+  type CommentTagGroup,
+  type CommentTagGrouping,
+} from './comment-tags.ts';
@@ -11,6 +11,7 @@ import type {
   SearchProgressCallback,
   Unsubscribe,
 } from './types.ts';
+import type { DocumentService } from '../document/interface.ts';
 
 /**
  * Search service interface.
@@ -28,6 +29,12 @@ export interface SearchService {
    */
   getWorkspaceRoot(): string;
 
+  /**
+   * Set the document service whose open documents are searched and
+   * replaced in memory instead of on disk. Pass null to search files only.
+   */
+  setDocumentService(service: DocumentService | null): void;
+
   /**
    * Search for a query across all files.
    *
@@ -50,14 +57,16 @@ export interface SearchService {
   /**
    * Replace matches in specific files only.
    *
-   * @param files Array of file paths with specific matches to replace
+   * @param files Array of file paths with specific matches to replace. Files
+   *   with the `version` a search found them at are remapped through edits
+   *   made to their open document since.
    * @param query Original search query
    * @param replacement Replacement text
    * @param options Search options (for regex mode)
    * @returns Replace results
    */
   replaceInFiles(
-    files: { path: string; matches: { line: number; column: number; length: number }[] }[],
+    files: { path: string; matches: { line: number; column: number; length: number }[]; version?: number }[],
     query: string,
     replacement: string,
     options?: SearchOptions
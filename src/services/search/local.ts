@@ -2,10 +2,17 @@
  * Local Search Service
  *
  * Search service implementation using ripgrep for fast file searching.
+ * With a document service attached, open documents are searched on
+ * snapshots and replaced in memory, so unsaved edits are seen and never
+ * overwritten, and matches follow edits made after the search.
  */
 
 import { $ } from 'bun';
+import * as path from 'path';
 import type { SearchService } from './interface.ts';
+import type { DocumentService } from '../document/interface.ts';
+import { localDocumentService } from '../document/local.ts';
+import type { ApplyEditsOptions } from '../document/types.ts';
 import type {
   SearchOptions,
   SearchResult,
@@ -15,6 +22,8 @@ import type {
   SearchProgressCallback,
   Unsubscribe,
 } from './types.ts';
+import { isInComment } from './comment-scope.ts';
+import { buildSearchRegex, mergeBufferResults, type SearchBuffer } from './buffer-search.ts';
 import { debugLog } from '../../debug.ts';
 
 // ============================================
@@ -25,6 +34,7 @@ export class LocalSearchService implements SearchService {
   private workspaceRoot: string = '';
   private abortController: AbortController | null = null;
   private progressCallbacks: Set<SearchProgressCallback> = new Set();
+  private documentService: DocumentService | null = null;
 
   // ─────────────────────────────────────────────────────────────────────────
   // Configuration
@@ -39,6 +49,10 @@ export class LocalSearchService implements SearchService {
     return this.workspaceRoot;
   }
 
+  setDocumentService(service: DocumentService | null): void {
+    this.documentService = service;
+  }
+
   // ─────────────────────────────────────────────────────────────────────────
   // Search
   // ─────────────────────────────────────────────────────────────────────────
@@ -85,7 +99,11 @@ export class LocalSearchService implements SearchService {
         debugLog(`[SearchService] ripgrep error: ${stderr}`);
       }
 
-      const result = this.parseRipgrepOutput(query, output, options);
+      const result = mergeBufferResults(
+        this.parseRipgrepOutput(query, output, options),
+        this.getOpenBuffers(),
+        options
+      );
       result.durationMs = Date.now() - startTime;
 
       // Notify progress complete
@@ -117,6 +135,34 @@ export class LocalSearchService implements SearchService {
     }
   }
 
+  /**
+   * Snapshots of the open documents in the workspace. Clean ones are
+   * searched too, so their results carry a version replaces can remap.
+   */
+  private getOpenBuffers(): SearchBuffer[] {
+    if (!this.documentService) return [];
+
+    const buffers: SearchBuffer[] = [];
+    for (const info of this.documentService.listOpen()) {
+      const relativePath = this.toWorkspacePath(info.uri);
+      const snapshot = relativePath ? this.documentService.getSnapshot(info.documentId) : null;
+      if (relativePath && snapshot) {
+        buffers.push({ path: relativePath, snapshot });
+      }
+    }
+    return buffers;
+  }
+
+  /**
+   * Path of a file:// URI relative to the workspace, or null if outside it.
+   */
+  private toWorkspacePath(uri: string): string | null {
+    if (!uri.startsWith('file://')) return null;
+    const relativePath = path.relative(this.workspaceRoot, uri.slice('file://'.length));
+    if (!relativePath || relativePath.startsWith('..') || path.isAbsolute(relativePath)) return null;
+    return relativePath;
+  }
+
   /**
    * Build ripgrep command-line arguments.
    */
@@ -187,17 +233,23 @@ export class LocalSearchService implements SearchService {
           const path = data.data.path.text;
           const lineNum = data.data.line_number;
           const lineText = data.data.lines.text.replace(/\n$/, ''); // Remove trailing newline
-
-          if (!filesMap.has(path)) {
-            filesMap.set(path, []);
-          }
+          // ripgrep reports byte offsets; columns are string offsets
+          const lineBytes = Buffer.from(lineText);
+          const toColumn = (offset: number) => lineBytes.subarray(0, offset).toString().length;
 
           // Handle multiple submatches on the same line
           for (const submatch of data.data.submatches) {
+            const column = toColumn(submatch.start);
+            if (options.commentsOnly && !isInComment(path, lineText, column)) {
+              continue;
+            }
+            if (!filesMap.has(path)) {
+              filesMap.set(path, []);
+            }
             filesMap.get(path)!.push({
               line: lineNum,
-              column: submatch.start,
-              length: submatch.end - submatch.start,
+              column,
+              length: toColumn(submatch.end) - column,
               lineText,
             });
             totalMatches++;
@@ -252,25 +304,49 @@ export class LocalSearchService implements SearchService {
         column: m.column,
         length: m.length,
       })),
+      version: f.version,
     }));
 
     return this.replaceInFiles(filesToReplace, query, replacement, options);
   }
 
   async replaceInFiles(
-    files: { path: string; matches: { line: number; column: number; length: number }[] }[],
+    files: { path: string; matches: { line: number; column: number; length: number }[]; version?: number }[],
     query: string,
     replacement: string,
     options: SearchOptions = {}
   ): Promise<ReplaceResult> {
     let filesModified = 0;
     let matchesReplaced = 0;
+    let matchesSkipped = 0;
     const errors: { path: string; error: string }[] = [];
+    const buffersModified: string[] = [];
 
     for (const fileInfo of files) {
       const filePath = `${this.workspaceRoot}/${fileInfo.path}`;
 
       try {
+        // Open documents are edited in memory (undoable, left unsaved)
+        const documentId = this.documentService?.findByUri(`file://${path.resolve(filePath)}`) ?? null;
+        if (documentId !== null) {
+          const { replaced, skipped } = this.replaceInDocument(
+            documentId,
+            fileInfo.matches,
+            fileInfo.version,
+            query,
+            replacement,
+            options
+          );
+          matchesSkipped += skipped;
+          if (replaced > 0) {
+            matchesReplaced += replaced;
+            filesModified++;
+            buffersModified.push(fileInfo.path);
+            debugLog(`[SearchService] Modified open document: ${fileInfo.path}`);
+          }
+          continue;
+        }
+
         // Read file content
         const file = Bun.file(filePath);
         const content = await file.text();
@@ -289,24 +365,11 @@ export class LocalSearchService implements SearchService {
           if (lineIdx < 0 || lineIdx >= lines.length) continue;
 
           const line = lines[lineIdx]!;
-
-          // Perform replacement
-          let actualReplacement = replacement;
-
-          // Handle regex group references if in regex mode
-          if (options.regex) {
-            const regex = new RegExp(query, options.caseSensitive ? 'g' : 'gi');
-            const matchText = line.substring(match.column, match.column + match.length);
-            const regexMatch = regex.exec(matchText);
-
-            if (regexMatch) {
-              // Replace $1, $2, etc. with capture groups
-              actualReplacement = replacement.replace(/\$(\d+)/g, (_, num) => {
-                const idx = parseInt(num, 10);
-                return regexMatch[idx] ?? '';
-              });
-            }
+          if (!this.matchesAt(line, match, query, options)) {
+            matchesSkipped++;
+            continue;
           }
+          const actualReplacement = this.expandReplacement(line, match, query, replacement, options);
 
           // Replace in line
           const before = line.substring(0, match.column);
@@ -338,9 +401,111 @@ export class LocalSearchService implements SearchService {
       filesModified,
       matchesReplaced,
       errors,
+      buffersModified,
+      matchesSkipped,
     };
   }
 
+  /**
+   * Replace matches in an open document as a single undo step. Matches
+   * found at an older `version` are first remapped through the edits
+   * made since. Returns the number of matches replaced and skipped.
+   */
+  private replaceInDocument(
+    documentId: string,
+    matches: { line: number; column: number; length: number }[],
+    version: number | undefined,
+    query: string,
+    replacement: string,
+    options: SearchOptions
+  ): { replaced: number; skipped: number } {
+    const service = this.documentService!;
+    const stale = version !== undefined && version !== service.getVersion(documentId);
+    const edits: ApplyEditsOptions['edits'] = [];
+    let skipped = 0;
+
+    for (const match of matches) {
+      let range = {
+        start: { line: match.line - 1, column: match.column },
+        end: { line: match.line - 1, column: match.column + match.length },
+      };
+      // Outside the change history, the re-check below still catches moved text
+      if (stale) {
+        range = service.transformRange(documentId, range, version) ?? range;
+      }
+
+      const current = { column: range.start.column, length: range.end.column - range.start.column };
+      const line = service.getLine(documentId, range.start.line);
+      if (!line || range.end.line !== range.start.line || !this.matchesAt(line.text, current, query, options)) {
+        skipped++;
+        continue;
+      }
+
+      edits.push({
+        range,
+        text: this.expandReplacement(line.text, current, query, replacement, options),
+      });
+    }
+
+    if (edits.length === 0) return { replaced: 0, skipped };
+
+    const result = service.applyEdits({ documentId, edits });
+    if (!result.success) {
+      throw new Error(result.error ?? 'Edit failed');
+    }
+    return { replaced: edits.length, skipped };
+  }
+
+  /**
+   * Whether the query matches exactly at the match's range of the line.
+   * Matches from a search the text has since changed under are skipped
+   * rather than overwriting whatever now sits at their old position.
+   */
+  private matchesAt(
+    line: string,
+    match: { column: number; length: number },
+    query: string,
+    options: SearchOptions
+  ): boolean {
+    let regex: RegExp;
+    try {
+      regex = buildSearchRegex(query, options);
+    } catch {
+      return false;
+    }
+
+    for (const found of line.matchAll(regex)) {
+      if (found.index === match.column) return found[0].length === match.length;
+      if (found.index > match.column) break;
+    }
+    return false;
+  }
+
+  /**
+   * Expand `$1`, `$2`, ... in the replacement from the match's capture
+   * groups (regex mode only).
+   */
+  private expandReplacement(
+    line: string,
+    match: { column: number; length: number },
+    query: string,
+    replacement: string,
+    options: SearchOptions
+  ): string {
+    if (!options.regex) return replacement;
+
+    const regex = new RegExp(query, options.caseSensitive ? 'g' : 'gi');
+    const matchText = line.substring(match.column, match.column + match.length);
+    const regexMatch = regex.exec(matchText);
+    if (!regexMatch) return replacement;
+
+    // Replace $1, $2, etc. with capture groups
+    return replacement.replace(/\$(\d+)/g, (_, num) => {
+      const idx = parseInt(num, 10);
+      return regexMatch[idx] ?? '';
+    });
+  }
+
   // ─────────────────────────────────────────────────────────────────────────
   // Cancellation
   // ─────────────────────────────────────────────────────────────────────────
@@ -376,4 +541,5 @@ export class LocalSearchService implements SearchService {
 // ============================================
 
 export const localSearchService = new LocalSearchService();
+localSearchService.setDocumentService(localDocumentService);
 export default localSearchService;
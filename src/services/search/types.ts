@@ -22,6 +22,8 @@ export interface SearchOptions {
   maxResults?: number;
   /** Number of context lines before/after matches (default: 0) */
   contextLines?: number;
+  /** Only keep matches inside comments (default: false) */
+  commentsOnly?: boolean;
 }
 
 /**
@@ -50,6 +52,8 @@ export interface SearchFileResult {
   path: string;
   /** Matches in this file */
   matches: SearchMatchResult[];
+  /** Version of the open document the matches were found in */
+  version?: number;
 }
 
 /**
@@ -78,6 +82,10 @@ export interface ReplaceResult {
   matchesReplaced: number;
   /** Errors encountered during replacement */
   errors: { path: string; error: string }[];
+  /** Files replaced in open documents rather than on disk (left unsaved) */
+  buffersModified?: string[];
+  /** Matches skipped because the text at their range no longer matches */
+  matchesSkipped?: number;
 }
 
 /**
@@ -0,0 +1,341 @@
+/**
+ * AI Session History
+ *
+ * Tracks AI chat sessions per workspace so past sessions can be browsed,
+ * resumed, and exported. Stores history in ~/.ultra/sessions/ai-history.json
+ * and transcripts in ~/.ultra/sessions/ai-transcripts/
+ */
+
+import { mkdir, unlink } from 'fs/promises';
+import { homedir } from 'os';
+import { dirname, join } from 'path';
+import { debugLog } from '../../debug.ts';
+import type { AIProvider } from './types.ts';
+
+const DEFAULT_HISTORY_FILE = join(homedir(), '.ultra', 'sessions', 'ai-history.json');
+
+const MAX_HISTORY_ENTRIES = 500;
+const MAX_PROMPT_LENGTH = 200;
+const SAVE_DEBOUNCE_MS = 1000;
+
+/**
+ * A recorded AI chat session.
+ */
+export interface AISessionHistoryEntry {
+  /** Provider session ID (used for resume) */
+  sessionId: string;
+  /** AI provider */
+  provider: AIProvider;
+  /** Custom provider ID when provider is 'custom' */
+  customProviderId?: string;
+  /** Workspace root the chat was opened in */
+  workspace: string;
+  /** Working directory of the AI process */
+  cwd: string;
+  /** First prompt submitted in the session */
+  firstPrompt?: string;
+  /** ISO timestamp of when the session was first recorded */
+  createdAt: string;
+  /** ISO timestamp of the last recorded activity */
+  lastActiveAt: string;
+}
+
+/**
+ * Identifies a session's transcript. Sessions of custom providers are told
+ * apart by their custom provider ID.
+ */
+export type AITranscriptKey = Pick<AISessionHistoryEntry, 'provider' | 'sessionId' | 'customProviderId'>;
+
+/**
+ * Filter for listing history entries.
+ */
+export interface AISessionHistoryFilter {
+  workspace?: string;
+  provider?: AIProvider;
+}
+
+/**
+ * Configuration options for AISessionHistory.
+ */
+export interface AISessionHistoryConfig {
+  /** Override the history file (for testing). */
+  historyFile?: string;
+  /** Delay before changes are written to disk (ms). */
+  saveDebounceMs?: number;
+}
+
+/**
+ * AI Session History.
+ *
+ * Entries are keyed by provider and session ID. Recording an existing
+ * session updates its activity timestamp and keeps the first prompt.
+ * Changes are written to disk after a short delay, so recording every
+ * prompt doesn't rewrite the file each time; call flush() before exit.
+ */
+export class AISessionHistory {
+  private historyFile: string;
+  private entries: AISessionHistoryEntry[] = [];
+  private loaded = false;
+  private saving: Promise<void> = Promise.resolve();
+  private saveDebounceMs: number;
+  private saveTimer: ReturnType<typeof setTimeout> | null = null;
+  /** Transcripts stored before a session was resumed, by transcript path */
+  private resumedTranscripts = new Map<string, string[]>();
+
+  constructor(config?: AISessionHistoryConfig) {
+    this.historyFile = config?.historyFile ?? DEFAULT_HISTORY_FILE;
+    this.saveDebounceMs = config?.saveDebounceMs ?? SAVE_DEBOUNCE_MS;
+  }
+
+  /**
+   * Load history from disk. Missing or corrupt files yield an empty history.
+   */
+  async load(): Promise<void> {
+    try {
+      const file = Bun.file(this.historyFile);
+      if (await file.exists()) {
+        const data = await file.json();
+        this.entries = Array.isArray(data?.sessions)
+          ? (data.sessions as AISessionHistoryEntry[]).filter((e) => e && e.sessionId && e.provider)
+          : [];
+      }
+    } catch (error) {
+      debugLog(`[AISessionHistory] Failed to load history: ${error}`);
+      this.entries = [];
+    }
+    this.loaded = true;
+  }
+
+  /**
+   * Record a session or update an existing one.
+   */
+  async record(
+    entry: Omit<AISessionHistoryEntry, 'createdAt' | 'lastActiveAt'> & { timestamp?: Date }
+  ): Promise<AISessionHistoryEntry> {
+    await this.ensureLoaded();
+
+    const now = (entry.timestamp ?? new Date()).toISOString();
+    const firstPrompt = entry.firstPrompt ? truncatePrompt(entry.firstPrompt) : undefined;
+    const existing = this.find(entry.provider, entry.sessionId);
+
+    let result: AISessionHistoryEntry;
+    if (existing) {
+      existing.lastActiveAt = now;
+      existing.cwd = entry.cwd || existing.cwd;
+      if (!existing.firstPrompt && firstPrompt) {
+        existing.firstPrompt = firstPrompt;
+      }
+      result = existing;
+    } else {
+      result = {
+        sessionId: entry.sessionId,
+        provider: entry.provider,
+        customProviderId: entry.customProviderId,
+        workspace: entry.workspace,
+        cwd: entry.cwd,
+        firstPrompt,
+        createdAt: now,
+        lastActiveAt: now,
+      };
+      this.entries.push(result);
+      if (this.entries.length > MAX_HISTORY_ENTRIES) {
+        this.entries.sort((a, b) => a.lastActiveAt.localeCompare(b.lastActiveAt));
+        const pruned = this.entries.splice(0, this.entries.length - MAX_HISTORY_ENTRIES);
+        await Promise.all(pruned.map((e) => this.deleteTranscript(e)));
+      }
+    }
+
+    this.scheduleSave();
+    return { ...result };
+  }
+
+  /**
+   * List sessions, most recently active first.
+   */
+  async list(filter: AISessionHistoryFilter = {}): Promise<AISessionHistoryEntry[]> {
+    await this.ensureLoaded();
+
+    return this.entries
+      .filter((e) => !filter.workspace || e.workspace === filter.workspace)
+      .filter((e) => !filter.provider || e.provider === filter.provider)
+      .sort((a, b) => b.lastActiveAt.localeCompare(a.lastActiveAt))
+      .map((e) => ({ ...e }));
+  }
+
+  /**
+   * Get a single session.
+   */
+  async get(provider: AIProvider, sessionId: string): Promise<AISessionHistoryEntry | null> {
+    await this.ensureLoaded();
+    const entry = this.find(provider, sessionId);
+    return entry ? { ...entry } : null;
+  }
+
+  /**
+   * Remove a session from history.
+   */
+  async remove(provider: AIProvider, sessionId: string): Promise<boolean> {
+    await this.ensureLoaded();
+    const index = this.entries.findIndex((e) => e.provider === provider && e.sessionId === sessionId);
+    if (index === -1) return false;
+    const [removed] = this.entries.splice(index, 1);
+    this.scheduleSave();
+    await this.deleteTranscript(removed!);
+    return true;
+  }
+
+  /**
+   * Write pending changes to disk now.
+   */
+  async flush(): Promise<void> {
+    if (this.saveTimer) {
+      clearTimeout(this.saveTimer);
+      this.saveTimer = null;
+      await this.save();
+    }
+    await this.saving;
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Transcripts
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /**
+   * Note that a session is being resumed. Transcripts saved from now on
+   * continue the one stored so far instead of replacing it.
+   */
+  async resumeTranscript(key: AITranscriptKey): Promise<void> {
+    const earlier = await this.getTranscript(key);
+    this.resumedTranscripts.set(this.transcriptPath(key), earlier ?? []);
+  }
+
+  /**
+   * Store a session's transcript (plain text lines). Replaces any earlier
+   * transcript, except the part stored before the session was resumed.
+   */
+  async saveTranscript(key: AITranscriptKey, lines: string[]): Promise<void> {
+    const file = this.transcriptPath(key);
+    const earlier = this.resumedTranscripts.get(file) ?? [];
+    try {
+      await mkdir(dirname(file), { recursive: true });
+      await Bun.write(file, [...earlier, ...lines].join('\n'));
+    } catch (error) {
+      debugLog(`[AISessionHistory] Failed to save transcript: ${error}`);
+    }
+  }
+
+  /**
+   * Get a session's stored transcript, or null if none was stored.
+   */
+  async getTranscript(key: AITranscriptKey): Promise<string[] | null> {
+    const file = Bun.file(this.transcriptPath(key));
+    try {
+      if (!(await file.exists())) return null;
+      return (await file.text()).split('\n');
+    } catch (error) {
+      debugLog(`[AISessionHistory] Failed to read transcript: ${error}`);
+      return null;
+    }
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Private
+  // ─────────────────────────────────────────────────────────────────────────
+
+  private find(provider: AIProvider, sessionId: string): AISessionHistoryEntry | undefined {
+    return this.entries.find((e) => e.provider === provider && e.sessionId === sessionId);
+  }
+
+  private async ensureLoaded(): Promise<void> {
+    if (!this.loaded) {
+      await this.load();
+    }
+  }
+
+  private transcriptPath(key: AITranscriptKey): string {
+    const owner = key.customProviderId ? `${key.provider}-${key.customProviderId}` : key.provider;
+    const name = `${owner}-${key.sessionId}`.replace(/[^\w.-]/g, '_');
+    return join(dirname(this.historyFile), 'ai-transcripts', `${name}.txt`);
+  }
+
+  private async deleteTranscript(key: AITranscriptKey): Promise<void> {
+    const file = this.transcriptPath(key);
+    this.resumedTranscripts.delete(file);
+    await unlink(file).catch(() => {});
+  }
+
+  private scheduleSave(): void {
+    if (this.saveTimer) return;
+    this.saveTimer = setTimeout(() => {
+      this.saveTimer = null;
+      this.save();
+    }, this.saveDebounceMs);
+  }
+
+  private async save(): Promise<void> {
+    // Serialize writes so concurrent records don't interleave
+    const content = JSON.stringify({ version: 1, sessions: this.entries }, null, 2);
+    this.saving = this.saving.then(async () => {
+      try {
+        await mkdir(dirname(this.historyFile), { recursive: true });
+        await Bun.write(this.historyFile, content);
+      } catch (error) {
+        debugLog(`[AISessionHistory] Failed to save history: ${error}`);
+      }
+    });
+    await this.saving;
+  }
+}
+
+/**
+ * Collapse whitespace and cap prompt length for display.
+ */
+function truncatePrompt(prompt: string): string {
+  const collapsed = prompt.replace(/\s+/g, ' ').trim();
+  return collapsed.length > MAX_PROMPT_LENGTH
+    ? `${collapsed.slice(0, MAX_PROMPT_LENGTH - 1)}…`
+    : collapsed;
+}
+
+// ============================================
+// Transcript Export
+// ============================================
+
+/**
+ * Metadata rendered at the top of an exported transcript.
+ */
+export interface AITranscriptMetadata {
+  providerName: string;
+  sessionId: string | null;
+  cwd: string;
+  firstPrompt?: string;
+  createdAt?: string;
+  exportedAt?: Date;
+}
+
+/**
+ * Render terminal transcript lines as Markdown.
+ * Lines are expected to be plain text (ANSI already stripped).
+ */
+export function formatTranscriptMarkdown(meta: AITranscriptMetadata, lines: string[]): string {
+  const exportedAt = (meta.exportedAt ?? new Date()).toISOString();
+  const body = lines.map((line) => line.trimEnd());
+
+  // Trim leading/trailing blank lines
+  while (body.length > 0 && body[0] === '') body.shift();
+  while (body.length > 0 && body[body.length - 1] === '') body.pop();
+
+  // Use a fence longer than any backtick run in the transcript
+  const longestRun = Math.max(0, ...body.map((l) => Math.max(0, ...(l.match(/`+/g) ?? []).map((r) => r.length))));
+  const fence = '`'.repeat(Math.max(3, longestRun + 1));
+
+  const out: string[] = [`# ${meta.providerName} session`, ''];
+  if (meta.sessionId) out.push(`- **Session:** \`${meta.sessionId}\``);
+  out.push(`- **Working directory:** \`${meta.cwd}\``);
+  if (meta.createdAt) out.push(`- **Started:** ${meta.createdAt}`);
+  out.push(`- **Exported:** ${exportedAt}`);
+  if (meta.firstPrompt) out.push(`- **First prompt:** ${meta.firstPrompt}`);
+  out.push('', '## Transcript', '', `${fence}text`, ...body, fence, '');
+
+  return out.join('\n');
+}
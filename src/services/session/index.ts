@@ -9,6 +9,7 @@ export type {
   EditorSettings,
   SessionDocumentState,
   SessionTerminalState,
+  SessionReplState,
   SessionAIChatState,
   SessionSQLEditorState,
   AIProvider,
@@ -53,3 +54,14 @@ export { LocalSessionService, localSessionService, type SessionPathsConfig } fro
 
 // Adapter
 export { SessionServiceAdapter, SessionECPErrorCodes } from './adapter.ts';
+
+// AI session history
+export {
+  AISessionHistory,
+  formatTranscriptMarkdown,
+  type AISessionHistoryEntry,
+  type AISessionHistoryFilter,
+  type AISessionHistoryConfig,
+  type AITranscriptMetadata,
+  type AITranscriptKey,
+} from './ai-history.ts';
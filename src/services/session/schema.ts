@@ -211,6 +211,11 @@ export const settingsSchema: SettingsSchema = {
       maximum: 10,
       description: 'Number of tabs to scroll when clicking tab bar arrows',
     },
+    'tui.tabBar.enablePreview': {
+      type: 'boolean',
+      default: true,
+      description: 'Open files from quick open, the file tree and search results as preview tabs',
+    },
 
     // ─────────────────────────────────────────────────────────────────────────
     // Outline Panel Settings
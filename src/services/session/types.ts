@@ -9,6 +9,21 @@
  */
 export type { EditorSettings } from '../../config/settings.ts';
 
+import type { TerminalWatchRule } from '../../terminal/output-watcher.ts';
+import type { ReplDefinition } from '../../terminal/repl.ts';
+
+/**
+ * Session state for a REPL running in a terminal.
+ */
+export interface SessionReplState {
+  /** Language the REPL evaluates */
+  languageId: string;
+  /** REPL program */
+  repl: ReplDefinition;
+  /** URIs of documents that send code to this REPL */
+  documents: string[];
+}
+
 /**
  * Session state for a terminal in a pane.
  */
@@ -25,6 +40,10 @@ export interface SessionTerminalState {
   cwd: string;
   /** Terminal title */
   title: string;
+  /** Watch rules added to this terminal */
+  watchRules?: TerminalWatchRule[];
+  /** Set when the terminal runs a REPL instead of a shell */
+  repl?: SessionReplState;
 }
 
 /**
@@ -32,6 +51,37 @@ export interface SessionTerminalState {
  */
 export type AIProvider = 'claude-code' | 'codex' | 'gemini' | 'custom';
 
+/**
+ * User-defined AI chat provider (from the ai.customProviders setting).
+ *
+ * Strings in command, args, resumeArgs and env may use the placeholders
+ * ${sessionId}, ${cwd}, ${prompt} and ${env:NAME}.
+ */
+export interface CustomAIProviderConfig {
+  /** Unique provider ID (usable as ai.defaultProvider) */
+  id: string;
+  /** Display name for tabs and pickers */
+  name: string;
+  /** Executable to run */
+  command: string;
+  /** Arguments for a new session */
+  args?: string[];
+  /** Arguments when resuming a captured session (defaults to args) */
+  resumeArgs?: string[];
+  /** Extra environment variables */
+  env?: Record<string, string>;
+  /** Regex matched against output to capture the session ID (group 1, or the whole match) */
+  sessionIdPattern?: string;
+  /** Initial prompt for new sessions (defaults to ai.panel.initialPrompt) */
+  initialPrompt?: string;
+  /** How the initial prompt is delivered: 'none', 'arg' (via ${prompt}) or 'type' (typed once output settles) */
+  initialPromptMode?: 'none' | 'arg' | 'type';
+  /** Working directory: 'workspace' (default), 'file' (active file's directory) or a path */
+  cwd?: string;
+  /** Whether the tool draws its own cursor (ink-based TUIs) */
+  usesInkCursor?: boolean;
+}
+
 /**
  * Session state for an AI chat in a pane.
  */
@@ -46,6 +96,8 @@ export interface SessionAIChatState {
   isActiveInPane: boolean;
   /** AI provider (claude-code, codex, etc.) */
   provider: AIProvider;
+  /** Custom provider ID when provider is 'custom' */
+  customProviderId?: string;
   /** Session ID for resume (Claude --resume support) */
   sessionId: string | null;
   /** Working directory for the AI chat */
@@ -105,14 +157,18 @@ export interface SessionDocumentState {
   /** Selection anchor (if any) */
   selectionAnchorLine?: number;
   selectionAnchorColumn?: number;
-  /** Folded line numbers */
-  foldedRegions: number[];
+  /** Folded region start lines; folded manual regions are saved as ranges */
+  foldedRegions: Array<number | { startLine: number; endLine: number }>;
   /** Pane ID where document is open */
   paneId: string;
   /** Tab order within pane (0-indexed) */
   tabOrder: number;
   /** Whether this tab is active in its pane */
   isActiveInPane: boolean;
+  /** Whether this tab is pinned */
+  pinned?: boolean;
+  /** Whether this tab is its pane's preview tab */
+  preview?: boolean;
   /** Unsaved content (if file was modified) */
   unsavedContent?: string;
   /** Undo/redo history for session persistence */
@@ -27,6 +27,15 @@ export const TerminalECPErrorCodes = {
   WriteFailed: -32706,
 } as const;
 
+/**
+ * Notifications sent by this adapter.
+ */
+export const TERMINAL_NOTIFICATIONS = [
+  'terminal/output',
+  'terminal/exit',
+  'terminal/title',
+] as const;
+
 /**
  * JSON-RPC error response.
  */
@@ -82,7 +91,7 @@ export class TerminalServiceAdapter {
   /**
    * Send a notification.
    */
-  private sendNotification(method: string, params: unknown): void {
+  private sendNotification(method: (typeof TERMINAL_NOTIFICATIONS)[number], params: unknown): void {
     if (this.notificationHandler) {
       this.notificationHandler({ method, params });
     }
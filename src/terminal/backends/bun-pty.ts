@@ -112,6 +112,10 @@ export class BunPtyBackend implements PTYBackend {
     return this.pty.isCursorVisible();
   }
 
+  isBracketedPasteEnabled(): boolean {
+    return this.pty.isBracketedPasteEnabled();
+  }
+
   getViewOffset(): number {
     return this.pty.getViewOffset();
   }
@@ -120,6 +124,10 @@ export class BunPtyBackend implements PTYBackend {
     return this.pty.getTotalLines();
   }
 
+  getTextLines(): string[] {
+    return this.pty.getTextLines();
+  }
+
   scrollViewUp(lines: number): boolean {
     return this.pty.scrollViewUp(lines);
   }
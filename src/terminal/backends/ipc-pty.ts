@@ -309,6 +309,10 @@ export class IpcPtyBackend implements PTYBackend {
     return this.screenBuffer.isCursorVisible();
   }
 
+  isBracketedPasteEnabled(): boolean {
+    return this.screenBuffer.isBracketedPaste();
+  }
+
   getViewOffset(): number {
     return this.screenBuffer.getViewOffset();
   }
@@ -317,6 +321,10 @@ export class IpcPtyBackend implements PTYBackend {
     return this.screenBuffer.getTotalLines();
   }
 
+  getTextLines(): string[] {
+    return this.screenBuffer.getTextLines();
+  }
+
   scrollViewUp(lines: number): boolean {
     return this.screenBuffer.scrollViewUp(lines);
   }
@@ -167,6 +167,10 @@ export class NodePtyBackend implements PTYBackend {
     return this.screenBuffer.isCursorVisible();
   }
 
+  isBracketedPasteEnabled(): boolean {
+    return this.screenBuffer.isBracketedPaste();
+  }
+
   getViewOffset(): number {
     return this.screenBuffer.getViewOffset();
   }
@@ -175,6 +179,10 @@ export class NodePtyBackend implements PTYBackend {
     return this.screenBuffer.getTotalLines();
   }
 
+  getTextLines(): string[] {
+    return this.screenBuffer.getTextLines();
+  }
+
   scrollViewUp(lines: number): boolean {
     return this.screenBuffer.scrollViewUp(lines);
   }
@@ -0,0 +1,443 @@
+/**
+ * Terminal Output Watcher
+ *
+ * Watches raw PTY output for user-defined patterns ("FAIL", "panic:",
+ * "listening on :8080") and for command completion, so the UI can raise
+ * alerts without the user staring at a terminal tab.
+ *
+ * Command completion is detected from shell integration marks
+ * (OSC 133;D;<exit>) when the shell emits them, and otherwise by the
+ * prompt reappearing at the end of the output.
+ */
+
+import { debugLog } from '../debug.ts';
+import type { Unsubscribe } from './pty-backend.ts';
+
+// ============================================
+// Types
+// ============================================
+
+/**
+ * Action to take when a watch rule matches.
+ * - notify: status bar / toast notification
+ * - bell: ring the host terminal bell
+ * - desktop: OSC 99 desktop notification via the host terminal
+ * - focus: switch focus to the terminal tab
+ * - command: run an Ultra command (see `TerminalWatchRule.command`)
+ */
+export type TerminalWatchAction = 'notify' | 'bell' | 'desktop' | 'focus' | 'command';
+
+/**
+ * A watch rule attached to a terminal.
+ */
+export interface TerminalWatchRule {
+  /** Unique rule ID (generated if omitted) */
+  id?: string;
+  /** Regular expression source matched against each output line */
+  pattern: string;
+  /** Regular expression flags (default: none) */
+  flags?: string;
+  /** Actions to perform on match (default: ['notify']) */
+  actions?: TerminalWatchAction[];
+  /** Ultra command ID to run for the 'command' action */
+  command?: string;
+  /** Notification severity (default: 'info') */
+  severity?: 'info' | 'warning' | 'error' | 'success';
+  /** Message template; `$0` is the matched text, `$1`..`$9` are groups */
+  message?: string;
+  /** Remove the rule after its first match */
+  once?: boolean;
+}
+
+/**
+ * A rule match reported by the watcher.
+ */
+export interface TerminalWatchMatch {
+  rule: TerminalWatchRule;
+  /** Output line (ANSI stripped) that matched */
+  line: string;
+  /** Rendered notification message */
+  message: string;
+  /** Regex match groups (index 0 is the full match) */
+  groups: string[];
+}
+
+/**
+ * Reported when a watched command finishes.
+ */
+export interface CommandFinishedEvent {
+  /** Exit code if reported by shell integration, otherwise null */
+  exitCode: number | null;
+  /** Time since the watch was armed, in milliseconds */
+  durationMs: number;
+  /** How completion was detected */
+  source: 'osc133' | 'prompt';
+}
+
+/**
+ * Options for the output watcher.
+ */
+export interface OutputWatcherOptions {
+  /** Regex source matching a shell prompt at the end of the output */
+  promptPattern?: string;
+  /** How long the prompt must stay idle before completion fires (ms) */
+  promptSettleMs?: number;
+}
+
+/** Default prompt pattern: a typical prompt terminator at end of line */
+export const DEFAULT_PROMPT_PATTERN = '[$#%>❯»]\\s*$';
+
+/** Maximum length of a buffered partial line */
+const MAX_PARTIAL_LENGTH = 4096;
+
+/** Shell integration "command finished" mark: OSC 133 ; D [; exit] ST */
+const OSC133_FINISHED = /\x1b\]133;D(?:;(-?\d+))?[^\x07\x1b]*(?:\x07|\x1b\\)/g;
+
+/** ANSI escape sequences (CSI, OSC, charset designation, two-byte ESC) */
+const ANSI_PATTERN =
+  /\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)|\x1b\[[0-?]*[ -/]*[@-~]|\x1b[()*+][0-9A-Za-z]|\x1b[@-Z\\-_78=>]|[\x00-\x08\x0b\x0c\x0e-\x1a\x1c-\x1f\x7f]/g;
+
+/**
+ * Strip ANSI escape sequences and non-printing control characters.
+ * Newlines, carriage returns and tabs are preserved.
+ */
+export function stripAnsi(text: string): string {
+  return text.replace(ANSI_PATTERN, '');
+}
+
+/**
+ * Split off a trailing, unterminated escape sequence so it can be
+ * completed by the next chunk of output.
+ */
+function splitIncompleteEscape(data: string): [string, string] {
+  // OSC: complete only when terminated by BEL or ST
+  const oscStart = data.lastIndexOf('\x1b]');
+  if (oscStart !== -1) {
+    const body = data.slice(oscStart + 2);
+    if (!body.includes('\x07') && !body.includes('\x1b\\')) {
+      return [data.slice(0, oscStart), data.slice(oscStart)];
+    }
+  }
+
+  const lastEsc = data.lastIndexOf('\x1b');
+  if (lastEsc === -1) return [data, ''];
+
+  const tail = data.slice(lastEsc);
+  if (tail.length === 1 || /^\x1b[()*+]$/.test(tail)) {
+    return [data.slice(0, lastEsc), tail];
+  }
+  // CSI: complete once a final byte is present
+  if (tail.startsWith('\x1b[') && !/^\x1b\[[0-?]*[ -/]*[@-~]/.test(tail)) {
+    return [data.slice(0, lastEsc), tail];
+  }
+  return [data, ''];
+}
+
+/**
+ * Render a rule's message template for a match.
+ */
+function renderMessage(rule: TerminalWatchRule, groups: string[], line: string): string {
+  if (!rule.message) {
+    return line.trim();
+  }
+  return rule.message.replace(/\$(\d)/g, (_, index: string) => groups[parseInt(index, 10)] ?? '');
+}
+
+// ============================================
+// Output Watcher
+// ============================================
+
+let nextRuleId = 1;
+
+/**
+ * Watches terminal output for pattern matches and command completion.
+ */
+export class OutputWatcher {
+  private rules: TerminalWatchRule[] = [];
+  private compiled = new Map<string, RegExp>();
+  private matchCallbacks = new Set<(match: TerminalWatchMatch) => void>();
+  private finishedCallbacks = new Set<(event: CommandFinishedEvent) => void>();
+
+  /** Trailing, unterminated escape sequence from the last chunk */
+  private pendingEscape = '';
+  /** Current (unterminated) output line, ANSI stripped */
+  private partialLine = '';
+
+  /** Command completion watch state */
+  private armed = false;
+  private armedAt = 0;
+  private outputSinceArmed = false;
+  private settleTimer: ReturnType<typeof setTimeout> | null = null;
+
+  private promptRegex: RegExp;
+  private promptSettleMs: number;
+
+  constructor(rules: TerminalWatchRule[] = [], options: OutputWatcherOptions = {}) {
+    this.promptRegex = this.compilePrompt(options.promptPattern);
+    this.promptSettleMs = options.promptSettleMs ?? 300;
+    this.setRules(rules);
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Rules
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /**
+   * Replace all rules. Rules with invalid patterns are skipped.
+   */
+  setRules(rules: TerminalWatchRule[]): void {
+    this.rules = [];
+    this.compiled.clear();
+    for (const rule of rules) {
+      this.addRule(rule);
+    }
+  }
+
+  /**
+   * Add a rule.
+   * @returns The rule ID, or null if the pattern is not a valid regex
+   */
+  addRule(rule: TerminalWatchRule): string | null {
+    const id = rule.id ?? `watch-${nextRuleId++}`;
+    let regex: RegExp;
+    try {
+      // Strip 'g'/'y' so exec() is stateless across lines
+      regex = new RegExp(rule.pattern, (rule.flags ?? '').replace(/[gy]/g, ''));
+    } catch (error) {
+      debugLog(`[OutputWatcher] Invalid watch pattern "${rule.pattern}": ${error}`);
+      return null;
+    }
+    const stored = { ...rule, id };
+    this.rules = this.rules.filter((r) => r.id !== id);
+    this.rules.push(stored);
+    this.compiled.set(id, regex);
+    return id;
+  }
+
+  /**
+   * Remove a rule by ID.
+   */
+  removeRule(id: string): boolean {
+    const before = this.rules.length;
+    this.rules = this.rules.filter((r) => r.id !== id);
+    this.compiled.delete(id);
+    return this.rules.length !== before;
+  }
+
+  /**
+   * Get all rules.
+   */
+  getRules(): TerminalWatchRule[] {
+    return [...this.rules];
+  }
+
+  /**
+   * Change the prompt pattern used for completion detection.
+   */
+  setPromptPattern(pattern: string | undefined): void {
+    this.promptRegex = this.compilePrompt(pattern);
+  }
+
+  private compilePrompt(pattern: string | undefined): RegExp {
+    try {
+      return new RegExp(pattern || DEFAULT_PROMPT_PATTERN);
+    } catch (error) {
+      debugLog(`[OutputWatcher] Invalid prompt pattern "${pattern}": ${error}`);
+      return new RegExp(DEFAULT_PROMPT_PATTERN);
+    }
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Command Completion
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /**
+   * Arm a one-shot watch that fires when the running command finishes.
+   */
+  armCommandFinished(): void {
+    this.armed = true;
+    this.armedAt = Date.now();
+    this.outputSinceArmed = false;
+    this.clearSettleTimer();
+  }
+
+  /**
+   * Cancel a pending command completion watch.
+   */
+  disarmCommandFinished(): void {
+    this.armed = false;
+    this.clearSettleTimer();
+  }
+
+  /**
+   * Check if a command completion watch is pending.
+   */
+  isCommandFinishedArmed(): boolean {
+    return this.armed;
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Callbacks
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /**
+   * Register callback for rule matches.
+   */
+  onMatch(callback: (match: TerminalWatchMatch) => void): Unsubscribe {
+    this.matchCallbacks.add(callback);
+    return () => {
+      this.matchCallbacks.delete(callback);
+    };
+  }
+
+  /**
+   * Register callback for command completion.
+   */
+  onCommandFinished(callback: (event: CommandFinishedEvent) => void): Unsubscribe {
+    this.finishedCallbacks.add(callback);
+    return () => {
+      this.finishedCallbacks.delete(callback);
+    };
+  }
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Processing
+  // ─────────────────────────────────────────────────────────────────────────
+
+  /**
+   * Feed raw terminal output (may contain ANSI sequences and partial lines).
+   */
+  feed(data: string): void {
+    const [complete, pending] = splitIncompleteEscape(this.pendingEscape + data);
+    // Drop runaway sequences that never terminate
+    this.pendingEscape = pending.length > MAX_PARTIAL_LENGTH ? '' : pending;
+    if (!complete) return;
+
+    // Shell integration marks take priority over prompt heuristics
+    OSC133_FINISHED.lastIndex = 0;
+    let mark: RegExpExecArray | null;
+    let finishedCode: number | null | undefined;
+    while ((mark = OSC133_FINISHED.exec(complete)) !== null) {
+      finishedCode = mark[1] !== undefined ? parseInt(mark[1], 10) : null;
+    }
+
+    const text = stripAnsi(complete);
+    if (this.armed && text.trim().length > 0) {
+      this.outputSinceArmed = true;
+    }
+
+    const parts = (this.partialLine + text).split('\n');
+    this.partialLine = parts.pop() ?? '';
+    if (this.partialLine.length > MAX_PARTIAL_LENGTH) {
+      this.partialLine = this.partialLine.slice(-MAX_PARTIAL_LENGTH);
+    }
+
+    for (const raw of parts) {
+      this.matchLine(this.normalizeLine(raw));
+    }
+
+    if (finishedCode !== undefined) {
+      this.fireFinished(finishedCode, 'osc133');
+    } else {
+      this.checkPrompt();
+    }
+  }
+
+  /**
+   * Flush the partial line through the rules (e.g. when the process exits).
+   */
+  flush(): void {
+    if (this.partialLine) {
+      this.matchLine(this.normalizeLine(this.partialLine));
+      this.partialLine = '';
+    }
+  }
+
+  /**
+   * Release timers and callbacks.
+   */
+  dispose(): void {
+    this.clearSettleTimer();
+    this.matchCallbacks.clear();
+    this.finishedCallbacks.clear();
+  }
+
+  /**
+   * Apply carriage returns: the visible line is the text after the last CR.
+   */
+  private normalizeLine(line: string): string {
+    const trimmed = line.endsWith('\r') ? line.slice(0, -1) : line;
+    const lastCr = trimmed.lastIndexOf('\r');
+    return lastCr === -1 ? trimmed : trimmed.slice(lastCr + 1);
+  }
+
+  private matchLine(line: string): void {
+    if (!line || this.rules.length === 0) return;
+
+    for (const rule of [...this.rules]) {
+      const regex = this.compiled.get(rule.id!);
+      if (!regex) continue;
+      const result = regex.exec(line);
+      if (!result) continue;
+
+      const groups = Array.from(result, (g) => g ?? '');
+      const match: TerminalWatchMatch = {
+        rule,
+        line,
+        message: renderMessage(rule, groups, line),
+        groups,
+      };
+
+      if (rule.once) {
+        this.removeRule(rule.id!);
+      }
+
+      for (const callback of this.matchCallbacks) {
+        try {
+          callback(match);
+        } catch (error) {
+          debugLog(`[OutputWatcher] Match callback error: ${error}`);
+        }
+      }
+    }
+  }
+
+  private checkPrompt(): void {
+    this.clearSettleTimer();
+    if (!this.armed || !this.outputSinceArmed) return;
+    if (!this.promptRegex.test(this.normalizeLine(this.partialLine))) return;
+
+    this.settleTimer = setTimeout(() => {
+      this.settleTimer = null;
+      if (this.armed && this.promptRegex.test(this.normalizeLine(this.partialLine))) {
+        this.fireFinished(null, 'prompt');
+      }
+    }, this.promptSettleMs);
+  }
+
+  private fireFinished(exitCode: number | null, source: CommandFinishedEvent['source']): void {
+    if (!this.armed) return;
+    this.armed = false;
+    this.clearSettleTimer();
+
+    const event: CommandFinishedEvent = {
+      exitCode,
+      durationMs: Date.now() - this.armedAt,
+      source,
+    };
+    for (const callback of this.finishedCallbacks) {
+      try {
+        callback(event);
+      } catch (error) {
+        debugLog(`[OutputWatcher] Finished callback error: ${error}`);
+      }
+    }
+  }
+
+  private clearSettleTimer(): void {
+    if (this.settleTimer) {
+      clearTimeout(this.settleTimer);
+      this.settleTimer = null;
+    }
+  }
+}
@@ -120,6 +120,12 @@ export interface PTYBackend {
    */
   isCursorVisible(): boolean;
 
+  /**
+   * Check if the application enabled bracketed paste (mode 2004), in
+   * which case pasted text should be wrapped in ESC[200~ ... ESC[201~.
+   */
+  isBracketedPasteEnabled(): boolean;
+
   /**
    * Get the current view offset (for scrollback).
    * 0 means viewing the current screen, positive values mean scrolled back.
@@ -132,6 +138,12 @@ export interface PTYBackend {
    */
   getTotalLines(): number;
 
+  /**
+   * Get scrollback and visible buffer as plain text lines.
+   * Used for exporting transcripts.
+   */
+  getTextLines(): string[];
+
   /**
    * Scroll the view up (into history).
    * @param lines - Number of lines to scroll
@@ -209,6 +209,13 @@ export class PTY {
     return this.screen.isCursorVisible();
   }
 
+  /**
+   * Check if bracketed paste mode is enabled
+   */
+  isBracketedPasteEnabled(): boolean {
+    return this.screen.isBracketedPaste();
+  }
+
   /**
    * Scroll view up (into scrollback history)
    * @returns true if scroll position changed
@@ -246,6 +253,13 @@ export class PTY {
     return this.screen.getTotalLines();
   }
 
+  /**
+   * Get scrollback and visible buffer as plain text lines
+   */
+  getTextLines(): string[] {
+    return this.screen.getTextLines();
+  }
+
   /**
    * Set callback for data events
    */
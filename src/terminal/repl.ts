@@ -0,0 +1,270 @@
+/**
+ * REPL Support
+ *
+ * Picks a REPL for a document language, extracts the code to send (the
+ * selection, the cursor line or the enclosing top-level block) and
+ * formats it as terminal input.
+ *
+ * Multi-line input is wrapped in bracketed paste (ESC[200~ ... ESC[201~)
+ * when the REPL has enabled mode 2004, so it arrives as one unit instead
+ * of being executed line by line. REPLs without it get the lines typed
+ * one after another, adjusted for the REPL's quirks (e.g. Python ends an
+ * indented block at the first blank line). A REPL only enables mode 2004
+ * once it prompts, so input for a REPL that is just starting waits for
+ * that first.
+ */
+
+import { accessSync, constants } from 'fs';
+import { delimiter, join } from 'path';
+import type { PTYBackend } from './pty-backend.ts';
+
+// ============================================
+// Types
+// ============================================
+
+/**
+ * A REPL program.
+ */
+export interface ReplDefinition {
+  /** Display name */
+  name: string;
+  /** Executable */
+  command: string;
+  /** Arguments */
+  args: string[];
+  /** A blank line ends the current block (Python) */
+  blankLineEndsBlock?: boolean;
+}
+
+/**
+ * A line range of code to send (0-indexed, inclusive).
+ */
+export interface CodeRange {
+  startLine: number;
+  endLine: number;
+}
+
+// ============================================
+// REPL Definitions
+// ============================================
+
+const NODE: ReplDefinition = { name: 'Node', command: 'node', args: [] };
+const BUN: ReplDefinition = { name: 'Bun', command: 'bun', args: ['repl'] };
+const PYTHON3: ReplDefinition = { name: 'Python', command: 'python3', args: ['-q'], blankLineEndsBlock: true };
+const PYTHON: ReplDefinition = { name: 'Python', command: 'python', args: ['-q'], blankLineEndsBlock: true };
+const PSQL: ReplDefinition = { name: 'psql', command: 'psql', args: [] };
+const YAEGI: ReplDefinition = { name: 'yaegi', command: 'yaegi', args: [] };
+const GORE: ReplDefinition = { name: 'gore', command: 'gore', args: [] };
+
+/**
+ * Built-in REPLs per language, in order of preference. The first one
+ * found on PATH is used.
+ */
+export const BUILTIN_REPLS: Record<string, ReplDefinition[]> = {
+  javascript: [NODE, BUN],
+  javascriptreact: [NODE, BUN],
+  typescript: [BUN],
+  typescriptreact: [BUN],
+  python: [PYTHON3, PYTHON],
+  sql: [PSQL],
+  go: [YAEGI, GORE],
+};
+
+/**
+ * Split a command line into arguments, honouring single and double quotes.
+ */
+export function splitCommandLine(commandLine: string): string[] {
+  const args: string[] = [];
+  let current = '';
+  let quote: string | null = null;
+  let hasArg = false;
+
+  for (const char of commandLine) {
+    if (quote) {
+      if (char === quote) quote = null;
+      else current += char;
+    } else if (char === '"' || char === "'") {
+      quote = char;
+      hasArg = true;
+    } else if (/\s/.test(char)) {
+      if (hasArg || current) args.push(current);
+      current = '';
+      hasArg = false;
+    } else {
+      current += char;
+    }
+  }
+  if (hasArg || current) args.push(current);
+  return args;
+}
+
+/**
+ * Find an executable on PATH. Paths containing a slash are checked as-is.
+ */
+export function findExecutable(command: string, env: Record<string, string | undefined> = process.env): string | null {
+  const candidates = command.includes('/')
+    ? [command]
+    : (env.PATH ?? '').split(delimiter).filter(Boolean).map((dir) => join(dir, command));
+  for (const candidate of candidates) {
+    try {
+      accessSync(candidate, constants.X_OK);
+      return candidate;
+    } catch {
+      // Not here
+    }
+  }
+  return null;
+}
+
+/**
+ * Choose the REPL for a language. A command line configured for the
+ * language (`tui.repl.commands`) wins; otherwise the first built-in REPL
+ * found on PATH. Returns null when the language has no REPL.
+ */
+export function resolveRepl(
+  languageId: string,
+  overrides: Record<string, string> = {},
+  isAvailable: (command: string) => boolean = (command) => findExecutable(command) !== null
+): ReplDefinition | null {
+  const builtins = BUILTIN_REPLS[languageId] ?? [];
+  const override = overrides[languageId]?.trim();
+  if (override) {
+    const [command, ...args] = splitCommandLine(override);
+    if (command) {
+      const known = builtins.find((repl) => repl.command === command);
+      return { name: known?.name ?? command.split('/').pop()!, command, args, blankLineEndsBlock: known?.blankLineEndsBlock };
+    }
+  }
+  return builtins.find((repl) => isAvailable(repl.command)) ?? null;
+}
+
+// ============================================
+// Code Extraction
+// ============================================
+
+/** Lines that continue the statement above them at the same indentation */
+const CONTINUATION = /^(?:[)}\]]|end\b|else\b|elif\b|except\b|finally\b|catch\b|case\b|default\b|\.)/;
+
+function indentOf(line: string): number {
+  return line.length - line.trimStart().length;
+}
+
+function isBlank(line: string): boolean {
+  return line.trim() === '';
+}
+
+/**
+ * Find the top-level block containing `line`: the unindented statement it
+ * belongs to, including decorators above it, its indented body and closing
+ * lines such as `}` or `else:`. Returns null on a blank line outside any
+ * block.
+ */
+export function findEnclosingBlock(lines: readonly string[], line: number): CodeRange | null {
+  if (line < 0 || line >= lines.length) return null;
+
+  // Walk up to the unindented line that starts the statement
+  let start = line;
+  while (start > 0 && (isBlank(lines[start]!) || indentOf(lines[start]!) > 0 || CONTINUATION.test(lines[start]!))) {
+    start--;
+  }
+  if (isBlank(lines[start]!)) {
+    return isBlank(lines[line]!) ? null : { startLine: line, endLine: line };
+  }
+
+  // Walk down past decorators, then over the body and closing lines
+  let end = start;
+  while (lines[end]!.startsWith('@') && end + 1 < lines.length) {
+    end++;
+  }
+  while (end + 1 < lines.length) {
+    const next = lines[end + 1]!;
+    if (!isBlank(next) && indentOf(next) === 0 && !CONTINUATION.test(next)) break;
+    end++;
+  }
+  while (end > start && isBlank(lines[end]!)) {
+    end--;
+  }
+
+  if (line > end) {
+    return isBlank(lines[line]!) ? null : { startLine: line, endLine: line };
+  }
+
+  // Decorators belong to the definition below them
+  while (start > 0 && lines[start - 1]!.startsWith('@')) {
+    start--;
+  }
+  return { startLine: start, endLine: end };
+}
+
+// ============================================
+// Input Formatting
+// ============================================
+
+const PASTE_START = '\x1b[200~';
+const PASTE_END = '\x1b[201~';
+
+/**
+ * Format code as REPL input. With bracketed paste the code is sent as one
+ * paste followed by Enter; without it each line is typed, and for REPLs
+ * where a blank line ends a block, blank lines inside blocks are dropped
+ * and a trailing one closes the last block.
+ */
+export function formatReplInput(code: string, repl: ReplDefinition, bracketedPaste: boolean): string {
+  const text = code.replace(/\r\n?/g, '\n').replace(/\n+$/, '');
+  if (isBlank(text)) return '';
+
+  if (bracketedPaste && text.includes('\n')) {
+    // A stray end marker would end the paste early
+    return `${PASTE_START}${text.split(PASTE_END).join('')}${PASTE_END}\r`;
+  }
+
+  let lines = text.split('\n');
+  if (repl.blankLineEndsBlock) {
+    lines = lines.filter((l) => !isBlank(l));
+    const last = lines[lines.length - 1];
+    if (lines.length > 1 && last !== undefined && indentOf(last) > 0) {
+      lines.push('');
+    }
+  }
+  return lines.map((l) => `${l}\r`).join('');
+}
+
+// ============================================
+// Startup
+// ============================================
+
+/**
+ * Wait until a just-started REPL is ready for input: it has enabled
+ * bracketed paste, or its first output has settled (nothing more for
+ * `quietMs`), or `timeoutMs` has passed without output.
+ */
+export function waitForReplReady(
+  pty: Pick<PTYBackend, 'onData' | 'isBracketedPasteEnabled'>,
+  options: { quietMs?: number; timeoutMs?: number } = {}
+): Promise<void> {
+  const { quietMs = 100, timeoutMs = 2000 } = options;
+
+  return new Promise((resolve) => {
+    if (pty.isBracketedPasteEnabled()) {
+      resolve();
+      return;
+    }
+
+    let quietTimer: ReturnType<typeof setTimeout> | null = null;
+    const done = () => {
+      clearTimeout(timeout);
+      if (quietTimer) clearTimeout(quietTimer);
+      unsubscribe();
+      resolve();
+    };
+    const timeout = setTimeout(done, timeoutMs);
+    const unsubscribe = pty.onData(() => {
+      if (pty.isBracketedPasteEnabled()) {
+        done();
+        return;
+      }
+      if (quietTimer) clearTimeout(quietTimer);
+      quietTimer = setTimeout(done, quietMs);
+    });
+  });
+}
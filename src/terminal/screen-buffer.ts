@@ -79,6 +79,67 @@ export function ansiToHex(code: number): string | null {
   return `#${gray.toString(16).padStart(2, '0')}${gray.toString(16).padStart(2, '0')}${gray.toString(16).padStart(2, '0')}`;
 }
 
+/**
+ * DEC Special Graphics character set (ESC ( 0), used for line drawing.
+ * Maps ASCII 0x5f-0x7e to their Unicode equivalents.
+ */
+const DEC_SPECIAL_GRAPHICS: Record<string, string> = {
+  _: ' ',
+  '`': '◆',
+  a: '▒',
+  b: '␉',
+  c: '␌',
+  d: '␍',
+  e: '␊',
+  f: '°',
+  g: '±',
+  h: '␤',
+  i: '␋',
+  j: '┘',
+  k: '┐',
+  l: '┌',
+  m: '└',
+  n: '┼',
+  o: '⎺',
+  p: '⎻',
+  q: '─',
+  r: '⎼',
+  s: '⎽',
+  t: '├',
+  u: '┤',
+  v: '┴',
+  w: '┬',
+  x: '│',
+  y: '≤',
+  z: '≥',
+  '{': 'π',
+  '|': '≠',
+  '}': '£',
+  '~': '·',
+};
+
+/**
+ * Cursor state saved by DECSC (ESC 7) and restored by DECRC (ESC 8).
+ */
+interface SavedCursorState {
+  x: number;
+  y: number;
+  wrapPending: boolean;
+  originMode: boolean;
+  fg: string | null;
+  bg: string | null;
+  bold: boolean;
+  italic: boolean;
+  underline: boolean;
+  dim: boolean;
+  inverse: boolean;
+  charsets: string[];
+  activeCharset: number;
+}
+
+/** Default tab stop interval */
+const TAB_WIDTH = 8;
+
 /**
  * Simple screen buffer for terminal rendering
  */
@@ -88,10 +149,29 @@ export class ScreenBuffer {
   private viewOffset: number = 0; // How many lines scrolled back (0 = showing current)
   private cursorX: number = 0;
   private cursorY: number = 0;
-  private savedCursorX: number = 0;
-  private savedCursorY: number = 0;
+  private savedCursor: SavedCursorState | null = null;
   private cursorVisible: boolean = true; // DECTCEM cursor visibility
 
+  // Deferred wrap: set after writing the last column, wraps on the next printable
+  private wrapPending: boolean = false;
+
+  // Modes
+  private originMode: boolean = false; // DECOM - cursor addressing relative to scroll region
+  private autoWrap: boolean = true; // DECAWM
+  private insertMode: boolean = false; // IRM
+  private newLineMode: boolean = false; // LNM - LF also performs CR
+  private bracketedPaste: boolean = false; // Mode 2004 - wrap pasted text in ESC[200~/ESC[201~
+
+  // Tab stops (column indexes)
+  private tabStops = new Set<number>();
+
+  // Character sets: G0-G3 designations and which one is mapped to GL (SI/SO)
+  private charsets: string[] = ['B', 'B', 'B', 'B'];
+  private activeCharset: number = 0;
+
+  // Last printed character, for REP (CSI b)
+  private lastChar: string | null = null;
+
   // Scroll region (DECSTBM) - 0-indexed, inclusive
   private scrollTop: number = 0;
   private scrollBottom: number;
@@ -119,6 +199,7 @@ export class ScreenBuffer {
   ) {
     this.buffer = this.createEmptyBuffer();
     this.scrollBottom = rows - 1;
+    this.resetTabStops();
   }
 
   private createEmptyBuffer(): TerminalCell[][] {
@@ -137,7 +218,32 @@ export class ScreenBuffer {
     return row;
   }
 
+  /**
+   * Create a blank cell for erase/insert/scroll operations.
+   * Uses the current background color (background color erase).
+   */
+  private createBlankCell(): TerminalCell {
+    const cell = createEmptyCell();
+    cell.bg = this.currentBg;
+    return cell;
+  }
+
+  /**
+   * Create a blank row using the current background color.
+   */
+  private createBlankRow(): TerminalCell[] {
+    const row: TerminalCell[] = [];
+    for (let x = 0; x < this.cols; x++) {
+      row.push(this.createBlankCell());
+    }
+    return row;
+  }
+
   resize(cols: number, rows: number): void {
+    // Extend default tab stops into new columns
+    for (let x = Math.ceil(this.cols / TAB_WIDTH) * TAB_WIDTH; x < cols; x += TAB_WIDTH) {
+      this.tabStops.add(x);
+    }
     this.cols = cols;
     this.rows = rows;
 
@@ -164,6 +270,7 @@ export class ScreenBuffer {
     // Clamp cursor
     this.cursorX = Math.min(this.cursorX, cols - 1);
     this.cursorY = Math.min(this.cursorY, rows - 1);
+    this.wrapPending = false;
 
     // Reset scroll region to full screen
     this.scrollTop = 0;
@@ -174,8 +281,31 @@ export class ScreenBuffer {
    * Write a character at current cursor position.
    * Handles wide characters (emoji, CJK) by advancing cursor appropriately
    * and setting a placeholder cell for the second position.
+   *
+   * Writing the last column leaves the cursor there with a pending wrap;
+   * the wrap happens when the next printable character arrives (DECAWM).
    */
   writeChar(char: string): void {
+    this.printChar(this.translateCharset(char));
+  }
+
+  /**
+   * Repeat the last printed character (REP - CSI b).
+   */
+  repeatLastChar(n: number): void {
+    if (this.lastChar === null) return;
+    const char = this.lastChar;
+    // Bound the repeat count to one screenful
+    const count = Math.min(n, this.cols * this.rows);
+    for (let i = 0; i < count; i++) {
+      this.printChar(char);
+    }
+  }
+
+  /**
+   * Print an already charset-translated character.
+   */
+  private printChar(char: string): void {
     const charWidth = getCharWidth(char);
 
     // Skip zero-width characters (control chars, combining marks)
@@ -183,10 +313,22 @@ export class ScreenBuffer {
       return;
     }
 
-    // Handle line wrap if we would exceed the line
+    if (this.wrapPending) {
+      this.wrapPending = false;
+      if (this.autoWrap) {
+        this.cursorX = 0;
+        this.newLine();
+      }
+    }
+
+    // Wide character that doesn't fit on the rest of the line
     if (this.cursorX + charWidth > this.cols) {
-      this.cursorX = 0;
-      this.newLine();
+      if (this.autoWrap) {
+        this.cursorX = 0;
+        this.newLine();
+      } else {
+        this.cursorX = Math.max(0, this.cols - charWidth);
+      }
     }
 
     if (
@@ -195,6 +337,10 @@ export class ScreenBuffer {
       this.cursorX >= 0 &&
       this.cursorX < this.cols
     ) {
+      if (this.insertMode) {
+        this.insertChars(charWidth);
+      }
+
       const cell: TerminalCell = {
         char,
         fg: this.inverse ? this.currentBg : this.currentFg,
@@ -221,7 +367,14 @@ export class ScreenBuffer {
         };
       }
     }
-    this.cursorX += charWidth;
+
+    this.lastChar = char;
+    if (this.cursorX + charWidth >= this.cols) {
+      this.cursorX = this.cols - 1;
+      this.wrapPending = this.autoWrap;
+    } else {
+      this.cursorX += charWidth;
+    }
   }
 
   /**
@@ -229,6 +382,7 @@ export class ScreenBuffer {
    */
   carriageReturn(): void {
     this.cursorX = 0;
+    this.wrapPending = false;
   }
 
   /**
@@ -236,6 +390,7 @@ export class ScreenBuffer {
    */
   newLine(): void {
     debugLog(`[ScreenBuffer] newLine: cursorY=${this.cursorY}, scrollTop=${this.scrollTop}, scrollBottom=${this.scrollBottom}, rows=${this.rows}`);
+    this.wrapPending = false;
 
     // Check if cursor is within the scroll region
     const inScrollRegion = this.cursorY >= this.scrollTop && this.cursorY <= this.scrollBottom;
@@ -257,6 +412,17 @@ export class ScreenBuffer {
     }
   }
 
+  /**
+   * Handle a line feed control (LF, VT, FF).
+   * In new line mode (LNM) this also returns the carriage.
+   */
+  lineFeed(): void {
+    this.newLine();
+    if (this.newLineMode) {
+      this.carriageReturn();
+    }
+  }
+
   /**
    * Scroll the scroll region up by one line.
    * The top line of the region is removed, bottom gets a new empty line.
@@ -272,11 +438,11 @@ export class ScreenBuffer {
         }
       }
       // Insert new row at bottom of scroll region
-      this.buffer.splice(this.scrollBottom, 0, this.createEmptyRow());
+      this.buffer.splice(this.scrollBottom, 0, this.createBlankRow());
     } else {
       // Scroll region doesn't start at top - no scrollback
       this.buffer.splice(this.scrollTop, 1);
-      this.buffer.splice(this.scrollBottom, 0, this.createEmptyRow());
+      this.buffer.splice(this.scrollBottom, 0, this.createBlankRow());
     }
   }
 
@@ -286,7 +452,27 @@ export class ScreenBuffer {
    */
   private scrollRegionDown(): void {
     this.buffer.splice(this.scrollBottom, 1);
-    this.buffer.splice(this.scrollTop, 0, this.createEmptyRow());
+    this.buffer.splice(this.scrollTop, 0, this.createBlankRow());
+  }
+
+  /**
+   * Scroll the scroll region up by n lines (SU - CSI S).
+   */
+  scrollUp(n: number = 1): void {
+    const count = Math.min(n, this.scrollBottom - this.scrollTop + 1);
+    for (let i = 0; i < count; i++) {
+      this.scrollRegionUp();
+    }
+  }
+
+  /**
+   * Scroll the scroll region down by n lines (SD - CSI T).
+   */
+  scrollDown(n: number = 1): void {
+    const count = Math.min(n, this.scrollBottom - this.scrollTop + 1);
+    for (let i = 0; i < count; i++) {
+      this.scrollRegionDown();
+    }
   }
 
   /**
@@ -305,9 +491,8 @@ export class ScreenBuffer {
       this.scrollBottom = this.rows - 1;
     }
 
-    // Move cursor to home position (top-left of screen, not region)
-    this.cursorX = 0;
-    this.cursorY = 0;
+    // Move cursor to home position (region top in origin mode)
+    this.setCursor(1, 1);
   }
 
   /**
@@ -316,6 +501,14 @@ export class ScreenBuffer {
   resetScrollRegion(): void {
     this.scrollTop = 0;
     this.scrollBottom = this.rows - 1;
+    this.setCursor(1, 1);
+  }
+
+  /**
+   * Get the scroll region (0-indexed, inclusive).
+   */
+  getScrollRegion(): { top: number; bottom: number } {
+    return { top: this.scrollTop, bottom: this.scrollBottom };
   }
 
   /**
@@ -336,6 +529,7 @@ export class ScreenBuffer {
     this.scrollback = [];
     this.cursorX = 0;
     this.cursorY = 0;
+    this.wrapPending = false;
     this.scrollTop = 0;
     this.scrollBottom = this.rows - 1;
     this.viewOffset = 0;
@@ -360,6 +554,7 @@ export class ScreenBuffer {
     }
     this.cursorX = this.savedMainCursorX;
     this.cursorY = this.savedMainCursorY;
+    this.wrapPending = false;
     this.scrollTop = 0;
     this.scrollBottom = this.rows - 1;
     this.viewOffset = 0;
@@ -377,6 +572,7 @@ export class ScreenBuffer {
    * Reverse index (ESC M) - move cursor up, scrolling region down if at top.
    */
   reverseIndex(): void {
+    this.wrapPending = false;
     if (this.cursorY === this.scrollTop) {
       // At top of scroll region - scroll the region down
       this.scrollRegionDown();
@@ -390,42 +586,132 @@ export class ScreenBuffer {
    * Handle backspace
    */
   backspace(): void {
+    this.wrapPending = false;
     if (this.cursorX > 0) {
       this.cursorX--;
     }
   }
 
+  // ============================================
+  // Tab Stops
+  // ============================================
+
   /**
-   * Handle tab
+   * Move to the next tab stop (HT, CHT - CSI I).
+   * Stops at the last column if there are no more tab stops.
    */
-  tab(): void {
-    const tabStop = 8;
-    this.cursorX = Math.min(this.cols - 1, (Math.floor(this.cursorX / tabStop) + 1) * tabStop);
+  tab(n: number = 1): void {
+    this.wrapPending = false;
+    for (let i = 0; i < n; i++) {
+      let x = this.cursorX + 1;
+      while (x < this.cols - 1 && !this.tabStops.has(x)) {
+        x++;
+      }
+      this.cursorX = Math.min(this.cols - 1, x);
+    }
   }
 
   /**
-   * Move cursor to position (1-based coordinates from ANSI)
+   * Move to the previous tab stop (CBT - CSI Z).
+   */
+  backTab(n: number = 1): void {
+    this.wrapPending = false;
+    for (let i = 0; i < n; i++) {
+      let x = this.cursorX - 1;
+      while (x > 0 && !this.tabStops.has(x)) {
+        x--;
+      }
+      this.cursorX = Math.max(0, x);
+    }
+  }
+
+  /**
+   * Set a tab stop at the cursor column (HTS - ESC H).
+   */
+  setTabStop(): void {
+    this.tabStops.add(this.cursorX);
+  }
+
+  /**
+   * Clear tab stops (TBC - CSI g).
+   * @param mode 0 = clear at cursor column, 3 = clear all
+   */
+  clearTabStop(mode: number): void {
+    if (mode === 0) {
+      this.tabStops.delete(this.cursorX);
+    } else if (mode === 3) {
+      this.tabStops.clear();
+    }
+  }
+
+  /**
+   * Get tab stop columns (0-indexed, sorted).
+   */
+  getTabStops(): number[] {
+    return [...this.tabStops].sort((a, b) => a - b);
+  }
+
+  private resetTabStops(): void {
+    this.tabStops.clear();
+    for (let x = TAB_WIDTH; x < this.cols; x += TAB_WIDTH) {
+      this.tabStops.add(x);
+    }
+  }
+
+  // ============================================
+  // Cursor Movement
+  // ============================================
+
+  /**
+   * Move cursor to position (1-based coordinates from ANSI).
+   * In origin mode, the row is relative to the scroll region and the
+   * cursor can't leave it.
    */
   setCursor(row: number, col: number): void {
     const oldX = this.cursorX;
     const oldY = this.cursorY;
-    this.cursorY = Math.max(0, Math.min(this.rows - 1, row - 1));
+    if (this.originMode) {
+      this.cursorY = Math.max(this.scrollTop, Math.min(this.scrollBottom, this.scrollTop + row - 1));
+    } else {
+      this.cursorY = Math.max(0, Math.min(this.rows - 1, row - 1));
+    }
     this.cursorX = Math.max(0, Math.min(this.cols - 1, col - 1));
+    this.wrapPending = false;
     debugLog(`[ScreenBuffer] setCursor: (${row},${col}) -> internal (${this.cursorY},${this.cursorX}) [was (${oldY},${oldX})]`);
   }
 
   /**
-   * Move cursor up
+   * Move cursor to a column on the current row (CHA/HPA, 1-based).
+   */
+  setCursorColumn(col: number): void {
+    this.cursorX = Math.max(0, Math.min(this.cols - 1, col - 1));
+    this.wrapPending = false;
+  }
+
+  /**
+   * Move cursor to a row keeping the column (VPA, 1-based, origin-aware).
+   */
+  setCursorRow(row: number): void {
+    const col = this.cursorX + 1;
+    this.setCursor(row, col);
+  }
+
+  /**
+   * Move cursor up. Stops at the top margin when inside the scroll region.
    */
   cursorUp(n: number = 1): void {
-    this.cursorY = Math.max(0, this.cursorY - n);
+    const top = this.cursorY >= this.scrollTop ? this.scrollTop : 0;
+    this.cursorY = Math.max(top, this.cursorY - n);
+    this.wrapPending = false;
   }
 
   /**
-   * Move cursor down
+   * Move cursor down. Stops at the bottom margin when inside the scroll region.
    */
   cursorDown(n: number = 1): void {
-    this.cursorY = Math.min(this.rows - 1, this.cursorY + n);
+    const bottom = this.cursorY <= this.scrollBottom ? this.scrollBottom : this.rows - 1;
+    this.cursorY = Math.min(bottom, this.cursorY + n);
+    this.wrapPending = false;
   }
 
   /**
@@ -433,6 +719,7 @@ export class ScreenBuffer {
    */
   cursorForward(n: number = 1): void {
     this.cursorX = Math.min(this.cols - 1, this.cursorX + n);
+    this.wrapPending = false;
   }
 
   /**
@@ -440,24 +727,229 @@ export class ScreenBuffer {
    */
   cursorBackward(n: number = 1): void {
     this.cursorX = Math.max(0, this.cursorX - n);
+    this.wrapPending = false;
   }
 
   /**
-   * Save cursor position
+   * Save cursor position, attributes, charsets and origin mode (DECSC).
    */
   saveCursor(): void {
-    this.savedCursorX = this.cursorX;
-    this.savedCursorY = this.cursorY;
+    this.savedCursor = {
+      x: this.cursorX,
+      y: this.cursorY,
+      wrapPending: this.wrapPending,
+      originMode: this.originMode,
+      fg: this.currentFg,
+      bg: this.currentBg,
+      bold: this.bold,
+      italic: this.italic,
+      underline: this.underline,
+      dim: this.dim,
+      inverse: this.inverse,
+      charsets: [...this.charsets],
+      activeCharset: this.activeCharset,
+    };
   }
 
   /**
-   * Restore cursor position
+   * Restore state saved by saveCursor (DECRC).
+   * Without a saved state, homes the cursor and resets attributes.
    */
   restoreCursor(): void {
-    this.cursorX = this.savedCursorX;
-    this.cursorY = this.savedCursorY;
+    const saved = this.savedCursor;
+    if (!saved) {
+      this.originMode = false;
+      this.setCursor(1, 1);
+      this.setGraphicsRendition([0]);
+      this.resetCharsets();
+      return;
+    }
+    this.cursorX = Math.min(saved.x, this.cols - 1);
+    this.cursorY = Math.min(saved.y, this.rows - 1);
+    this.wrapPending = saved.wrapPending;
+    this.originMode = saved.originMode;
+    this.currentFg = saved.fg;
+    this.currentBg = saved.bg;
+    this.bold = saved.bold;
+    this.italic = saved.italic;
+    this.underline = saved.underline;
+    this.dim = saved.dim;
+    this.inverse = saved.inverse;
+    this.charsets = [...saved.charsets];
+    this.activeCharset = saved.activeCharset;
   }
 
+  /**
+   * Get the cursor position as reported by CPR (1-based, origin-aware).
+   */
+  getCursorReport(): { row: number; col: number } {
+    const row = this.originMode ? this.cursorY - this.scrollTop + 1 : this.cursorY + 1;
+    return { row, col: this.cursorX + 1 };
+  }
+
+  // ============================================
+  // Modes
+  // ============================================
+
+  /**
+   * Set origin mode (DECOM). Homes the cursor.
+   */
+  setOriginMode(enabled: boolean): void {
+    this.originMode = enabled;
+    this.setCursor(1, 1);
+  }
+
+  isOriginMode(): boolean {
+    return this.originMode;
+  }
+
+  /**
+   * Set auto-wrap mode (DECAWM).
+   */
+  setAutoWrap(enabled: boolean): void {
+    this.autoWrap = enabled;
+    if (!enabled) {
+      this.wrapPending = false;
+    }
+  }
+
+  isAutoWrap(): boolean {
+    return this.autoWrap;
+  }
+
+  /**
+   * Set insert/replace mode (IRM).
+   */
+  setInsertMode(enabled: boolean): void {
+    this.insertMode = enabled;
+  }
+
+  isInsertMode(): boolean {
+    return this.insertMode;
+  }
+
+  /**
+   * Set line feed/new line mode (LNM).
+   */
+  setNewLineMode(enabled: boolean): void {
+    this.newLineMode = enabled;
+  }
+
+  isNewLineMode(): boolean {
+    return this.newLineMode;
+  }
+
+  /**
+   * Set bracketed paste mode (CSI ? 2004 h/l).
+   */
+  setBracketedPaste(enabled: boolean): void {
+    this.bracketedPaste = enabled;
+  }
+
+  isBracketedPaste(): boolean {
+    return this.bracketedPaste;
+  }
+
+  // ============================================
+  // Character Sets
+  // ============================================
+
+  /**
+   * Designate a character set to G0-G3 (ESC ( F, ESC ) F, ...).
+   * Supported: 'B' (US ASCII), '0' (DEC Special Graphics), 'A' (UK).
+   * Other sets are treated as ASCII.
+   */
+  designateCharset(slot: number, charset: string): void {
+    if (slot >= 0 && slot < this.charsets.length) {
+      this.charsets[slot] = charset;
+    }
+  }
+
+  /**
+   * Invoke a character set into GL (SO = 1, SI = 0).
+   */
+  shiftCharset(slot: number): void {
+    this.activeCharset = slot;
+  }
+
+  private resetCharsets(): void {
+    this.charsets = ['B', 'B', 'B', 'B'];
+    this.activeCharset = 0;
+  }
+
+  /**
+   * Translate a character through the active character set.
+   */
+  private translateCharset(char: string): string {
+    const charset = this.charsets[this.activeCharset];
+    if (charset === '0') {
+      return DEC_SPECIAL_GRAPHICS[char] ?? char;
+    }
+    if (charset === 'A' && char === '#') {
+      return '£';
+    }
+    return char;
+  }
+
+  // ============================================
+  // Reset
+  // ============================================
+
+  /**
+   * Soft terminal reset (DECSTR - CSI ! p).
+   * Resets modes, margins, attributes and charsets; keeps screen contents.
+   */
+  softReset(): void {
+    this.cursorVisible = true;
+    this.originMode = false;
+    this.autoWrap = true;
+    this.insertMode = false;
+    this.newLineMode = false;
+    this.wrapPending = false;
+    this.scrollTop = 0;
+    this.scrollBottom = this.rows - 1;
+    this.setGraphicsRendition([0]);
+    this.resetCharsets();
+    this.savedCursor = null;
+  }
+
+  /**
+   * Full terminal reset (RIS - ESC c).
+   */
+  reset(): void {
+    this.exitAlternateScreen();
+    this.softReset();
+    this.bracketedPaste = false;
+    this.resetTabStops();
+    this.lastChar = null;
+    this.buffer = this.createEmptyBuffer();
+    this.cursorX = 0;
+    this.cursorY = 0;
+    this.viewOffset = 0;
+  }
+
+  /**
+   * Screen alignment test (DECALN - ESC # 8).
+   * Fills the screen with 'E', resets margins and homes the cursor.
+   */
+  alignmentTest(): void {
+    this.scrollTop = 0;
+    this.scrollBottom = this.rows - 1;
+    this.originMode = false;
+    for (let y = 0; y < this.rows; y++) {
+      const row: TerminalCell[] = [];
+      for (let x = 0; x < this.cols; x++) {
+        row.push({ ...createEmptyCell(), char: 'E' });
+      }
+      this.buffer[y] = row;
+    }
+    this.setCursor(1, 1);
+  }
+
+  // ============================================
+  // Editing
+  // ============================================
+
   /**
    * Erase in display
    */
@@ -466,18 +958,20 @@ export class ScreenBuffer {
       case 0: // Erase from cursor to end
         this.eraseInLine(0);
         for (let y = this.cursorY + 1; y < this.rows; y++) {
-          this.buffer[y] = this.createEmptyRow();
+          this.buffer[y] = this.createBlankRow();
         }
         break;
       case 1: // Erase from start to cursor
         this.eraseInLine(1);
         for (let y = 0; y < this.cursorY; y++) {
-          this.buffer[y] = this.createEmptyRow();
+          this.buffer[y] = this.createBlankRow();
         }
         break;
       case 2: // Erase entire display
       case 3: // Erase entire display and scrollback
-        this.buffer = this.createEmptyBuffer();
+        for (let y = 0; y < this.rows; y++) {
+          this.buffer[y] = this.createBlankRow();
+        }
         if (mode === 3) {
           this.scrollback = [];
         }
@@ -490,20 +984,21 @@ export class ScreenBuffer {
    */
   eraseInLine(mode: number): void {
     if (this.cursorY < 0 || this.cursorY >= this.rows) return;
+    this.wrapPending = false;
 
     switch (mode) {
       case 0: // Erase from cursor to end of line
         for (let x = this.cursorX; x < this.cols; x++) {
-          this.buffer[this.cursorY]![x] = createEmptyCell();
+          this.buffer[this.cursorY]![x] = this.createBlankCell();
         }
         break;
       case 1: // Erase from start of line to cursor
         for (let x = 0; x <= this.cursorX; x++) {
-          this.buffer[this.cursorY]![x] = createEmptyCell();
+          this.buffer[this.cursorY]![x] = this.createBlankCell();
         }
         break;
       case 2: // Erase entire line
-        this.buffer[this.cursorY] = this.createEmptyRow();
+        this.buffer[this.cursorY] = this.createBlankRow();
         break;
     }
   }
@@ -514,11 +1009,13 @@ export class ScreenBuffer {
   insertChars(n: number): void {
     if (this.cursorY < 0 || this.cursorY >= this.rows) return;
     const row = this.buffer[this.cursorY]!;
+    this.wrapPending = false;
 
     // Shift characters right from cursor position
-    for (let i = 0; i < n; i++) {
+    const count = Math.min(n, this.cols - this.cursorX);
+    for (let i = 0; i < count; i++) {
       row.pop(); // Remove last character
-      row.splice(this.cursorX, 0, createEmptyCell()); // Insert blank at cursor
+      row.splice(this.cursorX, 0, this.createBlankCell()); // Insert blank at cursor
     }
   }
 
@@ -528,11 +1025,13 @@ export class ScreenBuffer {
   deleteChars(n: number): void {
     if (this.cursorY < 0 || this.cursorY >= this.rows) return;
     const row = this.buffer[this.cursorY]!;
+    this.wrapPending = false;
 
     // Remove n characters at cursor, add blanks at end
-    for (let i = 0; i < n && this.cursorX < this.cols; i++) {
+    const count = Math.min(n, this.cols - this.cursorX);
+    for (let i = 0; i < count; i++) {
       row.splice(this.cursorX, 1);
-      row.push(createEmptyCell());
+      row.push(this.createBlankCell());
     }
   }
 
@@ -541,15 +1040,17 @@ export class ScreenBuffer {
    */
   eraseChars(n: number): void {
     if (this.cursorY < 0 || this.cursorY >= this.rows) return;
+    this.wrapPending = false;
 
     for (let i = 0; i < n && this.cursorX + i < this.cols; i++) {
-      this.buffer[this.cursorY]![this.cursorX + i] = createEmptyCell();
+      this.buffer[this.cursorY]![this.cursorX + i] = this.createBlankCell();
     }
   }
 
   /**
    * Insert n blank lines at cursor (IL - CSI L)
    * Respects scroll region - only affects lines within the region.
+   * Moves the cursor to the first column.
    */
   insertLines(n: number): void {
     // Only works if cursor is within scroll region
@@ -557,16 +1058,20 @@ export class ScreenBuffer {
       return;
     }
 
-    for (let i = 0; i < n; i++) {
+    const count = Math.min(n, this.scrollBottom - this.cursorY + 1);
+    for (let i = 0; i < count; i++) {
       // Remove line at bottom of scroll region, insert blank line at cursor
       this.buffer.splice(this.scrollBottom, 1);
-      this.buffer.splice(this.cursorY, 0, this.createEmptyRow());
+      this.buffer.splice(this.cursorY, 0, this.createBlankRow());
     }
+    this.cursorX = 0;
+    this.wrapPending = false;
   }
 
   /**
    * Delete n lines at cursor (DL - CSI M)
    * Respects scroll region - only affects lines within the region.
+   * Moves the cursor to the first column.
    */
   deleteLines(n: number): void {
     // Only works if cursor is within scroll region
@@ -574,11 +1079,14 @@ export class ScreenBuffer {
       return;
     }
 
-    for (let i = 0; i < n; i++) {
+    const count = Math.min(n, this.scrollBottom - this.cursorY + 1);
+    for (let i = 0; i < count; i++) {
       // Remove line at cursor, add blank line at bottom of scroll region
       this.buffer.splice(this.cursorY, 1);
-      this.buffer.splice(this.scrollBottom, 0, this.createEmptyRow());
+      this.buffer.splice(this.scrollBottom, 0, this.createBlankRow());
     }
+    this.cursorX = 0;
+    this.wrapPending = false;
   }
 
   /**
@@ -771,15 +1279,34 @@ export class ScreenBuffer {
   getTotalLines(): number {
     return this.scrollback.length + this.buffer.length;
   }
+
+  /**
+   * Get scrollback and visible buffer as plain text lines.
+   * Trailing whitespace and trailing blank lines are dropped.
+   */
+  getTextLines(): string[] {
+    const lines = [...this.scrollback, ...this.buffer].map((row) =>
+      row.map((cell) => cell.char).join('').trimEnd()
+    );
+    while (lines.length > 0 && lines[lines.length - 1] === '') {
+      lines.pop();
+    }
+    return lines;
+  }
 }
 
 /**
  * Simple ANSI escape sequence parser
  */
 export class AnsiParser {
-  private state: 'normal' | 'escape' | 'csi' | 'osc' | 'charset' = 'normal';
+  private state: 'normal' | 'escape' | 'csi' | 'osc' | 'dcs' | 'charset' | 'hash' | 'escIntermediate' =
+    'normal';
   private csiParams: string = '';
+  private csiIntermediates: string = '';
   private oscData: string = '';
+  private dcsData: string = '';
+  /** G0-G3 slot targeted by a pending charset designation */
+  private charsetSlot: number = 0;
 
   /** Callback for OSC 99 notifications (used by Claude Code, etc.) */
   private onNotificationCallback?: (message: string) => void;
@@ -833,10 +1360,23 @@ export class AnsiParser {
         case 'osc':
           this.processOSC(char, code);
           break;
+        case 'dcs':
+          this.processDCS(char, code);
+          break;
         case 'charset':
-          // Character set designation - just consume the designator and return to normal
-          // ESC ( B = ASCII, ESC ( 0 = DEC Special Graphics, etc.
-          // We don't actually change character sets, just acknowledge them
+          // Character set designation: ESC ( B = ASCII, ESC ( 0 = DEC Special Graphics, etc.
+          this.screen.designateCharset(this.charsetSlot, char);
+          this.state = 'normal';
+          break;
+        case 'hash':
+          // ESC # 8 = DECALN; double width/height lines (3-6) are not supported
+          if (char === '8') {
+            this.screen.alignmentTest();
+          }
+          this.state = 'normal';
+          break;
+        case 'escIntermediate':
+          // ESC SP F, ESC % G, etc. - consume the final byte
           this.state = 'normal';
           break;
       }
@@ -847,69 +1387,146 @@ export class AnsiParser {
     if (code === 0x1b) {
       // ESC
       this.state = 'escape';
-    } else if (code === 0x0d) {
-      // CR
-      this.screen.carriageReturn();
-    } else if (code === 0x0a) {
-      // LF
-      this.screen.newLine();
-    } else if (code === 0x08) {
-      // BS
-      this.screen.backspace();
-    } else if (code === 0x09) {
-      // TAB
-      this.screen.tab();
-    } else if (code === 0x07) {
-      // BEL - ignore for now
-    } else if (code >= 0x20) {
+    } else if (code >= 0x20 && code !== 0x7f) {
       // Printable
       this.screen.writeChar(char);
+    } else {
+      this.executeControl(code);
+    }
+  }
+
+  /**
+   * Execute a C0 control character.
+   * Controls are also executed when embedded in escape sequences.
+   */
+  private executeControl(code: number): void {
+    switch (code) {
+      case 0x0d: // CR
+        this.screen.carriageReturn();
+        break;
+      case 0x0a: // LF
+      case 0x0b: // VT
+      case 0x0c: // FF
+        this.screen.lineFeed();
+        break;
+      case 0x08: // BS
+        this.screen.backspace();
+        break;
+      case 0x09: // TAB
+        this.screen.tab();
+        break;
+      case 0x0e: // SO - invoke G1
+        this.screen.shiftCharset(1);
+        break;
+      case 0x0f: // SI - invoke G0
+        this.screen.shiftCharset(0);
+        break;
+      case 0x07: // BEL - ignore for now
+      default:
+        break;
     }
   }
 
   private processEscape(char: string, code: number): void {
-    if (char === '[') {
-      this.state = 'csi';
-      this.csiParams = '';
-    } else if (char === ']') {
-      this.state = 'osc';
-      this.oscData = '';
-    } else if (char === '(' || char === ')' || char === '*' || char === '+') {
-      // Character set designation (G0-G3) - next char specifies the set
-      // ESC ( B = ASCII, ESC ( 0 = DEC Special Graphics, etc.
-      // We ignore this but need to consume the next character
-      this.state = 'charset';
-      return;
-    } else if (char === '7') {
-      this.screen.saveCursor();
-      this.state = 'normal';
-    } else if (char === '8') {
-      this.screen.restoreCursor();
-      this.state = 'normal';
-    } else if (char === 'c') {
-      // Reset terminal
-      this.screen.eraseInDisplay(2);
-      this.screen.setCursor(1, 1);
-      this.state = 'normal';
-    } else if (char === 'M') {
-      // Reverse index - move up, scroll region down if at top
-      this.screen.reverseIndex();
-      this.state = 'normal';
-    } else {
-      // Unknown escape sequence
-      debugLog(`[ANSI] Unknown ESC sequence: ESC ${char} (0x${code.toString(16)})`);
-      this.state = 'normal';
+    this.state = 'normal';
+
+    switch (char) {
+      case '[':
+        this.state = 'csi';
+        this.csiParams = '';
+        this.csiIntermediates = '';
+        break;
+      case ']':
+        this.state = 'osc';
+        this.oscData = '';
+        break;
+      case 'P':
+        // DCS - device control string, consumed until ST
+        this.state = 'dcs';
+        this.dcsData = '';
+        break;
+      case '(':
+      case ')':
+      case '*':
+      case '+':
+        // Character set designation (G0-G3) - next char specifies the set
+        this.charsetSlot = '()*+'.indexOf(char);
+        this.state = 'charset';
+        break;
+      case '-':
+      case '.':
+      case '/':
+        // 96-character set designation (G1-G3)
+        this.charsetSlot = '-./'.indexOf(char) + 1;
+        this.state = 'charset';
+        break;
+      case '#':
+        this.state = 'hash';
+        break;
+      case ' ':
+      case '%':
+        this.state = 'escIntermediate';
+        break;
+      case '7': // DECSC
+        this.screen.saveCursor();
+        break;
+      case '8': // DECRC
+        this.screen.restoreCursor();
+        break;
+      case 'c': // RIS - Reset terminal
+        this.screen.reset();
+        break;
+      case 'D': // IND - Index
+        this.screen.newLine();
+        break;
+      case 'E': // NEL - Next line
+        this.screen.carriageReturn();
+        this.screen.newLine();
+        break;
+      case 'H': // HTS - Set tab stop
+        this.screen.setTabStop();
+        break;
+      case 'M':
+        // Reverse index - move up, scroll region down if at top
+        this.screen.reverseIndex();
+        break;
+      case '=': // DECKPAM - Application keypad
+      case '>': // DECKPNM - Normal keypad
+      case '\\': // ST without a string
+        break;
+      default:
+        if (code < 0x20) {
+          // Control character inside escape - execute and keep waiting
+          this.executeControl(code);
+          this.state = code === 0x18 || code === 0x1a ? 'normal' : 'escape';
+          return;
+        }
+        // Unknown escape sequence
+        debugLog(`[ANSI] Unknown ESC sequence: ESC ${char} (0x${code.toString(16)})`);
+        break;
     }
   }
 
   private processCSI(char: string, code: number): void {
-    if ((code >= 0x30 && code <= 0x3f) || char === ';') {
-      // Parameter bytes
+    if (code >= 0x30 && code <= 0x3f) {
+      // Parameter bytes (digits, ';', ':' and private prefixes '<=>?')
       this.csiParams += char;
+    } else if (code >= 0x20 && code <= 0x2f) {
+      // Intermediate bytes ('!', '$', ' ', '"', ...)
+      this.csiIntermediates += char;
     } else if (code >= 0x40 && code <= 0x7e) {
       // Final byte - execute command
       this.executeCSI(char);
       this.state = 'normal';
+    } else if (code === 0x1b) {
+      // ESC aborts the sequence and starts a new one
+      this.state = 'escape';
+    } else if (code === 0x18 || code === 0x1a) {
+      // CAN / SUB - abort
+      this.state = 'normal';
+    } else if (code < 0x20) {
+      // Control characters are executed without interrupting the sequence
+      this.executeControl(code);
     } else {
       // Invalid - abort
       this.state = 'normal';
@@ -917,16 +1534,34 @@ export class AnsiParser {
   }
 
   private executeCSI(command: string): void {
-    const params = this.csiParams.split(';').map((p) => parseInt(p, 10) || 0);
+    // Private prefix: '?' (DEC), '>' (secondary), '=' (tertiary), '<'
+    const first = this.csiParams[0];
+    const prefix = first === '?' || first === '>' || first === '=' || first === '<' ? first : '';
+    const paramString = prefix ? this.csiParams.slice(1) : this.csiParams;
+    const params = paramString.split(';').map((p) => parseInt(p, 10) || 0);
+
+    if (this.csiIntermediates) {
+      this.executeCSIWithIntermediates(command, prefix, params);
+      return;
+    }
+
+    if (prefix && !'hlnJKc'.includes(command)) {
+      // Private variants of other commands (e.g. CSI > 4 ; 1 m, CSI > 1 u)
+      // are keyboard/window extensions we don't implement
+      debugLog(`[ANSI] Ignored private CSI command: CSI ${this.csiParams} ${command}`);
+      return;
+    }
 
     switch (command) {
       case 'A': // Cursor Up
         this.screen.cursorUp(params[0] || 1);
         break;
       case 'B': // Cursor Down
+      case 'e': // VPR - Vertical Position Relative
         this.screen.cursorDown(params[0] || 1);
         break;
       case 'C': // Cursor Forward
+      case 'a': // HPR - Horizontal Position Relative
         this.screen.cursorForward(params[0] || 1);
         break;
       case 'D': // Cursor Backward
@@ -936,10 +1571,10 @@ export class AnsiParser {
       case 'f':
         this.screen.setCursor(params[0] || 1, params[1] || 1);
         break;
-      case 'J': // Erase in Display
+      case 'J': // Erase in Display (DECSED with '?')
         this.screen.eraseInDisplay(params[0] || 0);
         break;
-      case 'K': // Erase in Line
+      case 'K': // Erase in Line (DECSEL with '?')
         this.screen.eraseInLine(params[0] || 0);
         break;
       case 'm': // SGR (Select Graphic Rendition)
@@ -952,47 +1587,42 @@ export class AnsiParser {
         this.screen.restoreCursor();
         break;
       case 'G': // Cursor Horizontal Absolute
-        this.screen.setCursor(this.screen.getCursor().y + 1, params[0] || 1);
+      case '`': // HPA - Horizontal Position Absolute
+        this.screen.setCursorColumn(params[0] || 1);
         break;
       case 'd': // Cursor Vertical Absolute
-        this.screen.setCursor(params[0] || 1, this.screen.getCursor().x + 1);
+        this.screen.setCursorRow(params[0] || 1);
+        break;
+      case 'I': // CHT - Cursor Forward Tabulation
+        this.screen.tab(params[0] || 1);
+        break;
+      case 'Z': // CBT - Cursor Backward Tabulation
+        this.screen.backTab(params[0] || 1);
+        break;
+      case 'g': // TBC - Tab Clear
+        this.screen.clearTabStop(params[0] || 0);
+        break;
+      case 'S': // SU - Scroll Up
+        this.screen.scrollUp(params[0] || 1);
+        break;
+      case 'T': // SD - Scroll Down
+        this.screen.scrollDown(params[0] || 1);
+        break;
+      case 'b': // REP - Repeat preceding character
+        this.screen.repeatLastChar(params[0] || 1);
         break;
       case 'h': // Set mode
       case 'l': // Reset mode
-        // Handle private modes (CSI ? Ps h/l)
-        if (this.csiParams.startsWith('?')) {
-          const mode = parseInt(this.csiParams.slice(1), 10);
-          if (mode === 25) {
-            // DECTCEM - Cursor visibility
-            this.screen.setCursorVisible(command === 'h');
-          } else if (mode === 1049) {
-            // Alternate screen buffer
-            if (command === 'h') {
-              this.screen.enterAlternateScreen();
-            } else {
-              this.screen.exitAlternateScreen();
-            }
-          } else if (mode === 2026) {
-            // Synchronized updates (kitty/iTerm2 extension)
-            // h = begin synchronized update, l = end synchronized update
-            // We don't buffer, so just acknowledge and ignore
-          } else if (mode === 1) {
-            // DECCKM - Cursor keys mode (application vs normal)
-            // Ignored - we always use normal mode cursor keys
-          } else if (mode === 7) {
-            // DECAWM - Auto-wrap mode
-            // Ignored - we always have auto-wrap enabled
-          } else if (mode === 12) {
-            // Cursor blinking (AT&T 610)
-            // Ignored - cursor blink is a visual preference
+        for (const mode of params) {
+          if (prefix === '?') {
+            this.setPrivateMode(mode, command === 'h');
           } else {
-            // Log unhandled private modes
-            debugLog(`[ANSI] Unhandled private mode: CSI ? ${mode} ${command}`);
+            this.setMode(mode, command === 'h');
           }
         }
         break;
       case 'r': // DECSTBM - Set scroll region
-        if (params.length === 0 || (params[0] === 0 && params[1] === 0)) {
+        if (params.length === 0 || (params[0] === 0 && !params[1])) {
           // CSI r with no params - reset to full screen
           debugLog(`[ANSI] Reset scroll region to full screen`);
           this.screen.resetScrollRegion();
@@ -1028,25 +1658,16 @@ export class AnsiParser {
         this.screen.carriageReturn();
         break;
       case 'n': // DSR - Device Status Report
-        if (params[0] === 6) {
-          // Cursor Position Report - respond with CSI row ; col R
-          const cursor = this.screen.getCursor();
-          // Convert 0-indexed to 1-indexed
-          const response = `\x1b[${cursor.y + 1};${cursor.x + 1}R`;
-          debugLog(`[ANSI] DSR 6: Reporting cursor position ${cursor.y + 1};${cursor.x + 1}, sending response`);
-          this.sendOutput(response);
-          debugLog(`[ANSI] DSR 6: Response sent (callback exists: ${!!this.onOutputCallback})`);
-        } else if (params[0] === 5) {
-          // Device status - respond "OK"
-          debugLog(`[ANSI] DSR 5: Reporting OK status`);
-          this.sendOutput('\x1b[0n');
-        }
+        this.reportDeviceStatus(prefix, params[0] ?? 0);
         break;
       case 'c': // DA - Device Attributes
-        // Respond as VT100 with advanced video option
-        // CSI ? 1 ; 2 c means "VT100 with Advanced Video Option"
-        debugLog(`[ANSI] DA: Reporting device attributes`);
-        this.sendOutput('\x1b[?1;2c');
+        this.reportDeviceAttributes(prefix, params[0] ?? 0);
+        break;
+      case 'x': // DECREQTPARM - Request terminal parameters
+        if ((params[0] ?? 0) <= 1) {
+          // Unsolicited (2) or solicited (3) report: no parity, 8 bits, 38400 baud
+          this.sendOutput(`\x1b[${(params[0] ?? 0) + 2};1;1;128;128;1;0x`);
+        }
         break;
       default:
         // Unknown CSI command - log for debugging
@@ -1055,9 +1676,205 @@ export class AnsiParser {
     }
   }
 
+  /**
+   * Execute CSI sequences with intermediate bytes.
+   */
+  private executeCSIWithIntermediates(command: string, prefix: string, params: number[]): void {
+    const sequence = this.csiIntermediates + command;
+
+    switch (sequence) {
+      case '!p': // DECSTR - Soft terminal reset
+        this.screen.softReset();
+        break;
+      case '$p': // DECRQM - Request mode
+        this.reportMode(prefix, params[0] ?? 0);
+        break;
+      case ' q': // DECSCUSR - Cursor style (visual preference, ignored)
+        break;
+      default:
+        debugLog(`[ANSI] Unknown CSI command: CSI ${this.csiParams} ${sequence}`);
+        break;
+    }
+  }
+
+  /**
+   * Set or reset an ANSI mode (SM/RM - CSI Ps h/l).
+   */
+  private setMode(mode: number, enabled: boolean): void {
+    switch (mode) {
+      case 4: // IRM - Insert/replace mode
+        this.screen.setInsertMode(enabled);
+        break;
+      case 20: // LNM - Line feed/new line mode
+        this.screen.setNewLineMode(enabled);
+        break;
+      default:
+        debugLog(`[ANSI] Unhandled mode: CSI ${mode} ${enabled ? 'h' : 'l'}`);
+        break;
+    }
+  }
+
+  /**
+   * Set or reset a DEC private mode (DECSET/DECRST - CSI ? Ps h/l).
+   */
+  private setPrivateMode(mode: number, enabled: boolean): void {
+    switch (mode) {
+      case 25:
+        // DECTCEM - Cursor visibility
+        this.screen.setCursorVisible(enabled);
+        break;
+      case 6:
+        // DECOM - Origin mode
+        this.screen.setOriginMode(enabled);
+        break;
+      case 7:
+        // DECAWM - Auto-wrap mode
+        this.screen.setAutoWrap(enabled);
+        break;
+      case 47:
+      case 1047:
+        // Alternate screen buffer without the DECSC cursor save
+        if (enabled) {
+          this.screen.enterAlternateScreen();
+        } else {
+          this.screen.exitAlternateScreen();
+        }
+        break;
+      case 1048:
+        // Save/restore cursor as in DECSC/DECRC
+        if (enabled) {
+          this.screen.saveCursor();
+        } else {
+          this.screen.restoreCursor();
+        }
+        break;
+      case 1049:
+        // Alternate screen buffer with cursor save
+        if (enabled) {
+          this.screen.saveCursor();
+          this.screen.enterAlternateScreen();
+        } else {
+          this.screen.exitAlternateScreen();
+          this.screen.restoreCursor();
+        }
+        break;
+      case 2004:
+        // Bracketed paste
+        this.screen.setBracketedPaste(enabled);
+        break;
+      case 2026:
+        // Synchronized updates (kitty/iTerm2 extension)
+        // h = begin synchronized update, l = end synchronized update
+        // We don't buffer, so just acknowledge and ignore
+        break;
+      case 1:
+        // DECCKM - Cursor keys mode (application vs normal)
+        // Ignored - we always use normal mode cursor keys
+        break;
+      case 3:
+        // DECCOLM - 132 column mode
+        // Ignored - the column count follows the pane size
+        break;
+      case 12:
+        // Cursor blinking (AT&T 610)
+        // Ignored - cursor blink is a visual preference
+        break;
+      default:
+        // Log unhandled private modes
+        debugLog(`[ANSI] Unhandled private mode: CSI ? ${mode} ${enabled ? 'h' : 'l'}`);
+        break;
+    }
+  }
+
+  /**
+   * Answer a mode query (DECRQM - CSI ? Ps $ p / CSI Ps $ p).
+   * Reply: CSI [?] Ps ; Pm $ y where Pm is 1 = set, 2 = reset, 0 = unknown.
+   */
+  private reportMode(prefix: string, mode: number): void {
+    let value: boolean | undefined;
+    if (prefix === '?') {
+      const privateModes: Record<number, () => boolean> = {
+        6: () => this.screen.isOriginMode(),
+        7: () => this.screen.isAutoWrap(),
+        25: () => this.screen.isCursorVisible(),
+        47: () => this.screen.isAlternateScreen(),
+        1047: () => this.screen.isAlternateScreen(),
+        1049: () => this.screen.isAlternateScreen(),
+        2004: () => this.screen.isBracketedPaste(),
+      };
+      value = privateModes[mode]?.();
+    } else if (mode === 4) {
+      value = this.screen.isInsertMode();
+    } else if (mode === 20) {
+      value = this.screen.isNewLineMode();
+    }
+
+    const state = value === undefined ? 0 : value ? 1 : 2;
+    this.sendOutput(`\x1b[${prefix}${mode};${state}$y`);
+  }
+
+  /**
+   * Answer a device status report (DSR - CSI Ps n / CSI ? Ps n).
+   */
+  private reportDeviceStatus(prefix: string, request: number): void {
+    if (request === 6) {
+      // Cursor Position Report - respond with CSI row ; col R (DECXCPR with '?')
+      const { row, col } = this.screen.getCursorReport();
+      debugLog(`[ANSI] DSR 6: Reporting cursor position ${row};${col}, sending response`);
+      this.sendOutput(`\x1b[${prefix}${row};${col}R`);
+      debugLog(`[ANSI] DSR 6: Response sent (callback exists: ${!!this.onOutputCallback})`);
+    } else if (request === 5 && !prefix) {
+      // Device status - respond "OK"
+      debugLog(`[ANSI] DSR 5: Reporting OK status`);
+      this.sendOutput('\x1b[0n');
+    } else if (prefix === '?' && request === 15) {
+      // Printer status - no printer
+      this.sendOutput('\x1b[?13n');
+    } else if (prefix === '?' && request === 25) {
+      // User-defined keys - locked
+      this.sendOutput('\x1b[?21n');
+    } else if (prefix === '?' && request === 26) {
+      // Keyboard status - North American
+      this.sendOutput('\x1b[?27;1n');
+    }
+  }
+
+  /**
+   * Answer a device attributes request (DA1/DA2/DA3).
+   */
+  private reportDeviceAttributes(prefix: string, request: number): void {
+    if (request !== 0) return;
+
+    if (prefix === '') {
+      // Primary DA: VT220 with ANSI color
+      debugLog(`[ANSI] DA: Reporting device attributes`);
+      this.sendOutput('\x1b[?62;22c');
+    } else if (prefix === '>') {
+      // Secondary DA: VT220, firmware version 10, no options
+      this.sendOutput('\x1b[>1;10;0c');
+    } else if (prefix === '=') {
+      // Tertiary DA: unit ID
+      this.sendOutput('\x1bP!|00000000\x1b\\');
+    }
+  }
+
+  private processDCS(char: string, code: number): void {
+    if ((char === '\\' && this.dcsData.endsWith('\x1b')) || code === 0x18 || code === 0x1a) {
+      // ST (ESC \) or CAN/SUB ends the string; contents are ignored
+      this.dcsData = '';
+      this.state = 'normal';
+    } else {
+      this.dcsData += char;
+      // Safety limit - keep only the tail so ST can still be seen
+      if (this.dcsData.length > 4096) {
+        this.dcsData = this.dcsData.slice(-1);
+      }
+    }
+  }
+
   private processOSC(char: string, code: number): void {
-    if (code === 0x07 || (code === 0x1b && this.oscData.endsWith('\\'))) {
-      // OSC terminator (BEL or ESC \)
+    if (code === 0x07 || (char === '\\' && this.oscData.endsWith('\x1b'))) {
+      // OSC terminator (BEL or ESC \) - handleOSC strips the trailing ESC
       this.handleOSC(this.oscData);
       this.oscData = '';
       this.state = 'normal';
@@ -8,7 +8,12 @@
 import { tmpdir } from 'os';
 import { mkdir } from 'fs/promises';
 import { ECPServer, type ECPServerOptions } from '../../src/ecp/server.ts';
-import type { ECPResponse, ECPNotification } from '../../src/ecp/types.ts';
+import type {
+  ECPResponse,
+  ECPNotification,
+  ECPInitializeParams,
+  ECPInitializeResult,
+} from '../../src/ecp/types.ts';
 import type { LocalSessionService } from '../../src/services/session/local.ts';
 
 /**
@@ -93,6 +98,13 @@ export class TestECPClient {
     });
   }
 
+  /**
+   * Perform the initialize handshake.
+   */
+  async initialize(params: ECPInitializeParams): Promise<ECPInitializeResult> {
+    return this.server.initialize(params);
+  }
+
   /**
    * Send a request and get the result.
    * Throws an error if the request fails.
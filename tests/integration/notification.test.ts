@@ -0,0 +1,111 @@
+/**
+ * Notification Service Integration Tests
+ *
+ * Tests notification/* ECP methods via TestECPClient.
+ */
+
+import { describe, test, expect, beforeEach, afterEach } from 'bun:test';
+import { TestECPClient } from '../helpers/ecp-client.ts';
+import type { NotificationInfo } from '../../src/services/notification/types.ts';
+
+describe('Notification Service ECP Integration', () => {
+  let client: TestECPClient;
+
+  beforeEach(() => {
+    client = new TestECPClient();
+  });
+
+  afterEach(async () => {
+    await client.shutdown();
+  });
+
+  describe('notification/show', () => {
+    test('shows a notification and emits notification/shown', async () => {
+      const result = await client.request<{ id: string; suppressed: boolean }>('notification/show', {
+        message: 'Extension updated',
+        severity: 'info',
+        source: 'my-extension',
+        actions: [{ id: 'reload', label: 'Reload' }],
+      });
+
+      expect(result.id).toBeDefined();
+      expect(result.suppressed).toBe(false);
+
+      const shown = await client.waitForNotification('notification/shown');
+      const params = shown.params as NotificationInfo;
+      expect(params.id).toBe(result.id);
+      expect(params.actions).toEqual([{ id: 'reload', label: 'Reload' }]);
+    });
+
+    test('requires a message', async () => {
+      const response = await client.requestRaw('notification/show', {});
+      expect(response.error?.code).toBe(-32602);
+    });
+
+    test('reports suppressed notifications', async () => {
+      await client.request('notification/suppress', { key: 'tips.welcome' });
+      const result = await client.request<{ id: string | null; suppressed: boolean }>('notification/show', {
+        message: 'Welcome!',
+        doNotShowAgainKey: 'tips.welcome',
+      });
+
+      expect(result).toEqual({ id: null, suppressed: true });
+    });
+  });
+
+  describe('actions', () => {
+    test('notification/action emits action and closed', async () => {
+      const { id } = await client.request<{ id: string }>('notification/show', {
+        message: 'Push failed',
+        severity: 'error',
+        actions: [{ id: 'retry', label: 'Retry' }],
+      });
+
+      await client.request('notification/action', { id, actionId: 'retry' });
+
+      const action = await client.waitForNotification('notification/action');
+      expect(action.params).toEqual({ id, actionId: 'retry' });
+      const closed = await client.waitForNotification('notification/closed');
+      expect(closed.params).toEqual({ id, reason: 'action', actionId: 'retry' });
+    });
+
+    test('unknown actions are rejected', async () => {
+      const { id } = await client.request<{ id: string }>('notification/show', { message: 'Hello' });
+      const response = await client.requestRaw('notification/action', { id, actionId: 'nope' });
+      expect(response.error?.code).toBe(-32801);
+    });
+  });
+
+  describe('progress', () => {
+    test('updates, cancels and lists progress notifications', async () => {
+      const { id } = await client.request<{ id: string }>('notification/progress', {
+        message: 'Indexing...',
+        cancellable: true,
+      });
+
+      await client.request('notification/update', { id, percent: 40 });
+      const updated = await client.waitForNotification('notification/updated');
+      expect((updated.params as NotificationInfo).progress?.percent).toBe(40);
+
+      await client.request('notification/cancel', { id });
+      const { notifications } = await client.request<{ notifications: NotificationInfo[] }>('notification/list');
+      expect(notifications.find((n) => n.id === id)?.closeReason).toBe('cancelled');
+    });
+
+    test('closing unknown notifications fails', async () => {
+      const response = await client.requestRaw('notification/close', { id: 'missing' });
+      expect(response.error?.code).toBe(-32800);
+    });
+  });
+
+  describe('history', () => {
+    test('notification/clear removes closed notifications', async () => {
+      const { id } = await client.request<{ id: string }>('notification/show', { message: 'A' });
+      await client.request('notification/close', { id });
+      await client.request('notification/clear');
+
+      const { notifications } = await client.request<{ notifications: NotificationInfo[] }>('notification/list');
+      expect(notifications).toHaveLength(0);
+    });
+  });
+});
@@ -0,0 +1,128 @@
+/**
+ * Screen Reader Tests
+ */
+
+import { describe, test, expect, beforeEach } from 'bun:test';
+import {
+  Announcer,
+  ScreenReaderTracker,
+  linearizeRegion,
+  describeDiagnostic,
+  formatAnnounceNotification,
+  toSpeechLine,
+  type Announcement,
+  type AccessibilitySnapshot,
+} from '../../../../../src/clients/tui/accessibility/index.ts';
+import { createScreenBuffer } from '../../../../../src/clients/tui/rendering/buffer.ts';
+
+// ============================================
+// Test Setup
+// ============================================
+
+const EDITOR_FOCUS: AccessibilitySnapshot = {
+  focus: { id: 'editor-1', description: 'app.ts, editor' },
+  overlay: null,
+  diagnostic: null,
+};
+
+// ============================================
+// Tests
+// ============================================
+
+describe('Announcer', () => {
+  let announcer: Announcer;
+  let heard: Announcement[];
+
+  beforeEach(() => {
+    announcer = new Announcer();
+    heard = [];
+    announcer.onAnnouncement((a) => heard.push(a));
+  });
+
+  test('normalizes whitespace and drops empty text', () => {
+    announcer.announce('  Save   file \n\n  Confirm ', 'dialog');
+    announcer.announce('   ');
+
+    expect(heard.map((a) => a.text)).toEqual(['Save file\nConfirm']);
+    expect(toSpeechLine(heard[0]!.text)).toBe('Save file — Confirm');
+  });
+
+  test('drops immediate duplicates but repeats after a pause', () => {
+    announcer.announce('Saved', 'notification', 'polite', 1000);
+    announcer.announce('Saved', 'notification', 'polite', 1100);
+    announcer.announce('Saved', 'notification', 'polite', 2000);
+
+    expect(heard).toHaveLength(2);
+    expect(announcer.getLast()?.timestamp).toBe(2000);
+  });
+});
+
+describe('ScreenReaderTracker', () => {
+  let announcer: Announcer;
+  let tracker: ScreenReaderTracker;
+  let heard: string[];
+
+  beforeEach(() => {
+    announcer = new Announcer();
+    tracker = new ScreenReaderTracker(announcer);
+    heard = [];
+    announcer.onAnnouncement((a) => heard.push(`${a.kind}: ${a.text}`));
+  });
+
+  test('announces focus once, and again when it changes', () => {
+    tracker.update(EDITOR_FOCUS);
+    tracker.update(EDITOR_FOCUS);
+    tracker.update({ ...EDITOR_FOCUS, focus: { id: 'tree', description: 'src, folder, collapsed' } });
+
+    expect(heard).toEqual(['focus: app.ts, editor', 'focus: src, folder, collapsed']);
+  });
+
+  test('announces dialogs, then only their changed lines', () => {
+    tracker.update(EDITOR_FOCUS);
+    tracker.update({ ...EDITOR_FOCUS, overlay: { id: 'palette', text: 'Commands\nSave, 1 of 9', kind: 'dialog' } });
+    tracker.update({ ...EDITOR_FOCUS, overlay: { id: 'palette', text: 'Commands\nQuit, 2 of 9', kind: 'dialog' } });
+    tracker.update(EDITOR_FOCUS);
+
+    expect(heard).toEqual([
+      'focus: app.ts, editor',
+      'dialog: Commands\nSave, 1 of 9',
+      'dialog: Quit, 2 of 9',
+      'focus: app.ts, editor',
+    ]);
+  });
+
+  test('announces new diagnostics at the cursor', () => {
+    const error = { text: "Error: Cannot find name 'x' (ts)", isError: true };
+    tracker.update({ ...EDITOR_FOCUS, diagnostic: error });
+    tracker.update({ ...EDITOR_FOCUS, diagnostic: error });
+
+    expect(heard).toEqual(['focus: app.ts, editor', "diagnostic: Error: Cannot find name 'x' (ts)"]);
+    expect(announcer.getLast()?.priority).toBe('assertive');
+  });
+});
+
+describe('helpers', () => {
+  test('linearizeRegion reads text without borders', () => {
+    const buffer = createScreenBuffer({ width: 20, height: 4 });
+    buffer.drawBox({ x: 0, y: 0, width: 12, height: 4 }, '#fff', '#000', 'rounded');
+    buffer.writeString(2, 1, 'Delete?', '#fff', '#000');
+    buffer.writeString(2, 2, 'Yes  No', '#fff', '#000');
+
+    expect(linearizeRegion(buffer, { x: 0, y: 0, width: 12, height: 4 })).toBe('Delete?\nYes No');
+  });
+
+  test('describeDiagnostic names the severity and first line', () => {
+    expect(describeDiagnostic(2, 'Unused variable\nmore detail', 'eslint')).toBe('Warning: Unused variable (eslint)');
+    expect(describeDiagnostic(4, 'Consider const')).toBe('Hint: Consider const');
+  });
+
+  test('formats announcements as ECP notifications', () => {
+    const line = formatAnnounceNotification({ text: 'Saved', kind: 'notification', priority: 'polite', timestamp: 5 });
+    expect(line.endsWith('\n')).toBe(true);
+    expect(JSON.parse(line)).toEqual({
+      jsonrpc: '2.0',
+      method: 'accessibility/announce',
+      params: { text: 'Saved', kind: 'notification', priority: 'polite', timestamp: 5 },
+    });
+  });
+});
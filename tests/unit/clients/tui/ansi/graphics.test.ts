@@ -0,0 +1,100 @@
+/**
+ * Terminal Graphics Tests
+ *
+ * Protocol detection and the Kitty and sixel encoders.
+ */
+
+import { describe, test, expect } from 'bun:test';
+import { inflateSync } from 'zlib';
+import {
+  detectGraphicsProtocol,
+  kittyDelete,
+  kittyImage,
+  resolveGraphicsProtocol,
+  sixelImage,
+} from '../../../../../src/clients/tui/ansi/graphics.ts';
+
+function solid(width: number, height: number, rgba: number[]) {
+  const data = new Uint8ClampedArray(width * height * 4);
+  for (let i = 0; i < width * height; i++) data.set(rgba, i * 4);
+  return { width, height, data };
+}
+
+describe('detectGraphicsProtocol', () => {
+  test('detects kitty-compatible terminals', () => {
+    expect(detectGraphicsProtocol({ TERM: 'xterm-kitty' })).toBe('kitty');
+    expect(detectGraphicsProtocol({ TERM_PROGRAM: 'WezTerm' })).toBe('kitty');
+    expect(detectGraphicsProtocol({ TERM_PROGRAM: 'ghostty' })).toBe('kitty');
+  });
+
+  test('detects sixel terminals', () => {
+    expect(detectGraphicsProtocol({ TERM: 'foot' })).toBe('sixel');
+    expect(detectGraphicsProtocol({ TERM_PROGRAM: 'iTerm.app' })).toBe('sixel');
+    expect(detectGraphicsProtocol({ WT_SESSION: 'x' })).toBe('sixel');
+  });
+
+  test('falls back to half-blocks inside multiplexers and elsewhere', () => {
+    expect(detectGraphicsProtocol({ TERM: 'xterm-kitty', TMUX: '/tmp/tmux' })).toBe('halfblock');
+    expect(detectGraphicsProtocol({ TERM: 'screen-256color' })).toBe('halfblock');
+    expect(detectGraphicsProtocol({ TERM: 'xterm-256color' })).toBe('halfblock');
+  });
+
+  test('explicit settings override detection', () => {
+    expect(resolveGraphicsProtocol('sixel', { TERM: 'xterm-kitty' })).toBe('sixel');
+    expect(resolveGraphicsProtocol('auto', { TERM: 'xterm-kitty' })).toBe('kitty');
+    expect(resolveGraphicsProtocol('bogus', {})).toBe('halfblock');
+  });
+});
+
+describe('kittyImage', () => {
+  test('sends compressed RGBA scaled to the cell size', () => {
+    const pixels = solid(2, 2, [1, 2, 3, 4]);
+    const output = kittyImage(pixels, { id: 7, columns: 3, rows: 1 });
+    const match = /^\x1b_G([^;]*);([^\x1b]*)\x1b\\$/.exec(output);
+    expect(match).not.toBeNull();
+    expect(match![1]).toBe('a=T,f=32,o=z,s=2,v=2,c=3,r=1,i=7,q=2,C=1,m=0');
+    expect([...inflateSync(Buffer.from(match![2]!, 'base64'))]).toEqual([...pixels.data]);
+  });
+
+  test('splits large payloads into chunks', () => {
+    // Noise doesn't compress, so the payload exceeds one chunk
+    let seed = 1;
+    const noise = new Uint8ClampedArray(64 * 64 * 4).map(() => {
+      seed ^= seed << 13;
+      seed ^= seed >>> 17;
+      seed ^= seed << 5;
+      return seed & 0xff;
+    });
+    const pixels = { width: 64, height: 64, data: noise };
+    const chunks = kittyImage(pixels, { id: 1, columns: 8, rows: 4 }).split('\x1b\\').filter(Boolean);
+    expect(chunks.length).toBeGreaterThan(1);
+    expect(chunks[0]).toContain('m=1;');
+    expect(chunks[chunks.length - 1]!.startsWith('\x1b_Gm=0;')).toBe(true);
+  });
+
+  test('deletes by id', () => {
+    expect(kittyDelete(7)).toBe('\x1b_Ga=d,d=I,i=7,q=2\x1b\\');
+  });
+});
+
+describe('sixelImage', () => {
+  test('encodes bands with a palette and run lengths', () => {
+    const output = sixelImage(solid(8, 6, [255, 0, 0, 255]), { r: 0, g: 0, b: 0 });
+    expect(output.startsWith('\x1bP0;1;0q"1;1;8;6')).toBe(true);
+    expect(output).toContain('#0;2;99;2;2');
+    // One full band of 8 columns: '~' is all six pixels set
+    expect(output).toContain('#0!8~');
+    expect(output.endsWith('\x1b\\')).toBe(true);
+    expect(output).not.toContain('-');
+  });
+
+  test('separates bands and skips transparent pixels', () => {
+    const pixels = solid(2, 7, [0, 0, 255, 255]);
+    pixels.data[3] = 0;
+    const output = sixelImage(pixels, { r: 0, g: 0, b: 0 });
+    const body = output.slice(output.indexOf('#0', output.lastIndexOf(';2;')));
+    expect(body.split('-')).toHaveLength(2);
+    // First column of the first band is missing its top pixel
+    expect(body).toContain('#0}~');
+  });
+});
@@ -0,0 +1,145 @@
+/**
+ * LSP Integration Tests
+ *
+ * Tests for LSP status, completion edit, link and colour helpers.
+ */
+
+import { describe, test, expect } from 'bun:test';
+import {
+  formatProgressStatus,
+  planCompletionEdits,
+  positionAfterEdits,
+  parseLinkTarget,
+  lspColorToHex,
+  colorPresentationEdits,
+} from '../../../../../src/clients/tui/client/lsp-integration.ts';
+import type { LSPProgress } from '../../../../../src/services/lsp/types.ts';
+
+const progress = (overrides: Partial<LSPProgress>): LSPProgress => ({
+  languageId: 'go',
+  token: 1,
+  title: 'Indexing',
+  done: false,
+  ...overrides,
+});
+
+describe('formatProgressStatus', () => {
+  test('is empty when nothing is running', () => {
+    expect(formatProgressStatus([])).toBe('');
+    expect(formatProgressStatus([progress({ done: true })])).toBe('');
+  });
+
+  test('shows title, message and percentage', () => {
+    expect(formatProgressStatus([progress({ message: 'pkg/foo', percentage: 42.4 })]))
+      .toBe('⟳ Indexing: pkg/foo 42%');
+  });
+
+  test('counts additional running tasks', () => {
+    expect(formatProgressStatus([progress({}), progress({ token: 2, title: 'Loading' })]))
+      .toBe('⟳ Indexing (+1)');
+  });
+
+  test('falls back to the language ID when untitled', () => {
+    expect(formatProgressStatus([progress({ title: '' })])).toBe('⟳ go');
+  });
+});
+
+describe('planCompletionEdits', () => {
+  const range = (sl: number, sc: number, el: number, ec: number) => ({
+    start: { line: sl, character: sc },
+    end: { line: el, character: ec },
+  });
+
+  test('replaces the typed prefix when there is no textEdit', () => {
+    const plan = planCompletionEdits({ label: 'Println' }, { line: 4, character: 9 }, 5);
+    expect(plan.edits).toEqual([{ range: range(4, 5, 4, 9), newText: 'Println' }]);
+    expect(plan.cursor).toEqual({ line: 4, character: 12 });
+  });
+
+  test('uses textEdit and extends it to the cursor', () => {
+    const plan = planCompletionEdits(
+      { label: 'Println', textEdit: { range: range(4, 5, 4, 7), newText: 'Println' } },
+      { line: 4, character: 9 },
+      7
+    );
+    expect(plan.edits[0]!.range).toEqual(range(4, 5, 4, 9));
+  });
+
+  test('adds auto-import edits and shifts the cursor', () => {
+    const plan = planCompletionEdits(
+      {
+        label: 'Println',
+        additionalTextEdits: [{ range: range(2, 0, 2, 0), newText: 'import "fmt"\n\n' }],
+      },
+      { line: 4, character: 5 },
+      1
+    );
+
+    // Last-to-first: the completion before the import
+    expect(plan.edits.map((e) => e.range.start.line)).toEqual([4, 2]);
+    expect(plan.cursor).toEqual({ line: 6, character: 8 });
+  });
+});
+
+describe('positionAfterEdits', () => {
+  const edit = (sl: number, sc: number, el: number, ec: number, newText: string) => ({
+    range: { start: { line: sl, character: sc }, end: { line: el, character: ec } },
+    newText,
+  });
+
+  test('edits after the position do not move it', () => {
+    expect(positionAfterEdits({ line: 1, character: 3 }, [edit(5, 0, 5, 0, 'x\n')])).toEqual({ line: 1, character: 3 });
+  });
+
+  test('edits on earlier lines shift the line', () => {
+    expect(positionAfterEdits({ line: 3, character: 3 }, [edit(0, 0, 1, 0, 'a\nb\nc\n')])).toEqual({ line: 5, character: 3 });
+  });
+
+  test('edits earlier on the same line shift the column', () => {
+    expect(positionAfterEdits({ line: 2, character: 10 }, [edit(2, 0, 2, 4, 'ab')])).toEqual({ line: 2, character: 8 });
+  });
+
+  test('multiple edits compose', () => {
+    const result = positionAfterEdits({ line: 2, character: 10 }, [
+      edit(0, 0, 0, 0, 'x\n'),
+      edit(2, 0, 2, 0, 'yy'),
+    ]);
+    expect(result).toEqual({ line: 3, character: 12 });
+  });
+});
+
+describe('parseLinkTarget', () => {
+  test('returns URLs unchanged', () => {
+    expect(parseLinkTarget('https://example.com/#L10')).toEqual({ uri: 'https://example.com/#L10' });
+  });
+
+  test('splits line and column fragments from file URIs', () => {
+    expect(parseLinkTarget('file:///a.ts#L12')).toEqual({ uri: 'file:///a.ts', line: 11, column: undefined });
+    expect(parseLinkTarget('file:///a.ts#L12,5')).toEqual({ uri: 'file:///a.ts', line: 11, column: 4 });
+    expect(parseLinkTarget('file:///a.ts#3:2')).toEqual({ uri: 'file:///a.ts', line: 2, column: 1 });
+  });
+
+  test('leaves file URIs without a position alone', () => {
+    expect(parseLinkTarget('file:///a.ts')).toEqual({ uri: 'file:///a.ts' });
+  });
+});
+
+describe('lspColorToHex', () => {
+  test('converts channels in [0, 1] to hex', () => {
+    expect(lspColorToHex({ red: 1, green: 0.5, blue: 0, alpha: 1 })).toBe('#ff8000');
+  });
+});
+
+describe('colorPresentationEdits', () => {
+  const range = { start: { line: 1, character: 7 }, end: { line: 1, character: 14 } };
+
+  test('replaces the colour range with the label', () => {
+    expect(colorPresentationEdits({ label: 'red' }, range)).toEqual([{ range, newText: 'red' }]);
+  });
+
+  test('prefers the presentation text edit and keeps additional edits', () => {
+    const textEdit = { range, newText: 'rgb(255, 0, 0)' };
+    const extra = { range: { start: { line: 0, character: 0 }, end: { line: 0, character: 0 } }, newText: '@use "sass:color";\n' };
+    expect(colorPresentationEdits({ label: 'rgb', textEdit, additionalTextEdits: [extra] }, range)).toEqual([textEdit, extra]);
+  });
+});
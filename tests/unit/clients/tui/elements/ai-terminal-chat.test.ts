@@ -6,7 +6,11 @@ import { describe, test, expect, beforeEach } from 'bun:test';
 import {
   ClaudeTerminalChat,
   CodexTerminalChat,
+  CustomTerminalChat,
   createAITerminalChat,
+  expandProviderTemplate,
+  findCustomAIProvider,
+  type CustomAIProviderConfig,
   createClaudeTerminalChat,
   createCodexTerminalChat,
 } from '../../../../../src/clients/tui/elements/ai-terminal-chat.ts';
@@ -244,6 +248,115 @@ describe('CodexTerminalChat', () => {
   });
 });
 
+// ============================================
+// CustomTerminalChat Tests
+// ============================================
+
+describe('CustomTerminalChat', () => {
+  let ctx: ElementContext;
+  const aider: CustomAIProviderConfig = {
+    id: 'aider',
+    name: 'Aider',
+    command: 'aider',
+    args: ['--no-auto-commits', '--message', '${prompt}'],
+    resumeArgs: ['--restore-chat-history', '--session', '${sessionId}'],
+    env: { AIDER_CWD: '${cwd}' },
+    initialPromptMode: 'arg',
+    initialPrompt: 'hello',
+  };
+
+  beforeEach(() => {
+    ctx = createTestContext();
+  });
+
+  describe('provider info', () => {
+    test('uses the configured command and name', () => {
+      const chat = new CustomTerminalChat('c1', '', ctx, { config: aider, cwd: '/repo' });
+      expect(chat.getProvider()).toBe('custom');
+      expect(chat.getCustomProviderId()).toBe('aider');
+      expect(chat.getCommand()).toBe('aider');
+      expect(chat.getProviderName()).toBe('Aider');
+      expect(chat.getTitle()).toBe('Aider');
+    });
+
+    test('expands placeholders in env', () => {
+      const chat = new CustomTerminalChat('c1', '', ctx, { config: aider, cwd: '/repo' });
+      expect(chat.getEnv()).toEqual({ AIDER_CWD: '/repo' });
+    });
+  });
+
+  describe('arguments', () => {
+    test('new session passes the initial prompt as an argument', () => {
+      const chat = new CustomTerminalChat('c1', '', ctx, { config: aider, cwd: '/repo' });
+      expect(chat.getArgs()).toEqual(['--no-auto-commits', '--message', 'hello']);
+    });
+
+    test('resumed session uses resumeArgs', () => {
+      const chat = new CustomTerminalChat('c1', '', ctx, {
+        config: aider,
+        cwd: '/repo',
+        sessionId: 'abc',
+      });
+      expect(chat.getArgs()).toEqual(['--restore-chat-history', '--session', 'abc']);
+    });
+
+    test('placeholder-only arguments without a value are dropped', () => {
+      const config: CustomAIProviderConfig = {
+        id: 'agent',
+        name: 'Agent',
+        command: 'agent',
+        args: ['chat', '${prompt}'],
+      };
+      const chat = new CustomTerminalChat('c1', '', ctx, { config });
+      expect(chat.getArgs()).toEqual(['chat']);
+    });
+
+    test('a flag is dropped together with its empty value', () => {
+      const config: CustomAIProviderConfig = {
+        id: 'agent',
+        name: 'Agent',
+        command: 'agent',
+        args: ['--verbose', '--session', '${sessionId}', '--resume=${sessionId}', '--cwd', '${cwd}'],
+      };
+      const chat = new CustomTerminalChat('c1', '', ctx, { config, cwd: '/repo' });
+      expect(chat.getArgs()).toEqual(['--verbose', '--cwd', '/repo']);
+
+      chat.setSessionId('s1');
+      expect(chat.getArgs()).toEqual(['--verbose', '--session', 's1', '--resume=s1', '--cwd', '/repo']);
+    });
+  });
+
+  describe('state serialization', () => {
+    test('getState includes the custom provider ID', () => {
+      const chat = new CustomTerminalChat('c1', '', ctx, { config: aider, cwd: '/repo' });
+      const state = chat.getState();
+      expect(state.provider).toBe('custom');
+      expect(state.customProviderId).toBe('aider');
+      expect(state.cwd).toBe('/repo');
+    });
+  });
+
+  describe('helpers', () => {
+    test('expandProviderTemplate substitutes variables', () => {
+      process.env.ULTRA_TEST_TOKEN = 'secret';
+      const result = expandProviderTemplate('${cwd}:${sessionId}:${prompt}:${env:ULTRA_TEST_TOKEN}', {
+        cwd: '/w',
+        sessionId: 's1',
+        prompt: 'p',
+      });
+      expect(result).toBe('/w:s1:p:secret');
+      delete process.env.ULTRA_TEST_TOKEN;
+    });
+
+    test('findCustomAIProvider matches by ID and requires a command', () => {
+      const providers = [aider, { id: 'broken', name: 'Broken', command: '' }];
+      expect(findCustomAIProvider(providers, 'aider')).toBe(aider);
+      expect(findCustomAIProvider(providers, 'broken')).toBeNull();
+      expect(findCustomAIProvider(undefined, 'aider')).toBeNull();
+    });
+  });
+});
+
 // ============================================
 // Factory Function Tests
 // ============================================
@@ -323,6 +436,21 @@ describe('factory functions', () => {
       expect(chat.getSessionId()).toBe('factory-session');
     });
 
+    test('creates custom chat from settings', () => {
+      const settingsCtx = createTestContext({
+        getSetting: <T>(key: string, defaultValue: T) =>
+          (key === 'ai.customProviders'
+            ? [{ id: 'agent', name: 'Agent', command: 'agent' }]
+            : defaultValue) as T,
+      });
+      const chat = createAITerminalChat('chat1', '', settingsCtx, {
+        provider: 'custom',
+        customProviderId: 'agent',
+      });
+      expect(chat).toBeInstanceOf(CustomTerminalChat);
+      expect(chat.getCommand()).toBe('agent');
+    });
+
     test('creates Claude for custom provider (fallback)', () => {
       const chat = createAITerminalChat('chat1', 'AI', ctx, {
         provider: 'custom',
@@ -370,6 +498,68 @@ describe('input handling', () => {
     });
     expect(handled).toBe(false);
   });
+
+  test('getTranscriptLines is empty when no PTY', () => {
+    expect(chat.getTranscriptLines()).toEqual([]);
+  });
+});
+
+describe('prompt tracking', () => {
+  /** Exposes input tracking without a running PTY */
+  class TrackingChat extends ClaudeTerminalChat {
+    track(input: string, pasted = false): void {
+      this.trackInput(input, pasted);
+    }
+  }
+
+  let chat: TrackingChat;
+  let submitted: string[];
+
+  beforeEach(() => {
+    submitted = [];
+    chat = new TrackingChat('claude1', 'Claude', createTestContext(), {
+      callbacks: { onPromptSubmitted: (prompt) => submitted.push(prompt) },
+    });
+  });
+
+  test('records the first submitted prompt', () => {
+    for (const ch of 'fix the bug') chat.track(ch);
+    chat.track('\r');
+    for (const ch of 'thanks') chat.track(ch);
+    chat.track('\r');
+
+    expect(chat.getFirstPrompt()).toBe('fix the bug');
+    expect(submitted).toEqual(['fix the bug', 'thanks']);
+  });
+
+  test('applies backspace and ignores escape sequences', () => {
+    chat.track('a');
+    chat.track('b');
+    chat.track('\x7f');
+    chat.track('\x1b[A');
+    chat.track('c');
+    chat.track('\r');
+
+    expect(chat.getFirstPrompt()).toBe('ac');
+  });
+
+  test('blank lines and Ctrl+C do not submit', () => {
+    chat.track('\r');
+    chat.track('x');
+    chat.track('\x03');
+    chat.track('\r');
+
+    expect(submitted).toEqual([]);
+    expect(chat.getFirstPrompt()).toBeNull();
+  });
+
+  test('newlines in pasted text do not submit', () => {
+    chat.track('line one\nline two', true);
+    expect(submitted).toEqual([]);
+
+    chat.track('\r');
+    expect(submitted).toEqual(['line one line two']);
+  });
 });
 
 // ============================================
@@ -0,0 +1,108 @@
+/**
+ * DependencyGraphViewer Tests
+ *
+ * Tests for the dependency graph viewer: tree expansion, direction,
+ * cycle and layer-violation markers, filters and focus.
+ */
+
+import { describe, test, expect } from 'bun:test';
+import { DependencyGraphViewer } from '../../../../../src/clients/tui/elements/dependency-graph.ts';
+import { createTestContext } from '../../../../../src/clients/tui/elements/base.ts';
+import {
+  addDependency,
+  addDependencyNode,
+  createDependencyGraph,
+  type DependencyNode,
+} from '../../../../../src/core/deps/index.ts';
+
+const key = (k: string) => ({ key: k, ctrl: false, alt: false, shift: false, meta: false });
+
+/** api -> store -> api (cycle), cmd -> api, store -> util */
+function createViewer(onOpen?: (node: DependencyNode) => void): DependencyGraphViewer {
+  const graph = createDependencyGraph('go');
+  for (const id of ['cmd', 'api', 'store', 'util']) {
+    addDependencyNode(graph, { id, label: id, path: id });
+  }
+  addDependency(graph, 'cmd', 'api');
+  addDependency(graph, 'api', 'store');
+  addDependency(graph, 'store', 'api');
+  addDependency(graph, 'store', 'util');
+
+  const viewer = new DependencyGraphViewer('deps1', 'Dependencies', createTestContext(), { onOpen });
+  viewer.setBounds({ x: 0, y: 0, width: 60, height: 20 });
+  viewer.setGraph(graph, [
+    { name: 'top', include: ['cmd', 'api'] },
+    { name: 'bottom', include: ['store', 'util'] },
+  ]);
+  return viewer;
+}
+
+describe('DependencyGraphViewer', () => {
+  test('lists workspace nodes with cycle markers', () => {
+    const viewer = createViewer();
+    expect(viewer.getLines()).toEqual(['▸ api (1) ↻', '▸ cmd (1)', '▸ store (2) ↻', '  util']);
+    expect(viewer.getCycles()).toEqual([['api', 'store']]);
+  });
+
+  test('expands imports and stops at repeated nodes', () => {
+    const viewer = createViewer();
+    viewer.handleKey(key('l'));
+    viewer.handleKey(key('j'));
+    viewer.handleKey(key('l'));
+    expect(viewer.getLines().slice(0, 5)).toEqual([
+      '▾ api (1) ↻',
+      '  ▾ store (2) ↻',
+      '      api ↻ cycle ✗ bottom → top',
+      '      util',
+      '▸ cmd (1)',
+    ]);
+  });
+
+  test('collapses back to the parent with h', () => {
+    const viewer = createViewer();
+    viewer.handleKey(key('l'));
+    viewer.handleKey(key('j'));
+    viewer.handleKey(key('h'));
+    viewer.handleKey(key('h'));
+    expect(viewer.getLines()[0]).toBe('▸ api (1) ↻');
+  });
+
+  test('shows importers when the direction is flipped', () => {
+    const viewer = createViewer();
+    viewer.handleKey(key('d'));
+    expect(viewer.getDirection()).toBe('importedBy');
+    viewer.handleKey(key('l'));
+    expect(viewer.getLines().slice(0, 3)).toEqual(['▾ api (2) ↻', '    cmd', '  ▸ store (1) ↻ ✗ bottom → top']);
+  });
+
+  test('filters to cycles and violations', () => {
+    const viewer = createViewer();
+    viewer.handleKey(key('c'));
+    expect(viewer.getLines()).toEqual(['▸ api (1) ↻', '▸ store (2) ↻']);
+    viewer.handleKey(key('c'));
+    expect(viewer.getFilter()).toBe('violations');
+    expect(viewer.getLines()).toEqual(['▸ store (2) ↻']);
+  });
+
+  test('focuses on a node and opens it', () => {
+    const opened: string[] = [];
+    const viewer = createViewer((node) => opened.push(node.id));
+    viewer.handleKey(key('j'));
+    viewer.handleKey(key('f'));
+    expect(viewer.getFocus()).toBe('cmd');
+    expect(viewer.getLines()).toEqual(['▾ cmd (1)', '  ▸ api (1) ↻']);
+    viewer.handleKey(key('Enter'));
+    viewer.handleKey(key('Backspace'));
+    expect(viewer.getFocus()).toBeNull();
+    expect(opened).toEqual(['cmd']);
+  });
+
+  test('restores direction and filter', () => {
+    const viewer = createViewer();
+    viewer.setDirection('importedBy');
+    viewer.setFilter('cycles');
+    const restored = createViewer();
+    restored.setState(viewer.getState());
+    expect(restored.getState()).toEqual({ direction: 'importedBy', filter: 'cycles', focusId: null });
+  });
+});
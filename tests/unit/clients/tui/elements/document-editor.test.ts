@@ -332,6 +332,30 @@ describe('DocumentEditor', () => {
       }
       expect(found1).toBe(true);
     });
+
+    test('getCurrentParagraph spans non-blank lines around the cursor', () => {
+      editor.setContent('a\n\nb\nc\n\nd');
+      editor.setCursor({ line: 3, column: 0 });
+      expect(editor.getCurrentParagraph()).toEqual({ startLine: 2, endLine: 3 });
+
+      editor.setCursor({ line: 1, column: 0 });
+      expect(editor.getCurrentParagraph()).toEqual({ startLine: 1, endLine: 1 });
+    });
+
+    test('dims text outside the current paragraph', () => {
+      editor.setContent('aaa\n\nbbb');
+      editor.setCursor({ line: 0, column: 0 });
+      const plain = createScreenBuffer({ width: 80, height: 24 });
+      editor.render(plain);
+
+      editor.setDimOutsideParagraph(true);
+      const dimmed = createScreenBuffer({ width: 80, height: 24 });
+      editor.render(dimmed);
+
+      const col = editor.getGutterWidth() + 1;
+      expect(dimmed.get(col, 0)?.fg).toBe(plain.get(col, 0)?.fg);
+      expect(dimmed.get(col, 2)?.fg).not.toBe(plain.get(col, 2)?.fg);
+    });
   });
 
   // ─────────────────────────────────────────────────────────────────────────
@@ -508,6 +532,257 @@ describe('DocumentEditor', () => {
     });
   });
 
+  // ─────────────────────────────────────────────────────────────────────────
+  // Folding
+  // ─────────────────────────────────────────────────────────────────────────
+
+  describe('folding', () => {
+    const source = 'import a;\nimport b;\n\n// #region\nfunction f() {\n  x();\n}\n// #endregion\n// one\n// two';
+
+    beforeEach(() => {
+      editor.setLanguageId('typescript');
+      editor.setContent(source);
+    });
+
+    test('foldToLevel keeps the cursor region open', () => {
+      editor.setCursor({ line: 5, column: 0 });
+      editor.foldToLevel(1);
+
+      const folds = editor.getFoldManager();
+      expect(folds.isFolded(0)).toBe(true);
+      expect(folds.isFolded(3)).toBe(false);
+      expect(folds.isFolded(8)).toBe(true);
+    });
+
+    test('folds comments and imports', () => {
+      editor.setCursor({ line: 4, column: 0 });
+      editor.foldAllComments();
+      editor.foldImports();
+      expect(editor.getFoldManager().getFoldedLines()).toEqual([0, 8]);
+    });
+
+    test('foldAllExceptSelection moves no cursor into hidden lines', () => {
+      editor.setCursor({ line: 9, column: 3 });
+      editor.foldAllExceptSelection();
+
+      const folds = editor.getFoldManager();
+      expect(folds.isFolded(3)).toBe(true);
+      expect(folds.isFolded(8)).toBe(false);
+      expect(editor.getCursor()).toEqual({ line: 9, column: 3 });
+    });
+
+    test('creates and removes folds from a selection', () => {
+      editor.setSelection({ start: { line: 4, column: 0 }, end: { line: 6, column: 1 } });
+      expect(editor.createFoldFromSelection()).toBe(true);
+      expect(editor.getSelection()).toBeNull();
+      expect(editor.getFoldManager().isHidden(6)).toBe(true);
+
+      editor.setCursor({ line: 4, column: 0 });
+      expect(editor.removeManualFolds()).toBe(1);
+      expect(editor.getFoldManager().isHidden(6)).toBe(false);
+    });
+
+    test('single-line selections do not create folds', () => {
+      editor.setSelection({ start: { line: 4, column: 0 }, end: { line: 4, column: 5 } });
+      expect(editor.createFoldFromSelection()).toBe(false);
+    });
+
+    test('fold state round-trips through getState', () => {
+      editor.getFoldManager().fold(3);
+      editor.setSelection({ start: { line: 8, column: 0 }, end: { line: 9, column: 6 } });
+      editor.createFoldFromSelection();
+
+      const state = editor.getState();
+      expect(state.foldedRegions).toEqual([3, { startLine: 8, endLine: 9 }]);
+
+      const other = new DocumentEditor('doc2', 'test.ts', ctx);
+      other.setLanguageId('typescript');
+      other.setContent(source);
+      other.restoreFoldedRegions(state.foldedRegions!);
+      expect(other.getState().foldedRegions).toEqual(state.foldedRegions);
+    });
+  });
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Surround, Tags & Emmet
+  // ─────────────────────────────────────────────────────────────────────────
+
+  describe('surround, tags and emmet', () => {
+    const key = (k: string, shift = false) => ({ key: k, ctrl: false, alt: false, shift, meta: false });
+
+    test('surroundSelections wraps the selection and keeps it selected', () => {
+      editor.setContent('const x = value;');
+      editor.setSelection({ start: { line: 0, column: 10 }, end: { line: 0, column: 15 } });
+
+      expect(editor.surroundSelections('b')).toBe(true);
+      expect(editor.getContent()).toBe('const x = (value);');
+      expect(editor.getSelectedText()).toBe('value');
+
+      editor.undo();
+      expect(editor.getContent()).toBe('const x = value;');
+    });
+
+    test('typing a quote with a selection surrounds it', () => {
+      editor.setContent('say hi');
+      editor.setSelection({ start: { line: 0, column: 4 }, end: { line: 0, column: 6 } });
+      editor.handleKey(key('"'));
+      expect(editor.getContent()).toBe('say "hi"');
+    });
+
+    test('changes and deletes surrounding pairs', () => {
+      editor.setContent('<p class="a">text</p>');
+      editor.setCursor({ line: 0, column: 15 });
+
+      expect(editor.changeSurrounding('t', 'section')).toBe(true);
+      expect(editor.getContent()).toBe('<section class="a">text</section>');
+
+      expect(editor.deleteSurrounding('t')).toBe(true);
+      expect(editor.getContent()).toBe('text');
+      expect(editor.deleteSurrounding('(')).toBe(false);
+    });
+
+    test('auto-closes JSX tags on >', () => {
+      editor.setLanguageId('typescriptreact');
+      editor.setContent('const el = <div');
+      editor.setCursor({ line: 0, column: 15 });
+
+      editor.handleKey(key('>'));
+      expect(editor.getContent()).toBe('const el = <div></div>');
+      expect(editor.getCursor()).toEqual({ line: 0, column: 16 });
+    });
+
+    test('does not auto-close comparisons', () => {
+      editor.setLanguageId('typescript');
+      editor.setContent('if (a');
+      editor.setCursor({ line: 0, column: 5 });
+      editor.handleKey(key('>'));
+      expect(editor.getContent()).toBe('if (a>');
+    });
+
+    test('Tab expands Emmet abbreviations with the line indent', () => {
+      editor.setLanguageId('html');
+      editor.setContent('  ul>li*2');
+      editor.setCursor({ line: 0, column: 9 });
+
+      editor.handleKey(key('Tab'));
+      expect(editor.getContent()).toBe('  <ul>\n    <li></li>\n    <li></li>\n  </ul>');
+      expect(editor.getCursor()).toEqual({ line: 1, column: 8 });
+    });
+
+    test('Tab indents when there is no abbreviation', () => {
+      editor.setLanguageId('html');
+      editor.setContent('');
+      editor.handleKey(key('Tab'));
+      expect(editor.getContent()).toBe('  ');
+    });
+  });
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Language Server Decorations
+  // ─────────────────────────────────────────────────────────────────────────
+
+  describe('language server decorations', () => {
+    test('folding ranges replace indentation folding', () => {
+      editor.setContent('a\n  b\n  c\nd\ne\nf');
+      expect(editor.getFoldManager().canFold(0)).toBe(true);
+
+      editor.setFoldingRanges([{ startLine: 3, endLine: 5 }]);
+      const folds = editor.getFoldManager();
+      expect(folds.canFold(0)).toBe(false);
+      expect(folds.canFold(3)).toBe(true);
+
+      // Fold state survives a refresh with the same start line
+      folds.fold(3);
+      editor.setFoldingRanges([{ startLine: 3, endLine: 4 }]);
+      expect(folds.isFolded(3)).toBe(true);
+      expect(folds.isHidden(5)).toBe(false);
+
+      editor.setFoldingRanges(null);
+      expect(editor.getFoldManager().canFold(0)).toBe(true);
+    });
+
+    test('getLinkAt finds links by position', () => {
+      editor.setContent('import "./util.ts";');
+      editor.setDocumentLinks([{ startLine: 0, startColumn: 8, endLine: 0, endColumn: 17, target: 'file:///util.ts' }]);
+
+      expect(editor.getLinkAt({ line: 0, column: 8 })?.target).toBe('file:///util.ts');
+      expect(editor.getLinkAt({ line: 0, column: 17 })).toBeNull();
+      expect(editor.getLinkAt({ line: 0, column: 2 })).toBeNull();
+    });
+
+    test('Ctrl+click on a link opens it instead of adding a cursor', () => {
+      const opened: string[] = [];
+      editor = new DocumentEditor('doc1', 'test.ts', ctx, {
+        onOpenLink: (link) => opened.push(link.target ?? ''),
+      });
+      editor.setBounds({ x: 0, y: 0, width: 80, height: 24 });
+      editor.setContent('see https://example.com');
+      editor.setDocumentLinks([{ startLine: 0, startColumn: 4, endLine: 0, endColumn: 23, target: 'https://example.com' }]);
+
+      const x = editor.getGutterWidth() + 6;
+      editor.handleMouse({ type: 'press', button: 'left', x, y: 0, ctrl: true, shift: false, alt: false });
+
+      expect(opened).toEqual(['https://example.com']);
+      expect(editor.getCursors()).toHaveLength(1);
+    });
+
+    test('colour values render with the colour as background', () => {
+      editor.setContent('color: #ff0000;');
+      editor.setColorDecorators([{ line: 0, startColumn: 7, endColumn: 14, color: '#ff0000' }]);
+      expect(editor.getColorAt({ line: 0, column: 9 })?.color).toBe('#ff0000');
+
+      const buffer = createScreenBuffer({ width: 80, height: 24 });
+      editor.render(buffer);
+
+      const x = editor.getGutterWidth() + 7;
+      expect(buffer.get(x, 0)?.char).toBe('#');
+      expect(buffer.get(x, 0)?.bg).toBe('#ff0000');
+      expect(buffer.get(x - 1, 0)?.bg).not.toBe('#ff0000');
+    });
+  });
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Accessibility
+  // ─────────────────────────────────────────────────────────────────────────
+
+  describe('accessibility', () => {
+    test('describes the editor and its state', () => {
+      editor.setContent('x');
+      editor.setReadOnly(true);
+      expect(editor.getAccessibleDescription()).toBe('test.ts, editor, read only');
+    });
+
+    test('finds the most severe diagnostic at the cursor', () => {
+      editor.setContent('const x = y;');
+      editor.setDiagnostics([
+        { startLine: 0, startColumn: 6, endLine: 0, endColumn: 7, message: 'Unused', severity: 2 },
+        { startLine: 0, startColumn: 0, endLine: 0, endColumn: 12, message: 'Broken', severity: 1 },
+      ]);
+      editor.setCursor({ line: 0, column: 6 });
+      expect(editor.getDiagnosticAtCursor()?.message).toBe('Broken');
+
+      editor.setDiagnostics([]);
+      expect(editor.getDiagnosticAtCursor()).toBeNull();
+    });
+
+    test('screen reader mode reports the cursor instead of painting it', () => {
+      const readerCtx = createTestContext({
+        getSetting: <T>(key: string, defaultValue: T) =>
+          (key === 'tui.accessibility.screenReaderMode' ? true : defaultValue) as T,
+      });
+      const readerEditor = new DocumentEditor('doc2', 'test.ts', readerCtx);
+      readerEditor.setBounds({ x: 0, y: 0, width: 80, height: 24 });
+      readerEditor.onFocus();
+      readerEditor.setContent('hello\nworld');
+      readerEditor.setCursor({ line: 1, column: 2 });
+
+      const buffer = createScreenBuffer({ width: 80, height: 24 });
+      readerEditor.render(buffer);
+
+      expect(readerEditor.getCursorScreenPosition()).toEqual({ x: readerEditor.getGutterWidth() + 2, y: 1 });
+    });
+  });
+
   // ─────────────────────────────────────────────────────────────────────────
   // State Serialization
   // ─────────────────────────────────────────────────────────────────────────
@@ -349,6 +349,28 @@ describe('FileTree', () => {
       expect(srcNode?.expanded).toBe(true);
     });
   });
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Accessibility
+  // ─────────────────────────────────────────────────────────────────────────
+
+  describe('accessibility', () => {
+    test('describes the selected node and its position', () => {
+      tree.selectPath('/project/src');
+      expect(tree.getAccessibleDescription()).toBe('src, folder, collapsed, level 1, 1 of 4');
+
+      tree.toggle();
+      tree.selectPath('/project/src/utils.ts');
+      expect(tree.getAccessibleDescription()).toBe('utils.ts, level 2, 3 of 6');
+    });
+
+    test('cursor sits at the start of the selected row', () => {
+      tree.selectPath('/project/src');
+      tree.toggle();
+      tree.selectPath('/project/src/index.ts');
+      expect(tree.getCursorScreenPosition()).toEqual({ x: 3, y: 1 });
+    });
+  });
 });
 
 // ============================================
@@ -0,0 +1,161 @@
+/**
+ * ImageViewer Tests
+ *
+ * Tests for the image viewer: half-block rendering, zoom and pan,
+ * metadata and graphics-protocol placements.
+ */
+
+import { describe, test, expect } from 'bun:test';
+import { ImageViewer } from '../../../../../src/clients/tui/elements/image-viewer.ts';
+import { createTestContext } from '../../../../../src/clients/tui/elements/base.ts';
+import { createScreenBuffer } from '../../../../../src/clients/tui/rendering/buffer.ts';
+import { encodePng } from '../../../core/image/encoders.ts';
+
+const key = (k: string) => ({ key: k, ctrl: false, alt: false, shift: false, meta: false });
+
+/** 4x4 PNG: top half red, bottom half blue */
+function redBluePng(width = 4, height = 4): Uint8Array {
+  const samples: number[] = [];
+  for (let y = 0; y < height; y++) {
+    for (let x = 0; x < width; x++) {
+      samples.push(...(y < height / 2 ? [255, 0, 0] : [0, 0, 255]));
+    }
+  }
+  return encodePng({ width, height, colorType: 2, bitDepth: 8, samples });
+}
+
+function createViewer(protocol = 'halfblock', width = 20, height = 8): ImageViewer {
+  const ctx = createTestContext({
+    getSetting: <T>(k: string, defaultValue: T) => (k === 'tui.imagePreview.protocol' ? (protocol as T) : defaultValue),
+    getThemeColor: (_key: string, fallback = '#000000') => fallback,
+  });
+  const viewer = new ImageViewer('image1', 'red-blue.png', ctx);
+  viewer.setBounds({ x: 0, y: 0, width, height });
+  return viewer;
+}
+
+describe('ImageViewer', () => {
+  test('renders half-blocks with two pixels per cell', () => {
+    const viewer = createViewer();
+    viewer.setImage(redBluePng(), 'file:///tmp/red-blue.png');
+    const buffer = createScreenBuffer({ width: 20, height: 8 });
+    viewer.render(buffer);
+
+    // 4x4 image fits unscaled: 4 columns x 2 rows, centered below the header
+    const cells = [];
+    for (let y = 1; y < 8; y++) {
+      for (let x = 0; x < 20; x++) {
+        const cell = buffer.get(x, y)!;
+        if (cell.fg === '#ff0000' || cell.bg === '#0000ff' || cell.bg === '#ff0000') cells.push({ x, y, cell });
+      }
+    }
+    expect(cells).toHaveLength(8);
+    expect(cells[0]!.cell.char).toBe(' ');
+    expect(cells[0]!.cell.bg).toBe('#ff0000');
+    expect(cells.every(({ x }) => x >= 8 && x < 12)).toBe(true);
+  });
+
+  test('shows dimensions and format in the header', () => {
+    const viewer = createViewer();
+    viewer.setImage(redBluePng(), 'file:///tmp/red-blue.png');
+    const buffer = createScreenBuffer({ width: 40, height: 8 });
+    viewer.setBounds({ x: 0, y: 0, width: 40, height: 8 });
+    viewer.render(buffer);
+
+    let header = '';
+    for (let x = 0; x < 40; x++) header += buffer.get(x, 0)!.char;
+    expect(header).toContain('red-blue.png');
+    expect(header).toContain('4×4 PNG');
+    expect(header).toContain('100% fit');
+  });
+
+  test('fits large images and zooms in steps', () => {
+    const viewer = createViewer();
+    viewer.setImage(redBluePng(80, 80), 'file:///tmp/big.png');
+    // View is 20 x 14 half-block pixels
+    expect(viewer.isFit()).toBe(true);
+    expect(viewer.getScale()).toBeCloseTo(14 / 80);
+
+    viewer.handleKey(key('+'));
+    expect(viewer.isFit()).toBe(false);
+    expect(viewer.getScale()).toBe(0.25);
+    viewer.handleKey(key('-'));
+    expect(viewer.getScale()).toBe(0.125);
+    viewer.handleKey(key('1'));
+    expect(viewer.getScale()).toBe(1);
+    viewer.handleKey(key('f'));
+    expect(viewer.isFit()).toBe(true);
+  });
+
+  test('pans within the zoomed image', () => {
+    const viewer = createViewer();
+    viewer.setImage(redBluePng(80, 80), 'file:///tmp/big.png');
+    viewer.actualSize();
+    const start = viewer.getPan();
+
+    viewer.handleKey(key('ArrowDown'));
+    expect(viewer.getPan().y).toBeGreaterThan(start.y);
+    for (let i = 0; i < 100; i++) viewer.handleKey(key('l'));
+    expect(viewer.getPan().x).toBe(80 - 20);
+    for (let i = 0; i < 100; i++) viewer.handleKey(key('k'));
+    expect(viewer.getPan().y).toBe(0);
+  });
+
+  test('shows metadata in the info panel', () => {
+    const viewer = createViewer('halfblock', 60, 12);
+    viewer.setImage(redBluePng(), 'file:///tmp/red-blue.png');
+    viewer.handleKey(key('i'));
+    expect(viewer.isInfoVisible()).toBe(true);
+
+    const rows = Object.fromEntries(viewer.getMetadataRows());
+    expect(rows['Format']).toBe('PNG');
+    expect(rows['Size']).toBe('4 × 4');
+    expect(rows['Color']).toBe('RGB');
+    expect(rows['Renderer']).toBe('halfblock');
+
+    const buffer = createScreenBuffer({ width: 60, height: 12 });
+    viewer.render(buffer);
+    let text = '';
+    for (let y = 0; y < 12; y++) for (let x = 32; x < 60; x++) text += buffer.get(x, y)!.char;
+    expect(text).toContain('Bit depth');
+  });
+
+  test('shows decode errors', () => {
+    const viewer = createViewer();
+    viewer.setImage(new Uint8Array([0x89, 0x50, 0x4e, 0x47, 1, 2]), 'file:///tmp/broken.png');
+    expect(viewer.getImage()).toBeNull();
+    expect(viewer.getError()).toContain('PNG');
+    expect(viewer.getAccessibleDescription()).toContain('broken.png');
+  });
+
+  test('returns a graphics placement for kitty and sixel only', () => {
+    const halfblock = createViewer('halfblock');
+    halfblock.setImage(redBluePng(), 'file:///tmp/a.png');
+    expect(halfblock.getGraphicsPlacement()).toBeNull();
+
+    const kitty = createViewer('kitty');
+    kitty.onVisibilityChange(true);
+    kitty.setImage(redBluePng(40, 40), 'file:///tmp/a.png');
+    const placement = kitty.getGraphicsPlacement()!;
+    expect(placement.key).toBe('image1');
+    expect(placement.rect.y).toBeGreaterThanOrEqual(1);
+    const pixels = placement.getPixels();
+    expect([pixels.width, pixels.height]).toEqual([40, 40]);
+
+    const before = placement.signature;
+    kitty.zoomIn();
+    expect(kitty.getGraphicsPlacement()!.signature).not.toBe(before);
+  });
+
+  test('round-trips state', () => {
+    const viewer = createViewer();
+    viewer.setImage(redBluePng(), 'file:///tmp/a.png');
+    viewer.setZoom(2);
+    viewer.toggleInfo();
+    expect(viewer.getState()).toEqual({ uri: 'file:///tmp/a.png', zoom: 2, showInfo: true });
+
+    const restored = createViewer();
+    restored.setState(viewer.getState());
+    expect(restored.getState()).toEqual(viewer.getState());
+  });
+});
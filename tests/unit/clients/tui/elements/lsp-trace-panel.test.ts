@@ -0,0 +1,91 @@
+/**
+ * LSPTracePanel Tests
+ *
+ * Tests for the language server trace panel: follow mode, payload
+ * expansion and clearing.
+ */
+
+import { describe, test, expect, beforeEach } from 'bun:test';
+import { LSPTracePanel } from '../../../../../src/clients/tui/elements/lsp-trace-panel.ts';
+import { createTestContext } from '../../../../../src/clients/tui/elements/base.ts';
+import { createScreenBuffer } from '../../../../../src/clients/tui/rendering/buffer.ts';
+import type { LSPTraceEntry } from '../../../../../src/services/lsp/trace.ts';
+
+const key = (k: string) => ({ key: k, ctrl: false, alt: false, shift: false, meta: false });
+
+function createEntries(count: number): LSPTraceEntry[] {
+  return Array.from({ length: count }, (_, i) => ({
+    seq: i + 1,
+    timestamp: 0,
+    direction: 'send' as const,
+    kind: 'notification' as const,
+    method: `method/${i}`,
+    payload: i === 0 ? '{"uri":"file:///a.go"}' : undefined,
+  }));
+}
+
+function rowText(buffer: ReturnType<typeof createScreenBuffer>, y: number, width: number): string {
+  let text = '';
+  for (let x = 0; x < width; x++) text += buffer.get(x, y)?.char ?? '';
+  return text;
+}
+
+describe('LSPTracePanel', () => {
+  let panel: LSPTracePanel;
+
+  beforeEach(() => {
+    panel = new LSPTracePanel('trace1', '', createTestContext(), 'go');
+    panel.setBounds({ x: 0, y: 0, width: 60, height: 6 });
+  });
+
+  test('uses the language ID in the title', () => {
+    expect(panel.getTitle()).toBe('LSP Trace: go');
+  });
+
+  test('follows new entries by default', () => {
+    panel.setEntries(createEntries(20));
+    const buffer = createScreenBuffer({ width: 60, height: 6 });
+    panel.render(buffer);
+
+    expect(rowText(buffer, 5, 60)).toContain('method/19');
+    expect(panel.isFollowing()).toBe(true);
+  });
+
+  test('moving up stops following, End resumes', () => {
+    panel.setEntries(createEntries(20));
+    panel.handleKey(key('k'));
+    expect(panel.isFollowing()).toBe(false);
+
+    panel.handleKey(key('End'));
+    expect(panel.isFollowing()).toBe(true);
+  });
+
+  test('Enter expands the selected payload', () => {
+    panel.setEntries(createEntries(3));
+    panel.handleKey(key('Home'));
+    panel.handleKey(key('Enter'));
+
+    expect(panel.getLines()).toHaveLength(4);
+    expect(panel.getLines()[1]).toBe('{"uri":"file:///a.go"}');
+
+    panel.handleKey(key('Enter'));
+    expect(panel.getLines()).toHaveLength(3);
+  });
+
+  test('c clears the trace and notifies', () => {
+    const cleared: string[] = [];
+    panel.setCallbacks({ onClear: (id) => cleared.push(id) });
+    panel.setEntries(createEntries(3));
+
+    panel.handleKey(key('c'));
+    expect(panel.getEntries()).toEqual([]);
+    expect(cleared).toEqual(['go']);
+  });
+
+  test('onClose is called when unmounted', () => {
+    let closed = false;
+    panel.setCallbacks({ onClose: () => { closed = true; } });
+    panel.onUnmount();
+    expect(closed).toBe(true);
+  });
+});
@@ -9,6 +9,36 @@ import {
 } from '../../../../../src/clients/tui/elements/terminal-session.ts';
 import { createTestContext, type ElementContext } from '../../../../../src/clients/tui/elements/base.ts';
 import { createScreenBuffer } from '../../../../../src/clients/tui/rendering/buffer.ts';
+import type { PTYBackend } from '../../../../../src/terminal/pty-backend.ts';
+
+// ============================================
+// Helpers
+// ============================================
+
+/**
+ * Minimal PTY stand-in that lets tests emit output.
+ */
+function createFakePty(): { pty: PTYBackend; emit: (data: string) => void } {
+  const dataCallbacks: Array<(data: string) => void> = [];
+  const noop = () => () => {};
+  const pty = {
+    onUpdate: noop,
+    onTitle: noop,
+    onNotification: noop,
+    onExit: noop,
+    onData: (callback: (data: string) => void) => {
+      dataCallbacks.push(callback);
+      return () => {};
+    },
+    getSize: () => ({ cols: 80, rows: 24 }),
+    getCwd: () => null,
+    write: () => {},
+    resize: () => {},
+    kill: () => {},
+    isRunning: () => true,
+  } as unknown as PTYBackend;
+  return { pty, emit: (data) => dataCallbacks.forEach((cb) => cb(data)) };
+}
 
 // ============================================
 // Tests
@@ -387,6 +417,54 @@ describe('TerminalSession', () => {
     });
   });
 
+  // ─────────────────────────────────────────────────────────────────────────
+  // Output Watching
+  // ─────────────────────────────────────────────────────────────────────────
+
+  describe('output watching', () => {
+    test('watch rule match invokes onWatchMatch', () => {
+      const messages: string[] = [];
+      terminal.setCallbacks({ onWatchMatch: (match) => messages.push(match.message) });
+      terminal.addWatchRule({ pattern: 'FAIL (\\w+)', message: 'Failed: $1' });
+      const { pty, emit } = createFakePty();
+      terminal.attachPty(pty);
+
+      emit('ok TestA\r\nFAIL TestB\r\n');
+
+      expect(messages).toEqual(['Failed: TestB']);
+    });
+
+    test('pasted input does not trigger watch rules', () => {
+      const messages: string[] = [];
+      terminal.setCallbacks({ onWatchMatch: (match) => messages.push(match.message) });
+      terminal.addWatchRule({ pattern: 'FAIL (\\w+)', message: 'Failed: $1' });
+      terminal.attachPty(createFakePty().pty);
+
+      terminal.write('echo FAIL TestB\r\n');
+
+      expect(messages).toEqual([]);
+    });
+
+    test('session watch rules round-trip through state', () => {
+      terminal.addWatchRule({ id: 'w1', pattern: 'panic:' });
+
+      const state = terminal.getState();
+      expect(state.watchRules?.map((r) => r.id)).toEqual(['w1']);
+
+      const restored = new TerminalSession('term2', 'Terminal', ctx);
+      restored.setState(state);
+      expect(restored.getWatchRules().map((r) => r.id)).toContain('w1');
+    });
+
+    test('notify when finished can be toggled', () => {
+      terminal.setNotifyWhenFinished(true);
+      expect(terminal.isNotifyWhenFinishedArmed()).toBe(true);
+
+      terminal.setNotifyWhenFinished(false);
+      expect(terminal.isNotifyWhenFinishedArmed()).toBe(false);
+    });
+  });
+
   // ─────────────────────────────────────────────────────────────────────────
   // Scrolling
   // ─────────────────────────────────────────────────────────────────────────
@@ -0,0 +1,114 @@
+/**
+ * TodoPanel Tests
+ *
+ * Tests for the TODO/FIXME panel: grouping, collapsing, age filtering
+ * and blame requests.
+ */
+
+import { describe, test, expect } from 'bun:test';
+import { TodoPanel, type TodoPanelCallbacks } from '../../../../../src/clients/tui/elements/todo-panel.ts';
+import { createTestContext } from '../../../../../src/clients/tui/elements/base.ts';
+import type { CommentTag } from '../../../../../src/services/search/comment-tags.ts';
+
+const key = (k: string) => ({ key: k, ctrl: false, alt: false, shift: false, meta: false });
+
+const TAGS: CommentTag[] = [
+  { path: 'src/a.ts', line: 3, column: 5, tag: 'TODO', text: 'handle errors' },
+  { path: 'src/a.ts', line: 10, column: 2, tag: 'FIXME', text: 'leaks' },
+  { path: 'src/b.ts', line: 7, column: 0, tag: 'TODO', text: 'rename' },
+];
+
+function createPanel(callbacks: TodoPanelCallbacks = {}): TodoPanel {
+  const panel = new TodoPanel('todo1', 'TODOs', createTestContext(), callbacks);
+  panel.setBounds({ x: 0, y: 0, width: 40, height: 10 });
+  panel.setTags(TAGS.map((t) => ({ ...t })));
+  return panel;
+}
+
+describe('TodoPanel', () => {
+  test('groups by file by default', () => {
+    expect(createPanel().getLines()).toEqual([
+      '▾ src/a.ts (2)',
+      '  TODO 3 handle errors',
+      '  FIXME 10 leaks',
+      '▾ src/b.ts (1)',
+      '  TODO 7 rename',
+    ]);
+  });
+
+  test('cycles grouping with g', () => {
+    const panel = createPanel();
+    panel.handleKey(key('g'));
+    expect(panel.getGroupBy()).toBe('tag');
+    expect(panel.getLines()[0]).toBe('▾ TODO (2)');
+    expect(panel.getLines()[1]).toBe('  TODO a.ts:3 handle errors');
+  });
+
+  test('collapses groups and opens tags', () => {
+    const opened: unknown[] = [];
+    const panel = createPanel({ onOpen: (...args) => opened.push(args) });
+    panel.handleKey(key('h'));
+    expect(panel.getLines()[0]).toBe('▸ src/a.ts (2)');
+    panel.handleKey(key('j'));
+    panel.handleKey(key('j'));
+    panel.handleKey(key('Enter'));
+    expect(opened).toEqual([['src/b.ts', 7, 0]]);
+  });
+
+  test('requests blame once when grouping by author', () => {
+    const requested: string[][] = [];
+    const panel = createPanel({ onBlameNeeded: (paths) => requested.push(paths) });
+    panel.setGroupBy('author');
+    expect(requested).toEqual([['src/a.ts', 'src/b.ts']]);
+    expect(panel.getLines()[0]).toBe('▾ Unknown (3)');
+
+    panel.setBlame('src/a.ts', [{ commit: 'abc', author: 'Ann', date: '2020-01-01', line: 3, content: '' }]);
+    panel.setGroupBy('file');
+    panel.setGroupBy('author');
+    expect(requested).toHaveLength(1);
+    expect(panel.getLines()[0]).toBe('▾ Ann (1)');
+  });
+
+  test('replaces the tags of one file', () => {
+    const panel = createPanel();
+    panel.setFileTags('src/a.ts', [{ path: 'src/a.ts', line: 4, column: 0, tag: 'HACK', text: 'moved' }]);
+    panel.setFileTags('src/c.ts', [{ path: 'src/c.ts', line: 1, column: 0, tag: 'TODO', text: 'new' }]);
+    panel.setFileTags('src/b.ts', []);
+
+    expect(panel.getLines()).toEqual([
+      '▾ src/a.ts (1)',
+      '  HACK 4 moved',
+      '▾ src/c.ts (1)',
+      '  TODO 1 new',
+    ]);
+  });
+
+  test('reports becoming visible', () => {
+    let shown = 0;
+    const panel = createPanel({ onShow: () => shown++ });
+    panel.onVisibilityChange(true);
+    panel.onVisibilityChange(true);
+    panel.onVisibilityChange(false);
+    panel.onVisibilityChange(true);
+    expect(shown).toBe(2);
+  });
+
+  test('filters by minimum age', () => {
+    const panel = createPanel();
+    panel.setBlame('src/a.ts', [{ commit: 'abc', author: 'Ann', date: '2020-01-01', line: 3, content: '' }]);
+    panel.handleKey(key('a'));
+    expect(panel.getMinAgeDays()).toBe(30);
+    expect(panel.getLines()).toHaveLength(2);
+    expect(panel.getLines()[1]).toMatch(/^ {2}TODO 3 handle errors · \d+d$/);
+  });
+
+  test('persists grouping and collapsed groups', () => {
+    const panel = createPanel();
+    panel.setGroupBy('tag');
+    panel.handleKey(key('h'));
+    const restored = createPanel();
+    restored.setState(panel.getState());
+    expect(restored.getGroupBy()).toBe('tag');
+    expect(restored.getLines()[0]).toBe('▸ TODO (2)');
+  });
+});
@@ -0,0 +1,163 @@
+/**
+ * Message Catalog Tests
+ *
+ * Checks the catalogs against each other and the sources against the
+ * catalogs: every locale translates every English message with the same
+ * arguments, every `t()` key exists, and no new hardcoded UI strings
+ * appear in the localized sources (see hardcoded-baseline.json).
+ */
+
+import { describe, test, expect, afterEach } from 'bun:test';
+import * as fs from 'fs';
+import {
+  t,
+  setLocale,
+  getLocale,
+  resolveLocale,
+  onLocaleChange,
+  getMessageArguments,
+  MESSAGE_CATALOGS,
+  AVAILABLE_LOCALES,
+} from '../../../../../src/clients/tui/i18n/index.ts';
+import { en } from '../../../../../src/clients/tui/i18n/messages/en.ts';
+import {
+  BASELINE_PATH,
+  findHardcodedStrings,
+  findMessageKeys,
+  scanHardcodedStrings,
+  diffAgainstBaseline,
+  type HardcodedBaseline,
+} from '../../../../../src/clients/tui/i18n/extract.ts';
+import { getSettingDescription } from '../../../../../src/clients/tui/overlays/settings-utils.ts';
+
+// ============================================
+// Test Helpers
+// ============================================
+
+/**
+ * All TypeScript files under a directory.
+ */
+function listTypeScriptFiles(dir: string): string[] {
+  return fs.readdirSync(dir, { withFileTypes: true }).flatMap((entry) => {
+    const fullPath = `${dir}/${entry.name}`;
+    if (entry.isDirectory()) return listTypeScriptFiles(fullPath);
+    return entry.name.endsWith('.ts') ? [fullPath] : [];
+  });
+}
+
+// ============================================
+// Tests
+// ============================================
+
+describe('locale selection', () => {
+  afterEach(() => {
+    setLocale('en');
+  });
+
+  test('resolves settings and environment variables', () => {
+    expect(resolveLocale('de')).toBe('de');
+    expect(resolveLocale('ja-JP')).toBe('ja');
+    expect(resolveLocale('auto', { LANG: 'de_AT.UTF-8' })).toBe('de');
+    expect(resolveLocale('auto', { LC_ALL: 'ja_JP.UTF-8', LANG: 'de_DE.UTF-8' })).toBe('ja');
+    expect(resolveLocale('auto', { LANG: 'C' })).toBe('en');
+    expect(resolveLocale('fr')).toBe('en');
+  });
+
+  test('translates and falls back to English', () => {
+    setLocale('de');
+    expect(getLocale()).toBe('de');
+    expect(t('common.cancel')).toBe('Abbrechen');
+    expect(t('statusBar.selection', { lines: 1, chars: 12 })).toBe('1 Zeile, 12 Zeichen ausgewählt');
+
+    setLocale('ja');
+    expect(t('statusBar.position', { line: 3, column: 7 })).toBe('3 行、7 列');
+
+    MESSAGE_CATALOGS.ja!['common.save'] = undefined;
+    expect(t('common.save')).toBe('Save');
+    MESSAGE_CATALOGS.ja!['common.save'] = '保存';
+  });
+
+  test('translates catalog setting descriptions', () => {
+    expect(getSettingDescription('tui.imagePreview.protocol')).toBe('How images are drawn (auto-detected by default)');
+
+    setLocale('de');
+    expect(getSettingDescription('tui.imagePreview.protocol')).toBe('Wie Bilder gezeichnet werden (standardmäßig automatisch erkannt)');
+    expect(getSettingDescription('editor.tabSize')).toBe('Number of spaces per tab');
+  });
+
+  test('notifies listeners when the locale changes', () => {
+    const changes: string[] = [];
+    const unsubscribe = onLocaleChange((locale) => changes.push(locale));
+    setLocale('ja');
+    setLocale('ja_JP.UTF-8');
+    setLocale('en');
+    unsubscribe();
+    setLocale('de');
+
+    expect(changes).toEqual(['ja', 'en']);
+  });
+});
+
+describe('catalogs', () => {
+  test('ship English and at least one other locale', () => {
+    expect(AVAILABLE_LOCALES).toContain('en');
+    expect(AVAILABLE_LOCALES.length).toBeGreaterThan(1);
+  });
+
+  for (const locale of AVAILABLE_LOCALES) {
+    test(`${locale} messages parse and match the English arguments`, () => {
+      const catalog = MESSAGE_CATALOGS[locale]!;
+      for (const [key, message] of Object.entries(catalog)) {
+        expect(key in en).toBe(true);
+        const expected = [...getMessageArguments(en[key as keyof typeof en])].sort();
+        expect({ key, args: [...getMessageArguments(message!)].sort() }).toEqual({ key, args: expected });
+      }
+    });
+
+    test(`${locale} translates every English message`, () => {
+      const catalog = MESSAGE_CATALOGS[locale]!;
+      const missing = Object.keys(en).filter((key) => catalog[key as keyof typeof en] === undefined);
+      expect(missing).toEqual([]);
+    });
+  }
+});
+
+describe('extraction', () => {
+  test('flags UI text but not code strings', () => {
+    const source = [
+      "import { t } from '../i18n/index.ts';",
+      "const title = 'Settings';",
+      "const hint = `Press ${key} to continue`;",
+      "if (event.key === 'Enter') {}",
+      "const id = 'dialog-settings';",
+      "debugLog('[Dialog] Failed to load settings');",
+      "const icon = 'Aa'; // i18n-ignore",
+      "const re = /'Not a string'/;",
+      "// 'Commented out'",
+      "const label = t('settings.title');",
+    ].join('\n');
+
+    const found = findHardcodedStrings(source, 'x.ts').map((item) => `${item.line}:${item.text}`);
+    expect(found).toEqual(['2:Settings', '3:Press {} to continue']);
+    expect(findMessageKeys(source)).toEqual(['settings.title']);
+  });
+
+  test('no new hardcoded strings in localized sources', () => {
+    const baseline = JSON.parse(fs.readFileSync(BASELINE_PATH, 'utf-8')) as HardcodedBaseline;
+    const added = diffAgainstBaseline(scanHardcodedStrings('.'), baseline).map(
+      (item) => `${item.file}:${item.line} ${JSON.stringify(item.text)}`
+    );
+    expect(added).toEqual([]);
+  });
+
+  test('every message key used in the TUI exists', () => {
+    const missing: string[] = [];
+    for (const file of listTypeScriptFiles('src/clients/tui')) {
+      if (file.includes('/i18n/')) continue;
+      for (const key of findMessageKeys(fs.readFileSync(file, 'utf-8'))) {
+        if (!(key in en)) missing.push(`${file}: ${key}`);
+      }
+    }
+    expect(missing).toEqual([]);
+  });
+});
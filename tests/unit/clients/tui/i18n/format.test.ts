@@ -0,0 +1,68 @@
+/**
+ * Message Format Tests
+ */
+
+import { describe, test, expect } from 'bun:test';
+import {
+  formatMessage,
+  parseMessage,
+  getMessageArguments,
+  MessageFormatError,
+} from '../../../../../src/clients/tui/i18n/format.ts';
+
+describe('formatMessage', () => {
+  test('substitutes arguments as is', () => {
+    expect(formatMessage('Ln {line}, Col {column}', { line: 1234, column: 5 })).toBe('Ln 1234, Col 5');
+  });
+
+  test('leaves missing arguments visible', () => {
+    expect(formatMessage('Save {filename}?')).toBe('Save {filename}?');
+  });
+
+  test('formats numbers for the locale', () => {
+    expect(formatMessage('{n, number}', { n: 1234.5 }, 'en')).toBe('1,234.5');
+    expect(formatMessage('{n, number}', { n: 1234.5 }, 'de')).toBe('1.234,5');
+  });
+
+  test('selects plural branches by locale rules', () => {
+    const message = '{count, plural, =0 {no files} one {# file} other {# files}}';
+    expect(formatMessage(message, { count: 0 })).toBe('no files');
+    expect(formatMessage(message, { count: 1 })).toBe('1 file');
+    expect(formatMessage(message, { count: 2000 })).toBe('2,000 files');
+
+    // Japanese has no singular form
+    expect(formatMessage('{count, plural, one {one} other {# 行}}', { count: 1 }, 'ja')).toBe('1 行');
+  });
+
+  test('supports plural offsets', () => {
+    const message = '{count, plural, offset:1 =0 {nobody} =1 {{name}} other {{name} and # others}}';
+    expect(formatMessage(message, { count: 1, name: 'Ana' })).toBe('Ana');
+    expect(formatMessage(message, { count: 3, name: 'Ana' })).toBe('Ana and 2 others');
+  });
+
+  test('selects by value and nests plurals', () => {
+    const message = '{kind, select, folder {{count, plural, one {# folder} other {# folders}}} other {{count} items}}';
+    expect(formatMessage(message, { kind: 'folder', count: 1 })).toBe('1 folder');
+    expect(formatMessage(message, { kind: 'file', count: 3 })).toBe('3 items');
+  });
+
+  test('handles apostrophe quoting', () => {
+    expect(formatMessage("Don't save")).toBe("Don't save");
+    expect(formatMessage("It''s '{literal}' and '#'", {})).toBe("It's {literal} and #");
+    expect(formatMessage("{n, plural, other {'#' #}}", { n: 3 })).toBe('# 3');
+  });
+});
+
+describe('parseMessage', () => {
+  test('rejects malformed messages', () => {
+    expect(() => parseMessage('{count, plural, one {# file}}')).toThrow(MessageFormatError);
+    expect(() => parseMessage('{count, date}')).toThrow(MessageFormatError);
+    expect(() => parseMessage('unbalanced }')).toThrow(MessageFormatError);
+    expect(() => parseMessage('{name')).toThrow(MessageFormatError);
+  });
+
+  test('lists arguments including nested ones', () => {
+    const args = getMessageArguments('{lines, plural, other {# lines}} in {file}, {kind, select, other {{size}}}');
+    expect([...args].sort()).toEqual(['file', 'kind', 'lines', 'size']);
+  });
+});
@@ -395,6 +395,61 @@ describe('PaneContainer', () => {
     });
   });
 
+  // ─────────────────────────────────────────────────────────────────────────
+  // Zoom
+  // ─────────────────────────────────────────────────────────────────────────
+
+  describe('zoom', () => {
+    test('zoomed pane is centered and alone', () => {
+      const root = container.ensureRoot();
+      const otherId = container.split('vertical');
+      const other = container.getPane(otherId)!;
+      const otherBounds = other.getBounds();
+
+      expect(container.setZoomedPane(root.id, 40)).toBe(true);
+      expect(root.getBounds()).toEqual({ x: 20, y: 0, width: 40, height: 24 });
+      expect(other.getBounds()).toEqual(otherBounds);
+      expect(container.findPaneAtPoint(otherBounds.x + 1, 5)?.id).toBe(root.id);
+      expect(container.findPaneAtPoint(5, 5)).toBeNull();
+    });
+
+    test('zoom follows resizes and ends with the original layout', () => {
+      const root = container.ensureRoot();
+      container.split('vertical');
+      const before = root.getBounds();
+
+      container.setZoomedPane(root.id);
+      container.setBounds({ x: 0, y: 0, width: 100, height: 30 });
+      expect(root.getBounds()).toEqual({ x: 0, y: 0, width: 100, height: 30 });
+
+      container.setBounds({ x: 0, y: 0, width: 80, height: 24 });
+      container.setZoomedPane(null);
+      expect(container.getZoomedPane()).toBeNull();
+      expect(root.getBounds()).toEqual(before);
+    });
+
+    test('restoreRatios reapplies serialized split ratios', () => {
+      const root = container.ensureRoot();
+      container.split('vertical');
+      const layout = container.serialize();
+      const splitId = (layout as { id: string }).id;
+
+      container.adjustRatios(splitId, [0.2, 0.8]);
+      container.restoreRatios(layout);
+      expect(container.serialize()).toEqual(layout);
+      expect(root.getBounds().width).toBe(39);
+    });
+
+    test('renders margins around a zoomed pane', () => {
+      const root = container.ensureRoot();
+      container.setZoomedPane(root.id, 40);
+      const buffer = createScreenBuffer({ width: 80, height: 24 });
+      buffer.set(0, 0, { char: 'x', fg: '#ffffff', bg: '#000000' });
+      container.render(buffer);
+      expect(buffer.get(0, 0)?.char).toBe(' ');
+    });
+  });
+
   // ─────────────────────────────────────────────────────────────────────────
   // Queries
   // ─────────────────────────────────────────────────────────────────────────
@@ -340,6 +340,125 @@ describe('Pane', () => {
     });
   });
 
+  // ─────────────────────────────────────────────────────────────────────────
+  // Preview, Pinned and Recent Tabs
+  // ─────────────────────────────────────────────────────────────────────────
+
+  describe('preview, pinned and recent tabs', () => {
+    const titles = () => pane.getElements().map((e) => e.getTitle());
+
+    test('keepElement ends preview', () => {
+      const id = pane.addElement('DocumentEditor', 'First');
+      pane.setPreviewElement(id);
+      expect(pane.getPreviewElementId()).toBe(id);
+
+      expect(pane.keepElement(id)).toBe(true);
+      expect(pane.getPreviewElementId()).toBeNull();
+      expect(pane.keepElement(id)).toBe(false);
+    });
+
+    test('removing the preview clears it', () => {
+      const id = pane.addElement('DocumentEditor', 'First');
+      pane.setPreviewElement(id);
+      pane.removeElement(id);
+      expect(pane.getPreviewElementId()).toBeNull();
+    });
+
+    test('double-clicking a tab keeps it', () => {
+      pane.setBounds({ x: 0, y: 0, width: 80, height: 24 });
+      const id = pane.addElement('DocumentEditor', 'First');
+      pane.setPreviewElement(id);
+
+      pane.handleMouse({ type: 'press', x: 2, y: 0, button: 'left' });
+      expect(pane.getPreviewElementId()).toBe(id);
+      pane.handleMouse({ type: 'press', x: 2, y: 0, button: 'left' });
+      expect(pane.getPreviewElementId()).toBeNull();
+    });
+
+    test('renders the preview title in italics', () => {
+      pane.setBounds({ x: 0, y: 0, width: 80, height: 24 });
+      const id = pane.addElement('DocumentEditor', 'First');
+      const buffer = createScreenBuffer({ width: 80, height: 24 });
+
+      pane.render(buffer);
+      expect(buffer.get(1, 0)?.italic).toBeFalsy();
+
+      pane.setPreviewElement(id);
+      pane.render(buffer);
+      expect(buffer.get(1, 0)?.char).toBe('F');
+      expect(buffer.get(1, 0)?.italic).toBe(true);
+    });
+
+    test('pinned tabs move left in pin order and are never previews', () => {
+      pane.addElement('DocumentEditor', 'A');
+      const b = pane.addElement('DocumentEditor', 'B');
+      const c = pane.addElement('DocumentEditor', 'C');
+      pane.setPreviewElement(c);
+
+      pane.pinElement(c);
+      pane.pinElement(b);
+      expect(titles()).toEqual(['C', 'B', 'A']);
+      expect(pane.getPreviewElementId()).toBeNull();
+      expect(pane.isElementPinned(c)).toBe(true);
+
+      pane.setPreviewElement(b);
+      expect(pane.getPreviewElementId()).toBeNull();
+
+      pane.unpinElement(c);
+      expect(titles()).toEqual(['B', 'C', 'A']);
+      expect(pane.isElementPinned(c)).toBe(false);
+    });
+
+    test('moving tabs keeps the active tab', () => {
+      pane.addElement('DocumentEditor', 'A');
+      pane.addElement('DocumentEditor', 'B');
+      const c = pane.addElement('DocumentEditor', 'C');
+
+      pane.moveElement(c, 0);
+      expect(titles()).toEqual(['C', 'A', 'B']);
+      expect(pane.getActiveElement()?.id).toBe(c);
+      expect(pane.getActiveElementIndex()).toBe(0);
+    });
+
+    test('clicking a pinned tab pin unpins it', () => {
+      pane.setBounds({ x: 0, y: 0, width: 80, height: 24 });
+      const id = pane.addElement('DocumentEditor', 'A');
+      pane.pinElement(id);
+
+      // " A 📌" - the pin is in the close button slot
+      pane.handleMouse({ type: 'press', x: 3, y: 0, button: 'left' });
+      expect(pane.isElementPinned(id)).toBe(false);
+      expect(pane.getElementCount()).toBe(1);
+    });
+
+    test('getElementsByRecentUse orders by activation', () => {
+      const a = pane.addElement('DocumentEditor', 'A');
+      pane.addElement('DocumentEditor', 'B');
+      pane.addElement('DocumentEditor', 'C');
+
+      pane.setActiveElement(a);
+      expect(pane.getElementsByRecentUse().map((e) => e.getTitle())).toEqual(['A', 'C', 'B']);
+
+      pane.prevTab();
+      expect(pane.getElementsByRecentUse()[0]?.getTitle()).toBe('C');
+      expect(pane.getElementLastUsed(a)).toBeGreaterThan(0);
+    });
+
+    test('serialization keeps pinned and preview tabs', () => {
+      const a = pane.addElement('DocumentEditor', 'A');
+      const b = pane.addElement('DocumentEditor', 'B');
+      pane.pinElement(b);
+      pane.setPreviewElement(a);
+
+      const pane2 = new Pane('pane-2', callbacks);
+      pane2.deserialize(pane.serialize());
+
+      expect(pane2.isElementPinned(b)).toBe(true);
+      expect(pane2.getPreviewElementId()).toBe(a);
+      expect(pane2.getElements().map((e) => e.getTitle())).toEqual(['B', 'A']);
+    });
+  });
+
   // ─────────────────────────────────────────────────────────────────────────
   // Accordion Operations
   // ─────────────────────────────────────────────────────────────────────────
@@ -412,6 +531,16 @@ describe('Pane', () => {
       expect(content.height).toBe(23);
     });
 
+    test('hidden tab bar gives its row to content', () => {
+      pane.setBounds({ x: 0, y: 0, width: 80, height: 24 });
+      const id = pane.addElement('DocumentEditor');
+      pane.setTabBarHidden(true);
+
+      expect(pane.isTabBarHidden()).toBe(true);
+      expect(pane.getElement(id)?.getBounds()).toEqual({ x: 0, y: 0, width: 80, height: 24 });
+      expect(pane.handleMouse({ type: 'press', x: 5, y: 0, button: 'left' })).toBe(false);
+    });
+
     test('elements get content bounds in tabs mode', () => {
       pane.setBounds({ x: 0, y: 0, width: 80, height: 24 });
       const id = pane.addElement('DocumentEditor');
@@ -267,6 +267,95 @@ describe('AutocompletePopup', () => {
   });
 });
 
+describe('AutocompletePopup commit characters and documentation', () => {
+  let popup: AutocompletePopup;
+  let callbacks: ReturnType<typeof createTestCallbacks>;
+  const key = (k: string) => ({ key: k, ctrl: false, alt: false, shift: false, meta: false });
+
+  beforeEach(() => {
+    callbacks = createTestCallbacks();
+    callbacks.getScreenSize = () => ({ width: 120, height: 24 });
+    popup = createAutocompletePopup('autocomplete-test', callbacks);
+  });
+
+  test('commit character accepts the item and passes the key through', () => {
+    const accepted: string[] = [];
+    popup.onSelect((item) => accepted.push(item.label));
+    popup.showCompletions([{ label: 'fmt', kind: 9, commitCharacters: ['.'] }], 10, 5);
+
+    expect(popup.handleInput(key('.'))).toBe(false);
+    expect(accepted).toEqual(['fmt']);
+    expect(popup.isVisible()).toBe(false);
+  });
+
+  test('other characters do not accept', () => {
+    const accepted: string[] = [];
+    popup.onSelect((item) => accepted.push(item.label));
+    popup.showCompletions([{ label: 'fmt', kind: 9, commitCharacters: ['.'] }], 10, 5);
+
+    popup.handleInput(key('('));
+    expect(accepted).toEqual([]);
+    expect(popup.isVisible()).toBe(true);
+  });
+
+  test('filters on filterText', () => {
+    popup.showCompletions([{ label: 'Println(a ...any)', kind: 3, filterText: 'Println' }], 10, 5, 'prin');
+    expect(popup.getSelectedItem()?.label).toBe('Println(a ...any)');
+  });
+
+  test('resolves the selected item and replaces it', async () => {
+    const item: LSPCompletionItem = { label: 'Println', kind: 3 };
+    popup.setResolver(async (i) => ({ ...i, documentation: { kind: 'markdown', value: '**Prints** output' } }));
+    popup.showCompletions([item], 10, 5);
+
+    await new Promise((resolve) => setTimeout(resolve, 0));
+
+    const selected = popup.getSelectedItem()!;
+    expect(selected).not.toBe(item);
+    expect(popup.getDocLines(selected, 40).map((l) => l.text)).toEqual(['Prints output']);
+  });
+
+  test('doc lines put detail before documentation', () => {
+    const lines = popup.getDocLines({ label: 'x', detail: 'func x() int', documentation: 'Returns x' }, 40);
+    expect(lines).toEqual([
+      { text: 'func x() int', kind: 'code' },
+      { text: '', kind: 'text' },
+      { text: 'Returns x', kind: 'text' },
+    ]);
+  });
+
+  test('renders the documentation panel beside the list', () => {
+    popup.showCompletions([{ label: 'x', kind: 3, documentation: 'Docs here' }], 2, 2);
+    const buffer = createScreenBuffer({ width: 120, height: 24 });
+    popup.render(buffer);
+
+    const { x, width } = popup.getBounds();
+    let row = '';
+    for (let col = x + width; col < x + width + 20; col++) {
+      row += buffer.get(col, popup.getBounds().y + 1)?.char ?? '';
+    }
+    expect(row).toContain('Docs here');
+  });
+
+  test('no documentation panel when disabled', () => {
+    popup.setShowDocumentation(false);
+    popup.showCompletions([{ label: 'x', kind: 3, documentation: 'Docs here' }], 2, 2);
+    const buffer = createScreenBuffer({ width: 120, height: 24 });
+    popup.render(buffer);
+
+    const { x, width, y } = popup.getBounds();
+    expect(buffer.get(x + width, y)?.char).toBe(' ');
+  });
+});
+
+describe('AutocompletePopup accessibility', () => {
+  test('describes the selected item and its position', () => {
+    const popup = createAutocompletePopup('test', createTestCallbacks());
+    popup.showCompletions(createTestCompletions(), 10, 5);
+    expect(popup.getAccessibleText()).toBe('console, variable, Console object, 1 of 5');
+  });
+});
+
 describe('createAutocompletePopup', () => {
   test('returns AutocompletePopup instance', () => {
     const callbacks = createTestCallbacks();
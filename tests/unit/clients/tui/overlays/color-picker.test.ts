@@ -0,0 +1,100 @@
+/**
+ * ColorPicker Tests
+ */
+
+import { describe, test, expect, beforeEach } from 'bun:test';
+import { ColorPicker, createColorPicker } from '../../../../../src/clients/tui/overlays/color-picker.ts';
+import type { OverlayManagerCallbacks } from '../../../../../src/clients/tui/overlays/overlay-manager.ts';
+import type { LSPColor } from '../../../../../src/services/lsp/types.ts';
+import { createScreenBuffer } from '../../../../../src/clients/tui/rendering/buffer.ts';
+
+// ============================================
+// Test Setup
+// ============================================
+
+function createTestCallbacks(): OverlayManagerCallbacks {
+  return {
+    onDirty: () => {},
+    getThemeColor: (_key: string, fallback = '#ffffff') => fallback,
+    getScreenSize: () => ({ width: 80, height: 24 }),
+  };
+}
+
+const key = (name: string, shift = false) => ({ key: name, ctrl: false, alt: false, shift, meta: false });
+
+const RED: LSPColor = { red: 1, green: 0, blue: 0, alpha: 1 };
+
+// ============================================
+// Tests
+// ============================================
+
+describe('ColorPicker', () => {
+  let picker: ColorPicker;
+  let committed: Array<{ color: LSPColor; formatIndex: number }>;
+
+  beforeEach(() => {
+    picker = createColorPicker('color-test', createTestCallbacks());
+    committed = [];
+    picker.showPicker(RED, 10, 5, (color, formatIndex) => committed.push({ color, formatIndex }));
+  });
+
+  test('shows the initial colour', () => {
+    expect(picker.isVisible()).toBe(true);
+    expect(picker.getHex()).toBe('#ff0000');
+  });
+
+  test('arrow keys select and adjust channels', () => {
+    picker.handleInput(key('ArrowDown'));
+    expect(picker.getSelectedChannel()).toBe(1);
+
+    picker.handleInput(key('ArrowRight', true));
+    picker.handleInput(key('ArrowRight'));
+    expect(picker.getHex()).toBe('#ff1100');
+
+    picker.handleInput(key('ArrowUp'));
+    picker.handleInput(key('ArrowLeft'));
+    expect(picker.getHex()).toBe('#fe1100');
+  });
+
+  test('Enter commits the colour and chosen format', () => {
+    picker.setFormats(['#ff0000', 'rgb(255, 0, 0)']);
+    picker.handleInput(key('Tab'));
+    picker.handleInput(key('Enter'));
+
+    expect(picker.isVisible()).toBe(false);
+    expect(committed).toEqual([{ color: RED, formatIndex: 1 }]);
+  });
+
+  test('Escape cancels without committing', () => {
+    picker.handleInput(key('Escape'));
+    expect(picker.isVisible()).toBe(false);
+    expect(committed).toHaveLength(0);
+  });
+
+  test('clicking a channel bar sets its value', () => {
+    const { x, y } = picker.getBounds();
+    // Blue bar (third channel), leftmost cell
+    picker.handleInput({ type: 'press', button: 'left', x: x + 6, y: y + 4, ctrl: false, alt: false, shift: false });
+
+    expect(picker.getSelectedChannel()).toBe(2);
+    expect(picker.getHex()).toBe('#ff000d');
+  });
+
+  test('clicking outside closes the picker', () => {
+    picker.handleInput({ type: 'press', button: 'left', x: 0, y: 0, ctrl: false, alt: false, shift: false });
+    expect(picker.isVisible()).toBe(false);
+  });
+
+  test('renders the swatch in the colour', () => {
+    const buffer = createScreenBuffer({ width: 80, height: 24 });
+    picker.render(buffer);
+
+    const { x, y } = picker.getBounds();
+    expect(buffer.get(x + 2, y + 1)?.bg).toBe('#ff0000');
+  });
+
+  test('opens above the anchor near the bottom of the screen', () => {
+    picker.showPicker(RED, 10, 22, () => {});
+    expect(picker.getBounds().y + picker.getBounds().height).toBeLessThanOrEqual(22);
+  });
+});
@@ -0,0 +1,61 @@
+/**
+ * Markdown Lines Tests
+ */
+
+import { describe, test, expect } from 'bun:test';
+import {
+  renderMarkdownLines,
+  renderDocumentation,
+} from '../../../../../src/clients/tui/overlays/markdown-lines.ts';
+
+describe('renderMarkdownLines', () => {
+  test('renders fenced code without wrapping', () => {
+    const lines = renderMarkdownLines('```go\nfunc Println(a ...any) (n int, err error)\n```\nPrints.', 20);
+    expect(lines[0]).toEqual({ text: 'func Println(a ...a…', kind: 'code' });
+    expect(lines[1]).toEqual({ text: '', kind: 'text' });
+    expect(lines[2]).toEqual({ text: 'Prints.', kind: 'text' });
+  });
+
+  test('strips inline syntax but keeps snake_case', () => {
+    const lines = renderMarkdownLines('Use **bold** and `code` with [a link](http://x) in my_var_name', 80);
+    expect(lines[0]!.text).toBe('Use bold and code with a link in my_var_name');
+  });
+
+  test('renders headings, quotes, lists and rules', () => {
+    const lines = renderMarkdownLines('# Title\n> note\n- one\n2. two\n---', 40);
+    expect(lines.map((l) => [l.kind, l.text])).toEqual([
+      ['heading', 'Title'],
+      ['quote', '│ note'],
+      ['text', '• one'],
+      ['text', '2. two'],
+      ['text', ''],
+      ['rule', '─'.repeat(40)],
+    ]);
+  });
+
+  test('wraps long paragraphs with hanging indent for lists', () => {
+    const lines = renderMarkdownLines('- alpha beta gamma', 10);
+    expect(lines.map((l) => l.text)).toEqual(['• alpha', '  beta', '  gamma']);
+  });
+
+  test('collapses blank lines', () => {
+    const lines = renderMarkdownLines('a\n\n\n\nb\n\n', 10);
+    expect(lines.map((l) => l.text)).toEqual(['a', '', 'b']);
+  });
+});
+
+describe('renderDocumentation', () => {
+  test('plain text is wrapped as-is', () => {
+    const lines = renderDocumentation('Keep **this**', 80);
+    expect(lines).toEqual([{ text: 'Keep **this**', kind: 'text' }]);
+  });
+
+  test('markdown MarkupContent is rendered', () => {
+    const lines = renderDocumentation({ kind: 'markdown', value: '**Returns** a value' }, 80);
+    expect(lines[0]!.text).toBe('Returns a value');
+  });
+
+  test('missing documentation renders nothing', () => {
+    expect(renderDocumentation(undefined, 80)).toEqual([]);
+  });
+});
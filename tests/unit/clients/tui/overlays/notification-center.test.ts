@@ -0,0 +1,140 @@
+/**
+ * NotificationCenterDialog Tests
+ */
+
+import { describe, test, expect, beforeEach } from 'bun:test';
+import {
+  NotificationCenterDialog,
+  formatNotificationTime,
+} from '../../../../../src/clients/tui/overlays/notification-center.ts';
+import {
+  OverlayManager,
+  type OverlayManagerCallbacks,
+} from '../../../../../src/clients/tui/overlays/overlay-manager.ts';
+import { createScreenBuffer } from '../../../../../src/clients/tui/rendering/buffer.ts';
+
+// ============================================
+// Test Setup
+// ============================================
+
+function createTestCallbacks(): OverlayManagerCallbacks {
+  return {
+    onDirty: () => {},
+    getThemeColor: (_key: string, fallback = '#ffffff') => fallback,
+    getScreenSize: () => ({ width: 80, height: 24 }),
+  };
+}
+
+const key = (name: string) => ({ key: name, ctrl: false, alt: false, shift: false, meta: false });
+
+// ============================================
+// Tests
+// ============================================
+
+describe('formatNotificationTime', () => {
+  test('formats local time as HH:MM:SS', () => {
+    expect(formatNotificationTime(new Date(2024, 0, 2, 9, 5, 7).getTime())).toBe('09:05:07');
+  });
+});
+
+describe('NotificationCenterDialog', () => {
+  let manager: OverlayManager;
+  let center: NotificationCenterDialog;
+
+  beforeEach(() => {
+    const callbacks = createTestCallbacks();
+    manager = new OverlayManager(callbacks);
+    center = new NotificationCenterDialog('notification-center-test', callbacks, manager);
+    manager.addOverlay(center);
+  });
+
+  test('lists notifications newest first with timestamps', () => {
+    manager.showNotification('First');
+    manager.showNotification('Second');
+    center.showCenter();
+
+    const buffer = createScreenBuffer({ width: 80, height: 24 });
+    center.render(buffer);
+
+    let text = '';
+    for (let y = 0; y < 24; y++) {
+      for (let x = 0; x < 80; x++) text += buffer.get(x, y)?.char ?? ' ';
+      text += '\n';
+    }
+    expect(text.indexOf('Second')).toBeLessThan(text.indexOf('First'));
+    expect(text).toMatch(/\d\d:\d\d:\d\d/);
+    expect(center.getSelected()?.message).toBe('Second');
+  });
+
+  test('arrow keys move the selection', () => {
+    manager.showNotification('First');
+    manager.showNotification('Second');
+    center.showCenter();
+
+    center.handleInput(key('ArrowDown'));
+    expect(center.getSelected()?.message).toBe('First');
+    center.handleInput(key('ArrowDown'));
+    expect(center.getSelected()?.message).toBe('First');
+    center.handleInput(key('ArrowUp'));
+    expect(center.getSelected()?.message).toBe('Second');
+  });
+
+  test('digits run actions of the selected notification', () => {
+    const ran: string[] = [];
+    manager.notify('Push failed', 'error', {
+      actions: [
+        { label: 'Retry', run: () => ran.push('retry') },
+        { label: 'Show Log', run: () => ran.push('log') },
+      ],
+    });
+    center.showCenter();
+
+    center.handleInput(key('2'));
+
+    expect(ran).toEqual(['log']);
+    expect(manager.getNotifications()).toHaveLength(0);
+  });
+
+  test('x cancels progress and Delete dismisses', () => {
+    let cancelled = false;
+    const progress = manager.showProgress('Indexing...', { onCancel: () => { cancelled = true; } });
+    center.showCenter();
+    center.handleInput(key('x'));
+    expect(cancelled).toBe(true);
+
+    manager.notify('Sticky', 'info', { duration: 0 });
+    center.handleInput(key('Home'));
+    center.handleInput(key('Delete'));
+    expect(manager.getNotifications()).toHaveLength(0);
+    expect(manager.getNotificationHistory().find((n) => n.id === progress)?.closeReason).toBe('cancelled');
+  });
+
+  test('d chooses do not show again', () => {
+    const keys: string[] = [];
+    manager.onDoNotShowAgain((k) => keys.push(k));
+    manager.notify('Tip', 'info', { doNotShowAgainKey: 'tips' });
+    center.showCenter();
+
+    center.handleInput(key('d'));
+
+    expect(keys).toEqual(['tips']);
+  });
+
+  test('c clears closed notifications', () => {
+    const id = manager.showNotification('Old');
+    manager.removeNotification(id);
+    manager.notify('Open', 'info', { duration: 0 });
+    center.showCenter();
+
+    center.handleInput(key('c'));
+
+    expect(manager.getNotificationHistory().map((n) => n.message)).toEqual(['Open']);
+  });
+
+  test('Escape closes the center', async () => {
+    const result = center.showCenter();
+    center.handleInput(key('Escape'));
+    expect((await result).cancelled).toBe(true);
+    expect(center.isVisible()).toBe(false);
+  });
+});
@@ -7,6 +7,7 @@ import {
   OverlayManager,
   BaseDialog,
   createOverlayManager,
+  formatProgressBar,
   type OverlayManagerCallbacks,
   type Overlay,
 } from '../../../../../src/clients/tui/overlays/overlay-manager.ts';
@@ -273,6 +274,158 @@ describe('OverlayManager', () => {
     });
   });
 
+  describe('actionable notifications', () => {
+    function findText(buffer: ScreenBuffer, text: string): { x: number; y: number } | null {
+      for (let y = 0; y < 24; y++) {
+        let row = '';
+        for (let x = 0; x < 80; x++) row += buffer.get(x, y)?.char ?? ' ';
+        const x = row.indexOf(text);
+        if (x !== -1) return { x, y };
+      }
+      return null;
+    }
+
+    function click(x: number, y: number) {
+      return { type: 'press' as const, button: 'left' as const, x, y, ctrl: false, alt: false, shift: false };
+    }
+
+    test('notifications with actions or progress stay until closed', () => {
+      manager.notify('Push failed', 'error', { actions: [{ label: 'Retry', run: () => {} }] });
+      manager.showProgress('Indexing...');
+      expect(manager.getNotifications().map((n) => n.duration)).toEqual([0, 0]);
+    });
+
+    test('invokeNotificationAction closes and runs the action', () => {
+      const reasons: string[] = [];
+      let ran = false;
+      const id = manager.notify('Push failed', 'error', {
+        actions: [{ label: 'Retry', run: () => { ran = true; } }],
+        onClose: (reason) => reasons.push(reason),
+      })!;
+
+      expect(manager.invokeNotificationAction(id, 1)).toBe(false);
+      expect(manager.invokeNotificationAction(id, 0)).toBe(true);
+
+      expect(ran).toBe(true);
+      expect(reasons).toEqual(['action']);
+      expect(manager.getNotifications()).toHaveLength(0);
+    });
+
+    test('cancelNotification only cancels cancellable progress', () => {
+      let cancelled = false;
+      const fixed = manager.showProgress('Saving...');
+      const cancellable = manager.showProgress('Indexing...', { onCancel: () => { cancelled = true; } });
+
+      expect(manager.cancelNotification(fixed)).toBe(false);
+      expect(manager.cancelNotification(cancellable)).toBe(true);
+      expect(cancelled).toBe(true);
+    });
+
+    test('updateNotification changes progress', () => {
+      const id = manager.showProgress('Indexing...', { percent: 0 });
+      manager.updateNotification(id, { message: 'Indexing src/', percent: 150 });
+      const [notification] = manager.getNotifications();
+      expect(notification!.message).toBe('Indexing src/');
+      expect(notification!.progress!.percent).toBe(100);
+    });
+
+    test('trimming drops expiring toasts before sticky ones', () => {
+      const sticky = manager.notify('Sticky', 'info', { duration: 0 })!;
+      for (let i = 0; i < 10; i++) {
+        manager.showNotification(`Message ${i}`);
+      }
+      expect(manager.getNotifications().map((n) => n.id)).toContain(sticky);
+    });
+
+    test('renders buttons and runs them on click', () => {
+      let ran = false;
+      manager.notify('Push failed', 'error', { actions: [{ label: 'Retry', run: () => { ran = true; } }] });
+
+      const buffer = createScreenBuffer({ width: 80, height: 24 });
+      manager.render(buffer);
+      const button = findText(buffer, ' Retry ');
+      expect(button).not.toBeNull();
+
+      expect(manager.handleNotificationMouse(click(button!.x + 1, button!.y))).toBe(true);
+      expect(ran).toBe(true);
+    });
+
+    test('close button dismisses sticky toasts', () => {
+      const reasons: string[] = [];
+      manager.notify('Sticky', 'info', { duration: 0, onClose: (reason) => reasons.push(reason) });
+
+      const buffer = createScreenBuffer({ width: 80, height: 24 });
+      manager.render(buffer);
+      const close = findText(buffer, '×');
+      expect(close).not.toBeNull();
+
+      manager.handleNotificationMouse(click(close!.x, close!.y));
+      expect(reasons).toEqual(['dismissed']);
+    });
+
+    test('clicks outside toasts are not handled', () => {
+      manager.showNotification('Hello');
+      manager.render(createScreenBuffer({ width: 80, height: 24 }));
+      expect(manager.handleNotificationMouse(click(0, 20))).toBe(false);
+    });
+  });
+
+  describe('notification history', () => {
+    test('keeps closed notifications, newest first', () => {
+      const a = manager.showNotification('A');
+      const b = manager.showNotification('B');
+      manager.removeNotification(a);
+
+      const history = manager.getNotificationHistory();
+      expect(history.map((n) => n.id)).toEqual([b, a]);
+      expect(history[1]!.closeReason).toBe('dismissed');
+      expect(history[1]!.closedAt).toBeDefined();
+    });
+
+    test('clearNotificationHistory keeps active notifications', () => {
+      const a = manager.showNotification('A');
+      const b = manager.showNotification('B');
+      manager.removeNotification(a);
+      manager.clearNotificationHistory();
+
+      expect(manager.getNotificationHistory().map((n) => n.id)).toEqual([b]);
+    });
+
+    test('clearNotifications closes every active notification', () => {
+      const reasons: string[] = [];
+      manager.notify('A', 'info', { onClose: (reason) => reasons.push(reason) });
+      manager.notify('B', 'info', { onClose: (reason) => reasons.push(reason) });
+      manager.clearNotifications();
+      expect(reasons).toEqual(['dismissed', 'dismissed']);
+    });
+  });
+
+  describe('do not show again', () => {
+    test('suppressed keys are not shown', () => {
+      manager.setSuppressedNotificationKeys(['tips']);
+      expect(manager.notify('Tip', 'info', { doNotShowAgainKey: 'tips' })).toBeNull();
+      expect(manager.getNotifications()).toHaveLength(0);
+    });
+
+    test('doNotShowAgain suppresses the key, closes matching toasts and notifies', () => {
+      const keys: string[] = [];
+      manager.onDoNotShowAgain((key) => keys.push(key));
+      const a = manager.notify('Tip 1', 'info', { doNotShowAgainKey: 'tips' })!;
+      manager.notify('Tip 2', 'info', { doNotShowAgainKey: 'tips' });
+
+      expect(manager.doNotShowAgain(a)).toBe(true);
+
+      expect(keys).toEqual(['tips']);
+      expect(manager.getNotifications()).toHaveLength(0);
+      expect(manager.getSuppressedNotificationKeys()).toEqual(['tips']);
+    });
+
+    test('doNotShowAgain needs a key', () => {
+      const id = manager.showNotification('Hello');
+      expect(manager.doNotShowAgain(id)).toBe(false);
+    });
+  });
+
   // ─────────────────────────────────────────────────────────────────────────
   // Input Handling
   // ─────────────────────────────────────────────────────────────────────────
@@ -462,6 +615,17 @@ describe('BaseDialog', () => {
 // Factory Function Tests
 // ============================================
 
+describe('formatProgressBar', () => {
+  test('fills in proportion to the percentage', () => {
+    expect(formatProgressBar(50, 10)).toBe('█████░░░░░ 50%');
+    expect(formatProgressBar(150, 4)).toBe('████ 100%');
+  });
+
+  test('shows indeterminate progress', () => {
+    expect(formatProgressBar(undefined, 4)).toBe('░░░░ …');
+  });
+});
+
 describe('createOverlayManager', () => {
   test('creates overlay manager', () => {
     const callbacks = createTestCallbacks();
@@ -0,0 +1,31 @@
+/**
+ * TabSwitcherDialog Tests
+ */
+
+import { describe, test, expect } from 'bun:test';
+import {
+  orderTabsByRecentUse,
+  type TabInfo,
+} from '../../../../../src/clients/tui/overlays/tab-switcher.ts';
+
+function tab(id: string, lastUsed?: number, isActive = false): TabInfo {
+  return { id, title: id, paneId: 'pane-1', isActive, lastUsed };
+}
+
+describe('orderTabsByRecentUse', () => {
+  test('orders most recently used first', () => {
+    const ordered = orderTabsByRecentUse([tab('a', 1), tab('b', 3, true), tab('c', 2)]);
+    expect(ordered.map((t) => t.id)).toEqual(['b', 'c', 'a']);
+  });
+
+  test('keeps unused tabs in order at the end', () => {
+    const ordered = orderTabsByRecentUse([tab('a'), tab('b', 1), tab('c')]);
+    expect(ordered.map((t) => t.id)).toEqual(['b', 'a', 'c']);
+  });
+
+  test('does not modify the input', () => {
+    const tabs = [tab('a', 1), tab('b', 2)];
+    orderTabsByRecentUse(tabs);
+    expect(tabs.map((t) => t.id)).toEqual(['a', 'b']);
+  });
+});
@@ -0,0 +1,99 @@
+/**
+ * WhichKeyPopup Tests
+ */
+
+import { describe, test, expect } from 'bun:test';
+import {
+  WhichKeyPopup,
+  getWhichKeyEntries,
+  describeWhichKeyEntry,
+} from '../../../../../src/clients/tui/overlays/which-key.ts';
+import type { OverlayManagerCallbacks } from '../../../../../src/clients/tui/overlays/overlay-manager.ts';
+import { createScreenBuffer } from '../../../../../src/clients/tui/rendering/buffer.ts';
+
+// ============================================
+// Test Setup
+// ============================================
+
+function createTestCallbacks(): OverlayManagerCallbacks {
+  return {
+    onDirty: () => {},
+    getThemeColor: (_key: string, fallback = '#ffffff') => fallback,
+    getScreenSize: () => ({ width: 80, height: 24 }),
+  };
+}
+
+// ============================================
+// Tests
+// ============================================
+
+describe('getWhichKeyEntries', () => {
+  test('lists next keys sorted, with titles', () => {
+    const entries = getWhichKeyEntries('ctrl+k', [
+      { key: 'ctrl+k z', title: 'Toggle Zen Mode' },
+      { key: 'ctrl+k n', title: 'Show Notifications' },
+      { key: 'ctrl+s', title: 'Save' },
+    ]);
+
+    expect(entries).toEqual([
+      { key: 'n', title: 'Show Notifications' },
+      { key: 'z', title: 'Toggle Zen Mode' },
+    ]);
+  });
+
+  test('groups longer sequences', () => {
+    const entries = getWhichKeyEntries('ctrl+k', [
+      { key: 'ctrl+k g s', title: 'Status' },
+      { key: 'ctrl+k g p', title: 'Push' },
+      { key: 'ctrl+k g', title: 'Git' },
+    ]);
+
+    expect(entries).toEqual([{ key: 'g', title: 'Git', groupSize: 2 }]);
+    expect(describeWhichKeyEntry(entries[0]!)).toBe('+2 commands');
+  });
+
+  test('first binding for a key wins', () => {
+    const entries = getWhichKeyEntries('ctrl+k', [
+      { key: 'ctrl+k w', title: 'Close All' },
+      { key: 'ctrl+k w', title: 'Other' },
+    ]);
+
+    expect(entries).toEqual([{ key: 'w', title: 'Close All' }]);
+  });
+});
+
+describe('WhichKeyPopup', () => {
+  test('renders entries above the given row', () => {
+    const popup = new WhichKeyPopup('which-key-test', createTestCallbacks());
+    popup.showEntries('ctrl+k', [{ key: 'z', title: 'Toggle Zen Mode' }], 23);
+
+    const buffer = createScreenBuffer({ width: 80, height: 24 });
+    popup.render(buffer);
+
+    const bounds = popup.getBounds();
+    expect(bounds.y + bounds.height).toBe(23);
+
+    let row = '';
+    for (let x = 0; x < 80; x++) row += buffer.get(x, bounds.y + 1)?.char ?? ' ';
+    expect(row).toContain('z → Toggle Zen Mode');
+  });
+
+  test('stays hidden without entries and never consumes input', () => {
+    const popup = new WhichKeyPopup('which-key-test', createTestCallbacks());
+    popup.showEntries('ctrl+k', [], 23);
+    expect(popup.isVisible()).toBe(false);
+
+    popup.showEntries('ctrl+k', [{ key: 'z', title: 'Zen' }], 23);
+    expect(popup.handleInput({ key: 'z', ctrl: false, alt: false, shift: false, meta: false })).toBe(false);
+  });
+
+  test('dismissal notifies the owner', () => {
+    const popup = new WhichKeyPopup('which-key-test', createTestCallbacks());
+    let dismissed = false;
+    popup.onDismissed(() => (dismissed = true));
+
+    popup.onDismiss();
+
+    expect(dismissed).toBe(true);
+  });
+});
@@ -0,0 +1,91 @@
+/**
+ * GraphicsLayer Tests
+ *
+ * Inline images are only re-sent when they change (Kitty) or when the
+ * cells under them were repainted (sixel).
+ */
+
+import { describe, test, expect } from 'bun:test';
+import { GraphicsLayer, type GraphicsPlacement } from '../../../../../src/clients/tui/rendering/graphics-layer.ts';
+import { createScreenBuffer } from '../../../../../src/clients/tui/rendering/buffer.ts';
+
+function placement(signature: string, rect = { x: 2, y: 1, width: 4, height: 2 }): GraphicsPlacement {
+  return {
+    key: 'viewer',
+    rect,
+    signature,
+    getPixels: () => ({ width: 4, height: 4, data: new Uint8ClampedArray(64).fill(255) }),
+    background: { r: 0, g: 0, b: 0 },
+  };
+}
+
+describe('GraphicsLayer', () => {
+  test('kitty images are sent once and replaced on change', () => {
+    const layer = new GraphicsLayer('kitty');
+    const buffer = createScreenBuffer({ width: 10, height: 5 });
+
+    const first = layer.update([placement('a')], buffer);
+    expect(first).toContain('\x1b_Ga=T');
+    expect(first).toContain('i=1');
+    expect(layer.update([placement('a')], buffer)).toBe('');
+
+    const changed = layer.update([placement('b')], buffer);
+    expect(changed).toContain('i=1');
+    expect(changed).not.toContain('a=d');
+  });
+
+  test('kitty images are deleted when they go away', () => {
+    const layer = new GraphicsLayer('kitty');
+    const buffer = createScreenBuffer({ width: 10, height: 5 });
+    layer.update([placement('a')], buffer);
+    expect(layer.update([], buffer)).toBe('\x1b_Ga=d,d=I,i=1,q=2\x1b\\');
+  });
+
+  test('moving a kitty image deletes the old placement', () => {
+    const layer = new GraphicsLayer('kitty');
+    const buffer = createScreenBuffer({ width: 10, height: 5 });
+    layer.update([placement('a')], buffer);
+    const output = layer.update([placement('a', { x: 0, y: 0, width: 4, height: 2 })], buffer);
+    expect(output.indexOf('a=d')).toBeLessThan(output.indexOf('a=T'));
+  });
+
+  test('sixel images are redrawn when cells under them are repainted', () => {
+    const layer = new GraphicsLayer('sixel');
+    const buffer = createScreenBuffer({ width: 10, height: 5 });
+    buffer.clearDirty();
+
+    expect(layer.update([placement('a')], buffer)).toContain('\x1bP');
+    expect(layer.update([placement('a')], buffer)).toBe('');
+
+    buffer.set(3, 1, { char: 'x', fg: '#ffffff', bg: '#000000' });
+    expect(layer.update([placement('a')], buffer)).toContain('\x1bP');
+  });
+
+  test('removing a sixel image repaints its cells', () => {
+    const layer = new GraphicsLayer('sixel');
+    const buffer = createScreenBuffer({ width: 10, height: 5 });
+    buffer.clearDirty();
+    layer.update([placement('a')], buffer);
+
+    expect(layer.update([], buffer)).toBe('');
+    expect(buffer.isDirty(2, 1)).toBe(true);
+    expect(buffer.isDirty(5, 2)).toBe(true);
+    expect(buffer.isDirty(6, 1)).toBe(false);
+  });
+
+  test('draws at the placement and restores the cursor', () => {
+    const layer = new GraphicsLayer('kitty');
+    const buffer = createScreenBuffer({ width: 10, height: 5 });
+    const output = layer.update([placement('a')], buffer);
+    expect(output.startsWith('\x1b[s\x1b[2;3H')).toBe(true);
+    expect(output.endsWith('\x1b[u')).toBe(true);
+  });
+
+  test('switching protocols clears existing images', () => {
+    const layer = new GraphicsLayer('kitty');
+    const buffer = createScreenBuffer({ width: 10, height: 5 });
+    layer.update([placement('a')], buffer);
+    expect(layer.setProtocol('halfblock', buffer)).toContain('a=d');
+    expect(layer.update([placement('a')], buffer)).toBe('');
+  });
+});
@@ -308,6 +308,21 @@ describe('StatusBar', () => {
       expect(foundBranch).toBe(true);
     });
 
+    test('speech line replaces items in the collapsed bar', () => {
+      statusBar.setItemContent('branch', 'main');
+      statusBar.setSpeechLine('app.ts, editor');
+      const buffer = createScreenBuffer({ width: 80, height: 24 });
+
+      statusBar.render(buffer);
+
+      let line = '';
+      for (let x = 0; x < 80; x++) {
+        line += buffer.get(x, 23)?.char ?? '';
+      }
+      expect(line).toContain('app.ts, editor');
+      expect(line).not.toContain('main');
+    });
+
     test('renders expanded view', () => {
       statusBar.setBounds({ x: 0, y: 15, width: 80, height: 10 });
       statusBar.addHistory('Test message');
@@ -164,6 +164,15 @@ describe('Window', () => {
       expect(statusBar.getItem('test')?.content).toBe('Updated');
     });
 
+    test('hidden status bar gives its row to panes', () => {
+      expect(window.getPaneContainer().getBounds().height).toBe(23);
+      window.setStatusBarVisible(false);
+      expect(window.isStatusBarVisible()).toBe(false);
+      expect(window.getPaneContainer().getBounds().height).toBe(24);
+      window.setStatusBarVisible(true);
+      expect(window.getPaneContainer().getBounds().height).toBe(23);
+    });
+
     test('addStatusHistory adds entry', () => {
       window.addStatusHistory('Test message', 'info');
       expect(window.getStatusBar().getHistoryCount()).toBe(1);
@@ -295,6 +304,86 @@ describe('Window', () => {
     });
   });
 
+  // ─────────────────────────────────────────────────────────────────────────
+  // Chords
+  // ─────────────────────────────────────────────────────────────────────────
+
+  describe('chords', () => {
+    const press = (key: string, ctrl = false) =>
+      window.handleInput({ key, ctrl, alt: false, shift: false, meta: false });
+    const wait = (ms: number) => new Promise((resolve) => setTimeout(resolve, ms));
+
+    test('runs chords longer than two keys', () => {
+      let called = false;
+      window.addKeybinding({ key: 'ctrl+k g s', handler: () => (called = true) });
+
+      press('k', true);
+      press('g');
+      expect(window.getPendingChord()).toBe('ctrl+k g');
+      press('s');
+
+      expect(called).toBe(true);
+      expect(window.isChordPending()).toBe(false);
+    });
+
+    test('Escape cancels a pending chord', () => {
+      let called = false;
+      window.addKeybinding({ key: 'ctrl+k x', handler: () => (called = true) });
+
+      press('k', true);
+      expect(press('Escape')).toBe(true);
+      press('x');
+
+      expect(window.isChordPending()).toBe(false);
+      expect(called).toBe(false);
+    });
+
+    test('leader stands for the leader key', () => {
+      let called = false;
+      window.addKeybinding({ key: 'leader g', handler: () => (called = true) });
+
+      press(' ');
+      press('g');
+      expect(called).toBe(false);
+
+      window.setLeaderKey('space');
+      press(' ');
+      press('g');
+      expect(called).toBe(true);
+    });
+
+    test('which-key popup lists active continuations after its delay', async () => {
+      window.setWhichKeyOptions({ enabled: true, delay: 0 });
+      window.addKeybinding({ key: 'ctrl+k z', handler: () => true, title: 'Toggle Zen Mode' });
+      window.addKeybinding({ key: 'ctrl+k d', handler: () => true, title: 'Diff', when: () => false });
+
+      press('k', true);
+      await wait(5);
+
+      const popup = window.getWhichKeyPopup();
+      expect(popup.isVisible()).toBe(true);
+      expect(popup.getEntries()).toEqual([{ key: 'z', title: 'Toggle Zen Mode' }]);
+
+      // Escape dismisses the popup and cancels the chord
+      press('Escape');
+      expect(popup.isVisible()).toBe(false);
+      expect(window.isChordPending()).toBe(false);
+    });
+
+    test('pending chord expires with which-key enabled', async () => {
+      window.setWhichKeyOptions({ enabled: true, delay: 0, timeout: 10 });
+      window.addKeybinding({ key: 'ctrl+k z', handler: () => true, title: 'Toggle Zen Mode' });
+
+      press('k', true);
+      await wait(5);
+      expect(window.getWhichKeyPopup().isVisible()).toBe(true);
+
+      await wait(20);
+      expect(window.isChordPending()).toBe(false);
+      expect(window.getWhichKeyPopup().isVisible()).toBe(false);
+    });
+  });
+
   // ─────────────────────────────────────────────────────────────────────────
   // Rendering
   // ─────────────────────────────────────────────────────────────────────────
@@ -0,0 +1,63 @@
+/**
+ * Auto-pair Tests
+ */
+
+import { describe, test, expect } from 'bun:test';
+import {
+  getAutoCloseTag,
+  getSurroundPair,
+  findSurroundingPair,
+  deleteSurroundingPair,
+  changeSurroundingPair,
+} from '../../../src/core/auto-pair.ts';
+
+// ============================================
+// Tests
+// ============================================
+
+describe('getAutoCloseTag', () => {
+  test('closes the tag just opened', () => {
+    expect(getAutoCloseTag('<div class="a">', '', {})).toBe('</div>');
+    expect(getAutoCloseTag('return <Foo.Bar x={1}>', '', { jsx: true })).toBe('</Foo.Bar>');
+    expect(getAutoCloseTag('<>', '', { jsx: true })).toBe('</>');
+  });
+
+  test('skips self-closing, void and already closed tags', () => {
+    expect(getAutoCloseTag('<br/>', '', {})).toBeNull();
+    expect(getAutoCloseTag('<img src="a">', '', {})).toBeNull();
+    expect(getAutoCloseTag('<li>', '</li>', {})).toBeNull();
+  });
+
+  test('ignores comparisons and generics', () => {
+    expect(getAutoCloseTag('if (a>', '', { jsx: true })).toBeNull();
+    expect(getAutoCloseTag('const x: Array<string>', '', { jsx: true })).toBeNull();
+  });
+});
+
+describe('getSurroundPair', () => {
+  test('resolves brackets, aliases, quotes and tags', () => {
+    expect(getSurroundPair(')')).toEqual({ open: '(', close: ')' });
+    expect(getSurroundPair('B')).toEqual({ open: '{', close: '}' });
+    expect(getSurroundPair('*')).toEqual({ open: '*', close: '*' });
+    expect(getSurroundPair('<a href="#">')).toEqual({ open: '<a href="#">', close: '</a>' });
+    expect(getSurroundPair('em')).toEqual({ open: '<em>', close: '</em>' });
+  });
+});
+
+describe('surrounding pairs', () => {
+  test('finds the innermost enclosing bracket', () => {
+    const text = 'f(a, [b, c])';
+    expect(findSurroundingPair(text, 7, '(')).toEqual({ openStart: 1, openEnd: 2, closeStart: 11, closeEnd: 12 });
+    expect(findSurroundingPair(text, 7, '[')).toEqual({ openStart: 5, openEnd: 6, closeStart: 10, closeEnd: 11 });
+    expect(findSurroundingPair(text, 0, '(')).toBeNull();
+  });
+
+  test('deletes and changes pairs', () => {
+    const apply = (text: string, changes: { start: number; end: number; text: string }[] | null) =>
+      [...changes!].reverse().reduce((t, c) => t.slice(0, c.start) + c.text + t.slice(c.end), text);
+
+    expect(apply('say "hi"', deleteSurroundingPair('say "hi"', 6, '"'))).toBe('say hi');
+    expect(apply('say "hi"', changeSurroundingPair('say "hi"', 6, '"', "'"))).toBe("say 'hi'");
+    expect(apply('<b><i>x</i></b>', changeSurroundingPair('<b><i>x</i></b>', 6, 't', 'em'))).toBe('<b><em>x</em></b>');
+  });
+});
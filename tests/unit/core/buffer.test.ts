@@ -0,0 +1,188 @@
+/**
+ * Buffer (piece tree) Tests
+ */
+
+import { describe, test, expect } from 'bun:test';
+import { Buffer, transformPosition, transformRange, type BufferSnapshot } from '../../../src/core/buffer.ts';
+
+// ============================================
+// Helpers
+// ============================================
+
+/**
+ * Assert every read API agrees with a plain string model.
+ */
+function expectMatches(buffer: Buffer | BufferSnapshot, text: string): void {
+  expect(buffer.getContent()).toBe(text);
+  expect(buffer.length).toBe(text.length);
+
+  const lines = text.split('\n');
+  expect(buffer.lineCount).toBe(lines.length);
+
+  let offset = 0;
+  lines.forEach((line, i) => {
+    expect(buffer.getLine(i)).toBe(line);
+    expect(buffer.positionToOffset({ line: i, column: 0 })).toBe(offset);
+    if (offset < text.length) {
+      expect(buffer.offsetToPosition(offset)).toEqual({ line: i, column: 0 });
+    }
+    offset += line.length + 1;
+  });
+}
+
+/** Small deterministic PRNG so failures are reproducible */
+function createRandom(seed: number): () => number {
+  let state = seed;
+  return () => {
+    state = (state * 1103515245 + 12345) & 0x7fffffff;
+    return state / 0x7fffffff;
+  };
+}
+
+// ============================================
+// Tests
+// ============================================
+
+describe('Buffer', () => {
+  test('reads lines and converts positions', () => {
+    const buffer = new Buffer('line 1\nline 2\nline 3');
+
+    expect(buffer.lineCount).toBe(3);
+    expect(buffer.getLine(1)).toBe('line 2');
+    expect(buffer.getLineLength(2)).toBe(6);
+    expect(buffer.positionToOffset({ line: 1, column: 2 })).toBe(9);
+    expect(buffer.offsetToPosition(9)).toEqual({ line: 1, column: 2 });
+    expect(buffer.offsetToPosition(100)).toEqual({ line: 2, column: 6 });
+  });
+
+  test('handles empty content and trailing newlines', () => {
+    const buffer = new Buffer('');
+    expect(buffer.lineCount).toBe(1);
+    expect(buffer.getLine(0)).toBe('');
+
+    buffer.insert(0, 'a\n');
+    expectMatches(buffer, 'a\n');
+    expect(buffer.offsetToPosition(2)).toEqual({ line: 1, column: 0 });
+  });
+
+  test('inserts and deletes across pieces', () => {
+    const buffer = new Buffer('Hello World');
+    buffer.insertAt({ line: 0, column: 5 }, ' Beautiful');
+    expect(buffer.getContent()).toBe('Hello Beautiful World');
+
+    buffer.insert(5, '\nnew line\n');
+    expectMatches(buffer, 'Hello\nnew line\n Beautiful World');
+
+    expect(buffer.delete(3, 12)).toBe('lo\nnew li');
+    expectMatches(buffer, 'Helne\n Beautiful World');
+  });
+
+  test('version increments on every modification', () => {
+    const buffer = new Buffer('abc');
+    const version = buffer.version;
+
+    buffer.insert(1, '');
+    expect(buffer.version).toBe(version);
+
+    buffer.insert(1, 'x');
+    buffer.delete(0, 1);
+    expect(buffer.version).toBe(version + 2);
+  });
+
+  test('stays consistent with a string model over many random edits', () => {
+    const random = createRandom(42);
+    const alphabet = 'ab\nc d\n';
+    const randomText = (n: number): string =>
+      Array.from({ length: n }, () => alphabet[Math.floor(random() * alphabet.length)]).join('');
+
+    let text = randomText(50);
+    const buffer = new Buffer(text);
+
+    for (let i = 0; i < 500; i++) {
+      const start = Math.floor(random() * (text.length + 1));
+      if (random() < 0.6) {
+        const inserted = randomText(1 + Math.floor(random() * 5));
+        buffer.insert(start, inserted);
+        text = text.slice(0, start) + inserted + text.slice(start);
+      } else {
+        const end = Math.min(text.length, start + Math.floor(random() * 6));
+        expect(buffer.delete(start, end)).toBe(text.slice(start, end));
+        text = text.slice(0, start) + text.slice(end);
+      }
+    }
+
+    expectMatches(buffer, text);
+  });
+
+  describe('snapshots', () => {
+    test('snapshot is unaffected by later edits', () => {
+      const buffer = new Buffer('one\ntwo');
+      const snapshot = buffer.snapshot();
+
+      buffer.insert(3, '\nthree');
+      buffer.delete(0, 2);
+
+      expect(snapshot.version).toBeLessThan(buffer.version);
+      expectMatches(snapshot, 'one\ntwo');
+      expect(snapshot.getRangeByPosition({ line: 0, column: 1 }, { line: 1, column: 2 })).toBe('ne\ntw');
+      expectMatches(buffer, 'e\nthree\ntwo');
+    });
+
+    test('getSnapshot/restoreSnapshot round-trips', () => {
+      const buffer = new Buffer('Hello');
+      const before = buffer.getSnapshot();
+      const live = buffer.snapshot();
+
+      buffer.insert(5, ' World\n!');
+      buffer.restoreSnapshot(before);
+
+      expectMatches(buffer, 'Hello');
+      expectMatches(live, 'Hello');
+    });
+
+    test('clone is independent of the original', () => {
+      const buffer = new Buffer('a\nb');
+      const copy = buffer.clone();
+
+      copy.insert(3, '\nc');
+      buffer.insert(0, 'z');
+
+      expectMatches(copy, 'a\nb\nc');
+      expectMatches(buffer, 'za\nb');
+    });
+  });
+
+  describe('change history', () => {
+    test('remaps positions computed on a snapshot', () => {
+      const buffer = new Buffer('let a = 1;\nlet b = 2;');
+      const snapshot = buffer.snapshot();
+
+      buffer.insert(0, '// header\n');
+      buffer.replace(14, 15, 'alpha');
+
+      const changes = buffer.getChangesSince(snapshot.version)!;
+      expect(changes).toHaveLength(3);
+
+      // "b" on the old second line
+      const b = transformPosition({ line: 1, column: 4 }, changes);
+      expect(buffer.getLine(b.line).charAt(b.column)).toBe('b');
+
+      // A replaced identifier collapses to an empty range after the new text
+      const range = transformRange({ start: { line: 0, column: 4 }, end: { line: 0, column: 5 } }, changes);
+      expect(range).toEqual({ start: { line: 1, column: 9 }, end: { line: 1, column: 9 } });
+      expect(transformPosition({ line: 0, column: 4 }, changes, 'left')).toEqual({ line: 1, column: 4 });
+    });
+
+    test('has no history across reset', () => {
+      const buffer = new Buffer('one');
+      const version = buffer.version;
+
+      buffer.reset('two\nlines');
+
+      expect(buffer.version).toBeGreaterThan(version);
+      expect(buffer.getChangesSince(version)).toBeNull();
+      expect(buffer.getChangesSince(buffer.version)).toEqual([]);
+      expectMatches(buffer, 'two\nlines');
+    });
+  });
+});
@@ -0,0 +1,60 @@
+/**
+ * Dependency Graph Export Tests
+ */
+
+import { describe, test, expect } from 'bun:test';
+import {
+  addDependency,
+  addDependencyNode,
+  createDependencyGraph,
+  toDot,
+  toMermaid,
+} from '../../../../src/core/deps/index.ts';
+
+function sampleGraph() {
+  const graph = createDependencyGraph('typescript');
+  addDependencyNode(graph, { id: 'a.ts', label: 'a.ts' });
+  addDependencyNode(graph, { id: 'b.ts', label: 'b "quoted".ts' });
+  addDependencyNode(graph, { id: 'c.ts', label: 'c.ts' });
+  addDependencyNode(graph, { id: 'react', label: 'react', external: true });
+  addDependency(graph, 'a.ts', 'b.ts');
+  addDependency(graph, 'b.ts', 'a.ts');
+  addDependency(graph, 'c.ts', 'a.ts');
+  addDependency(graph, 'a.ts', 'react');
+  return graph;
+}
+
+const violations = [{ from: 'c.ts', to: 'a.ts', fromLayer: 'low', toLayer: 'high' }];
+
+describe('toDot', () => {
+  test('renders nodes, escaped labels and highlighted edges', () => {
+    const dot = toDot(sampleGraph(), { violations });
+    expect(dot).toContain('"b.ts" [label="b \\"quoted\\".ts"];');
+    expect(dot).toContain('"react" [label="react", style=dashed, color=gray];');
+    expect(dot).toContain('"a.ts" -> "b.ts" [color=red];');
+    expect(dot).toContain('"c.ts" -> "a.ts" [style=dashed, color=orange];');
+    expect(dot).toContain('"a.ts" -> "react";');
+    expect(dot.startsWith('digraph dependencies {')).toBe(true);
+  });
+});
+
+describe('toMermaid', () => {
+  test('renders a flowchart with link styles', () => {
+    expect(toMermaid(sampleGraph(), { violations })).toBe(
+      [
+        'flowchart LR',
+        '  n0["a.ts"]',
+        '  n1["b #quot;quoted#quot;.ts"]',
+        '  n2["c.ts"]',
+        '  n3(["react"])',
+        '  n0 --> n1',
+        '  n0 --> n3',
+        '  n1 --> n0',
+        '  n2 -.-> n0',
+        '  linkStyle 0 stroke:red',
+        '  linkStyle 2 stroke:red',
+        '  linkStyle 3 stroke:orange',
+      ].join('\n') + '\n'
+    );
+  });
+});
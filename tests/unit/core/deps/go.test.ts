@@ -0,0 +1,52 @@
+/**
+ * Go Package Graph Tests
+ */
+
+import { describe, test, expect } from 'bun:test';
+import { parseGoList, splitJsonStream } from '../../../../src/core/deps/index.ts';
+
+const OUTPUT = `{
+	"Dir": "/work/app",
+	"ImportPath": "example.com/app",
+	"Module": {"Path": "example.com/app"},
+	"Imports": ["example.com/app/internal/api", "fmt"]
+}
+{
+	"Dir": "/work/app/internal/api",
+	"ImportPath": "example.com/app/internal/api",
+	"Doc": "Package api serves {json} \\"requests\\".",
+	"Module": {"Path": "example.com/app"},
+	"Imports": ["C", "example.com/app/internal/store", "github.com/go-chi/chi/v5"]
+}
+{
+	"Dir": "/work/app/internal/store",
+	"ImportPath": "example.com/app/internal/store",
+	"Module": {"Path": "example.com/app"}
+}
+`;
+
+describe('splitJsonStream', () => {
+  test('splits concatenated objects, ignoring braces in strings', () => {
+    expect(splitJsonStream(OUTPUT)).toHaveLength(3);
+  });
+});
+
+describe('parseGoList', () => {
+  test('builds workspace packages with module-relative labels', () => {
+    const graph = parseGoList(OUTPUT, '/work/app');
+    expect(graph.kind).toBe('go');
+    expect([...graph.nodes.values()].map((n) => [n.label, n.path])).toEqual([
+      ['example.com/app', '.'],
+      ['internal/api', 'internal/api'],
+      ['internal/store', 'internal/store'],
+    ]);
+    expect([...graph.imports.get('example.com/app/internal/api')!]).toEqual(['example.com/app/internal/store']);
+  });
+
+  test('adds external imports when asked', () => {
+    const graph = parseGoList(OUTPUT, '/work/app', { includeExternal: true });
+    expect(graph.nodes.get('fmt')).toEqual({ id: 'fmt', label: 'fmt', external: true });
+    expect(graph.nodes.has('github.com/go-chi/chi/v5')).toBe(true);
+    expect(graph.nodes.has('C')).toBe(false);
+  });
+});
@@ -0,0 +1,90 @@
+/**
+ * Dependency Graph Tests
+ *
+ * Reverse dependencies, cycle detection and layer checks.
+ */
+
+import { describe, test, expect } from 'bun:test';
+import {
+  addDependency,
+  addDependencyNode,
+  checkLayers,
+  createDependencyGraph,
+  edgeKey,
+  findCycles,
+  getCycleEdges,
+  reverseDependencies,
+  type DependencyGraph,
+} from '../../../../src/core/deps/index.ts';
+
+function graphOf(edges: Record<string, string[]>): DependencyGraph {
+  const graph = createDependencyGraph('go');
+  for (const id of new Set([...Object.keys(edges), ...Object.values(edges).flat()])) {
+    addDependencyNode(graph, { id, label: id });
+  }
+  for (const [from, targets] of Object.entries(edges)) {
+    for (const to of targets) addDependency(graph, from, to);
+  }
+  return graph;
+}
+
+describe('reverseDependencies', () => {
+  test('lists importers of each node', () => {
+    const reverse = reverseDependencies(graphOf({ a: ['b', 'c'], b: ['c'] }));
+    expect([...reverse.get('c')!].sort()).toEqual(['a', 'b']);
+    expect([...reverse.get('a')!]).toEqual([]);
+  });
+});
+
+describe('findCycles', () => {
+  test('finds strongly connected components and self-imports', () => {
+    const graph = graphOf({ a: ['b'], b: ['c'], c: ['a', 'd'], d: ['e'], e: ['e'], f: ['a'] });
+    expect(findCycles(graph)).toEqual([['a', 'b', 'c'], ['e']]);
+  });
+
+  test('returns no cycles for a DAG', () => {
+    expect(findCycles(graphOf({ a: ['b', 'c'], b: ['c'] }))).toEqual([]);
+  });
+
+  test('handles deep chains without recursion', () => {
+    const edges: Record<string, string[]> = {};
+    for (let i = 0; i < 20000; i++) edges[`n${i}`] = [`n${i + 1}`];
+    edges['n20000'] = ['n0'];
+    const cycles = findCycles(graphOf(edges));
+    expect(cycles).toHaveLength(1);
+    expect(cycles[0]).toHaveLength(20001);
+  });
+
+  test('marks only edges inside a cycle', () => {
+    const graph = graphOf({ a: ['b'], b: ['a', 'c'] });
+    const edges = getCycleEdges(graph, findCycles(graph));
+    expect(edges).toEqual(new Set([edgeKey('a', 'b'), edgeKey('b', 'a')]));
+  });
+});
+
+describe('checkLayers', () => {
+  const layers = [
+    { name: 'cmd', include: ['cmd/**'] },
+    { name: 'api', include: ['internal/api', 'internal/api/**'] },
+    { name: 'store', include: ['internal/store/**'] },
+  ];
+
+  test('reports imports of higher layers', () => {
+    const graph = graphOf({
+      'cmd/server': ['internal/api'],
+      'internal/api': ['internal/store/sql'],
+      'internal/store/sql': ['internal/api', 'internal/util'],
+      'internal/util': ['cmd/server'],
+    });
+    expect(checkLayers(graph, layers)).toEqual([
+      { from: 'internal/store/sql', to: 'internal/api', fromLayer: 'store', toLayer: 'api' },
+    ]);
+  });
+
+  test('ignores external nodes and empty layer lists', () => {
+    const graph = graphOf({ 'internal/store/x': ['cmd/tool'] });
+    graph.nodes.get('cmd/tool')!.external = true;
+    expect(checkLayers(graph, layers)).toEqual([]);
+    expect(checkLayers(graphOf({ a: ['b'] }), [])).toEqual([]);
+  });
+});
@@ -0,0 +1,87 @@
+/**
+ * TypeScript Module Graph Tests
+ */
+
+import { describe, test, expect } from 'bun:test';
+import {
+  buildTypeScriptGraph,
+  packageName,
+  parseImportSpecifiers,
+  resolveImport,
+} from '../../../../src/core/deps/index.ts';
+
+describe('parseImportSpecifiers', () => {
+  test('finds static, side-effect, re-export and dynamic imports', () => {
+    const source = [
+      "import { a, type B } from './a.ts';",
+      'import type { C } from "./c";',
+      "import './polyfill';",
+      "export * from './reexport.ts';",
+      'import {',
+      '  d,',
+      "} from '../d';",
+      "const e = await import('./lazy');",
+      "const f = require('lodash/fp');",
+    ].join('\n');
+    expect(parseImportSpecifiers(source)).toEqual([
+      './a.ts',
+      './c',
+      './polyfill',
+      './reexport.ts',
+      '../d',
+      './lazy',
+      'lodash/fp',
+    ]);
+  });
+
+  test('ignores comments, strings and unrelated from', () => {
+    const source = [
+      "// import x from './commented';",
+      "/* import './block'; */",
+      "const s = '// not a comment'; import y from './real';",
+      "export function f() { const from = 'x'; }",
+    ].join('\n');
+    expect(parseImportSpecifiers(source)).toEqual(['./real']);
+  });
+});
+
+describe('resolveImport', () => {
+  const files = new Set(['src/a.ts', 'src/b.tsx', 'src/lib/index.ts', 'src/util.ts']);
+
+  test('resolves extensions, emitted .js and index files', () => {
+    expect(resolveImport('src/a.ts', './b', files)).toBe('src/b.tsx');
+    expect(resolveImport('src/a.ts', './util.js', files)).toBe('src/util.ts');
+    expect(resolveImport('src/a.ts', './lib', files)).toBe('src/lib/index.ts');
+    expect(resolveImport('src/lib/index.ts', '../a.ts', files)).toBe('src/a.ts');
+  });
+
+  test('returns null for bare and missing specifiers', () => {
+    expect(resolveImport('src/a.ts', 'react', files)).toBeNull();
+    expect(resolveImport('src/a.ts', './missing', files)).toBeNull();
+  });
+
+  test('extracts package names', () => {
+    expect(packageName('@scope/pkg/sub')).toBe('@scope/pkg');
+    expect(packageName('lodash/fp')).toBe('lodash');
+    expect(packageName('node:fs')).toBe('node:fs');
+  });
+});
+
+describe('buildTypeScriptGraph', () => {
+  const sources = new Map([
+    ['src/a.ts', "import { b } from './b.ts';\nimport React from 'react';"],
+    ['src/b.ts', "import { a } from './a.ts';"],
+  ]);
+
+  test('links workspace modules', () => {
+    const graph = buildTypeScriptGraph(sources);
+    expect([...graph.nodes.keys()]).toEqual(['src/a.ts', 'src/b.ts']);
+    expect([...graph.imports.get('src/a.ts')!]).toEqual(['src/b.ts']);
+  });
+
+  test('adds packages as external nodes when asked', () => {
+    const graph = buildTypeScriptGraph(sources, { includeExternal: true });
+    expect(graph.nodes.get('react')?.external).toBe(true);
+    expect(graph.imports.get('src/a.ts')!.has('react')).toBe(true);
+  });
+});
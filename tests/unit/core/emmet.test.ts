@@ -0,0 +1,68 @@
+/**
+ * Emmet Tests
+ */
+
+import { describe, test, expect } from 'bun:test';
+import { getEmmetSyntax, extractAbbreviation, expandAbbreviation } from '../../../src/core/emmet.ts';
+
+// ============================================
+// Tests
+// ============================================
+
+describe('getEmmetSyntax', () => {
+  test('uses markup scope in HTML', () => {
+    const html = '<div>\n<style>a { }</style>\n<script>x</script>\n<!-- c -->';
+    expect(getEmmetSyntax('html', html, 5)).toBe('html');
+    expect(getEmmetSyntax('html', html, 2)).toBeNull();
+    expect(getEmmetSyntax('html', html, html.indexOf('{') + 1)).toBe('css');
+    expect(getEmmetSyntax('html', html, html.indexOf('x<'))).toBeNull();
+    expect(getEmmetSyntax('html', html, html.indexOf(' c'))).toBeNull();
+  });
+
+  test('uses JSX only in code', () => {
+    const tsx = 'const a = "ul"; // li\nreturn ';
+    expect(getEmmetSyntax('typescriptreact', tsx, tsx.length)).toBe('jsx');
+    expect(getEmmetSyntax('typescriptreact', tsx, 12)).toBeNull();
+    expect(getEmmetSyntax('typescriptreact', tsx, 20)).toBeNull();
+    expect(getEmmetSyntax('typescript', tsx, tsx.length)).toBeNull();
+  });
+
+  test('uses CSS inside rule blocks', () => {
+    const css = 'a {\n  m10\n}';
+    expect(getEmmetSyntax('css', css, 9)).toBe('css');
+    expect(getEmmetSyntax('css', css, 1)).toBeNull();
+  });
+});
+
+describe('extractAbbreviation', () => {
+  test('stops at text before the abbreviation', () => {
+    expect(extractAbbreviation('return ul>li', 'jsx')).toEqual({ abbreviation: 'ul>li', start: 7 });
+    expect(extractAbbreviation('<div>p.intro', 'html')).toEqual({ abbreviation: 'p.intro', start: 5 });
+    expect(extractAbbreviation('a[title="x y"]', 'html')).toEqual({ abbreviation: 'a[title="x y"]', start: 0 });
+  });
+});
+
+describe('expandAbbreviation', () => {
+  test('expands nesting, numbering and attributes', () => {
+    const result = expandAbbreviation('ul#nav>li.item$*2>a', 'html', '  ')!;
+    expect(result.text).toBe(
+      '<ul id="nav">\n  <li class="item1">\n    <a href=""></a>\n  </li>\n  <li class="item2">\n    <a href=""></a>\n  </li>\n</ul>'
+    );
+    expect(result.text.slice(0, result.cursor)).toBe('<ul id="nav">\n  <li class="item1">\n    <a href="');
+  });
+
+  test('uses JSX attribute names and self-closing voids', () => {
+    expect(expandAbbreviation('label.x+input', 'jsx')!.text).toBe('<label className="x" htmlFor=""></label>\n<input type="text" />');
+  });
+
+  test('expands CSS properties', () => {
+    expect(expandAbbreviation('m10-20', 'css')!.text).toBe('margin: 10px 20px;');
+    expect(expandAbbreviation('pos:a', 'css')!.text).toBe('position: absolute;');
+    expect(expandAbbreviation('c#f', 'css')!.text).toBe('color: #fff;');
+  });
+
+  test('rejects plain identifiers', () => {
+    expect(expandAbbreviation('Foo', 'jsx')).toBeNull();
+    expect(expandAbbreviation('console.log', 'jsx')).toBeNull();
+  });
+});
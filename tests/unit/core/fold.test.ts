@@ -0,0 +1,166 @@
+/**
+ * FoldManager Tests
+ */
+
+import { describe, test, expect, beforeEach } from 'bun:test';
+import { FoldManager } from '../../../src/core/fold.ts';
+
+const TS_SOURCE = [
+  "import { a } from './a.ts';",   // 0
+  'import {',                       // 1
+  '  b,',                           // 2
+  "} from './b.ts';",               // 3
+  '',                               // 4
+  '// #region Helpers',             // 5
+  '/**',                            // 6
+  ' * Does things.',                // 7
+  ' */',                            // 8
+  'function outer() {',             // 9
+  '  if (x) {',                     // 10
+  '    y();',                       // 11
+  '  }',                            // 12
+  '}',                              // 13
+  '// #endregion',                  // 14
+  '// one',                         // 15
+  '// two',                         // 16
+];
+
+// ============================================
+// Tests
+// ============================================
+
+describe('FoldManager', () => {
+  let folds: FoldManager;
+
+  beforeEach(() => {
+    folds = new FoldManager();
+    folds.computeRegions(TS_SOURCE, 'typescript');
+  });
+
+  describe('language regions', () => {
+    test('detects region markers, comments and imports', () => {
+      const kinds = folds.getRegions()
+        .filter(r => r.kind)
+        .map(r => [r.startLine, r.endLine, r.kind]);
+
+      expect(kinds).toEqual([
+        [0, 3, 'imports'],
+        [5, 14, 'region'],
+        [6, 8, 'comment'],
+        [15, 16, 'comment'],
+      ]);
+    });
+
+    test('supports HTML region markers', () => {
+      folds.computeRegions(['<!-- #region nav -->', '<a>', '<!-- #endregion -->'], 'html');
+      expect(folds.getRegions()).toEqual([
+        { startLine: 0, endLine: 2, indent: 0, isFolded: false, kind: 'region' },
+      ]);
+    });
+
+    test('groups Go build constraints', () => {
+      folds.computeRegions(['//go:build linux', '// +build linux', '', 'package main'], 'go');
+      const region = folds.getRegions().find(r => r.startLine === 0);
+      expect(region).toMatchObject({ endLine: 1, kind: 'region' });
+    });
+
+    test('ignores unmatched markers', () => {
+      folds.computeRegions(['// #endregion', 'a', '// #region'], 'typescript');
+      expect(folds.getRegions().some(r => r.kind === 'region')).toBe(false);
+    });
+  });
+
+  describe('fold commands', () => {
+    test('foldToLevel folds only the given nesting level', () => {
+      folds.foldToLevel(2);
+
+      expect(folds.isFolded(5)).toBe(false);
+      expect(folds.isFolded(6)).toBe(true);
+      expect(folds.isFolded(9)).toBe(true);
+      expect(folds.isFolded(10)).toBe(false);
+    });
+
+    test('foldToLevel keeps regions containing excluded lines open', () => {
+      folds.foldToLevel(1, [11]);
+
+      expect(folds.isFolded(0)).toBe(true);
+      expect(folds.isFolded(5)).toBe(false);
+      expect(folds.isHidden(11)).toBe(false);
+    });
+
+    test('foldKind folds comments and imports', () => {
+      expect(folds.foldKind('comment')).toBe(2);
+      expect(folds.getFoldedLines()).toEqual([6, 15]);
+
+      folds.foldKind('imports');
+      expect(folds.isHidden(3)).toBe(true);
+    });
+
+    test('foldAllExcept unfolds regions containing the lines', () => {
+      folds.foldAllExcept([11]);
+
+      expect(folds.isFolded(0)).toBe(true);
+      expect(folds.isFolded(6)).toBe(true);
+      expect(folds.isHidden(11)).toBe(false);
+    });
+  });
+
+  describe('manual regions', () => {
+    test('survive recomputation until removed', () => {
+      expect(folds.addManualRegion(15, 16)).toBe(true);
+      expect(folds.getRegions().find(r => r.startLine === 15)?.kind).toBe('manual');
+
+      folds.computeRegions(TS_SOURCE, 'typescript');
+      expect(folds.canFold(15)).toBe(true);
+
+      expect(folds.addManualRegion(11, 12)).toBe(true);
+      expect(folds.removeManualRegions([11])).toBe(1);
+      expect(folds.canFold(11)).toBe(false);
+      expect(folds.isHidden(12)).toBe(false);
+    });
+
+    test('move with lines inserted or removed above them', () => {
+      folds.addManualRegion(15, 16);
+
+      folds.computeRegions(['// one', '// two', ...TS_SOURCE], 'typescript');
+      expect(folds.getRegionAt(17)).toMatchObject({ endLine: 18, kind: 'manual' });
+      expect(folds.getRegionAt(15)?.kind).not.toBe('manual');
+
+      folds.computeRegions(TS_SOURCE.slice(1), 'typescript');
+      expect(folds.getRegionAt(14)).toMatchObject({ endLine: 15, kind: 'manual' });
+    });
+
+    test('grow with lines inserted inside them', () => {
+      folds.addManualRegion(15, 16);
+
+      folds.computeRegions([...TS_SOURCE.slice(0, 16), '  // new', ...TS_SOURCE.slice(16)], 'typescript');
+      expect(folds.getRegionAt(15)).toMatchObject({ endLine: 17, kind: 'manual' });
+    });
+
+    test('rejects single-line regions', () => {
+      expect(folds.addManualRegion(3, 3)).toBe(false);
+    });
+  });
+
+  describe('fold state', () => {
+    test('round-trips folded and manual regions', () => {
+      folds.fold(9);
+      folds.addManualRegion(15, 16);
+      const state = folds.getFoldState();
+      expect(state).toEqual([9, { startLine: 15, endLine: 16 }]);
+
+      const restored = new FoldManager();
+      restored.computeRegions(TS_SOURCE, 'typescript');
+      restored.restoreFoldState(state);
+
+      expect(restored.isFolded(9)).toBe(true);
+      expect(restored.isHidden(16)).toBe(true);
+      expect(restored.getFoldState()).toEqual(state);
+    });
+
+    test('accepts plain line numbers', () => {
+      folds.restoreFoldState([0, 5]);
+      expect(folds.getFoldedLines()).toEqual([0, 5]);
+    });
+  });
+});
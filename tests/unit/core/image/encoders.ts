@@ -0,0 +1,719 @@
+/**
+ * Test Image Encoders
+ *
+ * Minimal PNG, GIF and JPEG encoders used to build decoder fixtures with
+ * known pixels. They favour clarity over compression.
+ */
+
+import { deflateSync } from 'zlib';
+
+// ============================================
+// PNG
+// ============================================
+
+const CRC_TABLE = (() => {
+  const table = new Uint32Array(256);
+  for (let n = 0; n < 256; n++) {
+    let c = n;
+    for (let k = 0; k < 8; k++) c = c & 1 ? 0xedb88320 ^ (c >>> 1) : c >>> 1;
+    table[n] = c >>> 0;
+  }
+  return table;
+})();
+
+function crc32(bytes: Uint8Array): number {
+  let c = 0xffffffff;
+  for (const byte of bytes) c = CRC_TABLE[(c ^ byte) & 0xff]! ^ (c >>> 8);
+  return (c ^ 0xffffffff) >>> 0;
+}
+
+function chunk(type: string, data: Uint8Array): Uint8Array {
+  const out = new Uint8Array(12 + data.length);
+  const view = new DataView(out.buffer);
+  view.setUint32(0, data.length);
+  for (let i = 0; i < 4; i++) out[4 + i] = type.charCodeAt(i);
+  out.set(data, 8);
+  view.setUint32(8 + data.length, crc32(out.subarray(4, 8 + data.length)));
+  return out;
+}
+
+function concat(parts: Uint8Array[]): Uint8Array {
+  const out = new Uint8Array(parts.reduce((sum, p) => sum + p.length, 0));
+  let offset = 0;
+  for (const part of parts) {
+    out.set(part, offset);
+    offset += part.length;
+  }
+  return out;
+}
+
+const PNG_CHANNELS: Record<number, number> = { 0: 1, 2: 3, 3: 1, 4: 2, 6: 4 };
+
+export interface PngOptions {
+  width: number;
+  height: number;
+  colorType: 0 | 2 | 3 | 4 | 6;
+  bitDepth: 1 | 2 | 4 | 8 | 16;
+  /** Samples per pixel, row-major (`channels` values each) */
+  samples: number[];
+  palette?: Array<[number, number, number]>;
+  /** Raw tRNS chunk contents */
+  transparency?: number[];
+  /** Filter type applied to every row (0-4) */
+  filter?: number;
+  interlace?: boolean;
+}
+
+/**
+ * Encode a PNG from raw samples.
+ */
+export function encodePng(options: PngOptions): Uint8Array {
+  const { width, height, colorType, bitDepth } = options;
+  const channels = PNG_CHANNELS[colorType]!;
+  const sampleAt = (x: number, y: number, c: number) => options.samples[(y * width + x) * channels + c]!;
+
+  const scanlines: Uint8Array[] = [];
+  const encodePass = (xs: number[], ys: number[]) => {
+    if (xs.length === 0 || ys.length === 0) return;
+    const rowBytes = Math.ceil((xs.length * channels * bitDepth) / 8);
+    const bpp = Math.max(1, Math.ceil((channels * bitDepth) / 8));
+    let previous = new Uint8Array(rowBytes);
+    for (const y of ys) {
+      const row = new Uint8Array(rowBytes);
+      let bit = 0;
+      for (const x of xs) {
+        for (let c = 0; c < channels; c++) {
+          const value = sampleAt(x, y, c);
+          if (bitDepth === 16) {
+            row[bit / 8] = value >> 8;
+            row[bit / 8 + 1] = value & 0xff;
+          } else if (bitDepth === 8) {
+            row[bit / 8] = value;
+          } else {
+            const index = bit >> 3;
+            row[index] = row[index]! | (value << (8 - bitDepth - (bit & 7)));
+          }
+          bit += bitDepth;
+        }
+      }
+      scanlines.push(filterRow(options.filter ?? 0, row, previous, bpp));
+      previous = row;
+    }
+  };
+
+  if (options.interlace) {
+    const passes = [
+      [0, 0, 8, 8],
+      [4, 0, 8, 8],
+      [0, 4, 4, 8],
+      [2, 0, 4, 4],
+      [0, 2, 2, 4],
+      [1, 0, 2, 2],
+      [0, 1, 1, 2],
+    ];
+    for (const [x0, y0, dx, dy] of passes) {
+      const xs: number[] = [];
+      const ys: number[] = [];
+      for (let x = x0!; x < width; x += dx!) xs.push(x);
+      for (let y = y0!; y < height; y += dy!) ys.push(y);
+      encodePass(xs, ys);
+    }
+  } else {
+    encodePass(
+      Array.from({ length: width }, (_, i) => i),
+      Array.from({ length: height }, (_, i) => i)
+    );
+  }
+
+  const header = new Uint8Array(13);
+  const view = new DataView(header.buffer);
+  view.setUint32(0, width);
+  view.setUint32(4, height);
+  header[8] = bitDepth;
+  header[9] = colorType;
+  header[12] = options.interlace ? 1 : 0;
+
+  const parts = [new Uint8Array([0x89, 0x50, 0x4e, 0x47, 0x0d, 0x0a, 0x1a, 0x0a]), chunk('IHDR', header)];
+  if (options.palette) parts.push(chunk('PLTE', new Uint8Array(options.palette.flat())));
+  if (options.transparency) parts.push(chunk('tRNS', new Uint8Array(options.transparency)));
+  parts.push(chunk('IDAT', deflateSync(concat(scanlines))));
+  parts.push(chunk('IEND', new Uint8Array(0)));
+  return concat(parts);
+}
+
+function filterRow(type: number, row: Uint8Array, previous: Uint8Array, bpp: number): Uint8Array {
+  const out = new Uint8Array(row.length + 1);
+  out[0] = type;
+  for (let i = 0; i < row.length; i++) {
+    const a = i >= bpp ? row[i - bpp]! : 0;
+    const b = previous[i]!;
+    const c = i >= bpp ? previous[i - bpp]! : 0;
+    let predictor = 0;
+    if (type === 1) predictor = a;
+    else if (type === 2) predictor = b;
+    else if (type === 3) predictor = (a + b) >> 1;
+    else if (type === 4) {
+      const p = a + b - c;
+      const pa = Math.abs(p - a);
+      const pb = Math.abs(p - b);
+      const pc = Math.abs(p - c);
+      predictor = pa <= pb && pa <= pc ? a : pb <= pc ? b : c;
+    }
+    out[i + 1] = (row[i]! - predictor) & 0xff;
+  }
+  return out;
+}
+
+// ============================================
+// GIF
+// ============================================
+
+export interface GifFrame {
+  left?: number;
+  top?: number;
+  width: number;
+  height: number;
+  indices: number[];
+  transparentIndex?: number;
+  interlace?: boolean;
+}
+
+/**
+ * Encode a GIF with a global color table.
+ */
+export function encodeGif(
+  width: number,
+  height: number,
+  palette: Array<[number, number, number]>,
+  frames: GifFrame[]
+): Uint8Array {
+  let depth = 1;
+  while (1 << depth < palette.length) depth++;
+  const bytes: number[] = [...'GIF89a'].map((c) => c.charCodeAt(0));
+  const u16 = (v: number) => bytes.push(v & 0xff, v >> 8);
+
+  u16(width);
+  u16(height);
+  bytes.push(0x80 | ((depth - 1) << 4) | (depth - 1), 0, 0);
+  for (let i = 0; i < 1 << depth; i++) bytes.push(...(palette[i] ?? [0, 0, 0]));
+
+  for (const frame of frames) {
+    if (frame.transparentIndex !== undefined) {
+      bytes.push(0x21, 0xf9, 4, 1, 0, 0, frame.transparentIndex, 0);
+    }
+    bytes.push(0x2c);
+    u16(frame.left ?? 0);
+    u16(frame.top ?? 0);
+    u16(frame.width);
+    u16(frame.height);
+    bytes.push(frame.interlace ? 0x40 : 0);
+
+    let indices = frame.indices;
+    if (frame.interlace) {
+      const order: number[] = [];
+      for (const [start, step] of [[0, 8], [4, 8], [2, 4], [1, 2]]) {
+        for (let y = start!; y < frame.height; y += step!) order.push(y);
+      }
+      indices = order.flatMap((y) => frame.indices.slice(y * frame.width, (y + 1) * frame.width));
+    }
+
+    const minCodeSize = Math.max(2, depth);
+    bytes.push(minCodeSize);
+    const data = encodeLzw(indices, minCodeSize);
+    for (let i = 0; i < data.length; i += 255) {
+      const block = data.subarray(i, i + 255);
+      bytes.push(block.length, ...block);
+    }
+    bytes.push(0);
+  }
+
+  bytes.push(0x3b);
+  return new Uint8Array(bytes);
+}
+
+/**
+ * GIF-flavoured LZW (LSB-first codes, clear and end codes).
+ */
+export function encodeLzw(indices: number[], minCodeSize: number): Uint8Array {
+  const clear = 1 << minCodeSize;
+  const end = clear + 1;
+  const out: number[] = [];
+  let buffer = 0;
+  let bits = 0;
+  let codeSize = minCodeSize + 1;
+  let next = end + 1;
+  let table = new Map<string, number>();
+
+  const write = (code: number) => {
+    buffer |= code << bits;
+    bits += codeSize;
+    while (bits >= 8) {
+      out.push(buffer & 0xff);
+      buffer >>>= 8;
+      bits -= 8;
+    }
+  };
+
+  write(clear);
+  let prefix = indices[0]!;
+  for (let i = 1; i < indices.length; i++) {
+    const k = indices[i]!;
+    const key = `${prefix},${k}`;
+    const code = table.get(key);
+    if (code !== undefined) {
+      prefix = code;
+      continue;
+    }
+    write(prefix);
+    if (next === 4096) {
+      write(clear);
+      table = new Map();
+      next = end + 1;
+      codeSize = minCodeSize + 1;
+    } else {
+      if (next >= 1 << codeSize) codeSize++;
+      table.set(key, next++);
+    }
+    prefix = k;
+  }
+  write(prefix);
+  write(end);
+  if (bits > 0) out.push(buffer & 0xff);
+  return new Uint8Array(out);
+}
+
+// ============================================
+// JPEG
+// ============================================
+
+const ZIGZAG = [
+  0, 1, 8, 16, 9, 2, 3, 10, 17, 24, 32, 25, 18, 11, 4, 5, 12, 19, 26, 33, 40, 48, 41, 34, 27, 20, 13, 6, 7, 14, 21,
+  28, 35, 42, 49, 56, 57, 50, 43, 36, 29, 22, 15, 23, 30, 37, 44, 51, 58, 59, 52, 45, 38, 31, 39, 46, 53, 60, 61,
+  54, 47, 55, 62, 63,
+];
+
+/** DC symbols 0-11, all with 4-bit codes */
+const DC_SYMBOLS = Array.from({ length: 12 }, (_, i) => i);
+
+/** Every AC run/size symbol plus the EOBn symbols, all with 8-bit codes */
+const AC_SYMBOLS = [
+  0x00,
+  0xf0,
+  ...Array.from({ length: 14 }, (_, i) => (i + 1) << 4),
+  ...Array.from({ length: 160 }, (_, i) => ((i / 10) << 4) | ((i % 10) + 1)),
+];
+
+export interface JpegOptions {
+  grayscale?: boolean;
+  /** 4:2:0 chroma subsampling (baseline only) */
+  subsample?: boolean;
+  progressive?: boolean;
+  /** MCUs between restart markers (baseline only) */
+  restartInterval?: number;
+}
+
+interface Component {
+  id: number;
+  h: number;
+  v: number;
+  blocksX: number;
+  blocksY: number;
+  /** Quantized coefficients per block, natural order */
+  blocks: Int32Array[];
+}
+
+/**
+ * Encode RGBA pixels as a JPEG with a quantization table of all ones, so
+ * decoding should reproduce the pixels up to rounding (and subsampling).
+ */
+export function encodeJpeg(data: Uint8ClampedArray | number[], width: number, height: number, options: JpegOptions = {}): Uint8Array {
+  const planes = toPlanes(data, width, height, !!options.grayscale);
+  const sampling = options.grayscale ? [[1, 1]] : options.subsample ? [[2, 2], [1, 1], [1, 1]] : [[1, 1], [1, 1], [1, 1]];
+  const hMax = Math.max(...sampling.map((s) => s[0]!));
+  const vMax = Math.max(...sampling.map((s) => s[1]!));
+  const mcusX = Math.ceil(width / (8 * hMax));
+  const mcusY = Math.ceil(height / (8 * vMax));
+
+  const components: Component[] = sampling.map(([h, v], c) => {
+    const planeWidth = Math.ceil((width * h!) / hMax);
+    const planeHeight = Math.ceil((height * v!) / vMax);
+    const plane = downsample(planes[c]!, width, height, hMax / h!, vMax / v!, planeWidth, planeHeight);
+    const blocksX = mcusX * h!;
+    const blocksY = mcusY * v!;
+    const blocks: Int32Array[] = [];
+    for (let by = 0; by < blocksY; by++) {
+      for (let bx = 0; bx < blocksX; bx++) {
+        blocks.push(forwardDct(plane, planeWidth, planeHeight, bx * 8, by * 8));
+      }
+    }
+    return { id: c + 1, h: h!, v: v!, blocksX, blocksY, blocks };
+  });
+
+  const out: number[] = [0xff, 0xd8];
+  const segment = (marker: number, payload: number[]) => {
+    out.push(0xff, marker, (payload.length + 2) >> 8, (payload.length + 2) & 0xff, ...payload);
+  };
+
+  segment(0xdb, [0, ...new Array(64).fill(1)]);
+  segment(options.progressive ? 0xc2 : 0xc0, [
+    8,
+    height >> 8,
+    height & 0xff,
+    width >> 8,
+    width & 0xff,
+    components.length,
+    ...components.flatMap((c) => [c.id, (c.h << 4) | c.v, 0]),
+  ]);
+  const dcCounts = new Array(16).fill(0);
+  dcCounts[3] = DC_SYMBOLS.length;
+  const acCounts = new Array(16).fill(0);
+  acCounts[7] = AC_SYMBOLS.length;
+  segment(0xc4, [0x00, ...dcCounts, ...DC_SYMBOLS, 0x10, ...acCounts, ...AC_SYMBOLS]);
+  if (options.restartInterval) {
+    segment(0xdd, [options.restartInterval >> 8, options.restartInterval & 0xff]);
+  }
+
+  const scan = (scanComponents: Component[], ss: number, se: number, ah: number, al: number, body: number[]) => {
+    segment(0xda, [
+      scanComponents.length,
+      ...scanComponents.flatMap((c) => [c.id, 0x00]),
+      ss,
+      se,
+      (ah << 4) | al,
+    ]);
+    out.push(...body);
+  };
+
+  if (!options.progressive) {
+    scan(components, 0, 63, 0, 0, encodeBaseline(components, mcusX, mcusY, options.restartInterval ?? 0));
+  } else {
+    scan(components, 0, 0, 0, 1, encodeDcScan(components, 1, false));
+    for (const component of components) {
+      scan([component], 1, 5, 0, 1, encodeAcFirst(component, 1, 5, 1));
+      scan([component], 6, 63, 0, 1, encodeAcFirst(component, 6, 63, 1));
+    }
+    scan(components, 0, 0, 1, 0, encodeDcScan(components, 0, true));
+    for (const component of components) {
+      scan([component], 1, 63, 1, 0, encodeAcRefine(component, 1, 63, 0));
+    }
+  }
+
+  out.push(0xff, 0xd9);
+  return new Uint8Array(out);
+}
+
+function toPlanes(data: ArrayLike<number>, width: number, height: number, grayscale: boolean): Float64Array[] {
+  const count = width * height;
+  const y = new Float64Array(count);
+  const cb = new Float64Array(count);
+  const cr = new Float64Array(count);
+  for (let p = 0; p < count; p++) {
+    const r = data[p * 4]!;
+    const g = data[p * 4 + 1]!;
+    const b = data[p * 4 + 2]!;
+    y[p] = 0.299 * r + 0.587 * g + 0.114 * b;
+    cb[p] = -0.168736 * r - 0.331264 * g + 0.5 * b + 128;
+    cr[p] = 0.5 * r - 0.418688 * g - 0.081312 * b + 128;
+  }
+  return grayscale ? [y] : [y, cb, cr];
+}
+
+function downsample(
+  plane: Float64Array,
+  width: number,
+  height: number,
+  factorX: number,
+  factorY: number,
+  outWidth: number,
+  outHeight: number
+): Float64Array {
+  if (factorX === 1 && factorY === 1) return plane;
+  const out = new Float64Array(outWidth * outHeight);
+  for (let y = 0; y < outHeight; y++) {
+    for (let x = 0; x < outWidth; x++) {
+      let sum = 0;
+      let count = 0;
+      for (let dy = 0; dy < factorY; dy++) {
+        for (let dx = 0; dx < factorX; dx++) {
+          const sx = Math.min(width - 1, x * factorX + dx);
+          const sy = Math.min(height - 1, y * factorY + dy);
+          sum += plane[sy * width + sx]!;
+          count++;
+        }
+      }
+      out[y * outWidth + x] = sum / count;
+    }
+  }
+  return out;
+}
+
+function forwardDct(plane: Float64Array, width: number, height: number, x0: number, y0: number): Int32Array {
+  const block = new Float64Array(64);
+  for (let y = 0; y < 8; y++) {
+    for (let x = 0; x < 8; x++) {
+      const sx = Math.min(width - 1, x0 + x);
+      const sy = Math.min(height - 1, y0 + y);
+      block[y * 8 + x] = plane[sy * width + sx]! - 128;
+    }
+  }
+  const out = new Int32Array(64);
+  for (let v = 0; v < 8; v++) {
+    for (let u = 0; u < 8; u++) {
+      let sum = 0;
+      for (let y = 0; y < 8; y++) {
+        for (let x = 0; x < 8; x++) {
+          sum +=
+            block[y * 8 + x]! * Math.cos(((2 * x + 1) * u * Math.PI) / 16) * Math.cos(((2 * y + 1) * v * Math.PI) / 16);
+        }
+      }
+      const cu = u === 0 ? Math.SQRT1_2 : 1;
+      const cv = v === 0 ? Math.SQRT1_2 : 1;
+      out[v * 8 + u] = Math.round((cu * cv * sum) / 4);
+    }
+  }
+  return out;
+}
+
+/** Number of bits needed for a magnitude */
+function category(value: number): number {
+  let abs = Math.abs(value);
+  let bits = 0;
+  while (abs > 0) {
+    bits++;
+    abs >>= 1;
+  }
+  return bits;
+}
+
+class BitWriter {
+  readonly bytes: number[] = [];
+  private buffer = 0;
+  private count = 0;
+
+  write(value: number, length: number): void {
+    for (let i = length - 1; i >= 0; i--) {
+      this.buffer = (this.buffer << 1) | ((value >> i) & 1);
+      this.count++;
+      if (this.count === 8) {
+        this.bytes.push(this.buffer);
+        if (this.buffer === 0xff) this.bytes.push(0);
+        this.buffer = 0;
+        this.count = 0;
+      }
+    }
+  }
+
+  /** Write a value's magnitude bits (negative values as value - 1) */
+  writeMagnitude(value: number, size: number): void {
+    if (size > 0) this.write(value < 0 ? value + (1 << size) - 1 : value, size);
+  }
+
+  dc(symbol: number): void {
+    this.write(DC_SYMBOLS.indexOf(symbol), 4);
+  }
+
+  ac(symbol: number): void {
+    this.write(AC_SYMBOLS.indexOf(symbol), 8);
+  }
+
+  /** Pad to a byte boundary with ones */
+  flush(): void {
+    if (this.count > 0) this.write(0xff, 8 - this.count);
+  }
+}
+
+function forEachMcuBlock(
+  components: Component[],
+  mcusX: number,
+  mcusY: number,
+  visit: (component: Component, block: Int32Array, mcu: number) => void
+): void {
+  for (let my = 0; my < mcusY; my++) {
+    for (let mx = 0; mx < mcusX; mx++) {
+      for (const component of components) {
+        for (let v = 0; v < component.v; v++) {
+          for (let h = 0; h < component.h; h++) {
+            const bx = mx * component.h + h;
+            const by = my * component.v + v;
+            visit(component, component.blocks[by * component.blocksX + bx]!, my * mcusX + mx);
+          }
+        }
+      }
+    }
+  }
+}
+
+function encodeBaseline(components: Component[], mcusX: number, mcusY: number, restartInterval: number): number[] {
+  const writer = new BitWriter();
+  const predictors = new Map<Component, number>();
+  let lastMcu = -1;
+  let restarts = 0;
+
+  forEachMcuBlock(components, mcusX, mcusY, (component, block, mcu) => {
+    if (mcu !== lastMcu) {
+      if (restartInterval && mcu > 0 && mcu % restartInterval === 0) {
+        writer.flush();
+        writer.bytes.push(0xff, 0xd0 + (restarts++ % 8));
+        predictors.clear();
+      }
+      lastMcu = mcu;
+    }
+
+    const diff = block[0]! - (predictors.get(component) ?? 0);
+    predictors.set(component, block[0]!);
+    writer.dc(category(diff));
+    writer.writeMagnitude(diff, category(diff));
+
+    let run = 0;
+    for (let k = 1; k < 64; k++) {
+      const value = block[ZIGZAG[k]!]!;
+      if (value === 0) {
+        run++;
+        continue;
+      }
+      while (run > 15) {
+        writer.ac(0xf0);
+        run -= 16;
+      }
+      const size = category(value);
+      writer.ac((run << 4) | size);
+      writer.writeMagnitude(value, size);
+      run = 0;
+    }
+    if (run > 0) writer.ac(0x00);
+  });
+
+  writer.flush();
+  return writer.bytes;
+}
+
+function encodeDcScan(components: Component[], al: number, refine: boolean): number[] {
+  const writer = new BitWriter();
+  const mcusX = components[0]!.blocksX / components[0]!.h;
+  const mcusY = components[0]!.blocksY / components[0]!.v;
+  const predictors = new Map<Component, number>();
+
+  forEachMcuBlock(components, mcusX, mcusY, (component, block) => {
+    const value = block[0]! >> al;
+    if (refine) {
+      writer.write(value & 1, 1);
+      return;
+    }
+    const diff = value - (predictors.get(component) ?? 0);
+    predictors.set(component, value);
+    writer.dc(category(diff));
+    writer.writeMagnitude(diff, category(diff));
+  });
+
+  writer.flush();
+  return writer.bytes;
+}
+
+function encodeAcFirst(component: Component, ss: number, se: number, al: number): number[] {
+  const writer = new BitWriter();
+  let eobrun = 0;
+  const emitEobrun = () => {
+    if (eobrun === 0) return;
+    const bits = category(eobrun) - 1;
+    writer.ac(bits << 4);
+    if (bits > 0) writer.write(eobrun & ((1 << bits) - 1), bits);
+    eobrun = 0;
+  };
+
+  for (const block of component.blocks) {
+    let run = 0;
+    for (let k = ss; k <= se; k++) {
+      const coefficient = block[ZIGZAG[k]!]!;
+      const value = coefficient < 0 ? -(-coefficient >> al) : coefficient >> al;
+      if (value === 0) {
+        run++;
+        continue;
+      }
+      emitEobrun();
+      while (run > 15) {
+        writer.ac(0xf0);
+        run -= 16;
+      }
+      const size = category(value);
+      writer.ac((run << 4) | size);
+      writer.writeMagnitude(value, size);
+      run = 0;
+    }
+    if (run > 0) {
+      eobrun++;
+      if (eobrun === 0x7fff) emitEobrun();
+    }
+  }
+
+  emitEobrun();
+  writer.flush();
+  return writer.bytes;
+}
+
+/**
+ * Successive-approximation AC refinement, following libjpeg's
+ * encode_mcu_AC_refine.
+ */
+function encodeAcRefine(component: Component, ss: number, se: number, al: number): number[] {
+  const writer = new BitWriter();
+  let eobrun = 0;
+  let pendingBits: number[] = [];
+
+  const emitEobrun = () => {
+    if (eobrun > 0) {
+      const bits = category(eobrun) - 1;
+      writer.ac(bits << 4);
+      if (bits > 0) writer.write(eobrun & ((1 << bits) - 1), bits);
+      eobrun = 0;
+    }
+    for (const bit of pendingBits) writer.write(bit, 1);
+    pendingBits = [];
+  };
+
+  for (const block of component.blocks) {
+    const absolute: number[] = [];
+    let eob = 0;
+    for (let k = ss; k <= se; k++) {
+      absolute[k] = Math.abs(block[ZIGZAG[k]!]!) >> al;
+      if (absolute[k] === 1) eob = k;
+    }
+
+    let run = 0;
+    let corrections: number[] = [];
+    for (let k = ss; k <= se; k++) {
+      const value = absolute[k]!;
+      if (value === 0) {
+        run++;
+        continue;
+      }
+      while (run > 15 && k <= eob) {
+        emitEobrun();
+        writer.ac(0xf0);
+        run -= 16;
+        for (const bit of corrections) writer.write(bit, 1);
+        corrections = [];
+      }
+      if (value > 1) {
+        // Previously nonzero: only a correction bit
+        corrections.push(value & 1);
+        continue;
+      }
+      emitEobrun();
+      writer.ac((run << 4) | 1);
+      writer.write(block[ZIGZAG[k]!]! < 0 ? 0 : 1, 1);
+      for (const bit of corrections) writer.write(bit, 1);
+      corrections = [];
+      run = 0;
+    }
+
+    if (run > 0 || corrections.length > 0) {
+      eobrun++;
+      pendingBits.push(...corrections);
+      if (eobrun === 0x7fff || pendingBits.length > 937) emitEobrun();
+    }
+  }
+
+  emitEobrun();
+  writer.flush();
+  return writer.bytes;
+}
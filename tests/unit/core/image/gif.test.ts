@@ -0,0 +1,85 @@
+/**
+ * GIF Decoder Tests
+ */
+
+import { describe, test, expect } from 'bun:test';
+import { decodeGif, decodeLzw, isGif } from '../../../../src/core/image/gif.ts';
+import { encodeGif, encodeLzw } from './encoders.ts';
+
+const PALETTE: Array<[number, number, number]> = [
+  [0, 0, 0],
+  [255, 0, 0],
+  [0, 255, 0],
+  [0, 0, 255],
+];
+
+function pixelAt(data: Uint8ClampedArray, width: number, x: number, y: number): number[] {
+  const i = (y * width + x) * 4;
+  return [...data.subarray(i, i + 4)];
+}
+
+describe('decodeLzw', () => {
+  test('round-trips long runs that grow the code size', () => {
+    const indices = Array.from({ length: 5000 }, (_, i) => (i * i + (i >> 3)) % 4);
+    expect([...decodeLzw(encodeLzw(indices, 2), 2, indices.length)]).toEqual(indices);
+  });
+
+  test('round-trips input that fills the code table', () => {
+    const indices = Array.from({ length: 60000 }, (_, i) => ((i * 2654435761) >>> 13) & 0xff);
+    expect([...decodeLzw(encodeLzw(indices, 8), 8, indices.length)]).toEqual(indices);
+  });
+
+  test('handles the KwKwK case', () => {
+    const indices = new Array(100).fill(1);
+    expect([...decodeLzw(encodeLzw(indices, 2), 2, indices.length)]).toEqual(indices);
+  });
+});
+
+describe('decodeGif', () => {
+  test('decodes the first frame', () => {
+    const indices = [0, 1, 2, 3, 3, 2, 1, 0];
+    const gif = encodeGif(4, 2, PALETTE, [{ width: 4, height: 2, indices }]);
+    expect(isGif(gif)).toBe(true);
+
+    const image = decodeGif(gif);
+    expect(image.width).toBe(4);
+    expect(image.height).toBe(2);
+    expect(pixelAt(image.data, 4, 1, 0)).toEqual([255, 0, 0, 255]);
+    expect(pixelAt(image.data, 4, 0, 1)).toEqual([0, 0, 255, 255]);
+    expect(image.metadata).toMatchObject({ format: 'gif', frameCount: 1, hasAlpha: false, interlaced: false });
+  });
+
+  test('applies the transparent index', () => {
+    const gif = encodeGif(2, 1, PALETTE, [{ width: 2, height: 1, indices: [0, 2], transparentIndex: 0 }]);
+    const image = decodeGif(gif);
+    expect(image.data[3]).toBe(0);
+    expect(pixelAt(image.data, 2, 1, 0)).toEqual([0, 255, 0, 255]);
+    expect(image.metadata.hasAlpha).toBe(true);
+  });
+
+  test('de-interlaces rows', () => {
+    const indices = Array.from({ length: 3 * 10 }, (_, i) => Math.floor(i / 3) % 4);
+    const image = decodeGif(encodeGif(3, 10, PALETTE, [{ width: 3, height: 10, indices, interlace: true }]));
+    for (let y = 0; y < 10; y++) {
+      expect(pixelAt(image.data, 3, 0, y).slice(0, 3)).toEqual(PALETTE[y % 4]!);
+    }
+    expect(image.metadata.interlaced).toBe(true);
+  });
+
+  test('places a frame smaller than the screen and counts frames', () => {
+    const gif = encodeGif(4, 4, PALETTE, [
+      { left: 1, top: 2, width: 2, height: 1, indices: [1, 3] },
+      { width: 4, height: 4, indices: new Array(16).fill(2) },
+    ]);
+    const image = decodeGif(gif);
+    expect(pixelAt(image.data, 4, 1, 2)).toEqual([255, 0, 0, 255]);
+    expect(pixelAt(image.data, 4, 2, 2)).toEqual([0, 0, 255, 255]);
+    expect(pixelAt(image.data, 4, 0, 0)[3]).toBe(0);
+    expect(image.metadata.frameCount).toBe(2);
+  });
+
+  test('rejects truncated files', () => {
+    const gif = encodeGif(2, 2, PALETTE, [{ width: 2, height: 2, indices: [0, 1, 2, 3] }]);
+    expect(() => decodeGif(gif.subarray(0, 20))).toThrow(/GIF/);
+  });
+});
@@ -0,0 +1,75 @@
+/**
+ * Image Module Tests
+ *
+ * Format detection, dispatch and resampling.
+ */
+
+import { describe, test, expect } from 'bun:test';
+import {
+  decodeImage,
+  detectImageFormat,
+  fitSize,
+  isImagePath,
+  resampleRegion,
+  scaleImage,
+  ImageDecodeError,
+} from '../../../../src/core/image/index.ts';
+import { encodeGif, encodePng } from './encoders.ts';
+
+function solid(width: number, height: number, rgba: number[]) {
+  const data = new Uint8ClampedArray(width * height * 4);
+  for (let i = 0; i < width * height; i++) data.set(rgba, i * 4);
+  return { width, height, data };
+}
+
+describe('format detection', () => {
+  test('recognizes image extensions', () => {
+    expect(isImagePath('/a/logo.PNG')).toBe(true);
+    expect(isImagePath('file:///a/photo.jpeg')).toBe(true);
+    expect(isImagePath('/a/icon.svg')).toBe(true);
+    expect(isImagePath('/a/notes.md')).toBe(false);
+  });
+
+  test('detects formats from content', () => {
+    expect(detectImageFormat(encodePng({ width: 1, height: 1, colorType: 0, bitDepth: 8, samples: [0] }))).toBe('png');
+    expect(detectImageFormat(encodeGif(1, 1, [[0, 0, 0]], [{ width: 1, height: 1, indices: [0] }]))).toBe('gif');
+    expect(detectImageFormat(new TextEncoder().encode('<svg/>'))).toBe('svg');
+    expect(detectImageFormat(new Uint8Array([1, 2, 3]))).toBeNull();
+  });
+
+  test('decodes by content regardless of extension', () => {
+    const png = encodePng({ width: 2, height: 1, colorType: 0, bitDepth: 8, samples: [0, 255] });
+    expect(decodeImage(png, 'misnamed.gif').metadata.format).toBe('png');
+    expect(() => decodeImage(new Uint8Array([1, 2, 3]), 'x.bin')).toThrow(ImageDecodeError);
+  });
+});
+
+describe('resampling', () => {
+  test('averages when downscaling, weighting by alpha', () => {
+    const image = solid(2, 1, [0, 0, 0, 0]);
+    image.data.set([200, 100, 50, 255], 0);
+    const scaled = scaleImage(image, 1, 1);
+    expect([...scaled.data]).toEqual([200, 100, 50, 128]);
+  });
+
+  test('repeats pixels when upscaling', () => {
+    const image = solid(2, 1, [0, 0, 0, 255]);
+    image.data.set([255, 255, 255, 255], 4);
+    const scaled = scaleImage(image, 4, 2);
+    expect(scaled.data[0]).toBe(0);
+    expect(scaled.data[(1 * 4 + 2) * 4]).toBe(255);
+  });
+
+  test('crops a region and leaves the outside transparent', () => {
+    const image = solid(4, 4, [9, 9, 9, 255]);
+    const region = resampleRegion(image, 2, 2, 6, 6, 4, 4);
+    expect(region.data[3]).toBe(255);
+    expect(region.data[(3 * 4 + 3) * 4 + 3]).toBe(0);
+  });
+
+  test('fits inside a box without enlarging by default', () => {
+    expect(fitSize(200, 100, 50, 50)).toEqual({ width: 50, height: 25, scale: 0.25 });
+    expect(fitSize(10, 10, 50, 50).scale).toBe(1);
+    expect(fitSize(10, 10, 50, 40, true)).toEqual({ width: 40, height: 40, scale: 4 });
+  });
+});
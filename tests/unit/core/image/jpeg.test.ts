@@ -0,0 +1,103 @@
+/**
+ * JPEG Decoder Tests
+ *
+ * Fixtures use a quantization table of all ones, so decoded pixels should
+ * match the source up to rounding.
+ */
+
+import { describe, test, expect } from 'bun:test';
+import { decodeJpeg, isJpeg } from '../../../../src/core/image/jpeg.ts';
+import { encodeJpeg } from './encoders.ts';
+
+/** Smooth RGBA test pattern */
+function pattern(width: number, height: number): Uint8ClampedArray {
+  const data = new Uint8ClampedArray(width * height * 4);
+  for (let y = 0; y < height; y++) {
+    for (let x = 0; x < width; x++) {
+      const i = (y * width + x) * 4;
+      data[i] = 40 + (x * 160) / width;
+      data[i + 1] = 200 - (y * 150) / height;
+      data[i + 2] = 90 + 60 * Math.sin((x + y) / 5);
+      data[i + 3] = 255;
+    }
+  }
+  return data;
+}
+
+function maxError(a: Uint8ClampedArray, b: Uint8ClampedArray): number {
+  let max = 0;
+  for (let i = 0; i < a.length; i++) max = Math.max(max, Math.abs(a[i]! - b[i]!));
+  return max;
+}
+
+function meanError(a: Uint8ClampedArray, b: Uint8ClampedArray): number {
+  let sum = 0;
+  for (let i = 0; i < a.length; i++) sum += Math.abs(a[i]! - b[i]!);
+  return sum / a.length;
+}
+
+describe('decodeJpeg', () => {
+  test('decodes baseline YCbCr', () => {
+    const source = pattern(19, 13);
+    const jpeg = encodeJpeg(source, 19, 13);
+    expect(isJpeg(jpeg)).toBe(true);
+
+    const image = decodeJpeg(jpeg);
+    expect(image.width).toBe(19);
+    expect(image.height).toBe(13);
+    expect(maxError(image.data, source)).toBeLessThanOrEqual(4);
+    expect(image.metadata).toMatchObject({ format: 'jpeg', colorType: 'YCbCr', progressive: false, hasAlpha: false });
+  });
+
+  test('decodes grayscale', () => {
+    const source = pattern(8, 8);
+    const image = decodeJpeg(encodeJpeg(source, 8, 8, { grayscale: true }));
+    const luma = 0.299 * source[0]! + 0.587 * source[1]! + 0.114 * source[2]!;
+    expect(Math.abs(image.data[0]! - luma)).toBeLessThanOrEqual(2);
+    expect(image.data[0]).toBe(image.data[1]!);
+    expect(image.metadata.colorType).toBe('Grayscale');
+  });
+
+  test('upsamples 4:2:0 chroma', () => {
+    const source = pattern(21, 17);
+    const image = decodeJpeg(encodeJpeg(source, 21, 17, { subsample: true }));
+    expect(meanError(image.data, source)).toBeLessThan(3);
+  });
+
+  test('resets predictions at restart markers', () => {
+    const source = pattern(40, 24);
+    const image = decodeJpeg(encodeJpeg(source, 40, 24, { restartInterval: 2 }));
+    expect(maxError(image.data, source)).toBeLessThanOrEqual(4);
+  });
+
+  test('decodes progressive scans with successive approximation', () => {
+    const source = pattern(27, 18);
+    const baseline = decodeJpeg(encodeJpeg(source, 27, 18));
+    const progressive = decodeJpeg(encodeJpeg(source, 27, 18, { progressive: true }));
+    expect(progressive.metadata.progressive).toBe(true);
+    expect([...progressive.data]).toEqual([...baseline.data]);
+  });
+
+  test('decodes progressive images with long zero runs', () => {
+    // Flat blocks produce end-of-band runs; the edge produces sparse refinements
+    const source = new Uint8ClampedArray(64 * 16 * 4);
+    for (let i = 0; i < 64 * 16; i++) {
+      const bright = i % 64 > 40;
+      source.set(bright ? [230, 220, 210, 255] : [20, 30, 40, 255], i * 4);
+    }
+    const baseline = decodeJpeg(encodeJpeg(source, 64, 16));
+    const progressive = decodeJpeg(encodeJpeg(source, 64, 16, { progressive: true }));
+    expect([...progressive.data]).toEqual([...baseline.data]);
+    expect(maxError(progressive.data, source)).toBeLessThanOrEqual(4);
+  });
+
+  test('rejects unsupported and malformed files', () => {
+    expect(() => decodeJpeg(new Uint8Array([0, 1, 2]))).toThrow('missing SOI');
+    const jpeg = encodeJpeg(pattern(8, 8), 8, 8);
+    // Lossless (SOF3) frames are not supported
+    const lossless = jpeg.slice();
+    const sof = lossless.findIndex((b, i) => b === 0xff && lossless[i + 1] === 0xc0);
+    lossless[sof + 1] = 0xc3;
+    expect(() => decodeJpeg(lossless)).toThrow('not supported');
+  });
+});
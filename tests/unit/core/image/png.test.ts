@@ -0,0 +1,120 @@
+/**
+ * PNG Decoder Tests
+ */
+
+import { describe, test, expect } from 'bun:test';
+import { decodePng, isPng } from '../../../../src/core/image/png.ts';
+import { ImageDecodeError } from '../../../../src/core/image/types.ts';
+import { encodePng } from './encoders.ts';
+
+/** A small RGBA gradient with varying alpha */
+function rgbaSamples(width: number, height: number): number[] {
+  const samples: number[] = [];
+  for (let y = 0; y < height; y++) {
+    for (let x = 0; x < width; x++) {
+      samples.push((x * 37) & 0xff, (y * 53) & 0xff, ((x + y) * 19) & 0xff, 255 - ((x * y * 7) & 0x7f));
+    }
+  }
+  return samples;
+}
+
+describe('decodePng', () => {
+  test('recognizes the signature', () => {
+    const png = encodePng({ width: 1, height: 1, colorType: 6, bitDepth: 8, samples: [1, 2, 3, 4] });
+    expect(isPng(png)).toBe(true);
+    expect(isPng(new Uint8Array([0xff, 0xd8, 0xff]))).toBe(false);
+  });
+
+  for (const filter of [0, 1, 2, 3, 4]) {
+    test(`decodes RGBA with filter ${filter}`, () => {
+      const samples = rgbaSamples(9, 5);
+      const image = decodePng(encodePng({ width: 9, height: 5, colorType: 6, bitDepth: 8, samples, filter }));
+      expect(image.width).toBe(9);
+      expect(image.height).toBe(5);
+      expect([...image.data]).toEqual(samples);
+      expect(image.metadata).toMatchObject({ format: 'png', colorType: 'RGBA', bitDepth: 8, hasAlpha: true });
+    });
+  }
+
+  test('expands low bit-depth grayscale', () => {
+    const image = decodePng(
+      encodePng({ width: 5, height: 1, colorType: 0, bitDepth: 2, samples: [0, 1, 2, 3, 1], filter: 1 })
+    );
+    expect([...image.data].filter((_, i) => i % 4 === 0)).toEqual([0, 85, 170, 255, 85]);
+    expect(image.metadata.hasAlpha).toBe(false);
+  });
+
+  test('decodes 1-bit grayscale across byte boundaries', () => {
+    const samples = [1, 0, 1, 1, 0, 0, 1, 0, 1, 1];
+    const image = decodePng(encodePng({ width: 10, height: 1, colorType: 0, bitDepth: 1, samples }));
+    expect([...image.data].filter((_, i) => i % 4 === 0)).toEqual(samples.map((s) => s * 255));
+  });
+
+  test('keeps the high byte of 16-bit samples', () => {
+    const image = decodePng(
+      encodePng({ width: 1, height: 2, colorType: 2, bitDepth: 16, samples: [0x1234, 0xabcd, 0xff00, 0, 1, 2], filter: 4 })
+    );
+    expect([...image.data]).toEqual([0x12, 0xab, 0xff, 255, 0, 0, 0, 255]);
+  });
+
+  test('decodes grayscale with alpha', () => {
+    const image = decodePng(encodePng({ width: 2, height: 1, colorType: 4, bitDepth: 8, samples: [10, 20, 200, 255] }));
+    expect([...image.data]).toEqual([10, 10, 10, 20, 200, 200, 200, 255]);
+  });
+
+  test('applies palette transparency', () => {
+    const image = decodePng(
+      encodePng({
+        width: 3,
+        height: 1,
+        colorType: 3,
+        bitDepth: 4,
+        samples: [0, 1, 2],
+        palette: [[255, 0, 0], [0, 255, 0], [0, 0, 255]],
+        transparency: [0, 128],
+      })
+    );
+    expect([...image.data]).toEqual([255, 0, 0, 0, 0, 255, 0, 128, 0, 0, 255, 255]);
+    expect(image.metadata).toMatchObject({ colorType: 'Indexed', hasAlpha: true });
+  });
+
+  test('applies a transparent color key', () => {
+    const image = decodePng(
+      encodePng({ width: 2, height: 1, colorType: 2, bitDepth: 8, samples: [1, 2, 3, 4, 5, 6], transparency: [0, 4, 0, 5, 0, 6] })
+    );
+    expect([...image.data]).toEqual([1, 2, 3, 255, 4, 5, 6, 0]);
+  });
+
+  test('decodes Adam7 interlaced images', () => {
+    const samples = rgbaSamples(11, 7);
+    const image = decodePng(encodePng({ width: 11, height: 7, colorType: 6, bitDepth: 8, samples, filter: 4, interlace: true }));
+    expect([...image.data]).toEqual(samples);
+    expect(image.metadata.interlaced).toBe(true);
+  });
+
+  test('decodes interlaced images smaller than a pass', () => {
+    const samples = [1, 0, 1];
+    const image = decodePng(encodePng({ width: 3, height: 1, colorType: 0, bitDepth: 1, samples, interlace: true }));
+    expect([...image.data].filter((_, i) => i % 4 === 0)).toEqual([255, 0, 255]);
+  });
+
+  test('rejects malformed files', () => {
+    expect(() => decodePng(new Uint8Array([1, 2, 3]))).toThrow(ImageDecodeError);
+    const png = encodePng({ width: 4, height: 4, colorType: 6, bitDepth: 8, samples: rgbaSamples(4, 4) });
+    expect(() => decodePng(png.subarray(0, 40))).toThrow(/PNG/);
+    expect(() =>
+      decodePng(encodePng({ width: 1, height: 1, colorType: 3, bitDepth: 8, samples: [0] }))
+    ).toThrow('without PLTE');
+  });
+
+  test('rejects image data larger than the dimensions', () => {
+    const png = encodePng({ width: 64, height: 64, colorType: 0, bitDepth: 8, samples: new Array(64 * 64).fill(0) });
+    // Shrink the IHDR to 1x1 (the decoder doesn't check CRCs)
+    const view = new DataView(png.buffer, png.byteOffset);
+    view.setUint32(16, 1);
+    view.setUint32(20, 1);
+
+    expect(() => decodePng(png)).toThrow(ImageDecodeError);
+    expect(() => decodePng(png)).toThrow('larger than the image dimensions');
+  });
+});
@@ -0,0 +1,166 @@
+/**
+ * SVG Rasterizer Tests
+ */
+
+import { describe, test, expect } from 'bun:test';
+import {
+  decodeSvg,
+  flattenPath,
+  isSvg,
+  parseColor,
+  parseTransform,
+  parseXml,
+} from '../../../../src/core/image/svg.ts';
+
+const encode = (text: string) => new TextEncoder().encode(text);
+
+function pixel(svg: string, x: number, y: number, scale = 1): number[] {
+  const image = decodeSvg(encode(svg), { scale });
+  const i = (y * image.width + x) * 4;
+  return [...image.data.subarray(i, i + 4)];
+}
+
+describe('parseXml', () => {
+  test('parses nested elements, attributes and entities', () => {
+    const [root] = parseXml(
+      '<?xml version="1.0"?><!-- c --><svg a="1" b=\'x &amp; y\'><g><rect/></g><![CDATA[<x>]]></svg>'
+    );
+    expect(root!.name).toBe('svg');
+    expect(root!.attributes).toEqual({ a: '1', b: 'x & y' });
+    expect(root!.children[0]!.children[0]!.name).toBe('rect');
+    expect(root!.children).toHaveLength(1);
+  });
+});
+
+describe('parseColor', () => {
+  test('parses hex, functional and named colors', () => {
+    expect(parseColor('#f00')).toEqual({ r: 255, g: 0, b: 0, a: 1 });
+    expect(parseColor('#00ff0080')!.a).toBeCloseTo(128 / 255);
+    expect(parseColor('rgb(10, 20, 30)')).toEqual({ r: 10, g: 20, b: 30, a: 1 });
+    expect(parseColor('rgba(100%, 0%, 0%, 0.5)')).toEqual({ r: 255, g: 0, b: 0, a: 0.5 });
+    expect(parseColor('hsl(120, 100%, 50%)')).toEqual({ r: 0, g: 255, b: 0, a: 1 });
+    expect(parseColor('RebeccaPurple')).toEqual({ r: 102, g: 51, b: 153, a: 1 });
+    expect(parseColor('none')).toBeNull();
+    expect(parseColor('currentColor', { r: 1, g: 2, b: 3, a: 1 })).toEqual({ r: 1, g: 2, b: 3, a: 1 });
+  });
+});
+
+describe('parseTransform', () => {
+  test('composes transforms left to right', () => {
+    const m = parseTransform('translate(10 20) scale(2)');
+    expect(m).toEqual([2, 0, 0, 2, 10, 20]);
+  });
+
+  test('rotates about a point', () => {
+    const m = parseTransform('rotate(90 5 5)');
+    // (10, 5) rotates to (5, 10)
+    expect(m[0] * 10 + m[2] * 5 + m[4]).toBeCloseTo(5);
+    expect(m[1] * 10 + m[3] * 5 + m[5]).toBeCloseTo(10);
+  });
+});
+
+describe('flattenPath', () => {
+  test('handles relative commands and closepath', () => {
+    const [polyline] = flattenPath('m1 1 h4 v4 h-4 z');
+    expect(polyline!.closed).toBe(true);
+    expect(polyline!.points.slice(0, 4)).toEqual([
+      { x: 1, y: 1 },
+      { x: 5, y: 1 },
+      { x: 5, y: 5 },
+      { x: 1, y: 5 },
+    ]);
+  });
+
+  test('parses compact arc flags', () => {
+    const [polyline] = flattenPath('M0 0a5 5 0 105 5');
+    const last = polyline!.points[polyline!.points.length - 1]!;
+    expect(last.x).toBeCloseTo(5);
+    expect(last.y).toBeCloseTo(5);
+  });
+
+  test('flattens curves through their end points', () => {
+    const [polyline] = flattenPath('M0 0 C 0 10 10 10 10 0 S 20 -10 20 0 Q 25 5 30 0 T 40 0');
+    const last = polyline!.points[polyline!.points.length - 1]!;
+    expect(last).toEqual({ x: 40, y: 0 });
+  });
+});
+
+describe('decodeSvg', () => {
+  test('detects SVG documents', () => {
+    expect(isSvg(encode('\uFEFF<?xml version="1.0"?>\n<svg xmlns="http://www.w3.org/2000/svg"/>'))).toBe(true);
+    expect(isSvg(encode('<html></html>'))).toBe(false);
+  });
+
+  test('uses width/height, then the viewBox, then 300x150', () => {
+    expect(decodeSvg(encode('<svg width="40" height="20"/>')).width).toBe(40);
+    const fromViewBox = decodeSvg(encode('<svg viewBox="0 0 64 32"/>'));
+    expect([fromViewBox.width, fromViewBox.height]).toEqual([64, 32]);
+    const fromWidth = decodeSvg(encode('<svg width="32" viewBox="0 0 64 32"/>'));
+    expect(fromWidth.height).toBe(16);
+    const fallback = decodeSvg(encode('<svg/>'));
+    expect([fallback.width, fallback.height]).toEqual([300, 150]);
+  });
+
+  test('fills shapes and leaves the rest transparent', () => {
+    const svg = '<svg width="20" height="20"><rect x="5" y="5" width="10" height="10" fill="#0000ff"/></svg>';
+    expect(pixel(svg, 10, 10)).toEqual([0, 0, 255, 255]);
+    expect(pixel(svg, 2, 2)[3]).toBe(0);
+  });
+
+  test('maps the viewBox and scales', () => {
+    const svg = '<svg width="10" height="10" viewBox="0 0 100 100"><rect x="50" y="50" width="50" height="50" fill="red"/></svg>';
+    expect(pixel(svg, 7, 7)).toEqual([255, 0, 0, 255]);
+    expect(pixel(svg, 2, 2)[3]).toBe(0);
+    expect(pixel(svg, 15, 15, 2)).toEqual([255, 0, 0, 255]);
+  });
+
+  test('draws circles', () => {
+    const svg = '<svg width="20" height="20"><circle cx="10" cy="10" r="8" fill="lime"/></svg>';
+    expect(pixel(svg, 10, 10)).toEqual([0, 255, 0, 255]);
+    expect(pixel(svg, 1, 1)[3]).toBe(0);
+  });
+
+  test('honours the evenodd fill rule', () => {
+    const d = 'M0 0H20V20H0Z M5 5H15V15H5Z';
+    const nonzero = `<svg width="20" height="20"><path d="${d}"/></svg>`;
+    const evenodd = `<svg width="20" height="20"><path d="${d}" fill-rule="evenodd"/></svg>`;
+    expect(pixel(nonzero, 10, 10)[3]).toBe(255);
+    expect(pixel(evenodd, 10, 10)[3]).toBe(0);
+  });
+
+  test('strokes lines and inherits group styles', () => {
+    const svg =
+      '<svg width="20" height="20"><g stroke="#ff0000" stroke-width="4"><line x1="0" y1="10" x2="20" y2="10"/></g></svg>';
+    expect(pixel(svg, 10, 10)).toEqual([255, 0, 0, 255]);
+    expect(pixel(svg, 10, 2)[3]).toBe(0);
+  });
+
+  test('applies opacity and the style attribute', () => {
+    const svg = '<svg width="4" height="4"><rect width="4" height="4" style="fill: white; fill-opacity: 0.5"/></svg>';
+    const [r, , , a] = pixel(svg, 1, 1);
+    expect(r).toBe(255);
+    expect(a).toBeGreaterThan(120);
+    expect(a).toBeLessThan(135);
+  });
+
+  test('approximates gradients with their average color', () => {
+    const svg =
+      '<svg width="4" height="4"><defs><linearGradient id="g"><stop offset="0" stop-color="#000"/>' +
+      '<stop offset="1" stop-color="#fff"/></linearGradient></defs><rect width="4" height="4" fill="url(#g)"/></svg>';
+    const [r] = pixel(svg, 1, 1);
+    expect(r).toBeGreaterThan(120);
+    expect(r).toBeLessThan(135);
+  });
+
+  test('resolves <use> references', () => {
+    const svg =
+      '<svg width="20" height="20"><defs><rect id="r" width="5" height="5" fill="blue"/></defs>' +
+      '<use href="#r" x="10" y="10"/></svg>';
+    expect(pixel(svg, 12, 12)).toEqual([0, 0, 255, 255]);
+    expect(pixel(svg, 2, 2)[3]).toBe(0);
+  });
+
+  test('rejects documents without an svg element', () => {
+    expect(() => decodeSvg(encode('<html/>'))).toThrow('no <svg> element');
+  });
+});
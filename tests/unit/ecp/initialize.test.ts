@@ -0,0 +1,76 @@
+/**
+ * ECP Initialize Handshake Tests
+ */
+
+import { describe, test, expect } from 'bun:test';
+import * as fs from 'fs';
+import {
+  ECP_PROTOCOL_VERSION,
+  ECP_MIN_PROTOCOL_VERSION,
+  negotiateProtocolVersion,
+  matchesNotificationFilter,
+} from '../../../src/ecp/initialize.ts';
+import { DOCUMENT_NOTIFICATIONS } from '../../../src/services/document/adapter.ts';
+import { FILE_NOTIFICATIONS } from '../../../src/services/file/adapter.ts';
+import { LSP_NOTIFICATIONS } from '../../../src/services/lsp/adapter.ts';
+import { TERMINAL_NOTIFICATIONS } from '../../../src/services/terminal/adapter.ts';
+import { NOTIFICATION_NOTIFICATIONS } from '../../../src/services/notification/adapter.ts';
+
+describe('negotiateProtocolVersion', () => {
+  test('uses the oldest version when the client does not state one', () => {
+    expect(negotiateProtocolVersion(undefined)).toBe(ECP_MIN_PROTOCOL_VERSION);
+  });
+
+  test('uses the lower of the client and server versions', () => {
+    expect(negotiateProtocolVersion(ECP_MIN_PROTOCOL_VERSION)).toBe(ECP_MIN_PROTOCOL_VERSION);
+    expect(negotiateProtocolVersion(ECP_PROTOCOL_VERSION + 5)).toBe(ECP_PROTOCOL_VERSION);
+  });
+
+  test('rejects versions older than the minimum or not integers', () => {
+    expect(negotiateProtocolVersion(ECP_MIN_PROTOCOL_VERSION - 1)).toBeNull();
+    expect(negotiateProtocolVersion(1.5)).toBeNull();
+  });
+});
+
+describe('matchesNotificationFilter', () => {
+  test('matches everything without a filter', () => {
+    expect(matchesNotificationFilter('lsp/progress', undefined)).toBe(true);
+  });
+
+  test('matches method names and service patterns', () => {
+    const filter = ['document/didChange', 'lsp/*'];
+
+    expect(matchesNotificationFilter('document/didChange', filter)).toBe(true);
+    expect(matchesNotificationFilter('document/didOpen', filter)).toBe(false);
+    expect(matchesNotificationFilter('lsp/didPublishDiagnostics', filter)).toBe(true);
+    expect(matchesNotificationFilter('lspx/progress', filter)).toBe(false);
+  });
+
+  test('an empty filter matches nothing and * matches everything', () => {
+    expect(matchesNotificationFilter('terminal/output', [])).toBe(false);
+    expect(matchesNotificationFilter('terminal/output', ['*'])).toBe(true);
+  });
+});
+
+describe('adapter notifications', () => {
+  test('the advertised lists cover every notification an adapter sends', () => {
+    const advertised = new Set<string>([
+      ...DOCUMENT_NOTIFICATIONS,
+      ...FILE_NOTIFICATIONS,
+      ...LSP_NOTIFICATIONS,
+      ...TERMINAL_NOTIFICATIONS,
+      ...NOTIFICATION_NOTIFICATIONS,
+    ]);
+    const sent = new Set<string>();
+    for (const service of fs.readdirSync('src/services')) {
+      const file = `src/services/${service}/adapter.ts`;
+      if (!fs.existsSync(file)) continue;
+      for (const match of fs.readFileSync(file, 'utf-8').matchAll(/(?:send|emit)Notification\(\s*'([^']+)'/g)) {
+        sent.add(match[1]!);
+      }
+    }
+
+    expect([...sent].filter((method) => !advertised.has(method))).toEqual([]);
+    expect([...advertised].filter((method) => !sent.has(method))).toEqual([]);
+  });
+});
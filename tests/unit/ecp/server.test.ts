@@ -3,8 +3,13 @@
  */
 
 import { describe, test, expect, beforeEach, afterEach } from 'bun:test';
+import * as fs from 'fs';
+import * as os from 'os';
+import * as path from 'path';
 import { ECPServer, createECPServer } from '../../../src/ecp/server.ts';
 import { ECPErrorCodes } from '../../../src/ecp/types.ts';
+import type { ECPInitializeResult } from '../../../src/ecp/types.ts';
+import { ECP_PROTOCOL_VERSION, ECP_MIN_PROTOCOL_VERSION } from '../../../src/ecp/initialize.ts';
 
 describe('ECPServer', () => {
   let server: ECPServer;
@@ -200,6 +205,88 @@ describe('ECPServer', () => {
     });
   });
 
+  // ─────────────────────────────────────────────────────────────────────────
+  // Initialize Handshake
+  // ─────────────────────────────────────────────────────────────────────────
+
+  describe('initialize handshake', () => {
+    test('negotiates the protocol version and reports capabilities', async () => {
+      const result = await server.request<ECPInitializeResult>('initialize', {
+        protocolVersion: ECP_PROTOCOL_VERSION + 1,
+        clientInfo: { name: 'test-client', version: '1.2.3' },
+        capabilities: {},
+      });
+
+      expect(result.protocolVersion).toBe(ECP_PROTOCOL_VERSION);
+      expect(result.serverInfo.name).toBe('ultra');
+      expect(result.capabilities.services).toContain('document');
+      expect(result.capabilities.documentSync.incremental).toBe(true);
+      expect(result.capabilities.notifications).toContain('document/didChange');
+      expect(server.getClientInfo()).toEqual({ name: 'test-client', version: '1.2.3' });
+      expect(server.getProtocolVersion()).toBe(ECP_PROTOCOL_VERSION);
+    });
+
+    test('clients that skip the handshake use the oldest version', async () => {
+      expect(server.getProtocolVersion()).toBe(ECP_MIN_PROTOCOL_VERSION);
+      expect(server.getClientInfo()).toBeNull();
+
+      const result = await server.initialize();
+      expect(result.protocolVersion).toBe(ECP_MIN_PROTOCOL_VERSION);
+      expect(server.getProtocolVersion()).toBe(ECP_MIN_PROTOCOL_VERSION);
+    });
+
+    test('reports the package version', async () => {
+      const result = await server.initialize();
+      const packageJson = JSON.parse(fs.readFileSync('package.json', 'utf-8')) as { version: string };
+
+      expect(result.serverInfo.version).toBe(packageJson.version);
+    });
+
+    test('rejects unsupported protocol versions', async () => {
+      const response = await server.requestRaw('initialize', { protocolVersion: 0 });
+
+      expect(response.error?.code).toBe(ECPErrorCodes.UnsupportedProtocolVersion);
+      expect(response.error?.data).toEqual({ min: ECP_MIN_PROTOCOL_VERSION, max: ECP_PROTOCOL_VERSION });
+    });
+
+    test('rejects a second handshake', async () => {
+      await server.initialize({ clientInfo: { name: 'first' } });
+
+      await expect(server.initialize({ clientInfo: { name: 'second' } })).rejects.toThrow('already initialized');
+      expect(server.getClientInfo()?.name).toBe('first');
+    });
+
+    test('sends only the notifications the client wants', async () => {
+      await server.initialize({ capabilities: { notifications: ['document/didOpen'] } });
+      const methods: string[] = [];
+      server.onNotification((method) => methods.push(method));
+
+      const { documentId } = await server.request<{ documentId: string }>(
+        'document/open',
+        { uri: 'memory://test.txt', content: 'hello' }
+      );
+      await server.request('document/insert', { documentId, position: { line: 0, column: 5 }, text: '!' });
+
+      expect(methods).toEqual(['document/didOpen']);
+    });
+
+    test('adds full content to didChange for non-incremental clients', async () => {
+      await server.initialize({ capabilities: { documentSync: { incremental: false } } });
+      const changes: Array<{ content?: string }> = [];
+      server.onNotification((method, params) => {
+        if (method === 'document/didChange') changes.push(params as { content?: string });
+      });
+
+      const { documentId } = await server.request<{ documentId: string }>(
+        'document/open',
+        { uri: 'memory://test.txt', content: 'hello' }
+      );
+      await server.request('document/insert', { documentId, position: { line: 0, column: 5 }, text: '!' });
+
+      expect(changes[0]?.content).toBe('hello!');
+    });
+  });
+
   // ─────────────────────────────────────────────────────────────────────────
   // Service Access
   // ─────────────────────────────────────────────────────────────────────────
@@ -240,6 +327,11 @@ describe('ECPServer', () => {
       expect(service).toBeDefined();
     });
 
+    test('returns search service', () => {
+      const service = server.getService('search');
+      expect(service).toBeDefined();
+    });
+
     test('throws for unknown service', () => {
       expect(() => server.getService('unknown' as any)).toThrow();
     });
@@ -311,6 +403,69 @@ describe('ECPServer', () => {
       // Close terminal
       await server.request('terminal/close', { terminalId });
     });
+
+    describe('search workflow', () => {
+      let root: string;
+      let searchServer: ECPServer;
+
+      beforeEach(() => {
+        root = fs.realpathSync(fs.mkdtempSync(path.join(os.tmpdir(), 'ecp-search-')));
+        fs.writeFileSync(path.join(root, 'a.ts'), 'const foo = 1;\n');
+        searchServer = createECPServer({ workspaceRoot: root });
+      });
+
+      afterEach(async () => {
+        await searchServer.shutdown();
+        fs.rmSync(root, { recursive: true, force: true });
+      });
+
+      async function openEdited(): Promise<string> {
+        const { documentId } = await searchServer.request<{ documentId: string }>(
+          'document/open',
+          { uri: `file://${root}/a.ts`, content: 'const foo = 1;\n' }
+        );
+        await searchServer.request('document/insert', {
+          documentId,
+          position: { line: 0, column: 0 },
+          text: '// foo\n',
+        });
+        return documentId;
+      }
+
+      test('finds unsaved edits in open documents', async () => {
+        await openEdited();
+
+        const result = await searchServer.request<{ files: { path: string; matches: { line: number }[] }[] }>(
+          'search/find',
+          { query: 'foo' }
+        );
+
+        expect(result.files).toEqual([
+          { path: 'a.ts', matches: [expect.objectContaining({ line: 1 }), expect.objectContaining({ line: 2 })] },
+        ]);
+      });
+
+      test('replaces in open documents without touching the file', async () => {
+        const documentId = await openEdited();
+
+        const result = await searchServer.request<{ buffersModified?: string[] }>('search/replaceInFiles', {
+          files: [{ path: 'a.ts', matches: [{ line: 1, column: 3, length: 3 }, { line: 2, column: 6, length: 3 }] }],
+          query: 'foo',
+          replacement: 'bar',
+        });
+        const { content } = await searchServer.request<{ content: string }>('document/content', { documentId });
+
+        expect(result.buffersModified).toEqual(['a.ts']);
+        expect(content).toBe('// bar\nconst bar = 1;\n');
+        expect(fs.readFileSync(path.join(root, 'a.ts'), 'utf8')).toBe('const foo = 1;\n');
+      });
+
+      test('rejects missing params', async () => {
+        const response = await searchServer.requestRaw('search/find', {});
+
+        expect(response.error?.code).toBe(ECPErrorCodes.InvalidParams);
+      });
+    });
   });
 });
 
@@ -260,6 +260,72 @@ describe('LocalDocumentService', () => {
     });
   });
 
+  describe('snapshots', () => {
+    test('snapshot keeps its version while the document changes', async () => {
+      const { documentId } = await service.open({
+        uri: 'memory://test.txt',
+        content: 'one\ntwo\nthree',
+      });
+
+      const snapshot = service.getSnapshot(documentId)!;
+      service.replace({
+        documentId,
+        range: { start: { line: 1, column: 0 }, end: { line: 1, column: 3 } },
+        text: 'TWO\nand a half',
+      });
+
+      expect(snapshot.documentId).toBe(documentId);
+      expect(snapshot.version).toBeLessThan(service.getVersion(documentId)!);
+      expect(snapshot.lineCount).toBe(3);
+      expect(snapshot.getLine(1)).toBe('two');
+      expect(snapshot.getText({ start: { line: 0, column: 2 }, end: { line: 1, column: 1 } })).toBe('e\nt');
+      expect(snapshot.getText()).toBe('one\ntwo\nthree');
+      expect(service.getLine(documentId, 1)?.text).toBe('TWO');
+    });
+
+    test('returns null for unknown documents', () => {
+      expect(service.getSnapshot('missing')).toBeNull();
+      expect(service.transformPosition('missing', { line: 0, column: 0 }, 1)).toBeNull();
+    });
+
+    test('remaps positions from an older version', async () => {
+      const { documentId } = await service.open({
+        uri: 'memory://test.txt',
+        content: 'alpha\nbeta\ngamma',
+      });
+      const version = service.getVersion(documentId)!;
+
+      service.insert({ documentId, position: { line: 0, column: 0 }, text: 'new line\n' });
+      service.delete({ documentId, range: { start: { line: 2, column: 0 }, end: { line: 2, column: 2 } } });
+
+      // "gamma" moved down a line; "ta" in "beta" shifted left by two
+      expect(service.transformPosition(documentId, { line: 2, column: 1 }, version)).toEqual({ line: 3, column: 1 });
+      expect(service.transformPosition(documentId, { line: 1, column: 2 }, version)).toEqual({ line: 2, column: 0 });
+      expect(service.transformRange(documentId, {
+        start: { line: 1, column: 0 },
+        end: { line: 1, column: 4 },
+      }, version)).toEqual({ start: { line: 2, column: 0 }, end: { line: 2, column: 2 } });
+    });
+
+    test('insertions at a position respect the bias', async () => {
+      const { documentId } = await service.open({ uri: 'memory://test.txt', content: 'ab' });
+      const version = service.getVersion(documentId)!;
+
+      service.insert({ documentId, position: { line: 0, column: 1 }, text: 'XY' });
+
+      expect(service.transformPosition(documentId, { line: 0, column: 1 }, version)).toEqual({ line: 0, column: 3 });
+      expect(service.transformPosition(documentId, { line: 0, column: 1 }, version, 'left')).toEqual({ line: 0, column: 1 });
+    });
+
+    test('returns null for versions outside the history', async () => {
+      const { documentId } = await service.open({ uri: 'memory://test.txt', content: 'ab' });
+      const version = service.getVersion(documentId)!;
+
+      expect(service.transformPosition(documentId, { line: 0, column: 0 }, version + 1)).toBeNull();
+      expect(service.transformPosition(documentId, { line: 0, column: 0 }, version - 1)).toBeNull();
+    });
+  });
+
   // ───────────────────────────────────────────────────────────────────────
   // Text Editing
   // ───────────────────────────────────────────────────────────────────────
@@ -357,6 +423,51 @@ describe('LocalDocumentService', () => {
     });
   });
 
+  describe('applyEdits', () => {
+    test('replaces several ranges as one undo step', async () => {
+      const { documentId } = await service.open({
+        uri: 'memory://test.txt',
+        content: 'foo bar\nbar foo',
+      });
+      service.setCursor(documentId, { line: 1, column: 7 });
+
+      const result = service.applyEdits({
+        documentId,
+        edits: [
+          { range: { start: { line: 0, column: 0 }, end: { line: 0, column: 3 } }, text: 'x' },
+          { range: { start: { line: 1, column: 4 }, end: { line: 1, column: 7 } }, text: 'quux' },
+        ],
+      });
+
+      expect(result.success).toBe(true);
+      expect(service.getContent(documentId)!.content).toBe('x bar\nbar quux');
+      expect(service.getCursors(documentId)![0]!.position).toEqual({ line: 1, column: 8 });
+      expect(service.isDirty(documentId)).toBe(true);
+
+      service.undo(documentId);
+      expect(service.getContent(documentId)!.content).toBe('foo bar\nbar foo');
+
+      service.redo(documentId);
+      expect(service.getContent(documentId)!.content).toBe('x bar\nbar quux');
+    });
+
+    test('does not merge with preceding typing', async () => {
+      const { documentId } = await service.open({
+        uri: 'memory://test.txt',
+        content: 'ab',
+      });
+      service.insert({ documentId, position: { line: 0, column: 2 }, text: 'c' });
+
+      service.applyEdits({
+        documentId,
+        edits: [{ range: { start: { line: 0, column: 0 }, end: { line: 0, column: 1 } }, text: 'A' }],
+      });
+      service.undo(documentId);
+
+      expect(service.getContent(documentId)!.content).toBe('abc');
+    });
+  });
+
   describe('setContent', () => {
     test('replaces entire content', async () => {
       const { documentId } = await service.open({
@@ -369,6 +480,19 @@ describe('LocalDocumentService', () => {
       expect(result.success).toBe(true);
       expect(service.getContent(documentId)!.content).toBe('New content');
     });
+
+    test('changes only the span that differs', async () => {
+      const { documentId, info } = await service.open({
+        uri: 'memory://test.txt',
+        content: 'one\ntwo\nthree',
+      });
+
+      service.setContent(documentId, 'zero\none\nTWO\nthree');
+
+      expect(service.transformPosition(documentId, { line: 2, column: 3 }, info.version)).toEqual({ line: 3, column: 3 });
+      service.undo(documentId);
+      expect(service.getContent(documentId)!.content).toBe('one\ntwo\nthree');
+    });
   });
 
   // ───────────────────────────────────────────────────────────────────────
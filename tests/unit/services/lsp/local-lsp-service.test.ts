@@ -9,7 +9,8 @@
 import { describe, test, expect, beforeEach, afterEach } from 'bun:test';
 import { LocalLSPService } from '../../../../src/services/lsp/service.ts';
 import { LSPError, LSPErrorCode } from '../../../../src/services/lsp/errors.ts';
-import type { ServerStatus, LSPDiagnostic } from '../../../../src/services/lsp/types.ts';
+import { MessageType } from '../../../../src/services/lsp/types.ts';
+import type { ServerStatus, LSPDiagnostic, LSPProgress, LSPServerMessage } from '../../../../src/services/lsp/types.ts';
 
 describe('LocalLSPService', () => {
   let service: LocalLSPService;
@@ -333,6 +334,17 @@ describe('LocalLSPService', () => {
       expect(symbols).toEqual([]);
     });
 
+    test('folding ranges are null (use indentation) for unopened document', async () => {
+      expect(await service.getFoldingRanges('file:///test.ts')).toBeNull();
+    });
+
+    test('links and colours are empty for unopened document', async () => {
+      expect(await service.getDocumentLinks('file:///test.css')).toEqual([]);
+      expect(await service.getDocumentColors('file:///test.css')).toEqual([]);
+      const range = { start: { line: 0, character: 0 }, end: { line: 0, character: 4 } };
+      expect(await service.getColorPresentations('file:///test.css', { red: 1, green: 0, blue: 0, alpha: 1 }, range)).toEqual([]);
+    });
+
     test('rename returns null for unopened document', async () => {
       const edit = await service.rename(
         'file:///test.ts',
@@ -342,6 +354,232 @@ describe('LocalLSPService', () => {
       expect(edit).toBeNull();
     });
   });
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Progress and Server Message Tests
+  // ─────────────────────────────────────────────────────────────────────────
+
+  describe('progress', () => {
+    const progress = (token: string, value: Record<string, unknown>) =>
+      (service as any).handleNotification('go', '$/progress', { token, value });
+
+    test('tracks begin, report and end', () => {
+      const updates: LSPProgress[] = [];
+      service.onProgress((p) => updates.push(p));
+
+      progress('t1', { kind: 'begin', title: 'Indexing' });
+      progress('t1', { kind: 'report', message: '3/10', percentage: 30 });
+      expect(service.getActiveProgress()).toEqual([
+        { languageId: 'go', token: 't1', title: 'Indexing', message: '3/10', percentage: 30, done: false },
+      ]);
+
+      progress('t1', { kind: 'end' });
+      expect(service.getActiveProgress()).toEqual([]);
+      expect(updates.map((u) => u.done)).toEqual([false, false, true]);
+    });
+
+    test('ignores reports for unknown tokens', () => {
+      const updates: LSPProgress[] = [];
+      service.onProgress((p) => updates.push(p));
+
+      progress('missing', { kind: 'report', message: 'x' });
+      expect(updates).toEqual([]);
+    });
+
+    test('filters active progress by language', () => {
+      progress('t1', { kind: 'begin', title: 'Indexing' });
+      expect(service.getActiveProgress('go')).toHaveLength(1);
+      expect(service.getActiveProgress('rust')).toHaveLength(0);
+    });
+  });
+
+  describe('server messages', () => {
+    test('window/showMessage is forwarded', () => {
+      const messages: LSPServerMessage[] = [];
+      service.onServerMessage((m) => messages.push(m));
+
+      (service as any).handleNotification('go', 'window/showMessage', { type: MessageType.Warning, message: 'gopls is old' });
+      expect(messages).toEqual([{ languageId: 'go', type: MessageType.Warning, message: 'gopls is old' }]);
+    });
+
+    test('showMessageRequest resolves to the chosen action', async () => {
+      service.setMessageRequestHandler(async (m) => m.actions?.[1] ?? null);
+
+      const result = await (service as any).handleServerRequest('go', 'window/showMessageRequest', {
+        type: MessageType.Info,
+        message: 'Reload?',
+        actions: [{ title: 'No' }, { title: 'Yes' }],
+      });
+      expect(result).toEqual({ title: 'Yes' });
+    });
+
+    test('showMessageRequest without a handler shows the message and resolves null', async () => {
+      const messages: LSPServerMessage[] = [];
+      service.onServerMessage((m) => messages.push(m));
+
+      const result = await (service as any).handleServerRequest('go', 'window/showMessageRequest', {
+        type: MessageType.Info,
+        message: 'Reload?',
+        actions: [{ title: 'Yes' }],
+      });
+      expect(result).toBeNull();
+      expect(messages[0]?.actions).toEqual(['Yes']);
+    });
+
+    test('other server requests are not handled', () => {
+      expect((service as any).handleServerRequest('go', 'workspace/configuration', {})).toBeUndefined();
+    });
+  });
+
+  describe('pull diagnostics', () => {
+    /** Minimal stand-in for LSPClient with queued pull reports */
+    function fakeClient(options: { interFileDependencies: boolean; workspaceDiagnostics: boolean } | null) {
+      return {
+        documentReports: [] as unknown[],
+        workspaceReports: [] as unknown[],
+        documentCalls: [] as Array<{ uri: string; previousResultId?: string }>,
+        workspaceCalls: [] as Array<Array<{ uri: string; value: string }>>,
+        getDiagnosticOptions() {
+          return options;
+        },
+        async pullDocumentDiagnostics(uri: string, previousResultId?: string) {
+          this.documentCalls.push({ uri, previousResultId });
+          return this.documentReports.shift() ?? null;
+        },
+        async pullWorkspaceDiagnostics(previousResultIds: Array<{ uri: string; value: string }>) {
+          this.workspaceCalls.push(previousResultIds);
+          return this.workspaceReports.shift() ?? null;
+        },
+        didChange() {},
+        async shutdown() {},
+      };
+    }
+
+    function openDocument(uri: string, languageId: string, version = 1): void {
+      (service as any).documentLanguages.set(uri, languageId);
+      (service as any).documentVersions.set(uri, version);
+    }
+
+    const diagnostic = (message: string): LSPDiagnostic => ({
+      range: { start: { line: 0, character: 0 }, end: { line: 0, character: 1 } },
+      severity: 1,
+      message,
+    });
+
+    test('full reports replace diagnostics and remember the result ID', async () => {
+      const client = fakeClient({ interFileDependencies: false, workspaceDiagnostics: false });
+      (service as any).clients.set('go', client);
+      openDocument('file:///a.go', 'go');
+
+      const received: string[] = [];
+      service.onDiagnostics((uri) => received.push(uri));
+
+      client.documentReports.push({ kind: 'full', resultId: 'r1', items: [diagnostic('bad')] });
+      await service.pullDiagnostics('file:///a.go');
+      expect(service.getDiagnostics('file:///a.go').map((d) => d.message)).toEqual(['bad']);
+      expect(received).toEqual(['file:///a.go']);
+
+      // Unchanged keeps diagnostics and sends the previous result ID
+      client.documentReports.push({ kind: 'unchanged', resultId: 'r2' });
+      await service.pullDiagnostics('file:///a.go');
+      expect(client.documentCalls[1]?.previousResultId).toBe('r1');
+      expect(service.getDiagnostics('file:///a.go').map((d) => d.message)).toEqual(['bad']);
+      expect(received).toHaveLength(1);
+    });
+
+    test('related documents are applied', async () => {
+      const client = fakeClient({ interFileDependencies: true, workspaceDiagnostics: false });
+      (service as any).clients.set('go', client);
+      openDocument('file:///a.go', 'go');
+
+      client.documentReports.push({
+        kind: 'full',
+        items: [],
+        relatedDocuments: { 'file:///b.go': { kind: 'full', resultId: 'b1', items: [diagnostic('in b')] } },
+      });
+      await service.pullDiagnostics('file:///a.go');
+
+      expect(service.getDiagnostics('file:///b.go').map((d) => d.message)).toEqual(['in b']);
+    });
+
+    test('reports for a stale document version are dropped', async () => {
+      const client = fakeClient({ interFileDependencies: false, workspaceDiagnostics: false });
+      (service as any).clients.set('go', client);
+      openDocument('file:///a.go', 'go');
+
+      client.pullDocumentDiagnostics = async function () {
+        (service as any).documentVersions.set('file:///a.go', 2);
+        return { kind: 'full', items: [diagnostic('old')] };
+      };
+      await service.pullDiagnostics('file:///a.go');
+
+      expect(service.getDiagnostics('file:///a.go')).toEqual([]);
+    });
+
+    test('servers without pull support are not asked', async () => {
+      const client = fakeClient(null);
+      (service as any).clients.set('go', client);
+      openDocument('file:///a.go', 'go');
+
+      await service.pullDiagnostics('file:///a.go');
+      await service.pullWorkspaceDiagnostics('go');
+      expect(client.documentCalls).toHaveLength(0);
+      expect(client.workspaceCalls).toHaveLength(0);
+    });
+
+    test('workspace reports cover unopened files and skip stale open ones', async () => {
+      const client = fakeClient({ interFileDependencies: false, workspaceDiagnostics: true });
+      (service as any).clients.set('go', client);
+      openDocument('file:///open.go', 'go', 3);
+
+      client.workspaceReports.push({
+        items: [
+          { uri: 'file:///closed.go', version: null, kind: 'full', resultId: 'c1', items: [diagnostic('closed')] },
+          { uri: 'file:///open.go', version: 2, kind: 'full', items: [diagnostic('stale')] },
+        ],
+      });
+      await service.pullWorkspaceDiagnostics('go');
+
+      expect(service.getDiagnostics('file:///closed.go').map((d) => d.message)).toEqual(['closed']);
+      expect(service.getDiagnostics('file:///open.go')).toEqual([]);
+
+      // Known result IDs are sent on the next pull
+      await service.pullWorkspaceDiagnostics('go');
+      expect(client.workspaceCalls[1]).toEqual([{ uri: 'file:///closed.go', value: 'c1' }]);
+    });
+
+    test('refresh request re-pulls open documents', async () => {
+      const client = fakeClient({ interFileDependencies: false, workspaceDiagnostics: false });
+      (service as any).clients.set('go', client);
+      openDocument('file:///a.go', 'go');
+      openDocument('file:///b.ts', 'typescript');
+
+      const result = await (service as any).handleServerRequest('go', 'workspace/diagnostic/refresh', null);
+      expect(result).toBeNull();
+
+      await new Promise((resolve) => setTimeout(resolve, 10));
+      expect(client.documentCalls.map((c) => c.uri)).toEqual(['file:///a.go']);
+    });
+
+    test('edits are debounced into one pull', async () => {
+      const client = fakeClient({ interFileDependencies: false, workspaceDiagnostics: false });
+      (service as any).clients.set('go', client);
+      openDocument('file:///a.go', 'go');
+
+      await service.documentChanged('file:///a.go', 'a', 2);
+      await service.documentChanged('file:///a.go', 'ab', 3);
+      await new Promise((resolve) => setTimeout(resolve, 300));
+
+      expect(client.documentCalls).toHaveLength(1);
+    });
+  });
+
+  describe('tracing', () => {
+    test('no servers are traced initially', () => {
+      expect(service.getTracedServers()).toEqual([]);
+      expect(service.getTrace('go')).toEqual([]);
+    });
+  });
 });
 
 describe('LSPError', () => {
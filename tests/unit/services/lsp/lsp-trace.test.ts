@@ -0,0 +1,111 @@
+/**
+ * LSPTraceLog Unit Tests
+ *
+ * Tests for JSON-RPC trace recording and formatting.
+ */
+
+import { describe, test, expect } from 'bun:test';
+import { LSPTraceLog, formatTraceEntry } from '../../../../src/services/lsp/trace.ts';
+
+describe('LSPTraceLog', () => {
+  test('records request and response with duration', () => {
+    const log = new LSPTraceLog();
+    log.recordMessage('send', { jsonrpc: '2.0', id: 1, method: 'textDocument/hover', params: {} }, 1000);
+    log.recordMessage('receive', { jsonrpc: '2.0', id: 1, result: null }, 1042);
+
+    const [request, response] = log.getEntries();
+    expect(request).toMatchObject({ kind: 'request', direction: 'send', id: 1, method: 'textDocument/hover' });
+    expect(response).toMatchObject({ kind: 'response', direction: 'receive', id: 1, method: 'textDocument/hover', durationMs: 42, isError: false });
+  });
+
+  test('times server-to-client requests', () => {
+    const log = new LSPTraceLog();
+    log.recordMessage('receive', { id: 'a', method: 'window/workDoneProgress/create', params: {} }, 0);
+    log.recordMessage('send', { id: 'a', result: null }, 5);
+
+    expect(log.getEntries()[1]).toMatchObject({ kind: 'response', method: 'window/workDoneProgress/create', durationMs: 5 });
+  });
+
+  test('records error responses', () => {
+    const log = new LSPTraceLog();
+    log.recordMessage('send', { id: 2, method: 'textDocument/rename' }, 0);
+    log.recordMessage('receive', { id: 2, error: { code: -32603, message: 'boom' } }, 10);
+
+    const response = log.getEntries()[1]!;
+    expect(response.isError).toBe(true);
+    expect(response.text).toBe('boom');
+  });
+
+  test('window/logMessage becomes a log entry', () => {
+    const log = new LSPTraceLog();
+    log.recordMessage('receive', { method: 'window/logMessage', params: { type: 2, message: 'slow index' } });
+
+    expect(log.getEntries()[0]).toMatchObject({ kind: 'log', logType: 2, text: 'slow index' });
+  });
+
+  test('records stderr lines, skipping blanks', () => {
+    const log = new LSPTraceLog();
+    log.recordStderr('panic: oops\n\n  at main  \n');
+
+    expect(log.getEntries().map((e) => e.text)).toEqual(['panic: oops', '  at main']);
+  });
+
+  test('records payloads only when verbose', () => {
+    const log = new LSPTraceLog('messages');
+    log.recordMessage('send', { method: 'initialized', params: { a: 1 } });
+    expect(log.getEntries()[0]!.payload).toBeUndefined();
+
+    log.setLevel('verbose');
+    log.recordMessage('send', { method: 'initialized', params: { a: 1 } });
+    expect(log.getEntries()[1]!.payload).toBe('{"a":1}');
+  });
+
+  test('records nothing when off', () => {
+    const log = new LSPTraceLog('off');
+    log.recordMessage('send', { id: 1, method: 'shutdown' });
+    log.recordStderr('error');
+    expect(log.getEntries()).toEqual([]);
+  });
+
+  test('keeps only the newest entries', () => {
+    const log = new LSPTraceLog('messages', 3);
+    for (let i = 0; i < 5; i++) {
+      log.recordMessage('send', { method: `m${i}` });
+    }
+
+    expect(log.getEntries().map((e) => e.method)).toEqual(['m2', 'm3', 'm4']);
+    expect(log.getEntries()[0]!.seq).toBe(3);
+  });
+
+  test('notifies listeners until unsubscribed', () => {
+    const log = new LSPTraceLog();
+    const seen: string[] = [];
+    const unsubscribe = log.onEntry((e) => seen.push(e.method ?? ''));
+
+    log.recordMessage('send', { method: 'a' });
+    unsubscribe();
+    log.recordMessage('send', { method: 'b' });
+
+    expect(seen).toEqual(['a']);
+  });
+});
+
+describe('formatTraceEntry', () => {
+  const timestamp = new Date(2026, 0, 1, 12, 3, 4, 120).getTime();
+
+  test('formats requests and responses', () => {
+    expect(formatTraceEntry({ seq: 1, timestamp, direction: 'send', kind: 'request', id: 5, method: 'textDocument/hover' }))
+      .toBe('12:03:04.120 → request #5 textDocument/hover');
+    expect(formatTraceEntry({ seq: 2, timestamp, direction: 'receive', kind: 'response', id: 5, method: 'textDocument/hover', durationMs: 42 }))
+      .toBe('12:03:04.120 ← response #5 textDocument/hover (42ms)');
+  });
+
+  test('formats errors, logs and stderr', () => {
+    expect(formatTraceEntry({ seq: 1, timestamp, direction: 'receive', kind: 'response', id: 3, isError: true, text: 'boom' }))
+      .toBe('12:03:04.120 ← response #3 error: boom');
+    expect(formatTraceEntry({ seq: 2, timestamp, direction: 'receive', kind: 'log', logType: 1, text: 'failed' }))
+      .toBe('12:03:04.120 [error] failed');
+    expect(formatTraceEntry({ seq: 3, timestamp, direction: 'receive', kind: 'stderr', text: 'panic' }))
+      .toBe('12:03:04.120 [stderr] panic');
+  });
+});
@@ -0,0 +1,254 @@
+/**
+ * ServerDiscovery Unit Tests
+ *
+ * Tests for resolving language server commands from project, Go and
+ * toolchain installs.
+ */
+
+import { describe, test, expect, beforeEach, afterEach } from 'bun:test';
+import * as fs from 'fs';
+import * as os from 'os';
+import * as path from 'path';
+import {
+  ServerDiscovery,
+  parseGoDirectives,
+  parseEnvOutput,
+  type CommandRunner,
+} from '../../../../src/services/lsp/discovery.ts';
+
+const COMMAND = 'fake-language-server';
+
+let root: string;
+
+function write(relative: string, content = ''): string {
+  const file = path.join(root, relative);
+  fs.mkdirSync(path.dirname(file), { recursive: true });
+  fs.writeFileSync(file, content);
+  return file;
+}
+
+function executable(relative: string): string {
+  const file = write(relative, '#!/bin/sh\n');
+  fs.chmodSync(file, 0o755);
+  return file;
+}
+
+function discovery(env: Record<string, string> = {}, run?: CommandRunner): ServerDiscovery {
+  return new ServerDiscovery({
+    env: { PATH: '', GOPATH: path.join(root, 'gopath'), ...env },
+    homeDir: path.join(root, 'home'),
+    run: run ?? (async () => ({ exitCode: 1, stdout: '' })),
+  });
+}
+
+beforeEach(() => {
+  root = fs.realpathSync(fs.mkdtempSync(path.join(os.tmpdir(), 'server-discovery-')));
+});
+
+afterEach(() => {
+  fs.rmSync(root, { recursive: true, force: true });
+});
+
+describe('ServerDiscovery', () => {
+  test('prefers the nearest node_modules/.bin', async () => {
+    executable(`node_modules/.bin/${COMMAND}`);
+    const nearest = executable(`packages/web/node_modules/.bin/${COMMAND}`);
+    write('packages/web/src/index.ts');
+
+    const result = await discovery().discover(COMMAND, path.join(root, 'packages/web/src'));
+
+    expect(result.resolved).toMatchObject({ command: nearest, args: [], source: 'project', cwd: path.join(root, 'packages/web') });
+  });
+
+  test('falls back to a hoisted node_modules/.bin', async () => {
+    const hoisted = executable(`node_modules/.bin/${COMMAND}`);
+    fs.mkdirSync(path.join(root, 'packages/api/node_modules/.bin'), { recursive: true });
+    fs.mkdirSync(path.join(root, 'packages/api/src'), { recursive: true });
+
+    const result = await discovery().discover(COMMAND, path.join(root, 'packages/api/src'));
+
+    expect(result.resolved?.command).toBe(hoisted);
+    expect(result.candidates.map((c) => c.found)).toEqual([false, true]);
+  });
+
+  test('uses go tool when go.mod declares the tool', async () => {
+    const go = executable('sdk/bin/go');
+    write('go.mod', `module example.com/app\n\ntool (\n\texample.com/tools/cmd/${COMMAND} // pinned\n)\n`);
+    fs.mkdirSync(path.join(root, 'internal'), { recursive: true });
+
+    const result = await discovery({ PATH: path.dirname(go) }).discover(COMMAND, path.join(root, 'internal'));
+
+    expect(result.resolved).toMatchObject({ command: go, args: ['tool', COMMAND], source: 'goTool', cwd: root });
+  });
+
+  test('uses go tool from a go.work module, running in the workspace root', async () => {
+    const go = executable('sdk/bin/go');
+    write('go.work', 'go 1.24\n\nuse (\n\t./svc\n\t./tools\n)\n');
+    write('svc/go.mod', 'module example.com/svc\n');
+    write('tools/go.mod', `module example.com/tools\n\ntool example.com/x/${COMMAND}\n`);
+
+    const result = await discovery({ PATH: path.dirname(go) }).discover(COMMAND, path.join(root, 'svc'));
+
+    expect(result.resolved).toMatchObject({ source: 'goTool', cwd: root });
+  });
+
+  test('captures the toolchain environment from the login shell', async () => {
+    write('.tool-versions', 'golang 1.22.0\n');
+    const pinned = executable(`toolchain/bin/${COMMAND}`);
+    executable(`usr/bin/${COMMAND}`);
+    const calls: string[][] = [];
+    const run: CommandRunner = async (argv, { cwd }) => {
+      calls.push(argv);
+      expect(cwd).toBe(path.join(root, 'home'));
+      return {
+        exitCode: 0,
+        stdout: `Welcome!\n__ULTRA_ENV__\nPATH=${path.dirname(pinned)}\nGOFLAGS=-mod=mod\n__ULTRA_ENV__\n`,
+      };
+    };
+
+    const result = await discovery({ PATH: path.join(root, 'usr/bin'), SHELL: '/bin/zsh' }, run).discover(COMMAND, root);
+
+    expect(calls[0]!.slice(0, 3)).toEqual(['/bin/zsh', '-l', '-c']);
+    expect(result.toolchains).toEqual(['asdf']);
+    expect(result.resolved).toMatchObject({ command: pinned, source: 'toolchain' });
+    expect(result.resolved?.env?.['GOFLAGS']).toBe('-mod=mod');
+  });
+
+  test('applies direnv and mise exports on top of the login shell', async () => {
+    write('mise.toml', '[tools]\nnode = "20"\n');
+    write('.envrc', 'use mise\n');
+    const direnv = executable('bin/direnv');
+    executable('bin/mise');
+    const pinned = executable(`mise/installs/node/20/bin/${COMMAND}`);
+    const run: CommandRunner = async (argv) => {
+      if (argv[0] === 'direnv') return { exitCode: 0, stdout: JSON.stringify({ FROM_DIRENV: '1', UNSET_ME: null }) };
+      if (argv[0] === 'mise') {
+        return { exitCode: 0, stdout: JSON.stringify({ PATH: `${path.dirname(pinned)}:${path.dirname(direnv)}` }) };
+      }
+      return { exitCode: 0, stdout: '' };
+    };
+
+    const result = await discovery({ PATH: path.dirname(direnv), UNSET_ME: 'x' }, run).discover(COMMAND, root);
+
+    expect(result.toolchains.sort()).toEqual(['direnv', 'mise']);
+    expect(result.resolved?.command).toBe(pinned);
+    expect(result.resolved?.env?.['FROM_DIRENV']).toBe('1');
+    expect(result.resolved?.env?.['UNSET_ME']).toBeUndefined();
+  });
+
+  test('caches the toolchain environment per project until clearCache', async () => {
+    write('.envrc');
+    let shells = 0;
+    const run: CommandRunner = async () => {
+      shells++;
+      return { exitCode: 0, stdout: '' };
+    };
+    const d = discovery({}, run);
+
+    write('src/deep/a.ts');
+    await d.discover(COMMAND, root);
+    await d.discover(COMMAND, path.join(root, 'src/deep'));
+    expect(shells).toBe(1);
+
+    d.clearCache();
+    await d.discover(COMMAND, root);
+    expect(shells).toBe(2);
+  });
+
+  test('runs the login shell once for several projects', async () => {
+    write('a/.envrc');
+    write('b/.envrc');
+    let shells = 0;
+    const d = discovery({}, async () => {
+      shells++;
+      return { exitCode: 0, stdout: '' };
+    });
+
+    await d.discover(COMMAND, path.join(root, 'a'));
+    await d.discover(COMMAND, path.join(root, 'b'));
+    expect(shells).toBe(1);
+  });
+
+  test('finds the command on PATH', async () => {
+    const onPath = executable(`usr/bin/${COMMAND}`);
+
+    const result = await discovery({ PATH: `${path.join(root, 'missing')}:${path.dirname(onPath)}` }).discover(COMMAND, root);
+
+    expect(result.resolved).toMatchObject({ command: onPath, source: 'path', cwd: root });
+    expect(result.resolved?.env).toBeUndefined();
+  });
+
+  test('checks GOBIN and GOPATH/bin from go env', async () => {
+    executable('sdk/bin/go');
+    const gobin = executable(`gobin/${COMMAND}`);
+    const run: CommandRunner = async (argv) => {
+      expect(argv).toEqual(['go', 'env', 'GOBIN', 'GOPATH']);
+      return { exitCode: 0, stdout: `${path.join(root, 'gobin')}\n${path.join(root, 'gopath')}\n` };
+    };
+
+    const result = await discovery({ PATH: path.join(root, 'sdk/bin'), GOPATH: '' }, run).discover(COMMAND, root);
+
+    expect(result.resolved).toMatchObject({ command: gobin, source: 'goBin' });
+  });
+
+  test('falls back to GOPATH/bin from the environment', async () => {
+    const installed = executable(`gopath/bin/${COMMAND}`);
+
+    const result = await discovery().discover(COMMAND, root);
+
+    expect(result.resolved).toMatchObject({ command: installed, source: 'goBin' });
+  });
+
+  test('lists every candidate and an install hint when not found', async () => {
+    fs.mkdirSync(path.join(root, 'node_modules/.bin'), { recursive: true });
+
+    const result = await discovery().discover('solargraph', root);
+
+    expect(result.resolved).toBeNull();
+    expect(result.installHint).toBe('gem install solargraph');
+    expect(result.candidates.every((c) => !c.found)).toBe(true);
+    expect(result.candidates.map((c) => c.source)).toEqual([
+      'project', 'path', 'goBin', 'common', 'common', 'common', 'common', 'common',
+    ]);
+    expect(result.candidates[0]!.path).toBe(path.join(root, 'node_modules/.bin/solargraph'));
+    expect(result.candidates).toContainEqual({ source: 'common', path: path.join(root, 'home/.cargo/bin/solargraph'), found: false });
+  });
+
+  test('resolves explicit paths relative to the search directory', async () => {
+    const local = executable(`tools/${COMMAND}`);
+
+    const result = await discovery().discover(`./tools/${COMMAND}`, root);
+
+    expect(result.resolved).toMatchObject({ command: local, source: 'configured' });
+    expect(result.candidates).toHaveLength(1);
+  });
+});
+
+describe('parseGoDirectives', () => {
+  test('parses single-line and block forms', () => {
+    const content = [
+      'module example.com/app',
+      'tool golang.org/x/tools/gopls',
+      'tool (',
+      '\thonnef.co/go/tools/cmd/staticcheck // lint',
+      '',
+      ')',
+      'toolchain go1.24.0',
+    ].join('\n');
+
+    expect(parseGoDirectives(content, 'tool')).toEqual([
+      'golang.org/x/tools/gopls',
+      'honnef.co/go/tools/cmd/staticcheck',
+    ]);
+  });
+});
+
+describe('parseEnvOutput', () => {
+  test('reads variables between the markers', () => {
+    expect(parseEnvOutput('motd\n__ULTRA_ENV__\nA=1\nB=x=y\n__ULTRA_ENV__\nprompt')).toEqual({ A: '1', B: 'x=y' });
+  });
+
+  test('returns null without markers', () => {
+    expect(parseEnvOutput('A=1\n')).toBeNull();
+  });
+});
@@ -0,0 +1,232 @@
+/**
+ * LocalNotificationService Unit Tests
+ *
+ * Tests for the local notification service implementation.
+ */
+
+import { describe, test, expect, beforeEach } from 'bun:test';
+import { LocalNotificationService } from '../../../../src/services/notification/local.ts';
+import type {
+  NotificationInfo,
+  NotificationClosedEvent,
+} from '../../../../src/services/notification/types.ts';
+
+describe('LocalNotificationService', () => {
+  let service: LocalNotificationService;
+
+  beforeEach(() => {
+    service = new LocalNotificationService();
+  });
+
+  describe('show', () => {
+    test('returns an id and emits shown', () => {
+      const shown: NotificationInfo[] = [];
+      service.onShown((n) => shown.push(n));
+
+      const id = service.show('Build finished', { severity: 'success', source: 'tasks' });
+
+      expect(id).toMatch(/^notification-\d+$/);
+      expect(shown).toHaveLength(1);
+      expect(shown[0]!.message).toBe('Build finished');
+      expect(shown[0]!.severity).toBe('success');
+      expect(shown[0]!.source).toBe('tasks');
+    });
+
+    test('defaults to info with no actions', () => {
+      const id = service.show('Hello')!;
+      const info = service.get(id)!;
+      expect(info.severity).toBe('info');
+      expect(info.actions).toEqual([]);
+      expect(info.timeout).toBeUndefined();
+    });
+
+    test('notifications with actions stay until closed', () => {
+      const id = service.show('Reload?', { actions: [{ id: 'reload', label: 'Reload' }] })!;
+      expect(service.get(id)!.timeout).toBe(0);
+    });
+
+    test('returns null for suppressed keys', () => {
+      service.suppress('tips.welcome');
+      expect(service.show('Welcome!', { doNotShowAgainKey: 'tips.welcome' })).toBeNull();
+      expect(service.list()).toHaveLength(0);
+    });
+  });
+
+  describe('progress', () => {
+    test('startProgress creates open progress notification', () => {
+      const id = service.startProgress('Indexing...', { cancellable: true, percent: 10 });
+      const info = service.get(id)!;
+      expect(info.progress).toEqual({ percent: 10, cancellable: true });
+      expect(info.timeout).toBe(0);
+    });
+
+    test('update changes message and clamps percent', () => {
+      const updated: NotificationInfo[] = [];
+      service.onUpdated((n) => updated.push(n));
+      const id = service.startProgress('Indexing...');
+
+      expect(service.update(id, { message: 'Indexing src/', percent: 140 })).toBe(true);
+
+      expect(updated).toHaveLength(1);
+      expect(updated[0]!.message).toBe('Indexing src/');
+      expect(updated[0]!.progress!.percent).toBe(100);
+    });
+
+    test('cancel closes cancellable progress only', () => {
+      const closed: NotificationClosedEvent[] = [];
+      service.onClosed((e) => closed.push(e));
+      const fixed = service.startProgress('Saving...');
+      const cancellable = service.startProgress('Indexing...', { cancellable: true });
+
+      expect(service.cancel(fixed)).toBe(false);
+      expect(service.cancel(cancellable)).toBe(true);
+      expect(closed).toEqual([{ id: cancellable, reason: 'cancelled', actionId: undefined }]);
+    });
+  });
+
+  describe('close and actions', () => {
+    test('close records reason and emits closed', () => {
+      const closed: NotificationClosedEvent[] = [];
+      service.onClosed((e) => closed.push(e));
+      const id = service.show('Hello')!;
+
+      expect(service.close(id, 'expired')).toBe(true);
+      expect(service.close(id)).toBe(false);
+
+      expect(closed).toHaveLength(1);
+      expect(service.get(id)!.closeReason).toBe('expired');
+      expect(service.get(id)!.closedAt).toBeDefined();
+    });
+
+    test('update fails once closed', () => {
+      const id = service.show('Hello')!;
+      service.close(id);
+      expect(service.update(id, { message: 'Changed' })).toBe(false);
+    });
+
+    test('invokeAction emits action and closes with the action id', () => {
+      const actions: string[] = [];
+      service.onAction((e) => actions.push(e.actionId));
+      const id = service.show('Push failed', { actions: [{ id: 'retry', label: 'Retry' }] })!;
+
+      expect(service.invokeAction(id, 'unknown')).toBe(false);
+      expect(service.invokeAction(id, 'retry')).toBe(true);
+
+      expect(actions).toEqual(['retry']);
+      expect(service.get(id)!.closeReason).toBe('action');
+      expect(service.get(id)!.actionId).toBe('retry');
+    });
+
+    test('waitForAction resolves with the chosen action', async () => {
+      const id = service.show('Push failed', { actions: [{ id: 'retry', label: 'Retry' }] })!;
+      const waiting = service.waitForAction(id);
+      service.invokeAction(id, 'retry');
+      expect(await waiting).toBe('retry');
+    });
+
+    test('waitForAction resolves null when dismissed', async () => {
+      const id = service.show('Push failed', { actions: [{ id: 'retry', label: 'Retry' }] })!;
+      const waiting = service.waitForAction(id);
+      service.close(id);
+      expect(await waiting).toBeNull();
+    });
+
+    test('waitForAction resolves at once for closed notifications', async () => {
+      const id = service.show('Hello')!;
+      service.close(id);
+      expect(await service.waitForAction(id)).toBeNull();
+      expect(await service.waitForAction('unknown')).toBeNull();
+    });
+  });
+
+  describe('history', () => {
+    test('list returns open and closed, oldest first', () => {
+      const a = service.show('A')!;
+      const b = service.show('B')!;
+      service.close(a);
+
+      expect(service.list().map((n) => n.id)).toEqual([a, b]);
+      expect(service.list(true).map((n) => n.id)).toEqual([b]);
+    });
+
+    test('clearHistory keeps open notifications', () => {
+      const a = service.show('A')!;
+      const b = service.show('B')!;
+      service.close(a);
+      service.clearHistory();
+
+      expect(service.list().map((n) => n.id)).toEqual([b]);
+    });
+
+    test('history is bounded', () => {
+      for (let i = 0; i < 150; i++) {
+        service.close(service.show(`N${i}`)!);
+      }
+      const list = service.list();
+      expect(list).toHaveLength(100);
+      expect(list[0]!.message).toBe('N50');
+    });
+
+    test('returned notifications are copies', () => {
+      const id = service.show('Hello', { actions: [{ id: 'a', label: 'A' }] })!;
+      service.get(id)!.actions[0]!.label = 'Changed';
+      expect(service.get(id)!.actions[0]!.label).toBe('A');
+    });
+  });
+
+  describe('do not show again', () => {
+    test('suppress closes open notifications with the key', () => {
+      const id = service.show('Tip', { doNotShowAgainKey: 'tips' })!;
+      const other = service.show('Other')!;
+      service.suppress('tips');
+
+      expect(service.get(id)!.closeReason).toBe('dismissed');
+      expect(service.get(other)!.closedAt).toBeUndefined();
+      expect(service.isSuppressed('tips')).toBe(true);
+    });
+
+    test('emits suppressed changes', () => {
+      const changes: string[][] = [];
+      service.onSuppressedChange((keys) => changes.push(keys));
+
+      service.suppress('b');
+      service.suppress('a');
+      service.suppress('a');
+      service.unsuppress('b');
+
+      expect(changes).toEqual([['b'], ['a', 'b'], ['a']]);
+    });
+
+    test('setSuppressed replaces keys without emitting', () => {
+      const changes: string[][] = [];
+      service.onSuppressedChange((keys) => changes.push(keys));
+      service.suppress('old');
+
+      service.setSuppressed(['x', 'y']);
+
+      expect(service.getSuppressed()).toEqual(['x', 'y']);
+      expect(changes).toHaveLength(1);
+    });
+  });
+
+  describe('events', () => {
+    test('unsubscribe stops callbacks', () => {
+      const shown: string[] = [];
+      const unsubscribe = service.onShown((n) => shown.push(n.id));
+      service.show('A');
+      unsubscribe();
+      service.show('B');
+      expect(shown).toHaveLength(1);
+    });
+
+    test('callback errors do not break other callbacks', () => {
+      const shown: string[] = [];
+      service.onShown(() => {
+        throw new Error('boom');
+      });
+      service.onShown((n) => shown.push(n.id));
+      service.show('A');
+      expect(shown).toHaveLength(1);
+    });
+  });
+});
@@ -0,0 +1,216 @@
+/**
+ * Buffer Search Tests
+ *
+ * Tests for searching open documents' unsaved content and replacing in
+ * their buffers instead of on disk.
+ */
+
+import { describe, test, expect, beforeEach, afterEach } from 'bun:test';
+import * as fs from 'fs';
+import * as os from 'os';
+import * as path from 'path';
+import {
+  searchContent,
+  matchesSearchGlob,
+  mergeBufferResults,
+  type SearchSnapshot,
+} from '../../../../src/services/search/buffer-search.ts';
+import { LocalSearchService, localSearchService } from '../../../../src/services/search/local.ts';
+import { LocalDocumentService, localDocumentService } from '../../../../src/services/document/local.ts';
+import type { SearchResult } from '../../../../src/services/search/types.ts';
+
+describe('searchContent', () => {
+  test('finds literal matches per line, case-insensitive by default', () => {
+    const matches = searchContent('a.ts', 'const Foo = foo;\nbar();\nfoo.x', 'foo');
+
+    expect(matches.map((m) => [m.line, m.column, m.length])).toEqual([
+      [1, 6, 3],
+      [1, 12, 3],
+      [3, 0, 3],
+    ]);
+    expect(matches[2]!.lineText).toBe('foo.x');
+  });
+
+  test('escapes literal queries and honours case and whole word', () => {
+    expect(searchContent('a.ts', 'a.b axb', 'a.b')).toHaveLength(1);
+    expect(searchContent('a.ts', 'Foo foo', 'foo', { caseSensitive: true })).toHaveLength(1);
+    expect(searchContent('a.ts', 'foo food', 'foo', { wholeWord: true })).toHaveLength(1);
+  });
+
+  test('supports regex queries and ignores invalid ones', () => {
+    expect(searchContent('a.ts', 'v1 v22 v', 'v\\d+', { regex: true }).map((m) => m.length)).toEqual([2, 3]);
+    expect(searchContent('a.ts', 'x', '(', { regex: true })).toEqual([]);
+  });
+
+  test('keeps only comment matches when asked', () => {
+    const matches = searchContent('a.ts', 'todo();\n// TODO: fix', 'todo', { commentsOnly: true });
+
+    expect(matches.map((m) => m.line)).toEqual([2]);
+  });
+});
+
+describe('matchesSearchGlob', () => {
+  test('matches file names at any depth and paths with directories', () => {
+    expect(matchesSearchGlob('src/deep/a.ts', '*.ts')).toBe(true);
+    expect(matchesSearchGlob('src/a.js', '*.ts')).toBe(false);
+    expect(matchesSearchGlob('packages/x/node_modules/y.js', 'node_modules/**')).toBe(true);
+  });
+});
+
+function snapshotOf(content: string, version = 1): SearchSnapshot {
+  const lines = content.split('\n');
+  return { version, lineCount: lines.length, getLine: (i) => lines[i] ?? '' };
+}
+
+describe('mergeBufferResults', () => {
+  const disk: SearchResult = {
+    query: 'needle',
+    files: [
+      { path: 'a.ts', matches: [{ line: 1, column: 0, length: 6, lineText: 'needle' }] },
+      { path: 'b.ts', matches: [{ line: 5, column: 0, length: 6, lineText: 'needle' }] },
+      { path: 'c.ts', matches: [{ line: 2, column: 0, length: 6, lineText: 'needle' }] },
+    ],
+    totalMatches: 3,
+    truncated: false,
+  };
+
+  test('replaces stale disk matches with buffer matches', () => {
+    const result = mergeBufferResults(disk, [
+      { path: 'a.ts', snapshot: snapshotOf('// added\n\nneedle needle', 7) },
+      { path: 'b.ts', snapshot: snapshotOf('removed') },
+      { path: 'd.ts', snapshot: snapshotOf('new needle') },
+    ]);
+
+    expect(result.files.map((f) => f.path)).toEqual(['a.ts', 'c.ts', 'd.ts']);
+    expect(result.files[0]!.matches.map((m) => [m.line, m.column])).toEqual([[3, 0], [3, 7]]);
+    expect(result.totalMatches).toBe(4);
+  });
+
+  test('tags buffer results with the version they were found at', () => {
+    const result = mergeBufferResults(disk, [{ path: 'a.ts', snapshot: snapshotOf('needle', 7) }]);
+
+    expect(result.files.map((f) => f.version)).toEqual([7, undefined, undefined]);
+  });
+
+  test('skips buffers outside the include glob', () => {
+    const result = mergeBufferResults(disk, [{ path: 'notes.md', snapshot: snapshotOf('needle') }], { includeGlob: '*.ts' });
+
+    expect(result).toBe(disk);
+  });
+});
+
+describe('LocalSearchService with open documents', () => {
+  let root: string;
+  let documents: LocalDocumentService;
+  let search: LocalSearchService;
+
+  beforeEach(() => {
+    root = fs.realpathSync(fs.mkdtempSync(path.join(os.tmpdir(), 'buffer-search-')));
+    documents = new LocalDocumentService();
+    search = new LocalSearchService();
+    search.setWorkspaceRoot(root);
+    search.setDocumentService(documents);
+  });
+
+  afterEach(() => {
+    fs.rmSync(root, { recursive: true, force: true });
+  });
+
+  test('replaces in open buffers, undoably, and on disk for the rest', async () => {
+    fs.writeFileSync(path.join(root, 'open.ts'), 'const foo = 1;\n');
+    fs.writeFileSync(path.join(root, 'closed.ts'), 'foo(foo);\n');
+    const { documentId } = await documents.open({ uri: `file://${root}/open.ts`, content: '// edited\nconst foo = 1;\n' });
+
+    const matches = searchContent('open.ts', documents.getContent(documentId)!.content, 'foo');
+    const result = await search.replaceInFiles(
+      [
+        { path: 'open.ts', matches },
+        { path: 'closed.ts', matches: [{ line: 1, column: 0, length: 3 }, { line: 1, column: 4, length: 3 }] },
+      ],
+      'foo',
+      'bar'
+    );
+
+    expect(result).toMatchObject({ filesModified: 2, matchesReplaced: 3, errors: [], buffersModified: ['open.ts'] });
+    expect(documents.getContent(documentId)!.content).toBe('// edited\nconst bar = 1;\n');
+    expect(fs.readFileSync(path.join(root, 'open.ts'), 'utf8')).toBe('const foo = 1;\n');
+    expect(fs.readFileSync(path.join(root, 'closed.ts'), 'utf8')).toBe('bar(bar);\n');
+
+    documents.undo(documentId);
+    expect(documents.getContent(documentId)!.content).toBe('// edited\nconst foo = 1;\n');
+  });
+
+  test('expands regex groups in buffers', async () => {
+    const { documentId } = await documents.open({ uri: `file://${root}/a.ts`, content: 'get(x) get(y)' });
+
+    await search.replaceInFiles(
+      [{ path: 'a.ts', matches: searchContent('a.ts', 'get(x) get(y)', 'get\\((\\w)\\)', { regex: true }) }],
+      'get\\((\\w)\\)',
+      'fetch($1)',
+      { regex: true }
+    );
+
+    expect(documents.getContent(documentId)!.content).toBe('fetch(x) fetch(y)');
+  });
+
+  test('skips matches the buffer was edited under since the search', async () => {
+    const { documentId } = await documents.open({ uri: `file://${root}/a.ts`, content: 'foo(); foo();\nfoo();' });
+    const matches = searchContent('a.ts', documents.getContent(documentId)!.content, 'foo');
+
+    // Shift the first line's matches and leave the second line alone
+    documents.insert({ documentId, position: { line: 0, column: 0 }, text: 'x' });
+    const result = await search.replaceInFiles([{ path: 'a.ts', matches }], 'foo', 'bar');
+
+    expect(result).toMatchObject({ matchesReplaced: 1, matchesSkipped: 2 });
+    expect(documents.getContent(documentId)!.content).toBe('xfoo(); foo();\nbar();');
+  });
+
+  test('follows edits made to a buffer since the search', async () => {
+    const { documentId } = await documents.open({ uri: `file://${root}/a.ts`, content: 'foo(); foo();\nfoo();' });
+    const found = mergeBufferResults(
+      { query: 'foo', files: [], totalMatches: 0, truncated: false },
+      [{ path: 'a.ts', snapshot: documents.getSnapshot(documentId)! }]
+    );
+
+    // Insert lines above and text before the matches, and edit inside the last one
+    documents.insert({ documentId, position: { line: 0, column: 0 }, text: '// new\nx' });
+    documents.insert({ documentId, position: { line: 2, column: 1 }, text: 'o' });
+    const result = await search.replaceInFiles(found.files, 'foo', 'bar');
+
+    expect(result).toMatchObject({ matchesReplaced: 2, matchesSkipped: 1 });
+    expect(documents.getContent(documentId)!.content).toBe('// new\nxbar(); bar();\nfooo();');
+  });
+
+  test('skips matches in files changed on disk since the search', async () => {
+    fs.writeFileSync(path.join(root, 'a.ts'), 'let foo;\n');
+    const matches = searchContent('a.ts', 'const foo;\n', 'foo');
+
+    const result = await search.replaceInFiles([{ path: 'a.ts', matches }], 'foo', 'bar');
+
+    expect(result).toMatchObject({ filesModified: 0, matchesReplaced: 0, matchesSkipped: 1 });
+    expect(fs.readFileSync(path.join(root, 'a.ts'), 'utf8')).toBe('let foo;\n');
+  });
+});
+
+describe('localSearchService', () => {
+  test('replaces in the shared document service', async () => {
+    const root = fs.realpathSync(fs.mkdtempSync(path.join(os.tmpdir(), 'buffer-search-')));
+    const { documentId } = await localDocumentService.open({ uri: `file://${root}/a.ts`, content: 'foo' });
+
+    try {
+      localSearchService.setWorkspaceRoot(root);
+      const result = await localSearchService.replaceInFiles(
+        [{ path: 'a.ts', matches: [{ line: 1, column: 0, length: 3 }] }],
+        'foo',
+        'bar'
+      );
+
+      expect(result.buffersModified).toEqual(['a.ts']);
+      expect(localDocumentService.getContent(documentId)!.content).toBe('bar');
+      expect(fs.existsSync(path.join(root, 'a.ts'))).toBe(false);
+    } finally {
+      await localDocumentService.close(documentId);
+      fs.rmSync(root, { recursive: true, force: true });
+    }
+  });
+});
@@ -0,0 +1,48 @@
+/**
+ * Comment Scope Tests
+ *
+ * Detecting whether search matches fall inside comments.
+ */
+
+import { describe, test, expect } from 'bun:test';
+import { findCommentStart, getCommentSyntax, isInComment } from '../../../../src/services/search/comment-scope.ts';
+
+describe('getCommentSyntax', () => {
+  test('maps extensions and file names', () => {
+    expect(getCommentSyntax('src/a.ts')?.line).toEqual(['//']);
+    expect(getCommentSyntax('lib/x.PY')?.line).toEqual(['#']);
+    expect(getCommentSyntax('docker/Dockerfile')?.line).toEqual(['#']);
+    expect(getCommentSyntax('README')).toBeNull();
+  });
+});
+
+describe('findCommentStart', () => {
+  const ts = getCommentSyntax('a.ts')!;
+
+  test('finds line and block comments', () => {
+    expect(findCommentStart('foo(); // TODO', ts)).toBe(7);
+    expect(findCommentStart('x = 1; /* FIXME */', ts)).toBe(7);
+    expect(findCommentStart('   * TODO: continue', ts)).toBe(3);
+    expect(findCommentStart('const x = 1;', ts)).toBe(-1);
+  });
+
+  test('ignores markers inside strings', () => {
+    expect(findCommentStart('fetch("http://x") // TODO', ts)).toBe(18);
+    expect(findCommentStart('const s = "// TODO";', ts)).toBe(-1);
+    expect(findCommentStart('const s = `a\\`// b`;', ts)).toBe(-1);
+  });
+});
+
+describe('isInComment', () => {
+  test('keeps only matches after the comment start', () => {
+    expect(isInComment('a.ts', 'const TODO = 1; // TODO later', 19)).toBe(true);
+    expect(isInComment('a.ts', 'const TODO = 1; // TODO later', 6)).toBe(false);
+    expect(isInComment('a.py', "print('# TODO')", 9)).toBe(false);
+    expect(isInComment('q.sql', 'SELECT 1; -- FIXME', 13)).toBe(true);
+    expect(isInComment('page.html', "<p>it's</p> <!-- TODO -->", 17)).toBe(true);
+  });
+
+  test('does not filter unknown languages', () => {
+    expect(isInComment('notes.txt', 'TODO: buy milk', 0)).toBe(true);
+  });
+});
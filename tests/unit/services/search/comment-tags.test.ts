@@ -0,0 +1,126 @@
+/**
+ * Comment Tags Tests
+ *
+ * Extracting, grouping and age-filtering TODO/FIXME tags.
+ */
+
+import { describe, test, expect } from 'bun:test';
+import {
+  applyBlame,
+  buildTagPattern,
+  extractCommentTags,
+  filterTagsByAge,
+  groupCommentTags,
+  scanCommentTags,
+  type CommentTag,
+} from '../../../../src/services/search/comment-tags.ts';
+import type { SearchService } from '../../../../src/services/search/interface.ts';
+import type { SearchOptions, SearchResult } from '../../../../src/services/search/types.ts';
+
+const TAGS = ['TODO', 'FIXME', 'HACK', 'XXX'];
+
+function result(files: Record<string, [number, string][]>): SearchResult {
+  const pattern = new RegExp(buildTagPattern(TAGS), 'g');
+  return {
+    query: '',
+    totalMatches: 0,
+    truncated: false,
+    files: Object.entries(files).map(([path, lines]) => ({
+      path,
+      matches: lines.flatMap(([line, lineText]) =>
+        [...lineText.matchAll(pattern)].map((m) => ({ line, column: m.index!, length: m[0].length, lineText }))
+      ),
+    })),
+  };
+}
+
+function tag(path: string, line: number, name: string, extra: Partial<CommentTag> = {}): CommentTag {
+  return { path, line, column: 0, tag: name, text: '', ...extra };
+}
+
+describe('extractCommentTags', () => {
+  test('parses tag text, owners and comment closers', () => {
+    const tags = extractCommentTags(
+      result({
+        'src/a.ts': [
+          [3, '  // TODO(alice): handle errors'],
+          [9, '  /* FIXME - leaks */'],
+        ],
+        'b.html': [[1, '<!-- HACK: inline style -->']],
+      }),
+      TAGS
+    );
+    expect(tags.map((t) => [t.path, t.line, t.tag, t.text])).toEqual([
+      ['src/a.ts', 3, 'TODO', 'handle errors'],
+      ['src/a.ts', 9, 'FIXME', 'leaks'],
+      ['b.html', 1, 'HACK', 'inline style'],
+    ]);
+  });
+
+  test('builds a whole-word pattern', () => {
+    const pattern = new RegExp(buildTagPattern(['TODO', 'C++']));
+    expect(pattern.test('TODOS')).toBe(false);
+    expect(pattern.test('// TODO x')).toBe(true);
+  });
+});
+
+describe('scanCommentTags', () => {
+  test('searches comments with a case-sensitive regex', async () => {
+    let options: SearchOptions | undefined;
+    const service = {
+      search: async (_query: string, opts?: SearchOptions) => {
+        options = opts;
+        return result({ 'a.ts': [[1, '// XXX: why']] });
+      },
+    } as unknown as SearchService;
+
+    const tags = await scanCommentTags(service, TAGS);
+    expect(options).toMatchObject({ regex: true, caseSensitive: true, commentsOnly: true });
+    expect(tags).toHaveLength(1);
+    expect(await scanCommentTags(service, [])).toEqual([]);
+  });
+
+  test('limits the search to one file', async () => {
+    let options: SearchOptions | undefined;
+    const service = {
+      search: async (_query: string, opts?: SearchOptions) => {
+        options = opts;
+        return result({ 'src/[id].ts': [[1, '// TODO: a']], 'x/src/[id].ts': [[2, '// TODO: b']] });
+      },
+    } as unknown as SearchService;
+
+    const tags = await scanCommentTags(service, TAGS, 'src/[id].ts');
+    expect(options?.includeGlob).toBe('src/\\[id\\].ts');
+    expect(tags.map((t) => [t.path, t.line])).toEqual([['src/[id].ts', 1]]);
+  });
+});
+
+describe('grouping and blame', () => {
+  const tags = [tag('b.ts', 5, 'FIXME'), tag('a.ts', 9, 'TODO'), tag('a.ts', 2, 'XXX')];
+
+  test('groups by file with tags in line order', () => {
+    const groups = groupCommentTags(tags, 'file');
+    expect(groups.map((g) => g.key)).toEqual(['a.ts', 'b.ts']);
+    expect(groups[0]!.tags.map((t) => t.line)).toEqual([2, 9]);
+  });
+
+  test('groups by tag in configured order', () => {
+    expect(groupCommentTags(tags, 'tag', TAGS).map((g) => g.key)).toEqual(['TODO', 'FIXME', 'XXX']);
+  });
+
+  test('groups by blame author with unknown authors last', () => {
+    const copy = tags.map((t) => ({ ...t }));
+    applyBlame(copy, 'a.ts', [
+      { commit: 'abc', author: 'Zoe', date: '2020-01-01', line: 9, content: '' },
+      { commit: 'def', author: 'Ann', date: '2026-10-01', line: 2, content: '' },
+    ]);
+    expect(groupCommentTags(copy, 'author').map((g) => g.key)).toEqual(['Ann', 'Zoe', 'Unknown']);
+  });
+
+  test('filters by age from blame', () => {
+    const now = Date.parse('2026-10-16');
+    const dated = [tag('a.ts', 1, 'TODO', { date: '2026-10-10' }), tag('a.ts', 2, 'TODO', { date: '2025-01-01' }), tag('b.ts', 1, 'TODO')];
+    expect(filterTagsByAge(dated, 0, now)).toHaveLength(3);
+    expect(filterTagsByAge(dated, 30, now).map((t) => t.line)).toEqual([2]);
+  });
+});
@@ -0,0 +1,228 @@
+/**
+ * AISessionHistory Unit Tests
+ *
+ * Tests for AI session history and transcript export.
+ */
+
+import { describe, test, expect, beforeEach, afterEach } from 'bun:test';
+import {
+  AISessionHistory,
+  formatTranscriptMarkdown,
+} from '../../../../src/services/session/ai-history.ts';
+import { join } from 'path';
+import { $ } from 'bun';
+import { randomUUID } from 'crypto';
+
+const TEST_BASE_DIR = '/tmp/ultra-test-ai-history';
+
+describe('AISessionHistory', () => {
+  let history: AISessionHistory;
+  let testDir: string;
+  let historyFile: string;
+
+  beforeEach(async () => {
+    testDir = join(TEST_BASE_DIR, randomUUID());
+    historyFile = join(testDir, 'ai-history.json');
+    history = new AISessionHistory({ historyFile });
+  });
+
+  afterEach(async () => {
+    await $`rm -rf ${testDir}`.quiet().nothrow();
+  });
+
+  test('records a new session', async () => {
+    const entry = await history.record({
+      sessionId: 'abc',
+      provider: 'claude-code',
+      workspace: '/proj',
+      cwd: '/proj',
+      firstPrompt: 'Add tests',
+      timestamp: new Date('2026-01-01T10:00:00Z'),
+    });
+
+    expect(entry.createdAt).toBe('2026-01-01T10:00:00.000Z');
+    expect(entry.lastActiveAt).toBe(entry.createdAt);
+    expect(entry.firstPrompt).toBe('Add tests');
+  });
+
+  test('updates activity but keeps the first prompt', async () => {
+    await history.record({
+      sessionId: 'abc',
+      provider: 'claude-code',
+      workspace: '/proj',
+      cwd: '/proj',
+      firstPrompt: 'first',
+      timestamp: new Date('2026-01-01T10:00:00Z'),
+    });
+    const updated = await history.record({
+      sessionId: 'abc',
+      provider: 'claude-code',
+      workspace: '/proj',
+      cwd: '/proj',
+      firstPrompt: 'second',
+      timestamp: new Date('2026-01-02T10:00:00Z'),
+    });
+
+    expect(updated.firstPrompt).toBe('first');
+    expect(updated.createdAt).toBe('2026-01-01T10:00:00.000Z');
+    expect(updated.lastActiveAt).toBe('2026-01-02T10:00:00.000Z');
+    expect(await history.list()).toHaveLength(1);
+  });
+
+  test('fills in a missing first prompt', async () => {
+    await history.record({ sessionId: 'abc', provider: 'claude-code', workspace: '/p', cwd: '/p' });
+    const updated = await history.record({
+      sessionId: 'abc',
+      provider: 'claude-code',
+      workspace: '/p',
+      cwd: '/p',
+      firstPrompt: '  refactor\n the   parser ',
+    });
+
+    expect(updated.firstPrompt).toBe('refactor the parser');
+  });
+
+  test('lists by workspace and provider, newest first', async () => {
+    await history.record({ sessionId: 'a', provider: 'claude-code', workspace: '/one', cwd: '/one', timestamp: new Date('2026-01-01') });
+    await history.record({ sessionId: 'b', provider: 'claude-code', workspace: '/one', cwd: '/one', timestamp: new Date('2026-01-03') });
+    await history.record({ sessionId: 'c', provider: 'custom', customProviderId: 'aider', workspace: '/one', cwd: '/one', timestamp: new Date('2026-01-02') });
+    await history.record({ sessionId: 'd', provider: 'claude-code', workspace: '/two', cwd: '/two' });
+
+    const one = await history.list({ workspace: '/one' });
+    expect(one.map((e) => e.sessionId)).toEqual(['b', 'c', 'a']);
+
+    const claude = await history.list({ workspace: '/one', provider: 'claude-code' });
+    expect(claude.map((e) => e.sessionId)).toEqual(['b', 'a']);
+  });
+
+  test('persists to disk', async () => {
+    await history.record({ sessionId: 'abc', provider: 'claude-code', workspace: '/p', cwd: '/p', firstPrompt: 'hi' });
+    await history.flush();
+
+    const reloaded = new AISessionHistory({ historyFile });
+    const entry = await reloaded.get('claude-code', 'abc');
+    expect(entry?.firstPrompt).toBe('hi');
+  });
+
+  test('batches writes until the debounce delay or flush', async () => {
+    await history.record({ sessionId: 'abc', provider: 'claude-code', workspace: '/p', cwd: '/p' });
+    await history.record({ sessionId: 'abc', provider: 'claude-code', workspace: '/p', cwd: '/p', firstPrompt: 'hi' });
+
+    expect(await Bun.file(historyFile).exists()).toBe(false);
+
+    await history.flush();
+    const data = await Bun.file(historyFile).json();
+    expect(data.sessions).toHaveLength(1);
+    expect(data.sessions[0].firstPrompt).toBe('hi');
+  });
+
+  test('writes after the debounce delay', async () => {
+    const quick = new AISessionHistory({ historyFile, saveDebounceMs: 5 });
+    await quick.record({ sessionId: 'abc', provider: 'claude-code', workspace: '/p', cwd: '/p' });
+
+    await new Promise((resolve) => setTimeout(resolve, 30));
+    await quick.flush();
+    expect(await Bun.file(historyFile).exists()).toBe(true);
+  });
+
+  test('stores transcripts per session', async () => {
+    await history.saveTranscript({ provider: 'codex', sessionId: 'a/b' }, ['> hi', 'hello']);
+
+    expect(await history.getTranscript({ provider: 'codex', sessionId: 'a/b' })).toEqual(['> hi', 'hello']);
+    expect(await history.getTranscript({ provider: 'codex', sessionId: 'other' })).toBeNull();
+  });
+
+  test('keys custom provider transcripts by custom provider ID', async () => {
+    await history.saveTranscript({ provider: 'custom', customProviderId: 'aider', sessionId: 's1' }, ['aider']);
+    await history.saveTranscript({ provider: 'custom', customProviderId: 'goose', sessionId: 's1' }, ['goose']);
+
+    expect(await history.getTranscript({ provider: 'custom', customProviderId: 'aider', sessionId: 's1' })).toEqual(['aider']);
+    expect(await history.getTranscript({ provider: 'custom', customProviderId: 'goose', sessionId: 's1' })).toEqual(['goose']);
+  });
+
+  test('resumed sessions continue their stored transcript', async () => {
+    const key = { provider: 'claude-code' as const, sessionId: 'abc' };
+    await history.saveTranscript(key, ['> first', 'one']);
+
+    await history.resumeTranscript(key);
+    await history.saveTranscript(key, ['> second']);
+    // Storing again while resumed replaces only the part since resuming
+    await history.saveTranscript(key, ['> second', 'two']);
+
+    expect(await history.getTranscript(key)).toEqual(['> first', 'one', '> second', 'two']);
+  });
+
+  test('pruning old sessions deletes their transcripts', async () => {
+    const start = Date.parse('2026-01-01T00:00:00Z');
+    await history.record({ sessionId: 'oldest', provider: 'codex', workspace: '/p', cwd: '/p', timestamp: new Date(start) });
+    await history.saveTranscript({ provider: 'codex', sessionId: 'oldest' }, ['old']);
+
+    for (let i = 1; i <= 500; i++) {
+      await history.record({ sessionId: `s${i}`, provider: 'codex', workspace: '/p', cwd: '/p', timestamp: new Date(start + i * 1000) });
+    }
+
+    expect(await history.get('codex', 'oldest')).toBeNull();
+    expect(await history.getTranscript({ provider: 'codex', sessionId: 'oldest' })).toBeNull();
+  });
+
+  test('remove deletes a session', async () => {
+    await history.record({ sessionId: 'abc', provider: 'claude-code', workspace: '/p', cwd: '/p' });
+
+    await history.saveTranscript({ provider: 'claude-code', sessionId: 'abc' }, ['x']);
+
+    expect(await history.remove('claude-code', 'abc')).toBe(true);
+    expect(await history.getTranscript({ provider: 'claude-code', sessionId: 'abc' })).toBeNull();
+    expect(await history.remove('claude-code', 'abc')).toBe(false);
+    expect(await history.list()).toHaveLength(0);
+  });
+
+  test('corrupt history file yields empty history', async () => {
+    await $`mkdir -p ${testDir}`.quiet();
+    await Bun.write(historyFile, '{not json');
+
+    expect(await history.list()).toEqual([]);
+  });
+});
+
+describe('formatTranscriptMarkdown', () => {
+  const exportedAt = new Date('2026-01-01T12:00:00Z');
+
+  test('renders header and fenced transcript', () => {
+    const md = formatTranscriptMarkdown(
+      { providerName: 'Claude', sessionId: 'abc', cwd: '/proj', firstPrompt: 'Add tests', exportedAt },
+      ['', '> Add tests   ', 'Done.', '', '']
+    );
+
+    expect(md).toBe([
+      '# Claude session',
+      '',
+      '- **Session:** `abc`',
+      '- **Working directory:** `/proj`',
+      '- **Exported:** 2026-01-01T12:00:00.000Z',
+      '- **First prompt:** Add tests',
+      '',
+      '## Transcript',
+      '',
+      '```text',
+      '> Add tests',
+      'Done.',
+      '```',
+      '',
+    ].join('\n'));
+  });
+
+  test('omits session line when there is no session ID', () => {
+    const md = formatTranscriptMarkdown({ providerName: 'Codex', sessionId: null, cwd: '/p', exportedAt }, ['x']);
+    expect(md).not.toContain('**Session:**');
+  });
+
+  test('uses a longer fence when the transcript contains backticks', () => {
+    const md = formatTranscriptMarkdown(
+      { providerName: 'Claude', sessionId: null, cwd: '/p', exportedAt },
+      ['```ts', 'const x = 1;', '```']
+    );
+
+    expect(md).toContain('````text\n```ts');
+    expect(md.trimEnd().endsWith('```\n````')).toBe(true);
+  });
+});
@@ -0,0 +1,158 @@
+/**
+ * OutputWatcher Tests
+ */
+
+import { describe, test, expect, beforeEach } from 'bun:test';
+import {
+  OutputWatcher,
+  stripAnsi,
+  type TerminalWatchMatch,
+  type CommandFinishedEvent,
+} from '../../../src/terminal/output-watcher.ts';
+
+describe('stripAnsi', () => {
+  test('removes SGR and cursor sequences', () => {
+    expect(stripAnsi('\x1b[31mFAIL\x1b[0m \x1b[2K\x1b[1;1Hdone')).toBe('FAIL done');
+  });
+
+  test('removes OSC sequences with BEL and ST terminators', () => {
+    expect(stripAnsi('\x1b]0;title\x07a\x1b]133;D;0\x1b\\b')).toBe('ab');
+  });
+
+  test('keeps newlines, carriage returns and tabs', () => {
+    expect(stripAnsi('a\tb\r\nc')).toBe('a\tb\r\nc');
+  });
+});
+
+describe('OutputWatcher', () => {
+  let watcher: OutputWatcher;
+  let matches: TerminalWatchMatch[];
+  let finished: CommandFinishedEvent[];
+
+  beforeEach(() => {
+    watcher = new OutputWatcher([], { promptSettleMs: 0 });
+    matches = [];
+    finished = [];
+    watcher.onMatch((m) => matches.push(m));
+    watcher.onCommandFinished((e) => finished.push(e));
+  });
+
+  describe('rules', () => {
+    test('matches complete lines', () => {
+      watcher.addRule({ pattern: 'FAIL' });
+      watcher.feed('ok\nFAIL: TestFoo\n');
+
+      expect(matches).toHaveLength(1);
+      expect(matches[0]!.line).toBe('FAIL: TestFoo');
+      expect(matches[0]!.message).toBe('FAIL: TestFoo');
+    });
+
+    test('waits for the line to complete across chunks', () => {
+      watcher.addRule({ pattern: 'listening on :(\\d+)' });
+      watcher.feed('listening on :80');
+      expect(matches).toHaveLength(0);
+
+      watcher.feed('80\n');
+      expect(matches).toHaveLength(1);
+      expect(matches[0]!.groups[1]).toBe('8080');
+    });
+
+    test('matches through ANSI colouring', () => {
+      watcher.addRule({ pattern: '^panic:' });
+      watcher.feed('\x1b[1;31mpanic:\x1b[0m runtime error\r\n');
+
+      expect(matches).toHaveLength(1);
+      expect(matches[0]!.line).toBe('panic: runtime error');
+    });
+
+    test('renders message templates', () => {
+      watcher.addRule({ pattern: 'port (\\d+)', message: 'Server up on $1' });
+      watcher.feed('bound to port 3000\n');
+
+      expect(matches[0]!.message).toBe('Server up on 3000');
+    });
+
+    test('once rules are removed after the first match', () => {
+      const id = watcher.addRule({ pattern: 'ready', once: true });
+      watcher.feed('ready\nready\n');
+
+      expect(matches).toHaveLength(1);
+      expect(watcher.getRules().find((r) => r.id === id)).toBeUndefined();
+    });
+
+    test('invalid patterns are rejected', () => {
+      expect(watcher.addRule({ pattern: '(' })).toBeNull();
+      expect(watcher.getRules()).toHaveLength(0);
+    });
+
+    test('removeRule stops matching', () => {
+      const id = watcher.addRule({ pattern: 'x' })!;
+      expect(watcher.removeRule(id)).toBe(true);
+      watcher.feed('x\n');
+      expect(matches).toHaveLength(0);
+    });
+
+    test('flush matches the trailing partial line', () => {
+      watcher.addRule({ pattern: 'bye' });
+      watcher.feed('bye');
+      watcher.flush();
+      expect(matches).toHaveLength(1);
+    });
+  });
+
+  describe('command completion', () => {
+    test('fires on OSC 133 D with exit code', () => {
+      watcher.armCommandFinished();
+      watcher.feed('building...\n\x1b]133;D;2\x07');
+
+      expect(finished).toHaveLength(1);
+      expect(finished[0]!.exitCode).toBe(2);
+      expect(finished[0]!.source).toBe('osc133');
+      expect(watcher.isCommandFinishedArmed()).toBe(false);
+    });
+
+    test('handles OSC 133 split across chunks', () => {
+      watcher.armCommandFinished();
+      watcher.feed('done\n\x1b]133;D;');
+      expect(finished).toHaveLength(0);
+
+      watcher.feed('0\x1b\\');
+      expect(finished).toHaveLength(1);
+      expect(finished[0]!.exitCode).toBe(0);
+    });
+
+    test('does not fire when not armed', () => {
+      watcher.feed('\x1b]133;D;0\x07');
+      expect(finished).toHaveLength(0);
+    });
+
+    test('fires when the prompt returns after output', async () => {
+      watcher.armCommandFinished();
+      watcher.feed('compiling\nok\n');
+      watcher.feed('user@host:~/src$ ');
+
+      await new Promise((resolve) => setTimeout(resolve, 10));
+
+      expect(finished).toHaveLength(1);
+      expect(finished[0]!.exitCode).toBeNull();
+      expect(finished[0]!.source).toBe('prompt');
+    });
+
+    test('does not fire while output lacks a prompt', async () => {
+      watcher.armCommandFinished();
+      watcher.feed('downloading...');
+
+      await new Promise((resolve) => setTimeout(resolve, 10));
+
+      expect(finished).toHaveLength(0);
+      expect(watcher.isCommandFinishedArmed()).toBe(true);
+    });
+
+    test('disarm cancels the watch', () => {
+      watcher.armCommandFinished();
+      watcher.disarmCommandFinished();
+      watcher.feed('\x1b]133;D;0\x07');
+      expect(finished).toHaveLength(0);
+    });
+  });
+});
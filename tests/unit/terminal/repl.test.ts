@@ -0,0 +1,142 @@
+/**
+ * REPL Support Tests
+ *
+ * REPL selection, block extraction and input formatting.
+ */
+
+import { describe, test, expect } from 'bun:test';
+import {
+  BUILTIN_REPLS,
+  findEnclosingBlock,
+  formatReplInput,
+  resolveRepl,
+  splitCommandLine,
+  waitForReplReady,
+} from '../../../src/terminal/repl.ts';
+
+const python = BUILTIN_REPLS['python']![0]!;
+const node = BUILTIN_REPLS['javascript']![0]!;
+
+describe('resolveRepl', () => {
+  test('picks the first available built-in REPL', () => {
+    expect(resolveRepl('javascript', {}, () => true)?.command).toBe('node');
+    expect(resolveRepl('javascript', {}, (cmd) => cmd === 'bun')).toMatchObject({ command: 'bun', args: ['repl'] });
+    expect(resolveRepl('go', {}, (cmd) => cmd === 'gore')?.command).toBe('gore');
+    expect(resolveRepl('go', {}, () => false)).toBeNull();
+    expect(resolveRepl('markdown', {}, () => true)).toBeNull();
+  });
+
+  test('configured command lines win and keep REPL quirks', () => {
+    const repl = resolveRepl('python', { python: 'python3 -i "my script.py"' }, () => false);
+    expect(repl).toMatchObject({ command: 'python3', args: ['-i', 'my script.py'], blankLineEndsBlock: true });
+    expect(resolveRepl('sql', { sql: 'psql mydb' }, () => false)).toMatchObject({ name: 'psql', args: ['mydb'] });
+    expect(resolveRepl('ruby', { ruby: 'irb' }, () => false)?.name).toBe('irb');
+  });
+
+  test('splits command lines with quotes', () => {
+    expect(splitCommandLine(`a 'b c' "" d`)).toEqual(['a', 'b c', '', 'd']);
+  });
+});
+
+describe('findEnclosingBlock', () => {
+  const js = [
+    'const a = 1;',
+    '',
+    'function add(x, y) {',
+    '  const sum = x + y;',
+    '',
+    '  return sum;',
+    '}',
+    '',
+    'if (a) {',
+    '  add(1, 2);',
+    '} else {',
+    '  add(3, 4);',
+    '}',
+  ];
+
+  test('finds the top-level statement around the cursor', () => {
+    expect(findEnclosingBlock(js, 0)).toEqual({ startLine: 0, endLine: 0 });
+    expect(findEnclosingBlock(js, 4)).toEqual({ startLine: 2, endLine: 6 });
+    expect(findEnclosingBlock(js, 6)).toEqual({ startLine: 2, endLine: 6 });
+  });
+
+  test('includes continuation lines like else', () => {
+    expect(findEnclosingBlock(js, 11)).toEqual({ startLine: 8, endLine: 12 });
+  });
+
+  test('returns null between blocks', () => {
+    expect(findEnclosingBlock(js, 7)).toBeNull();
+  });
+
+  test('includes Python decorators', () => {
+    const py = ['import os', '', '@cache', 'def f(x):', '    if x:', '        return 1', '    return 2', '', 'f(1)'];
+    expect(findEnclosingBlock(py, 5)).toEqual({ startLine: 2, endLine: 6 });
+    expect(findEnclosingBlock(py, 2)).toEqual({ startLine: 2, endLine: 6 });
+    expect(findEnclosingBlock(py, 8)).toEqual({ startLine: 8, endLine: 8 });
+  });
+});
+
+describe('formatReplInput', () => {
+  test('wraps multi-line code in bracketed paste', () => {
+    expect(formatReplInput('a\r\nb\n\n', node, true)).toBe('\x1b[200~a\nb\x1b[201~\r');
+    expect(formatReplInput('x\x1b[201~\ny', node, true)).toBe('\x1b[200~x\ny\x1b[201~\r');
+  });
+
+  test('types single lines directly', () => {
+    expect(formatReplInput('1 + 1\n', node, true)).toBe('1 + 1\r');
+    expect(formatReplInput('  \n', node, true)).toBe('');
+  });
+
+  test('types lines one by one without bracketed paste', () => {
+    expect(formatReplInput('a\n\nb', node, false)).toBe('a\r\rb\r');
+  });
+
+  test('closes Python blocks with a blank line', () => {
+    const code = 'def f():\n    x = 1\n\n    return x';
+    expect(formatReplInput(code, python, false)).toBe('def f():\r    x = 1\r    return x\r\r');
+    expect(formatReplInput('a = 1\nb = 2', python, false)).toBe('a = 1\rb = 2\r');
+  });
+});
+
+describe('waitForReplReady', () => {
+  /** A PTY stand-in whose output is pushed by the test */
+  function fakePty() {
+    const callbacks = new Set<(data: string) => void>();
+    const pty = {
+      bracketedPaste: false,
+      isBracketedPasteEnabled: () => pty.bracketedPaste,
+      onData: (callback: (data: string) => void) => {
+        callbacks.add(callback);
+        return () => callbacks.delete(callback);
+      },
+      emit: (data: string) => callbacks.forEach((c) => c(data)),
+      listeners: () => callbacks.size,
+    };
+    return pty;
+  }
+
+  test('resolves once the REPL enables bracketed paste', async () => {
+    const pty = fakePty();
+    let ready = false;
+    const waiting = waitForReplReady(pty, { quietMs: 1000, timeoutMs: 1000 }).then(() => (ready = true));
+
+    pty.emit('Python 3.12\r\n');
+    await new Promise((resolve) => setTimeout(resolve, 5));
+    expect(ready).toBe(false);
+
+    pty.bracketedPaste = true;
+    pty.emit('\x1b[?2004h>>> ');
+    await waiting;
+    expect(pty.listeners()).toBe(0);
+  });
+
+  test('resolves when the first output settles or nothing arrives', async () => {
+    const pty = fakePty();
+    const settled = waitForReplReady(pty, { quietMs: 10, timeoutMs: 1000 });
+    pty.emit('> ');
+    await settled;
+
+    await waitForReplReady(fakePty(), { quietMs: 10, timeoutMs: 10 });
+  });
+});
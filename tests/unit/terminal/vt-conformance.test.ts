@@ -0,0 +1,502 @@
+/**
+ * VT/xterm Conformance Tests
+ *
+ * Exercises ScreenBuffer and AnsiParser against the behaviours checked by
+ * vttest, grouped roughly like its menu: cursor movement, screen features,
+ * character sets, insert/delete, reports and VT220 additions.
+ */
+
+import { describe, test, expect, beforeEach } from 'bun:test';
+import { ScreenBuffer, AnsiParser } from '../../../src/terminal/screen-buffer.ts';
+
+// ============================================
+// Helpers
+// ============================================
+
+const ESC = '\x1b';
+const CSI = '\x1b[';
+
+interface Term {
+  screen: ScreenBuffer;
+  parser: AnsiParser;
+  replies: string[];
+  write: (data: string) => void;
+}
+
+function createTerm(cols = 80, rows = 24): Term {
+  const screen = new ScreenBuffer(cols, rows);
+  const parser = new AnsiParser(screen);
+  const replies: string[] = [];
+  parser.onOutput((data) => replies.push(data));
+  return { screen, parser, replies, write: (data) => parser.process(data) };
+}
+
+/** Text of a screen row with trailing blanks removed */
+function rowText(screen: ScreenBuffer, y: number): string {
+  return (screen.getBuffer()[y] ?? []).map((c) => c.char).join('').trimEnd();
+}
+
+function cursor(screen: ScreenBuffer): [number, number] {
+  const { x, y } = screen.getCursor();
+  return [y, x];
+}
+
+// ============================================
+// Tests
+// ============================================
+
+describe('VT conformance', () => {
+  let term: Term;
+
+  beforeEach(() => {
+    term = createTerm(20, 10);
+  });
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Cursor Movement (vttest 1)
+  // ─────────────────────────────────────────────────────────────────────────
+
+  describe('cursor movement', () => {
+    test('CUP is 1-based and clamps to the screen', () => {
+      term.write(`${CSI}3;5H`);
+      expect(cursor(term.screen)).toEqual([2, 4]);
+
+      term.write(`${CSI}99;99H`);
+      expect(cursor(term.screen)).toEqual([9, 19]);
+
+      term.write(`${CSI}H`);
+      expect(cursor(term.screen)).toEqual([0, 0]);
+    });
+
+    test('CUU/CUD stop at the scroll margins', () => {
+      term.write(`${CSI}3;6r`);
+      term.write(`${CSI}4;1H${CSI}10A`);
+      expect(cursor(term.screen)).toEqual([2, 0]);
+
+      term.write(`${CSI}10B`);
+      expect(cursor(term.screen)).toEqual([5, 0]);
+    });
+
+    test('CUU/CUD outside the region move to the screen edge', () => {
+      term.write(`${CSI}3;6r`);
+      term.write(`${CSI}9;1H${CSI}10B`);
+      expect(cursor(term.screen)).toEqual([9, 0]);
+
+      term.write(`${CSI}2;1H${CSI}10A`);
+      expect(cursor(term.screen)).toEqual([0, 0]);
+    });
+
+    test('HPA, HPR, VPA and VPR', () => {
+      term.write(`${CSI}5\``);
+      expect(cursor(term.screen)).toEqual([0, 4]);
+      term.write(`${CSI}2a`);
+      expect(cursor(term.screen)).toEqual([0, 6]);
+      term.write(`${CSI}4d`);
+      expect(cursor(term.screen)).toEqual([3, 6]);
+      term.write(`${CSI}3e`);
+      expect(cursor(term.screen)).toEqual([6, 6]);
+    });
+
+    test('control characters embedded in CSI are executed', () => {
+      // vttest: "CSI 2 <BS> C" moves left one, then right two
+      term.write(`${CSI}1;5H`);
+      term.write(`${CSI}2\bC`);
+      expect(cursor(term.screen)).toEqual([0, 5]);
+
+      // "CSI <CR> 2 C" returns the carriage mid-sequence
+      term.write(`${CSI}\r2C`);
+      expect(cursor(term.screen)).toEqual([0, 2]);
+    });
+
+    test('CAN aborts a sequence', () => {
+      term.write(`${CSI}5\x18X`);
+      expect(rowText(term.screen, 0)).toBe('X');
+    });
+
+    test('IND, NEL and RI', () => {
+      term.write(`${CSI}2;5H${ESC}D`);
+      expect(cursor(term.screen)).toEqual([2, 4]);
+      term.write(`${ESC}E`);
+      expect(cursor(term.screen)).toEqual([3, 0]);
+      term.write(`${ESC}M`);
+      expect(cursor(term.screen)).toEqual([2, 0]);
+    });
+
+    test('VT and FF act as line feeds', () => {
+      term.write('a\x0bb\x0cc');
+      expect(cursor(term.screen)).toEqual([2, 3]);
+    });
+  });
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Screen Features (vttest 2)
+  // ─────────────────────────────────────────────────────────────────────────
+
+  describe('auto-wrap', () => {
+    test('writing the last column defers the wrap', () => {
+      term.write('x'.repeat(20));
+      expect(cursor(term.screen)).toEqual([0, 19]);
+
+      term.write('y');
+      expect(rowText(term.screen, 1)).toBe('y');
+      expect(cursor(term.screen)).toEqual([1, 1]);
+    });
+
+    test('CR after a full line does not produce a blank line', () => {
+      term.write('x'.repeat(20) + '\r\nnext');
+      expect(rowText(term.screen, 1)).toBe('next');
+    });
+
+    test('cursor movement cancels the pending wrap', () => {
+      term.write('x'.repeat(20));
+      term.write(`${CSI}D`);
+      term.write('Y');
+      expect(rowText(term.screen, 0)).toBe('x'.repeat(18) + 'Yx');
+      expect(cursor(term.screen)).toEqual([0, 19]);
+    });
+
+    test('DECAWM off overwrites the last column', () => {
+      term.write(`${CSI}?7l`);
+      term.write('x'.repeat(19) + 'abc');
+      expect(rowText(term.screen, 0)).toBe('x'.repeat(19) + 'c');
+      expect(rowText(term.screen, 1)).toBe('');
+
+      term.write(`${CSI}?7h\r\n` + 'y'.repeat(21));
+      expect(rowText(term.screen, 2)).toBe('y');
+    });
+  });
+
+  describe('tab stops', () => {
+    test('default stops every 8 columns', () => {
+      term.write('\t');
+      expect(cursor(term.screen)).toEqual([0, 8]);
+      term.write('\t\t');
+      expect(cursor(term.screen)).toEqual([0, 19]);
+    });
+
+    test('TBC clears and HTS sets stops', () => {
+      term.write(`${CSI}3g`);
+      expect(term.screen.getTabStops()).toEqual([]);
+
+      term.write(`${CSI}1;4H${ESC}H${CSI}1;11H${ESC}H`);
+      expect(term.screen.getTabStops()).toEqual([3, 10]);
+
+      term.write('\r\t');
+      expect(cursor(term.screen)).toEqual([0, 3]);
+      term.write('\t');
+      expect(cursor(term.screen)).toEqual([0, 10]);
+
+      term.write(`${CSI}g`);
+      expect(term.screen.getTabStops()).toEqual([3]);
+    });
+
+    test('CHT and CBT', () => {
+      term.write(`${CSI}2I`);
+      expect(cursor(term.screen)).toEqual([0, 16]);
+      term.write(`${CSI}Z`);
+      expect(cursor(term.screen)).toEqual([0, 8]);
+      term.write(`${CSI}5Z`);
+      expect(cursor(term.screen)).toEqual([0, 0]);
+    });
+  });
+
+  describe('origin mode', () => {
+    test('CUP is relative to the scroll region', () => {
+      term.write(`${CSI}4;8r${CSI}?6h`);
+      expect(cursor(term.screen)).toEqual([3, 0]);
+
+      term.write(`${CSI}2;3H`);
+      expect(cursor(term.screen)).toEqual([4, 2]);
+
+      // Cannot leave the region
+      term.write(`${CSI}20;1H`);
+      expect(cursor(term.screen)).toEqual([7, 0]);
+    });
+
+    test('CPR reports region-relative position', () => {
+      term.write(`${CSI}4;8r${CSI}?6h${CSI}2;3H${CSI}6n`);
+      expect(term.replies).toEqual([`${CSI}2;3R`]);
+    });
+
+    test('resetting origin mode homes to the screen', () => {
+      term.write(`${CSI}4;8r${CSI}?6h${CSI}?6l`);
+      expect(cursor(term.screen)).toEqual([0, 0]);
+    });
+  });
+
+  describe('scrolling regions', () => {
+    function fillRows(t: Term): void {
+      for (let i = 0; i < 10; i++) {
+        t.write(`${CSI}${i + 1};1H${i}`);
+      }
+    }
+
+    test('LF at the bottom margin scrolls only the region', () => {
+      fillRows(term);
+      term.write(`${CSI}3;5r${CSI}5;1H\n`);
+
+      expect([0, 1, 2, 3, 4, 5].map((y) => rowText(term.screen, y))).toEqual([
+        '0',
+        '1',
+        '3',
+        '4',
+        '',
+        '5',
+      ]);
+    });
+
+    test('SU and SD scroll the region', () => {
+      fillRows(term);
+      term.write(`${CSI}2;4r${CSI}2S`);
+      expect([0, 1, 2, 3, 4].map((y) => rowText(term.screen, y))).toEqual(['0', '3', '', '', '4']);
+
+      term.write(`${CSI}T`);
+      expect([0, 1, 2, 3, 4].map((y) => rowText(term.screen, y))).toEqual(['0', '', '3', '', '4']);
+    });
+
+    test('erase uses the current background color', () => {
+      term.write(`${CSI}44m${CSI}2J`);
+      expect(term.screen.getBuffer()[5]![5]!.bg).toBe('#0000ee');
+    });
+  });
+
+  describe('DECSC / DECRC', () => {
+    test('saves position, attributes and charsets', () => {
+      term.write(`${CSI}3;4H${CSI}1;31m${ESC}(0${ESC}7`);
+      term.write(`${CSI}m${ESC}(B${CSI}H`);
+      term.write(`${ESC}8q`);
+
+      const cell = term.screen.getBuffer()[2]![3]!;
+      expect(cell.char).toBe('─');
+      expect(cell.bold).toBe(true);
+      expect(cell.fg).toBe('#cd0000');
+    });
+
+    test('saves origin mode', () => {
+      term.write(`${CSI}3;6r${CSI}?6h${ESC}7${CSI}?6l${ESC}8`);
+      expect(term.screen.isOriginMode()).toBe(true);
+    });
+
+    test('restore without save homes the cursor', () => {
+      term.write(`${CSI}5;5H${ESC}8`);
+      expect(cursor(term.screen)).toEqual([0, 0]);
+    });
+
+    test('mode 1049 saves and restores the cursor', () => {
+      term.write(`${CSI}4;6H${CSI}?1049h`);
+      expect(term.screen.isAlternateScreen()).toBe(true);
+      term.write(`${CSI}9;9H${CSI}?1049l`);
+      expect(cursor(term.screen)).toEqual([3, 5]);
+    });
+  });
+
+  describe('DECALN', () => {
+    test('fills the screen with E and homes the cursor', () => {
+      term.write(`${CSI}5;5H${ESC}#8`);
+      expect(rowText(term.screen, 0)).toBe('E'.repeat(20));
+      expect(rowText(term.screen, 9)).toBe('E'.repeat(20));
+      expect(cursor(term.screen)).toEqual([0, 0]);
+    });
+  });
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Character Sets (vttest 3)
+  // ─────────────────────────────────────────────────────────────────────────
+
+  describe('character sets', () => {
+    test('DEC Special Graphics draws boxes', () => {
+      term.write(`${ESC}(0lqk\r\nx x\r\nmqj${ESC}(B`);
+      expect(rowText(term.screen, 0)).toBe('┌─┐');
+      expect(rowText(term.screen, 1)).toBe('│ │');
+      expect(rowText(term.screen, 2)).toBe('└─┘');
+    });
+
+    test('ASCII outside 0x5f-0x7e is unchanged', () => {
+      term.write(`${ESC}(0ABC123${ESC}(B`);
+      expect(rowText(term.screen, 0)).toBe('ABC123');
+    });
+
+    test('SO and SI switch between G0 and G1', () => {
+      term.write(`${ESC})0a\x0eq\x0fq`);
+      expect(rowText(term.screen, 0)).toBe('a─q');
+    });
+
+    test('UK set maps # to pound', () => {
+      term.write(`${ESC}(A#${ESC}(B#`);
+      expect(rowText(term.screen, 0)).toBe('£#');
+    });
+  });
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Insert/Delete (vttest 8)
+  // ─────────────────────────────────────────────────────────────────────────
+
+  describe('insert and delete', () => {
+    test('ICH inserts blanks and shifts right', () => {
+      term.write(`abcdef${CSI}1;3H${CSI}2@`);
+      expect(rowText(term.screen, 0)).toBe('ab  cdef');
+    });
+
+    test('ICH drops characters pushed past the margin', () => {
+      term.write('x'.repeat(19) + 'z');
+      term.write(`${CSI}1;1H${CSI}@`);
+      expect(rowText(term.screen, 0)).toBe(' ' + 'x'.repeat(19));
+    });
+
+    test('DCH deletes and shifts left', () => {
+      term.write(`abcdef${CSI}1;2H${CSI}2P`);
+      expect(rowText(term.screen, 0)).toBe('adef');
+    });
+
+    test('ECH erases without shifting', () => {
+      term.write(`abcdef${CSI}1;2H${CSI}2X`);
+      expect(rowText(term.screen, 0)).toBe('a  def');
+    });
+
+    test('IRM inserts printed characters', () => {
+      term.write(`abc${CSI}1;2H${CSI}4hXY${CSI}4l`);
+      expect(rowText(term.screen, 0)).toBe('aXYbc');
+    });
+
+    test('IL and DL respect the region and home the column', () => {
+      for (let i = 0; i < 6; i++) {
+        term.write(`${CSI}${i + 1};1H${i}`);
+      }
+      term.write(`${CSI}2;5r${CSI}3;4H${CSI}L`);
+      expect([1, 2, 3, 4, 5].map((y) => rowText(term.screen, y))).toEqual(['1', '', '2', '3', '5']);
+      expect(cursor(term.screen)).toEqual([2, 0]);
+
+      term.write(`${CSI}2M`);
+      expect([1, 2, 3, 4, 5].map((y) => rowText(term.screen, y))).toEqual(['1', '3', '', '', '5']);
+    });
+
+    test('LNM makes LF return the carriage', () => {
+      term.write(`${CSI}20hab\ncd${CSI}20l`);
+      expect(rowText(term.screen, 1)).toBe('cd');
+    });
+  });
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // Reports (vttest 6)
+  // ─────────────────────────────────────────────────────────────────────────
+
+  describe('reports', () => {
+    test('DSR status and cursor position', () => {
+      term.write(`${CSI}5n${CSI}4;7H${CSI}6n${CSI}?6n`);
+      expect(term.replies).toEqual([`${CSI}0n`, `${CSI}4;7R`, `${CSI}?4;7R`]);
+    });
+
+    test('DSR printer, UDK and keyboard status', () => {
+      term.write(`${CSI}?15n${CSI}?25n${CSI}?26n`);
+      expect(term.replies).toEqual([`${CSI}?13n`, `${CSI}?21n`, `${CSI}?27;1n`]);
+    });
+
+    test('primary, secondary and tertiary device attributes', () => {
+      term.write(`${CSI}c${CSI}0c${CSI}>c${CSI}=c`);
+      expect(term.replies).toEqual([
+        `${CSI}?62;22c`,
+        `${CSI}?62;22c`,
+        `${CSI}>1;10;0c`,
+        `${ESC}P!|00000000${ESC}\\`,
+      ]);
+    });
+
+    test('DECREQTPARM', () => {
+      term.write(`${CSI}x${CSI}1x`);
+      expect(term.replies).toEqual([`${CSI}2;1;1;128;128;1;0x`, `${CSI}3;1;1;128;128;1;0x`]);
+    });
+
+    test('DECRQM reports mode state', () => {
+      term.write(`${CSI}?7$p${CSI}?6$p${CSI}4$p${CSI}?9999$p`);
+      expect(term.replies).toEqual([`${CSI}?7;1$y`, `${CSI}?6;2$y`, `${CSI}4;2$y`, `${CSI}?9999;0$y`]);
+    });
+
+    test('bracketed paste mode is tracked and reported', () => {
+      expect(term.screen.isBracketedPaste()).toBe(false);
+      term.write(`${CSI}?2004h${CSI}?2004$p`);
+      expect(term.screen.isBracketedPaste()).toBe(true);
+      expect(term.replies).toEqual([`${CSI}?2004;1$y`]);
+
+      term.write(`${ESC}c`);
+      expect(term.screen.isBracketedPaste()).toBe(false);
+    });
+  });
+
+  // ─────────────────────────────────────────────────────────────────────────
+  // VT220 and xterm Additions (vttest 11)
+  // ─────────────────────────────────────────────────────────────────────────
+
+  describe('VT220 and xterm additions', () => {
+    test('REP repeats the last printed character', () => {
+      term.write(`a${CSI}4b`);
+      expect(rowText(term.screen, 0)).toBe('aaaaa');
+    });
+
+    test('REP repeats the translated line-drawing character', () => {
+      term.write(`${ESC}(0q${ESC}(B${CSI}3b`);
+      expect(rowText(term.screen, 0)).toBe('────');
+    });
+
+    test('DECSTR soft reset keeps the screen', () => {
+      term.write(`hello${CSI}?6h${CSI}?7l${CSI}4h${CSI}1m${CSI}!p`);
+      expect(rowText(term.screen, 0)).toBe('hello');
+      expect(term.screen.isOriginMode()).toBe(false);
+      expect(term.screen.isAutoWrap()).toBe(true);
+      expect(term.screen.isInsertMode()).toBe(false);
+
+      term.write('X');
+      const cell = term.screen.getBuffer()[0]!.find((c) => c.char === 'X');
+      expect(cell?.bold).toBe(false);
+    });
+
+    test('RIS resets everything', () => {
+      term.write(`${CSI}?1049hdata${CSI}3g${ESC}c`);
+      expect(term.screen.isAlternateScreen()).toBe(false);
+      expect(term.screen.getTabStops()).toEqual([8, 16]);
+      expect(rowText(term.screen, 0)).toBe('');
+      expect(cursor(term.screen)).toEqual([0, 0]);
+    });
+
+    test('multiple private modes in one sequence', () => {
+      term.write(`${CSI}?25;7l`);
+      expect(term.screen.isCursorVisible()).toBe(false);
+      expect(term.screen.isAutoWrap()).toBe(false);
+    });
+
+    test('private SGR and kitty keyboard sequences are ignored', () => {
+      term.write(`${CSI}1m${CSI}>4;1m${CSI}>1u`);
+      term.write(`${CSI}3;3H${ESC}7${CSI}H${CSI}>1u`);
+      term.write('X');
+      const cell = term.screen.getBuffer()[0]![0]!;
+      expect(cell.char).toBe('X');
+      expect(cell.bold).toBe(true);
+    });
+
+    test('DCS strings are swallowed', () => {
+      term.write(`${ESC}P$qm${ESC}\\ok`);
+      expect(rowText(term.screen, 0)).toBe('ok');
+    });
+
+    test('OSC terminated by ST', () => {
+      const messages: string[] = [];
+      term.parser.onNotification((m) => messages.push(m));
+      term.write(`${ESC}]99;i=1:p=body;done${ESC}\\after`);
+      expect(messages).toEqual(['done']);
+      expect(rowText(term.screen, 0)).toBe('after');
+    });
+  });
+});
+
+describe('getTextLines', () => {
+  test('includes scrollback and drops trailing blanks', () => {
+    const term = createTerm(10, 3);
+    term.write('one\r\ntwo\r\n\x1b[31mthree\x1b[0m   \r\nfour');
+
+    expect(term.screen.getTextLines()).toEqual(['one', 'two', 'three', 'four']);
+  });
+
+  test('empty screen has no lines', () => {
+    const term = createTerm(10, 3);
+    expect(term.screen.getTextLines()).toEqual([]);
+  });
+});